@@ -0,0 +1,203 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	ReasonSynced  = "Synced"
+	ReasonErrored = "Errored"
+)
+
+// PushSecretStoreRef defines a SecretStore the Secret is pushed to.
+type PushSecretStoreRef struct {
+	// Name of the SecretStore resource
+	Name string `json:"name"`
+
+	// Kind of the SecretStore resource (SecretStore or ClusterSecretStore)
+	// Defaults to `SecretStore`
+	// +kubebuilder:default="SecretStore"
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// PushSecretSpec configures the behavior of the PushSecret.
+type PushSecretSpec struct {
+	// The Interval to which External Secrets will try to push a secret definition
+	// +kubebuilder:default="1h"
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// SecretStoreRefs are the SecretStores the selected keys are pushed to.
+	SecretStoreRefs []PushSecretStoreRef `json:"secretStoreRefs"`
+
+	// The Secret Selector (k8s source) for the Push Secret
+	Selector PushSecretSelector `json:"selector"`
+
+	// Secret Data that should be pushed to providers
+	// +optional
+	Data []PushSecretData `json:"data,omitempty"`
+
+	// Template is rendered over the keys of the selected Secret before they are pushed.
+	// The keys of data[].match.secretKey refer to the rendered keys.
+	// +optional
+	Template *PushSecretTemplate `json:"template,omitempty"`
+
+	// DeletionPolicy defines what happens to the pushed secrets of the provider if the
+	// PushSecret is deleted or a key is no longer pushed to a store.
+	// Delete removes them from the provider, Retain keeps them. Defaults to Retain.
+	// +optional
+	// +kubebuilder:default="Retain"
+	DeletionPolicy PushSecretDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// PushSecretDeletionPolicy defines how pushed secrets are handled when they are no longer pushed.
+// +kubebuilder:validation:Enum=Delete;Retain
+type PushSecretDeletionPolicy string
+
+const (
+	// PushSecretDeletionPolicyDelete deletes the pushed secrets from the provider.
+	PushSecretDeletionPolicyDelete PushSecretDeletionPolicy = "Delete"
+
+	// PushSecretDeletionPolicyRetain keeps the pushed secrets at the provider.
+	PushSecretDeletionPolicyRetain PushSecretDeletionPolicy = "Retain"
+)
+
+// PushSecretTemplate defines the keys that are rendered from the selected Secret.
+// Only the rendered keys can be pushed, so keys that are not templated are stripped.
+type PushSecretTemplate struct {
+	// EngineVersion specifies the template engine version
+	// that should be used to execute the templates in .data.
+	// +kubebuilder:default="v2"
+	// +optional
+	EngineVersion esv1beta1.TemplateEngineVersion `json:"engineVersion,omitempty"`
+
+	// Data maps the rendered keys to their templates.
+	// The templates have access to the keys of the selected Secret.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+type PushSecretSecret struct {
+	// Name of the Secret. The Secret must exist in the same namespace as the PushSecret manifest.
+	Name string `json:"name"`
+}
+
+type PushSecretSelector struct {
+	// Select a Secret to Push.
+	Secret PushSecretSecret `json:"secret"`
+}
+
+type PushSecretRemoteRef struct {
+	// Name of the resulting provider secret.
+	RemoteKey string `json:"remoteKey"`
+}
+
+type PushSecretMatch struct {
+	// Secret Key to be pushed
+	SecretKey string `json:"secretKey"`
+
+	// Remote Refs to push to providers.
+	RemoteRef PushSecretRemoteRef `json:"remoteRef"`
+}
+
+type PushSecretData struct {
+	// Match a given Secret Key to be pushed to the provider.
+	Match PushSecretMatch `json:"match"`
+}
+
+type PushSecretConditionType string
+
+const (
+	PushSecretReady PushSecretConditionType = "Ready"
+)
+
+// PushSecretStatusCondition indicates the status of the PushSecret.
+type PushSecretStatusCondition struct {
+	Type   PushSecretConditionType `json:"type"`
+	Status corev1.ConditionStatus  `json:"status"`
+
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// SyncedPushSecretsMap holds the pushed data by store and remote key.
+type SyncedPushSecretsMap map[string]map[string]PushSecretData
+
+// PushSecretStatus indicates the history of the status of PushSecret.
+type PushSecretStatus struct {
+	// +nullable
+	// refreshTime is the time and date the external secret was fetched and
+	// the target secret updated
+	RefreshTime metav1.Time `json:"refreshTime,omitempty"`
+
+	// SyncedResourceVersion keeps track of the last synced version.
+	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
+
+	// SourceSecretResourceVersion is the resourceVersion of the source Secret of the last push.
+	// +optional
+	SourceSecretResourceVersion string `json:"sourceSecretResourceVersion,omitempty"`
+
+	// Synced Push Secrets for later deletion. Matches Secret Stores to PushSecretData that was stored to that secretStore.
+	// +optional
+	SyncedPushSecrets SyncedPushSecretsMap `json:"syncedPushSecrets,omitempty"`
+
+	// FailedSyncs is the number of consecutive failed syncs, it is reset by a successful sync.
+	// +optional
+	FailedSyncs int32 `json:"failedSyncs,omitempty"`
+
+	// NextRetryTime is the time a failed sync is retried.
+	// The retries back off exponentially with the number of failed syncs.
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// +optional
+	Conditions []PushSecretStatusCondition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PushSecret is the Schema for the PushSecrets API.
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories={pushsecrets}
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+type PushSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PushSecretSpec   `json:"spec,omitempty"`
+	Status PushSecretStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PushSecretList contains a list of PushSecret resources.
+type PushSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PushSecret `json:"items"`
+}
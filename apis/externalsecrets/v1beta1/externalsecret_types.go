@@ -30,6 +30,39 @@ type SecretStoreRef struct {
 	Kind string `json:"kind,omitempty"`
 }
 
+// SourceRef overrides the source from which the data of an entry is fetched.
+// Exactly one of storeRef or generatorRef must be set.
+type SourceRef struct {
+	// SecretStoreRef defines which SecretStore to fetch the data from,
+	// instead of spec.secretStoreRef.
+	// +optional
+	SecretStoreRef *SecretStoreRef `json:"storeRef,omitempty"`
+
+	// GeneratorRef points to a generator custom resource which produces the data.
+	// +optional
+	GeneratorRef *GeneratorRef `json:"generatorRef,omitempty"`
+}
+
+// GeneratorRef points to a generator custom resource in the namespace of the ExternalSecret,
+// or to a cluster-scoped ClusterGenerator.
+type GeneratorRef struct {
+	// Specify the apiVersion of the generator resource
+	// +kubebuilder:default="generators.external-secrets.io/v1alpha1"
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Specify the Kind of the generator resource, e.g. Password or ClusterGenerator
+	Kind string `json:"kind"`
+
+	// Specify the name of the generator resource
+	Name string `json:"name"`
+
+	// Keys selects the generated keys used by a dataFrom entry, all generated keys are used if not set.
+	// The keys are selected before the rewrite. It must not be set in data, where remoteRef.key selects the key.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
 // ExternalSecretCreationPolicy defines rules on how to create the resulting Secret.
 // +kubebuilder:validation:Enum=Owner;Orphan;Merge;None
 type ExternalSecretCreationPolicy string
@@ -73,6 +106,7 @@ const (
 )
 
 // ExternalSecretTemplateMetadata defines metadata fields for the Secret blueprint.
+// The values are rendered as templates with engineVersion v2.
 type ExternalSecretTemplateMetadata struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
@@ -84,6 +118,8 @@ type ExternalSecretTemplateMetadata struct {
 // ExternalSecretTemplate defines a blueprint for the created Secret resource.
 // we can not use native corev1.Secret, it will have empty ObjectMeta values: https://github.com/kubernetes-sigs/controller-tools/issues/448
 type ExternalSecretTemplate struct {
+	// Type of the created Secret, e.g. kubernetes.io/dockerconfigjson or kubernetes.io/tls.
+	// The keys required by the type must be part of the Secret data.
 	// +optional
 	Type corev1.SecretType `json:"type,omitempty"`
 
@@ -110,19 +146,43 @@ const (
 	TemplateEngineV2 TemplateEngineVersion = "v2"
 )
 
-// +kubebuilder:validation:MinProperties=1
-// +kubebuilder:validation:MaxProperties=1
+// TemplateTarget defines which part of the Secret a template is rendered into.
+// +kubebuilder:validation:Enum=Data;Annotations;Labels
+type TemplateTarget string
+
+const (
+	TemplateTargetData        TemplateTarget = "Data"
+	TemplateTargetAnnotations TemplateTarget = "Annotations"
+	TemplateTargetLabels      TemplateTarget = "Labels"
+)
+
+// TemplateFrom references a ConfigMap or Secret in the namespace of the
+// ExternalSecret that holds templates.
+// Exactly one of configMap or secret must be set.
 type TemplateFrom struct {
 	ConfigMap *TemplateRef `json:"configMap,omitempty"`
 	Secret    *TemplateRef `json:"secret,omitempty"`
+
+	// Target defines where the rendered templates are stored in the Secret.
+	// Annotations and Labels require engineVersion v2.
+	// +kubebuilder:default="Data"
+	// +optional
+	Target TemplateTarget `json:"target,omitempty"`
 }
 
+// TemplateRef selects the keys of a ConfigMap or Secret that are used as templates.
 type TemplateRef struct {
-	Name  string            `json:"name"`
+	// The name of the ConfigMap/Secret resource
+	Name string `json:"name"`
+
+	// A list of keys in the ConfigMap/Secret to use as templates for Secret data
 	Items []TemplateRefItem `json:"items"`
 }
 
+// TemplateRefItem is a key of a ConfigMap or Secret. Its value is used as
+// template for the Secret key of the same name.
 type TemplateRefItem struct {
+	// A key in the ConfigMap/Secret
 	Key string `json:"key"`
 }
 
@@ -132,6 +192,8 @@ type ExternalSecretTarget struct {
 	// Name defines the name of the Secret resource to be managed
 	// This field is immutable
 	// Defaults to the .metadata.name of the ExternalSecret resource
+	// The name may be a template with the .name, .namespace, .labels and .annotations
+	// of the ExternalSecret, e.g. `{{ .name }}-credentials`.
 	// +optional
 	Name string `json:"name,omitempty"`
 
@@ -149,7 +211,8 @@ type ExternalSecretTarget struct {
 	// +optional
 	Template *ExternalSecretTemplate `json:"template,omitempty"`
 
-	// Immutable defines if the final secret will be immutable
+	// Immutable defines if the final secret will be immutable.
+	// The secret is recreated if its data changes.
 	// +optional
 	Immutable bool `json:"immutable,omitempty"`
 }
@@ -159,6 +222,17 @@ type ExternalSecretData struct {
 	SecretKey string `json:"secretKey"`
 
 	RemoteRef ExternalSecretDataRemoteRef `json:"remoteRef"`
+
+	// SourceRef allows to fetch the entry from another store or a generator.
+	// With a generator remoteRef.key selects the key of the generated data.
+	// +optional
+	SourceRef *SourceRef `json:"sourceRef,omitempty"`
+
+	// Optional skips the entry if the remote key does not exist at the provider,
+	// instead of failing the sync. Skipped keys are reported with a warning event
+	// and in the message of the Ready condition.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
 }
 
 // ExternalSecretDataRemoteRef defines Provider data location.
@@ -166,29 +240,70 @@ type ExternalSecretDataRemoteRef struct {
 	// Key is the key used in the Provider, mandatory
 	Key string `json:"key"`
 
-	// Used to select a specific version of the Provider value, if supported
+	// Used to select a specific version of the Provider value, if supported.
+	// The version is mapped to the native versioning of the provider, e.g. the version of a
+	// Vault KV v2 secret, the version of a GCP secret or the version stage of an AWS secret.
+	// The sync fails if the provider does not support versions or the version is invalid.
 	// +optional
 	Version string `json:"version,omitempty"`
 
 	// +optional
-	// Used to select a specific property of the Provider value (if a map), if supported
+	// Used to select a specific property of the Provider value (if a map), if supported.
+	// Nested values are selected with gjson syntax, a top-level key matching the property exactly takes precedence.
 	Property string `json:"property,omitempty"`
 
 	// +optional
-	// Used to define a conversion Strategy
+	// Used to define a conversion Strategy for keys that are not valid Secret keys
+	// +kubebuilder:validation:Enum=Default;Unicode
 	// +kubebuilder:default="Default"
 	ConversionStrategy ExternalSecretConversionStrategy `json:"conversionStrategy,omitempty"`
+
+	// +optional
+	// Used to define a decoding Strategy
+	// +kubebuilder:default="None"
+	DecodingStrategy ExternalSecretDecodingStrategy `json:"decodingStrategy,omitempty"`
+
+	// +optional
+	// Policy for fetching the metadata of the Provider value instead of its data, if supported.
+	// Supported by Vault (custom_metadata), AWS Secrets Manager (tags) and GCP Secret Manager (labels).
+	// Possible options are Fetch and None, defaults to None
+	// +kubebuilder:validation:Enum=None;Fetch
+	// +kubebuilder:default="None"
+	MetadataPolicy ExternalSecretMetadataPolicy `json:"metadataPolicy,omitempty"`
 }
 
+type ExternalSecretMetadataPolicy string
+
+const (
+	ExternalSecretMetadataPolicyNone  ExternalSecretMetadataPolicy = "None"
+	ExternalSecretMetadataPolicyFetch ExternalSecretMetadataPolicy = "Fetch"
+)
+
 type ExternalSecretConversionStrategy string
 
 const (
+	// ExternalSecretConversionDefault replaces invalid characters with _.
 	ExternalSecretConversionDefault ExternalSecretConversionStrategy = "Default"
+	// ExternalSecretConversionUnicode replaces invalid characters with their code point, e.g. / with _U002f_.
 	ExternalSecretConversionUnicode ExternalSecretConversionStrategy = "Unicode"
+	// ExternalSecretConversionNone keeps the keys as returned by the provider.
+	// It is only used internally, to apply rewrites before the conversion.
+	ExternalSecretConversionNone ExternalSecretConversionStrategy = "None"
 )
 
-// +kubebuilder:validation:MinProperties=1
-// +kubebuilder:validation:MaxProperties=1
+// +kubebuilder:validation:Enum=Auto;Base64;Base64URL;None
+type ExternalSecretDecodingStrategy string
+
+const (
+	// ExternalSecretDecodeAuto decodes base64 or base64url values and keeps other values as they are.
+	ExternalSecretDecodeAuto      ExternalSecretDecodingStrategy = "Auto"
+	ExternalSecretDecodeBase64    ExternalSecretDecodingStrategy = "Base64"
+	ExternalSecretDecodeBase64URL ExternalSecretDecodingStrategy = "Base64URL"
+	ExternalSecretDecodeNone      ExternalSecretDecodingStrategy = "None"
+)
+
+// ExternalSecretDataFromRemoteRef defines the Provider data of a dataFrom entry,
+// exactly one of extract or find must be set.
 type ExternalSecretDataFromRemoteRef struct {
 	// Used to extract multiple key/value pairs from one secret
 	// +optional
@@ -196,6 +311,42 @@ type ExternalSecretDataFromRemoteRef struct {
 	// Used to find secrets based on tags or regular expressions
 	// +optional
 	Find *ExternalSecretFind `json:"find,omitempty"`
+
+	// Used to rewrite the keys of the secrets returned by extract, find or a generator.
+	// The operations are applied in order to the keys as returned by the provider or generator,
+	// the conversionStrategy is applied to the result.
+	// +optional
+	Rewrite []ExternalSecretRewrite `json:"rewrite,omitempty"`
+
+	// SourceRef allows to fetch the entry from another store or a generator.
+	// With a generator extract and find must not be set, the keys selected by generatorRef.keys
+	// or all generated keys are used.
+	// +optional
+	SourceRef *SourceRef `json:"sourceRef,omitempty"`
+}
+
+// ExternalSecretRewrite is a single rewrite operation, either regexp or transform must be set.
+type ExternalSecretRewrite struct {
+	// Used to rewrite keys with a regular expression.
+	// +optional
+	Regexp *ExternalSecretRewriteRegexp `json:"regexp,omitempty"`
+
+	// Used to rewrite keys with a template.
+	// +optional
+	Transform *ExternalSecretRewriteTransform `json:"transform,omitempty"`
+}
+
+type ExternalSecretRewriteRegexp struct {
+	// Regular expression matched against the key.
+	Source string `json:"source"`
+	// Replacement of the matches, may reference capture groups like $1.
+	Target string `json:"target"`
+}
+
+type ExternalSecretRewriteTransform struct {
+	// Template producing the new key, `.value` holds the current key.
+	// The sprig functions are available, e.g. `{{ .value | lower }}` or `{{ .value | trimPrefix "prod/" }}`.
+	Template string `json:"template"`
 }
 
 type ExternalSecretFind struct {
@@ -210,9 +361,15 @@ type ExternalSecretFind struct {
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
 	// +optional
-	// Used to define a conversion Strategy
+	// Used to define a conversion Strategy for keys that are not valid Secret keys
+	// +kubebuilder:validation:Enum=Default;Unicode
 	// +kubebuilder:default="Default"
 	ConversionStrategy ExternalSecretConversionStrategy `json:"conversionStrategy,omitempty"`
+
+	// +optional
+	// Used to define a decoding Strategy
+	// +kubebuilder:default="None"
+	DecodingStrategy ExternalSecretDecodingStrategy `json:"decodingStrategy,omitempty"`
 }
 
 type FindName struct {
@@ -229,7 +386,8 @@ type ExternalSecretSpec struct {
 
 	// RefreshInterval is the amount of time before the values are read again from the SecretStore provider
 	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h"
-	// May be set to zero to fetch and create it once. Defaults to 1h.
+	// May be set to zero to fetch and create it once, the values are only read again when the
+	// ExternalSecret changes or the target secret was deleted. Defaults to 1h.
 	// +kubebuilder:default="1h"
 	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
 
@@ -241,8 +399,31 @@ type ExternalSecretSpec struct {
 	// If multiple entries are specified, the Secret keys are merged in the specified order
 	// +optional
 	DataFrom []ExternalSecretDataFromRemoteRef `json:"dataFrom,omitempty"`
+
+	// ConflictPolicy defines how a Secret key is handled which is set by several entries.
+	// The entries of dataFrom are merged in order, followed by the entries of data.
+	// Error fails the sync, FirstWins keeps the value of the first entry and
+	// LastWins overwrites it with the value of the last entry. If not set, the value of the
+	// last entry is used like with LastWins, but conflicts are not reported with events.
+	// +optional
+	ConflictPolicy ExternalSecretConflictPolicy `json:"conflictPolicy,omitempty"`
 }
 
+// ExternalSecretConflictPolicy defines how Secret keys set by several entries are handled.
+// +kubebuilder:validation:Enum=Error;FirstWins;LastWins
+type ExternalSecretConflictPolicy string
+
+const (
+	// ConflictPolicyError fails the sync if a Secret key is set by several entries.
+	ConflictPolicyError ExternalSecretConflictPolicy = "Error"
+
+	// ConflictPolicyFirstWins keeps the value of the first entry which sets a Secret key.
+	ConflictPolicyFirstWins ExternalSecretConflictPolicy = "FirstWins"
+
+	// ConflictPolicyLastWins keeps the value of the last entry which sets a Secret key.
+	ConflictPolicyLastWins ExternalSecretConflictPolicy = "LastWins"
+)
+
 type ExternalSecretConditionType string
 
 const (
@@ -271,12 +452,19 @@ const (
 	ConditionReasonSecretSyncedError = "SecretSyncedError"
 	// ConditionReasonSecretDeleted indicates that the secret has been deleted.
 	ConditionReasonSecretDeleted = "SecretDeleted"
+	// ConditionReasonSecretRecreated indicates that the immutable secret has been recreated because its data changed.
+	ConditionReasonSecretRecreated = "SecretRecreated"
+	// ConditionReasonSecretKeyConflict indicates that a key is set by several entries with conflictPolicy=Error.
+	ConditionReasonSecretKeyConflict = "SecretKeyConflict"
 
 	ReasonInvalidStoreRef      = "InvalidStoreRef"
 	ReasonProviderClientConfig = "InvalidProviderClientConfig"
 	ReasonUpdateFailed         = "UpdateFailed"
 	ReasonUpdated              = "Updated"
 	ReasonDeleted              = "Deleted"
+	ReasonRecreated            = "Recreated"
+	ReasonKeyConflict          = "KeyConflict"
+	ReasonMissingOptionalKey   = "MissingOptionalKey"
 )
 
 type ExternalSecretStatus struct {
@@ -288,10 +476,68 @@ type ExternalSecretStatus struct {
 	// SyncedResourceVersion keeps track of the last synced version
 	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
 
+	// TargetSecret keeps track of the target Secret as written by the last sync,
+	// changes of the target Secret are detected by comparing it to this version.
+	// +optional
+	TargetSecret *ExternalSecretTargetStatus `json:"targetSecret,omitempty"`
+
+	// Generators records the keys the generator entries wrote to the target Secret by the last sync.
+	// Generators with rotationPolicy=OnlyWhenMissing keep these keys until they are missing or expire.
+	// +optional
+	Generators []ExternalSecretGeneratorStatus `json:"generators,omitempty"`
+
+	// FailedSyncs is the number of consecutive failed syncs, it is reset by a successful sync.
+	// +optional
+	FailedSyncs int32 `json:"failedSyncs,omitempty"`
+
+	// NextRetryTime is the time a failed sync is retried.
+	// The retries back off exponentially with the number of failed syncs.
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
 	// +optional
 	Conditions []ExternalSecretStatusCondition `json:"conditions,omitempty"`
 }
 
+// ExternalSecretGeneratorStatus is the data a generator entry wrote to the target Secret.
+type ExternalSecretGeneratorStatus struct {
+	// Source is the entry that references the generator, e.g. dataFrom[0] or data[1]
+	Source string `json:"source"`
+
+	// Kind of the generator resource
+	Kind string `json:"kind"`
+
+	// Name of the generator resource
+	Name string `json:"name"`
+
+	// Hash is the hash of the spec of the generator resource and of the entry,
+	// the data is generated again if either changes
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Keys of the target Secret set by the entry
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// ExpiresAt is the time the generated data expires, if the generator reports it
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// ExternalSecretTargetStatus is the version of the target Secret written by the last sync.
+type ExternalSecretTargetStatus struct {
+	// Name of the target Secret
+	Name string `json:"name"`
+
+	// ResourceVersion of the target Secret after the last sync
+	// +optional
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// DataHash is the hash of the data of the target Secret written by the last sync
+	// +optional
+	DataHash string `json:"dataHash,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // ExternalSecret is the Schema for the external-secrets API.
@@ -300,6 +546,7 @@ type ExternalSecretStatus struct {
 // +kubebuilder:printcolumn:name="Store",type=string,JSONPath=`.spec.secretStoreRef.name`
 // +kubebuilder:printcolumn:name="Refresh Interval",type=string,JSONPath=`.spec.refreshInterval`
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Data Hash",type=string,JSONPath=`.status.targetSecret.dataHash`,priority=1
 type ExternalSecret struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -16,6 +16,7 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -48,5 +49,73 @@ func validateExternalSecret(obj runtime.Object) error {
 	if es.Spec.Target.DeletionPolicy == DeletionPolicyMerge && es.Spec.Target.CreationPolicy == CreatePolicyNone {
 		return fmt.Errorf("deletionPolicy=Merge must not be used with creationPolcy=None. There is no Secret to merge with")
 	}
+
+	if err := validateTemplate(es.Spec.Target.Template); err != nil {
+		return err
+	}
+
+	for i, ref := range es.Spec.Data {
+		if err := validateSourceRef(ref.SourceRef); err != nil {
+			return fmt.Errorf("invalid data[%d].sourceRef: %w", i, err)
+		}
+		if ref.SourceRef != nil && ref.SourceRef.GeneratorRef != nil && len(ref.SourceRef.GeneratorRef.Keys) > 0 {
+			return fmt.Errorf("invalid data[%d].sourceRef: generatorRef.keys must not be set, remoteRef.key selects the generated key", i)
+		}
+	}
+
+	for i, ref := range es.Spec.DataFrom {
+		if err := validateSourceRef(ref.SourceRef); err != nil {
+			return fmt.Errorf("invalid dataFrom[%d].sourceRef: %w", i, err)
+		}
+		if ref.SourceRef != nil && ref.SourceRef.GeneratorRef != nil {
+			if ref.Extract != nil || ref.Find != nil {
+				return fmt.Errorf("invalid dataFrom[%d]: extract and find must not be set with a generatorRef", i)
+			}
+		} else if (ref.Extract == nil) == (ref.Find == nil) {
+			return fmt.Errorf("invalid dataFrom[%d]: exactly one of extract or find must be set", i)
+		}
+		if err := validateRewrite(ref.Rewrite); err != nil {
+			return fmt.Errorf("invalid dataFrom[%d].rewrite: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateTemplate(tpl *ExternalSecretTemplate) error {
+	if tpl == nil {
+		return nil
+	}
+	for i, from := range tpl.TemplateFrom {
+		if (from.ConfigMap == nil) == (from.Secret == nil) {
+			return fmt.Errorf("invalid templateFrom[%d]: exactly one of configMap or secret must be set", i)
+		}
+		if from.Target != "" && from.Target != TemplateTargetData && tpl.EngineVersion != TemplateEngineV2 {
+			return fmt.Errorf("invalid templateFrom[%d]: target %s requires engineVersion %s", i, from.Target, TemplateEngineV2)
+		}
+	}
+	return nil
+}
+
+func validateSourceRef(ref *SourceRef) error {
+	if ref == nil {
+		return nil
+	}
+	if (ref.SecretStoreRef == nil) == (ref.GeneratorRef == nil) {
+		return fmt.Errorf("exactly one of storeRef or generatorRef must be set")
+	}
+	return nil
+}
+
+func validateRewrite(operations []ExternalSecretRewrite) error {
+	for i, op := range operations {
+		if (op.Regexp == nil) == (op.Transform == nil) {
+			return fmt.Errorf("[%d]: exactly one of regexp or transform must be set", i)
+		}
+		if op.Regexp != nil {
+			if _, err := regexp.Compile(op.Regexp.Source); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	}
 	return nil
 }
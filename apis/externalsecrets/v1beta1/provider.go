@@ -59,6 +59,52 @@ type SecretsClient interface {
 	Close(ctx context.Context) error
 }
 
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretsPusher is implemented by SecretsClients that are able to write secrets to the provider.
+type SecretsPusher interface {
+	// PushSecret creates or updates the secret remoteKey with value.
+	PushSecret(ctx context.Context, value []byte, remoteKey string) error
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretsDeleter is implemented by SecretsPushers that are able to delete pushed secrets from the provider.
+type SecretsDeleter interface {
+	// DeleteSecret deletes the secret remoteKey, a secret which doesn't exist is not an error.
+	DeleteSecret(ctx context.Context, remoteKey string) error
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// VersionValidator is implemented by SecretsClients that are able to select a version of a secret
+// with ExternalSecretDataRemoteRef.Version.
+type VersionValidator interface {
+	// ValidateVersion returns an error if version does not select a version of a secret.
+	ValidateVersion(version string) error
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// InternalRetrier is implemented by SecretsClients that retry failed requests
+// according to the retrySettings of the store themselves.
+type InternalRetrier interface {
+	// RetriesInternally returns true if the client retries failed requests itself.
+	RetriesInternally() bool
+}
+
 var NoSecretErr = NoSecretError{}
 
 // NoSecretError shall be returned when a GetSecret can not find the
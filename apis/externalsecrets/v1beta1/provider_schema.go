@@ -88,6 +88,16 @@ func GetProvider(s GenericStore) (Provider, error) {
 	return f, nil
 }
 
+// GetProviderName returns the name of the provider configured in the store
+// or an empty string if the provider is not configured.
+func GetProviderName(s GenericStore) string {
+	name, err := getProviderName(s.GetSpec().Provider)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
 // getProviderName returns the name of the configured provider
 // or an error if the provider is not configured.
 func getProviderName(storeSpec *SecretStoreProvider) (string, error) {
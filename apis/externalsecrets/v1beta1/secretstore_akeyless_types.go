@@ -22,14 +22,49 @@ import (
 type AkeylessProvider struct {
 
 	// Akeyless GW API Url from which the secrets to be fetched from.
+	// Defaults to https://api.akeyless.io, set it to reach a self-hosted Akeyless Gateway.
 	AkeylessGWApiURL *string `json:"akeylessGWApiURL"`
 
 	// Auth configures how the operator authenticates with Akeyless.
 	Auth *AkeylessAuth `json:"authSecretRef"`
+
+	// PEM encoded CA bundle used to validate the Akeyless Gateway certificate.
+	// If not set the system root certificates are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// The provider for the CA bundle to use to validate the Akeyless Gateway certificate.
+	// +optional
+	CAProvider *CAProvider `json:"caProvider,omitempty"`
 }
 
 type AkeylessAuth struct {
-	SecretRef AkeylessAuthSecretRef `json:"secretRef"`
+	// Reference to Secrets that contain the details to authenticate with Akeyless.
+	// +optional
+	SecretRef AkeylessAuthSecretRef `json:"secretRef,omitempty"`
+
+	// KubernetesAuth authenticates with the Kubernetes auth method of Akeyless
+	// using a token of a ServiceAccount.
+	// +optional
+	KubernetesAuth *AkeylessKubernetesAuth `json:"kubernetesAuth,omitempty"`
+}
+
+type AkeylessKubernetesAuth struct {
+	// AccessID is the access id of the Kubernetes auth method.
+	AccessID string `json:"accessID"`
+
+	// K8sConfName is the name of the Kubernetes auth config of the Akeyless Gateway.
+	K8sConfName string `json:"k8sConfName"`
+
+	// A token is requested for this ServiceAccount with the TokenRequest API
+	// of the cluster external-secrets runs in. If not set, the token of the
+	// ServiceAccount of external-secrets is used.
+	// +optional
+	ServiceAccountRef *esmeta.ServiceAccountSelector `json:"serviceAccountRef,omitempty"`
+
+	// Audiences of the requested token. Defaults to the audiences of the API server.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
 }
 
 // AkeylessAuthSecretRef
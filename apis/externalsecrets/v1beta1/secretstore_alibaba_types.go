@@ -18,9 +18,13 @@ import (
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 )
 
-// AlibabaAuth contains a secretRef for credentials.
+// AlibabaAuth contains a secretRef for credentials or
+// the configuration for RAM Roles for Service Accounts (RRSA).
 type AlibabaAuth struct {
-	SecretRef AlibabaAuthSecretRef `json:"secretRef"`
+	// +optional
+	SecretRef *AlibabaAuthSecretRef `json:"secretRef,omitempty"`
+	// +optional
+	RRSAAuth *AlibabaRRSAAuth `json:"rrsa,omitempty"`
 }
 
 // AlibabaAuthSecretRef holds secret references for Alibaba credentials.
@@ -31,6 +35,19 @@ type AlibabaAuthSecretRef struct {
 	AccessKeySecret esmeta.SecretKeySelector `json:"accessKeySecretSecretRef"`
 }
 
+// AlibabaRRSAAuth authenticates against Alibaba Cloud using RAM Roles for Service Accounts.
+// The OIDC token of the service account is exchanged for temporary STS credentials of the role.
+type AlibabaRRSAAuth struct {
+	// ARN of the OIDC identity provider of the ACK cluster
+	OIDCProviderARN string `json:"oidcProviderArn"`
+	// Path to the projected service account token, e.g. /var/run/secrets/tokens/oidc-token
+	OIDCTokenFilePath string `json:"oidcTokenFilePath"`
+	// ARN of the RAM role to assume
+	RoleARN string `json:"roleArn"`
+	// Name of the session used when assuming the role
+	SessionName string `json:"sessionName"`
+}
+
 // AlibabaProvider configures a store to sync secrets using the Alibaba Secret Manager provider.
 type AlibabaProvider struct {
 	Auth *AlibabaAuth `json:"auth"`
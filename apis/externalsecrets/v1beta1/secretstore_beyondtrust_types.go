@@ -0,0 +1,75 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// BeyondTrustRetrievalType selects what kind of BeyondTrust Password Safe object a store reads.
+type BeyondTrustRetrievalType string
+
+const (
+	// BeyondTrustRetrievalSecret reads Team Passwords, i.e. secrets of the Secrets Safe.
+	BeyondTrustRetrievalSecret BeyondTrustRetrievalType = "SECRET"
+	// BeyondTrustRetrievalManagedAccount checks out the credentials of managed accounts.
+	BeyondTrustRetrievalManagedAccount BeyondTrustRetrievalType = "MANAGED_ACCOUNT"
+)
+
+// BeyondTrustProvider configures a store to sync secrets from BeyondTrust Password Safe.
+type BeyondTrustProvider struct {
+	// Auth configures how the Operator authenticates with Password Safe
+	Auth BeyondTrustAuth `json:"auth"`
+
+	// APIURL is the URL of the Password Safe public API, e.g. https://example.com/BeyondTrust/api/public/v3.
+	APIURL string `json:"apiUrl"`
+
+	// RetrievalType selects whether remoteRef.key references a secret (Team Password)
+	// in the form folder/title or a managed account in the form system/account.
+	// +kubebuilder:validation:Enum=SECRET;MANAGED_ACCOUNT
+	// +kubebuilder:default=SECRET
+	// +optional
+	RetrievalType BeyondTrustRetrievalType `json:"retrievalType,omitempty"`
+
+	// Separator of the folder path and system/account names in remoteRef.key. Defaults to /.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+
+	// PEM encoded CA bundle used to validate the Password Safe server certificate.
+	// If not set the system root certificates are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+type BeyondTrustAuth struct {
+	// RunAs is the Password Safe user the API key signs in as.
+	RunAs string `json:"runAs"`
+
+	SecretRef BeyondTrustAuthSecretRef `json:"secretRef"`
+}
+
+type BeyondTrustAuthSecretRef struct {
+	// The APIKey of a Password Safe API registration.
+	APIKey esmeta.SecretKeySelector `json:"apiKey"`
+
+	// ClientCertificate is the PEM encoded client certificate,
+	// required if the API registration enforces client certificate authentication.
+	// +optional
+	ClientCertificate *esmeta.SecretKeySelector `json:"clientCertificate,omitempty"`
+
+	// ClientKey is the PEM encoded private key of the client certificate.
+	// +optional
+	ClientKey *esmeta.SecretKeySelector `json:"clientKey,omitempty"`
+}
@@ -0,0 +1,52 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// BitwardenSecretsManagerProvider configures a store to sync secrets with Bitwarden Secrets Manager.
+type BitwardenSecretsManagerProvider struct {
+	// Auth configures how the Operator authenticates with Bitwarden.
+	Auth BitwardenSecretsManagerAuth `json:"auth"`
+
+	// OrganizationID is the ID of the organization that owns the secrets.
+	OrganizationID string `json:"organizationID"`
+
+	// ProjectID restricts name lookups and find operations to a single project.
+	// +optional
+	ProjectID string `json:"projectID,omitempty"`
+
+	// APIURL configures the Bitwarden API URL. Defaults to https://api.bitwarden.com.
+	// For self-hosted instances use https://<your-instance>/api.
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+
+	// IdentityURL configures the Bitwarden identity URL. Defaults to https://identity.bitwarden.com.
+	// For self-hosted instances use https://<your-instance>/identity.
+	// +optional
+	IdentityURL string `json:"identityURL,omitempty"`
+}
+
+// BitwardenSecretsManagerAuth contains the reference to the machine account access token.
+type BitwardenSecretsManagerAuth struct {
+	SecretRef BitwardenSecretsManagerSecretRef `json:"secretRef"`
+}
+
+type BitwardenSecretsManagerSecretRef struct {
+	// AccessToken is the access token of a machine account.
+	AccessToken esmeta.SecretKeySelector `json:"accessToken"`
+}
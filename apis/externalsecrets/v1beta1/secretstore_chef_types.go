@@ -0,0 +1,51 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// ChefProvider configures a store to sync data bag items of a Chef Infra Server.
+type ChefProvider struct {
+	// Auth configures how the Operator authenticates with the Chef Infra Server
+	Auth ChefAuth `json:"auth"`
+
+	// ServerURL is the URL of the organization on the Chef Infra Server,
+	// e.g. https://chef.example.com/organizations/example.
+	ServerURL string `json:"serverUrl"`
+
+	// PEM encoded CA bundle used to validate the Chef Infra Server certificate.
+	// If not set the system root certificates are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+type ChefAuth struct {
+	// UserName is the name of the Chef client or user the requests are signed for.
+	UserName string `json:"userName"`
+
+	SecretRef ChefAuthSecretRef `json:"secretRef"`
+}
+
+type ChefAuthSecretRef struct {
+	// The PrivateKey is the PEM encoded RSA key of the client or user.
+	PrivateKey esmeta.SecretKeySelector `json:"privateKey"`
+
+	// The DataBagSecret is the shared secret used to decrypt encrypted data bag items.
+	// Items of unencrypted data bags are returned as they are.
+	// +optional
+	DataBagSecret *esmeta.SecretKeySelector `json:"dataBagSecret,omitempty"`
+}
@@ -0,0 +1,54 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// ConsulProvider configures a store to sync entries of the HashiCorp Consul KV store.
+type ConsulProvider struct {
+	// Auth configures how the Operator authenticates with Consul
+	Auth ConsulAuth `json:"auth"`
+
+	// Server is the address of the Consul HTTP API, e.g. https://consul.example.com:8501.
+	Server string `json:"server"`
+
+	// Namespace is the Consul Enterprise namespace of the KV entries.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Partition is the Consul Enterprise admin partition of the KV entries.
+	// +optional
+	Partition string `json:"partition,omitempty"`
+
+	// Datacenter to read the KV entries from. Defaults to the datacenter of the agent.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// PEM encoded CA bundle used to validate the Consul server certificate.
+	// If not set the system root certificates are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+type ConsulAuth struct {
+	SecretRef ConsulAuthSecretRef `json:"secretRef"`
+}
+
+type ConsulAuthSecretRef struct {
+	// The Token is a Consul ACL token with read access to the KV entries.
+	Token esmeta.SecretKeySelector `json:"token"`
+}
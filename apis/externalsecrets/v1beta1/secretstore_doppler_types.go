@@ -0,0 +1,49 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// DopplerProvider configures a store to sync secrets from a Doppler project config.
+type DopplerProvider struct {
+	// Auth configures how the Operator authenticates with the Doppler API
+	Auth DopplerAuth `json:"auth"`
+
+	// Project is the Doppler project to read secrets from.
+	// Not required when using a service token as it is scoped to a single config.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Config is the Doppler config (environment) to read secrets from.
+	// Not required when using a service token as it is scoped to a single config.
+	// +optional
+	Config string `json:"config,omitempty"`
+
+	// URL configures the Doppler API URL. Defaults to https://api.doppler.com.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+type DopplerAuth struct {
+	SecretRef DopplerAuthSecretRef `json:"secretRef"`
+}
+
+type DopplerAuthSecretRef struct {
+	// The DopplerToken is used for authentication.
+	// See https://docs.doppler.com/reference/auth-token-formats for auth token types.
+	DopplerToken esmeta.SecretKeySelector `json:"dopplerToken"`
+}
@@ -0,0 +1,55 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// EtcdProvider configures a store to sync keys of an external etcd v3 cluster.
+type EtcdProvider struct {
+	// Endpoints are the client URLs of the etcd members, e.g. https://etcd-0.example.com:2379.
+	// They are tried in order until one responds.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+
+	// Auth configures how the Operator authenticates with etcd.
+	// Client certificate and username/password authentication can be combined.
+	// +optional
+	Auth *EtcdAuth `json:"auth,omitempty"`
+
+	// PEM encoded CA bundle used to validate the etcd server certificates.
+	// If not set the system root certificates are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+type EtcdAuth struct {
+	// has both clientCert and clientKey as secretKeySelector
+	// +optional
+	Cert *CertAuth `json:"cert,omitempty"`
+
+	// authenticates with the username and password of an etcd user
+	// +optional
+	SecretRef *EtcdAuthSecretRef `json:"secretRef,omitempty"`
+}
+
+type EtcdAuthSecretRef struct {
+	// The Username of an etcd user with read permission on the keys.
+	Username esmeta.SecretKeySelector `json:"username"`
+
+	// The Password of the etcd user.
+	Password esmeta.SecretKeySelector `json:"password"`
+}
@@ -0,0 +1,39 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// FortanixProvider configures a store to sync security objects of a Fortanix DSM group.
+type FortanixProvider struct {
+	// Auth configures how the Operator authenticates with Fortanix DSM
+	Auth FortanixAuth `json:"auth"`
+
+	// APIURL configures the Fortanix DSM API URL. Defaults to https://apps.smartkey.io.
+	// +optional
+	APIURL string `json:"apiUrl,omitempty"`
+}
+
+type FortanixAuth struct {
+	SecretRef FortanixAuthSecretRef `json:"secretRef"`
+}
+
+type FortanixAuthSecretRef struct {
+	// The APIKey of a Fortanix DSM application which is allowed to export the security objects.
+	// See https://support.fortanix.com/hc/en-us/articles/360015941132-User-s-Guide-Applications
+	APIKey esmeta.SecretKeySelector `json:"apiKey"`
+}
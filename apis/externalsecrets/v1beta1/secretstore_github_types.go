@@ -0,0 +1,68 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// GithubProvider configures a store to push secrets to GitHub Actions secrets.
+// Actions secrets are write-only, secrets can not be read from this store.
+type GithubProvider struct {
+	// URL of the GitHub API. For GitHub Enterprise Server use https://<host>/api/v3.
+	// +kubebuilder:default="https://api.github.com"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Auth configures how the Operator authenticates with GitHub
+	Auth GithubAuth `json:"auth"`
+
+	// Owner is the user or organization that owns the repository or the organization secrets.
+	Owner string `json:"owner"`
+
+	// Repository receives repository secrets. If not set, organization secrets of the Owner are written.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// Visibility of organization secrets, either all or private repositories of the organization.
+	// Defaults to private.
+	// +kubebuilder:validation:Enum=all;private
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// GithubAuth uses either a personal access token or a GitHub App.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type GithubAuth struct {
+	// Token is a personal access token with write access to the secrets.
+	// +optional
+	Token *esmeta.SecretKeySelector `json:"token,omitempty"`
+
+	// App authenticates as an installation of a GitHub App.
+	// +optional
+	App *GithubAppAuth `json:"app,omitempty"`
+}
+
+type GithubAppAuth struct {
+	// AppID is the id of the GitHub App.
+	AppID int64 `json:"appId"`
+
+	// InstallationID is the id of the installation in the account of the Owner.
+	InstallationID int64 `json:"installationId"`
+
+	// PrivateKey is the PEM encoded private key of the GitHub App.
+	PrivateKey esmeta.SecretKeySelector `json:"privateKey"`
+}
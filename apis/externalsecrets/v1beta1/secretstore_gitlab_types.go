@@ -28,6 +28,17 @@ type GitlabProvider struct {
 
 	// ProjectID specifies a project where secrets are located.
 	ProjectID string `json:"projectID,omitempty"`
+
+	// GroupIDs specifies groups whose CI/CD variables are used if a variable is not
+	// defined in the project. Groups are searched in the given order, list subgroups
+	// before their parent groups to mirror the variable inheritance of GitLab.
+	// +optional
+	GroupIDs []string `json:"groupIDs,omitempty"`
+
+	// Environment selects the environment scope of the variables. Variables scoped
+	// to the environment take precedence over variables of the wildcard scope "*".
+	// +optional
+	Environment string `json:"environment,omitempty"`
 }
 
 type GitlabAuth struct {
@@ -0,0 +1,75 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// InfisicalProvider configures a store to sync secrets from an Infisical project environment.
+type InfisicalProvider struct {
+	// Auth configures how the Operator authenticates with Infisical.
+	Auth InfisicalAuth `json:"auth"`
+
+	// SecretsScope selects the project, environment and folder secrets are read from.
+	SecretsScope InfisicalSecretsScope `json:"secretsScope"`
+
+	// HostAPI configures the Infisical API URL. Defaults to https://app.infisical.com/api.
+	// +optional
+	HostAPI string `json:"hostAPI,omitempty"`
+}
+
+// InfisicalAuth configures the machine identity used to authenticate.
+// Exactly one auth method must be set.
+type InfisicalAuth struct {
+	// UniversalAuthCredentials authenticates with the client id and secret of a machine identity.
+	// +optional
+	UniversalAuthCredentials *InfisicalUniversalAuthCredentials `json:"universalAuthCredentials,omitempty"`
+
+	// KubernetesAuthCredentials authenticates a machine identity with the service account token of the operator.
+	// +optional
+	KubernetesAuthCredentials *InfisicalKubernetesAuthCredentials `json:"kubernetesAuthCredentials,omitempty"`
+}
+
+type InfisicalUniversalAuthCredentials struct {
+	ClientID     esmeta.SecretKeySelector `json:"clientId"`
+	ClientSecret esmeta.SecretKeySelector `json:"clientSecret"`
+}
+
+type InfisicalKubernetesAuthCredentials struct {
+	// IdentityID is the id of the machine identity.
+	IdentityID string `json:"identityId"`
+
+	// ServiceAccountTokenPath is the path of the service account token.
+	// Defaults to /var/run/secrets/kubernetes.io/serviceaccount/token.
+	// +optional
+	ServiceAccountTokenPath string `json:"serviceAccountTokenPath,omitempty"`
+}
+
+type InfisicalSecretsScope struct {
+	// ProjectSlug is the slug of the Infisical project.
+	ProjectSlug string `json:"projectSlug"`
+
+	// EnvironmentSlug is the slug of the environment, e.g. dev or prod.
+	EnvironmentSlug string `json:"environmentSlug"`
+
+	// SecretsPath is the folder secrets are read from. Defaults to /.
+	// +optional
+	SecretsPath string `json:"secretsPath,omitempty"`
+
+	// Recursive includes secrets of sub folders when finding secrets.
+	// +optional
+	Recursive bool `json:"recursive,omitempty"`
+}
@@ -47,7 +47,13 @@ type KubernetesProvider struct {
 	Server KubernetesServer `json:"server,omitempty"`
 
 	// Auth configures how secret-manager authenticates with a Kubernetes instance.
-	Auth KubernetesAuth `json:"auth"`
+	// +optional
+	Auth *KubernetesAuth `json:"auth,omitempty"`
+
+	// A reference to a secret that contains a kubeconfig to authenticate with
+	// a Kubernetes instance. Server and Auth are ignored if set.
+	// +optional
+	AuthRef *esmeta.SecretKeySelector `json:"authRef,omitempty"`
 
 	// Remote namespace to fetch the secrets from
 	// +kubebuilder:default= default
@@ -83,5 +89,11 @@ type TokenAuth struct {
 }
 
 type ServiceAccountAuth struct {
+	// A token is requested for this service account with the TokenRequest API
+	// of the cluster external-secrets runs in.
 	ServiceAccountRef esmeta.ServiceAccountSelector `json:"serviceAccount,omitempty"`
+
+	// Audiences of the requested token. Defaults to the audiences of the API server.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
 }
@@ -29,8 +29,27 @@ type OracleProvider struct {
 	// If empty, use the instance principal, otherwise the user credentials specified in Auth.
 	// +optional
 	Auth *OracleAuth `json:"auth,omitempty"`
+
+	// PrincipalType is the type of principal used to authenticate with the Oracle Vault.
+	// If empty, it is derived from Auth. Must be set to use the workload identity of OKE.
+	// +optional
+	PrincipalType OraclePrincipalType `json:"principalType,omitempty"`
 }
 
+// +kubebuilder:validation:Enum="";UserPrincipal;InstancePrincipal;Workload
+type OraclePrincipalType string
+
+const (
+	// UserPrincipal authenticates with the user credentials specified in Auth.
+	UserPrincipal OraclePrincipalType = "UserPrincipal"
+
+	// InstancePrincipal authenticates with the identity of the compute instance.
+	InstancePrincipal OraclePrincipalType = "InstancePrincipal"
+
+	// WorkloadPrincipal authenticates with the OKE workload identity of the external-secrets service account.
+	WorkloadPrincipal OraclePrincipalType = "Workload"
+)
+
 type OracleAuth struct {
 	// Tenancy is the tenancy OCID where user is located.
 	Tenancy string `json:"tenancy"`
@@ -0,0 +1,40 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// PassboltProvider configures a store to sync secrets from a Passbolt instance.
+type PassboltProvider struct {
+	// Auth configures how the Operator authenticates with Passbolt
+	Auth PassboltAuth `json:"auth"`
+
+	// Host is the URL of the Passbolt instance, e.g. https://passbolt.example.com.
+	Host string `json:"host"`
+}
+
+type PassboltAuth struct {
+	SecretRef PassboltAuthSecretRef `json:"secretRef"`
+}
+
+type PassboltAuthSecretRef struct {
+	// PrivateKey is the ASCII armored GPG private key of the Passbolt user.
+	PrivateKey esmeta.SecretKeySelector `json:"privateKey"`
+
+	// Passphrase of the private key.
+	Passphrase esmeta.SecretKeySelector `json:"passphrase"`
+}
@@ -0,0 +1,50 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// PulumiProvider configures a store to sync values of a Pulumi ESC environment.
+type PulumiProvider struct {
+	// Auth configures how the Operator authenticates with the Pulumi Cloud API
+	Auth PulumiAuth `json:"auth"`
+
+	// Organization is the Pulumi Cloud organization that owns the environment.
+	Organization string `json:"organization"`
+
+	// Project is the ESC project of the environment.
+	// +kubebuilder:default="default"
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Environment is the name of the ESC environment to open.
+	Environment string `json:"environment"`
+
+	// APIURL configures the Pulumi Cloud API URL. Defaults to https://api.pulumi.com.
+	// +optional
+	APIURL string `json:"apiUrl,omitempty"`
+}
+
+type PulumiAuth struct {
+	SecretRef PulumiAuthSecretRef `json:"secretRef"`
+}
+
+type PulumiAuthSecretRef struct {
+	// The AccessToken is a Pulumi personal, team or organization access token.
+	// See https://www.pulumi.com/docs/pulumi-cloud/access-management/access-tokens/
+	AccessToken esmeta.SecretKeySelector `json:"accessToken"`
+}
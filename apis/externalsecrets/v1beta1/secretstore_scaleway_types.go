@@ -0,0 +1,48 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// ScalewayProvider configures a store to sync secrets from Scaleway Secret Manager.
+type ScalewayProvider struct {
+	// Auth configures how the Operator authenticates with the Scaleway API
+	Auth ScalewayAuth `json:"auth"`
+
+	// Region is the Scaleway region of the secrets, e.g. fr-par.
+	// See https://www.scaleway.com/en/docs/console/my-account/reference-content/products-availability/
+	Region string `json:"region"`
+
+	// ProjectID is the ID of the Scaleway project that holds the secrets.
+	ProjectID string `json:"projectId"`
+
+	// APIURL configures the Scaleway API URL. Defaults to https://api.scaleway.com.
+	// +optional
+	APIURL string `json:"apiUrl,omitempty"`
+}
+
+type ScalewayAuth struct {
+	SecretRef ScalewayAuthSecretRef `json:"secretRef"`
+}
+
+type ScalewayAuthSecretRef struct {
+	// The AccessKey of the Scaleway API key.
+	AccessKey esmeta.SecretKeySelector `json:"accessKey"`
+
+	// The SecretKey of the Scaleway API key.
+	SecretKey esmeta.SecretKeySelector `json:"secretKey"`
+}
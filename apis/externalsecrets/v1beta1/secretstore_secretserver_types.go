@@ -0,0 +1,45 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// SecretServerProvider configures a store to sync secrets with Delinea (formerly Thycotic) Secret Server.
+type SecretServerProvider struct {
+	// ServerURL is the URL of the Secret Server instance,
+	// e.g. https://secretserver.example.com/SecretServer or https://tenant.secretservercloud.com.
+	ServerURL string `json:"serverURL"`
+
+	// Auth configures how the Operator authenticates with Secret Server.
+	Auth SecretServerAuth `json:"auth"`
+}
+
+// SecretServerAuth authenticates using the OAuth2 password grant.
+type SecretServerAuth struct {
+	SecretRef SecretServerAuthSecretRef `json:"secretRef"`
+
+	// Domain of the user for Active Directory accounts.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+}
+
+type SecretServerAuthSecretRef struct {
+	// Username is the name of the Secret Server user.
+	Username esmeta.SecretKeySelector `json:"username"`
+	// Password is the password of the Secret Server user.
+	Password esmeta.SecretKeySelector `json:"password"`
+}
@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// SOPSProvider configures a store to sync values of SOPS encrypted files of a Git repository.
+type SOPSProvider struct {
+	// Repository is the Git repository that holds the encrypted files.
+	Repository SOPSRepository `json:"repository"`
+
+	// Decryption configures the keys used to decrypt the data keys of the files.
+	Decryption SOPSDecryption `json:"decryption"`
+}
+
+type SOPSRepository struct {
+	// URL of the Git repository, e.g. https://github.com/example/secrets.git.
+	URL string `json:"url"`
+
+	// Branch to check out. Defaults to the default branch of the repository.
+	// +optional
+	Branch string `json:"branch,omitempty"`
+
+	// Auth configures how the Operator authenticates with the Git server.
+	// +optional
+	Auth *SOPSRepositoryAuth `json:"auth,omitempty"`
+}
+
+type SOPSRepositoryAuth struct {
+	SecretRef SOPSRepositoryAuthSecretRef `json:"secretRef"`
+}
+
+// SOPSRepositoryAuthSecretRef holds the HTTP basic auth credentials of the Git server.
+type SOPSRepositoryAuthSecretRef struct {
+	// The Username, defaults to git which works for access tokens of most Git hosts.
+	// +optional
+	Username *esmeta.SecretKeySelector `json:"username,omitempty"`
+
+	// The Password or access token.
+	Password esmeta.SecretKeySelector `json:"password"`
+}
+
+// SOPSDecryption configures at least one of age or kms.
+type SOPSDecryption struct {
+	// Age references age identities (AGE-SECRET-KEY-1...), one per line.
+	// +optional
+	Age *SOPSAgeDecryption `json:"age,omitempty"`
+
+	// KMS decrypts data keys with AWS KMS.
+	// +optional
+	KMS *SOPSKMSDecryption `json:"kms,omitempty"`
+}
+
+type SOPSAgeDecryption struct {
+	SecretRef esmeta.SecretKeySelector `json:"secretRef"`
+}
+
+type SOPSKMSDecryption struct {
+	// AWS Region of the KMS keys. Defaults to the region of the key ARN.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// SecretRef holds static AWS credentials.
+	// If not set the default credential chain of the controller is used.
+	// +optional
+	SecretRef *AWSAuthSecretRef `json:"secretRef,omitempty"`
+}
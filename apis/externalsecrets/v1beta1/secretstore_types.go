@@ -29,9 +29,29 @@ type SecretStoreSpec struct {
 	// Used to configure the provider. Only one provider may be set
 	Provider *SecretStoreProvider `json:"provider"`
 
-	// Used to configure http retries if failed
+	// Used to configure retries of failed provider requests
 	// +optional
 	RetrySettings *SecretStoreRetrySettings `json:"retrySettings,omitempty"`
+
+	// Used to limit the rate of provider requests
+	// +optional
+	RateLimit *SecretStoreRateLimit `json:"rateLimit,omitempty"`
+
+	// Used to constrain a ClusterSecretStore to specific namespaces. Relevant only to ClusterSecretStore
+	// +optional
+	Conditions []ClusterSecretStoreCondition `json:"conditions,omitempty"`
+}
+
+// ClusterSecretStoreCondition describes a condition by which to choose namespaces to process ExternalSecrets in
+// for a ClusterSecretStore instance. A namespace is allowed if it matches any of the conditions.
+type ClusterSecretStoreCondition struct {
+	// Choose namespaces using a labelSelector
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Choose namespaces by name
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
 }
 
 // SecretStoreProvider contains the provider-specific configration.
@@ -89,11 +109,94 @@ type SecretStoreProvider struct {
 	// Fake configures a store with static key/value pairs
 	// +optional
 	Fake *FakeProvider `json:"fake,omitempty"`
+
+	// Doppler configures this store to sync secrets using the Doppler provider
+	// +optional
+	Doppler *DopplerProvider `json:"doppler,omitempty"`
+
+	// BitwardenSecretsManager configures this store to sync secrets using the Bitwarden Secrets Manager provider
+	// +optional
+	BitwardenSecretsManager *BitwardenSecretsManagerProvider `json:"bitwardensecretsmanager,omitempty"`
+
+	// SecretServer configures this store to sync secrets using the Delinea Secret Server provider
+	// +optional
+	SecretServer *SecretServerProvider `json:"secretserver,omitempty"`
+
+	// Infisical configures this store to sync secrets using the Infisical provider
+	// +optional
+	Infisical *InfisicalProvider `json:"infisical,omitempty"`
+
+	// Scaleway configures this store to sync secrets using the Scaleway Secret Manager provider
+	// +optional
+	Scaleway *ScalewayProvider `json:"scaleway,omitempty"`
+
+	// Pulumi configures this store to sync secrets using the Pulumi ESC provider
+	// +optional
+	Pulumi *PulumiProvider `json:"pulumi,omitempty"`
+
+	// Passbolt configures this store to sync secrets using the Passbolt provider
+	// +optional
+	Passbolt *PassboltProvider `json:"passbolt,omitempty"`
+
+	// Fortanix configures this store to sync secrets using the Fortanix DSM provider
+	// +optional
+	Fortanix *FortanixProvider `json:"fortanix,omitempty"`
+
+	// BeyondTrust configures this store to sync secrets using the BeyondTrust Password Safe provider
+	// +optional
+	BeyondTrust *BeyondTrustProvider `json:"beyondtrust,omitempty"`
+
+	// Consul configures this store to sync secrets using the HashiCorp Consul KV provider
+	// +optional
+	Consul *ConsulProvider `json:"consul,omitempty"`
+
+	// Etcd configures this store to sync secrets using the etcd provider
+	// +optional
+	Etcd *EtcdProvider `json:"etcd,omitempty"`
+
+	// SOPS configures this store to sync secrets from SOPS encrypted files of a Git repository
+	// +optional
+	SOPS *SOPSProvider `json:"sops,omitempty"`
+
+	// Chef configures this store to sync secrets using the Chef Infra Server provider
+	// +optional
+	Chef *ChefProvider `json:"chef,omitempty"`
+
+	// Github configures this store to push secrets to GitHub Actions secrets
+	// +optional
+	Github *GithubProvider `json:"github,omitempty"`
 }
 
+// SecretStoreRetrySettings configures how failed provider requests are retried
+// before the sync of an ExternalSecret or PushSecret fails.
 type SecretStoreRetrySettings struct {
-	MaxRetries    *int32  `json:"maxRetries,omitempty"`
+	// MaxRetries is the number of retries of a failed request. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// RetryInterval is the time to wait before the first retry, e.g. "500ms" or "5s". Defaults to 5s.
+	// +optional
 	RetryInterval *string `json:"retryInterval,omitempty"`
+
+	// BackoffMultiplier multiplies the retry interval after every retry. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	BackoffMultiplier *int32 `json:"backoffMultiplier,omitempty"`
+}
+
+// SecretStoreRateLimit limits the rate of the provider requests of a store,
+// shared by all ExternalSecrets and PushSecrets that use the store.
+type SecretStoreRateLimit struct {
+	// QPS is the number of provider requests per second.
+	// +kubebuilder:validation:Minimum=1
+	QPS int32 `json:"qps"`
+
+	// Burst is the number of requests that may be sent at once above the QPS. Defaults to QPS.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
 }
 
 type SecretStoreConditionType string
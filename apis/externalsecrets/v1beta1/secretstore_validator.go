@@ -17,7 +17,9 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -25,7 +27,13 @@ import (
 var _ admission.CustomValidator = &GenericStoreValidator{}
 
 const (
-	errInvalidStore = "invalid store"
+	errInvalidStore         = "invalid store"
+	errConditionsNotAllowed = "conditions are only allowed on a ClusterSecretStore"
+	errInvalidCondition     = "invalid conditions[%d].namespaceSelector: %w"
+	errInvalidRetryInterval = "invalid retrySettings.retryInterval: %w"
+	errInvalidBackoff       = "invalid retrySettings.backoffMultiplier: must be between 1 and %d"
+
+	maxBackoffMultiplier = 10
 )
 
 type GenericStoreValidator struct{}
@@ -54,9 +62,48 @@ func (r *GenericStoreValidator) ValidateDelete(ctx context.Context, obj runtime.
 }
 
 func validateStore(store GenericStore) error {
+	if err := validateConditions(store); err != nil {
+		return err
+	}
+	if err := validateRetrySettings(store); err != nil {
+		return err
+	}
 	provider, err := GetProvider(store)
 	if err != nil {
 		return err
 	}
 	return provider.ValidateStore(store)
 }
+
+func validateConditions(store GenericStore) error {
+	conditions := store.GetSpec().Conditions
+	if _, ok := store.(*SecretStore); ok && len(conditions) > 0 {
+		return fmt.Errorf(errConditionsNotAllowed)
+	}
+	for i, condition := range conditions {
+		if condition.NamespaceSelector == nil {
+			continue
+		}
+		if _, err := metav1.LabelSelectorAsSelector(condition.NamespaceSelector); err != nil {
+			return fmt.Errorf(errInvalidCondition, i, err)
+		}
+	}
+	return nil
+}
+
+func validateRetrySettings(store GenericStore) error {
+	settings := store.GetSpec().RetrySettings
+	if settings == nil {
+		return nil
+	}
+	if m := settings.BackoffMultiplier; m != nil && (*m < 1 || *m > maxBackoffMultiplier) {
+		return fmt.Errorf(errInvalidBackoff, maxBackoffMultiplier)
+	}
+	if settings.RetryInterval == nil {
+		return nil
+	}
+	if _, err := time.ParseDuration(*settings.RetryInterval); err != nil {
+		return fmt.Errorf(errInvalidRetryInterval, err)
+	}
+	return nil
+}
@@ -67,11 +67,11 @@ type VaultProvider struct {
 	Path *string `json:"path"`
 
 	// Version is the Vault KV secret engine version. This can be either "v1" or
-	// "v2". Version defaults to "v2".
+	// "v2". If not set, the version of the engine mounted at Path is detected,
+	// without a Path it defaults to "v2".
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum="v1";"v2"
-	// +kubebuilder:default:="v2"
-	Version VaultKVStoreVersion `json:"version"`
+	Version VaultKVStoreVersion `json:"version,omitempty"`
 
 	// Name of the vault namespace. Namespaces is a set of features within Vault Enterprise that allows
 	// Vault environments to support Secure Multi-tenancy. e.g: "ns1".
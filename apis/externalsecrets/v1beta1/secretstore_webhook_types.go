@@ -20,7 +20,7 @@ import (
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 )
 
-// AkeylessProvider Configures an store to sync secrets using Akeyless KV.
+// WebhookProvider configures a store to sync secrets from a generic HTTP(S) endpoint.
 type WebhookProvider struct {
 	// Webhook Method
 	// +optional, default GET
@@ -90,6 +90,11 @@ type WebhookResult struct {
 	// Json path of return value
 	// +optional
 	JSONPath string `json:"jsonPath,omitempty"`
+
+	// GJSON expression of return value, see https://github.com/tidwall/gjson.
+	// Can not be combined with jsonPath
+	// +optional
+	GJSON string `json:"gjson,omitempty"`
 }
 
 type WebhookSecret struct {
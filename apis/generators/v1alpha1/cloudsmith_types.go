@@ -0,0 +1,78 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// CloudsmithAccessTokenSpec defines the service account and the OIDC token to exchange.
+// see: https://help.cloudsmith.io/docs/openid-connect
+type CloudsmithAccessTokenSpec struct {
+	// APIURL configures the Cloudsmith API URL.
+	// Defaults to https://api.cloudsmith.io
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+
+	// Registry is the host of the Cloudsmith registry the token is used for.
+	// Defaults to docker.cloudsmith.io
+	// +optional
+	Registry string `json:"registry,omitempty"`
+
+	// OrgSlug is the slug of the organization with the OIDC provider configured.
+	OrgSlug string `json:"orgSlug"`
+
+	// ServiceSlug is the slug of the service account the OIDC token is exchanged for.
+	ServiceSlug string `json:"serviceSlug"`
+
+	// Auth defines the OIDC token which is exchanged for the API token.
+	// Exactly one of serviceAccountRef or secretRef must be set.
+	Auth CloudsmithAuth `json:"auth"`
+}
+
+// CloudsmithAuth defines the OIDC token of the Cloudsmith OIDC provider.
+type CloudsmithAuth struct {
+	// ServiceAccountRef is a service account in the namespace of the generator,
+	// a token of the service account is requested with the Cloudsmith API host as audience.
+	// +optional
+	ServiceAccountRef *smmeta.ServiceAccountSelector `json:"serviceAccountRef,omitempty"`
+
+	// SecretRef references an OIDC token of a trusted issuer
+	// in a secret in the namespace of the generator.
+	// +optional
+	SecretRef *smmeta.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// CloudsmithAccessToken exchanges OIDC tokens for short-lived API tokens
+// of a Cloudsmith service account, which are used to pull packages and images.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={cloudsmithaccesstoken}
+type CloudsmithAccessToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CloudsmithAccessTokenSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CloudsmithAccessTokenList contains a list of CloudsmithAccessToken resources.
+type CloudsmithAccessTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudsmithAccessToken `json:"items"`
+}
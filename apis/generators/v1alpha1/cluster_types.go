@@ -0,0 +1,155 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// GeneratorKind is the kind of a generator.
+// +kubebuilder:validation:Enum=ACRAccessToken;CloudsmithAccessToken;ECRAuthorizationToken;Fake;GCRAccessToken;GithubAccessToken;Grafana;Password;QuayAccessToken;SSHKey;STSSessionToken;TLSCertificate;TOTP;UUID;VaultDynamicSecret;Webhook
+type GeneratorKind string
+
+// ClusterGeneratorSpec wraps the spec of a generator of any kind.
+type ClusterGeneratorSpec struct {
+	// Kind the kind of this generator.
+	Kind GeneratorKind `json:"kind"`
+
+	// Generator the spec for this generator, must match the kind.
+	Generator GeneratorSpec `json:"generator"`
+
+	// Used to constraint a ClusterGenerator to specific namespaces. A namespace may use
+	// the generator if it matches any of the conditions, all namespaces may use it without conditions.
+	// +optional
+	Conditions []esv1beta1.ClusterSecretStoreCondition `json:"conditions,omitempty"`
+}
+
+// GeneratorSpec holds the spec of exactly one generator kind.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type GeneratorSpec struct {
+	ACRAccessTokenSpec        *ACRAccessTokenSpec        `json:"acrAccessTokenSpec,omitempty"`
+	CloudsmithAccessTokenSpec *CloudsmithAccessTokenSpec `json:"cloudsmithAccessTokenSpec,omitempty"`
+	ECRAuthorizationTokenSpec *ECRAuthorizationTokenSpec `json:"ecrAuthorizationTokenSpec,omitempty"`
+	FakeSpec                  *FakeSpec                  `json:"fakeSpec,omitempty"`
+	GCRAccessTokenSpec        *GCRAccessTokenSpec        `json:"gcrAccessTokenSpec,omitempty"`
+	GithubAccessTokenSpec     *GithubAccessTokenSpec     `json:"githubAccessTokenSpec,omitempty"`
+	GrafanaSpec               *GrafanaSpec               `json:"grafanaSpec,omitempty"`
+	PasswordSpec              *PasswordSpec              `json:"passwordSpec,omitempty"`
+	QuayAccessTokenSpec       *QuayAccessTokenSpec       `json:"quayAccessTokenSpec,omitempty"`
+	SSHKeySpec                *SSHKeySpec                `json:"sshKeySpec,omitempty"`
+	STSSessionTokenSpec       *STSSessionTokenSpec       `json:"stsSessionTokenSpec,omitempty"`
+	TLSCertificateSpec        *TLSCertificateSpec        `json:"tlsCertificateSpec,omitempty"`
+	TOTPSpec                  *TOTPSpec                  `json:"totpSpec,omitempty"`
+	UUIDSpec                  *UUIDSpec                  `json:"uuidSpec,omitempty"`
+	VaultDynamicSecretSpec    *VaultDynamicSecretSpec    `json:"vaultDynamicSecretSpec,omitempty"`
+	WebhookSpec               *WebhookSpec               `json:"webhookSpec,omitempty"`
+}
+
+// ClusterGenerator is a cluster-scoped generator that can be referenced
+// from ExternalSecrets in any namespace allowed by its conditions.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories={clustergenerator}
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.kind`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type ClusterGenerator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterGeneratorSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterGeneratorList contains a list of ClusterGenerator resources.
+type ClusterGeneratorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterGenerator `json:"items"`
+}
+
+// ForKind returns the spec of the generator kind or nil if it is not set.
+func (s *GeneratorSpec) ForKind(kind GeneratorKind) interface{} {
+	var spec interface{}
+	switch kind {
+	case GeneratorKind(ACRAccessTokenKind):
+		if s.ACRAccessTokenSpec != nil {
+			spec = s.ACRAccessTokenSpec
+		}
+	case GeneratorKind(CloudsmithAccessTokenKind):
+		if s.CloudsmithAccessTokenSpec != nil {
+			spec = s.CloudsmithAccessTokenSpec
+		}
+	case GeneratorKind(ECRAuthorizationTokenKind):
+		if s.ECRAuthorizationTokenSpec != nil {
+			spec = s.ECRAuthorizationTokenSpec
+		}
+	case GeneratorKind(FakeKind):
+		if s.FakeSpec != nil {
+			spec = s.FakeSpec
+		}
+	case GeneratorKind(GCRAccessTokenKind):
+		if s.GCRAccessTokenSpec != nil {
+			spec = s.GCRAccessTokenSpec
+		}
+	case GeneratorKind(GithubAccessTokenKind):
+		if s.GithubAccessTokenSpec != nil {
+			spec = s.GithubAccessTokenSpec
+		}
+	case GeneratorKind(GrafanaKind):
+		if s.GrafanaSpec != nil {
+			spec = s.GrafanaSpec
+		}
+	case GeneratorKind(PasswordKind):
+		if s.PasswordSpec != nil {
+			spec = s.PasswordSpec
+		}
+	case GeneratorKind(QuayAccessTokenKind):
+		if s.QuayAccessTokenSpec != nil {
+			spec = s.QuayAccessTokenSpec
+		}
+	case GeneratorKind(SSHKeyKind):
+		if s.SSHKeySpec != nil {
+			spec = s.SSHKeySpec
+		}
+	case GeneratorKind(STSSessionTokenKind):
+		if s.STSSessionTokenSpec != nil {
+			spec = s.STSSessionTokenSpec
+		}
+	case GeneratorKind(TLSCertificateKind):
+		if s.TLSCertificateSpec != nil {
+			spec = s.TLSCertificateSpec
+		}
+	case GeneratorKind(TOTPKind):
+		if s.TOTPSpec != nil {
+			spec = s.TOTPSpec
+		}
+	case GeneratorKind(UUIDKind):
+		if s.UUIDSpec != nil {
+			spec = s.UUIDSpec
+		}
+	case GeneratorKind(VaultDynamicSecretKind):
+		if s.VaultDynamicSecretSpec != nil {
+			spec = s.VaultDynamicSecretSpec
+		}
+	case GeneratorKind(WebhookKind):
+		if s.WebhookSpec != nil {
+			spec = s.WebhookSpec
+		}
+	}
+	return spec
+}
@@ -0,0 +1,67 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// ECRAuthorizationTokenSpec configures the AWS account and region of the ECR registry.
+type ECRAuthorizationTokenSpec struct {
+	// Region specifies the region to operate in.
+	Region string `json:"region"`
+
+	// Auth defines how to authenticate with AWS.
+	// If not set the aws sdk infers the credentials from the environment of the controller.
+	// The referenced secrets and service accounts must exist in the namespace of the generator.
+	// +optional
+	Auth esv1beta1.AWSAuth `json:"auth,omitempty"`
+
+	// Role is a Role ARN which is assumed before the authorization token is requested.
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// RotationPolicy defines if a new authorization token is requested on every refresh of the ExternalSecret (Rotate)
+	// or only if it is missing in the target secret or expires before the next refresh (OnlyWhenMissing).
+	// Defaults to Rotate
+	// +kubebuilder:default=Rotate
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+}
+
+// ECRAuthorizationToken uses the GetAuthorizationToken API to retrieve an
+// authorization token. The authorization token is valid for 12 hours.
+// The authorizationToken returned is a base64 encoded string that can be decoded
+// and used in a docker login command to authenticate to a registry.
+// For more information, see Registry authentication (https://docs.aws.amazon.com/AmazonECR/latest/userguide/Registries.html#registry_auth) in the Amazon Elastic Container Registry User Guide.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={ecrauthorizationtoken}
+type ECRAuthorizationToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ECRAuthorizationTokenSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ECRAuthorizationTokenList contains a list of ECRAuthorizationToken resources.
+type ECRAuthorizationTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ECRAuthorizationToken `json:"items"`
+}
@@ -0,0 +1,60 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// GCRAccessTokenSpec configures the GCP credentials used to request an access token.
+type GCRAccessTokenSpec struct {
+	// Auth defines the means for authenticating with GCP.
+	// If not set the application default credentials of the controller are used.
+	// The referenced secrets and service accounts must exist in the namespace of the generator.
+	// +optional
+	Auth esv1beta1.GCPSMAuth `json:"auth,omitempty"`
+
+	// ProjectID defines which project to use to authenticate with.
+	ProjectID string `json:"projectID"`
+
+	// RotationPolicy defines if a new access token is requested on every refresh of the ExternalSecret (Rotate)
+	// or only if it is missing in the target secret or expires before the next refresh (OnlyWhenMissing).
+	// Defaults to Rotate
+	// +kubebuilder:default=Rotate
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+}
+
+// GCRAccessToken generates an GCP access token
+// that can be used to authenticate with GCR and Artifact Registry.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={gcraccesstoken}
+type GCRAccessToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GCRAccessTokenSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GCRAccessTokenList contains a list of GCRAccessToken resources.
+type GCRAccessTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GCRAccessToken `json:"items"`
+}
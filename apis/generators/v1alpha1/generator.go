@@ -0,0 +1,103 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RotationPolicy defines when the data of a generator is replaced in the target secret.
+// +kubebuilder:validation:Enum=Rotate;OnlyWhenMissing
+type RotationPolicy string
+
+const (
+	// RotationPolicyRotate replaces the data on every refresh of the ExternalSecret.
+	RotationPolicyRotate RotationPolicy = "Rotate"
+	// RotationPolicyOnlyWhenMissing keeps the data of the target secret and generates
+	// new data only if keys of the generator are missing in it or the data expires
+	// before the next refresh. The generator is not called while the data is kept.
+	RotationPolicyOnlyWhenMissing RotationPolicy = "OnlyWhenMissing"
+)
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// Generator is the common interface of generator implementations.
+// A generator produces secret data from its custom resource instead of
+// fetching it from a SecretStore.
+type Generator interface {
+	// Generate returns the generated data of the generator resource obj,
+	// obj holds the whole resource as JSON.
+	Generate(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// StatefulGenerator is implemented by generators which create resources
+// at the provider, like leases or tokens, which must be cleaned up once
+// the generated data is no longer used.
+// The controller records the returned state in a GeneratorState resource.
+type StatefulGenerator interface {
+	Generator
+	// GenerateWithState returns the generated data of the generator resource obj
+	// and the state required to clean up the resources created for it.
+	// A nil state signals that nothing has to be cleaned up.
+	GenerateWithState(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, *apiextensions.JSON, error)
+	// Cleanup deletes or revokes the resources recorded in state,
+	// obj holds the generator resource that produced the state.
+	Cleanup(ctx context.Context, obj, state *apiextensions.JSON, kube client.Client, namespace string) error
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// ExpiringGenerator is implemented by generators whose data expires,
+// like temporary credentials or certificates. Data of generators with
+// rotationPolicy=OnlyWhenMissing is generated again before it expires.
+type ExpiringGenerator interface {
+	Generator
+	// Expiry returns when the data returned by Generate expires,
+	// it is called right after the data was generated.
+	// It returns false if the expiry of data is unknown.
+	Expiry(data map[string][]byte) (time.Time, bool)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// RenewingGenerator is implemented by stateful generators whose data expires
+// and can be renewed, like leases. Instead of generating the data again,
+// the controller renews kept data of generators with rotationPolicy=OnlyWhenMissing
+// before it expires.
+type RenewingGenerator interface {
+	StatefulGenerator
+	ExpiringGenerator
+	// Renew extends the lifetime of the data recorded in state and returns
+	// when the data expires, obj holds the generator resource that produced the state.
+	Renew(ctx context.Context, obj, state *apiextensions.JSON, kube client.Client, namespace string) (time.Time, error)
+}
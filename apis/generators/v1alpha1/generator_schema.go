@@ -0,0 +1,56 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"sync"
+)
+
+var builder map[string]Generator
+var buildlock sync.RWMutex
+
+func init() {
+	builder = make(map[string]Generator)
+}
+
+// Register a generator implementation for the resource kind.
+// Register panics if a generator with the same kind is already registered.
+func Register(kind string, g Generator) {
+	buildlock.Lock()
+	defer buildlock.Unlock()
+	_, exists := builder[kind]
+	if exists {
+		panic(fmt.Sprintf("generator %q already registered", kind))
+	}
+
+	builder[kind] = g
+}
+
+// ForceRegister adds to the generator schema, overwriting a generator if
+// already registered. Should only be used for testing.
+func ForceRegister(kind string, g Generator) {
+	buildlock.Lock()
+	builder[kind] = g
+	buildlock.Unlock()
+}
+
+// GetGenerator returns the generator implementation of the resource kind.
+func GetGenerator(kind string) (Generator, bool) {
+	buildlock.RLock()
+	g, ok := builder[kind]
+	buildlock.RUnlock()
+	return g, ok
+}
@@ -0,0 +1,70 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GeneratorStateLabelOwner holds a hash of the namespace and name
+	// of the ExternalSecret that created the GeneratorState.
+	GeneratorStateLabelOwner = "generators.external-secrets.io/owner"
+	// GeneratorStateAnnotationSource holds the comma separated entries of the
+	// ExternalSecret that use the generated data, e.g. `data[0],data[1]`.
+	GeneratorStateAnnotationSource = "generators.external-secrets.io/source"
+	// GeneratorStateFinalizer prevents the deletion of a GeneratorState
+	// until the resources recorded in its state are cleaned up.
+	GeneratorStateFinalizer = "generators.external-secrets.io/cleanup"
+)
+
+// GeneratorStateSpec records the resources that a generator created at the provider.
+type GeneratorStateSpec struct {
+	// GarbageCollectionDeadline is the time after which the resources recorded in state
+	// are cleaned up and the GeneratorState is deleted.
+	// It is set once the generated data is superseded, e.g. by a rotation.
+	// +optional
+	GarbageCollectionDeadline *metav1.Time `json:"garbageCollectionDeadline,omitempty"`
+
+	// Resource is the generator resource that produced the state.
+	Resource *apiextensions.JSON `json:"resource"`
+
+	// State is the state returned by the generator, it is
+	// required to clean up the resources created at the provider.
+	State *apiextensions.JSON `json:"state"`
+}
+
+// GeneratorState records the resources a generator created for an ExternalSecret,
+// like Vault leases, so that they are revoked once the generated data is no longer used.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={generatorstate}
+// +kubebuilder:printcolumn:name="GC Deadline",type=string,JSONPath=`.spec.garbageCollectionDeadline`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type GeneratorState struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GeneratorStateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GeneratorStateList contains a list of GeneratorState resources.
+type GeneratorStateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GeneratorState `json:"items"`
+}
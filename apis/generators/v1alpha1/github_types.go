@@ -0,0 +1,88 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// GithubAccessTokenSpec defines the GitHub App and the installation to create tokens for.
+// see: https://docs.github.com/en/rest/apps/apps#create-an-installation-access-token-for-an-app
+type GithubAccessTokenSpec struct {
+	// URL configures the GitHub API URL, e.g. of a GitHub Enterprise Server.
+	// Defaults to https://api.github.com/
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// AppID is the ID of the GitHub App.
+	AppID string `json:"appID"`
+
+	// InstallID is the ID of the installation of the GitHub App.
+	InstallID string `json:"installID"`
+
+	// Repositories restricts the token to the repositories with these names.
+	// If not set the token has access to all repositories of the installation.
+	// +optional
+	Repositories []string `json:"repositories,omitempty"`
+
+	// Permissions restricts the permissions of the token, e.g. contents: read.
+	// If not set the token has all permissions of the installation.
+	// +optional
+	Permissions map[string]string `json:"permissions,omitempty"`
+
+	// Auth configures how to authenticate as the GitHub App.
+	Auth GithubAuth `json:"auth"`
+
+	// RotationPolicy defines if a new installation token is requested on every refresh of the ExternalSecret (Rotate)
+	// or only if it is missing in the target secret or expires before the next refresh (OnlyWhenMissing).
+	// Defaults to Rotate
+	// +kubebuilder:default=Rotate
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+}
+
+// GithubAuth defines the authentication of the GitHub App.
+type GithubAuth struct {
+	// PrivateKey is the private key of the GitHub App.
+	PrivateKey GithubSecretRef `json:"privateKey"`
+}
+
+// GithubSecretRef references a secret value.
+type GithubSecretRef struct {
+	// SecretRef references the key of a secret in the namespace of the generator.
+	SecretRef smmeta.SecretKeySelector `json:"secretRef"`
+}
+
+// GithubAccessToken generates short-lived installation access tokens of a GitHub App.
+// The tokens expire after one hour.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={githubaccesstoken}
+type GithubAccessToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GithubAccessTokenSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubAccessTokenList contains a list of GithubAccessToken resources.
+type GithubAccessTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubAccessToken `json:"items"`
+}
@@ -0,0 +1,94 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// GrafanaSpec defines the Grafana instance and the service account to create tokens for.
+type GrafanaSpec struct {
+	// URL is the URL of the Grafana instance.
+	URL string `json:"url"`
+
+	// Auth defines how to authenticate with the Grafana HTTP API.
+	// Exactly one of token or basic must be set.
+	Auth GrafanaAuth `json:"auth"`
+
+	// ServiceAccount is the service account which the tokens are created for.
+	// It is created if it doesn't exist.
+	ServiceAccount GrafanaServiceAccount `json:"serviceAccount"`
+
+	// TokenTTL is the lifetime of the tokens.
+	// If not set the tokens don't expire.
+	// +optional
+	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
+}
+
+// GrafanaAuth defines the authentication with the Grafana HTTP API.
+type GrafanaAuth struct {
+	// Token references a service account token or API key
+	// with permissions to manage service accounts.
+	// +optional
+	Token *smmeta.SecretKeySelector `json:"token,omitempty"`
+
+	// Basic authenticates with username and password of a Grafana admin.
+	// +optional
+	Basic *GrafanaBasicAuth `json:"basic,omitempty"`
+}
+
+// GrafanaBasicAuth defines the username and password of basic authentication.
+type GrafanaBasicAuth struct {
+	// Username of the Grafana user.
+	Username string `json:"username"`
+
+	// Password references the password of the Grafana user.
+	Password smmeta.SecretKeySelector `json:"password"`
+}
+
+// GrafanaServiceAccount defines the service account of the tokens.
+type GrafanaServiceAccount struct {
+	// Name of the service account.
+	Name string `json:"name"`
+
+	// Role of the service account when it is created: Viewer, Editor or Admin.
+	// Defaults to Viewer
+	// +kubebuilder:validation:Enum=Viewer;Editor;Admin
+	// +kubebuilder:default=Viewer
+	// +optional
+	Role string `json:"role,omitempty"`
+}
+
+// Grafana creates service account tokens via the Grafana HTTP API.
+// Tokens superseded by newer tokens of the generator are deleted.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={grafana}
+type Grafana struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GrafanaSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GrafanaList contains a list of Grafana resources.
+type GrafanaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Grafana `json:"items"`
+}
@@ -0,0 +1,71 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// QuayAccessTokenSpec defines the robot account and the federation token to exchange.
+// see: https://docs.projectquay.io/manage_quay.html#keyless-authentication-robot-accounts
+type QuayAccessTokenSpec struct {
+	// URL configures the Quay instance URL.
+	// Defaults to quay.io
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// RobotAccount is the name of the robot account with a federation configured,
+	// e.g. my-org+my-robot.
+	RobotAccount string `json:"robotAccount"`
+
+	// Auth defines the OIDC token which is exchanged for the robot token.
+	// Exactly one of serviceAccountRef or secretRef must be set.
+	Auth QuayAuth `json:"auth"`
+}
+
+// QuayAuth defines the OIDC token of the robot federation.
+type QuayAuth struct {
+	// ServiceAccountRef is a service account in the namespace of the generator,
+	// a token of the service account is requested with the Quay host as audience.
+	// +optional
+	ServiceAccountRef *smmeta.ServiceAccountSelector `json:"serviceAccountRef,omitempty"`
+
+	// SecretRef references an OIDC or OAuth token of a trusted issuer
+	// in a secret in the namespace of the generator.
+	// +optional
+	SecretRef *smmeta.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// QuayAccessToken exchanges OIDC tokens for short-lived robot account tokens
+// of Quay, which are used to pull images. The tokens expire after one hour.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={quayaccesstoken}
+type QuayAccessToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec QuayAccessTokenSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// QuayAccessTokenList contains a list of QuayAccessToken resources.
+type QuayAccessTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuayAccessToken `json:"items"`
+}
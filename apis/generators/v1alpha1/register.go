@@ -0,0 +1,202 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+// Package type metadata.
+const (
+	Group   = "generators.external-secrets.io"
+	Version = "v1alpha1"
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects.
+	SchemeGroupVersion = schema.GroupVersion{Group: Group, Version: Version}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme.
+	SchemeBuilder = &scheme.Builder{GroupVersion: SchemeGroupVersion}
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+// ACRAccessToken type metadata.
+var (
+	ACRAccessTokenKind             = reflect.TypeOf(ACRAccessToken{}).Name()
+	ACRAccessTokenGroupKind        = schema.GroupKind{Group: Group, Kind: ACRAccessTokenKind}.String()
+	ACRAccessTokenKindAPIVersion   = ACRAccessTokenKind + "." + SchemeGroupVersion.String()
+	ACRAccessTokenGroupVersionKind = SchemeGroupVersion.WithKind(ACRAccessTokenKind)
+)
+
+// Password type metadata.
+var (
+	PasswordKind             = reflect.TypeOf(Password{}).Name()
+	PasswordGroupKind        = schema.GroupKind{Group: Group, Kind: PasswordKind}.String()
+	PasswordKindAPIVersion   = PasswordKind + "." + SchemeGroupVersion.String()
+	PasswordGroupVersionKind = SchemeGroupVersion.WithKind(PasswordKind)
+)
+
+// ECRAuthorizationToken type metadata.
+var (
+	ECRAuthorizationTokenKind             = reflect.TypeOf(ECRAuthorizationToken{}).Name()
+	ECRAuthorizationTokenGroupKind        = schema.GroupKind{Group: Group, Kind: ECRAuthorizationTokenKind}.String()
+	ECRAuthorizationTokenKindAPIVersion   = ECRAuthorizationTokenKind + "." + SchemeGroupVersion.String()
+	ECRAuthorizationTokenGroupVersionKind = SchemeGroupVersion.WithKind(ECRAuthorizationTokenKind)
+)
+
+// CloudsmithAccessToken type metadata.
+var (
+	CloudsmithAccessTokenKind             = reflect.TypeOf(CloudsmithAccessToken{}).Name()
+	CloudsmithAccessTokenGroupKind        = schema.GroupKind{Group: Group, Kind: CloudsmithAccessTokenKind}.String()
+	CloudsmithAccessTokenKindAPIVersion   = CloudsmithAccessTokenKind + "." + SchemeGroupVersion.String()
+	CloudsmithAccessTokenGroupVersionKind = SchemeGroupVersion.WithKind(CloudsmithAccessTokenKind)
+)
+
+// ClusterGenerator type metadata.
+var (
+	ClusterGeneratorKind             = reflect.TypeOf(ClusterGenerator{}).Name()
+	ClusterGeneratorGroupKind        = schema.GroupKind{Group: Group, Kind: ClusterGeneratorKind}.String()
+	ClusterGeneratorKindAPIVersion   = ClusterGeneratorKind + "." + SchemeGroupVersion.String()
+	ClusterGeneratorGroupVersionKind = SchemeGroupVersion.WithKind(ClusterGeneratorKind)
+)
+
+// Fake type metadata.
+var (
+	FakeKind             = reflect.TypeOf(Fake{}).Name()
+	FakeGroupKind        = schema.GroupKind{Group: Group, Kind: FakeKind}.String()
+	FakeKindAPIVersion   = FakeKind + "." + SchemeGroupVersion.String()
+	FakeGroupVersionKind = SchemeGroupVersion.WithKind(FakeKind)
+)
+
+// GCRAccessToken type metadata.
+var (
+	GCRAccessTokenKind             = reflect.TypeOf(GCRAccessToken{}).Name()
+	GCRAccessTokenGroupKind        = schema.GroupKind{Group: Group, Kind: GCRAccessTokenKind}.String()
+	GCRAccessTokenKindAPIVersion   = GCRAccessTokenKind + "." + SchemeGroupVersion.String()
+	GCRAccessTokenGroupVersionKind = SchemeGroupVersion.WithKind(GCRAccessTokenKind)
+)
+
+// GeneratorState type metadata.
+var (
+	GeneratorStateKind             = reflect.TypeOf(GeneratorState{}).Name()
+	GeneratorStateGroupKind        = schema.GroupKind{Group: Group, Kind: GeneratorStateKind}.String()
+	GeneratorStateKindAPIVersion   = GeneratorStateKind + "." + SchemeGroupVersion.String()
+	GeneratorStateGroupVersionKind = SchemeGroupVersion.WithKind(GeneratorStateKind)
+)
+
+// GithubAccessToken type metadata.
+var (
+	GithubAccessTokenKind             = reflect.TypeOf(GithubAccessToken{}).Name()
+	GithubAccessTokenGroupKind        = schema.GroupKind{Group: Group, Kind: GithubAccessTokenKind}.String()
+	GithubAccessTokenKindAPIVersion   = GithubAccessTokenKind + "." + SchemeGroupVersion.String()
+	GithubAccessTokenGroupVersionKind = SchemeGroupVersion.WithKind(GithubAccessTokenKind)
+)
+
+// Grafana type metadata.
+var (
+	GrafanaKind             = reflect.TypeOf(Grafana{}).Name()
+	GrafanaGroupKind        = schema.GroupKind{Group: Group, Kind: GrafanaKind}.String()
+	GrafanaKindAPIVersion   = GrafanaKind + "." + SchemeGroupVersion.String()
+	GrafanaGroupVersionKind = SchemeGroupVersion.WithKind(GrafanaKind)
+)
+
+// QuayAccessToken type metadata.
+var (
+	QuayAccessTokenKind             = reflect.TypeOf(QuayAccessToken{}).Name()
+	QuayAccessTokenGroupKind        = schema.GroupKind{Group: Group, Kind: QuayAccessTokenKind}.String()
+	QuayAccessTokenKindAPIVersion   = QuayAccessTokenKind + "." + SchemeGroupVersion.String()
+	QuayAccessTokenGroupVersionKind = SchemeGroupVersion.WithKind(QuayAccessTokenKind)
+)
+
+// SSHKey type metadata.
+var (
+	SSHKeyKind             = reflect.TypeOf(SSHKey{}).Name()
+	SSHKeyGroupKind        = schema.GroupKind{Group: Group, Kind: SSHKeyKind}.String()
+	SSHKeyKindAPIVersion   = SSHKeyKind + "." + SchemeGroupVersion.String()
+	SSHKeyGroupVersionKind = SchemeGroupVersion.WithKind(SSHKeyKind)
+)
+
+// STSSessionToken type metadata.
+var (
+	STSSessionTokenKind             = reflect.TypeOf(STSSessionToken{}).Name()
+	STSSessionTokenGroupKind        = schema.GroupKind{Group: Group, Kind: STSSessionTokenKind}.String()
+	STSSessionTokenKindAPIVersion   = STSSessionTokenKind + "." + SchemeGroupVersion.String()
+	STSSessionTokenGroupVersionKind = SchemeGroupVersion.WithKind(STSSessionTokenKind)
+)
+
+// TLSCertificate type metadata.
+var (
+	TLSCertificateKind             = reflect.TypeOf(TLSCertificate{}).Name()
+	TLSCertificateGroupKind        = schema.GroupKind{Group: Group, Kind: TLSCertificateKind}.String()
+	TLSCertificateKindAPIVersion   = TLSCertificateKind + "." + SchemeGroupVersion.String()
+	TLSCertificateGroupVersionKind = SchemeGroupVersion.WithKind(TLSCertificateKind)
+)
+
+// TOTP type metadata.
+var (
+	TOTPKind             = reflect.TypeOf(TOTP{}).Name()
+	TOTPGroupKind        = schema.GroupKind{Group: Group, Kind: TOTPKind}.String()
+	TOTPKindAPIVersion   = TOTPKind + "." + SchemeGroupVersion.String()
+	TOTPGroupVersionKind = SchemeGroupVersion.WithKind(TOTPKind)
+)
+
+// UUID type metadata.
+var (
+	UUIDKind             = reflect.TypeOf(UUID{}).Name()
+	UUIDGroupKind        = schema.GroupKind{Group: Group, Kind: UUIDKind}.String()
+	UUIDKindAPIVersion   = UUIDKind + "." + SchemeGroupVersion.String()
+	UUIDGroupVersionKind = SchemeGroupVersion.WithKind(UUIDKind)
+)
+
+// VaultDynamicSecret type metadata.
+var (
+	VaultDynamicSecretKind             = reflect.TypeOf(VaultDynamicSecret{}).Name()
+	VaultDynamicSecretGroupKind        = schema.GroupKind{Group: Group, Kind: VaultDynamicSecretKind}.String()
+	VaultDynamicSecretKindAPIVersion   = VaultDynamicSecretKind + "." + SchemeGroupVersion.String()
+	VaultDynamicSecretGroupVersionKind = SchemeGroupVersion.WithKind(VaultDynamicSecretKind)
+)
+
+// Webhook type metadata.
+var (
+	WebhookKind             = reflect.TypeOf(Webhook{}).Name()
+	WebhookGroupKind        = schema.GroupKind{Group: Group, Kind: WebhookKind}.String()
+	WebhookKindAPIVersion   = WebhookKind + "." + SchemeGroupVersion.String()
+	WebhookGroupVersionKind = SchemeGroupVersion.WithKind(WebhookKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Password{}, &PasswordList{})
+	SchemeBuilder.Register(&ECRAuthorizationToken{}, &ECRAuthorizationTokenList{})
+	SchemeBuilder.Register(&GCRAccessToken{}, &GCRAccessTokenList{})
+	SchemeBuilder.Register(&ACRAccessToken{}, &ACRAccessTokenList{})
+	SchemeBuilder.Register(&CloudsmithAccessToken{}, &CloudsmithAccessTokenList{})
+	SchemeBuilder.Register(&ClusterGenerator{}, &ClusterGeneratorList{})
+	SchemeBuilder.Register(&Fake{}, &FakeList{})
+	SchemeBuilder.Register(&GeneratorState{}, &GeneratorStateList{})
+	SchemeBuilder.Register(&GithubAccessToken{}, &GithubAccessTokenList{})
+	SchemeBuilder.Register(&Grafana{}, &GrafanaList{})
+	SchemeBuilder.Register(&QuayAccessToken{}, &QuayAccessTokenList{})
+	SchemeBuilder.Register(&SSHKey{}, &SSHKeyList{})
+	SchemeBuilder.Register(&STSSessionToken{}, &STSSessionTokenList{})
+	SchemeBuilder.Register(&TLSCertificate{}, &TLSCertificateList{})
+	SchemeBuilder.Register(&TOTP{}, &TOTPList{})
+	SchemeBuilder.Register(&UUID{}, &UUIDList{})
+	SchemeBuilder.Register(&VaultDynamicSecret{}, &VaultDynamicSecretList{})
+	SchemeBuilder.Register(&Webhook{}, &WebhookList{})
+}
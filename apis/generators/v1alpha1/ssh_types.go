@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SSHKeyType is the algorithm of the generated key pair.
+// +kubebuilder:validation:Enum=ed25519;rsa
+type SSHKeyType string
+
+const (
+	SSHKeyTypeED25519 SSHKeyType = "ed25519"
+	SSHKeyTypeRSA     SSHKeyType = "rsa"
+)
+
+// SSHKeySpec controls the behavior of the ssh key generator.
+type SSHKeySpec struct {
+	// KeyType is the algorithm of the key pair.
+	// Defaults to ed25519
+	// +kubebuilder:default=ed25519
+	// +optional
+	KeyType SSHKeyType `json:"keyType,omitempty"`
+
+	// KeySize is the size of RSA keys in bits, it is ignored for ed25519 keys.
+	// Defaults to 3072
+	// +kubebuilder:validation:Minimum=2048
+	// +kubebuilder:validation:Maximum=8192
+	// +optional
+	KeySize *int `json:"keySize,omitempty"`
+
+	// Comment is added to the public key.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+
+	// Hosts are the host names or addresses of the known_hosts entry of the public key,
+	// e.g. if the key pair is the host key of an ssh server.
+	// Without hosts the known_hosts key isn't generated.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+
+	// RotationPolicy defines if a new key pair is generated on every refresh
+	// of the ExternalSecret (Rotate) or only if the key pair is missing in the target secret (OnlyWhenMissing).
+	// Defaults to Rotate
+	// +kubebuilder:default=Rotate
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+}
+
+// SSHKey generates an ssh key pair based on the
+// configuration parameters in spec.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={sshkey}
+type SSHKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SSHKeySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SSHKeyList contains a list of SSHKey resources.
+type SSHKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SSHKey `json:"items"`
+}
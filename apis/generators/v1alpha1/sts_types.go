@@ -0,0 +1,86 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// STSSessionTokenSpec configures the AWS account, region and request of the temporary credentials.
+type STSSessionTokenSpec struct {
+	// Region specifies the region to operate in.
+	Region string `json:"region"`
+
+	// Auth defines how to authenticate with AWS.
+	// If not set the aws sdk infers the credentials from the environment of the controller.
+	// The referenced secrets and service accounts must exist in the namespace of the generator.
+	// +optional
+	Auth esv1beta1.AWSAuth `json:"auth,omitempty"`
+
+	// Role is a Role ARN which is assumed with AssumeRole to get the temporary credentials.
+	// If not set the temporary credentials are requested with GetSessionToken.
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// RequestParameters contains parameters of the request of the temporary credentials.
+	// +optional
+	RequestParameters *STSSessionTokenRequestParameters `json:"requestParameters,omitempty"`
+
+	// RotationPolicy defines if new temporary credentials are requested on every refresh of the ExternalSecret (Rotate)
+	// or only if they are missing in the target secret or expire before the next refresh (OnlyWhenMissing).
+	// Defaults to Rotate
+	// +kubebuilder:default=Rotate
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+}
+
+// STSSessionTokenRequestParameters contains parameters of the AssumeRole or GetSessionToken request.
+type STSSessionTokenRequestParameters struct {
+	// SessionDuration is the duration of the credentials in seconds.
+	// AWS defaults to one hour for AssumeRole and 12 hours for GetSessionToken.
+	// +optional
+	SessionDuration *int64 `json:"sessionDuration,omitempty"`
+
+	// SerialNumber is the identification number of the MFA device of the user.
+	// +optional
+	SerialNumber *string `json:"serialNumber,omitempty"`
+
+	// TokenCode is the value provided by the MFA device, required if the
+	// policy of the user or role requires MFA.
+	// +optional
+	TokenCode *string `json:"tokenCode,omitempty"`
+}
+
+// STSSessionToken requests temporary AWS credentials with the AssumeRole or
+// GetSessionToken API of STS, for workloads which can't use IRSA.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={stssessiontoken}
+type STSSessionToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec STSSessionTokenSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// STSSessionTokenList contains a list of STSSessionToken resources.
+type STSSessionTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []STSSessionToken `json:"items"`
+}
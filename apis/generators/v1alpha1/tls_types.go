@@ -0,0 +1,105 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TLSKeyType is the algorithm of the private key of a certificate.
+// +kubebuilder:validation:Enum=ecdsa;rsa;ed25519
+type TLSKeyType string
+
+const (
+	TLSKeyTypeECDSA   TLSKeyType = "ecdsa"
+	TLSKeyTypeRSA     TLSKeyType = "rsa"
+	TLSKeyTypeED25519 TLSKeyType = "ed25519"
+)
+
+// TLSCertificateSpec controls the certificate that is issued.
+type TLSCertificateSpec struct {
+	// CommonName of the subject of the certificate.
+	// +optional
+	CommonName string `json:"commonName,omitempty"`
+
+	// DNSNames are the DNS subject alternative names of the certificate.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// IPAddresses are the IP subject alternative names of the certificate.
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// Duration is the validity of the certificate.
+	// Defaults to 24h
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// KeyType is the algorithm of the private key.
+	// Defaults to ecdsa
+	// +kubebuilder:default=ecdsa
+	// +optional
+	KeyType TLSKeyType `json:"keyType,omitempty"`
+
+	// KeySize is the size of RSA keys in bits, defaults to 2048, or the curve size
+	// of ECDSA keys: 256, 384 or 521, defaults to 256. It is ignored for ed25519 keys.
+	// +optional
+	KeySize *int `json:"keySize,omitempty"`
+
+	// IsCA issues a certificate authority, which can sign other certificates
+	// of this generator through caSecretRef.
+	// +optional
+	IsCA bool `json:"isCA,omitempty"`
+
+	// CASecretRef references a secret in the namespace of the generator that holds the
+	// certificate and key of the signing CA in tls.crt and tls.key, e.g. the target secret
+	// of an ExternalSecret with a CA generated by this generator.
+	// Without caSecretRef the certificate is self-signed.
+	// +optional
+	CASecretRef *TLSCertificateCASecretRef `json:"caSecretRef,omitempty"`
+
+	// RotationPolicy defines if a new certificate is issued on every refresh
+	// of the ExternalSecret (Rotate) or only if it is missing in the target secret or expires before the next refresh (OnlyWhenMissing).
+	// Defaults to Rotate
+	// +kubebuilder:default=Rotate
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+}
+
+// TLSCertificateCASecretRef references the secret of a CA.
+type TLSCertificateCASecretRef struct {
+	// The name of the Secret resource being referred to.
+	Name string `json:"name"`
+}
+
+// TLSCertificate issues short-lived self-signed or locally CA-signed certificates,
+// e.g. for dev clusters that don't run cert-manager.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={tlscertificate}
+type TLSCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TLSCertificateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TLSCertificateList contains a list of TLSCertificate resources.
+type TLSCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TLSCertificate `json:"items"`
+}
@@ -0,0 +1,80 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// TOTPAlgorithm is the HMAC algorithm of the one-time passcodes.
+// +kubebuilder:validation:Enum=SHA1;SHA256;SHA512
+type TOTPAlgorithm string
+
+const (
+	TOTPAlgorithmSHA1   TOTPAlgorithm = "SHA1"
+	TOTPAlgorithmSHA256 TOTPAlgorithm = "SHA256"
+	TOTPAlgorithmSHA512 TOTPAlgorithm = "SHA512"
+)
+
+// TOTPSpec configures the seed and the parameters of the time-based one-time passcodes.
+// see: https://www.rfc-editor.org/rfc/rfc6238
+type TOTPSpec struct {
+	// SecretRef references the key of a secret that holds the base32 encoded seed,
+	// e.g. a secret synced from a provider by another ExternalSecret.
+	SecretRef smmeta.SecretKeySelector `json:"secretRef"`
+
+	// Algorithm of the HMAC.
+	// Defaults to SHA1
+	// +kubebuilder:default=SHA1
+	// +optional
+	Algorithm TOTPAlgorithm `json:"algorithm,omitempty"`
+
+	// Digits of the passcode.
+	// Defaults to 6
+	// +kubebuilder:default=6
+	// +kubebuilder:validation:Minimum=6
+	// +kubebuilder:validation:Maximum=8
+	// +optional
+	Digits int `json:"digits,omitempty"`
+
+	// Period of a passcode in seconds.
+	// Defaults to 30
+	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Period int `json:"period,omitempty"`
+}
+
+// TOTP generates time-based one-time passcodes from a seed,
+// for workloads that authenticate to MFA-protected APIs.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={totp}
+type TOTP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TOTPSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TOTPList contains a list of TOTP resources.
+type TOTPList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TOTP `json:"items"`
+}
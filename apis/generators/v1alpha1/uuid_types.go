@@ -0,0 +1,65 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UUIDFormat is the format of the generated identifier.
+// +kubebuilder:validation:Enum=uuid;hex;alphanumeric
+type UUIDFormat string
+
+const (
+	UUIDFormatUUID         UUIDFormat = "uuid"
+	UUIDFormatHex          UUIDFormat = "hex"
+	UUIDFormatAlphanumeric UUIDFormat = "alphanumeric"
+)
+
+// UUIDSpec controls the behavior of the uuid generator.
+type UUIDSpec struct {
+	// Format of the identifier: a random (version 4) uuid,
+	// a random hex string or a random alphanumeric string.
+	// Defaults to uuid
+	// +kubebuilder:default=uuid
+	// +optional
+	Format UUIDFormat `json:"format,omitempty"`
+
+	// Length of hex and alphanumeric strings, it is ignored for uuids.
+	// Defaults to 32
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Length *int `json:"length,omitempty"`
+}
+
+// UUID generates random identifiers like uuids, hex or alphanumeric strings,
+// e.g. for webhook tokens or instance identifiers.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={uuid}
+type UUID struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec UUIDSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UUIDList contains a list of UUID resources.
+type UUIDList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UUID `json:"items"`
+}
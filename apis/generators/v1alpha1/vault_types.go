@@ -0,0 +1,68 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// VaultDynamicSecretSpec configures the Vault endpoint of the dynamic secret.
+type VaultDynamicSecretSpec struct {
+	// Vault API method to use (GET/POST/other)
+	// +kubebuilder:default="GET"
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Parameters to pass to Vault write (for non-GET methods)
+	// +optional
+	Parameters *apiextensions.JSON `json:"parameters,omitempty"`
+
+	// Vault provider common spec.
+	// The referenced secrets and service accounts must exist in the namespace of the generator.
+	Provider *esv1beta1.VaultProvider `json:"provider"`
+
+	// Vault path to obtain the dynamic secret from, e.g. database/creds/my-role
+	Path string `json:"path"`
+
+	// RotationPolicy defines if a new dynamic secret is requested on every refresh of the ExternalSecret (Rotate)
+	// or only if it is missing in the target secret or its lease expires before the next refresh (OnlyWhenMissing).
+	// Defaults to Rotate
+	// +kubebuilder:default=Rotate
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+}
+
+// VaultDynamicSecret reads or writes a dynamic secrets endpoint of Vault,
+// e.g. database credentials, credentials of the AWS engine or certificates of the PKI engine.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,categories={vaultdynamicsecret}
+type VaultDynamicSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VaultDynamicSecretSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VaultDynamicSecretList contains a list of VaultDynamicSecret resources.
+type VaultDynamicSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VaultDynamicSecret `json:"items"`
+}
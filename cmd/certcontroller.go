@@ -46,13 +46,11 @@ var certcontrollerCmd = &cobra.Command{
 		logger := zap.New(zap.Level(lvl))
 		ctrl.SetLogger(logger)
 
-		mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), leaderElectionOptions(ctrl.Options{
 			Scheme:                 scheme,
 			MetricsBindAddress:     metricsAddr,
 			HealthProbeBindAddress: healthzAddr,
 			Port:                   9443,
-			LeaderElection:         enableLeaderElection,
-			LeaderElectionID:       "crd-certs-controller",
 			ClientDisableCacheFor: []client.Object{
 				// the client creates a ListWatch for all resource kinds that
 				// are requested with .Get().
@@ -62,7 +60,7 @@ var certcontrollerCmd = &cobra.Command{
 				// see #721
 				&v1.Secret{},
 			},
-		})
+		}, "crd-certs-controller"))
 		if err != nil {
 			setupLog.Error(err, "unable to start manager")
 			os.Exit(1)
@@ -120,9 +118,7 @@ func init() {
 	certcontrollerCmd.Flags().StringVar(&serviceNamespace, "service-namespace", "default", "Webhook service namespace")
 	certcontrollerCmd.Flags().StringVar(&secretName, "secret-name", "external-secrets-webhook", "Secret to store certs for webhook")
 	certcontrollerCmd.Flags().StringVar(&secretNamespace, "secret-namespace", "default", "namespace of the secret to store certs")
-	certcontrollerCmd.Flags().BoolVar(&enableLeaderElection, "enable-leader-election", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
+	addLeaderElectionFlags(certcontrollerCmd)
 	certcontrollerCmd.Flags().StringVar(&loglevel, "loglevel", "info", "loglevel to use, one of: debug, info, warn, error, dpanic, panic, fatal")
 	certcontrollerCmd.Flags().DurationVar(&crdRequeueInterval, "crd-requeue-interval", time.Minute*5, "Time duration between reconciling CRDs for new certs")
 }
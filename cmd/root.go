@@ -35,8 +35,11 @@ import (
 
 	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
 	"github.com/external-secrets/external-secrets/pkg/controllers/clusterexternalsecret"
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret"
+	"github.com/external-secrets/external-secrets/pkg/controllers/generatorstate"
+	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
 )
 
@@ -49,12 +52,30 @@ var (
 	healthzAddr                           string
 	controllerClass                       string
 	enableLeaderElection                  bool
+	leaderElectionNamespace               string
+	leaderElectionIDOverride              string
+	leaderElectionLeaseDuration           time.Duration
+	leaderElectionRenewDeadline           time.Duration
+	leaderElectionRetryPeriod             time.Duration
 	concurrent                            int
+	storeConcurrent                       int
+	externalSecretConcurrent              int
+	clusterExternalSecretConcurrent       int
+	pushSecretConcurrent                  int
+	generatorStateConcurrent              int
 	loglevel                              string
 	namespace                             string
 	enableClusterStoreReconciler          bool
 	enableClusterExternalSecretReconciler bool
+	enableTargetSecretWatch               bool
 	storeRequeueInterval                  time.Duration
+	generatorStateGracePeriod             time.Duration
+	providerQPS                           float32
+	refreshJitter                         float64
+	providerClientCacheTTL                time.Duration
+	errorBackoffBase                      time.Duration
+	errorBackoffMax                       time.Duration
+	providerBurst                         int
 	serviceName, serviceNamespace         string
 	secretName, secretNamespace           string
 	crdRequeueInterval                    time.Duration
@@ -63,12 +84,65 @@ var (
 
 const (
 	errCreateController = "unable to create controller"
+
+	defaultControllerClass = "default"
+	leaderElectionID       = "external-secrets-controller"
 )
 
+// leaderElectionIDForClass returns the leader election id of the controller class,
+// so that controllers of different classes can run side by side with leader election.
+func leaderElectionIDForClass(class string) string {
+	if class == defaultControllerClass {
+		return leaderElectionID
+	}
+	return leaderElectionID + "-" + class
+}
+
+// concurrentReconciles returns the number of concurrent reconciles of a controller,
+// the --concurrent flag applies to the controllers without a flag of their own.
+func concurrentReconciles(controllerConcurrent int) int {
+	if controllerConcurrent > 0 {
+		return controllerConcurrent
+	}
+	return concurrent
+}
+
+// leaderElectionOptions sets the leader election options of the flags,
+// id is used as leader election id unless --leader-election-id is set.
+// The manager releases the lease when it stops, so that a standby replica takes over
+// without waiting for the lease to expire. This is safe as the command exits right
+// after the manager stopped.
+func leaderElectionOptions(opts ctrl.Options, id string) ctrl.Options {
+	opts.LeaderElection = enableLeaderElection
+	opts.LeaderElectionID = id
+	if leaderElectionIDOverride != "" {
+		opts.LeaderElectionID = leaderElectionIDOverride
+	}
+	opts.LeaderElectionNamespace = leaderElectionNamespace
+	opts.LeaderElectionReleaseOnCancel = true
+	opts.LeaseDuration = &leaderElectionLeaseDuration
+	opts.RenewDeadline = &leaderElectionRenewDeadline
+	opts.RetryPeriod = &leaderElectionRetryPeriod
+	return opts
+}
+
+// addLeaderElectionFlags adds the leader election flags to the command.
+func addLeaderElectionFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&enableLeaderElection, "enable-leader-election", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	cmd.Flags().StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "The namespace of the leader election lease, defaults to the namespace the controller runs in.")
+	cmd.Flags().StringVar(&leaderElectionIDOverride, "leader-election-id", "", "The name of the leader election lease, defaults to a name derived from the controller.")
+	cmd.Flags().DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "Time duration standby replicas wait before they take over a lease that was not renewed.")
+	cmd.Flags().DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 10*time.Second, "Time duration the leader retries to renew its lease before it gives up leadership.")
+	cmd.Flags().DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 2*time.Second, "Time duration between the attempts to acquire or renew the lease.")
+}
+
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = esv1beta1.AddToScheme(scheme)
 	_ = esv1alpha1.AddToScheme(scheme)
+	_ = genv1alpha1.AddToScheme(scheme)
 	_ = apiextensionsv1.AddToScheme(scheme)
 }
 
@@ -86,12 +160,10 @@ var rootCmd = &cobra.Command{
 		logger := zap.New(zap.Level(lvl))
 		ctrl.SetLogger(logger)
 
-		mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), leaderElectionOptions(ctrl.Options{
 			Scheme:             scheme,
 			MetricsBindAddress: metricsAddr,
 			Port:               9443,
-			LeaderElection:     enableLeaderElection,
-			LeaderElectionID:   "external-secrets-controller",
 			ClientDisableCacheFor: []client.Object{
 				// the client creates a ListWatch for all resource kinds that
 				// are requested with .Get().
@@ -103,18 +175,22 @@ var rootCmd = &cobra.Command{
 				&v1.ConfigMap{},
 			},
 			Namespace: namespace,
-		})
+		}, leaderElectionIDForClass(controllerClass)))
 		if err != nil {
 			setupLog.Error(err, "unable to start manager")
 			os.Exit(1)
 		}
+		secretstore.SetProviderRateLimit(providerQPS, providerBurst)
+		clientCache := secretstore.NewClientCache(ctrl.Log.WithName("provider-client-cache"), providerClientCacheTTL)
 		if err = (&secretstore.StoreReconciler{
 			Client:          mgr.GetClient(),
 			Log:             ctrl.Log.WithName("controllers").WithName("SecretStore"),
 			Scheme:          mgr.GetScheme(),
 			ControllerClass: controllerClass,
 			RequeueInterval: storeRequeueInterval,
-		}).SetupWithManager(mgr); err != nil {
+		}).SetupWithManager(mgr, controller.Options{
+			MaxConcurrentReconciles: concurrentReconciles(storeConcurrent),
+		}); err != nil {
 			setupLog.Error(err, errCreateController, "controller", "SecretStore")
 			os.Exit(1)
 		}
@@ -125,7 +201,9 @@ var rootCmd = &cobra.Command{
 				Scheme:          mgr.GetScheme(),
 				ControllerClass: controllerClass,
 				RequeueInterval: storeRequeueInterval,
-			}).SetupWithManager(mgr); err != nil {
+			}).SetupWithManager(mgr, controller.Options{
+				MaxConcurrentReconciles: concurrentReconciles(storeConcurrent),
+			}); err != nil {
 				setupLog.Error(err, errCreateController, "controller", "ClusterSecretStore")
 				os.Exit(1)
 			}
@@ -137,12 +215,45 @@ var rootCmd = &cobra.Command{
 			ControllerClass:           controllerClass,
 			RequeueInterval:           time.Hour,
 			ClusterSecretStoreEnabled: enableClusterStoreReconciler,
+			TargetSecretWatchEnabled:  enableTargetSecretWatch,
+			ErrorBackoffBase:          errorBackoffBase,
+			ErrorBackoffMax:           errorBackoffMax,
+			GeneratorStateGracePeriod: generatorStateGracePeriod,
+			RefreshJitter:             refreshJitter,
+			ClientCache:               clientCache,
 		}).SetupWithManager(mgr, controller.Options{
-			MaxConcurrentReconciles: concurrent,
+			MaxConcurrentReconciles: concurrentReconciles(externalSecretConcurrent),
 		}); err != nil {
 			setupLog.Error(err, errCreateController, "controller", "ExternalSecret")
 			os.Exit(1)
 		}
+		if err = (&generatorstate.Reconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("GeneratorState"),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr, controller.Options{
+			MaxConcurrentReconciles: concurrentReconciles(generatorStateConcurrent),
+		}); err != nil {
+			setupLog.Error(err, errCreateController, "controller", "GeneratorState")
+			os.Exit(1)
+		}
+		if err = (&pushsecret.Reconciler{
+			Client:                   mgr.GetClient(),
+			Log:                      ctrl.Log.WithName("controllers").WithName("PushSecret"),
+			Scheme:                   mgr.GetScheme(),
+			ControllerClass:          controllerClass,
+			RequeueInterval:          time.Hour,
+			ErrorBackoffBase:         errorBackoffBase,
+			ErrorBackoffMax:          errorBackoffMax,
+			RefreshJitter:            refreshJitter,
+			ClientCache:              clientCache,
+			SourceSecretWatchEnabled: enableTargetSecretWatch,
+		}).SetupWithManager(mgr, controller.Options{
+			MaxConcurrentReconciles: concurrentReconciles(pushSecretConcurrent),
+		}); err != nil {
+			setupLog.Error(err, errCreateController, "controller", "PushSecret")
+			os.Exit(1)
+		}
 		if enableClusterExternalSecretReconciler {
 			if err = (&clusterexternalsecret.Reconciler{
 				Client:          mgr.GetClient(),
@@ -150,7 +261,7 @@ var rootCmd = &cobra.Command{
 				Scheme:          mgr.GetScheme(),
 				RequeueInterval: time.Hour,
 			}).SetupWithManager(mgr, controller.Options{
-				MaxConcurrentReconciles: concurrent,
+				MaxConcurrentReconciles: concurrentReconciles(clusterExternalSecretConcurrent),
 			}); err != nil {
 				setupLog.Error(err, errCreateController, "controller", "ClusterExternalSecret")
 				os.Exit(1)
@@ -171,14 +282,25 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
-	rootCmd.Flags().StringVar(&controllerClass, "controller-class", "default", "the controller is instantiated with a specific controller name and filters ES based on this property")
-	rootCmd.Flags().BoolVar(&enableLeaderElection, "enable-leader-election", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-	rootCmd.Flags().IntVar(&concurrent, "concurrent", 1, "The number of concurrent ExternalSecret reconciles.")
+	rootCmd.Flags().StringVar(&controllerClass, "controller-class", defaultControllerClass, "the controller is instantiated with a specific controller name and filters ES based on this property")
+	addLeaderElectionFlags(rootCmd)
+	rootCmd.Flags().IntVar(&concurrent, "concurrent", 1, "The number of concurrent reconciles of the controllers without a --concurrent-* flag.")
+	rootCmd.Flags().IntVar(&storeConcurrent, "concurrent-secret-store", 0, "The number of concurrent (Cluster)SecretStore reconciles, defaults to --concurrent.")
+	rootCmd.Flags().IntVar(&externalSecretConcurrent, "concurrent-external-secret", 0, "The number of concurrent ExternalSecret reconciles, defaults to --concurrent.")
+	rootCmd.Flags().IntVar(&clusterExternalSecretConcurrent, "concurrent-cluster-external-secret", 0, "The number of concurrent ClusterExternalSecret reconciles, defaults to --concurrent.")
+	rootCmd.Flags().IntVar(&pushSecretConcurrent, "concurrent-push-secret", 0, "The number of concurrent PushSecret reconciles, defaults to --concurrent.")
+	rootCmd.Flags().IntVar(&generatorStateConcurrent, "concurrent-generator-state", 0, "The number of concurrent GeneratorState reconciles, defaults to --concurrent.")
 	rootCmd.Flags().StringVar(&loglevel, "loglevel", "info", "loglevel to use, one of: debug, info, warn, error, dpanic, panic, fatal")
 	rootCmd.Flags().StringVar(&namespace, "namespace", "", "watch external secrets scoped in the provided namespace only. ClusterSecretStore can be used but only work if it doesn't reference resources from other namespaces")
 	rootCmd.Flags().BoolVar(&enableClusterStoreReconciler, "enable-cluster-store-reconciler", true, "Enable cluster store reconciler.")
 	rootCmd.Flags().BoolVar(&enableClusterExternalSecretReconciler, "enable-cluster-external-secret-reconciler", true, "Enable cluster external secret reconciler.")
+	rootCmd.Flags().BoolVar(&enableTargetSecretWatch, "enable-target-secret-watch", true, "Reconcile ExternalSecrets right away when their target secret is changed or deleted and PushSecrets when their source secret is changed. If disabled, the changes are synced on the next refresh.")
 	rootCmd.Flags().DurationVar(&storeRequeueInterval, "store-requeue-interval", time.Minute*5, "Time duration between reconciling (Cluster)SecretStores")
+	rootCmd.Flags().Float32Var(&providerQPS, "provider-qps", 0, "The number of requests per second to every provider, summed up over all stores of the provider. 0 disables the limit.")
+	rootCmd.Flags().IntVar(&providerBurst, "provider-burst", 0, "The number of requests to every provider that may be sent at once above --provider-qps, defaults to --provider-qps.")
+	rootCmd.Flags().Float64Var(&refreshJitter, "refresh-jitter", 0, "Extends the refresh interval of ExternalSecrets and PushSecrets by a random fraction of up to the given factor, e.g. 0.1 for up to 10%. 0 disables the jitter.")
+	rootCmd.Flags().DurationVar(&providerClientCacheTTL, "provider-client-cache-ttl", 0, "Time duration provider clients are shared between reconciles before they are created again. 0 creates a new client on every reconcile.")
+	rootCmd.Flags().DurationVar(&errorBackoffBase, "error-backoff-base", 5*time.Second, "Time duration a failed ExternalSecret or PushSecret sync is retried after, it doubles with every further failed sync.")
+	rootCmd.Flags().DurationVar(&errorBackoffMax, "error-backoff-max", 10*time.Minute, "Maximum time duration a failed ExternalSecret or PushSecret sync is retried after.")
+	rootCmd.Flags().DurationVar(&generatorStateGracePeriod, "generator-state-gc-grace-period", time.Minute*5, "Time duration superseded generator states are kept before their resources are cleaned up")
 }
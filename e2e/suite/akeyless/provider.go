@@ -45,26 +45,34 @@ type akeylessProvider struct {
 	accessID        string
 	accessType      string
 	accessTypeParam string
+	gatewayURL      string
 	framework       *framework.Framework
 	restAPIClient   *akeyless.V2ApiService
 }
 
 var apiErr akeyless.GenericOpenAPIError
 
-const DefServiceAccountFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+const (
+	DefServiceAccountFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	defaultGatewayURL     = "https://api.akeyless.io"
+)
 
-func newAkeylessProvider(f *framework.Framework, accessID, accessType, accessTypeParam string) *akeylessProvider {
+func newAkeylessProvider(f *framework.Framework, accessID, accessType, accessTypeParam, gatewayURL string) *akeylessProvider {
+	if gatewayURL == "" {
+		gatewayURL = defaultGatewayURL
+	}
 	prov := &akeylessProvider{
 		accessID:        accessID,
 		accessType:      accessType,
 		accessTypeParam: accessTypeParam,
+		gatewayURL:      gatewayURL,
 		framework:       f,
 	}
 
 	restAPIClient := akeyless.NewAPIClient(&akeyless.Configuration{
 		Servers: []akeyless.ServerConfiguration{
 			{
-				URL: "https://api.akeyless.io",
+				URL: gatewayURL,
 			},
 		},
 	}).V2Api
@@ -79,7 +87,8 @@ func newFromEnv(f *framework.Framework) *akeylessProvider {
 	accessID := os.Getenv("AKEYLESS_ACCESS_ID")
 	accessType := os.Getenv("AKEYLESS_ACCESS_TYPE")
 	accessTypeParam := os.Getenv("AKEYLESS_ACCESS_TYPE_PARAM")
-	return newAkeylessProvider(f, accessID, accessType, accessTypeParam)
+	gatewayURL := os.Getenv("AKEYLESS_GATEWAY_URL")
+	return newAkeylessProvider(f, accessID, accessType, accessTypeParam, gatewayURL)
 }
 
 // CreateSecret creates a secret.
@@ -137,6 +146,7 @@ func (a *akeylessProvider) BeforeEach() {
 		Spec: esv1beta1.SecretStoreSpec{
 			Provider: &esv1beta1.SecretStoreProvider{
 				Akeyless: &esv1beta1.AkeylessProvider{
+					AkeylessGWApiURL: &a.gatewayURL,
 					Auth: &esv1beta1.AkeylessAuth{
 						SecretRef: esv1beta1.AkeylessAuthSecretRef{
 							AccessID: esmeta.SecretKeySelector{
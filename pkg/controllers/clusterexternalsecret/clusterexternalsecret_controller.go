@@ -22,7 +22,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,6 +29,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -45,7 +48,7 @@ type Reconciler struct {
 const (
 	errGetCES              = "could not get ClusterExternalSecret"
 	errPatchStatus         = "unable to patch status"
-	errLabelMap            = "unable to get map from labels"
+	errNamespaceSelector   = "unable to get selector from namespaceSelector"
 	errNamespaces          = "could not get namespaces from selector"
 	errGetExistingES       = "could not get existing ExternalSecret"
 	errCreatingOrUpdating  = "could not create or update ExternalSecret"
@@ -76,15 +79,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		refreshInt = clusterExternalSecret.Spec.RefreshInterval.Duration
 	}
 
-	labelMap, err := metav1.LabelSelectorAsMap(&clusterExternalSecret.Spec.NamespaceSelector)
+	selector, err := metav1.LabelSelectorAsSelector(&clusterExternalSecret.Spec.NamespaceSelector)
 	if err != nil {
-		log.Error(err, errLabelMap)
+		log.Error(err, errNamespaceSelector)
 		return ctrl.Result{RequeueAfter: refreshInt}, err
 	}
 
 	namespaceList := v1.NamespaceList{}
 
-	err = r.List(ctx, &namespaceList, &client.ListOptions{LabelSelector: labels.SelectorFromSet(labelMap)})
+	err = r.List(ctx, &namespaceList, &client.ListOptions{LabelSelector: selector})
 	if err != nil {
 		log.Error(err, errNamespaces)
 		return ctrl.Result{RequeueAfter: refreshInt}, err
@@ -95,7 +98,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		esName = clusterExternalSecret.ObjectMeta.Name
 	}
 
-	failedNamespaces := r.removeOldNamespaces(ctx, namespaceList, esName, clusterExternalSecret.Status.ProvisionedNamespaces)
+	failedNamespaces := r.removeOldNamespaces(ctx, &clusterExternalSecret, namespaceList, esName)
 	provisionedNamespaces := []string{}
 
 	for _, namespace := range namespaceList.Items {
@@ -130,10 +133,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	SetClusterExternalSecretCondition(&clusterExternalSecret, *condition)
 	setFailedNamespaces(&clusterExternalSecret, failedNamespaces)
-
-	if len(provisionedNamespaces) > 0 {
-		clusterExternalSecret.Status.ProvisionedNamespaces = provisionedNamespaces
-	}
+	clusterExternalSecret.Status.ProvisionedNamespaces = provisionedNamespaces
 
 	return ctrl.Result{RequeueAfter: refreshInt}, nil
 }
@@ -169,8 +169,8 @@ func (r *Reconciler) resolveExternalSecret(ctx context.Context, clusterExternalS
 	return "", nil
 }
 
-func (r *Reconciler) removeExternalSecret(ctx context.Context, esName, namespace string) (string, error) {
-	//
+// removeExternalSecret deletes the ExternalSecret esName in namespace if it is controlled by the ClusterExternalSecret.
+func (r *Reconciler) removeExternalSecret(ctx context.Context, clusterExternalSecret *esv1beta1.ClusterExternalSecret, esName, namespace string) (string, error) {
 	var existingES esv1beta1.ExternalSecret
 	err := r.Get(ctx, types.NamespacedName{
 		Name:      esName,
@@ -186,6 +186,11 @@ func (r *Reconciler) removeExternalSecret(ctx context.Context, esName, namespace
 		return result, err
 	}
 
+	// Never delete ExternalSecrets that were not created by this ClusterExternalSecret
+	if !metav1.IsControlledBy(&existingES, clusterExternalSecret) {
+		return "", nil
+	}
+
 	err = r.Delete(ctx, &existingES, &client.DeleteOptions{})
 
 	if err != nil {
@@ -201,11 +206,11 @@ func (r *Reconciler) deferPatch(ctx context.Context, log logr.Logger, clusterExt
 	}
 }
 
-func (r *Reconciler) removeOldNamespaces(ctx context.Context, namespaceList v1.NamespaceList, esName string, provisionedNamespaces []string) map[string]string {
+func (r *Reconciler) removeOldNamespaces(ctx context.Context, clusterExternalSecret *esv1beta1.ClusterExternalSecret, namespaceList v1.NamespaceList, esName string) map[string]string {
 	failedNamespaces := map[string]string{}
 	// Loop through existing namespaces first to make sure they still have our labels
-	for _, namespace := range getRemovedNamespaces(namespaceList, provisionedNamespaces) {
-		if result, _ := r.removeExternalSecret(ctx, esName, namespace); result != "" {
+	for _, namespace := range getRemovedNamespaces(namespaceList, clusterExternalSecret.Status.ProvisionedNamespaces) {
+		if result, _ := r.removeExternalSecret(ctx, clusterExternalSecret, esName, namespace); result != "" {
 			failedNamespaces[namespace] = result
 		}
 	}
@@ -251,12 +256,11 @@ func getRemovedNamespaces(nsList v1.NamespaceList, provisionedNs []string) []str
 }
 
 func setFailedNamespaces(ces *esv1beta1.ClusterExternalSecret, failedNamespaces map[string]string) {
+	ces.Status.FailedNamespaces = nil
 	if len(failedNamespaces) == 0 {
 		return
 	}
 
-	ces.Status.FailedNamespaces = []esv1beta1.ClusterExternalSecretNamespaceFailure{}
-
 	for namespace, message := range failedNamespaces {
 		ces.Status.FailedNamespaces = append(ces.Status.FailedNamespaces, esv1beta1.ClusterExternalSecretNamespaceFailure{
 			Namespace: namespace,
@@ -265,11 +269,35 @@ func setFailedNamespaces(ces *esv1beta1.ClusterExternalSecret, failedNamespaces
 	}
 }
 
+// findObjectsForNamespace returns a request for every ClusterExternalSecret that selects the namespace
+// or provisioned an ExternalSecret in it, so that namespaces are picked up as soon as their labels change.
+func (r *Reconciler) findObjectsForNamespace(namespace client.Object) []reconcile.Request {
+	var clusterExternalSecrets esv1beta1.ClusterExternalSecretList
+	if err := r.List(context.Background(), &clusterExternalSecrets); err != nil {
+		r.Log.Error(err, errGetCES)
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for i := range clusterExternalSecrets.Items {
+		ces := &clusterExternalSecrets.Items[i]
+		if selectsNamespace(ces, namespace) || containsString(ces.Status.ProvisionedNamespaces, namespace.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ces.GetName()}})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
 		For(&esv1beta1.ClusterExternalSecret{}).
 		Owns(&esv1beta1.ExternalSecret{}, builder.OnlyMetadata).
+		Watches(
+			&source.Kind{Type: &v1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
 		Complete(r)
 }
@@ -16,6 +16,9 @@ package clusterexternalsecret
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -63,3 +66,21 @@ func ContainsNamespace(namespaces v1.NamespaceList, namespace string) bool {
 
 	return false
 }
+
+// selectsNamespace returns true if the namespaceSelector of the ClusterExternalSecret matches the namespace.
+func selectsNamespace(ces *esv1beta1.ClusterExternalSecret, namespace client.Object) bool {
+	selector, err := metav1.LabelSelectorAsSelector(&ces.Spec.NamespaceSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(namespace.GetLabels()))
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
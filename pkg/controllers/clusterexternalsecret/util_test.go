@@ -0,0 +1,91 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestFindObjectsForNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = esv1beta1.AddToScheme(scheme)
+	byLabel := &esv1beta1.ClusterExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "by-label"},
+		Spec: esv1beta1.ClusterExternalSecretSpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+		},
+	}
+	byExpression := &esv1beta1.ClusterExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "by-expression"},
+		Spec: esv1beta1.ClusterExternalSecretSpec{
+			NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"dev"}},
+			}},
+		},
+	}
+	provisioned := &esv1beta1.ClusterExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "provisioned"},
+		Spec: esv1beta1.ClusterExternalSecretSpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "search"}},
+		},
+		Status: esv1beta1.ClusterExternalSecretStatus{ProvisionedNamespaces: []string{"payments-dev"}},
+	}
+	r := &Reconciler{
+		Client: clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(byLabel, byExpression, provisioned).Build(),
+		Log:    ctrl.Log.WithName("test"),
+	}
+
+	tests := map[string]struct {
+		namespace string
+		labels    map[string]string
+		want      []string
+	}{
+		"matching labels and expressions": {
+			namespace: "payments-prod",
+			labels:    map[string]string{"team": "payments", "env": "prod"},
+			want:      []string{"by-expression", "by-label"},
+		},
+		"excluded by expression but previously provisioned": {
+			namespace: "payments-dev",
+			labels:    map[string]string{"env": "dev"},
+			want:      []string{"provisioned"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tc.namespace, Labels: tc.labels}}
+			var want []reconcile.Request
+			for _, n := range tc.want {
+				want = append(want, reconcile.Request{NamespacedName: types.NamespacedName{Name: n}})
+			}
+			if got := r.findObjectsForNamespace(ns); !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected requests: expected %v, got %v", want, got)
+			}
+		})
+	}
+}
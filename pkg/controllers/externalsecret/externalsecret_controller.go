@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,6 +37,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
@@ -52,10 +57,17 @@ const (
 
 	errGetES                 = "could not get ExternalSecret"
 	errConvert               = "could not apply conversion strategy to keys: %v"
+	errRewrite               = "could not rewrite keys of dataFrom[%d]: %w"
+	errDecode                = "could not decode %s[%d]: %w"
+	errKeyConflict           = "key %s is set by %s and %s"
+	msgSyncedSkippedKeys     = "Secret was synced, skipped missing optional keys: %s"
+	errVersionUnsupported    = "provider does not support selecting version %s of key %s"
+	errInvalidVersion        = "could not select version of key %s: %w"
 	errUpdateSecret          = "could not update Secret"
 	errPatchStatus           = "unable to patch status"
 	errGetSecretStore        = "could not get SecretStore %q, %w"
 	errGetClusterSecretStore = "could not get ClusterSecretStore %q, %w"
+	errClusterStoreMismatch  = "using cluster store %q is not allowed from namespace %q: denied by spec.condition"
 	errStoreRef              = "could not get store reference"
 	errStoreProvider         = "could not get store provider"
 	errStoreClient           = "could not get provider client"
@@ -72,8 +84,14 @@ const (
 	errPolicyMergeGetSecret  = "unable to get secret %s: %w"
 	errPolicyMergeMutate     = "unable to mutate secret %s: %w"
 	errPolicyMergePatch      = "unable to patch secret %s: %w"
+	errRecreateSecret        = "unable to recreate immutable secret %s: %w"
+	errTplCMGet              = "could not get template configmap %s: %w"
+	errUnknownTplTarget      = "unknown templateFrom target %s"
+	errTplSecGet             = "could not get template secret %s: %w"
 	errTplCMMissingKey       = "error in configmap %s: missing key %s"
 	errTplSecMissingKey      = "error in secret %s: missing key %s"
+	errMissingTypeKey        = "secret of type %s must contain key %s"
+	errMissingBasicAuthKey   = "secret of type %s must contain key %s or %s"
 )
 
 // Reconciler reconciles a ExternalSecret object.
@@ -84,7 +102,19 @@ type Reconciler struct {
 	ControllerClass           string
 	RequeueInterval           time.Duration
 	ClusterSecretStoreEnabled bool
-	recorder                  record.EventRecorder
+	// TargetSecretWatchEnabled reconciles ExternalSecrets when their target secret changed.
+	TargetSecretWatchEnabled bool
+	// ErrorBackoffBase is the time a failed sync is retried after, it doubles with every
+	// further failed sync up to ErrorBackoffMax. Both default to 30s.
+	ErrorBackoffBase time.Duration
+	ErrorBackoffMax  time.Duration
+	// GeneratorStateGracePeriod delays the cleanup of superseded generator states.
+	GeneratorStateGracePeriod time.Duration
+	// RefreshJitter extends the refresh interval by a random fraction of up to RefreshJitter.
+	RefreshJitter float64
+	// ClientCache shares the provider clients between reconciles, if set.
+	ClientCache *secretstore.ClientCache
+	recorder    record.EventRecorder
 }
 
 // Reconcile implements the main reconciliation loop
@@ -93,6 +123,7 @@ type Reconciler struct {
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("ExternalSecret", req.NamespacedName)
 
+	start := time.Now()
 	syncCallsMetricLabels := prometheus.Labels{"name": req.Name, "namespace": req.Namespace}
 
 	var externalSecret esv1beta1.ExternalSecret
@@ -114,6 +145,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	storeMetricLabels := storeSyncMetricLabels(&externalSecret)
+	defer func() {
+		reconcileDuration.With(storeMetricLabels).Observe(time.Since(start).Seconds())
+	}()
+
 	if shouldSkipClusterSecretStore(r, externalSecret) {
 		log.Info("skipping cluster secret store as it is disabled")
 		return ctrl.Result{}, nil
@@ -128,14 +164,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}()
 
-	store, err := r.getStore(ctx, &externalSecret)
+	store, err := r.getStore(ctx, req.Namespace, externalSecret.Spec.SecretStoreRef)
 	if err != nil {
 		log.Error(err, errStoreRef)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonInvalidStoreRef, err.Error())
 		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreRef)
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
-		syncCallsError.With(syncCallsMetricLabels).Inc()
-		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		countSyncError(syncCallsMetricLabels, storeMetricLabels)
+		return r.errorResult(&externalSecret), nil
 	}
 
 	log = log.WithValues("SecretStore", store.GetNamespacedName())
@@ -146,29 +182,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
-	storeProvider, err := esv1beta1.GetProvider(store)
+	// a ClusterSecretStore may be restricted to specific namespaces
+	allowed, err := secretstore.IsNamespaceAllowed(ctx, r.Client, store, req.Namespace)
+	if err == nil && !allowed {
+		err = fmt.Errorf(errClusterStoreMismatch, store.GetName(), req.Namespace)
+	}
+	if err != nil {
+		log.Error(err, errStoreRef)
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonInvalidStoreRef, err.Error())
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, err.Error())
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		countSyncError(syncCallsMetricLabels, storeMetricLabels)
+		return r.errorResult(&externalSecret), nil
+	}
+
+	_, err = esv1beta1.GetProvider(store)
 	if err != nil {
 		log.Error(err, errStoreProvider)
-		syncCallsError.With(syncCallsMetricLabels).Inc()
-		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		countSyncError(syncCallsMetricLabels, storeMetricLabels)
+		return r.errorResult(&externalSecret), nil
 	}
 
-	secretClient, err := storeProvider.NewClient(ctx, store, r.Client, req.Namespace)
+	secretClient, release, err := r.ClientCache.NewClient(ctx, store, r.Client, req.Namespace)
 	if err != nil {
 		log.Error(err, errStoreClient)
 		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreClient)
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonProviderClientConfig, err.Error())
-		syncCallsError.With(syncCallsMetricLabels).Inc()
-		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		countSyncError(syncCallsMetricLabels, storeMetricLabels)
+		return r.errorResult(&externalSecret), nil
 	}
 
-	defer func() {
-		err = secretClient.Close(ctx)
-		if err != nil {
-			log.Error(err, errCloseStoreClient)
-		}
-	}()
+	// the stores referenced by sourceRefs share the clients with the default store
+	clients := newClientManager(r, req.Namespace)
+	clients.add(externalSecret.Spec.SecretStoreRef, store, secretClient, release)
+	defer clients.Close(ctx)
 
 	refreshInt := r.RequeueInterval
 	if externalSecret.Spec.RefreshInterval != nil {
@@ -176,9 +224,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// Target Secret Name should default to the ExternalSecret name if not explicitly specified
-	secretName := externalSecret.Spec.Target.Name
-	if secretName == "" {
-		secretName = externalSecret.ObjectMeta.Name
+	secretName, err := targetSecretName(&externalSecret)
+	if err != nil {
+		log.Error(err, errUpdateSecret)
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, err.Error())
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		countSyncError(syncCallsMetricLabels, storeMetricLabels)
+		return r.errorResult(&externalSecret), nil
 	}
 
 	// fetch external secret, we need to ensure that it exists, and it's hashmap corresponds
@@ -195,35 +248,44 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// 1. resource generation hasn't changed
 	// 2. refresh interval is 0
 	// 3. if we're still within refresh-interval
-	if !shouldRefresh(externalSecret) && isSecretValid(existingSecret) {
+	// 4. the target secret was not changed since the last sync, is not managed at all (creationPolicy=None)
+	//    or exists and must not be synced again (refreshInterval=0)
+	if !shouldRefresh(externalSecret) && (externalSecret.Spec.Target.CreationPolicy == esv1beta1.CreatePolicyNone || isSyncedOnce(externalSecret, existingSecret) || isSecretInSync(externalSecret, existingSecret)) {
 		log.V(1).Info("skipping refresh", "rv", getResourceVersion(externalSecret))
-		return ctrl.Result{RequeueAfter: refreshInt}, nil
+		return ctrl.Result{RequeueAfter: utils.Jitter(refreshInt, r.RefreshJitter)}, nil
 	}
-	if !shouldReconcile(externalSecret) {
-		log.V(1).Info("stopping reconciling", "rv", getResourceVersion(externalSecret))
-		return ctrl.Result{
-			RequeueAfter: 0,
-			Requeue:      false,
-		}, nil
-	}
-
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
 			Namespace: externalSecret.Namespace,
 		},
-		Immutable: &externalSecret.Spec.Target.Immutable,
-		Data:      make(map[string][]byte),
+		Data: make(map[string][]byte),
+	}
+	// the immutability is only managed for secrets the controller creates, with creationPolicy=Merge
+	// it is left to the owner of the secret.
+	if policy := externalSecret.Spec.Target.CreationPolicy; policy != esv1beta1.CreatePolicyMerge && policy != esv1beta1.CreatePolicyNone {
+		secret.Immutable = &externalSecret.Spec.Target.Immutable
 	}
 
-	dataMap, err := r.getProviderSecretData(ctx, secretClient, &externalSecret)
+	states := newGeneratorStates()
+	dataMap, skippedKeys, err := r.getProviderSecretData(ctx, clients, &externalSecret, &existingSecret, states)
+	createdStates, stateErr := r.createGeneratorStates(ctx, &externalSecret, states, err != nil)
+	if err == nil {
+		// generated data that can't be recorded is not synced.
+		err = stateErr
+	}
 	if err != nil {
 		log.Error(err, errGetSecretData)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
-		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errGetSecretData)
+		reason := esv1beta1.ConditionReasonSecretSyncedError
+		var conflict *keyConflictError
+		if errors.As(err, &conflict) {
+			reason = esv1beta1.ConditionReasonSecretKeyConflict
+		}
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, reason, fmt.Sprintf("%s: %v", errGetSecretData, err))
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
-		syncCallsError.With(syncCallsMetricLabels).Inc()
-		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		countSyncError(syncCallsMetricLabels, storeMetricLabels)
+		return r.errorResult(&externalSecret), nil
 	}
 
 	// if no data was found we can delete the secret if needed.
@@ -239,8 +301,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
 				conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errDeleteSecret)
 				SetExternalSecretCondition(&externalSecret, *conditionSynced)
-				syncCallsError.With(syncCallsMetricLabels).Inc()
-				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+				countSyncError(syncCallsMetricLabels, storeMetricLabels)
+				return r.errorResult(&externalSecret), nil
 			}
 			err = r.Delete(ctx, secret)
 			if err != nil && !apierrors.IsNotFound(err) {
@@ -248,11 +310,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
 				conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errDeleteSecret)
 				SetExternalSecretCondition(&externalSecret, *conditionSynced)
-				syncCallsError.With(syncCallsMetricLabels).Inc()
+				countSyncError(syncCallsMetricLabels, storeMetricLabels)
 			}
 
 			conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretDeleted, "secret deleted due to DeletionPolicy")
 			SetExternalSecretCondition(&externalSecret, *conditionSynced)
+			externalSecret.Status.FailedSyncs = 0
+			externalSecret.Status.NextRetryTime = nil
 			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 
 		case esv1beta1.DeletionPolicyMerge:
@@ -271,29 +335,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				return fmt.Errorf(errSetCtrlReference, err)
 			}
 		}
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
-		}
+		// the secret is populated from scratch, otherwise keys that are
+		// neither part of the template nor of the provider data would
+		// linger in the secret.
+		secret.Data = make(map[string][]byte)
 		err = r.applyTemplate(ctx, &externalSecret, secret, dataMap)
 		if err != nil {
 			return fmt.Errorf(errApplyTemplate, err)
 		}
-
-		// diff existing keys
-		if externalSecret.Spec.Target.DeletionPolicy == esv1beta1.DeletionPolicyMerge {
-			keys, err := getManagedKeys(&existingSecret)
-			if err != nil {
-				return err
-			}
-			for _, key := range keys {
-				if dataMap[key] == nil {
-					secret.Data[key] = nil
-				}
-			}
-		}
+		// with creationPolicy=Merge keys that are no longer part of the
+		// applied data are removed by server-side apply, keys of other
+		// field managers are left untouched.
 		return nil
 	}
 
+	var recreated bool
 	// nolint
 	switch externalSecret.Spec.Target.CreationPolicy {
 	case esv1beta1.CreatePolicyMerge:
@@ -302,7 +358,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		log.V(1).Info("secret creation skipped due to creationPolicy=None")
 		err = nil
 	default:
-		_, err = ctrl.CreateOrUpdate(ctx, r.Client, secret, mutationFunc)
+		if isImmutable(existingSecret) {
+			recreated, err = r.recreateSecret(ctx, secret, &existingSecret, mutationFunc)
+		} else {
+			_, err = ctrl.CreateOrUpdate(ctx, r.Client, secret, mutationFunc)
+		}
 	}
 
 	if err != nil {
@@ -310,17 +370,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
 		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errUpdateSecret)
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
-		syncCallsError.With(syncCallsMetricLabels).Inc()
-		return ctrl.Result{}, err
+		countSyncError(syncCallsMetricLabels, storeMetricLabels)
+		return r.errorResult(&externalSecret), nil
 	}
 
-	r.recorder.Event(&externalSecret, v1.EventTypeNormal, esv1beta1.ReasonUpdated, "Updated Secret")
+	r.supersedeGeneratorStates(ctx, &externalSecret, states, createdStates)
+
 	conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretSynced, "Secret was synced")
+	if len(skippedKeys) > 0 {
+		conditionSynced.Message = fmt.Sprintf(msgSyncedSkippedKeys, strings.Join(skippedKeys, ", "))
+	}
+	if recreated {
+		r.recorder.Event(&externalSecret, v1.EventTypeNormal, esv1beta1.ReasonRecreated, "Recreated immutable Secret")
+		conditionSynced = NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretRecreated, "immutable Secret was recreated")
+	} else {
+		r.recorder.Event(&externalSecret, v1.EventTypeNormal, esv1beta1.ReasonUpdated, "Updated Secret")
+	}
 	currCond := GetExternalSecretCondition(externalSecret.Status, esv1beta1.ExternalSecretReady)
 	SetExternalSecretCondition(&externalSecret, *conditionSynced)
 	externalSecret.Status.RefreshTime = metav1.NewTime(time.Now())
 	externalSecret.Status.SyncedResourceVersion = getResourceVersion(externalSecret)
-	syncCallsTotal.With(syncCallsMetricLabels).Inc()
+	externalSecret.Status.TargetSecret = targetStatus(externalSecret, secret)
+	externalSecret.Status.Generators = states.status
+	externalSecret.Status.FailedSyncs = 0
+	externalSecret.Status.NextRetryTime = nil
+	countSyncCall(syncCallsMetricLabels, storeMetricLabels)
 	if currCond == nil || currCond.Status != conditionSynced.Status {
 		log.Info("reconciled secret") // Log once if on success in any verbosity
 	} else {
@@ -328,10 +402,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	return ctrl.Result{
-		RequeueAfter: refreshInt,
+		RequeueAfter: utils.Jitter(refreshInt, r.RefreshJitter),
 	}, nil
 }
 
+// errorResult records a failed sync in the status of the ExternalSecret and returns
+// the result that retries the sync with an exponential backoff. The backoff is
+// independent of the refresh interval, so that a short refresh interval doesn't
+// retry a failing provider at the same rate.
+func (r *Reconciler) errorResult(es *esv1beta1.ExternalSecret) ctrl.Result {
+	base, maxBackoff := r.ErrorBackoffBase, r.ErrorBackoffMax
+	if base <= 0 {
+		base = requeueAfter
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = requeueAfter
+	}
+	es.Status.FailedSyncs++
+	backoff := utils.Backoff(base, maxBackoff, es.Status.FailedSyncs)
+	next := metav1.NewTime(time.Now().Add(backoff))
+	es.Status.NextRetryTime = &next
+	return ctrl.Result{RequeueAfter: backoff}
+}
+
 func patchSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, secret *v1.Secret, mutationFunc func() error) error {
 	err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret.DeepCopy())
 	if apierrors.IsNotFound(err) {
@@ -373,6 +466,31 @@ func patchSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, s
 	return nil
 }
 
+// recreateSecret deletes and creates the immutable secret if its data or
+// immutability changed, as these can not be updated. Metadata changes are
+// applied with an update.
+func (r *Reconciler) recreateSecret(ctx context.Context, secret, existingSecret *v1.Secret, mutationFunc func() error) (bool, error) {
+	desired := secret.DeepCopy()
+	err := mutationFunc()
+	if err != nil {
+		return false, err
+	}
+	if equality.Semantic.DeepEqual(secret.Data, existingSecret.Data) && equality.Semantic.DeepEqual(secret.Immutable, existingSecret.Immutable) {
+		*secret = *desired
+		_, err = ctrl.CreateOrUpdate(ctx, r.Client, secret, mutationFunc)
+		return false, err
+	}
+	err = r.Delete(ctx, existingSecret, client.Preconditions{UID: &existingSecret.UID})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf(errRecreateSecret, secret.Name, err)
+	}
+	err = r.Create(ctx, secret)
+	if err != nil {
+		return false, fmt.Errorf(errRecreateSecret, secret.Name, err)
+	}
+	return true, nil
+}
+
 func getManagedKeys(secret *v1.Secret) ([]string, error) {
 	var keys []string
 	for _, v := range secret.ObjectMeta.ManagedFields {
@@ -388,7 +506,7 @@ func getManagedKeys(secret *v1.Secret) ([]string, error) {
 		if dataFields == nil {
 			continue
 		}
-		df, ok := dataFields.(map[string]string)
+		df, ok := dataFields.(map[string]interface{})
 		if !ok {
 			continue
 		}
@@ -428,29 +546,58 @@ func shouldRefresh(es esv1beta1.ExternalSecret) bool {
 	}
 
 	// skip refresh if refresh interval is 0
-	if es.Spec.RefreshInterval.Duration == 0 && es.Status.SyncedResourceVersion != "" {
+	if isSyncOnce(es) && es.Status.SyncedResourceVersion != "" {
 		return false
 	}
-	if es.Status.RefreshTime.IsZero() {
+	if es.Status.RefreshTime.IsZero() || es.Spec.RefreshInterval == nil {
 		return true
 	}
 	return !es.Status.RefreshTime.Add(es.Spec.RefreshInterval.Duration).After(time.Now())
 }
 
-func shouldReconcile(es esv1beta1.ExternalSecret) bool {
-	if es.Spec.Target.Immutable && hasSyncedCondition(es) {
+// isSyncOnce checks if the ExternalSecret is synced on creation and spec changes only.
+func isSyncOnce(es esv1beta1.ExternalSecret) bool {
+	return es.Spec.RefreshInterval != nil && es.Spec.RefreshInterval.Duration == 0
+}
+
+// isSyncedOnce checks if the target secret of an ExternalSecret with refreshInterval=0 exists.
+// It is not synced again, even if its data was changed, so that changes at the provider don't overwrite it.
+func isSyncedOnce(es esv1beta1.ExternalSecret, existingSecret v1.Secret) bool {
+	return isSyncOnce(es) && existingSecret.UID != ""
+}
+
+// isSecretInSync checks if the target secret was not changed since the last sync.
+// ExternalSecrets which were synced before the target secret was tracked in the
+// status fall back to the data hash annotation.
+func isSecretInSync(es esv1beta1.ExternalSecret, existingSecret v1.Secret) bool {
+	target := es.Status.TargetSecret
+	if target == nil {
+		return isSecretValid(managedSecret(es, existingSecret))
+	}
+	if existingSecret.UID == "" || existingSecret.Name != target.Name {
 		return false
 	}
-	return true
+	if target.ResourceVersion != "" && existingSecret.ResourceVersion == target.ResourceVersion {
+		return true
+	}
+	return existingSecret.Annotations[esv1beta1.AnnotationDataHash] == target.DataHash && isSecretValid(managedSecret(es, existingSecret))
 }
 
-func hasSyncedCondition(es esv1beta1.ExternalSecret) bool {
-	for _, condition := range es.Status.Conditions {
-		if condition.Reason == "SecretSynced" {
-			return true
-		}
+// targetStatus returns the version of the target secret written by the sync.
+func targetStatus(es esv1beta1.ExternalSecret, secret *v1.Secret) *esv1beta1.ExternalSecretTargetStatus {
+	if es.Spec.Target.CreationPolicy == esv1beta1.CreatePolicyNone {
+		return nil
+	}
+	return &esv1beta1.ExternalSecretTargetStatus{
+		Name:            secret.Name,
+		ResourceVersion: secret.ResourceVersion,
+		DataHash:        secret.Annotations[esv1beta1.AnnotationDataHash],
 	}
-	return false
+}
+
+// isImmutable checks if the secret exists and is immutable.
+func isImmutable(existingSecret v1.Secret) bool {
+	return existingSecret.UID != "" && existingSecret.Immutable != nil && *existingSecret.Immutable
 }
 
 // isSecretValid checks if the secret exists, and it's data is consistent with the calculated hash.
@@ -467,13 +614,35 @@ func isSecretValid(existingSecret v1.Secret) bool {
 	return true
 }
 
-// getStore returns the store with the provided ExternalSecret.
-func (r *Reconciler) getStore(ctx context.Context, externalSecret *esv1beta1.ExternalSecret) (esv1beta1.GenericStore, error) {
+// managedSecret returns the existing secret reduced to the data keys owned by
+// external-secrets if the ExternalSecret uses creationPolicy=Merge.
+// The data hash only covers these keys, keys of other field managers must not
+// invalidate it.
+func managedSecret(es esv1beta1.ExternalSecret, existingSecret v1.Secret) v1.Secret {
+	if es.Spec.Target.CreationPolicy != esv1beta1.CreatePolicyMerge {
+		return existingSecret
+	}
+	keys, err := getManagedKeys(&existingSecret)
+	if err != nil {
+		return existingSecret
+	}
+	managed := *existingSecret.DeepCopy()
+	managed.Data = make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if value, ok := existingSecret.Data[key]; ok {
+			managed.Data[key] = value
+		}
+	}
+	return managed
+}
+
+// getStore returns the store of the provided store reference.
+func (r *Reconciler) getStore(ctx context.Context, namespace string, storeRef esv1beta1.SecretStoreRef) (esv1beta1.GenericStore, error) {
 	ref := types.NamespacedName{
-		Name: externalSecret.Spec.SecretStoreRef.Name,
+		Name: storeRef.Name,
 	}
 
-	if externalSecret.Spec.SecretStoreRef.Kind == esv1beta1.ClusterSecretStoreKind {
+	if storeRef.Kind == esv1beta1.ClusterSecretStoreKind {
 		var store esv1beta1.ClusterSecretStore
 		err := r.Get(ctx, ref, &store)
 		if err != nil {
@@ -483,7 +652,7 @@ func (r *Reconciler) getStore(ctx context.Context, externalSecret *esv1beta1.Ext
 		return &store, nil
 	}
 
-	ref.Namespace = externalSecret.Namespace
+	ref.Namespace = namespace
 
 	var store esv1beta1.SecretStore
 	err := r.Get(ctx, ref, &store)
@@ -493,67 +662,256 @@ func (r *Reconciler) getStore(ctx context.Context, externalSecret *esv1beta1.Ext
 	return &store, nil
 }
 
-// getProviderSecretData returns the provider's secret data with the provided ExternalSecret.
-func (r *Reconciler) getProviderSecretData(ctx context.Context, providerClient esv1beta1.SecretsClient, externalSecret *esv1beta1.ExternalSecret) (map[string][]byte, error) {
+// getProviderSecretData returns the provider's secret data with the provided ExternalSecret
+// and the keys of optional data entries which were skipped as they don't exist at the provider.
+// Entries with a sourceRef are fetched from the referenced store or generator.
+// Failed provider requests are retried according to the retrySettings of the store.
+// Generators are called once, entries which reference the same generator share its data.
+// Generators with rotationPolicy=OnlyWhenMissing keep their data of existingSecret.
+// The states and the status of the generators are added to states.
+func (r *Reconciler) getProviderSecretData(ctx context.Context, clients *clientManager, externalSecret *esv1beta1.ExternalSecret, existingSecret *v1.Secret, states *generatorStates) (map[string][]byte, []string, error) {
+	generatorEntries, err := r.prepareGeneratorEntries(ctx, externalSecret, existingSecret)
+	if err != nil {
+		return nil, nil, err
+	}
 	providerData := make(map[string][]byte)
+	// sources holds the entry which set a key of providerData.
+	sources := make(map[string]string)
+	var skippedKeys []string
 
 	for i, remoteRef := range externalSecret.Spec.DataFrom {
+		source := fmt.Sprintf("dataFrom[%d]", i)
 		var secretMap map[string][]byte
 		var err error
-		if remoteRef.Find != nil {
-			secretMap, err = providerClient.GetAllSecrets(ctx, *remoteRef.Find)
+		var strategy esv1beta1.ExternalSecretConversionStrategy
+		var decoding esv1beta1.ExternalSecretDecodingStrategy
+		var gen *generatorResult
+		var sc storeClient
+		if ref := generatorRef(remoteRef.SourceRef); ref != nil {
+			gen = generatorEntries[source]
+			if err := r.getGeneratorData(ctx, externalSecret, gen, states); err != nil {
+				return nil, nil, err
+			}
+			secretMap = gen.data
+			if !gen.kept {
+				secretMap, err = selectGeneratedKeys(ref, secretMap)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+		} else if sc, err = clients.forSource(ctx, externalSecret.Spec.SecretStoreRef, remoteRef.SourceRef); err != nil {
+			return nil, nil, err
+		} else if remoteRef.Find != nil {
+			// keys are converted after the rewrite, so that the rewrite operates on the provider keys.
+			find := *remoteRef.Find
+			find.ConversionStrategy = esv1beta1.ExternalSecretConversionNone
+			err = secretstore.Retry(ctx, sc.store, sc.client, secretstore.CallGetAllSecrets, func() (err error) {
+				secretMap, err = sc.client.GetAllSecrets(ctx, find)
+				return err
+			})
 			if errors.Is(err, esv1beta1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain {
 				r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .dataFrom[%d]", i))
 				continue
 			}
 			if err != nil {
-				return nil, err
-			}
-			secretMap, err = utils.ConvertKeys(remoteRef.Find.ConversionStrategy, secretMap)
-			if err != nil {
-				return nil, fmt.Errorf(errConvert, err)
+				return nil, nil, err
 			}
+			strategy = remoteRef.Find.ConversionStrategy
+			decoding = remoteRef.Find.DecodingStrategy
 		} else if remoteRef.Extract != nil {
-			secretMap, err = providerClient.GetSecretMap(ctx, *remoteRef.Extract)
+			if err := validateVersion(sc.client, *remoteRef.Extract); err != nil {
+				return nil, nil, err
+			}
+			err = secretstore.Retry(ctx, sc.store, sc.client, secretstore.CallGetSecretMap, func() (err error) {
+				secretMap, err = sc.client.GetSecretMap(ctx, *remoteRef.Extract)
+				return err
+			})
 			if errors.Is(err, esv1beta1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain {
 				r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .dataFrom[%d]", i))
 				continue
 			}
 			if err != nil {
-				return nil, err
+				return nil, nil, err
+			}
+			strategy = remoteRef.Extract.ConversionStrategy
+			decoding = remoteRef.Extract.DecodingStrategy
+		}
+		// kept generator data is taken from the target secret as is.
+		if gen == nil || !gen.kept {
+			secretMap, err = utils.RewriteMap(remoteRef.Rewrite, secretMap)
+			if err != nil {
+				return nil, nil, fmt.Errorf(errRewrite, i, err)
+			}
+			secretMap, err = utils.ConvertKeys(strategy, secretMap)
+			if err != nil {
+				return nil, nil, fmt.Errorf(errConvert, err)
 			}
-			secretMap, err = utils.ConvertKeys(remoteRef.Extract.ConversionStrategy, secretMap)
+			secretMap, err = utils.DecodeMap(decoding, secretMap)
 			if err != nil {
-				return nil, fmt.Errorf(errConvert, err)
+				return nil, nil, fmt.Errorf(errDecode, "dataFrom", i, err)
 			}
 		}
+		if gen != nil {
+			secretMap = recordGeneratorData(gen, existingSecret, secretMap, states)
+		}
 
-		providerData = utils.MergeByteMap(providerData, secretMap)
+		err = r.mergeData(externalSecret, providerData, sources, source, secretMap)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	for i, secretRef := range externalSecret.Spec.Data {
-		secretData, err := providerClient.GetSecret(ctx, secretRef.RemoteRef)
+		source := fmt.Sprintf("data[%d]", i)
+		if generatorRef(secretRef.SourceRef) != nil {
+			gen := generatorEntries[source]
+			if err := r.getGeneratedValue(ctx, externalSecret, gen, &externalSecret.Spec.Data[i], states); err != nil {
+				return nil, nil, err
+			}
+			if !gen.kept {
+				gen.data[secretRef.SecretKey], err = utils.Decode(secretRef.RemoteRef.DecodingStrategy, gen.data[secretRef.SecretKey])
+				if err != nil {
+					return nil, nil, fmt.Errorf(errDecode, "data", i, err)
+				}
+			}
+			err = r.mergeData(externalSecret, providerData, sources, source, recordGeneratorData(gen, existingSecret, gen.data, states))
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		var secretData []byte
+		sc, err := clients.forSource(ctx, externalSecret.Spec.SecretStoreRef, secretRef.SourceRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := validateVersion(sc.client, secretRef.RemoteRef); err != nil {
+			return nil, nil, err
+		}
+		err = secretstore.Retry(ctx, sc.store, sc.client, secretstore.CallGetSecret, func() (err error) {
+			secretData, err = sc.client.GetSecret(ctx, secretRef.RemoteRef)
+			return err
+		})
+		if errors.Is(err, esv1beta1.NoSecretErr) && secretRef.Optional {
+			r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonMissingOptionalKey, fmt.Sprintf("skipping optional .data[%d] key=%s, secret does not exist at provider", i, secretRef.RemoteRef.Key))
+			skippedKeys = append(skippedKeys, secretRef.SecretKey)
+			continue
+		}
 		if errors.Is(err, esv1beta1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain {
 			r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .data[%d] key=%s", i, secretRef.RemoteRef.Key))
 			continue
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		secretData, err = utils.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
+		if err != nil {
+			return nil, nil, fmt.Errorf(errDecode, "data", i, err)
+		}
+
+		err = r.mergeData(externalSecret, providerData, sources, source, map[string][]byte{secretRef.SecretKey: secretData})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return providerData, skippedKeys, nil
+}
+
+// keyConflictError is returned if a key is set by several entries with conflictPolicy=Error.
+type keyConflictError struct {
+	key    string
+	first  string
+	second string
+}
+
+func (e *keyConflictError) Error() string {
+	return fmt.Sprintf(errKeyConflict, e.key, e.first, e.second)
+}
+
+// mergeData merges the data of the entry source into providerData according to the conflictPolicy.
+// Keys which were set by an earlier entry fail with conflictPolicy=Error and are reported with an event
+// with FirstWins and LastWins. Without a conflictPolicy the last entry wins silently, like an overlay.
+func (r *Reconciler) mergeData(externalSecret *esv1beta1.ExternalSecret, providerData map[string][]byte, sources map[string]string, source string, data map[string][]byte) error {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if prev, ok := sources[key]; ok {
+			switch externalSecret.Spec.ConflictPolicy {
+			case esv1beta1.ConflictPolicyError:
+				return &keyConflictError{key: key, first: prev, second: source}
+			case esv1beta1.ConflictPolicyFirstWins:
+				r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonKeyConflict, fmt.Sprintf("%s, keeping the value of %s", fmt.Sprintf(errKeyConflict, key, prev, source), prev))
+				continue
+			case esv1beta1.ConflictPolicyLastWins:
+				r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonKeyConflict, fmt.Sprintf("%s, keeping the value of %s", fmt.Sprintf(errKeyConflict, key, prev, source), source))
+			}
 		}
+		providerData[key] = data[key]
+		sources[key] = source
+	}
+	return nil
+}
 
-		providerData[secretRef.SecretKey] = secretData
+// validateVersion checks that the provider supports the version of ref.
+func validateVersion(providerClient esv1beta1.SecretsClient, ref esv1beta1.ExternalSecretDataRemoteRef) error {
+	if ref.Version == "" {
+		return nil
+	}
+	validator, ok := providerClient.(esv1beta1.VersionValidator)
+	if !ok {
+		return fmt.Errorf(errVersionUnsupported, ref.Version, ref.Key)
+	}
+	if err := validator.ValidateVersion(ref.Version); err != nil {
+		return fmt.Errorf(errInvalidVersion, ref.Key, err)
 	}
+	return nil
+}
 
-	return providerData, nil
+// findObjectsForSecret returns a request for every ExternalSecret that targets the secret,
+// so that changes and deletions of target secrets are reverted immediately, regardless
+// of the creationPolicy.
+func (r *Reconciler) findObjectsForSecret(secret client.Object) []reconcile.Request {
+	var externalSecrets esv1beta1.ExternalSecretList
+	if err := r.List(context.Background(), &externalSecrets, client.InNamespace(secret.GetNamespace())); err != nil {
+		r.Log.Error(err, errGetES)
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for i := range externalSecrets.Items {
+		es := &externalSecrets.Items[i]
+		secretName, err := targetSecretName(es)
+		if err == nil && secretName == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: es.Name, Namespace: es.Namespace}})
+		}
+	}
+	return requests
 }
 
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("external-secrets")
 
-	return ctrl.NewControllerManagedBy(mgr).
+	// status updates don't trigger a reconcile, so that recording a failed sync
+	// doesn't retry it right away.
+	b := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
-		For(&esv1beta1.ExternalSecret{}).
-		Owns(&v1.Secret{}, builder.OnlyMetadata).
-		Complete(r)
+		For(&esv1beta1.ExternalSecret{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.LabelChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+		)))
+	// without the watch, changes of target secrets are reverted on refresh.
+	if r.TargetSecretWatchEnabled {
+		b = b.Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
+			builder.OnlyMetadata,
+		)
+	}
+	return b.Complete(r)
 }
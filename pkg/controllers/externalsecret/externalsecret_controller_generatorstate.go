@@ -0,0 +1,257 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/generatorstate"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errCreateGeneratorState    = "could not record generator state of %s: %w"
+	errCleanupGeneratorState   = "could not clean up unrecorded generator state of %s: %v"
+	errExpireGeneratorState    = "could not expire generator state %s: %v"
+	errListGeneratorStates     = "could not list generator states: %v"
+	errSupersedeGeneratorState = "could not set garbage collection deadline of generator state %s: %v"
+)
+
+// generatorState is the state a stateful generator returned for the entries of an ExternalSecret.
+type generatorState struct {
+	// sources are the entries which use the data of the state.
+	sources  []string
+	resource []byte
+	state    *apiextensions.JSON
+}
+
+// generatorStates collects the states of stateful generators during a reconcile.
+type generatorStates struct {
+	states []*generatorState
+	// runs holds the data of the generators called during the reconcile.
+	runs map[generatorRunKey]*generatorRun
+	// kept holds the entries which kept the data of the existing target secret,
+	// their new state is unused and prior states are still in use.
+	kept map[string]bool
+	// status holds the generator status of the entries, recorded in the ExternalSecret after the sync.
+	status []esv1beta1.ExternalSecretGeneratorStatus
+}
+
+func newGeneratorStates() *generatorStates {
+	return &generatorStates{kept: make(map[string]bool), runs: make(map[generatorRunKey]*generatorRun)}
+}
+
+// add adds the state a generator returned, the entries which use it are added by the caller.
+func (s *generatorStates) add(resource []byte, state *apiextensions.JSON) *generatorState {
+	if state == nil {
+		return nil
+	}
+	genState := &generatorState{resource: resource, state: state}
+	s.states = append(s.states, genState)
+	return genState
+}
+
+// keep marks that the entry source kept the data of the existing target secret.
+func (s *generatorStates) keep(source string) {
+	s.kept[source] = true
+}
+
+// unused reports whether all entries of the state kept the data of the existing target secret.
+func (s *generatorStates) unused(genState *generatorState) bool {
+	for _, source := range genState.sources {
+		if !s.kept[source] {
+			return false
+		}
+	}
+	return true
+}
+
+// inUse reports whether one of the entries of the prior state kept the data of the existing target secret.
+func (s *generatorStates) inUse(genState *genv1alpha1.GeneratorState) bool {
+	for _, source := range stateSources(genState) {
+		if s.kept[source] {
+			return true
+		}
+	}
+	return false
+}
+
+// stateSources returns the entries which use the data of the prior state.
+func stateSources(genState *genv1alpha1.GeneratorState) []string {
+	return strings.Split(genState.Annotations[genv1alpha1.GeneratorStateAnnotationSource], ",")
+}
+
+// record adds the generator status of an entry.
+func (s *generatorStates) record(status esv1beta1.ExternalSecretGeneratorStatus) {
+	s.status = append(s.status, status)
+}
+
+// generatorStateOwner returns the value of the owner label of the generator states of externalSecret.
+// Names may exceed the length of label values, so they are hashed.
+func generatorStateOwner(externalSecret *esv1beta1.ExternalSecret) string {
+	return utils.ObjectHash(types.NamespacedName{Namespace: externalSecret.Namespace, Name: externalSecret.Name})
+}
+
+// createGeneratorStates records the collected generator states in GeneratorState resources,
+// which are cleaned up by the GeneratorState controller. The states of unused data, as the
+// reconcile failed or the data of the target secret was kept, are garbage collected right away.
+// Unless the target secret is orphaned, the states are owned by the ExternalSecret and are
+// cleaned up together with it.
+// A state that can't be recorded is cleaned up right away and fails the sync, as nothing
+// would clean up the data once it was synced. The other states are garbage collected then.
+// It returns the names of the created GeneratorStates.
+func (r *Reconciler) createGeneratorStates(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, states *generatorStates, failed bool) (map[string]bool, error) {
+	created := make(map[string]bool, len(states.states))
+	var createdStates []*genv1alpha1.GeneratorState
+	var recordErr error
+	for _, s := range states.states {
+		genState := &genv1alpha1.GeneratorState{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: externalSecret.Name + "-",
+				Namespace:    externalSecret.Namespace,
+				Labels: map[string]string{
+					genv1alpha1.GeneratorStateLabelOwner: generatorStateOwner(externalSecret),
+				},
+				Annotations: map[string]string{
+					genv1alpha1.GeneratorStateAnnotationSource: strings.Join(s.sources, ","),
+				},
+				Finalizers: []string{genv1alpha1.GeneratorStateFinalizer},
+			},
+			Spec: genv1alpha1.GeneratorStateSpec{
+				Resource: &apiextensions.JSON{Raw: s.resource},
+				State:    s.state,
+			},
+		}
+		if failed || states.unused(s) {
+			now := metav1.Now()
+			genState.Spec.GarbageCollectionDeadline = &now
+		}
+		err := r.createGeneratorState(ctx, externalSecret, genState)
+		if err != nil {
+			r.Log.Error(err, "could not record generator state", "ExternalSecret", client.ObjectKeyFromObject(externalSecret), "source", s.sources)
+			if err := generatorstate.Cleanup(ctx, r.Client, genState); err != nil {
+				r.Log.Error(err, "could not clean up unrecorded generator state", "ExternalSecret", client.ObjectKeyFromObject(externalSecret), "source", s.sources)
+				r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, fmt.Sprintf(errCleanupGeneratorState, strings.Join(s.sources, ","), err))
+			}
+			if recordErr == nil {
+				recordErr = fmt.Errorf(errCreateGeneratorState, strings.Join(s.sources, ","), err)
+			}
+			continue
+		}
+		created[genState.Name] = true
+		createdStates = append(createdStates, genState)
+	}
+	if recordErr != nil && !failed {
+		r.expireGeneratorStates(ctx, externalSecret, createdStates)
+	}
+	return created, recordErr
+}
+
+// expireGeneratorStates sets the garbage collection deadline of the states to now,
+// as their data is not synced.
+func (r *Reconciler) expireGeneratorStates(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, states []*genv1alpha1.GeneratorState) {
+	now := metav1.Now()
+	for _, genState := range states {
+		p := client.MergeFrom(genState.DeepCopy())
+		genState.Spec.GarbageCollectionDeadline = &now
+		if err := r.Patch(ctx, genState, p); err != nil {
+			r.Log.Error(err, "could not expire generator state", "GeneratorState", genState.Name)
+			r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, fmt.Sprintf(errExpireGeneratorState, genState.Name, err))
+		}
+	}
+}
+
+func (r *Reconciler) createGeneratorState(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, genState *genv1alpha1.GeneratorState) error {
+	if externalSecret.Spec.Target.CreationPolicy != esv1beta1.CreatePolicyOrphan {
+		err := controllerutil.SetControllerReference(externalSecret, genState, r.Scheme)
+		if err != nil {
+			return fmt.Errorf(errSetCtrlReference, err)
+		}
+	}
+	return r.Create(ctx, genState)
+}
+
+// currentGeneratorState returns the newest generator state of externalSecret the entry source uses,
+// or nil if it has none.
+func (r *Reconciler) currentGeneratorState(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, source string) (*genv1alpha1.GeneratorState, error) {
+	var list genv1alpha1.GeneratorStateList
+	err := r.List(ctx, &list, client.InNamespace(externalSecret.Namespace), client.MatchingLabels{
+		genv1alpha1.GeneratorStateLabelOwner: generatorStateOwner(externalSecret),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var current *genv1alpha1.GeneratorState
+	for i := range list.Items {
+		genState := &list.Items[i]
+		if genState.Spec.GarbageCollectionDeadline != nil || !containsString(stateSources(genState), source) {
+			continue
+		}
+		if current == nil || current.CreationTimestamp.Before(&genState.CreationTimestamp) {
+			current = genState
+		}
+	}
+	return current, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// supersedeGeneratorStates sets the garbage collection deadline of the prior generator states
+// of externalSecret after the target secret was synced: their data was replaced or their entry
+// no longer references a generator. States of entries that kept the existing data are still in use.
+// The deadline is delayed by the GeneratorStateGracePeriod, so that consumers can pick up the new data.
+func (r *Reconciler) supersedeGeneratorStates(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, states *generatorStates, created map[string]bool) {
+	var list genv1alpha1.GeneratorStateList
+	err := r.List(ctx, &list, client.InNamespace(externalSecret.Namespace), client.MatchingLabels{
+		genv1alpha1.GeneratorStateLabelOwner: generatorStateOwner(externalSecret),
+	})
+	if err != nil {
+		r.Log.Error(err, "could not list generator states", "ExternalSecret", client.ObjectKeyFromObject(externalSecret))
+		r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, fmt.Sprintf(errListGeneratorStates, err))
+		return
+	}
+	deadline := metav1.NewTime(time.Now().Add(r.GeneratorStateGracePeriod))
+	for i := range list.Items {
+		genState := &list.Items[i]
+		if created[genState.Name] || genState.Spec.GarbageCollectionDeadline != nil || states.inUse(genState) {
+			continue
+		}
+		p := client.MergeFrom(genState.DeepCopy())
+		genState.Spec.GarbageCollectionDeadline = &deadline
+		if err := r.Patch(ctx, genState, p); err != nil {
+			r.Log.Error(err, "could not supersede generator state", "GeneratorState", genState.Name)
+			r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, fmt.Sprintf(errSupersedeGeneratorState, genState.Name, err))
+		}
+	}
+}
@@ -0,0 +1,564 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+
+	// Loading registered generators.
+	_ "github.com/external-secrets/external-secrets/pkg/generator/register"
+)
+
+const (
+	errUnmanagedStore         = "%s %q is not managed by this controller"
+	errClusterStoreDisabled   = "could not use ClusterSecretStore %q: cluster secret stores are disabled"
+	errSourceStoreProvider    = "could not get provider of %s %q: %w"
+	errSourceStoreClient      = "could not get provider client of %s %q: %w"
+	errGeneratorNotRegistered = "no generator registered for kind %s"
+	errGetGenerator           = "could not get generator %s %q: %w"
+	errGenerate               = "could not generate data with %s %q: %w"
+	errMissingGeneratedKey    = "generator %s %q did not generate key %s"
+	errClusterGeneratorDenied = "using cluster generator %q is not allowed from namespace %q: denied by spec.conditions"
+	errClusterGeneratorSpec   = "cluster generator %q has no spec of kind %s"
+	errParseGenerator         = "could not parse generator %s %q: %w"
+	errRenewGenerator         = "could not renew data of generator %s %q, generating new data: %v"
+)
+
+// storeClient is a provider client together with the store it was created for.
+type storeClient struct {
+	store  esv1beta1.GenericStore
+	client esv1beta1.SecretsClient
+	// release releases the client, see secretstore.ClientCache.
+	release func(context.Context) error
+}
+
+// clientManager creates the provider clients of the stores referenced by an
+// ExternalSecret, so that every store is only connected to once per reconcile.
+type clientManager struct {
+	r         *Reconciler
+	namespace string
+	clients   map[esv1beta1.SecretStoreRef]storeClient
+}
+
+func newClientManager(r *Reconciler, namespace string) *clientManager {
+	return &clientManager{
+		r:         r,
+		namespace: namespace,
+		clients:   make(map[esv1beta1.SecretStoreRef]storeClient),
+	}
+}
+
+// add registers a client which was created by the caller.
+func (m *clientManager) add(ref esv1beta1.SecretStoreRef, store esv1beta1.GenericStore, client esv1beta1.SecretsClient, release func(context.Context) error) {
+	m.clients[normalizeStoreRef(ref)] = storeClient{store: store, client: client, release: release}
+}
+
+// forSource returns the client of the store of sourceRef, or the client of defaultRef
+// if sourceRef doesn't reference a store.
+func (m *clientManager) forSource(ctx context.Context, defaultRef esv1beta1.SecretStoreRef, source *esv1beta1.SourceRef) (storeClient, error) {
+	if source != nil && source.SecretStoreRef != nil {
+		return m.get(ctx, *source.SecretStoreRef)
+	}
+	return m.get(ctx, defaultRef)
+}
+
+// get returns the client of the store, which is created on first use.
+func (m *clientManager) get(ctx context.Context, ref esv1beta1.SecretStoreRef) (storeClient, error) {
+	ref = normalizeStoreRef(ref)
+	if c, ok := m.clients[ref]; ok {
+		return c, nil
+	}
+	if ref.Kind == esv1beta1.ClusterSecretStoreKind && !m.r.ClusterSecretStoreEnabled {
+		return storeClient{}, fmt.Errorf(errClusterStoreDisabled, ref.Name)
+	}
+	store, err := m.r.getStore(ctx, m.namespace, ref)
+	if err != nil {
+		return storeClient{}, err
+	}
+	if !secretstore.ShouldProcessStore(store, m.r.ControllerClass) {
+		return storeClient{}, fmt.Errorf(errUnmanagedStore, ref.Kind, ref.Name)
+	}
+	allowed, err := secretstore.IsNamespaceAllowed(ctx, m.r.Client, store, m.namespace)
+	if err != nil {
+		return storeClient{}, err
+	}
+	if !allowed {
+		return storeClient{}, fmt.Errorf(errClusterStoreMismatch, ref.Name, m.namespace)
+	}
+	if _, err := esv1beta1.GetProvider(store); err != nil {
+		return storeClient{}, fmt.Errorf(errSourceStoreProvider, ref.Kind, ref.Name, err)
+	}
+	client, release, err := m.r.ClientCache.NewClient(ctx, store, m.r.Client, m.namespace)
+	if err != nil {
+		return storeClient{}, fmt.Errorf(errSourceStoreClient, ref.Kind, ref.Name, err)
+	}
+	c := storeClient{store: store, client: client, release: release}
+	m.clients[ref] = c
+	return c, nil
+}
+
+// Close releases all clients of the manager.
+func (m *clientManager) Close(ctx context.Context) {
+	for ref, c := range m.clients {
+		if err := c.release(ctx); err != nil {
+			m.r.Log.Error(err, errCloseStoreClient, "SecretStore", ref.Name)
+		}
+	}
+}
+
+func normalizeStoreRef(ref esv1beta1.SecretStoreRef) esv1beta1.SecretStoreRef {
+	if ref.Kind == "" {
+		ref.Kind = esv1beta1.SecretStoreKind
+	}
+	return ref
+}
+
+// generatorRef returns the generator of the sourceRef or nil.
+func generatorRef(source *esv1beta1.SourceRef) *esv1beta1.GeneratorRef {
+	if source == nil {
+		return nil
+	}
+	return source.GeneratorRef
+}
+
+// generatorResult is the data of a generator entry of an ExternalSecret.
+type generatorResult struct {
+	data   map[string][]byte
+	policy genv1alpha1.RotationPolicy
+	// status records the generator of the entry, the keys are set by recordGeneratorData.
+	status esv1beta1.ExternalSecretGeneratorStatus
+	// kept reports that the data of the existing target secret was kept without calling the generator.
+	kept bool
+	// recorded reports that the last sync recorded the status of the entry.
+	recorded bool
+
+	// run identifies the generator resource, entries of the same generator share its data.
+	run generatorRunKey
+	// kind and resource are the generator kind and the JSON of the generator resource.
+	kind     string
+	resource []byte
+	// keep holds the data of the existing target secret the entry may keep.
+	keep      map[string][]byte
+	keepUntil *metav1.Time
+}
+
+// generatorRunKey identifies a generator resource referenced by an ExternalSecret.
+type generatorRunKey struct {
+	apiVersion string
+	kind       string
+	name       string
+}
+
+// generatorRun is the data a generator returned during a reconcile. It is shared
+// by all entries of the generator, as data like key pairs or the access key id and the
+// secret access key of temporary credentials belongs together.
+type generatorRun struct {
+	data      map[string][]byte
+	expiresAt *metav1.Time
+	// state is the state of a stateful generator.
+	state *generatorState
+}
+
+// prepareGeneratorEntries prepares the entries of externalSecret which reference a generator by
+// their source. An entry keeps the data of the existing target secret only if all entries
+// of its generator can keep their data, so that data from different runs is never combined.
+func (r *Reconciler) prepareGeneratorEntries(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, existingSecret *v1.Secret) (map[string]*generatorResult, error) {
+	entries := make(map[string]*generatorResult)
+	// renewed holds the expiry of the renewed generator states by name, entries of a generator share its state.
+	renewed := make(map[string]*metav1.Time)
+	for i, remoteRef := range externalSecret.Spec.DataFrom {
+		if ref := generatorRef(remoteRef.SourceRef); ref != nil {
+			source := fmt.Sprintf("dataFrom[%d]", i)
+			res, err := r.prepareGeneratorEntry(ctx, externalSecret, existingSecret, ref, remoteRef, nil, source, renewed)
+			if err != nil {
+				return nil, err
+			}
+			entries[source] = res
+		}
+	}
+	for i := range externalSecret.Spec.Data {
+		secretRef := &externalSecret.Spec.Data[i]
+		if ref := generatorRef(secretRef.SourceRef); ref != nil {
+			source := fmt.Sprintf("data[%d]", i)
+			res, err := r.prepareGeneratorEntry(ctx, externalSecret, existingSecret, ref, secretRef, []string{secretRef.SecretKey}, source, renewed)
+			if err != nil {
+				return nil, err
+			}
+			entries[source] = res
+		}
+	}
+	regenerate := make(map[generatorRunKey]bool)
+	for _, res := range entries {
+		if res.keep == nil {
+			regenerate[res.run] = true
+		}
+	}
+	for _, res := range entries {
+		if regenerate[res.run] {
+			res.keep = nil
+		}
+	}
+	return entries, nil
+}
+
+// prepareGeneratorEntry returns the entry source which uses the generator resource of ref.
+// Generators with rotationPolicy=OnlyWhenMissing may keep the data of the existing target secret,
+// if it holds the data recorded by the last sync for the same generator spec and entry,
+// keys are the keys the entry sets if they are known in advance. Data which expires before
+// the next refresh is renewed if the generator supports it, otherwise it is generated again.
+func (r *Reconciler) prepareGeneratorEntry(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, existingSecret *v1.Secret, ref *esv1beta1.GeneratorRef, entry interface{}, keys []string, source string, renewed map[string]*metav1.Time) (*generatorResult, error) {
+	kind, raw, err := r.getGeneratorResource(ctx, externalSecret.Namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+	rawEntry, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	var resource struct {
+		Spec json.RawMessage `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return nil, fmt.Errorf(errParseGenerator, ref.Kind, ref.Name, err)
+	}
+	var spec struct {
+		RotationPolicy genv1alpha1.RotationPolicy `json:"rotationPolicy"`
+	}
+	if len(resource.Spec) > 0 {
+		if err := json.Unmarshal(resource.Spec, &spec); err != nil {
+			return nil, fmt.Errorf(errParseGenerator, ref.Kind, ref.Name, err)
+		}
+	}
+	apiVersion := ref.APIVersion
+	if apiVersion == "" {
+		apiVersion = genv1alpha1.SchemeGroupVersion.String()
+	}
+	res := &generatorResult{
+		policy: spec.RotationPolicy,
+		status: esv1beta1.ExternalSecretGeneratorStatus{
+			Source: source,
+			Kind:   ref.Kind,
+			Name:   ref.Name,
+			Hash:   utils.ObjectHash(string(resource.Spec) + string(rawEntry)),
+		},
+		run:      generatorRunKey{apiVersion: apiVersion, kind: ref.Kind, name: ref.Name},
+		kind:     kind,
+		resource: raw,
+	}
+	prior := generatorStatus(externalSecret, source)
+	res.recorded = prior != nil
+	if data, ok := keepGeneratorData(existingSecret, res, prior, keys); ok {
+		now := time.Now()
+		expiresAt := prior.ExpiresAt
+		if expiresBeforeRefresh(externalSecret, expiresAt, now) {
+			expiresAt = r.renewGeneratorData(ctx, externalSecret, res, renewed)
+			ok = expiresAt != nil && !expiresBeforeRefresh(externalSecret, expiresAt, now)
+		}
+		if ok {
+			res.keep = data
+			res.keepUntil = expiresAt
+		}
+	}
+	return res, nil
+}
+
+// renewGeneratorData renews the current generator state of the entry res and returns
+// the new expiry of its data, or nil if the generator can't renew it.
+func (r *Reconciler) renewGeneratorData(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, res *generatorResult, renewed map[string]*metav1.Time) *metav1.Time {
+	gen, ok := genv1alpha1.GetGenerator(res.kind)
+	if !ok {
+		return nil
+	}
+	renewing, ok := gen.(genv1alpha1.RenewingGenerator)
+	if !ok {
+		return nil
+	}
+	genState, err := r.currentGeneratorState(ctx, externalSecret, res.status.Source)
+	if err != nil || genState == nil {
+		if err != nil {
+			r.Log.Error(err, "could not get generator state", "ExternalSecret", client.ObjectKeyFromObject(externalSecret), "source", res.status.Source)
+		}
+		return nil
+	}
+	if expiresAt, ok := renewed[genState.Name]; ok {
+		return expiresAt
+	}
+	expiry, err := renewing.Renew(ctx, &apiextensions.JSON{Raw: res.resource}, genState.Spec.State, r.Client, externalSecret.Namespace)
+	if err != nil {
+		r.Log.Error(err, "could not renew generator data", "ExternalSecret", client.ObjectKeyFromObject(externalSecret), "source", res.status.Source)
+		r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, fmt.Sprintf(errRenewGenerator, res.status.Kind, res.status.Name, err))
+		renewed[genState.Name] = nil
+		return nil
+	}
+	expiresAt := metav1.NewTime(expiry)
+	renewed[genState.Name] = &expiresAt
+	return &expiresAt
+}
+
+// getGeneratorData sets the data of the prepared generator entry res. The entry keeps the data
+// of the existing target secret if possible, otherwise it gets the data of the generator,
+// which is called once per reconcile. The state of stateful generators is added to states.
+func (r *Reconciler) getGeneratorData(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, res *generatorResult, states *generatorStates) error {
+	source := res.status.Source
+	if res.keep != nil {
+		res.data = res.keep
+		res.kept = true
+		res.status.ExpiresAt = res.keepUntil
+		states.keep(source)
+		return nil
+	}
+	run, ok := states.runs[res.run]
+	if !ok {
+		var err error
+		run, err = r.runGenerator(ctx, externalSecret, res, states)
+		if err != nil {
+			return err
+		}
+		states.runs[res.run] = run
+	}
+	if run.state != nil {
+		run.state.sources = append(run.state.sources, source)
+	}
+	// entries rewrite their data, so every entry gets its own copy.
+	res.data = make(map[string][]byte, len(run.data))
+	for key, value := range run.data {
+		res.data[key] = value
+	}
+	res.status.ExpiresAt = run.expiresAt
+	return nil
+}
+
+// runGenerator calls the generator of the entry res.
+func (r *Reconciler) runGenerator(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, res *generatorResult, states *generatorStates) (*generatorRun, error) {
+	gen, ok := genv1alpha1.GetGenerator(res.kind)
+	if !ok {
+		return nil, fmt.Errorf(errGeneratorNotRegistered, res.kind)
+	}
+	run := &generatorRun{}
+	var err error
+	if stateful, ok := gen.(genv1alpha1.StatefulGenerator); ok {
+		var state *apiextensions.JSON
+		run.data, state, err = stateful.GenerateWithState(ctx, &apiextensions.JSON{Raw: res.resource}, r.Client, externalSecret.Namespace)
+		run.state = states.add(res.resource, state)
+	} else {
+		run.data, err = gen.Generate(ctx, &apiextensions.JSON{Raw: res.resource}, r.Client, externalSecret.Namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errGenerate, res.status.Kind, res.status.Name, err)
+	}
+	if expiring, ok := gen.(genv1alpha1.ExpiringGenerator); ok {
+		if expiry, ok := expiring.Expiry(run.data); ok {
+			expiresAt := metav1.NewTime(expiry)
+			run.expiresAt = &expiresAt
+		}
+	}
+	return run, nil
+}
+
+// getGeneratorResource returns the generator kind and the JSON of the generator resource of ref.
+// A ClusterGenerator is resolved to a resource of the kind it wraps, if the namespace may use it.
+func (r *Reconciler) getGeneratorResource(ctx context.Context, namespace string, ref *esv1beta1.GeneratorRef) (string, []byte, error) {
+	if ref.Kind == genv1alpha1.ClusterGeneratorKind {
+		return r.getClusterGeneratorResource(ctx, namespace, ref.Name)
+	}
+	apiVersion := ref.APIVersion
+	if apiVersion == "" {
+		apiVersion = genv1alpha1.SchemeGroupVersion.String()
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(ref.Kind)
+	err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, obj)
+	if err != nil {
+		return "", nil, fmt.Errorf(errGetGenerator, ref.Kind, ref.Name, err)
+	}
+	raw, err := obj.MarshalJSON()
+	if err != nil {
+		return "", nil, fmt.Errorf(errGetGenerator, ref.Kind, ref.Name, err)
+	}
+	return ref.Kind, raw, nil
+}
+
+// getClusterGeneratorResource returns the kind and the JSON of the generator resource
+// wrapped by the ClusterGenerator name. The resource is named like the ClusterGenerator.
+func (r *Reconciler) getClusterGeneratorResource(ctx context.Context, namespace, name string) (string, []byte, error) {
+	var clusterGen genv1alpha1.ClusterGenerator
+	err := r.Get(ctx, types.NamespacedName{Name: name}, &clusterGen)
+	if err != nil {
+		return "", nil, fmt.Errorf(errGetGenerator, genv1alpha1.ClusterGeneratorKind, name, err)
+	}
+	allowed, err := secretstore.NamespaceMatchesConditions(ctx, r.Client, clusterGen.Spec.Conditions, namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	if !allowed {
+		return "", nil, fmt.Errorf(errClusterGeneratorDenied, name, namespace)
+	}
+	kind := clusterGen.Spec.Kind
+	spec := clusterGen.Spec.Generator.ForKind(kind)
+	if spec == nil {
+		return "", nil, fmt.Errorf(errClusterGeneratorSpec, name, kind)
+	}
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": genv1alpha1.SchemeGroupVersion.String(),
+		"kind":       string(kind),
+		"metadata": map[string]interface{}{
+			"name": clusterGen.Name,
+		},
+		"spec": spec,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf(errGetGenerator, genv1alpha1.ClusterGeneratorKind, name, err)
+	}
+	return string(kind), raw, nil
+}
+
+// getGeneratedValue sets the data of the prepared generator entry res of secretRef to the value
+// of its remote key as data of its secretKey.
+func (r *Reconciler) getGeneratedValue(ctx context.Context, externalSecret *esv1beta1.ExternalSecret, res *generatorResult, secretRef *esv1beta1.ExternalSecretData, states *generatorStates) error {
+	key, secretKey := secretRef.RemoteRef.Key, secretRef.SecretKey
+	if err := r.getGeneratorData(ctx, externalSecret, res, states); err != nil || res.kept {
+		return err
+	}
+	value, ok := res.data[key]
+	if !ok {
+		return fmt.Errorf(errMissingGeneratedKey, res.status.Kind, res.status.Name, key)
+	}
+	res.data = map[string][]byte{secretKey: value}
+	return nil
+}
+
+// selectGeneratedKeys returns the keys of data selected by ref, or data if ref selects no keys.
+func selectGeneratedKeys(ref *esv1beta1.GeneratorRef, data map[string][]byte) (map[string][]byte, error) {
+	if len(ref.Keys) == 0 {
+		return data, nil
+	}
+	selected := make(map[string][]byte, len(ref.Keys))
+	for _, key := range ref.Keys {
+		value, ok := data[key]
+		if !ok {
+			return nil, fmt.Errorf(errMissingGeneratedKey, ref.Kind, ref.Name, key)
+		}
+		selected[key] = value
+	}
+	return selected, nil
+}
+
+// generatorStatus returns the generator status of the entry source recorded by the last sync.
+func generatorStatus(externalSecret *esv1beta1.ExternalSecret, source string) *esv1beta1.ExternalSecretGeneratorStatus {
+	for i := range externalSecret.Status.Generators {
+		if externalSecret.Status.Generators[i].Source == source {
+			return &externalSecret.Status.Generators[i]
+		}
+	}
+	return nil
+}
+
+// keepGeneratorData returns the data of the existing target secret for the keys recorded by prior,
+// if the generator must not rotate its data, the generator and the entry are unchanged and all keys still exist.
+// The caller checks the expiry of the data.
+func keepGeneratorData(existingSecret *v1.Secret, res *generatorResult, prior *esv1beta1.ExternalSecretGeneratorStatus, keys []string) (map[string][]byte, bool) {
+	if res.policy != genv1alpha1.RotationPolicyOnlyWhenMissing || existingSecret.UID == "" || prior == nil {
+		return nil, false
+	}
+	if prior.Kind != res.status.Kind || prior.Name != res.status.Name || prior.Hash != res.status.Hash || len(prior.Keys) == 0 {
+		return nil, false
+	}
+	if keys != nil && !reflect.DeepEqual(keys, prior.Keys) {
+		return nil, false
+	}
+	kept := make(map[string][]byte, len(prior.Keys))
+	for _, key := range prior.Keys {
+		value, ok := existingSecret.Data[key]
+		if !ok {
+			return nil, false
+		}
+		kept[key] = value
+	}
+	return kept, true
+}
+
+// expiresBeforeRefresh reports whether data which expires at expiresAt expires before the next
+// refresh of externalSecret. Data without an expiry never expires.
+func expiresBeforeRefresh(externalSecret *esv1beta1.ExternalSecret, expiresAt *metav1.Time, now time.Time) bool {
+	if expiresAt == nil {
+		return false
+	}
+	var refreshInterval time.Duration
+	if externalSecret.Spec.RefreshInterval != nil {
+		refreshInterval = externalSecret.Spec.RefreshInterval.Duration
+	}
+	return !now.Add(refreshInterval).Before(expiresAt.Time)
+}
+
+// recordGeneratorData records the keys of data, the final data of the generator entry res,
+// in the generator status of the entry and returns data.
+// Entries without a status of the last sync, e.g. synced by an earlier version, keep the data
+// of the existing target secret if their generator must not rotate its data.
+func recordGeneratorData(res *generatorResult, existingSecret *v1.Secret, data map[string][]byte, states *generatorStates) map[string][]byte {
+	if !res.kept && !res.recorded {
+		var kept bool
+		data, kept = keepExistingData(res.policy, existingSecret, data)
+		if kept {
+			states.keep(res.status.Source)
+			// the kept data may be older than the expiry of the new data.
+			res.status.ExpiresAt = nil
+		}
+	}
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	res.status.Keys = keys
+	states.record(res.status)
+	return data
+}
+
+// keepExistingData returns the data of the existing target secret for the keys of data
+// if the generator must not rotate its data and all keys exist in the target secret.
+// Data like key pairs belongs together, so otherwise data is returned unchanged.
+// It reports whether the existing data was kept.
+func keepExistingData(policy genv1alpha1.RotationPolicy, existingSecret *v1.Secret, data map[string][]byte) (map[string][]byte, bool) {
+	if policy != genv1alpha1.RotationPolicyOnlyWhenMissing || existingSecret.UID == "" {
+		return data, false
+	}
+	kept := make(map[string][]byte, len(data))
+	for key := range data {
+		value, ok := existingSecret.Data[key]
+		if !ok {
+			return data, false
+		}
+		kept[key] = value
+	}
+	return kept, true
+}
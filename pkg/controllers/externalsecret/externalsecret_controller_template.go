@@ -31,7 +31,7 @@ import (
 )
 
 // merge template in the following order:
-// * template.Data (highest precedence)
+// * template.Data and template.Metadata (highest precedence)
 // * template.templateFrom
 // * secret via es.data or es.dataFrom.
 func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSecret, secret *v1.Secret, dataMap map[string][]byte) error {
@@ -52,7 +52,17 @@ func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSe
 
 	// explicitly defined template.Data takes precedence over templateFrom
 	for k, v := range es.Spec.Target.Template.Data {
-		tplMap[k] = []byte(v)
+		tplMap[esv1beta1.TemplateTargetData][k] = []byte(v)
+	}
+	// template.metadata is rendered into the annotations and labels with the v2 engine,
+	// the v1 engine only renders data and keeps the metadata as it is.
+	if es.Spec.Target.Template.EngineVersion == esv1beta1.TemplateEngineV2 {
+		for k, v := range es.Spec.Target.Template.Metadata.Annotations {
+			tplMap[esv1beta1.TemplateTargetAnnotations][k] = []byte(v)
+		}
+		for k, v := range es.Spec.Target.Template.Metadata.Labels {
+			tplMap[esv1beta1.TemplateTargetLabels][k] = []byte(v)
+		}
 	}
 	r.Log.V(1).Info("found template data", "tpl_data", tplMap)
 
@@ -60,21 +70,57 @@ func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSe
 	if err != nil {
 		return err
 	}
-	err = execute(tplMap, dataMap, secret)
-	if err != nil {
-		return fmt.Errorf(errExecTpl, err)
+	for _, target := range []esv1beta1.TemplateTarget{esv1beta1.TemplateTargetData, esv1beta1.TemplateTargetAnnotations, esv1beta1.TemplateTargetLabels} {
+		if len(tplMap[target]) == 0 {
+			continue
+		}
+		err = execute(tplMap[target], dataMap, target, secret)
+		if err != nil {
+			return fmt.Errorf(errExecTpl, err)
+		}
 	}
 
 	// if no data was provided by template fallback
 	// to value from the provider
-	if len(es.Spec.Target.Template.Data) == 0 && len(es.Spec.Target.Template.TemplateFrom) == 0 {
+	if len(tplMap[esv1beta1.TemplateTargetData]) == 0 {
 		secret.Data = dataMap
 	}
+	err = validateSecretType(secret)
+	if err != nil {
+		return err
+	}
 	secret.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
 
 	return nil
 }
 
+// requiredKeys are the keys a secret of the given type must contain.
+var requiredKeys = map[v1.SecretType][]string{
+	v1.SecretTypeDockercfg:        {v1.DockerConfigKey},
+	v1.SecretTypeDockerConfigJson: {v1.DockerConfigJsonKey},
+	v1.SecretTypeSSHAuth:          {v1.SSHAuthPrivateKey},
+	v1.SecretTypeTLS:              {v1.TLSCertKey, v1.TLSPrivateKeyKey},
+}
+
+// validateSecretType checks that the data of the secret contains the keys
+// required by its type, otherwise the api server rejects the secret.
+func validateSecretType(secret *v1.Secret) error {
+	if secret.Type == v1.SecretTypeBasicAuth {
+		_, hasUsername := secret.Data[v1.BasicAuthUsernameKey]
+		_, hasPassword := secret.Data[v1.BasicAuthPasswordKey]
+		if !hasUsername && !hasPassword {
+			return fmt.Errorf(errMissingBasicAuthKey, secret.Type, v1.BasicAuthUsernameKey, v1.BasicAuthPasswordKey)
+		}
+		return nil
+	}
+	for _, key := range requiredKeys[secret.Type] {
+		if _, ok := secret.Data[key]; !ok {
+			return fmt.Errorf(errMissingTypeKey, secret.Type, key)
+		}
+	}
+	return nil
+}
+
 // we do not want to force-override the label/annotations
 // and only copy the necessary key/value pairs.
 func mergeMetadata(secret *v1.Secret, externalSecret *esv1beta1.ExternalSecret) {
@@ -95,17 +141,30 @@ func mergeMetadata(secret *v1.Secret, externalSecret *esv1beta1.ExternalSecret)
 	utils.MergeStringMap(secret.ObjectMeta.Annotations, externalSecret.Spec.Target.Template.Metadata.Annotations)
 }
 
-func (r *Reconciler) getTemplateData(ctx context.Context, externalSecret *esv1beta1.ExternalSecret) (map[string][]byte, error) {
-	out := make(map[string][]byte)
+// getTemplateData returns the templates defined in template.templateFrom by target.
+func (r *Reconciler) getTemplateData(ctx context.Context, externalSecret *esv1beta1.ExternalSecret) (map[esv1beta1.TemplateTarget]map[string][]byte, error) {
+	out := map[esv1beta1.TemplateTarget]map[string][]byte{
+		esv1beta1.TemplateTargetData:        make(map[string][]byte),
+		esv1beta1.TemplateTargetAnnotations: make(map[string][]byte),
+		esv1beta1.TemplateTargetLabels:      make(map[string][]byte),
+	}
 	if externalSecret.Spec.Target.Template == nil {
 		return out, nil
 	}
 	for _, tpl := range externalSecret.Spec.Target.Template.TemplateFrom {
-		err := mergeConfigMap(ctx, r.Client, externalSecret, tpl, out)
+		target := tpl.Target
+		if target == "" {
+			target = esv1beta1.TemplateTargetData
+		}
+		tplMap, ok := out[target]
+		if !ok {
+			return nil, fmt.Errorf(errUnknownTplTarget, target)
+		}
+		err := mergeConfigMap(ctx, r.Client, externalSecret, tpl, tplMap)
 		if err != nil {
 			return nil, err
 		}
-		err = mergeSecret(ctx, r.Client, externalSecret, tpl, out)
+		err = mergeSecret(ctx, r.Client, externalSecret, tpl, tplMap)
 		if err != nil {
 			return nil, err
 		}
@@ -124,7 +183,7 @@ func mergeConfigMap(ctx context.Context, k8sClient client.Client, es *esv1beta1.
 		Namespace: es.Namespace,
 	}, &cm)
 	if err != nil {
-		return err
+		return fmt.Errorf(errTplCMGet, tpl.ConfigMap.Name, err)
 	}
 	for _, k := range tpl.ConfigMap.Items {
 		val, ok := cm.Data[k.Key]
@@ -146,7 +205,7 @@ func mergeSecret(ctx context.Context, k8sClient client.Client, es *esv1beta1.Ext
 		Namespace: es.Namespace,
 	}, &sec)
 	if err != nil {
-		return err
+		return fmt.Errorf(errTplSecGet, tpl.Secret.Name, err)
 	}
 	for _, k := range tpl.Secret.Items {
 		val, ok := sec.Data[k.Key]
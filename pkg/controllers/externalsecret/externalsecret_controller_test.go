@@ -16,22 +16,32 @@ package externalsecret
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"golang.org/x/crypto/ssh"
 	v1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
 	ctest "github.com/external-secrets/external-secrets/pkg/controllers/commontest"
+	genfake "github.com/external-secrets/external-secrets/pkg/generator/fake"
+	genssh "github.com/external-secrets/external-secrets/pkg/generator/ssh"
 	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 )
 
@@ -42,6 +52,66 @@ var (
 	interval     = time.Millisecond * 250
 )
 
+// statefulFakeGenerator returns the data of the Fake generator together with a state.
+type statefulFakeGenerator struct {
+	genfake.Generator
+}
+
+func (g *statefulFakeGenerator) GenerateWithState(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, *apiextensions.JSON, error) {
+	data, err := g.Generate(ctx, obj, kube, namespace)
+	return data, &apiextensions.JSON{Raw: []byte(`{"id":"fake"}`)}, err
+}
+
+func (g *statefulFakeGenerator) Cleanup(ctx context.Context, obj, state *apiextensions.JSON, kube client.Client, namespace string) error {
+	return nil
+}
+
+// expiringGenerator counts the calls of the wrapped generator
+// and reports that its data expires after expiry.
+type expiringGenerator struct {
+	genv1alpha1.Generator
+	calls  int32
+	expiry time.Duration
+}
+
+func (g *expiringGenerator) Generate(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	atomic.AddInt32(&g.calls, 1)
+	return g.Generator.Generate(ctx, obj, kube, namespace)
+}
+
+func (g *expiringGenerator) Expiry(data map[string][]byte) (time.Time, bool) {
+	return time.Now().Add(g.expiry), g.expiry > 0
+}
+
+func (g *expiringGenerator) Calls() int32 {
+	return atomic.LoadInt32(&g.calls)
+}
+
+// renewingGenerator is an expiringGenerator with a state,
+// renewing its data extends the lifetime by an hour.
+type renewingGenerator struct {
+	expiringGenerator
+	renewals int32
+}
+
+func (g *renewingGenerator) GenerateWithState(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, *apiextensions.JSON, error) {
+	data, err := g.Generate(ctx, obj, kube, namespace)
+	return data, &apiextensions.JSON{Raw: []byte(`{"id":"renewing"}`)}, err
+}
+
+func (g *renewingGenerator) Cleanup(ctx context.Context, obj, state *apiextensions.JSON, kube client.Client, namespace string) error {
+	return nil
+}
+
+func (g *renewingGenerator) Renew(ctx context.Context, obj, state *apiextensions.JSON, kube client.Client, namespace string) (time.Time, error) {
+	atomic.AddInt32(&g.renewals, 1)
+	return time.Now().Add(time.Hour), nil
+}
+
+func (g *renewingGenerator) Renewals() int32 {
+	return atomic.LoadInt32(&g.renewals)
+}
+
 type testCase struct {
 	secretStore    *esv1beta1.SecretStore
 	externalSecret *esv1beta1.ExternalSecret
@@ -127,7 +197,92 @@ var _ = Describe("Kind=secret existence logic", func() {
 			Expect(isSecretValid(tt.Input)).To(BeEquivalentTo(tt.ExpectedOutput))
 		})
 	}
+
+	It("A merged secret should only be validated against the managed keys", func() {
+		secret := v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				UID: "xxx",
+				Annotations: map[string]string{
+					esv1beta1.AnnotationDataHash: "caa0155759a6a9b3b6ada5a6883ee2bb",
+				},
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{
+						Manager:  fieldOwner,
+						FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:foo":{},"f:bar":{}}}`)},
+					},
+					{
+						Manager:  "other-manager",
+						FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:baz":{}}}`)},
+					},
+				},
+			},
+			Data: map[string][]byte{
+				"foo": []byte("value1"),
+				"bar": []byte("value2"),
+				"baz": []byte("value3"),
+			},
+		}
+		es := esv1beta1.ExternalSecret{}
+		Expect(isSecretValid(managedSecret(es, secret))).To(BeFalse())
+		es.Spec.Target.CreationPolicy = esv1beta1.CreatePolicyMerge
+		Expect(isSecretValid(managedSecret(es, secret))).To(BeTrue())
+		Expect(secret.Data).To(HaveLen(3))
+	})
 })
+var _ = Describe("Kind=secret type validation", func() {
+	type testCase struct {
+		Name        string
+		Type        v1.SecretType
+		Data        map[string][]byte
+		ExpectedErr string
+	}
+	tests := []testCase{
+		{
+			Name: "An opaque secret should be valid",
+			Type: v1.SecretTypeOpaque,
+		},
+		{
+			Name: "A tls secret with certificate and key should be valid",
+			Type: v1.SecretTypeTLS,
+			Data: map[string][]byte{v1.TLSCertKey: []byte("crt"), v1.TLSPrivateKeyKey: []byte("key")},
+		},
+		{
+			Name:        "A tls secret without key should not be valid",
+			Type:        v1.SecretTypeTLS,
+			Data:        map[string][]byte{v1.TLSCertKey: []byte("crt")},
+			ExpectedErr: "secret of type kubernetes.io/tls must contain key tls.key",
+		},
+		{
+			Name:        "A dockerconfigjson secret without config should not be valid",
+			Type:        v1.SecretTypeDockerConfigJson,
+			Data:        map[string][]byte{"config.json": []byte("{}")},
+			ExpectedErr: "secret of type kubernetes.io/dockerconfigjson must contain key .dockerconfigjson",
+		},
+		{
+			Name: "A basic-auth secret with only a password should be valid",
+			Type: v1.SecretTypeBasicAuth,
+			Data: map[string][]byte{v1.BasicAuthPasswordKey: []byte("pass")},
+		},
+		{
+			Name:        "A basic-auth secret without username and password should not be valid",
+			Type:        v1.SecretTypeBasicAuth,
+			ExpectedErr: "secret of type kubernetes.io/basic-auth must contain key username or password",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		It(tt.Name, func() {
+			err := validateSecretType(&v1.Secret{Type: tt.Type, Data: tt.Data})
+			if tt.ExpectedErr == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(tt.ExpectedErr))
+			}
+		})
+	}
+})
+
 var _ = Describe("ExternalSecret controller", func() {
 	const (
 		ExternalSecretName             = "test-es"
@@ -239,6 +394,453 @@ var _ = Describe("ExternalSecret controller", func() {
 			Expect(secret.ObjectMeta.Name).To(Equal(ExternalSecretName))
 		}
 	}
+	// a templated target Secret name is rendered with the metadata of the ExternalSecret.
+	syncWithTemplatedTargetName := func(tc *testCase) {
+		tc.externalSecret.ObjectMeta.Labels = map[string]string{"app": "test"}
+		tc.externalSecret.Spec.Target.Name = "{{ .labels.app }}-secret"
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(secret.ObjectMeta.Name).To(Equal(ExternalSecretTargetSecretName))
+			Expect(es.Status.TargetSecret.Name).To(Equal(ExternalSecretTargetSecretName))
+		}
+	}
+
+	// dataFrom with a Password generator should sync a random password.
+	syncWithPasswordGenerator := func(tc *testCase) {
+		symbols := 0
+		gen := &genv1alpha1.Password{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "password",
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.PasswordSpec{
+				Length:  16,
+				Symbols: &symbols,
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gen)).To(Succeed())
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.PasswordKind,
+						Name: gen.Name,
+					},
+				},
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(secret.Data["password"]).To(HaveLen(16))
+		}
+	}
+
+	// dataFrom with a Fake generator must apply rewrite
+	// and template to the static generator data.
+	syncWithFakeGenerator := func(tc *testCase) {
+		gen := &genv1alpha1.Fake{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake",
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.FakeSpec{
+				Data: map[string]string{
+					"db-user":     "admin",
+					"db-password": "s3cr3t",
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gen)).To(Succeed())
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.FakeKind,
+						Name: gen.Name,
+					},
+				},
+				Rewrite: []esv1beta1.ExternalSecretRewrite{
+					{
+						Regexp: &esv1beta1.ExternalSecretRewriteRegexp{
+							Source: "db-(.*)",
+							Target: "$1",
+						},
+					},
+				},
+			},
+		}
+		tc.externalSecret.Spec.Target.Template = &esv1beta1.ExternalSecretTemplate{
+			Data: map[string]string{
+				"dsn": "postgres://{{ .user }}:{{ .password }}@db",
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data["dsn"])).To(Equal("postgres://admin:s3cr3t@db"))
+		}
+	}
+
+	// generatorRef.keys selects the generated keys of a dataFrom entry before the rewrite.
+	selectGeneratedKeys := func(tc *testCase) {
+		gen := &genv1alpha1.Fake{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-keys",
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.FakeSpec{
+				Data: map[string]string{
+					"db-user":     "admin",
+					"db-password": "s3cr3t",
+					"db-host":     "db.internal",
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gen)).To(Succeed())
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.FakeKind,
+						Name: gen.Name,
+						Keys: []string{"db-user", "db-password"},
+					},
+				},
+				Rewrite: []esv1beta1.ExternalSecretRewrite{
+					{
+						Regexp: &esv1beta1.ExternalSecretRewriteRegexp{
+							Source: "db-(.*)",
+							Target: "DB_$1",
+						},
+					},
+					{
+						Transform: &esv1beta1.ExternalSecretRewriteTransform{
+							Template: "{{ .value | upper }}",
+						},
+					},
+				},
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(secret.Data).To(Equal(map[string][]byte{
+				"DB_USER":     []byte("admin"),
+				"DB_PASSWORD": []byte("s3cr3t"),
+			}))
+		}
+	}
+
+	// selecting a key the generator doesn't generate must fail the sync.
+	selectMissingGeneratedKey := func(tc *testCase) {
+		gen := &genv1alpha1.Fake{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fake-missing-key",
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.FakeSpec{
+				Data: map[string]string{"token": FooValue},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gen)).To(Succeed())
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.FakeKind,
+						Name: gen.Name,
+						Keys: []string{"password"},
+					},
+				},
+			},
+		}
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			return cond != nil && cond.Status == v1.ConditionFalse && cond.Reason == esv1beta1.ConditionReasonSecretSyncedError &&
+				strings.Contains(cond.Message, "did not generate key password")
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {}
+	}
+
+	// a ClusterGenerator can be used from the namespaces allowed by its conditions.
+	syncWithClusterGenerator := func(tc *testCase, namespaces []string) {
+		gen := &genv1alpha1.ClusterGenerator{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-fake-" + ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.ClusterGeneratorSpec{
+				Kind: genv1alpha1.GeneratorKind(genv1alpha1.FakeKind),
+				Generator: genv1alpha1.GeneratorSpec{
+					FakeSpec: &genv1alpha1.FakeSpec{
+						Data: map[string]string{"token": FooValue},
+					},
+				},
+				Conditions: []esv1beta1.ClusterSecretStoreCondition{
+					{Namespaces: namespaces},
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gen)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(context.Background(), gen)).To(Succeed())
+		})
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.ClusterGeneratorKind,
+						Name: gen.Name,
+					},
+				},
+			},
+		}
+		tc.externalSecret.Spec.Data = nil
+	}
+	syncWithAllowedClusterGenerator := func(tc *testCase) {
+		syncWithClusterGenerator(tc, []string{ExternalSecretNamespace})
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data["token"])).To(Equal(FooValue))
+		}
+	}
+	denyClusterGeneratorNamespace := func(tc *testCase) {
+		syncWithClusterGenerator(tc, []string{"other-namespace"})
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			return cond != nil && cond.Status == v1.ConditionFalse && cond.Reason == esv1beta1.ConditionReasonSecretSyncedError &&
+				strings.Contains(cond.Message, "denied by spec.conditions")
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {}
+	}
+
+	// the states of stateful generators must be recorded in GeneratorStates,
+	// prior states are superseded on refresh.
+	recordGeneratorStates := func(tc *testCase) {
+		genv1alpha1.ForceRegister(genv1alpha1.FakeKind, &statefulFakeGenerator{})
+		DeferCleanup(func() {
+			genv1alpha1.ForceRegister(genv1alpha1.FakeKind, &genfake.Generator{})
+		})
+		gen := &genv1alpha1.Fake{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "stateful-fake",
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.FakeSpec{
+				Data: map[string]string{"token": FooValue},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gen)).To(Succeed())
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.FakeKind,
+						Name: gen.Name,
+					},
+				},
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data["token"])).To(Equal(FooValue))
+			Eventually(func() bool {
+				var states genv1alpha1.GeneratorStateList
+				err := k8sClient.List(context.Background(), &states, client.InNamespace(es.Namespace), client.MatchingLabels{
+					genv1alpha1.GeneratorStateLabelOwner: generatorStateOwner(es),
+				})
+				if err != nil || len(states.Items) < 2 {
+					return false
+				}
+				var active int
+				for _, state := range states.Items {
+					Expect(state.Annotations).To(HaveKeyWithValue(genv1alpha1.GeneratorStateAnnotationSource, "dataFrom[0]"))
+					Expect(state.Finalizers).To(ContainElement(genv1alpha1.GeneratorStateFinalizer))
+					Expect(ctest.HasOwnerRef(state.ObjectMeta, "ExternalSecret", ExternalSecretName)).To(BeTrue())
+					if state.Spec.GarbageCollectionDeadline == nil {
+						active++
+					}
+				}
+				return active == 1
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
+	// a generator with rotationPolicy=OnlyWhenMissing must keep
+	// its data of the target secret on refresh.
+	keepGeneratedSSHKey := func(tc *testCase) {
+		gen := &genv1alpha1.SSHKey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ssh-key",
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.SSHKeySpec{
+				RotationPolicy: genv1alpha1.RotationPolicyOnlyWhenMissing,
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), gen)).To(Succeed())
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.SSHKeyKind,
+						Name: gen.Name,
+					},
+				},
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			privateKey := secret.Data["private_key"]
+			Expect(privateKey).ToNot(BeEmpty())
+			Expect(secret.Data["public_key"]).ToNot(BeEmpty())
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			Consistently(func() []byte {
+				sec := &v1.Secret{}
+				Expect(k8sClient.Get(context.Background(), secretLookupKey, sec)).To(Succeed())
+				return sec.Data["private_key"]
+			}, time.Second*3, interval).Should(Equal(privateKey))
+		}
+	}
+
+	// generatorSSHKey registers gen for SSHKey generators and references
+	// a SSHKey generator with rotationPolicy=OnlyWhenMissing from data[0].
+	generatorSSHKey := func(tc *testCase, gen genv1alpha1.Generator) {
+		genv1alpha1.ForceRegister(genv1alpha1.SSHKeyKind, gen)
+		DeferCleanup(func() {
+			genv1alpha1.ForceRegister(genv1alpha1.SSHKeyKind, &genssh.Generator{})
+		})
+		sshKey := &genv1alpha1.SSHKey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "counted-ssh-key",
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.SSHKeySpec{
+				RotationPolicy: genv1alpha1.RotationPolicyOnlyWhenMissing,
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), sshKey)).To(Succeed())
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		tc.externalSecret.Spec.DataFrom = nil
+		tc.externalSecret.Spec.Data = []esv1beta1.ExternalSecretData{
+			{
+				SecretKey: "key",
+				RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: "private_key"},
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						Kind: genv1alpha1.SSHKeyKind,
+						Name: sshKey.Name,
+					},
+				},
+			},
+		}
+	}
+	// a generator with rotationPolicy=OnlyWhenMissing must not be called
+	// while the target secret holds its data.
+	skipGeneratorWhileKept := func(tc *testCase) {
+		gen := &expiringGenerator{Generator: &genssh.Generator{}}
+		generatorSSHKey(tc, gen)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(secret.Data["key"]).ToNot(BeEmpty())
+			esKey := types.NamespacedName{Name: ExternalSecretName, Namespace: ExternalSecretNamespace}
+			Eventually(func() []esv1beta1.ExternalSecretGeneratorStatus {
+				var current esv1beta1.ExternalSecret
+				Expect(k8sClient.Get(context.Background(), esKey, &current)).To(Succeed())
+				return current.Status.Generators
+			}, timeout, interval).Should(ContainElement(And(
+				HaveField("Source", "data[0]"),
+				HaveField("Kind", genv1alpha1.SSHKeyKind),
+				HaveField("Keys", []string{"key"}),
+			)))
+			Consistently(gen.Calls, time.Second*3, interval).Should(Equal(int32(1)))
+		}
+	}
+	// data of a generator with rotationPolicy=OnlyWhenMissing must be
+	// generated again if it expires before the next refresh.
+	regenerateExpiringData := func(tc *testCase) {
+		gen := &expiringGenerator{Generator: &genssh.Generator{}, expiry: time.Millisecond * 1500}
+		generatorSSHKey(tc, gen)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			privateKey := secret.Data["key"]
+			Expect(privateKey).ToNot(BeEmpty())
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			Eventually(func() []byte {
+				sec := &v1.Secret{}
+				Expect(k8sClient.Get(context.Background(), secretLookupKey, sec)).To(Succeed())
+				return sec.Data["key"]
+			}, timeout, interval).ShouldNot(Equal(privateKey))
+			Expect(gen.Calls()).To(BeNumerically(">=", 2))
+		}
+	}
+
+	// data of a generator with rotationPolicy=OnlyWhenMissing must be
+	// renewed instead of generated again if the generator supports it.
+	renewExpiringData := func(tc *testCase) {
+		gen := &renewingGenerator{expiringGenerator: expiringGenerator{Generator: &genssh.Generator{}, expiry: time.Millisecond * 1500}}
+		generatorSSHKey(tc, gen)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			privateKey := secret.Data["key"]
+			Expect(privateKey).ToNot(BeEmpty())
+			Eventually(gen.Renewals, timeout, interval).Should(Equal(int32(1)))
+			Consistently(gen.Renewals, time.Second*3, interval).Should(Equal(int32(1)))
+			Expect(gen.Calls()).To(Equal(int32(1)))
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			sec := &v1.Secret{}
+			Expect(k8sClient.Get(context.Background(), secretLookupKey, sec)).To(Succeed())
+			Expect(sec.Data["key"]).To(Equal(privateKey))
+		}
+	}
+
+	// entries which take keys from the same generator must get the data of a single run.
+	shareGeneratorRun := func(tc *testCase) {
+		gen := &expiringGenerator{Generator: &genssh.Generator{}}
+		genv1alpha1.ForceRegister(genv1alpha1.SSHKeyKind, gen)
+		DeferCleanup(func() {
+			genv1alpha1.ForceRegister(genv1alpha1.SSHKeyKind, &genssh.Generator{})
+		})
+		sshKey := &genv1alpha1.SSHKey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared-ssh-key",
+				Namespace: ExternalSecretNamespace,
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), sshKey)).To(Succeed())
+		sourceRef := &esv1beta1.SourceRef{
+			GeneratorRef: &esv1beta1.GeneratorRef{
+				Kind: genv1alpha1.SSHKeyKind,
+				Name: sshKey.Name,
+			},
+		}
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		tc.externalSecret.Spec.DataFrom = nil
+		tc.externalSecret.Spec.Data = []esv1beta1.ExternalSecretData{
+			{
+				SecretKey: "id_ed25519",
+				RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: "private_key"},
+				SourceRef: sourceRef,
+			},
+			{
+				SecretKey: "id_ed25519.pub",
+				RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: "public_key"},
+				SourceRef: sourceRef,
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			signer, err := ssh.ParsePrivateKey(secret.Data["id_ed25519"])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret.Data["id_ed25519.pub"]).To(Equal(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+			Expect(gen.Calls()).To(Equal(int32(1)))
+		}
+	}
+
 	// labels and annotations from the Kind=ExternalSecret
 	// should be copied over to the Kind=Secret
 	syncLabelsAnnotations := func(tc *testCase) {
@@ -274,6 +876,16 @@ var _ = Describe("ExternalSecret controller", func() {
 				Expect(syncCallsTotal.WithLabelValues(ExternalSecretName, ExternalSecretNamespace).Write(&metric)).To(Succeed())
 				return metric.GetCounter().GetValue() == 1.0
 			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				Expect(storeSyncCallsTotal.WithLabelValues(ExternalSecretNamespace, esv1beta1.SecretStoreKind, ExternalSecretStore).Write(&metric)).To(Succeed())
+				return metric.GetCounter().GetValue() == 1.0
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				observer, err := reconcileDuration.GetMetricWithLabelValues(ExternalSecretNamespace, esv1beta1.SecretStoreKind, ExternalSecretStore)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(observer.(prometheus.Metric).Write(&metric)).To(Succeed())
+				return metric.GetHistogram().GetSampleCount() >= 1
+			}, timeout, interval).Should(BeTrue())
 		}
 	}
 
@@ -313,12 +925,23 @@ var _ = Describe("ExternalSecret controller", func() {
 			Expect(ctest.HasFieldOwnership(
 				secret.ObjectMeta,
 				"external-secrets",
-				fmt.Sprintf("{\"f:data\":{\"f:targetProperty\":{}},\"f:immutable\":{},\"f:metadata\":{\"f:annotations\":{\"f:%s\":{}}}}", esv1beta1.AnnotationDataHash)),
+				fmt.Sprintf("{\"f:data\":{\"f:targetProperty\":{}},\"f:metadata\":{\"f:annotations\":{\"f:%s\":{}}}}", esv1beta1.AnnotationDataHash)),
 			).To(BeTrue())
 			Expect(ctest.HasFieldOwnership(secret.ObjectMeta, FakeManager, "{\"f:data\":{\".\":{},\"f:pre-existing-key\":{}},\"f:type\":{}}")).To(BeTrue())
 		}
 	}
 
+	// with creationPolicy=Merge the immutability of the secret is left to its owner.
+	mergeWithImmutable := func(tc *testCase) {
+		mergeWithSecret(tc)
+		tc.externalSecret.Spec.Target.Immutable = true
+		checkMerged := tc.checkSecret
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			checkMerged(es, secret)
+			Expect(secret.Immutable).To(BeNil())
+		}
+	}
+
 	// should not update if no changes
 	mergeWithSecretNoChange := func(tc *testCase) {
 		const existingKey = "pre-existing-key"
@@ -371,7 +994,8 @@ var _ = Describe("ExternalSecret controller", func() {
 			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretSyncedError {
 				return false
 			}
-			return true
+			// the failed update is retried with the error backoff.
+			return es.Status.FailedSyncs >= 1 && es.Status.NextRetryTime != nil
 		}
 		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
 			Eventually(func() bool {
@@ -410,7 +1034,7 @@ var _ = Describe("ExternalSecret controller", func() {
 			// check owner/managedFields
 			Expect(ctest.HasOwnerRef(secret.ObjectMeta, "ExternalSecret", ExternalSecretName)).To(BeFalse())
 			Expect(secret.ObjectMeta.ManagedFields).To(HaveLen(2))
-			Expect(ctest.HasFieldOwnership(secret.ObjectMeta, "external-secrets", "{\"f:data\":{\"f:targetProperty\":{}},\"f:immutable\":{},\"f:metadata\":{\"f:annotations\":{\"f:reconcile.external-secrets.io/data-hash\":{}}}}")).To(BeTrue())
+			Expect(ctest.HasFieldOwnership(secret.ObjectMeta, "external-secrets", "{\"f:data\":{\"f:targetProperty\":{}},\"f:metadata\":{\"f:annotations\":{\"f:reconcile.external-secrets.io/data-hash\":{}}}}")).To(BeTrue())
 		}
 	}
 
@@ -563,6 +1187,69 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// templateFrom can render templates into the annotations and labels of the secret
+	syncWithTemplateFromTargets := func(tc *testCase) {
+		const secretVal = "someValue"
+		const tplFromCMName = "template-cm-targets"
+		Expect(k8sClient.Create(context.Background(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tplFromCMName,
+				Namespace: ExternalSecretNamespace,
+			},
+			Data: map[string]string{
+				"owner-annotation": "annotation-{{ .targetProperty }}",
+				"owner-label":      "label-{{ .targetProperty }}",
+			},
+		})).To(Succeed())
+		tc.externalSecret.Spec.Target.Template = &esv1beta1.ExternalSecretTemplate{
+			EngineVersion: esv1beta1.TemplateEngineV2,
+			TemplateFrom: []esv1beta1.TemplateFrom{
+				{
+					ConfigMap: &esv1beta1.TemplateRef{
+						Name:  tplFromCMName,
+						Items: []esv1beta1.TemplateRefItem{{Key: "owner-annotation"}},
+					},
+					Target: esv1beta1.TemplateTargetAnnotations,
+				},
+				{
+					ConfigMap: &esv1beta1.TemplateRef{
+						Name:  tplFromCMName,
+						Items: []esv1beta1.TemplateRefItem{{Key: "owner-label"}},
+					},
+					Target: esv1beta1.TemplateTargetLabels,
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(secretVal), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			// data falls back to the provider values
+			Expect(string(secret.Data[targetProp])).To(Equal(secretVal))
+			Expect(secret.Data).ToNot(HaveKey("owner-annotation"))
+			Expect(secret.ObjectMeta.Annotations).To(HaveKeyWithValue("owner-annotation", "annotation-someValue"))
+			Expect(secret.ObjectMeta.Labels).To(HaveKeyWithValue("owner-label", "label-someValue"))
+		}
+	}
+
+	// template.metadata values are rendered into the labels and annotations
+	syncWithTemplateMetadata := func(tc *testCase) {
+		const secretVal = "someValue"
+		tc.externalSecret.Spec.Target.Template = &esv1beta1.ExternalSecretTemplate{
+			EngineVersion: esv1beta1.TemplateEngineV2,
+			Metadata: esv1beta1.ExternalSecretTemplateMetadata{
+				Labels:      map[string]string{"team": "payments", "owner": "{{ .targetProperty }}"},
+				Annotations: map[string]string{"reloader.stakater.com/match": "true", "checksum": "{{ .targetProperty | sha256sum }}"},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(secretVal), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(secretVal))
+			Expect(secret.ObjectMeta.Labels).To(HaveKeyWithValue("team", "payments"))
+			Expect(secret.ObjectMeta.Labels).To(HaveKeyWithValue("owner", secretVal))
+			Expect(secret.ObjectMeta.Annotations).To(HaveKeyWithValue("reloader.stakater.com/match", "true"))
+			Expect(secret.ObjectMeta.Annotations).To(HaveKeyWithValue("checksum", fmt.Sprintf("%x", sha256.Sum256([]byte(secretVal)))))
+		}
+	}
+
 	refreshWithTemplate := func(tc *testCase) {
 		const secretVal = "someValue"
 		const tplStaticKey = "tplstatickey"
@@ -749,30 +1436,101 @@ var _ = Describe("ExternalSecret controller", func() {
 				Name:      ExternalSecretTargetSecretName,
 				Namespace: ExternalSecretNamespace,
 			}
-			Eventually(func() bool {
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), secretLookupKey, sec)
+				if err != nil {
+					return false
+				}
+				return string(sec.Data["foo"]) == "1111" &&
+					sec.Data["bar"] == nil // must not be defined, it was deleted
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
+	// when a key was removed from the template it must be
+	// removed from the secret as well
+	refreshWithTemplateRemovedKey := func(tc *testCase) {
+		const secretVal = "someValue"
+		const tplStaticKey = "tplstatickey"
+		const tplStaticVal = "tplstaticvalue"
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		tc.externalSecret.Spec.Target.Template = &esv1beta1.ExternalSecretTemplate{
+			Data: map[string]string{
+				targetProp:   targetPropObj,
+				tplStaticKey: tplStaticVal,
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(secretVal), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			// check values
+			Expect(string(secret.Data[targetProp])).To(Equal(expectedSecretVal))
+			Expect(string(secret.Data[tplStaticKey])).To(Equal(tplStaticVal))
+
+			// now remove the static key from the template
+			cleanEs := tc.externalSecret.DeepCopy()
+			delete(tc.externalSecret.Spec.Target.Template.Data, tplStaticKey)
+			Expect(k8sClient.Patch(context.Background(), tc.externalSecret, client.MergeFrom(cleanEs))).To(Succeed())
+
+			sec := &v1.Secret{}
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), secretLookupKey, sec)
+				if err != nil {
+					return false
+				}
+				return string(sec.Data[targetProp]) == expectedSecretVal &&
+					sec.Data[tplStaticKey] == nil // must not be defined, it was removed from the template
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
+	refreshintervalZero := func(tc *testCase) {
+		const targetProp = "targetProperty"
+		const secretVal = "someValue"
+		fakeProvider.WithGetSecret([]byte(secretVal), nil)
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: 0}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			// check values
+			Expect(string(secret.Data[targetProp])).To(Equal(secretVal))
+
+			// update provider secret
+			newValue := "NEW VALUE"
+			sec := &v1.Secret{}
+			fakeProvider.WithGetSecret([]byte(newValue), nil)
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			Consistently(func() bool {
 				err := k8sClient.Get(context.Background(), secretLookupKey, sec)
 				if err != nil {
 					return false
 				}
-				return string(sec.Data["foo"]) == "1111" &&
-					sec.Data["bar"] == nil // must not be defined, it was deleted
-			}, timeout, interval).Should(BeTrue())
+				v := sec.Data[targetProp]
+				return string(v) == secretVal
+			}, time.Second*10, time.Second).Should(BeTrue())
 		}
 	}
 
-	refreshintervalZero := func(tc *testCase) {
+	// with refreshInterval=0 the target secret must not be synced again
+	// even if its data was changed, later provider values must not overwrite it.
+	refreshintervalZeroModifiedSecret := func(tc *testCase) {
 		const targetProp = "targetProperty"
 		const secretVal = "someValue"
 		fakeProvider.WithGetSecret([]byte(secretVal), nil)
 		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: 0}
 		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
-			// check values
 			Expect(string(secret.Data[targetProp])).To(Equal(secretVal))
 
-			// update provider secret
-			newValue := "NEW VALUE"
+			// rotate the provider secret and modify the target secret
+			fakeProvider.WithGetSecret([]byte("NEW VALUE"), nil)
+			secret.Data[targetProp] = []byte(FooValue)
+			Expect(k8sClient.Update(context.Background(), secret)).To(Succeed())
+
 			sec := &v1.Secret{}
-			fakeProvider.WithGetSecret([]byte(newValue), nil)
 			secretLookupKey := types.NamespacedName{
 				Name:      ExternalSecretTargetSecretName,
 				Namespace: ExternalSecretNamespace,
@@ -782,8 +1540,7 @@ var _ = Describe("ExternalSecret controller", func() {
 				if err != nil {
 					return false
 				}
-				v := sec.Data[targetProp]
-				return string(v) == secretVal
+				return string(sec.Data[targetProp]) == FooValue
 			}, time.Second*10, time.Second).Should(BeTrue())
 		}
 	}
@@ -966,6 +1723,28 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// with decodingStrategy the values are decoded
+	syncWithDecodingStrategy := func(tc *testCase) {
+		const secretVal = "someValue"
+		tc.externalSecret.Spec.Data[0].RemoteRef.DecodingStrategy = esv1beta1.ExternalSecretDecodeBase64
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{
+					Key:              remoteKey,
+					DecodingStrategy: esv1beta1.ExternalSecretDecodeAuto,
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(base64.StdEncoding.EncodeToString([]byte(secretVal))), nil)
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			"foo": []byte(base64.URLEncoding.EncodeToString([]byte(FooValue))),
+		}, nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(secretVal))
+			Expect(string(secret.Data["foo"])).To(Equal(FooValue))
+		}
+	}
+
 	// with dataFrom.Find the change is on the called method GetAllSecrets
 	// all keys should be put into the secret
 	syncDataFromFind := func(tc *testCase) {
@@ -990,6 +1769,33 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// with dataFrom.rewrite the keys are rewritten before they are converted
+	syncDataFromFindWithRewrite := func(tc *testCase) {
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Find: &esv1beta1.ExternalSecretFind{
+					Name: &esv1beta1.FindName{
+						RegExp: "^prod/app/",
+					},
+					ConversionStrategy: esv1beta1.ExternalSecretConversionDefault,
+				},
+				Rewrite: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "^prod/app/", Target: ""}},
+					{Transform: &esv1beta1.ExternalSecretRewriteTransform{Template: "{{ .value | lower }}"}},
+				},
+			},
+		}
+		fakeProvider.WithGetAllSecrets(map[string][]byte{
+			"prod/app/FOO":     []byte(FooValue),
+			"prod/app/sub/BAR": []byte(BarValue),
+		}, nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data["foo"])).To(Equal(FooValue))
+			Expect(string(secret.Data["sub_bar"])).To(Equal(BarValue))
+		}
+	}
+
 	// with dataFrom and using a template
 	// should be put into the secret
 	syncWithDataFromTemplate := func(tc *testCase) {
@@ -1020,6 +1826,222 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// when the data misses a key required by the secret type
+	// a error condition must be set.
+	syncWithTemplateTypeMissingKey := func(tc *testCase) {
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.Target = esv1beta1.ExternalSecretTarget{
+			Name: ExternalSecretTargetSecretName,
+			Template: &esv1beta1.ExternalSecretTemplate{
+				Type: v1.SecretTypeTLS,
+			},
+		}
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{
+					Key: remoteKey,
+				},
+			},
+		}
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			"tls.crt": []byte(FooValue),
+		}, nil)
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretSyncedError {
+				return false
+			}
+			return true
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			err := k8sClient.Get(context.Background(), secretLookupKey, &v1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	}
+
+	// when the provider rejects the version of a remoteRef
+	// a error condition must be set and the secret must not be fetched.
+	syncWithInvalidVersion := func(tc *testCase) {
+		tc.externalSecret.Spec.Data[0].RemoteRef.Version = "next"
+		fakeProvider.WithValidateVersion(func(version string) error {
+			return fmt.Errorf("invalid version %s", version)
+		})
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretSyncedError {
+				return false
+			}
+			return cond.Message == fmt.Sprintf("%s: could not select version of key %s: invalid version next", errGetSecretData, remoteKey)
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			err := k8sClient.Get(context.Background(), secretLookupKey, &v1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	}
+
+	// a data entry with a sourceRef is fetched from the referenced store.
+	syncWithSourceRefStore := func(tc *testCase) {
+		otherStore := tc.secretStore.DeepCopy()
+		otherStore.ObjectMeta.Name = "other-store"
+		Expect(k8sClient.Create(context.Background(), otherStore)).To(Succeed())
+		tc.externalSecret.Spec.Data[0].SourceRef = &esv1beta1.SourceRef{
+			SecretStoreRef: &esv1beta1.SecretStoreRef{
+				Name: otherStore.Name,
+				Kind: esv1beta1.SecretStoreKind,
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(FooValue))
+		}
+	}
+
+	// without a conflictPolicy data overlays a key of dataFrom without warning events.
+	syncWithDefaultConflictPolicy := func(tc *testCase) {
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{
+					Key: "datamap",
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			targetProp: []byte(BarValue),
+		}, nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(FooValue))
+			Consistently(func() []v1.Event {
+				var events v1.EventList
+				Expect(k8sClient.List(context.Background(), &events, client.InNamespace(ExternalSecretNamespace))).To(Succeed())
+				var warnings []v1.Event
+				for _, e := range events.Items {
+					if e.InvolvedObject.Name == ExternalSecretName && e.Type == v1.EventTypeWarning {
+						warnings = append(warnings, e)
+					}
+				}
+				return warnings
+			}, time.Second*2, interval).Should(BeEmpty())
+		}
+	}
+
+	// with conflictPolicy=FirstWins a key of dataFrom is not overwritten by data.
+	syncWithConflictPolicyFirstWins := func(tc *testCase) {
+		tc.externalSecret.Spec.ConflictPolicy = esv1beta1.ConflictPolicyFirstWins
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{
+					Key: "datamap",
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			targetProp: []byte(BarValue),
+		}, nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(BarValue))
+		}
+	}
+
+	// with conflictPolicy=Error a key set by data and dataFrom must set a conflict condition.
+	conflictPolicyErrCondition := func(tc *testCase) {
+		tc.externalSecret.Spec.ConflictPolicy = esv1beta1.ConflictPolicyError
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{
+					Key: "datamap",
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			targetProp: []byte(BarValue),
+		}, nil)
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretKeyConflict {
+				return false
+			}
+			return cond.Message == fmt.Sprintf("%s: key %s is set by dataFrom[0] and data[0]", errGetSecretData, targetProp)
+		}
+	}
+
+	// the target secret is tracked in the status and changes are reverted
+	// without waiting for the refresh interval.
+	syncedTargetSecretDrift := func(tc *testCase) {
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(es.Status.TargetSecret).ToNot(BeNil())
+			Expect(es.Status.TargetSecret.Name).To(Equal(ExternalSecretTargetSecretName))
+			Expect(es.Status.TargetSecret.DataHash).To(Equal(secret.Annotations[esv1beta1.AnnotationDataHash]))
+
+			secret.Data[targetProp] = []byte("edited")
+			Expect(k8sClient.Update(context.Background(), secret)).To(Succeed())
+			Eventually(func() bool {
+				var syncedSecret v1.Secret
+				err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(secret), &syncedSecret)
+				return err == nil && string(syncedSecret.Data[targetProp]) == FooValue
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
+	// optional data entries which don't exist at the provider are skipped.
+	syncWithMissingOptionalKey := func(tc *testCase) {
+		const missingKey = "missing"
+		tc.externalSecret.Spec.Data = append(tc.externalSecret.Spec.Data, esv1beta1.ExternalSecretData{
+			SecretKey: missingKey,
+			RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{
+				Key: missingKey,
+			},
+			Optional: true,
+		})
+		fakeProvider.GetSecretFn = func(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+			if ref.Key == missingKey {
+				return nil, esv1beta1.NoSecretErr
+			}
+			return []byte(FooValue), nil
+		}
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionTrue {
+				return false
+			}
+			return cond.Message == fmt.Sprintf(msgSyncedSkippedKeys, missingKey)
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(FooValue))
+			Expect(secret.Data).ToNot(HaveKey(missingKey))
+		}
+	}
+
+	// a sourceRef to a missing store must set an error condition.
+	sourceRefStoreMissingErrCondition := func(tc *testCase) {
+		tc.externalSecret.Spec.Data[0].SourceRef = &esv1beta1.SourceRef{
+			SecretStoreRef: &esv1beta1.SecretStoreRef{
+				Name: "missing-store",
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretSyncedError {
+				return false
+			}
+			return strings.HasPrefix(cond.Message, fmt.Sprintf("%s: could not get SecretStore %q", errGetSecretData, "missing-store"))
+		}
+	}
+
 	// when a provider errors in a GetSecret call
 	// a error condition must be set.
 	providerErrCondition := func(tc *testCase) {
@@ -1059,6 +2081,36 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// failed syncs are counted and their next retry is exposed in the status,
+	// a successful sync resets both.
+	errorBackoff := func(tc *testCase) {
+		const secretVal = "foobar"
+		fakeProvider.WithGetSecret(nil, fmt.Errorf("boom"))
+		// the backoff of the suite is shorter than the refresh interval.
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse {
+				return false
+			}
+			return es.Status.FailedSyncs >= 2 && es.Status.NextRetryTime != nil
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			fakeProvider.WithGetSecret([]byte(secretVal), nil)
+			esKey := types.NamespacedName{Name: ExternalSecretName, Namespace: ExternalSecretNamespace}
+			Eventually(func() bool {
+				if err := k8sClient.Get(context.Background(), esKey, es); err != nil {
+					return false
+				}
+				cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+				if cond == nil || cond.Status != v1.ConditionTrue {
+					return false
+				}
+				return es.Status.FailedSyncs == 0 && es.Status.NextRetryTime == nil
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
 	// When a ExternalSecret references an non-existing SecretStore
 	// a error condition must be set.
 	storeMissingErrCondition := func(tc *testCase) {
@@ -1177,6 +2229,50 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// an immutable secret must be recreated when the provider secret changes
+	recreateImmutableSecret := func(tc *testCase) {
+		const secretVal = "someValue"
+		fakeProvider.WithGetSecret([]byte(secretVal), nil)
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		tc.externalSecret.Spec.Target.Immutable = true
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(*secret.Immutable).To(BeTrue())
+			Expect(string(secret.Data[targetProp])).To(Equal(secretVal))
+			oldUID := secret.UID
+
+			// update provider secret
+			newValue := "NEW VALUE"
+			fakeProvider.WithGetSecret([]byte(newValue), nil)
+			var newSecret v1.Secret
+			secretLookupKey := types.NamespacedName{
+				Name:      ExternalSecretTargetSecretName,
+				Namespace: ExternalSecretNamespace,
+			}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), secretLookupKey, &newSecret)
+				if err != nil {
+					return false
+				}
+				return newSecret.UID != oldUID && string(newSecret.Data[targetProp]) == newValue
+			}, timeout, interval).Should(BeTrue())
+			Expect(*newSecret.Immutable).To(BeTrue())
+
+			// the condition is reset on the next refresh, the event persists
+			Eventually(func() bool {
+				var events v1.EventList
+				if err := k8sClient.List(context.Background(), &events, client.InNamespace(ExternalSecretNamespace)); err != nil {
+					return false
+				}
+				for _, e := range events.Items {
+					if e.InvolvedObject.Name == ExternalSecretName && e.Reason == esv1beta1.ReasonRecreated {
+						return true
+					}
+				}
+				return false
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
 	// Checks that secret annotation has been written based on the data
 	checkSecretDataHashAnnotation := func(tc *testCase) {
 		const secretVal = "someValue"
@@ -1263,12 +2359,14 @@ var _ = Describe("ExternalSecret controller", func() {
 			}
 		},
 		Entry("should recreate deleted secret", checkDeletion),
+		Entry("should recreate immutable secret when provider secret changes", recreateImmutableSecret),
 		Entry("should create proper hash annotation for the external secret", checkSecretDataHashAnnotation),
 		Entry("should refresh when the hash annotation doesn't correspond to secret data", checkSecretDataHashAnnotationChange),
 		Entry("should use external secret name if target secret name isn't defined", syncWithoutTargetName),
 		Entry("should set the condition eventually", syncLabelsAnnotations),
 		Entry("should set prometheus counters", checkPrometheusCounters),
 		Entry("should merge with existing secret using creationPolicy=Merge", mergeWithSecret),
+		Entry("should not make a secret immutable using creationPolicy=Merge", mergeWithImmutable),
 		Entry("should error if secret doesn't exist when using creationPolicy=Merge", mergeWithSecretErr),
 		Entry("should not resolve conflicts with creationPolicy=Merge", mergeWithConflict),
 		Entry("should not update unchanged secret using creationPolicy=Merge", mergeWithSecretNoChange),
@@ -1276,16 +2374,45 @@ var _ = Describe("ExternalSecret controller", func() {
 		Entry("should sync with template", syncWithTemplate),
 		Entry("should sync with template engine v2", syncWithTemplateV2),
 		Entry("should sync template with correct value precedence", syncWithTemplatePrecedence),
+		Entry("should sync templateFrom into annotations and labels", syncWithTemplateFromTargets),
+		Entry("should render template metadata into labels and annotations", syncWithTemplateMetadata),
 		Entry("should refresh secret from template", refreshWithTemplate),
 		Entry("should be able to use only metadata from template", onlyMetadataFromTemplate),
 		Entry("should refresh secret value when provider secret changes", refreshSecretValue),
 		Entry("should refresh secret map when provider secret changes", refreshSecretValueMap),
 		Entry("should refresh secret map when provider secret changes when using a template", refreshSecretValueMapTemplate),
+		Entry("should remove keys from the secret that were removed from the template", refreshWithTemplateRemovedKey),
 		Entry("should not refresh secret value when provider secret changes but refreshInterval is zero", refreshintervalZero),
+		Entry("should not sync a modified secret again when refreshInterval is zero", refreshintervalZeroModifiedSecret),
 		Entry("should fetch secret using dataFrom", syncWithDataFrom),
 		Entry("should fetch secret using dataFrom.find", syncDataFromFind),
+		Entry("should decode secret values with decodingStrategy", syncWithDecodingStrategy),
+		Entry("should rewrite keys of dataFrom.find", syncDataFromFindWithRewrite),
 		Entry("should fetch secret using dataFrom and a template", syncWithDataFromTemplate),
+		Entry("should set error condition when data misses a key of the template type", syncWithTemplateTypeMissingKey),
+		Entry("should set error condition when the provider rejects the version", syncWithInvalidVersion),
+		Entry("should sync data entries from the store of their sourceRef", syncWithSourceRefStore),
+		Entry("should set an error condition when the store of a sourceRef does not exist", sourceRefStoreMissingErrCondition),
+		Entry("should overlay a key without warning events if no conflictPolicy is set", syncWithDefaultConflictPolicy),
+		Entry("should keep the first value of a key with conflictPolicy=FirstWins", syncWithConflictPolicyFirstWins),
+		Entry("should set a conflict condition when a key is set twice with conflictPolicy=Error", conflictPolicyErrCondition),
+		Entry("should revert changes of the target secret before the refresh interval", syncedTargetSecretDrift),
+		Entry("should skip missing optional keys", syncWithMissingOptionalKey),
+		Entry("should render a templated target secret name", syncWithTemplatedTargetName),
+		Entry("should sync a password of a Password generator", syncWithPasswordGenerator),
+		Entry("should sync the data of a Fake generator with rewrite and template", syncWithFakeGenerator),
+		Entry("should sync the keys of a generator selected by generatorRef.keys", selectGeneratedKeys),
+		Entry("should error if generatorRef.keys selects a key that is not generated", selectMissingGeneratedKey),
+		Entry("should sync the data of a ClusterGenerator", syncWithAllowedClusterGenerator),
+		Entry("should not sync a ClusterGenerator from a namespace denied by its conditions", denyClusterGeneratorNamespace),
+		Entry("should record and supersede the states of stateful generators", recordGeneratorStates),
+		Entry("should keep a generated ssh key with rotationPolicy=OnlyWhenMissing", keepGeneratedSSHKey),
+		Entry("should not call a generator with rotationPolicy=OnlyWhenMissing while its data is kept", skipGeneratorWhileKept),
+		Entry("should call a generator once for all entries which take keys from it", shareGeneratorRun),
+		Entry("should generate data with rotationPolicy=OnlyWhenMissing again before it expires", regenerateExpiringData),
+		Entry("should renew data with rotationPolicy=OnlyWhenMissing before it expires", renewExpiringData),
 		Entry("should set error condition when provider errors", providerErrCondition),
+		Entry("should back off failed syncs and expose the next retry", errorBackoff),
 		Entry("should set an error condition when store does not exist", storeMissingErrCondition),
 		Entry("should set an error condition when store provider constructor fails", storeConstructErrCondition),
 		Entry("should not process store with mismatching controller field", ignoreMismatchController),
@@ -1486,43 +2613,6 @@ var _ = Describe("ExternalSecret refresh logic", func() {
 	})
 })
 
-var _ = Describe("Controller Reconcile logic", func() {
-	Context("controller reconcile", func() {
-		It("should reconcile when resource is not synced", func() {
-			Expect(shouldReconcile(esv1beta1.ExternalSecret{
-				Status: esv1beta1.ExternalSecretStatus{
-					SyncedResourceVersion: "some resource version",
-					Conditions:            []esv1beta1.ExternalSecretStatusCondition{{Reason: "NotASecretSynced"}},
-				},
-			})).To(BeTrue())
-		})
-
-		It("should reconcile when secret isn't immutable", func() {
-			Expect(shouldReconcile(esv1beta1.ExternalSecret{
-				Spec: esv1beta1.ExternalSecretSpec{
-					Target: esv1beta1.ExternalSecretTarget{
-						Immutable: false,
-					},
-				},
-			})).To(BeTrue())
-		})
-
-		It("should not reconcile if secret is immutable and has synced condition", func() {
-			Expect(shouldReconcile(esv1beta1.ExternalSecret{
-				Spec: esv1beta1.ExternalSecretSpec{
-					Target: esv1beta1.ExternalSecretTarget{
-						Immutable: true,
-					},
-				},
-				Status: esv1beta1.ExternalSecretStatus{
-					SyncedResourceVersion: "some resource version",
-					Conditions:            []esv1beta1.ExternalSecretStatusCondition{{Reason: "SecretSynced"}},
-				},
-			})).To(BeFalse())
-		})
-	})
-})
-
 func externalSecretConditionShouldBe(name, ns string, ct esv1beta1.ExternalSecretConditionType, cs v1.ConditionStatus, v float64) bool {
 	return Eventually(func() float64 {
 		Expect(externalSecretCondition.WithLabelValues(name, ns, string(ct), string(cs)).Write(&metric)).To(Succeed())
@@ -26,6 +26,9 @@ const (
 	ExternalSecretSubsystem          = "externalsecret"
 	SyncCallsKey                     = "sync_calls_total"
 	SyncCallsErrorKey                = "sync_calls_error"
+	StoreSyncCallsKey                = "store_sync_calls_total"
+	StoreSyncCallsErrorKey           = "store_sync_calls_error"
+	ReconcileDurationKey             = "reconcile_duration_seconds"
 	externalSecretStatusConditionKey = "status_condition"
 )
 
@@ -42,6 +45,25 @@ var (
 		Help:      "Total number of the External Secret sync errors",
 	}, []string{"name", "namespace"})
 
+	storeSyncCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      StoreSyncCallsKey,
+		Help:      "Total number of the External Secret sync calls by namespace and store",
+	}, []string{"namespace", "store_kind", "store_name"})
+
+	storeSyncCallsError = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      StoreSyncCallsErrorKey,
+		Help:      "Total number of the External Secret sync errors by namespace and store",
+	}, []string{"namespace", "store_kind", "store_name"})
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      ReconcileDurationKey,
+		Help:      "The duration of the External Secret reconciliation by namespace and store",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"namespace", "store_kind", "store_name"})
+
 	externalSecretCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: ExternalSecretSubsystem,
 		Name:      externalSecretStatusConditionKey,
@@ -49,6 +71,33 @@ var (
 	}, []string{"name", "namespace", "condition", "status"})
 )
 
+// storeSyncMetricLabels returns the labels of the store metrics of an ExternalSecret.
+// The store is the default store of the ExternalSecret, stores referenced by
+// sourceRefs are not taken into account.
+func storeSyncMetricLabels(es *esv1beta1.ExternalSecret) prometheus.Labels {
+	kind := es.Spec.SecretStoreRef.Kind
+	if kind == "" && es.Spec.SecretStoreRef.Name != "" {
+		kind = esv1beta1.SecretStoreKind
+	}
+	return prometheus.Labels{
+		"namespace":  es.Namespace,
+		"store_kind": kind,
+		"store_name": es.Spec.SecretStoreRef.Name,
+	}
+}
+
+// countSyncCall increments the sync call counters of an ExternalSecret and its store.
+func countSyncCall(labels, storeLabels prometheus.Labels) {
+	syncCallsTotal.With(labels).Inc()
+	storeSyncCallsTotal.With(storeLabels).Inc()
+}
+
+// countSyncError increments the sync error counters of an ExternalSecret and its store.
+func countSyncError(labels, storeLabels prometheus.Labels) {
+	syncCallsError.With(labels).Inc()
+	storeSyncCallsError.With(storeLabels).Inc()
+}
+
 // updateExternalSecretCondition updates the ExternalSecret conditions.
 func updateExternalSecretCondition(es *esv1beta1.ExternalSecret, condition *esv1beta1.ExternalSecretStatusCondition, value float64) {
 	switch condition.Type {
@@ -116,5 +165,5 @@ func updateExternalSecretCondition(es *esv1beta1.ExternalSecret, condition *esv1
 }
 
 func init() {
-	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition)
+	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, storeSyncCallsTotal, storeSyncCallsError, reconcileDuration, externalSecretCondition)
 }
@@ -14,12 +14,48 @@ limitations under the License.
 package externalsecret
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	tplv2 "github.com/external-secrets/external-secrets/pkg/template/v2"
 )
 
+const errTargetName = "could not render target name: %w"
+
+// targetSecretName returns the name of the target Secret of the ExternalSecret.
+// It defaults to the name of the ExternalSecret, a templated name is rendered
+// with the name, namespace, labels and annotations of the ExternalSecret.
+func targetSecretName(es *esv1beta1.ExternalSecret) (string, error) {
+	name := es.Spec.Target.Name
+	if name == "" {
+		return es.Name, nil
+	}
+	if !strings.Contains(name, "{{") {
+		return name, nil
+	}
+	tpl, err := template.New("name").Funcs(tplv2.FuncMap()).Option("missingkey=error").Parse(name)
+	if err != nil {
+		return "", fmt.Errorf(errTargetName, err)
+	}
+	var buf bytes.Buffer
+	err = tpl.Execute(&buf, map[string]interface{}{
+		"name":        es.Name,
+		"namespace":   es.Namespace,
+		"labels":      es.Labels,
+		"annotations": es.Annotations,
+	})
+	if err != nil {
+		return "", fmt.Errorf(errTargetName, err)
+	}
+	return buf.String(), nil
+}
+
 // NewExternalSecretCondition a set of default options for creating an External Secret Condition.
 func NewExternalSecretCondition(condType esv1beta1.ExternalSecretConditionType, status v1.ConditionStatus, reason, message string) *esv1beta1.ExternalSecretStatusCondition {
 	return &esv1beta1.ExternalSecretStatusCondition{
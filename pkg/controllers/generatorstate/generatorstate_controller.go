@@ -0,0 +1,151 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generatorstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+
+	// Loading registered generators.
+	_ "github.com/external-secrets/external-secrets/pkg/generator/register"
+)
+
+const (
+	// ReasonCleanupFailed is the reason of events of failed cleanups.
+	ReasonCleanupFailed = "CleanupFailed"
+	// ReasonCleanupSkipped is the reason of events of states which can't be cleaned up.
+	ReasonCleanupSkipped = "CleanupSkipped"
+
+	errGetGeneratorState    = "could not get GeneratorState"
+	errCleanup              = "could not clean up generator state"
+	errRemoveFinalizer      = "could not remove finalizer"
+	errDeleteGeneratorState = "could not delete GeneratorState"
+	errParseResource        = "could not parse generator resource: %w"
+	msgCleanupSkipped       = "%v, the resources of the state are not cleaned up"
+)
+
+var errGeneratorNotRegistered = errors.New("no generator registered")
+
+// Reconciler cleans up the resources recorded in GeneratorStates once their
+// garbage collection deadline passed or they are deleted, e.g. together with
+// the ExternalSecret that owns them.
+type Reconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// Reconcile cleans up and deletes a GeneratorState if it is due,
+// otherwise it requeues the GeneratorState for its deadline.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("GeneratorState", req.NamespacedName)
+
+	var state genv1alpha1.GeneratorState
+	err := r.Get(ctx, req.NamespacedName, &state)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, errGetGeneratorState)
+		return ctrl.Result{}, err
+	}
+
+	deleting := !state.DeletionTimestamp.IsZero()
+	if !deleting {
+		deadline := state.Spec.GarbageCollectionDeadline
+		if deadline == nil {
+			return ctrl.Result{}, nil
+		}
+		if d := time.Until(deadline.Time); d > 0 {
+			return ctrl.Result{RequeueAfter: d}, nil
+		}
+	}
+
+	if controllerutil.ContainsFinalizer(&state, genv1alpha1.GeneratorStateFinalizer) {
+		err := Cleanup(ctx, r.Client, &state)
+		// the finalizer of states of unknown generators is removed anyway,
+		// so that they don't block the deletion of their namespace.
+		if errors.Is(err, errGeneratorNotRegistered) {
+			log.Info("skipping cleanup of generator state", "reason", err.Error())
+			r.recorder.Event(&state, v1.EventTypeWarning, ReasonCleanupSkipped, fmt.Sprintf(msgCleanupSkipped, err))
+		} else if err != nil {
+			log.Error(err, errCleanup)
+			r.recorder.Event(&state, v1.EventTypeWarning, ReasonCleanupFailed, err.Error())
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&state, genv1alpha1.GeneratorStateFinalizer)
+		if err := r.Update(ctx, &state); err != nil {
+			log.Error(err, errRemoveFinalizer)
+			return ctrl.Result{}, err
+		}
+		log.V(1).Info("cleaned up generator state")
+	}
+
+	if !deleting {
+		err = r.Delete(ctx, &state)
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, errDeleteGeneratorState)
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// Cleanup lets the generator that produced the state clean up its resources.
+// Nothing is done for generators which don't keep a state.
+func Cleanup(ctx context.Context, kube client.Client, state *genv1alpha1.GeneratorState) error {
+	if state.Spec.Resource == nil || state.Spec.State == nil {
+		return nil
+	}
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(state.Spec.Resource.Raw, &typeMeta); err != nil {
+		return fmt.Errorf(errParseResource, err)
+	}
+	gen, ok := genv1alpha1.GetGenerator(typeMeta.Kind)
+	if !ok {
+		return fmt.Errorf("%w for kind %s", errGeneratorNotRegistered, typeMeta.Kind)
+	}
+	stateful, ok := gen.(genv1alpha1.StatefulGenerator)
+	if !ok {
+		return nil
+	}
+	return stateful.Cleanup(ctx, state.Spec.Resource, state.Spec.State, kube, state.Namespace)
+}
+
+// SetupWithManager returns a new controller builder that will be started by the provided Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
+	r.recorder = mgr.GetEventRecorderFor("external-secrets")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&genv1alpha1.GeneratorState{}).
+		Complete(r)
+}
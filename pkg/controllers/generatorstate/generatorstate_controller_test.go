@@ -0,0 +1,161 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generatorstate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+const testGeneratorKind = "TestStatefulGenerator"
+
+// testGenerator records the states it cleaned up.
+type testGenerator struct {
+	cleaned []string
+	err     error
+}
+
+func (g *testGenerator) Generate(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (g *testGenerator) GenerateWithState(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, *apiextensions.JSON, error) {
+	return nil, nil, nil
+}
+
+func (g *testGenerator) Cleanup(ctx context.Context, obj, state *apiextensions.JSON, kube client.Client, namespace string) error {
+	if g.err != nil {
+		return g.err
+	}
+	g.cleaned = append(g.cleaned, string(state.Raw))
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	tests := map[string]struct {
+		deadline    *metav1.Time
+		kind        string
+		delete      bool
+		cleanupErr  error
+		wantCleaned bool
+		wantExists  bool
+		wantRequeue bool
+		wantErr     bool
+	}{
+		"no deadline": {
+			wantExists: true,
+		},
+		"deadline not reached": {
+			deadline:    &future,
+			wantExists:  true,
+			wantRequeue: true,
+		},
+		"deadline passed": {
+			deadline:    &past,
+			wantCleaned: true,
+		},
+		"deleted": {
+			delete:      true,
+			wantCleaned: true,
+		},
+		"generator not registered": {
+			deadline: &past,
+			kind:     "UnknownGenerator",
+		},
+		"deleted with generator not registered": {
+			delete: true,
+			kind:   "UnknownGenerator",
+		},
+		"cleanup fails": {
+			deadline:   &past,
+			cleanupErr: errors.New("permission denied"),
+			wantExists: true,
+			wantErr:    true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gen := &testGenerator{err: tc.cleanupErr}
+			genv1alpha1.ForceRegister(testGeneratorKind, gen)
+			kind := testGeneratorKind
+			if tc.kind != "" {
+				kind = tc.kind
+			}
+
+			scheme := runtime.NewScheme()
+			if err := genv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			state := &genv1alpha1.GeneratorState{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "state",
+					Namespace:  "default",
+					Finalizers: []string{genv1alpha1.GeneratorStateFinalizer},
+				},
+				Spec: genv1alpha1.GeneratorStateSpec{
+					GarbageCollectionDeadline: tc.deadline,
+					Resource:                  &apiextensions.JSON{Raw: []byte(`{"kind":"` + kind + `"}`)},
+					State:                     &apiextensions.JSON{Raw: []byte(`{"id":"1"}`)},
+				},
+			}
+			kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(state).Build()
+			if tc.delete {
+				if err := kube.Delete(context.Background(), state); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := &Reconciler{
+				Client:   kube,
+				Log:      ctrl.Log,
+				Scheme:   scheme,
+				recorder: record.NewFakeRecorder(10),
+			}
+			key := types.NamespacedName{Name: "state", Namespace: "default"}
+			res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (res.RequeueAfter > 0) != tc.wantRequeue {
+				t.Errorf("unexpected requeue: %v", res.RequeueAfter)
+			}
+			if (len(gen.cleaned) > 0) != tc.wantCleaned {
+				t.Errorf("unexpected cleanup: %v", gen.cleaned)
+			}
+			err = kube.Get(context.Background(), key, &genv1alpha1.GeneratorState{})
+			if tc.wantExists && err != nil {
+				t.Errorf("expected generator state to exist: %v", err)
+			}
+			if !tc.wantExists && !apierrors.IsNotFound(err) {
+				t.Errorf("expected generator state to be deleted: %v", err)
+			}
+		})
+	}
+}
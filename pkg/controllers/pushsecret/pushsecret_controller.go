@@ -0,0 +1,534 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+
+	// Loading registered providers.
+	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
+	"github.com/external-secrets/external-secrets/pkg/template"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	requeueAfter = time.Second * 30
+
+	errGetPushSecret         = "could not get PushSecret"
+	errListPushSecrets       = "could not list PushSecrets"
+	errPatchStatus           = "unable to patch status"
+	errGetSecret             = "could not get source secret %s: %w"
+	errGetSecretStore        = "could not get SecretStore %q, %w"
+	errGetClusterSecretStore = "could not get ClusterSecretStore %q, %w"
+	errUnmanagedStore        = "store %s is not managed by this controller"
+	errClusterStoreMismatch  = "using cluster store %q is not allowed from namespace %q: denied by spec.condition"
+	errStoreProvider         = "could not get provider of store %s: %w"
+	errStoreClient           = "could not get provider client of store %s: %w"
+	errPushNotSupported      = "store %s does not support pushing secrets"
+	errMissingSecretKey      = "secret %s has no key %s"
+	errPushSecret            = "could not push key %s to %s in store %s: %w"
+	errCloseStoreClient      = "could not close provider client"
+	errDeleteNotSupported    = "store %s does not support deleting secrets"
+	errDeleteSecret          = "could not delete %s from store %s: %w"
+	errInvalidStoreKey       = "invalid store %s in status.syncedPushSecrets"
+	errUpdateFinalizer       = "could not update finalizers: %w"
+	errExecTpl               = "could not execute template: %w"
+
+	// pushSecretFinalizer is added to PushSecrets with deletionPolicy=Delete,
+	// so that the pushed secrets are deleted before the PushSecret.
+	pushSecretFinalizer = "pushsecret.externalsecrets.io/finalizer"
+)
+
+// Reconciler reconciles a PushSecret object.
+type Reconciler struct {
+	client.Client
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	ControllerClass string
+	RequeueInterval time.Duration
+	// ErrorBackoffBase is the time a failed sync is retried after, it doubles with every
+	// further failed sync up to ErrorBackoffMax. Both default to 30s.
+	ErrorBackoffBase time.Duration
+	ErrorBackoffMax  time.Duration
+	// RefreshJitter extends the refresh interval by a random fraction of up to RefreshJitter.
+	RefreshJitter float64
+	// ClientCache shares the provider clients between reconciles, if set.
+	ClientCache *secretstore.ClientCache
+	// SourceSecretWatchEnabled pushes changes of source secrets right away
+	// instead of on the next refresh.
+	SourceSecretWatchEnabled bool
+	recorder                 record.EventRecorder
+}
+
+// Reconcile pushes the selected keys of the source Secret of a PushSecret
+// to all referenced SecretStores.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("PushSecret", req.NamespacedName)
+
+	var ps esv1alpha1.PushSecret
+	err := r.Get(ctx, req.NamespacedName, &ps)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, errGetPushSecret)
+		return ctrl.Result{}, err
+	}
+
+	if !ps.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, &ps)
+	}
+	if err := r.updateFinalizer(ctx, &ps); err != nil {
+		log.Error(err, errUpdateFinalizer)
+		return ctrl.Result{}, err
+	}
+
+	refreshInt := r.RequeueInterval
+	if ps.Spec.RefreshInterval != nil {
+		refreshInt = ps.Spec.RefreshInterval.Duration
+	}
+	secret, err := r.getSecret(ctx, ps)
+	if err == nil && !shouldRefresh(ps, refreshInt, secret.ResourceVersion) {
+		log.V(1).Info("skipping refresh", "rv", getResourceVersion(ps))
+		return ctrl.Result{RequeueAfter: utils.Jitter(refreshInt, r.RefreshJitter)}, nil
+	}
+
+	// patch status when done processing
+	p := client.MergeFrom(ps.DeepCopy())
+	defer func() {
+		err := r.Status().Patch(ctx, &ps, p)
+		if err != nil {
+			log.Error(err, errPatchStatus)
+		}
+	}()
+
+	if err != nil {
+		r.markAsFailed(&ps, err)
+		return r.errorResult(&ps), nil
+	}
+	sourceVersion := secret.ResourceVersion
+	secret, err = renderTemplate(ps, secret)
+	if err != nil {
+		r.markAsFailed(&ps, err)
+		return r.errorResult(&ps), nil
+	}
+
+	synced, err := r.pushSecretToStores(ctx, ps, secret)
+	if err != nil {
+		// the keys pushed before the error are recorded, so that they are deleted
+		// with deletionPolicy=Delete even if the PushSecret never synced.
+		ps.Status.SyncedPushSecrets = mergeSecrets(ps.Status.SyncedPushSecrets, synced)
+		r.markAsFailed(&ps, err)
+		return r.errorResult(&ps), nil
+	}
+
+	// keys which are no longer pushed to a store are deleted from it
+	if ps.Spec.DeletionPolicy == esv1alpha1.PushSecretDeletionPolicyDelete {
+		err = r.deleteSecretsFromStores(ctx, ps, staleSecrets(ps.Status.SyncedPushSecrets, synced))
+		if err != nil {
+			r.markAsFailed(&ps, err)
+			return r.errorResult(&ps), nil
+		}
+	}
+
+	r.recorder.Event(&ps, v1.EventTypeNormal, esv1alpha1.ReasonSynced, "PushSecret synced successfully")
+	SetPushSecretCondition(&ps, *NewPushSecretCondition(esv1alpha1.PushSecretReady, v1.ConditionTrue, esv1alpha1.ReasonSynced, "PushSecret synced successfully"))
+	ps.Status.SyncedPushSecrets = synced
+	ps.Status.RefreshTime = metav1.NewTime(time.Now())
+	ps.Status.SyncedResourceVersion = getResourceVersion(ps)
+	ps.Status.SourceSecretResourceVersion = sourceVersion
+	ps.Status.FailedSyncs = 0
+	ps.Status.NextRetryTime = nil
+	log.V(1).Info("pushed secret")
+
+	return ctrl.Result{RequeueAfter: utils.Jitter(refreshInt, r.RefreshJitter)}, nil
+}
+
+// reconcileDelete deletes the pushed secrets of a deleted PushSecret with
+// deletionPolicy=Delete and removes its finalizer.
+func (r *Reconciler) reconcileDelete(ctx context.Context, ps *esv1alpha1.PushSecret) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(ps, pushSecretFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	if ps.Spec.DeletionPolicy == esv1alpha1.PushSecretDeletionPolicyDelete {
+		if err := r.deleteSecretsFromStores(ctx, *ps, ps.Status.SyncedPushSecrets); err != nil {
+			p := client.MergeFrom(ps.DeepCopy())
+			r.markAsFailed(ps, err)
+			result := r.errorResult(ps)
+			if err := r.Status().Patch(ctx, ps, p); err != nil {
+				r.Log.Error(err, errPatchStatus)
+			}
+			return result, nil
+		}
+	}
+	controllerutil.RemoveFinalizer(ps, pushSecretFinalizer)
+	if err := r.Update(ctx, ps); err != nil {
+		return ctrl.Result{}, fmt.Errorf(errUpdateFinalizer, err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// updateFinalizer adds the finalizer to PushSecrets with deletionPolicy=Delete
+// and removes it from PushSecrets with another deletionPolicy.
+func (r *Reconciler) updateFinalizer(ctx context.Context, ps *esv1alpha1.PushSecret) error {
+	shouldDelete := ps.Spec.DeletionPolicy == esv1alpha1.PushSecretDeletionPolicyDelete
+	if shouldDelete == controllerutil.ContainsFinalizer(ps, pushSecretFinalizer) {
+		return nil
+	}
+	if shouldDelete {
+		controllerutil.AddFinalizer(ps, pushSecretFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(ps, pushSecretFinalizer)
+	}
+	if err := r.Update(ctx, ps); err != nil {
+		return fmt.Errorf(errUpdateFinalizer, err)
+	}
+	return nil
+}
+
+func (r *Reconciler) markAsFailed(ps *esv1alpha1.PushSecret, err error) {
+	r.Log.Error(err, "could not push secret", "PushSecret", types.NamespacedName{Name: ps.Name, Namespace: ps.Namespace})
+	r.recorder.Event(ps, v1.EventTypeWarning, esv1alpha1.ReasonErrored, err.Error())
+	SetPushSecretCondition(ps, *NewPushSecretCondition(esv1alpha1.PushSecretReady, v1.ConditionFalse, esv1alpha1.ReasonErrored, err.Error()))
+}
+
+// errorResult records a failed sync in the status of the PushSecret and returns the result
+// that retries the sync with the exponential backoff of ExternalSecrets.
+func (r *Reconciler) errorResult(ps *esv1alpha1.PushSecret) ctrl.Result {
+	base, maxBackoff := r.ErrorBackoffBase, r.ErrorBackoffMax
+	if base <= 0 {
+		base = requeueAfter
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = requeueAfter
+	}
+	ps.Status.FailedSyncs++
+	backoff := utils.Backoff(base, maxBackoff, ps.Status.FailedSyncs)
+	next := metav1.NewTime(time.Now().Add(backoff))
+	ps.Status.NextRetryTime = &next
+	return ctrl.Result{RequeueAfter: backoff}
+}
+
+func (r *Reconciler) getSecret(ctx context.Context, ps esv1alpha1.PushSecret) (*v1.Secret, error) {
+	var secret v1.Secret
+	ref := types.NamespacedName{Name: ps.Spec.Selector.Secret.Name, Namespace: ps.Namespace}
+	if err := r.Get(ctx, ref, &secret); err != nil {
+		return nil, fmt.Errorf(errGetSecret, ref.Name, err)
+	}
+	return &secret, nil
+}
+
+// renderTemplate returns a Secret with the keys rendered by the template of the PushSecret
+// from the data of secret. Without a template secret is returned as it is.
+func renderTemplate(ps esv1alpha1.PushSecret, secret *v1.Secret) (*v1.Secret, error) {
+	if ps.Spec.Template == nil {
+		return secret, nil
+	}
+	execute, err := template.EngineForVersion(ps.Spec.Template.EngineVersion)
+	if err != nil {
+		return nil, err
+	}
+	tpl := make(map[string][]byte, len(ps.Spec.Template.Data))
+	for k, v := range ps.Spec.Template.Data {
+		tpl[k] = []byte(v)
+	}
+	rendered := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: secret.Namespace},
+		Data:       make(map[string][]byte, len(tpl)),
+	}
+	if err := execute(tpl, secret.Data, esv1beta1.TemplateTargetData, rendered); err != nil {
+		return nil, fmt.Errorf(errExecTpl, err)
+	}
+	return rendered, nil
+}
+
+// pushSecretToStores pushes the data of the PushSecret to every store and
+// returns the pushed data by store. On error the data pushed so far is returned.
+func (r *Reconciler) pushSecretToStores(ctx context.Context, ps esv1alpha1.PushSecret, secret *v1.Secret) (esv1alpha1.SyncedPushSecretsMap, error) {
+	synced := make(esv1alpha1.SyncedPushSecretsMap)
+	for _, ref := range ps.Spec.SecretStoreRefs {
+		store, err := r.getStore(ctx, ps.Namespace, ref)
+		if err != nil {
+			return synced, err
+		}
+		storeKey := fmt.Sprintf("%s/%s", store.GetObjectKind().GroupVersionKind().Kind, store.GetName())
+		data, err := r.pushSecretToStore(ctx, ps, secret, store, storeKey)
+		if len(data) > 0 {
+			synced[storeKey] = data
+		}
+		if err != nil {
+			return synced, err
+		}
+	}
+	return synced, nil
+}
+
+func (r *Reconciler) pushSecretToStore(ctx context.Context, ps esv1alpha1.PushSecret, secret *v1.Secret, store esv1beta1.GenericStore, storeKey string) (map[string]esv1alpha1.PushSecretData, error) {
+	secretClient, release, err := r.newStoreClient(ctx, ps.Namespace, store, storeKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := release(ctx); err != nil {
+			r.Log.Error(err, errCloseStoreClient)
+		}
+	}()
+	pusher, ok := secretClient.(esv1beta1.SecretsPusher)
+	if !ok {
+		return nil, fmt.Errorf(errPushNotSupported, storeKey)
+	}
+	data := make(map[string]esv1alpha1.PushSecretData, len(ps.Spec.Data))
+	for _, d := range ps.Spec.Data {
+		value, ok := secret.Data[d.Match.SecretKey]
+		if !ok {
+			return data, fmt.Errorf(errMissingSecretKey, secret.Name, d.Match.SecretKey)
+		}
+		err := secretstore.Retry(ctx, store, secretClient, secretstore.CallPushSecret, func() error {
+			return pusher.PushSecret(ctx, value, d.Match.RemoteRef.RemoteKey)
+		})
+		if err != nil {
+			return data, fmt.Errorf(errPushSecret, d.Match.SecretKey, d.Match.RemoteRef.RemoteKey, storeKey, err)
+		}
+		data[d.Match.RemoteRef.RemoteKey] = d
+	}
+	return data, nil
+}
+
+// newStoreClient returns a provider client of the store and the func that releases it,
+// if the store may be used by the PushSecret.
+func (r *Reconciler) newStoreClient(ctx context.Context, namespace string, store esv1beta1.GenericStore, storeKey string) (esv1beta1.SecretsClient, func(context.Context) error, error) {
+	if !secretstore.ShouldProcessStore(store, r.ControllerClass) {
+		return nil, nil, fmt.Errorf(errUnmanagedStore, storeKey)
+	}
+	allowed, err := secretstore.IsNamespaceAllowed(ctx, r.Client, store, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, fmt.Errorf(errClusterStoreMismatch, store.GetName(), namespace)
+	}
+	if _, err := esv1beta1.GetProvider(store); err != nil {
+		return nil, nil, fmt.Errorf(errStoreProvider, storeKey, err)
+	}
+	secretClient, release, err := r.ClientCache.NewClient(ctx, store, r.Client, namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errStoreClient, storeKey, err)
+	}
+	return secretClient, release, nil
+}
+
+// deleteSecretsFromStores deletes the pushed secrets from their stores.
+// Stores which no longer exist are skipped.
+func (r *Reconciler) deleteSecretsFromStores(ctx context.Context, ps esv1alpha1.PushSecret, secrets esv1alpha1.SyncedPushSecretsMap) error {
+	for storeKey, data := range secrets {
+		if len(data) == 0 {
+			continue
+		}
+		parts := strings.SplitN(storeKey, "/", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf(errInvalidStoreKey, storeKey)
+		}
+		store, err := r.getStore(ctx, ps.Namespace, esv1alpha1.PushSecretStoreRef{Kind: parts[0], Name: parts[1]})
+		if apierrors.IsNotFound(err) {
+			r.Log.Info("skipping deletion of pushed secrets from missing store", "PushSecret", types.NamespacedName{Name: ps.Name, Namespace: ps.Namespace}, "store", storeKey)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.deleteSecretsFromStore(ctx, ps, store, storeKey, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteSecretsFromStore(ctx context.Context, ps esv1alpha1.PushSecret, store esv1beta1.GenericStore, storeKey string, data map[string]esv1alpha1.PushSecretData) error {
+	secretClient, release, err := r.newStoreClient(ctx, ps.Namespace, store, storeKey)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := release(ctx); err != nil {
+			r.Log.Error(err, errCloseStoreClient)
+		}
+	}()
+	deleter, ok := secretClient.(esv1beta1.SecretsDeleter)
+	if !ok {
+		return fmt.Errorf(errDeleteNotSupported, storeKey)
+	}
+	for remoteKey := range data {
+		err := secretstore.Retry(ctx, store, secretClient, secretstore.CallDeleteSecret, func() error {
+			return deleter.DeleteSecret(ctx, remoteKey)
+		})
+		if err != nil {
+			return fmt.Errorf(errDeleteSecret, remoteKey, storeKey, err)
+		}
+	}
+	return nil
+}
+
+// staleSecrets returns the secrets of synced which are not part of current.
+func staleSecrets(synced, current esv1alpha1.SyncedPushSecretsMap) esv1alpha1.SyncedPushSecretsMap {
+	stale := make(esv1alpha1.SyncedPushSecretsMap)
+	for storeKey, data := range synced {
+		for remoteKey, d := range data {
+			if _, ok := current[storeKey][remoteKey]; ok {
+				continue
+			}
+			if stale[storeKey] == nil {
+				stale[storeKey] = make(map[string]esv1alpha1.PushSecretData)
+			}
+			stale[storeKey][remoteKey] = d
+		}
+	}
+	return stale
+}
+
+// mergeSecrets returns the secrets of synced and pushed, the data of pushed takes precedence.
+func mergeSecrets(synced, pushed esv1alpha1.SyncedPushSecretsMap) esv1alpha1.SyncedPushSecretsMap {
+	if len(pushed) == 0 {
+		return synced
+	}
+	merged := make(esv1alpha1.SyncedPushSecretsMap, len(synced)+len(pushed))
+	for _, secrets := range []esv1alpha1.SyncedPushSecretsMap{synced, pushed} {
+		for storeKey, data := range secrets {
+			if merged[storeKey] == nil {
+				merged[storeKey] = make(map[string]esv1alpha1.PushSecretData, len(data))
+			}
+			for remoteKey, d := range data {
+				merged[storeKey][remoteKey] = d
+			}
+		}
+	}
+	return merged
+}
+
+// getStore returns the SecretStore or ClusterSecretStore referenced by ref.
+func (r *Reconciler) getStore(ctx context.Context, namespace string, ref esv1alpha1.PushSecretStoreRef) (esv1beta1.GenericStore, error) {
+	if ref.Kind == esv1beta1.ClusterSecretStoreKind {
+		var store esv1beta1.ClusterSecretStore
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name}, &store); err != nil {
+			return nil, fmt.Errorf(errGetClusterSecretStore, ref.Name, err)
+		}
+		store.SetGroupVersionKind(esv1beta1.ClusterSecretStoreGroupVersionKind)
+		return &store, nil
+	}
+	var store esv1beta1.SecretStore
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &store); err != nil {
+		return nil, fmt.Errorf(errGetSecretStore, ref.Name, err)
+	}
+	store.SetGroupVersionKind(esv1beta1.SecretStoreGroupVersionKind)
+	return &store, nil
+}
+
+func getResourceVersion(ps esv1alpha1.PushSecret) string {
+	return fmt.Sprintf("%d-%s", ps.ObjectMeta.GetGeneration(), hashMeta(ps.ObjectMeta))
+}
+
+func hashMeta(m metav1.ObjectMeta) string {
+	type meta struct {
+		annotations map[string]string
+		labels      map[string]string
+	}
+	return utils.ObjectHash(meta{
+		annotations: m.Annotations,
+		labels:      m.Labels,
+	})
+}
+
+// shouldRefresh returns true if the PushSecret or its source Secret changed
+// since the last push or the refresh interval has passed.
+func shouldRefresh(ps esv1alpha1.PushSecret, refreshInt time.Duration, sourceVersion string) bool {
+	if ps.Status.SyncedResourceVersion != getResourceVersion(ps) {
+		return true
+	}
+	if ps.Status.SourceSecretResourceVersion != sourceVersion {
+		return true
+	}
+	if refreshInt == 0 {
+		return false
+	}
+	if ps.Status.RefreshTime.IsZero() {
+		return true
+	}
+	return !ps.Status.RefreshTime.Add(refreshInt).After(time.Now())
+}
+
+// findObjectsForSecret returns a request for every PushSecret that selects the secret,
+// so that changes of source secrets are pushed right away.
+func (r *Reconciler) findObjectsForSecret(secret client.Object) []reconcile.Request {
+	var pushSecrets esv1alpha1.PushSecretList
+	if err := r.List(context.Background(), &pushSecrets, client.InNamespace(secret.GetNamespace())); err != nil {
+		r.Log.Error(err, errListPushSecrets)
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for i := range pushSecrets.Items {
+		ps := &pushSecrets.Items[i]
+		if ps.Spec.Selector.Secret.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ps.Name, Namespace: ps.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager returns a new controller builder that will be started by the provided Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
+	r.recorder = mgr.GetEventRecorderFor("pushsecret")
+
+	// only changes of the spec and metadata are pushed right away, the status patch
+	// of a failed push must not bypass its backoff.
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&esv1alpha1.PushSecret{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.LabelChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+		)))
+	// without the watch, changes of source secrets are pushed on refresh.
+	if r.SourceSecretWatchEnabled {
+		b = b.Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
+			builder.OnlyMetadata,
+		)
+	}
+	return b.Complete(r)
+}
@@ -0,0 +1,442 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+var fakeProvider *fake.Client
+
+func init() {
+	fakeProvider = fake.New()
+	esv1beta1.ForceRegister(fakeProvider, &esv1beta1.SecretStoreProvider{
+		AWS: &esv1beta1.AWSProvider{
+			Service: esv1beta1.AWSServiceSecretsManager,
+		},
+	})
+}
+
+// readOnlyClient hides the PushSecret method of the fake client.
+type readOnlyClient struct {
+	esv1beta1.SecretsClient
+}
+
+const (
+	psName    = "push-db"
+	psNs      = "default"
+	storeName = "backend"
+)
+
+func makePushSecret(f ...func(ps *esv1alpha1.PushSecret)) *esv1alpha1.PushSecret {
+	ps := &esv1alpha1.PushSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: psName, Namespace: psNs, Generation: 1},
+		Spec: esv1alpha1.PushSecretSpec{
+			RefreshInterval: &metav1.Duration{Duration: time.Hour},
+			SecretStoreRefs: []esv1alpha1.PushSecretStoreRef{{Name: storeName, Kind: esv1beta1.SecretStoreKind}},
+			Selector:        esv1alpha1.PushSecretSelector{Secret: esv1alpha1.PushSecretSecret{Name: "db-credentials"}},
+			Data: []esv1alpha1.PushSecretData{{
+				Match: esv1alpha1.PushSecretMatch{SecretKey: "password", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/password"}},
+			}},
+		},
+	}
+	for _, fn := range f {
+		fn(ps)
+	}
+	return ps
+}
+
+func newReconciler(objs ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = esv1beta1.AddToScheme(scheme)
+	_ = esv1alpha1.AddToScheme(scheme)
+	provider := &esv1beta1.SecretStoreProvider{AWS: &esv1beta1.AWSProvider{Service: esv1beta1.AWSServiceSecretsManager}}
+	objs = append(objs,
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: psNs},
+			Data:       map[string][]byte{"password": []byte("s3cr3t"), "user": []byte("app")},
+		},
+		&esv1beta1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: storeName, Namespace: psNs},
+			Spec:       esv1beta1.SecretStoreSpec{Provider: provider},
+		},
+		&esv1beta1.ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: storeName},
+			Spec:       esv1beta1.SecretStoreSpec{Provider: provider},
+		},
+		&esv1beta1.ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider:   provider,
+				Conditions: []esv1beta1.ClusterSecretStoreCondition{{Namespaces: []string{"ops"}}},
+			},
+		},
+	)
+	return &Reconciler{
+		Client:          clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Log:             ctrl.Log.WithName("test"),
+		Scheme:          scheme,
+		RequeueInterval: time.Hour,
+		recorder:        record.NewFakeRecorder(10),
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := map[string]struct {
+		pushSecret    *esv1alpha1.PushSecret
+		pushSecretFn  func(ctx context.Context, value []byte, remoteKey string) error
+		readOnly      bool
+		expectStatus  v1.ConditionStatus
+		expectMessage string
+		expectPushed  map[string]string
+		expectSynced  esv1alpha1.SyncedPushSecretsMap
+	}{
+		"push to SecretStore and ClusterSecretStore": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.SecretStoreRefs = append(ps.Spec.SecretStoreRefs, esv1alpha1.PushSecretStoreRef{Name: storeName, Kind: esv1beta1.ClusterSecretStoreKind})
+				ps.Spec.Data = append(ps.Spec.Data, esv1alpha1.PushSecretData{
+					Match: esv1alpha1.PushSecretMatch{SecretKey: "user", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/user"}},
+				})
+			}),
+			expectStatus: v1.ConditionTrue,
+			expectPushed: map[string]string{"db/password": "s3cr3t", "db/user": "app"},
+			expectSynced: esv1alpha1.SyncedPushSecretsMap{
+				"SecretStore/backend": {
+					"db/password": {Match: esv1alpha1.PushSecretMatch{SecretKey: "password", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/password"}}},
+					"db/user":     {Match: esv1alpha1.PushSecretMatch{SecretKey: "user", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/user"}}},
+				},
+				"ClusterSecretStore/backend": {
+					"db/password": {Match: esv1alpha1.PushSecretMatch{SecretKey: "password", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/password"}}},
+					"db/user":     {Match: esv1alpha1.PushSecretMatch{SecretKey: "user", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/user"}}},
+				},
+			},
+		},
+		"push templated key": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.Template = &esv1alpha1.PushSecretTemplate{
+					EngineVersion: esv1beta1.TemplateEngineV2,
+					Data:          map[string]string{"config": `{"user":"{{ .user }}","password":"{{ .password }}"}`},
+				}
+				ps.Spec.Data[0].Match.SecretKey = "config"
+			}),
+			expectStatus: v1.ConditionTrue,
+			expectPushed: map[string]string{"db/password": `{"user":"app","password":"s3cr3t"}`},
+			expectSynced: esv1alpha1.SyncedPushSecretsMap{
+				"SecretStore/backend": {
+					"db/password": {Match: esv1alpha1.PushSecretMatch{SecretKey: "config", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/password"}}},
+				},
+			},
+		},
+		"template strips keys": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.Template = &esv1alpha1.PushSecretTemplate{
+					EngineVersion: esv1beta1.TemplateEngineV2,
+					Data:          map[string]string{"user": "{{ .user }}"},
+				}
+			}),
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: "secret db-credentials has no key password",
+		},
+		"invalid template": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.Template = &esv1alpha1.PushSecretTemplate{
+					EngineVersion: esv1beta1.TemplateEngineV2,
+					Data:          map[string]string{"password": "{{ .password "},
+				}
+			}),
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: "could not execute template",
+		},
+		"missing source secret": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.Selector.Secret.Name = "missing"
+			}),
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: "could not get source secret missing",
+		},
+		"missing secret key": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.Data[0].Match.SecretKey = "token"
+			}),
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: "secret db-credentials has no key token",
+		},
+		"missing store": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.SecretStoreRefs[0].Name = "missing"
+			}),
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: `could not get SecretStore "missing"`,
+		},
+		"provider error": {
+			pushSecret: makePushSecret(),
+			pushSecretFn: func(context.Context, []byte, string) error {
+				return errors.New("access denied")
+			},
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: "could not push key password to db/password in store SecretStore/backend: access denied",
+		},
+		"cluster store denies namespace": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.SecretStoreRefs = []esv1alpha1.PushSecretStoreRef{{Name: "restricted", Kind: esv1beta1.ClusterSecretStoreKind}}
+			}),
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: `using cluster store "restricted" is not allowed from namespace "default": denied by spec.condition`,
+		},
+		"failed store keeps pushed keys": {
+			pushSecret: makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.SecretStoreRefs = append(ps.Spec.SecretStoreRefs, esv1alpha1.PushSecretStoreRef{Name: "restricted", Kind: esv1beta1.ClusterSecretStoreKind})
+			}),
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: `using cluster store "restricted" is not allowed from namespace "default": denied by spec.condition`,
+			expectPushed:  map[string]string{"db/password": "s3cr3t"},
+			expectSynced: esv1alpha1.SyncedPushSecretsMap{
+				"SecretStore/backend": {
+					"db/password": {Match: esv1alpha1.PushSecretMatch{SecretKey: "password", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/password"}}},
+				},
+			},
+		},
+		"push not supported": {
+			pushSecret:    makePushSecret(),
+			readOnly:      true,
+			expectStatus:  v1.ConditionFalse,
+			expectMessage: "store SecretStore/backend does not support pushing secrets",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pushed := make(map[string]string)
+			fakeProvider.Reset()
+			fakeProvider.WithPushSecret(func(ctx context.Context, value []byte, remoteKey string) error {
+				pushed[remoteKey] = string(value)
+				return nil
+			})
+			if tc.pushSecretFn != nil {
+				fakeProvider.WithPushSecret(tc.pushSecretFn)
+			}
+			if tc.readOnly {
+				fakeProvider.WithNew(func(context.Context, esv1beta1.GenericStore, client.Client, string) (esv1beta1.SecretsClient, error) {
+					return &readOnlyClient{fakeProvider}, nil
+				})
+			}
+			r := newReconciler(tc.pushSecret)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: psName, Namespace: psNs}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ps esv1alpha1.PushSecret
+			if err := r.Get(context.Background(), req.NamespacedName, &ps); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			cond := GetPushSecretCondition(ps.Status, esv1alpha1.PushSecretReady)
+			if cond == nil || cond.Status != tc.expectStatus || !strings.Contains(cond.Message, tc.expectMessage) {
+				t.Fatalf("unexpected condition: %+v", cond)
+			}
+			if tc.expectPushed != nil && !reflect.DeepEqual(pushed, tc.expectPushed) {
+				t.Errorf("unexpected pushed data: %v", pushed)
+			}
+			if !reflect.DeepEqual(ps.Status.SyncedPushSecrets, tc.expectSynced) {
+				t.Errorf("unexpected synced push secrets: %v", ps.Status.SyncedPushSecrets)
+			}
+		})
+	}
+}
+
+func TestReconcileSkipsRefresh(t *testing.T) {
+	var pushes int
+	fakeProvider.Reset()
+	fakeProvider.WithPushSecret(func(context.Context, []byte, string) error {
+		pushes++
+		return nil
+	})
+	r := newReconciler(makePushSecret())
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: psName, Namespace: psNs}}
+	for i := 0; i < 2; i++ {
+		res, err := r.Reconcile(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.RequeueAfter != time.Hour {
+			t.Errorf("unexpected requeue after %v", res.RequeueAfter)
+		}
+	}
+	if pushes != 1 {
+		t.Errorf("expected the secret to be pushed once within the refresh interval, got %d pushes", pushes)
+	}
+}
+
+func TestReconcileErrorBackoff(t *testing.T) {
+	pushErr := errors.New("unavailable")
+	fakeProvider.Reset()
+	fakeProvider.WithPushSecret(func(context.Context, []byte, string) error {
+		return pushErr
+	})
+	r := newReconciler(makePushSecret())
+	r.ErrorBackoffBase = 5 * time.Second
+	r.ErrorBackoffMax = 15 * time.Second
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: psName, Namespace: psNs}}
+	var ps esv1alpha1.PushSecret
+	for i, want := range []time.Duration{5 * time.Second, 10 * time.Second, 15 * time.Second, 15 * time.Second} {
+		res, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.RequeueAfter != want {
+			t.Errorf("unexpected requeue after failure %d: expected %v, got %v", i+1, want, res.RequeueAfter)
+		}
+		if err := r.Get(ctx, req.NamespacedName, &ps); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ps.Status.FailedSyncs != int32(i+1) || ps.Status.NextRetryTime == nil {
+			t.Errorf("unexpected status after failure %d: %d failed syncs, next retry at %v", i+1, ps.Status.FailedSyncs, ps.Status.NextRetryTime)
+		}
+	}
+
+	// a successful push resets the backoff.
+	pushErr = nil
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.RequeueAfter != time.Hour {
+		t.Errorf("unexpected requeue after %v", res.RequeueAfter)
+	}
+	if err := r.Get(ctx, req.NamespacedName, &ps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ps.Status.FailedSyncs != 0 || ps.Status.NextRetryTime != nil {
+		t.Errorf("unexpected status after success: %d failed syncs, next retry at %v", ps.Status.FailedSyncs, ps.Status.NextRetryTime)
+	}
+}
+
+func TestReconcileSourceSecretChanged(t *testing.T) {
+	var pushed []string
+	fakeProvider.Reset()
+	fakeProvider.WithPushSecret(func(ctx context.Context, value []byte, remoteKey string) error {
+		pushed = append(pushed, string(value))
+		return nil
+	})
+	// the source secret is pushed on changes even without refresh interval.
+	r := newReconciler(makePushSecret(func(ps *esv1alpha1.PushSecret) {
+		ps.Spec.RefreshInterval = &metav1.Duration{}
+	}))
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: psName, Namespace: psNs}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var secret v1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: "db-credentials", Namespace: psNs}, &secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests := r.findObjectsForSecret(&secret); len(requests) != 1 || requests[0] != req {
+		t.Errorf("unexpected requests for source secret: %v", requests)
+	}
+	secret.Data["password"] = []byte("rotated")
+	if err := r.Update(ctx, &secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !reflect.DeepEqual(pushed, []string{"s3cr3t", "rotated"}) {
+		t.Errorf("expected the changed source secret to be pushed once, got %v", pushed)
+	}
+}
+
+func TestReconcileDeletionPolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy        esv1alpha1.PushSecretDeletionPolicy
+		expectDeleted []string
+	}{
+		"delete": {
+			policy:        esv1alpha1.PushSecretDeletionPolicyDelete,
+			expectDeleted: []string{"db/old", "db/password"},
+		},
+		"retain": {
+			policy: esv1alpha1.PushSecretDeletionPolicyRetain,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			fakeProvider.Reset()
+			fakeProvider.WithPushSecret(func(context.Context, []byte, string) error {
+				return nil
+			})
+			fakeProvider.WithDeleteSecret(func(ctx context.Context, remoteKey string) error {
+				deleted = append(deleted, remoteKey)
+				return nil
+			})
+			r := newReconciler(makePushSecret(func(ps *esv1alpha1.PushSecret) {
+				ps.Spec.DeletionPolicy = tc.policy
+				ps.Status.SyncedPushSecrets = esv1alpha1.SyncedPushSecretsMap{
+					"SecretStore/backend": {
+						"db/old": {Match: esv1alpha1.PushSecretMatch{SecretKey: "password", RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db/old"}}},
+					},
+				}
+			}))
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: psName, Namespace: psNs}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ps esv1alpha1.PushSecret
+			if err := r.Get(context.Background(), req.NamespacedName, &ps); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hasFinalizer := len(ps.Finalizers) > 0; hasFinalizer != (tc.policy == esv1alpha1.PushSecretDeletionPolicyDelete) {
+				t.Errorf("unexpected finalizers: %v", ps.Finalizers)
+			}
+			if err := r.Delete(context.Background(), &ps); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := r.Get(context.Background(), req.NamespacedName, &ps); !apierrors.IsNotFound(err) {
+				t.Errorf("expected PushSecret to be deleted, got %v", err)
+			}
+			if !reflect.DeepEqual(deleted, tc.expectDeleted) {
+				t.Errorf("unexpected deleted secrets: %v", deleted)
+			}
+		})
+	}
+}
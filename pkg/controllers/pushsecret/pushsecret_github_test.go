@@ -0,0 +1,141 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/nacl/box"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+
+	// the github provider is exercised through the controller.
+	_ "github.com/external-secrets/external-secrets/pkg/provider/github"
+)
+
+// newFakeGithub serves the Actions secrets API of the repository example/app
+// and keeps the decrypted secrets by name.
+func newFakeGithub(t *testing.T) (*httptest.Server, *sync.Map) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var secrets sync.Map
+	const prefix = "/repos/example/app/actions/secrets/"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghp_test" || !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+		switch {
+		case name == "public-key":
+			_ = json.NewEncoder(w).Encode(map[string]string{"key_id": "1", "key": base64.StdEncoding.EncodeToString(public[:])})
+		case r.Method == http.MethodPut:
+			var req struct {
+				EncryptedValue string `json:"encrypted_value"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			sealed, _ := base64.StdEncoding.DecodeString(req.EncryptedValue)
+			plain, ok := box.OpenAnonymous(nil, sealed, public, private)
+			if !ok {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				return
+			}
+			secrets.Store(name, string(plain))
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			if _, ok := secrets.LoadAndDelete(name); !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &secrets
+}
+
+func TestReconcileGithub(t *testing.T) {
+	srv, secrets := newFakeGithub(t)
+	r := newReconciler(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "github", Namespace: psNs},
+			Data:       map[string][]byte{"token": []byte("ghp_test")},
+		},
+		&esv1beta1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "github", Namespace: psNs},
+			Spec: esv1beta1.SecretStoreSpec{Provider: &esv1beta1.SecretStoreProvider{
+				Github: &esv1beta1.GithubProvider{
+					URL:        srv.URL,
+					Owner:      "example",
+					Repository: "app",
+					Auth:       esv1beta1.GithubAuth{Token: &esmeta.SecretKeySelector{Name: "github", Key: "token"}},
+				},
+			}},
+		},
+		makePushSecret(func(ps *esv1alpha1.PushSecret) {
+			ps.Spec.DeletionPolicy = esv1alpha1.PushSecretDeletionPolicyDelete
+			ps.Spec.SecretStoreRefs = []esv1alpha1.PushSecretStoreRef{{Name: "github", Kind: esv1beta1.SecretStoreKind}}
+			ps.Spec.Data[0].Match.RemoteRef.RemoteKey = "DB_PASSWORD"
+		}),
+	)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: psName, Namespace: psNs}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value, _ := secrets.Load("DB_PASSWORD"); value != "s3cr3t" {
+		t.Errorf("unexpected pushed secret: %v", value)
+	}
+	var ps esv1alpha1.PushSecret
+	if err := r.Get(ctx, req.NamespacedName, &ps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := ps.Status.SyncedPushSecrets["SecretStore/github"]["DB_PASSWORD"]; !ok {
+		t.Errorf("unexpected synced secrets: %v", ps.Status.SyncedPushSecrets)
+	}
+
+	if err := r.Delete(ctx, &ps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := secrets.Load("DB_PASSWORD"); ok {
+		t.Errorf("pushed secret was not deleted")
+	}
+	if err := r.Get(ctx, req.NamespacedName, &ps); !apierrors.IsNotFound(err) {
+		t.Errorf("expected PushSecret to be deleted, got %v", err)
+	}
+}
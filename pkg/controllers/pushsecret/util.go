@@ -0,0 +1,68 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+// NewPushSecretCondition a set of default options for creating a PushSecret Condition.
+func NewPushSecretCondition(condType esv1alpha1.PushSecretConditionType, status v1.ConditionStatus, reason, message string) *esv1alpha1.PushSecretStatusCondition {
+	return &esv1alpha1.PushSecretStatusCondition{
+		Type:               condType,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// GetPushSecretCondition returns the condition with the provided type.
+func GetPushSecretCondition(status esv1alpha1.PushSecretStatus, condType esv1alpha1.PushSecretConditionType) *esv1alpha1.PushSecretStatusCondition {
+	for i := range status.Conditions {
+		c := status.Conditions[i]
+		if c.Type == condType {
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetPushSecretCondition updates the PushSecret to include the provided condition.
+func SetPushSecretCondition(ps *esv1alpha1.PushSecret, condition esv1alpha1.PushSecretStatusCondition) {
+	currentCond := GetPushSecretCondition(ps.Status, condition.Type)
+
+	// Do not update lastTransitionTime if the status of the condition doesn't change.
+	if currentCond != nil && currentCond.Status == condition.Status {
+		condition.LastTransitionTime = currentCond.LastTransitionTime
+	}
+
+	ps.Status.Conditions = append(filterOutCondition(ps.Status.Conditions, condition.Type), condition)
+}
+
+// filterOutCondition returns an empty set of conditions with the provided type.
+func filterOutCondition(conditions []esv1alpha1.PushSecretStatusCondition, condType esv1alpha1.PushSecretConditionType) []esv1alpha1.PushSecretStatusCondition {
+	newConditions := make([]esv1alpha1.PushSecretStatusCondition, 0, len(conditions))
+	for _, c := range conditions {
+		if c.Type == condType {
+			continue
+		}
+		newConditions = append(newConditions, c)
+	}
+	return newConditions
+}
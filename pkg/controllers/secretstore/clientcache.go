@@ -0,0 +1,303 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errCloseCachedClient = "could not close cached provider client"
+)
+
+var (
+	secretKeySelectorType = reflect.TypeOf(esmeta.SecretKeySelector{})
+	caProviderType        = reflect.TypeOf(esapi.CAProvider{})
+	secretGVK             = v1.SchemeGroupVersion.WithKind("Secret")
+	configMapGVK          = v1.SchemeGroupVersion.WithKind("ConfigMap")
+)
+
+// ClientCache shares the provider clients of the stores between reconciles,
+// so that a client and its authentication are not created again on every reconcile.
+// A client is replaced when the spec of its store, one of the secrets referenced by
+// the store or its CA bundle changed. It is evicted once it was cached for the ttl,
+// so that it picks up expiring credentials and changes of other resources,
+// e.g. service accounts.
+// A nil ClientCache creates a new client on every call.
+type ClientCache struct {
+	log     logr.Logger
+	ttl     time.Duration
+	mu      sync.Mutex
+	clients map[clientSlot]*cachedClient
+}
+
+// clientSlot identifies the client of a store for a namespace.
+type clientSlot struct {
+	store     types.UID
+	namespace string
+}
+
+type cachedClient struct {
+	client esapi.SecretsClient
+	// version is the generation of the store and the hash of its credentials at creation.
+	version string
+	created time.Time
+	// expiry evicts the client once the ttl passed.
+	expiry *time.Timer
+	// refs is the number of callers which use the client.
+	refs    int
+	evicted bool
+}
+
+// NewClientCache returns a ClientCache which keeps clients for up to ttl.
+// A ttl of 0 or less disables the cache.
+func NewClientCache(log logr.Logger, ttl time.Duration) *ClientCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &ClientCache{
+		log:     log,
+		ttl:     ttl,
+		clients: make(map[clientSlot]*cachedClient),
+	}
+}
+
+// NewClient returns a provider client of the store for the namespace.
+// The client must not be closed by the caller, the returned release func must
+// be called instead once the client is no longer used. It closes the client
+// unless the client is cached.
+func (c *ClientCache) NewClient(ctx context.Context, store esapi.GenericStore, kube client.Client, namespace string) (esapi.SecretsClient, func(context.Context) error, error) {
+	provider, err := esapi.GetProvider(store)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c == nil {
+		cl, err := newProviderClient(ctx, provider, store, kube, namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cl, cl.Close, nil
+	}
+
+	// the client is not cached if the credentials of the store can't be read.
+	version, err := storeVersion(ctx, store, kube, namespace)
+	if err != nil {
+		cl, err := newProviderClient(ctx, provider, store, kube, namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cl, cl.Close, nil
+	}
+	slot := clientSlot{store: store.GetUID(), namespace: namespace}
+	if cached := c.acquire(slot, version); cached != nil {
+		return cached.client, c.releaseFunc(cached), nil
+	}
+	cl, err := newProviderClient(ctx, provider, store, kube, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	cached := c.add(ctx, slot, &cachedClient{client: cl, version: version, created: time.Now()})
+	return cached.client, c.releaseFunc(cached), nil
+}
+
+// newProviderClient creates a client of the provider. The creation counts against the rate
+// limits of the store and its provider, as most clients authenticate to the provider.
+func newProviderClient(ctx context.Context, provider esapi.Provider, store esapi.GenericStore, kube client.Client, namespace string) (esapi.SecretsClient, error) {
+	if err := waitRateLimit(ctx, store, esapi.GetProviderName(store)); err != nil {
+		return nil, err
+	}
+	return provider.NewClient(ctx, store, kube, namespace)
+}
+
+// acquire returns the cached client of the slot if it is still valid.
+func (c *ClientCache) acquire(slot clientSlot, version string) *cachedClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.clients[slot]
+	if !ok || cached.version != version || time.Since(cached.created) > c.ttl {
+		return nil
+	}
+	cached.refs++
+	return cached
+}
+
+// add caches the client in the slot and evicts the client it replaces.
+// If another caller cached an up to date client of the slot in the meantime,
+// that client is returned instead.
+func (c *ClientCache) add(ctx context.Context, slot clientSlot, cached *cachedClient) *cachedClient {
+	c.mu.Lock()
+	prior, ok := c.clients[slot]
+	if ok && prior.version == cached.version && time.Since(prior.created) <= c.ttl {
+		prior.refs++
+		c.mu.Unlock()
+		c.closeClient(ctx, cached.client)
+		return prior
+	}
+	var closing bool
+	if ok {
+		closing = c.evict(slot, prior)
+	}
+	cached.refs++
+	cached.expiry = time.AfterFunc(c.ttl, func() {
+		c.expire(slot, cached)
+	})
+	c.clients[slot] = cached
+	c.mu.Unlock()
+	if closing {
+		c.closeClient(ctx, prior.client)
+	}
+	return cached
+}
+
+// expire evicts the client of the slot once its ttl passed,
+// so that unused clients don't outlive the ttl.
+func (c *ClientCache) expire(slot clientSlot, cached *cachedClient) {
+	c.mu.Lock()
+	closing := c.clients[slot] == cached && c.evict(slot, cached)
+	c.mu.Unlock()
+	if closing {
+		c.closeClient(context.Background(), cached.client)
+	}
+}
+
+// evict removes the client from the slot and reports whether it must be closed,
+// which is the case if no caller uses it. c.mu must be held.
+func (c *ClientCache) evict(slot clientSlot, cached *cachedClient) bool {
+	delete(c.clients, slot)
+	cached.evicted = true
+	if cached.expiry != nil {
+		cached.expiry.Stop()
+	}
+	return cached.refs == 0
+}
+
+// releaseFunc returns a func that releases the cached client,
+// an evicted client is closed once it is released by all callers.
+func (c *ClientCache) releaseFunc(cached *cachedClient) func(context.Context) error {
+	var once sync.Once
+	return func(ctx context.Context) error {
+		var err error
+		once.Do(func() {
+			c.mu.Lock()
+			cached.refs--
+			closing := cached.evicted && cached.refs == 0
+			c.mu.Unlock()
+			if closing {
+				err = cached.client.Close(ctx)
+			}
+		})
+		return err
+	}
+}
+
+func (c *ClientCache) closeClient(ctx context.Context, cl esapi.SecretsClient) {
+	if err := cl.Close(ctx); err != nil {
+		c.log.Error(err, errCloseCachedClient)
+	}
+}
+
+// storeVersion returns the generation of the store and a hash of the resource versions
+// of the secrets and the CA bundle referenced by the store, so that a client is replaced
+// once the store or its credentials changed.
+func storeVersion(ctx context.Context, store esapi.GenericStore, kube client.Client, namespace string) (string, error) {
+	_, isClusterStore := store.(*esapi.ClusterSecretStore)
+	refs := storeRefs(reflect.ValueOf(store.GetSpec().Provider), nil)
+	versions := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		key := types.NamespacedName{Namespace: namespace, Name: ref.name}
+		if isClusterStore && ref.namespace != nil {
+			key.Namespace = *ref.namespace
+		}
+		var obj metav1.PartialObjectMetadata
+		obj.SetGroupVersionKind(ref.gvk)
+		err := kube.Get(ctx, key, &obj)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		// a missing object fails the creation of the client, so that it isn't cached.
+		versions = append(versions, fmt.Sprintf("%s/%s=%s", ref.gvk.Kind, key, obj.ResourceVersion))
+	}
+	sort.Strings(versions)
+	return fmt.Sprintf("%d-%s", store.GetGeneration(), utils.ObjectHash(strings.Join(versions, ","))), nil
+}
+
+// storeRef is a secret or config map referenced by a store.
+type storeRef struct {
+	gvk       schema.GroupVersionKind
+	name      string
+	namespace *string
+}
+
+// storeRefs returns the secret selectors and CA providers in the value.
+func storeRefs(v reflect.Value, refs []storeRef) []storeRef {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			refs = storeRefs(v.Elem(), refs)
+		}
+	case reflect.Struct:
+		switch v.Type() {
+		case secretKeySelectorType:
+			if ref := v.Interface().(esmeta.SecretKeySelector); ref.Name != "" {
+				refs = append(refs, storeRef{gvk: secretGVK, name: ref.Name, namespace: ref.Namespace})
+			}
+			return refs
+		case caProviderType:
+			ref := v.Interface().(esapi.CAProvider)
+			gvk := secretGVK
+			if ref.Type == esapi.CAProviderTypeConfigMap {
+				gvk = configMapGVK
+			}
+			if ref.Name != "" {
+				refs = append(refs, storeRef{gvk: gvk, name: ref.Name, namespace: ref.Namespace})
+			}
+			return refs
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				refs = storeRefs(v.Field(i), refs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			refs = storeRefs(v.Index(i), refs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			refs = storeRefs(iter.Value(), refs)
+		}
+	default:
+	}
+	return refs
+}
@@ -0,0 +1,244 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+// countingClient counts the times it was closed.
+type countingClient struct {
+	*fake.Client
+	closed int
+}
+
+func (c *countingClient) Close(ctx context.Context) error {
+	c.closed++
+	return nil
+}
+
+// closingClient signals when it is closed.
+type closingClient struct {
+	*fake.Client
+	closed chan struct{}
+}
+
+func (c *closingClient) Close(ctx context.Context) error {
+	c.closed <- struct{}{}
+	return nil
+}
+
+func TestClientCache(t *testing.T) {
+	var created []*countingClient
+	provider := fake.New()
+	provider.WithNew(func(context.Context, esapi.GenericStore, client.Client, string) (esapi.SecretsClient, error) {
+		c := &countingClient{Client: provider}
+		created = append(created, c)
+		return c, nil
+	})
+	provider.RegisterAs(&esapi.SecretStoreProvider{AWS: &esapi.AWSProvider{}})
+
+	credentials := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"id": []byte("id"), "secret": []byte("secret")},
+	}
+	serviceAccount := &v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "aws-auth", Namespace: "default"}}
+	kube := fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(credentials, serviceAccount).Build()
+	store := &esapi.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "default", UID: "store-uid", Generation: 1},
+		Spec: esapi.SecretStoreSpec{Provider: &esapi.SecretStoreProvider{AWS: &esapi.AWSProvider{
+			Auth: esapi.AWSAuth{
+				SecretRef: &esapi.AWSAuthSecretRef{
+					AccessKeyID:     esmeta.SecretKeySelector{Name: credentials.Name, Key: "id"},
+					SecretAccessKey: esmeta.SecretKeySelector{Name: credentials.Name, Key: "secret"},
+				},
+				JWTAuth: &esapi.AWSJWTAuth{
+					ServiceAccountRef: &esmeta.ServiceAccountSelector{Name: serviceAccount.Name},
+				},
+			},
+		}}},
+	}
+	ctx := context.Background()
+	newClient := func(cache *ClientCache) esapi.SecretsClient {
+		t.Helper()
+		cl, release, err := cache.NewClient(ctx, store, kube, "default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := release(ctx); err != nil {
+			t.Fatalf("unexpected error releasing client: %v", err)
+		}
+		return cl
+	}
+
+	// without cache every call creates and closes a client.
+	newClient(nil)
+	newClient(nil)
+	if len(created) != 2 || created[0].closed != 1 || created[1].closed != 1 {
+		t.Fatalf("expected two closed clients without cache, got %d", len(created))
+	}
+
+	created = nil
+	cache := NewClientCache(logr.Discard(), time.Hour)
+	first := newClient(cache)
+	if newClient(cache) != first || len(created) != 1 {
+		t.Fatalf("expected cached client to be reused, got %d clients", len(created))
+	}
+	if created[0].closed != 0 {
+		t.Errorf("expected cached client not to be closed")
+	}
+
+	// a changed store replaces the client.
+	store.Generation = 2
+	if newClient(cache) == first {
+		t.Fatalf("expected new client after store changed")
+	}
+	if created[0].closed != 1 {
+		t.Errorf("expected replaced client to be closed")
+	}
+
+	// rotating the credentials of the store replaces the client.
+	credentials.Data["secret"] = []byte("rotated")
+	if err := kube.Update(ctx, credentials); err != nil {
+		t.Fatalf("unexpected error updating credentials: %v", err)
+	}
+	if newClient(cache) != created[2] || len(created) != 3 {
+		t.Fatalf("expected new client after credentials were rotated, got %d clients", len(created))
+	}
+	if created[1].closed != 1 {
+		t.Errorf("expected client with rotated credentials to be closed")
+	}
+
+	// other referenced resources are picked up once the ttl passed.
+	serviceAccount.Labels = map[string]string{"rotated": "true"}
+	if err := kube.Update(ctx, serviceAccount); err != nil {
+		t.Fatalf("unexpected error updating service account: %v", err)
+	}
+	if newClient(cache) != created[2] || len(created) != 3 {
+		t.Errorf("expected cached client to be reused until the ttl passed, got %d clients", len(created))
+	}
+
+	// an evicted client is closed once it is released.
+	inUse, release, err := cache.NewClient(ctx, store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.Generation = 3
+	newClient(cache)
+	if inUse.(*countingClient).closed != 0 {
+		t.Errorf("expected client in use not to be closed")
+	}
+	if err := release(ctx); err != nil {
+		t.Fatalf("unexpected error releasing client: %v", err)
+	}
+	if inUse.(*countingClient).closed != 1 {
+		t.Errorf("expected evicted client to be closed after release")
+	}
+
+	// an expired client is replaced.
+	cache.ttl = 0
+	created = nil
+	newClient(cache)
+	if len(created) != 1 {
+		t.Errorf("expected new client after ttl passed, got %d clients", len(created))
+	}
+}
+
+func TestClientCacheExpiry(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	provider := fake.New()
+	provider.WithNew(func(context.Context, esapi.GenericStore, client.Client, string) (esapi.SecretsClient, error) {
+		return &closingClient{Client: provider, closed: closed}, nil
+	})
+	provider.RegisterAs(&esapi.SecretStoreProvider{AWS: &esapi.AWSProvider{}})
+	kube := fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	store := &esapi.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "expiring", Namespace: "default", UID: "expiring-uid", Generation: 1},
+		Spec:       esapi.SecretStoreSpec{Provider: &esapi.SecretStoreProvider{AWS: &esapi.AWSProvider{}}},
+	}
+	cache := NewClientCache(logr.Discard(), 10*time.Millisecond)
+	_, release, err := cache.NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := release(context.Background()); err != nil {
+		t.Fatalf("unexpected error releasing client: %v", err)
+	}
+
+	// an unused client is closed once its ttl passed, without another lookup.
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("expected expired client to be closed")
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if len(cache.clients) != 0 {
+		t.Errorf("expected expired client to be evicted, got %d clients", len(cache.clients))
+	}
+}
+
+func TestClientCacheRateLimit(t *testing.T) {
+	provider := fake.New()
+	provider.RegisterAs(&esapi.SecretStoreProvider{AWS: &esapi.AWSProvider{}})
+	kube := fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	store := &esapi.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "rate-limited", Namespace: "default", UID: "rate-limited-uid", Generation: 1},
+		Spec: esapi.SecretStoreSpec{
+			Provider:  &esapi.SecretStoreProvider{AWS: &esapi.AWSProvider{}},
+			RateLimit: &esapi.SecretStoreRateLimit{QPS: 1},
+		},
+	}
+	defer forgetStoreRateLimit(store.Namespace, store.Name)
+	newClient := func(cache *ClientCache) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, release, err := cache.NewClient(ctx, store, kube, "default")
+		if err == nil {
+			err = release(ctx)
+		}
+		return err
+	}
+
+	// every created client counts against the rate limit of the store.
+	if err := newClient(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := newClient(nil); err == nil {
+		t.Fatalf("expected the creation of a client to be rate limited")
+	}
+
+	// a cached client is reused without waiting for the rate limit.
+	forgetStoreRateLimit(store.Namespace, store.Name)
+	cache := NewClientCache(logr.Discard(), time.Hour)
+	for i := 0; i < 3; i++ {
+		if err := newClient(cache); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
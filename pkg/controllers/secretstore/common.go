@@ -20,6 +20,9 @@ import (
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +38,8 @@ const (
 	errUnableCreateClient  = "unable to create client"
 	errUnableValidateStore = "unable to validate store"
 	errUnableGetProvider   = "unable to get store provider"
+	errNamespaceSelector   = "invalid namespaceSelector in conditions: %w"
+	errGetNamespace        = "could not get namespace %s: %w"
 
 	msgStoreValidated = "store validated"
 )
@@ -84,7 +89,7 @@ func validateStore(ctx context.Context, namespace string, store esapi.GenericSto
 		return fmt.Errorf(errStoreProvider, err)
 	}
 
-	cl, err := storeProvider.NewClient(ctx, store, client, namespace)
+	cl, err := newProviderClient(ctx, storeProvider, store, client, namespace)
 	if err != nil {
 		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonInvalidProviderConfig, errUnableCreateClient)
 		SetExternalSecretCondition(store, *cond)
@@ -112,3 +117,48 @@ func ShouldProcessStore(store esapi.GenericStore, class string) bool {
 
 	return false
 }
+
+// IsNamespaceAllowed returns true if ExternalSecrets of the namespace may use the store.
+// Only the conditions of a ClusterSecretStore restrict namespaces, a namespace is allowed
+// if it is listed in or selected by any of the conditions.
+func IsNamespaceAllowed(ctx context.Context, cl client.Client, store esapi.GenericStore, namespace string) (bool, error) {
+	if _, ok := store.(*esapi.ClusterSecretStore); !ok {
+		return true, nil
+	}
+	return NamespaceMatchesConditions(ctx, cl, store.GetSpec().Conditions, namespace)
+}
+
+// NamespaceMatchesConditions returns true if the namespace is listed in or selected by
+// any of the conditions of a cluster-scoped resource, or if there are no conditions.
+func NamespaceMatchesConditions(ctx context.Context, cl client.Client, conditions []esapi.ClusterSecretStoreCondition, namespace string) (bool, error) {
+	if len(conditions) == 0 {
+		return true, nil
+	}
+	for _, condition := range conditions {
+		for _, name := range condition.Namespaces {
+			if name == namespace {
+				return true, nil
+			}
+		}
+	}
+	var ns *v1.Namespace
+	for _, condition := range conditions {
+		if condition.NamespaceSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(condition.NamespaceSelector)
+		if err != nil {
+			return false, fmt.Errorf(errNamespaceSelector, err)
+		}
+		if ns == nil {
+			ns = &v1.Namespace{}
+			if err := cl.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+				return false, fmt.Errorf(errGetNamespace, namespace, err)
+			}
+		}
+		if selector.Matches(labels.Set(ns.GetLabels())) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
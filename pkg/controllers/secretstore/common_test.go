@@ -15,13 +15,17 @@ package secretstore
 
 import (
 	"context"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -211,3 +215,58 @@ func hasEvent(involvedKind, name, reason string) bool {
 	}
 	return false
 }
+
+func TestIsNamespaceAllowed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "search", Labels: map[string]string{"team": "search"}}},
+	).Build()
+	conditions := []esapi.ClusterSecretStoreCondition{
+		{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}},
+		{Namespaces: []string{"ops"}},
+	}
+	tests := map[string]struct {
+		store     esapi.GenericStore
+		namespace string
+		want      bool
+	}{
+		"store without conditions": {
+			store:     &esapi.ClusterSecretStore{},
+			namespace: "search",
+			want:      true,
+		},
+		"selected namespace": {
+			store:     &esapi.ClusterSecretStore{Spec: esapi.SecretStoreSpec{Conditions: conditions}},
+			namespace: "payments",
+			want:      true,
+		},
+		"listed namespace": {
+			store:     &esapi.ClusterSecretStore{Spec: esapi.SecretStoreSpec{Conditions: conditions}},
+			namespace: "ops",
+			want:      true,
+		},
+		"denied namespace": {
+			store:     &esapi.ClusterSecretStore{Spec: esapi.SecretStoreSpec{Conditions: conditions}},
+			namespace: "search",
+			want:      false,
+		},
+		"conditions of a SecretStore are ignored": {
+			store:     &esapi.SecretStore{Spec: esapi.SecretStoreSpec{Conditions: conditions}},
+			namespace: "search",
+			want:      true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := IsNamespaceAllowed(context.Background(), kube, tc.store, tc.namespace)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected result: expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
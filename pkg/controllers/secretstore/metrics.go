@@ -0,0 +1,73 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	ProviderSubsystem      = "provider"
+	ProviderAPICallsKey    = "api_calls_total"
+	ProviderAPIDurationKey = "api_call_duration_seconds"
+	providerCallSuccess    = "success"
+	providerCallNotFound   = "not_found"
+	providerCallError      = "error"
+	CallGetSecret          = "GetSecret"
+	CallGetSecretMap       = "GetSecretMap"
+	CallGetAllSecrets      = "GetAllSecrets"
+	CallPushSecret         = "PushSecret"
+	CallDeleteSecret       = "DeleteSecret"
+)
+
+var (
+	providerAPICalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ProviderSubsystem,
+		Name:      ProviderAPICallsKey,
+		Help:      "Total number of the provider API calls by provider, call and status",
+	}, []string{"provider", "call", "status"})
+
+	providerAPIDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: ProviderSubsystem,
+		Name:      ProviderAPIDurationKey,
+		Help:      "The duration of the provider API calls by provider and call",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "call"})
+)
+
+// observeCall calls fn and records the call and its duration in the provider metrics.
+func observeCall(provider, call string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	providerAPIDuration.With(prometheus.Labels{"provider": provider, "call": call}).Observe(time.Since(start).Seconds())
+	status := providerCallSuccess
+	if errors.Is(err, esapi.NoSecretErr) {
+		status = providerCallNotFound
+	} else if err != nil {
+		status = providerCallError
+	}
+	providerAPICalls.With(prometheus.Labels{"provider": provider, "call": call, "status": status}).Inc()
+	return err
+}
+
+func init() {
+	metrics.Registry.MustRegister(providerAPICalls, providerAPIDuration)
+}
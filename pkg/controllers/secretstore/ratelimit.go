@@ -0,0 +1,125 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+var (
+	rateLimitMu sync.Mutex
+	// providerRateLimit is the global rate limit of every provider, disabled if qps is 0.
+	providerRateLimit rateLimit
+	// providerLimiters holds the limiters of the providers by provider name.
+	providerLimiters = map[string]*limiter{}
+	// storeLimiters holds the limiters of the stores by namespaced name.
+	storeLimiters = map[string]*limiter{}
+)
+
+type rateLimit struct {
+	qps   float32
+	burst int
+}
+
+type limiter struct {
+	rateLimit
+	flowcontrol.RateLimiter
+}
+
+// SetProviderRateLimit sets the rate limit that applies to the requests of every provider,
+// summed up over all stores of the provider. A qps of 0 disables the limit.
+// The burst defaults to the qps.
+func SetProviderRateLimit(qps float32, burst int) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	if burst <= 0 {
+		burst = int(qps)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	providerRateLimit = rateLimit{qps: qps, burst: burst}
+	providerLimiters = map[string]*limiter{}
+}
+
+// waitRateLimit blocks until the rate limits of the store and its provider allow a request.
+func waitRateLimit(ctx context.Context, store esapi.GenericStore, provider string) error {
+	if l := storeLimiter(store); l != nil {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l := providerLimiter(provider); l != nil {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeLimiter returns the limiter of the store or nil if the store is not rate limited.
+// The limiter is replaced when the rate limit of the store changed.
+func storeLimiter(store esapi.GenericStore) *limiter {
+	key := store.GetNamespacedName()
+	spec := store.GetSpec().RateLimit
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	if spec == nil || spec.QPS <= 0 {
+		delete(storeLimiters, key)
+		return nil
+	}
+	limit := rateLimit{qps: float32(spec.QPS), burst: int(spec.QPS)}
+	if spec.Burst != nil && *spec.Burst > 0 {
+		limit.burst = int(*spec.Burst)
+	}
+	return getLimiter(storeLimiters, key, limit)
+}
+
+// providerLimiter returns the global limiter of the provider or nil if providers are not rate limited.
+func providerLimiter(provider string) *limiter {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	if providerRateLimit.qps <= 0 {
+		return nil
+	}
+	return getLimiter(providerLimiters, provider, providerRateLimit)
+}
+
+// getLimiter returns the limiter of the key, a new limiter is created if there is none
+// or the limit changed. rateLimitMu must be held.
+func getLimiter(limiters map[string]*limiter, key string, limit rateLimit) *limiter {
+	if l, ok := limiters[key]; ok && l.rateLimit == limit {
+		return l
+	}
+	l := &limiter{
+		rateLimit:   limit,
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(limit.qps, limit.burst),
+	}
+	limiters[key] = l
+	return l
+}
+
+// forgetStoreRateLimit removes the limiter of a deleted store.
+func forgetStoreRateLimit(namespace, name string) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	delete(storeLimiters, fmt.Sprintf("%s/%s", namespace, name))
+}
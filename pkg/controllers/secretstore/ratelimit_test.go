@@ -0,0 +1,102 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestRateLimit(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	tests := map[string]struct {
+		rateLimit     *esapi.SecretStoreRateLimit
+		providerQPS   float32
+		providerBurst int
+		wantAllowed   int
+	}{
+		"no rate limit": {
+			wantAllowed: 5,
+		},
+		"store rate limit": {
+			rateLimit:   &esapi.SecretStoreRateLimit{QPS: 1, Burst: int32Ptr(2)},
+			wantAllowed: 2,
+		},
+		"store burst defaults to qps": {
+			rateLimit:   &esapi.SecretStoreRateLimit{QPS: 3},
+			wantAllowed: 3,
+		},
+		"provider rate limit": {
+			providerQPS:   1,
+			providerBurst: 2,
+			wantAllowed:   2,
+		},
+		"lower limit applies": {
+			rateLimit:     &esapi.SecretStoreRateLimit{QPS: 1, Burst: int32Ptr(3)},
+			providerQPS:   1,
+			providerBurst: 1,
+			wantAllowed:   1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			SetProviderRateLimit(tc.providerQPS, tc.providerBurst)
+			defer SetProviderRateLimit(0, 0)
+			store := &esapi.SecretStore{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+				Spec:       esapi.SecretStoreSpec{RateLimit: tc.rateLimit},
+			}
+			defer forgetStoreRateLimit(store.Namespace, store.Name)
+			var allowed int
+			for i := 0; i < 5; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				err := waitRateLimit(ctx, store, "fake")
+				cancel()
+				if err != nil {
+					break
+				}
+				allowed++
+			}
+			if allowed != tc.wantAllowed {
+				t.Errorf("unexpected allowed requests: expected %d, got %d", tc.wantAllowed, allowed)
+			}
+		})
+	}
+}
+
+func TestStoreLimiterUpdate(t *testing.T) {
+	store := &esapi.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "update"},
+		Spec:       esapi.SecretStoreSpec{RateLimit: &esapi.SecretStoreRateLimit{QPS: 1}},
+	}
+	defer forgetStoreRateLimit(store.Namespace, store.Name)
+	first := storeLimiter(store)
+	if storeLimiter(store) != first {
+		t.Errorf("expected limiter to be reused")
+	}
+	store.Spec.RateLimit.QPS = 2
+	if storeLimiter(store) == first {
+		t.Errorf("expected limiter to be replaced after the rate limit changed")
+	}
+	store.Spec.RateLimit = nil
+	if storeLimiter(store) != nil {
+		t.Errorf("expected no limiter without rate limit")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	defaultMaxRetries        = 3
+	defaultRetryInterval     = 5 * time.Second
+	defaultBackoffMultiplier = 1
+)
+
+// the retries block the reconcile worker, so the waits are capped.
+// Longer outages are left to the requeue backoff of the controllers.
+var (
+	maxRetryInterval = 10 * time.Second
+	maxRetryDuration = 30 * time.Second
+)
+
+// Retry calls fn until it succeeds or the retrySettings of the store are exhausted.
+// Every attempt waits for the rate limits of the store and its provider
+// and is recorded as the given call in the provider metrics.
+// The first retry waits retryInterval, every further retry waits backoffMultiplier
+// times longer, up to maxRetryInterval. Retries stop once they would wait longer
+// than maxRetryDuration in total. Errors of missing secrets are not retried.
+// Without retrySettings, or if client retries failed requests itself, fn is called once.
+func Retry(ctx context.Context, store esapi.GenericStore, client esapi.SecretsClient, call string, fn func() error) error {
+	provider := esapi.GetProviderName(store)
+	attempt := func() error {
+		if err := waitRateLimit(ctx, store, provider); err != nil {
+			return err
+		}
+		return observeCall(provider, call, fn)
+	}
+	spec := store.GetSpec()
+	if spec.RetrySettings == nil || retriesInternally(client) {
+		return attempt()
+	}
+	maxRetries, interval, multiplier := retryParams(spec.RetrySettings)
+	var waited time.Duration
+	err := attempt()
+	for i := 0; i < maxRetries && err != nil && !errors.Is(err, esapi.NoSecretErr); i++ {
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+		if waited+interval > maxRetryDuration {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+		waited += interval
+		interval *= time.Duration(multiplier)
+		err = attempt()
+	}
+	return err
+}
+
+// retriesInternally returns true if client retries failed requests itself.
+func retriesInternally(client esapi.SecretsClient) bool {
+	r, ok := client.(esapi.InternalRetrier)
+	return ok && r.RetriesInternally()
+}
+
+// retryParams returns the retry settings with defaults for unset fields.
+func retryParams(settings *esapi.SecretStoreRetrySettings) (int, time.Duration, int) {
+	maxRetries := defaultMaxRetries
+	if settings.MaxRetries != nil {
+		maxRetries = int(*settings.MaxRetries)
+	}
+	interval := defaultRetryInterval
+	if settings.RetryInterval != nil {
+		// the interval is validated by the webhook.
+		if d, err := time.ParseDuration(*settings.RetryInterval); err == nil {
+			interval = d
+		}
+	}
+	multiplier := defaultBackoffMultiplier
+	if settings.BackoffMultiplier != nil && *settings.BackoffMultiplier > 1 {
+		multiplier = int(*settings.BackoffMultiplier)
+	}
+	return maxRetries, interval, multiplier
+}
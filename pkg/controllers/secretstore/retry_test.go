@@ -0,0 +1,129 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// retrierClient is a SecretsClient that retries failed requests itself.
+type retrierClient struct {
+	esapi.SecretsClient
+}
+
+func (retrierClient) RetriesInternally() bool {
+	return true
+}
+
+func TestRetry(t *testing.T) {
+	defer func(interval, duration time.Duration) {
+		maxRetryInterval, maxRetryDuration = interval, duration
+	}(maxRetryInterval, maxRetryDuration)
+	maxRetryInterval, maxRetryDuration = 5*time.Millisecond, 20*time.Millisecond
+	errTransient := errors.New("connection reset")
+	int32Ptr := func(i int32) *int32 { return &i }
+	strPtr := func(s string) *string { return &s }
+	tests := map[string]struct {
+		settings  *esapi.SecretStoreRetrySettings
+		client    esapi.SecretsClient
+		failures  int
+		err       error
+		wantCalls int
+		wantErr   error
+	}{
+		"no retry settings": {
+			failures:  1,
+			err:       errTransient,
+			wantCalls: 1,
+			wantErr:   errTransient,
+		},
+		"succeeds after retries": {
+			settings:  &esapi.SecretStoreRetrySettings{MaxRetries: int32Ptr(3), RetryInterval: strPtr("1ms"), BackoffMultiplier: int32Ptr(2)},
+			failures:  2,
+			err:       errTransient,
+			wantCalls: 3,
+		},
+		"retries exhausted": {
+			settings:  &esapi.SecretStoreRetrySettings{MaxRetries: int32Ptr(2), RetryInterval: strPtr("1ms")},
+			failures:  5,
+			err:       errTransient,
+			wantCalls: 3,
+			wantErr:   errTransient,
+		},
+		"missing secret is not retried": {
+			settings:  &esapi.SecretStoreRetrySettings{MaxRetries: int32Ptr(3), RetryInterval: strPtr("1ms")},
+			failures:  5,
+			err:       esapi.NoSecretErr,
+			wantCalls: 1,
+			wantErr:   esapi.NoSecretErr,
+		},
+		"retries are capped": {
+			// waits 1ms, 5ms, 5ms and 5ms, the next wait exceeds the max retry duration.
+			settings:  &esapi.SecretStoreRetrySettings{MaxRetries: int32Ptr(10), RetryInterval: strPtr("1ms"), BackoffMultiplier: int32Ptr(10)},
+			failures:  20,
+			err:       errTransient,
+			wantCalls: 5,
+			wantErr:   errTransient,
+		},
+		"client retries itself": {
+			settings:  &esapi.SecretStoreRetrySettings{MaxRetries: int32Ptr(3), RetryInterval: strPtr("1ms")},
+			client:    retrierClient{},
+			failures:  5,
+			err:       errTransient,
+			wantCalls: 1,
+			wantErr:   errTransient,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := &esapi.SecretStore{
+				Spec: esapi.SecretStoreSpec{RetrySettings: tc.settings},
+			}
+			var calls int
+			// the test name is used as call to tell the metrics of the tests apart.
+			err := Retry(context.Background(), store, tc.client, name, func() error {
+				calls++
+				if calls <= tc.failures {
+					return tc.err
+				}
+				return nil
+			})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("unexpected error: expected %v, got %v", tc.wantErr, err)
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("unexpected calls: expected %d, got %d", tc.wantCalls, calls)
+			}
+			var recorded float64
+			for _, status := range []string{providerCallSuccess, providerCallNotFound, providerCallError} {
+				var metric dto.Metric
+				if err := providerAPICalls.WithLabelValues(esapi.GetProviderName(store), name, status).Write(&metric); err != nil {
+					t.Fatalf("unexpected error writing metric: %v", err)
+				}
+				recorded += metric.GetCounter().GetValue()
+			}
+			if recorded != float64(tc.wantCalls) {
+				t.Errorf("unexpected recorded calls: expected %d, got %v", tc.wantCalls, recorded)
+			}
+		})
+	}
+}
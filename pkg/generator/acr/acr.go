@@ -0,0 +1,298 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	kvauth "github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/generator/internal/satoken"
+)
+
+// Generator exchanges Azure Active Directory tokens for ACR refresh or access tokens.
+type Generator struct{}
+
+const (
+	// defaultUsername is the username ACR expects for refresh and access tokens.
+	defaultUsername      = "00000000-0000-0000-0000-000000000000"
+	azureDefaultAudience = "api://AzureADTokenExchange"
+	annotationClientID   = "azure.workload.identity/client-id"
+	annotationTenantID   = "azure.workload.identity/tenant-id"
+
+	errNoSpec                 = "no config spec provided"
+	errParseSpec              = "unable to parse spec: %w"
+	errMissingRegistry        = "no registry provided"
+	errMissingAuth            = "no auth provided: one of servicePrincipal, managedIdentity or workloadIdentity is required"
+	errMissingTenant          = "missing tenantID"
+	errMissingWorkloadEnvVars = "missing environment variables: AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE must be set"
+	errReadTokenFile          = "unable to read token file %s: %w"
+	errMissingSAAnnotation    = "missing service account annotation: %s"
+	errFindSecret             = "could not find secret %s/%s: %w"
+	errFindDataKey            = "no data for %q in secret '%s/%s'"
+	errGetAADToken            = "unable to get azure active directory token: %w"
+	errExchangeToken          = "unable to exchange token at %s: %w"
+	errUnexpectedStatus       = "unexpected status code %d: %s"
+	errEmptyToken             = "empty token returned"
+)
+
+type aadTokenFunc func(ctx context.Context, spec genv1alpha1.ACRAccessTokenSpec, kube client.Client, namespace string) (string, error)
+
+// Generate returns a refresh token of the registry of the ACRAccessToken resource jsonSpec,
+// or an access token if the resource defines a scope.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, fetchAADToken, http.DefaultClient)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, aadToken aadTokenFunc, httpClient *http.Client) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	if res.Spec.ACRRegistry == "" {
+		return nil, errors.New(errMissingRegistry)
+	}
+	accessToken, err := aadToken(ctx, res.Spec, kube, namespace)
+	if err != nil {
+		return nil, fmt.Errorf(errGetAADToken, err)
+	}
+	token, err := fetchACRRefreshToken(ctx, httpClient, accessToken, res.Spec.TenantID, res.Spec.ACRRegistry)
+	if err != nil {
+		return nil, err
+	}
+	if res.Spec.Scope != "" {
+		token, err = fetchACRAccessToken(ctx, httpClient, token, res.Spec.ACRRegistry, res.Spec.Scope)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return map[string][]byte{
+		"username": []byte(defaultUsername),
+		"password": []byte(token),
+	}, nil
+}
+
+// fetchACRRefreshToken exchanges an Azure Active Directory access token for an ACR refresh token.
+func fetchACRRefreshToken(ctx context.Context, httpClient *http.Client, accessToken, tenantID, registry string) (string, error) {
+	form := url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {registry},
+		"access_token": {accessToken},
+	}
+	if tenantID != "" {
+		form.Set("tenant", tenantID)
+	}
+	var res struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := postForm(ctx, httpClient, fmt.Sprintf("https://%s/oauth2/exchange", registry), form, &res); err != nil {
+		return "", err
+	}
+	return res.RefreshToken, nil
+}
+
+// fetchACRAccessToken exchanges an ACR refresh token for an access token with the given scope.
+func fetchACRAccessToken(ctx context.Context, httpClient *http.Client, refreshToken, registry, scope string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"service":       {registry},
+		"scope":         {scope},
+		"refresh_token": {refreshToken},
+	}
+	var res struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := postForm(ctx, httpClient, fmt.Sprintf("https://%s/oauth2/token", registry), form, &res); err != nil {
+		return "", err
+	}
+	return res.AccessToken, nil
+}
+
+func postForm(ctx context.Context, httpClient *http.Client, endpoint string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(errExchangeToken, endpoint, fmt.Errorf(errUnexpectedStatus, resp.StatusCode, string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	return nil
+}
+
+// fetchAADToken returns an Azure Active Directory access token for the Azure Resource Manager
+// with the auth of spec.
+func fetchAADToken(ctx context.Context, spec genv1alpha1.ACRAccessTokenSpec, kube client.Client, namespace string) (string, error) {
+	switch {
+	case spec.Auth.ServicePrincipal != nil:
+		return servicePrincipalToken(ctx, spec, kube, namespace)
+	case spec.Auth.ManagedIdentity != nil:
+		cfg := kvauth.NewMSIConfig()
+		cfg.Resource = azure.PublicCloud.ResourceManagerEndpoint
+		cfg.ClientID = spec.Auth.ManagedIdentity.IdentityID
+		spt, err := cfg.ServicePrincipalToken()
+		if err != nil {
+			return "", err
+		}
+		return refreshToken(ctx, spt)
+	case spec.Auth.WorkloadIdentity != nil:
+		return workloadIdentityToken(ctx, spec, kube, namespace)
+	}
+	return "", errors.New(errMissingAuth)
+}
+
+func servicePrincipalToken(ctx context.Context, spec genv1alpha1.ACRAccessTokenSpec, kube client.Client, namespace string) (string, error) {
+	if spec.TenantID == "" {
+		return "", errors.New(errMissingTenant)
+	}
+	cid, err := secretKeyRef(ctx, kube, namespace, spec.Auth.ServicePrincipal.SecretRef.ClientID)
+	if err != nil {
+		return "", err
+	}
+	csec, err := secretKeyRef(ctx, kube, namespace, spec.Auth.ServicePrincipal.SecretRef.ClientSecret)
+	if err != nil {
+		return "", err
+	}
+	cfg := kvauth.NewClientCredentialsConfig(cid, csec, spec.TenantID)
+	cfg.Resource = azure.PublicCloud.ResourceManagerEndpoint
+	spt, err := cfg.ServicePrincipalToken()
+	if err != nil {
+		return "", err
+	}
+	return refreshToken(ctx, spt)
+}
+
+func refreshToken(ctx context.Context, spt *adal.ServicePrincipalToken) (string, error) {
+	if err := spt.RefreshWithContext(ctx); err != nil {
+		return "", err
+	}
+	token := spt.OAuthToken()
+	if token == "" {
+		return "", errors.New(errEmptyToken)
+	}
+	return token, nil
+}
+
+func workloadIdentityToken(ctx context.Context, spec genv1alpha1.ACRAccessTokenSpec, kube client.Client, namespace string) (string, error) {
+	var clientID, tenantID, token string
+	saRef := spec.Auth.WorkloadIdentity.ServiceAccountRef
+	// if no serviceAccountRef was provided
+	// we expect certain env vars to be present.
+	// They are set by the azure workload identity webhook.
+	if saRef == nil {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+		tenantID = os.Getenv("AZURE_TENANT_ID")
+		tokenFilePath := os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+		if clientID == "" || tenantID == "" || tokenFilePath == "" {
+			return "", errors.New(errMissingWorkloadEnvVars)
+		}
+		raw, err := os.ReadFile(tokenFilePath)
+		if err != nil {
+			return "", fmt.Errorf(errReadTokenFile, tokenFilePath, err)
+		}
+		token = string(raw)
+	} else {
+		var sa corev1.ServiceAccount
+		err := kube.Get(ctx, types.NamespacedName{Name: saRef.Name, Namespace: namespace}, &sa)
+		if err != nil {
+			return "", err
+		}
+		var ok bool
+		clientID, ok = sa.ObjectMeta.Annotations[annotationClientID]
+		if !ok {
+			return "", fmt.Errorf(errMissingSAAnnotation, annotationClientID)
+		}
+		tenantID, ok = sa.ObjectMeta.Annotations[annotationTenantID]
+		if !ok {
+			return "", fmt.Errorf(errMissingSAAnnotation, annotationTenantID)
+		}
+		token, err = satoken.Fetch(ctx, namespace, saRef.Name, azureDefaultAudience)
+		if err != nil {
+			return "", err
+		}
+	}
+	cred, err := confidential.NewCredFromAssertion(token)
+	if err != nil {
+		return "", err
+	}
+	cClient, err := confidential.New(clientID, cred, confidential.WithAuthority(
+		fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/token", tenantID),
+	))
+	if err != nil {
+		return "", err
+	}
+	authRes, err := cClient.AcquireTokenByCredential(ctx, []string{
+		azure.PublicCloud.ResourceManagerEndpoint + ".default",
+	})
+	if err != nil {
+		return "", err
+	}
+	return authRes.AccessToken, nil
+}
+
+func secretKeyRef(ctx context.Context, kube client.Client, namespace string, ref smmeta.SecretKeySelector) (string, error) {
+	var secret corev1.Secret
+	err := kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &secret)
+	if err != nil {
+		return "", fmt.Errorf(errFindSecret, namespace, ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf(errFindDataKey, ref.Key, namespace, ref.Name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.ACRAccessToken, error) {
+	var spec genv1alpha1.ACRAccessToken
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.ACRAccessTokenKind, &Generator{})
+}
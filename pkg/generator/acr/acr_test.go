@@ -0,0 +1,113 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+func TestGenerate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch {
+		case r.URL.Path == "/oauth2/exchange" && r.Form.Get("access_token") == "aad-token":
+			_, _ = w.Write([]byte(`{"refresh_token":"refresh-token"}`))
+		case r.URL.Path == "/oauth2/token" && r.Form.Get("refresh_token") == "refresh-token" && r.Form.Get("scope") == "repository:app:pull":
+			_, _ = w.Write([]byte(`{"access_token":"access-token"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"UNAUTHORIZED"}]}`))
+		}
+	}))
+	defer srv.Close()
+	registry := strings.TrimPrefix(srv.URL, "https://")
+
+	tests := map[string]struct {
+		spec    string
+		aadErr  error
+		want    map[string][]byte
+		wantErr string
+	}{
+		"refresh token": {
+			spec: `{"spec":{"registry":"` + registry + `"}}`,
+			want: map[string][]byte{
+				"username": []byte(defaultUsername),
+				"password": []byte("refresh-token"),
+			},
+		},
+		"scoped access token": {
+			spec: `{"spec":{"registry":"` + registry + `","scope":"repository:app:pull"}}`,
+			want: map[string][]byte{
+				"username": []byte(defaultUsername),
+				"password": []byte("access-token"),
+			},
+		},
+		"unauthorized scope": {
+			spec:    `{"spec":{"registry":"` + registry + `","scope":"repository:other:pull"}}`,
+			wantErr: "unexpected status code 401",
+		},
+		"missing registry": {
+			spec:    `{"spec":{}}`,
+			wantErr: errMissingRegistry,
+		},
+		"aad error": {
+			spec:    `{"spec":{"registry":"` + registry + `"}}`,
+			aadErr:  errors.New("invalid client secret"),
+			wantErr: "invalid client secret",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.generate(context.Background(), &apiextensions.JSON{Raw: []byte(tc.spec)}, nil, "default",
+				func(context.Context, genv1alpha1.ACRAccessTokenSpec, client.Client, string) (string, error) {
+					return "aad-token", tc.aadErr
+				}, srv.Client())
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+		})
+	}
+}
+
+func TestFetchAADTokenWithoutAuth(t *testing.T) {
+	_, err := fetchAADToken(context.Background(), genv1alpha1.ACRAccessTokenSpec{}, nil, "default")
+	if err == nil || err.Error() != errMissingAuth {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudsmith
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/generator/internal/satoken"
+)
+
+// Generator exchanges OIDC tokens for API tokens of Cloudsmith service accounts.
+type Generator struct{}
+
+const (
+	defaultAPIURL   = "https://api.cloudsmith.io"
+	defaultRegistry = "docker.cloudsmith.io"
+
+	errNoSpec           = "no config spec provided"
+	errParseSpec        = "unable to parse spec: %w"
+	errMissingSlug      = "orgSlug and serviceSlug must be set"
+	errInvalidURL       = "invalid url %q: %w"
+	errInvalidAuth      = "exactly one of serviceAccountRef or secretRef must be set"
+	errFindSecret       = "could not find secret %s/%s: %w"
+	errFindDataKey      = "no data for %q in secret '%s/%s'"
+	errFetchSAToken     = "unable to fetch service account token: %w"
+	errExchangeToken    = "unable to exchange token at %s: %w"
+	errUnexpectedStatus = "unexpected status code %d: %s"
+	errEmptyToken       = "empty token returned"
+)
+
+type saTokenFunc func(ctx context.Context, namespace, name, audience string) (string, error)
+
+// Generate returns an API token of the service account of the CloudsmithAccessToken
+// resource jsonSpec as username and password for the Cloudsmith registry.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, satoken.Fetch, http.DefaultClient)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, saToken saTokenFunc, httpClient *http.Client) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	spec := res.Spec
+	if spec.OrgSlug == "" || spec.ServiceSlug == "" {
+		return nil, errors.New(errMissingSlug)
+	}
+	apiURL, err := parseURL(spec.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	var oidcToken string
+	auth := spec.Auth
+	switch {
+	case auth.ServiceAccountRef != nil && auth.SecretRef == nil:
+		oidcToken, err = saToken(ctx, namespace, auth.ServiceAccountRef.Name, apiURL.Host)
+		if err != nil {
+			return nil, fmt.Errorf(errFetchSAToken, err)
+		}
+	case auth.SecretRef != nil && auth.ServiceAccountRef == nil:
+		oidcToken, err = secretKeyRef(ctx, kube, namespace, auth.SecretRef.Name, auth.SecretRef.Key)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New(errInvalidAuth)
+	}
+	token, err := exchangeToken(ctx, httpClient, apiURL, spec.OrgSlug, spec.ServiceSlug, oidcToken)
+	if err != nil {
+		return nil, err
+	}
+	registry := spec.Registry
+	if registry == "" {
+		registry = defaultRegistry
+	}
+	return map[string][]byte{
+		"registry": []byte(registry),
+		"username": []byte(spec.ServiceSlug),
+		"password": []byte(token),
+	}, nil
+}
+
+// parseURL returns the URL of the Cloudsmith API, https is used if no scheme is set.
+func parseURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		raw = defaultAPIURL
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidURL, raw, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf(errInvalidURL, raw, errors.New("missing host"))
+	}
+	return u, nil
+}
+
+// exchangeToken exchanges the OIDC token for an API token of the service account
+// with the OpenID Connect endpoint of the organization.
+func exchangeToken(ctx context.Context, httpClient *http.Client, apiURL *url.URL, org, service, oidcToken string) (string, error) {
+	endpoint := strings.TrimSuffix(apiURL.String(), "/") + "/openid/" + url.PathEscape(org) + "/"
+	payload, err := json.Marshal(map[string]string{
+		"oidc_token":   oidcToken,
+		"service_slug": service,
+	})
+	if err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf(errExchangeToken, endpoint, fmt.Errorf(errUnexpectedStatus, resp.StatusCode, string(body)))
+	}
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	if out.Token == "" {
+		return "", errors.New(errEmptyToken)
+	}
+	return out.Token, nil
+}
+
+func secretKeyRef(ctx context.Context, kube client.Client, namespace, name, key string) (string, error) {
+	var secret corev1.Secret
+	err := kube.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &secret)
+	if err != nil {
+		return "", fmt.Errorf(errFindSecret, namespace, name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf(errFindDataKey, key, namespace, name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.CloudsmithAccessToken, error) {
+	var spec genv1alpha1.CloudsmithAccessToken
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.CloudsmithAccessTokenKind, &Generator{})
+}
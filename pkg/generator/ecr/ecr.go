@@ -0,0 +1,126 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecr
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	awsauth "github.com/external-secrets/external-secrets/pkg/provider/aws/auth"
+)
+
+// Generator requests authorization tokens of ECR registries.
+type Generator struct{}
+
+const (
+	errNoSpec          = "no config spec provided"
+	errParseSpec       = "unable to parse spec: %w"
+	errCreateSess      = "unable to create aws session: %w"
+	errGetToken        = "unable to get authorization token: %w"
+	errNoToken         = "no authorization data returned"
+	errDecodeToken     = "unable to decode authorization token: %w"
+	errUnexpectedToken = "unexpected authorization token format"
+)
+
+type ecrFactoryFunc func(*session.Session) ecriface.ECRAPI
+
+// Generate returns the username, password and proxy endpoint of the registry
+// of the ECRAuthorizationToken resource jsonSpec.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, ecrFactory)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, ecrFunc ecrFactoryFunc) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	sess, err := awsauth.NewGeneratorSession(ctx, res.Spec.Auth, res.Spec.Role, res.Spec.Region, kube, namespace, awsauth.DefaultSTSProvider, awsauth.DefaultJWTProvider)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateSess, err)
+	}
+	out, err := ecrFunc(sess).GetAuthorizationTokenWithContext(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return nil, fmt.Errorf(errGetToken, err)
+	}
+	if len(out.AuthorizationData) == 0 {
+		return nil, errors.New(errNoToken)
+	}
+	data := out.AuthorizationData[0]
+	decoded, err := base64.StdEncoding.DecodeString(stringValue(data.AuthorizationToken))
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeToken, err)
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New(errUnexpectedToken)
+	}
+	var expiresAt string
+	if data.ExpiresAt != nil {
+		expiresAt = strconv.FormatInt(data.ExpiresAt.Unix(), 10)
+	}
+	return map[string][]byte{
+		"username":       []byte(parts[0]),
+		"password":       []byte(parts[1]),
+		"proxy_endpoint": []byte(stringValue(data.ProxyEndpoint)),
+		"expires_at":     []byte(expiresAt),
+	}, nil
+}
+
+func ecrFactory(sess *session.Session) ecriface.ECRAPI {
+	return ecr.New(sess)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Expiry returns the expiration of the authorization token in data.
+func (g *Generator) Expiry(data map[string][]byte) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(string(data["expires_at"]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func parseSpec(data []byte) (*genv1alpha1.ECRAuthorizationToken, error) {
+	var spec genv1alpha1.ECRAuthorizationToken
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.ECRAuthorizationTokenKind, &Generator{})
+}
@@ -0,0 +1,107 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecr
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeECR struct {
+	ecriface.ECRAPI
+	out *ecr.GetAuthorizationTokenOutput
+	err error
+}
+
+func (f *fakeECR) GetAuthorizationTokenWithContext(aws.Context, *ecr.GetAuthorizationTokenInput, ...request.Option) (*ecr.GetAuthorizationTokenOutput, error) {
+	return f.out, f.err
+}
+
+func TestGenerate(t *testing.T) {
+	expiresAt := time.Unix(1700000000, 0)
+	token := base64.StdEncoding.EncodeToString([]byte("AWS:s3cr3t"))
+	tests := map[string]struct {
+		spec    *apiextensions.JSON
+		out     *ecr.GetAuthorizationTokenOutput
+		err     error
+		want    map[string][]byte
+		wantErr bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"token": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1"}}`)},
+			out: &ecr.GetAuthorizationTokenOutput{
+				AuthorizationData: []*ecr.AuthorizationData{{
+					AuthorizationToken: aws.String(token),
+					ProxyEndpoint:      aws.String("https://123456789012.dkr.ecr.eu-west-1.amazonaws.com"),
+					ExpiresAt:          &expiresAt,
+				}},
+			},
+			want: map[string][]byte{
+				"username":       []byte("AWS"),
+				"password":       []byte("s3cr3t"),
+				"proxy_endpoint": []byte("https://123456789012.dkr.ecr.eu-west-1.amazonaws.com"),
+				"expires_at":     []byte("1700000000"),
+			},
+		},
+		"api error": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1"}}`)},
+			err:     errors.New("access denied"),
+			wantErr: true,
+		},
+		"no authorization data": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1"}}`)},
+			out:     &ecr.GetAuthorizationTokenOutput{},
+			wantErr: true,
+		},
+		"invalid token": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1"}}`)},
+			out: &ecr.GetAuthorizationTokenOutput{
+				AuthorizationData: []*ecr.AuthorizationData{{
+					AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("nocolon"))),
+				}},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.generate(context.Background(), tc.spec, clientfake.NewClientBuilder().Build(), "default", func(*session.Session) ecriface.ECRAPI {
+				return &fakeECR{out: tc.out, err: tc.err}
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+		})
+	}
+}
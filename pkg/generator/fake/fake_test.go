@@ -0,0 +1,62 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		spec    *apiextensions.JSON
+		want    map[string][]byte
+		wantErr bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"invalid spec": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"data":["foo"]}}`)},
+			wantErr: true,
+		},
+		"no data": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{}}`)},
+			want: map[string][]byte{},
+		},
+		"static data": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{"data":{"username":"foo","password":"bar"}}}`)},
+			want: map[string][]byte{
+				"username": []byte("foo"),
+				"password": []byte("bar"),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.Generate(context.Background(), tc.spec, nil, "default")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+		})
+	}
+}
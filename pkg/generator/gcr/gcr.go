@@ -0,0 +1,107 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/oauth2"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/provider/gcp/secretmanager"
+)
+
+// Generator requests access tokens for GCR and Artifact Registry.
+type Generator struct{}
+
+const (
+	// defaultUsername is the username docker registries of GCP expect for access tokens.
+	defaultUsername = "oauth2accesstoken"
+
+	errNoSpec          = "no config spec provided"
+	errParseSpec       = "unable to parse spec: %w"
+	errGetTokenSource  = "unable to get token source: %w"
+	errGetToken        = "unable to get access token: %w"
+	errMissingProject  = "no projectID provided"
+	errEmptyTokenValue = "empty access token returned"
+)
+
+type tokenSourceFunc func(ctx context.Context, auth esv1beta1.GCPSMAuth, projectID string, kube client.Client, namespace string) (oauth2.TokenSource, error)
+
+// Generate returns an access token of the GCRAccessToken resource jsonSpec
+// as username and password for docker registries.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, secretmanager.NewTokenSource)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, tokenSource tokenSourceFunc) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	if res.Spec.ProjectID == "" {
+		return nil, errors.New(errMissingProject)
+	}
+	ts, err := tokenSource(ctx, res.Spec.Auth, res.Spec.ProjectID, kube, namespace)
+	if err != nil {
+		return nil, fmt.Errorf(errGetTokenSource, err)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return nil, fmt.Errorf(errGetToken, err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New(errEmptyTokenValue)
+	}
+	var expiry string
+	if !token.Expiry.IsZero() {
+		expiry = strconv.FormatInt(token.Expiry.Unix(), 10)
+	}
+	return map[string][]byte{
+		"username": []byte(defaultUsername),
+		"password": []byte(token.AccessToken),
+		"expiry":   []byte(expiry),
+	}, nil
+}
+
+// Expiry returns the expiration of the access token in data.
+func (g *Generator) Expiry(data map[string][]byte) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(string(data["expiry"]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func parseSpec(data []byte) (*genv1alpha1.GCRAccessToken, error) {
+	var spec genv1alpha1.GCRAccessToken
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.GCRAccessTokenKind, &Generator{})
+}
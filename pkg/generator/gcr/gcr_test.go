@@ -0,0 +1,94 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+type fakeTokenSource struct {
+	token *oauth2.Token
+	err   error
+}
+
+func (f *fakeTokenSource) Token() (*oauth2.Token, error) {
+	return f.token, f.err
+}
+
+func TestGenerate(t *testing.T) {
+	expiry := time.Unix(1700000000, 0)
+	spec := &apiextensions.JSON{Raw: []byte(`{"spec":{"projectID":"my-project"}}`)}
+	tests := map[string]struct {
+		spec     *apiextensions.JSON
+		token    *oauth2.Token
+		tokenErr error
+		want     map[string][]byte
+		wantErr  bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"missing project": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{}}`)},
+			wantErr: true,
+		},
+		"access token": {
+			spec:  spec,
+			token: &oauth2.Token{AccessToken: "ya29.token", Expiry: expiry},
+			want: map[string][]byte{
+				"username": []byte("oauth2accesstoken"),
+				"password": []byte("ya29.token"),
+				"expiry":   []byte("1700000000"),
+			},
+		},
+		"token error": {
+			spec:     spec,
+			tokenErr: errors.New("permission denied"),
+			wantErr:  true,
+		},
+		"empty token": {
+			spec:    spec,
+			token:   &oauth2.Token{},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.generate(context.Background(), tc.spec, nil, "default", func(ctx context.Context, auth esv1beta1.GCPSMAuth, projectID string, kube client.Client, namespace string) (oauth2.TokenSource, error) {
+				if projectID != "my-project" {
+					t.Errorf("unexpected project: %s", projectID)
+				}
+				return &fakeTokenSource{token: tc.token, err: tc.tokenErr}, nil
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+		})
+	}
+}
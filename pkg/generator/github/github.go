@@ -0,0 +1,190 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// Generator creates installation access tokens of GitHub Apps.
+type Generator struct{}
+
+const (
+	defaultURL = "https://api.github.com/"
+	// jwtLifetime is the lifetime of the JWT of the app, GitHub allows at most 10 minutes.
+	jwtLifetime = 9 * time.Minute
+	// jwtClockDrift is subtracted from the issue time to allow for clock drift.
+	jwtClockDrift = time.Minute
+
+	errNoSpec           = "no config spec provided"
+	errParseSpec        = "unable to parse spec: %w"
+	errMissingAppID     = "no appID provided"
+	errMissingInstallID = "no installID provided"
+	errFindSecret       = "could not find secret %s/%s: %w"
+	errFindDataKey      = "no data for %q in secret '%s/%s'"
+	errParseKey         = "unable to parse private key: %w"
+	errSignJWT          = "unable to sign app token: %w"
+	errCreateToken      = "unable to create installation token: %w"
+	errUnexpectedStatus = "unexpected status code %d: %s"
+	errEmptyToken       = "empty token returned"
+)
+
+// Generate returns an installation access token of the GithubAccessToken resource jsonSpec.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, http.DefaultClient)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, httpClient *http.Client) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	if res.Spec.AppID == "" {
+		return nil, errors.New(errMissingAppID)
+	}
+	if res.Spec.InstallID == "" {
+		return nil, errors.New(errMissingInstallID)
+	}
+	appToken, err := signAppToken(ctx, res.Spec, kube, namespace)
+	if err != nil {
+		return nil, err
+	}
+	token, expiresAt, err := createInstallationToken(ctx, httpClient, res.Spec, appToken)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateToken, err)
+	}
+	var expiry string
+	if !expiresAt.IsZero() {
+		expiry = strconv.FormatInt(expiresAt.Unix(), 10)
+	}
+	return map[string][]byte{
+		"token":      []byte(token),
+		"expires_at": []byte(expiry),
+	}, nil
+}
+
+// signAppToken returns a JWT to authenticate as the app, signed with the private key of the app.
+func signAppToken(ctx context.Context, spec genv1alpha1.GithubAccessTokenSpec, kube client.Client, namespace string) (string, error) {
+	ref := spec.Auth.PrivateKey.SecretRef
+	var secret corev1.Secret
+	err := kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &secret)
+	if err != nil {
+		return "", fmt.Errorf(errFindSecret, namespace, ref.Name, err)
+	}
+	pemKey, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf(errFindDataKey, ref.Key, namespace, ref.Name)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pemKey)
+	if err != nil {
+		return "", fmt.Errorf(errParseKey, err)
+	}
+	now := time.Now()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    spec.AppID,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-jwtClockDrift)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(jwtLifetime)),
+	}).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf(errSignJWT, err)
+	}
+	return signed, nil
+}
+
+// createInstallationToken creates an installation access token restricted to the repositories
+// and permissions of spec.
+func createInstallationToken(ctx context.Context, httpClient *http.Client, spec genv1alpha1.GithubAccessTokenSpec, appToken string) (string, time.Time, error) {
+	baseURL := spec.URL
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	endpoint := fmt.Sprintf("%s/app/installations/%s/access_tokens", strings.TrimSuffix(baseURL, "/"), spec.InstallID)
+	reqBody, err := json.Marshal(struct {
+		Repositories []string          `json:"repositories,omitempty"`
+		Permissions  map[string]string `json:"permissions,omitempty"`
+	}{spec.Repositories, spec.Permissions})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+appToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf(errUnexpectedStatus, resp.StatusCode, string(body))
+	}
+	var out struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", time.Time{}, err
+	}
+	if out.Token == "" {
+		return "", time.Time{}, errors.New(errEmptyToken)
+	}
+	return out.Token, out.ExpiresAt, nil
+}
+
+// Expiry returns the expiration of the installation token in data.
+func (g *Generator) Expiry(data map[string][]byte) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(string(data["expires_at"]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func parseSpec(data []byte) (*genv1alpha1.GithubAccessToken, error) {
+	var spec genv1alpha1.GithubAccessToken
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.GithubAccessTokenKind, &Generator{})
+}
@@ -0,0 +1,125 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), claims, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || claims.Issuer != "123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Repositories []string          `json:"repositories"`
+			Permissions  map[string]string `json:"permissions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || (body.Repositories != nil && body.Repositories[0] != "my-repo") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"ghs_generated","expires_at":"2023-11-14T22:13:20Z"}`))
+	}))
+	defer srv.Close()
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "github-app", Namespace: "default"},
+		Data:       map[string][]byte{"key": pemKey, "invalid": []byte("not a key")},
+	}).Build()
+	spec := func(installID, key, extra string) *apiextensions.JSON {
+		return &apiextensions.JSON{Raw: []byte(`{"spec":{"url":"` + srv.URL + `","appID":"123","installID":"` + installID + `",` +
+			`"auth":{"privateKey":{"secretRef":{"name":"github-app","key":"` + key + `"}}}` + extra + `}}`)}
+	}
+	token := map[string][]byte{
+		"token":      []byte("ghs_generated"),
+		"expires_at": []byte("1700000000"),
+	}
+	tests := map[string]struct {
+		spec    *apiextensions.JSON
+		want    map[string][]byte
+		wantErr bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"missing app id": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"installID":"42"}}`)},
+			wantErr: true,
+		},
+		"installation token": {
+			spec: spec("42", "key", ""),
+			want: token,
+		},
+		"scoped token": {
+			spec: spec("42", "key", `,"repositories":["my-repo"],"permissions":{"contents":"read"}`),
+			want: token,
+		},
+		"invalid private key": {
+			spec:    spec("42", "invalid", ""),
+			wantErr: true,
+		},
+		"missing secret key": {
+			spec:    spec("42", "missing", ""),
+			wantErr: true,
+		},
+		"unknown installation": {
+			spec:    spec("7", "key", ""),
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.generate(context.Background(), tc.spec, kube, "default", srv.Client())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+		})
+	}
+}
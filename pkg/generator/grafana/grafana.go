@@ -0,0 +1,333 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// Generator creates service account tokens of Grafana.
+type Generator struct{}
+
+const (
+	defaultRole = "Viewer"
+	tokenPrefix = "external-secrets-"
+
+	errNoSpec            = "no config spec provided"
+	errParseSpec         = "unable to parse spec: %w"
+	errMissingURL        = "no url provided"
+	errMissingSA         = "no service account name provided"
+	errInvalidAuth       = "exactly one of token or basic auth must be set"
+	errFindSecret        = "could not find secret %s/%s: %w"
+	errFindDataKey       = "no data for %q in secret '%s/%s'"
+	errGetServiceAccount = "unable to get service account %s: %w"
+	errCreateToken       = "unable to create token: %w"
+	errDeleteToken       = "unable to delete token %d: %w"
+	errEncodeState       = "unable to encode state: %w"
+	errParseState        = "unable to parse state: %w"
+	errRequest           = "%s %s: %w"
+	errUnexpectedStatus  = "unexpected status code %d: %s"
+	errEmptyToken        = "empty token returned"
+)
+
+// httpClient sends the requests to Grafana.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// tokenState is the generator state of a token, so that it is deleted once it is no longer used.
+type tokenState struct {
+	ServiceAccountID int64 `json:"serviceAccountID"`
+	TokenID          int64 `json:"tokenID"`
+}
+
+// Generate creates a new token of the service account of the Grafana resource jsonSpec.
+// The token is not deleted, see GenerateWithState.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	data, _, err := g.generate(ctx, jsonSpec, kube, namespace, httpClient)
+	return data, err
+}
+
+// GenerateWithState works like Generate, the state holds the token
+// so that it is deleted once it is no longer used.
+func (g *Generator) GenerateWithState(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, *apiextensions.JSON, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, httpClient)
+}
+
+// Cleanup deletes the token recorded in state.
+func (g *Generator) Cleanup(ctx context.Context, jsonSpec, state *apiextensions.JSON, kube client.Client, namespace string) error {
+	return g.cleanup(ctx, jsonSpec, state, kube, namespace, httpClient)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, httpClient *http.Client) (map[string][]byte, *apiextensions.JSON, error) {
+	res, c, err := newSpecClient(ctx, jsonSpec, kube, namespace, httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	spec := res.Spec
+	saID, err := c.ensureServiceAccount(ctx, spec.ServiceAccount)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errGetServiceAccount, spec.ServiceAccount.Name, err)
+	}
+	var ttl time.Duration
+	if spec.TokenTTL != nil {
+		ttl = spec.TokenTTL.Duration
+	}
+	name, err := tokenName()
+	if err != nil {
+		return nil, nil, fmt.Errorf(errCreateToken, err)
+	}
+	token, err := c.createToken(ctx, saID, name, ttl)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errCreateToken, err)
+	}
+	raw, err := json.Marshal(tokenState{ServiceAccountID: saID, TokenID: token.ID})
+	if err != nil {
+		return nil, nil, fmt.Errorf(errEncodeState, err)
+	}
+	return map[string][]byte{
+		"token": []byte(token.Key),
+	}, &apiextensions.JSON{Raw: raw}, nil
+}
+
+func (g *Generator) cleanup(ctx context.Context, jsonSpec, state *apiextensions.JSON, kube client.Client, namespace string, httpClient *http.Client) error {
+	if state == nil {
+		return nil
+	}
+	var token tokenState
+	if err := json.Unmarshal(state.Raw, &token); err != nil {
+		return fmt.Errorf(errParseState, err)
+	}
+	_, c, err := newSpecClient(ctx, jsonSpec, kube, namespace, httpClient)
+	if err != nil {
+		return err
+	}
+	if err := c.deleteToken(ctx, token.ServiceAccountID, token.TokenID); err != nil {
+		return fmt.Errorf(errDeleteToken, token.TokenID, err)
+	}
+	return nil
+}
+
+// newSpecClient parses the Grafana resource jsonSpec and returns a client of its Grafana instance.
+func newSpecClient(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, httpClient *http.Client) (*genv1alpha1.Grafana, *grafanaClient, error) {
+	if jsonSpec == nil {
+		return nil, nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errParseSpec, err)
+	}
+	if res.Spec.URL == "" {
+		return nil, nil, errors.New(errMissingURL)
+	}
+	if res.Spec.ServiceAccount.Name == "" {
+		return nil, nil, errors.New(errMissingSA)
+	}
+	c := &grafanaClient{baseURL: strings.TrimSuffix(res.Spec.URL, "/"), http: httpClient}
+	if err := c.setAuth(ctx, res.Spec.Auth, kube, namespace); err != nil {
+		return nil, nil, err
+	}
+	return res, c, nil
+}
+
+// tokenName returns a unique name of a token, names must be unique per service account.
+func tokenName() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
+
+// grafanaClient is a minimal client of the service account API of Grafana.
+type grafanaClient struct {
+	baseURL string
+	http    *http.Client
+	token   string
+	user    string
+	pass    string
+}
+
+type serviceAccount struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Login string `json:"login"`
+}
+
+type serviceAccountToken struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Key  string `json:"key,omitempty"`
+}
+
+func (c *grafanaClient) setAuth(ctx context.Context, auth genv1alpha1.GrafanaAuth, kube client.Client, namespace string) error {
+	var err error
+	switch {
+	case auth.Token != nil && auth.Basic == nil:
+		c.token, err = secretKeyRef(ctx, kube, namespace, *auth.Token)
+	case auth.Basic != nil && auth.Token == nil:
+		c.user = auth.Basic.Username
+		c.pass, err = secretKeyRef(ctx, kube, namespace, auth.Basic.Password)
+	default:
+		err = errors.New(errInvalidAuth)
+	}
+	return err
+}
+
+// ensureServiceAccount returns the id of the service account and creates it if it doesn't exist.
+func (c *grafanaClient) ensureServiceAccount(ctx context.Context, sa genv1alpha1.GrafanaServiceAccount) (int64, error) {
+	var found struct {
+		ServiceAccounts []serviceAccount `json:"serviceAccounts"`
+	}
+	err := c.do(ctx, http.MethodGet, "/api/serviceaccounts/search?query="+url.QueryEscape(sa.Name), nil, &found)
+	if err != nil {
+		return 0, err
+	}
+	// the search matches substrings of names and logins.
+	for _, acc := range found.ServiceAccounts {
+		if acc.Name == sa.Name {
+			return acc.ID, nil
+		}
+	}
+	role := sa.Role
+	if role == "" {
+		role = defaultRole
+	}
+	var created serviceAccount
+	err = c.do(ctx, http.MethodPost, "/api/serviceaccounts", map[string]string{
+		"name": sa.Name,
+		"role": role,
+	}, &created)
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (c *grafanaClient) createToken(ctx context.Context, saID int64, name string, ttl time.Duration) (*serviceAccountToken, error) {
+	body := map[string]interface{}{"name": name}
+	if ttl > 0 {
+		body["secondsToLive"] = int64(ttl.Seconds())
+	}
+	var token serviceAccountToken
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens", saID), body, &token); err != nil {
+		return nil, err
+	}
+	if token.Key == "" {
+		return nil, errors.New(errEmptyToken)
+	}
+	return &token, nil
+}
+
+// deleteToken deletes the token, a token that no longer exists is not an error.
+func (c *grafanaClient) deleteToken(ctx context.Context, saID, tokenID int64) error {
+	err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/serviceaccounts/%d/tokens/%d", saID, tokenID), nil, nil)
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (c *grafanaClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var reqBody io.Reader = http.NoBody
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf(errRequest, method, path, err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf(errRequest, method, path, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else {
+		req.SetBasicAuth(c.user, c.pass)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf(errRequest, method, path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf(errRequest, method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf(errRequest, method, path, &statusError{code: resp.StatusCode, body: string(body)})
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf(errRequest, method, path, err)
+	}
+	return nil
+}
+
+// statusError is returned for responses with a status other than 2xx.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf(errUnexpectedStatus, e.code, e.body)
+}
+
+func secretKeyRef(ctx context.Context, kube client.Client, namespace string, ref smmeta.SecretKeySelector) (string, error) {
+	var secret corev1.Secret
+	err := kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &secret)
+	if err != nil {
+		return "", fmt.Errorf(errFindSecret, namespace, ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf(errFindDataKey, ref.Key, namespace, ref.Name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.Grafana, error) {
+	var spec genv1alpha1.Grafana
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.GrafanaKind, &Generator{})
+}
@@ -0,0 +1,254 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeGrafana implements the service account API of Grafana in memory.
+type fakeGrafana struct {
+	mu       sync.Mutex
+	accounts map[int64]string
+	tokens   map[int64][]serviceAccountToken
+	nextID   int64
+}
+
+func newFakeGrafana(accounts map[int64]string, tokens map[int64][]serviceAccountToken) *fakeGrafana {
+	return &fakeGrafana{accounts: accounts, tokens: tokens, nextID: 100}
+}
+
+func (f *fakeGrafana) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, pass, basic := r.BasicAuth()
+	if r.Header.Get("Authorization") != "Bearer admin-token" && !(basic && user == "admin" && pass == "admin-pass") {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	// parts are api, serviceaccounts, the service account id, tokens and the token id.
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var saID, tokenID int64
+	if len(parts) > 2 {
+		saID, _ = strconv.ParseInt(parts[2], 10, 64)
+	}
+	if len(parts) > 4 {
+		tokenID, _ = strconv.ParseInt(parts[4], 10, 64)
+	}
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "search":
+		var found []serviceAccount
+		for id, name := range f.accounts {
+			if strings.Contains(name, r.URL.Query().Get("query")) {
+				found = append(found, serviceAccount{ID: id, Name: name})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"serviceAccounts": found})
+	case r.Method == http.MethodPost && len(parts) == 2:
+		var in map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		f.nextID++
+		f.accounts[f.nextID] = in["name"]
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(serviceAccount{ID: f.nextID, Name: in["name"]})
+	case r.Method == http.MethodPost && len(parts) == 4:
+		var in map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		f.nextID++
+		token := serviceAccountToken{ID: f.nextID, Name: in["name"].(string)}
+		f.tokens[saID] = append(f.tokens[saID], token)
+		token.Key = fmt.Sprintf("glsa_%d", token.ID)
+		_ = json.NewEncoder(w).Encode(token)
+	case r.Method == http.MethodGet && len(parts) == 4:
+		_ = json.NewEncoder(w).Encode(f.tokens[saID])
+	case r.Method == http.MethodDelete && len(parts) == 5:
+		var kept []serviceAccountToken
+		for _, t := range f.tokens[saID] {
+			if t.ID != tokenID {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(f.tokens[saID]) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.tokens[saID] = kept
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newKube() client.Client {
+	return clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "grafana-admin", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("admin-token"), "password": []byte("admin-pass")},
+	}).Build()
+}
+
+func newSpec(url, auth string) *apiextensions.JSON {
+	return &apiextensions.JSON{Raw: []byte(`{"metadata":{"name":"grafana","namespace":"default"},"spec":{"url":"` + url + `/",` +
+		`"serviceAccount":{"name":"dashboards-ci"},"auth":` + auth + `}}`)}
+}
+
+func TestGenerate(t *testing.T) {
+	kube := newKube()
+	tests := map[string]struct {
+		auth       string
+		accounts   map[int64]string
+		tokens     map[int64][]serviceAccountToken
+		wantToken  string
+		wantState  string
+		wantTokens map[int64][]string
+		wantErr    bool
+	}{
+		"create service account": {
+			auth:       `{"token":{"name":"grafana-admin","key":"token"}}`,
+			accounts:   map[int64]string{1: "dashboards-ci-other"},
+			wantToken:  "glsa_102",
+			wantState:  `{"serviceAccountID":101,"tokenID":102}`,
+			wantTokens: map[int64][]string{101: {tokenPrefix}},
+		},
+		"keep tokens of other syncs": {
+			auth:     `{"basic":{"username":"admin","password":{"name":"grafana-admin","key":"password"}}}`,
+			accounts: map[int64]string{1: "dashboards-ci"},
+			tokens: map[int64][]serviceAccountToken{1: {
+				{ID: 10, Name: tokenPrefix + "1"},
+				{ID: 11, Name: "manual"},
+				{ID: 12, Name: tokenPrefix + "2"},
+				{ID: 13, Name: tokenPrefix + "3"},
+			}},
+			wantToken:  "glsa_101",
+			wantState:  `{"serviceAccountID":1,"tokenID":101}`,
+			wantTokens: map[int64][]string{1: {tokenPrefix + "1", "manual", tokenPrefix + "2", tokenPrefix + "3", tokenPrefix}},
+		},
+		"invalid auth": {
+			auth:     `{}`,
+			accounts: map[int64]string{},
+			wantErr:  true,
+		},
+		"unauthorized": {
+			auth:     `{"token":{"name":"grafana-admin","key":"password"}}`,
+			accounts: map[int64]string{},
+			wantErr:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.tokens == nil {
+				tc.tokens = map[int64][]serviceAccountToken{}
+			}
+			fake := newFakeGrafana(tc.accounts, tc.tokens)
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+			g := &Generator{}
+			got, state, err := g.generate(context.Background(), newSpec(srv.URL, tc.auth), kube, "default", srv.Client())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if string(got["token"]) != tc.wantToken {
+				t.Errorf("unexpected token: %s", got["token"])
+			}
+			if string(state.Raw) != tc.wantState {
+				t.Errorf("unexpected state: %s", state.Raw)
+			}
+			names := map[int64][]string{}
+			for saID, tokens := range fake.tokens {
+				sort.Slice(tokens, func(i, j int) bool { return tokens[i].ID < tokens[j].ID })
+				for _, token := range tokens {
+					name := token.Name
+					// the name of the new token ends with a random suffix.
+					if strings.HasPrefix(name, tokenPrefix) && token.ID > 100 {
+						name = tokenPrefix
+					}
+					names[saID] = append(names[saID], name)
+				}
+			}
+			if !reflect.DeepEqual(names, tc.wantTokens) {
+				t.Errorf("unexpected tokens: %v", names)
+			}
+		})
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	kube := newKube()
+	const auth = `{"token":{"name":"grafana-admin","key":"token"}}`
+	tests := map[string]struct {
+		state      *apiextensions.JSON
+		wantTokens []int64
+		wantErr    bool
+	}{
+		"delete token of the state": {
+			state:      &apiextensions.JSON{Raw: []byte(`{"serviceAccountID":1,"tokenID":11}`)},
+			wantTokens: []int64{10, 12},
+		},
+		"deleted token": {
+			state:      &apiextensions.JSON{Raw: []byte(`{"serviceAccountID":1,"tokenID":13}`)},
+			wantTokens: []int64{10, 11, 12},
+		},
+		"no state": {
+			wantTokens: []int64{10, 11, 12},
+		},
+		"invalid state": {
+			state:   &apiextensions.JSON{Raw: []byte(`[]`)},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeGrafana(map[int64]string{1: "dashboards-ci"}, map[int64][]serviceAccountToken{1: {
+				{ID: 10, Name: tokenPrefix + "1"},
+				{ID: 11, Name: tokenPrefix + "2"},
+				{ID: 12, Name: tokenPrefix + "3"},
+			}})
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+			g := &Generator{}
+			err := g.cleanup(context.Background(), newSpec(srv.URL, auth), tc.state, kube, "default", srv.Client())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			var ids []int64
+			for _, token := range fake.tokens[1] {
+				ids = append(ids, token.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.wantTokens) {
+				t.Errorf("unexpected tokens: %v", ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package satoken
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCreate(t *testing.T) {
+	clientset := kfake.NewSimpleClientset()
+	clientset.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create := action.(k8stesting.CreateAction)
+		if create.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		if create.GetNamespace() != "default" {
+			return true, nil, errors.New("unexpected namespace " + create.GetNamespace())
+		}
+		req := create.GetObject().(*authv1.TokenRequest)
+		if !reflect.DeepEqual(req.Spec.Audiences, []string{"api://example"}) {
+			return true, nil, errors.New("unexpected audiences")
+		}
+		return true, &authv1.TokenRequest{Status: authv1.TokenRequestStatus{Token: "token-of-" + create.(k8stesting.CreateActionImpl).Name}}, nil
+	})
+
+	token, err := create(context.Background(), clientset.CoreV1(), "default", "builder", "api://example")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "token-of-builder" {
+		t.Errorf("unexpected token %q", token)
+	}
+
+	if _, err := create(context.Background(), clientset.CoreV1(), "other", "builder", "api://example"); err == nil {
+		t.Errorf("expected error of the token request")
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// Generator generates random passwords.
+type Generator struct{}
+
+const (
+	defaultLength      = 24
+	defaultSymbolChars = "~!@#$%^&*()_+`-={}|[]\\:\"<>?,./"
+	digitChars         = "0123456789"
+	lowerChars         = "abcdefghijklmnopqrstuvwxyz"
+	upperChars         = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	errNoSpec        = "no config spec provided"
+	errParseSpec     = "unable to parse spec: %w"
+	errInvalidLength = "length %d must be positive"
+	errTooManyChars  = "digits (%d) and symbols (%d) exceed the length %d"
+	errNegativeCount = "digits and symbols must not be negative"
+	errExhausted     = "not enough unique characters to generate a password of length %d, set allowRepeat"
+	errGenerate      = "unable to generate password: %w"
+
+	// PasswordKey is the key of the generated password.
+	PasswordKey = "password"
+)
+
+// Generate returns a random password of the Password resource jsonSpec.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	pass, err := generate(res.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{PasswordKey: []byte(pass)}, nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.Password, error) {
+	var spec genv1alpha1.Password
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+// generate returns a password with the configured number of digits and symbols,
+// the remaining characters are letters.
+func generate(spec genv1alpha1.PasswordSpec) (string, error) {
+	length := spec.Length
+	if length == 0 {
+		length = defaultLength
+	}
+	if length < 0 {
+		return "", fmt.Errorf(errInvalidLength, length)
+	}
+	digits := length / 4
+	if spec.Digits != nil {
+		digits = *spec.Digits
+	}
+	symbols := length / 4
+	if spec.Symbols != nil {
+		symbols = *spec.Symbols
+	}
+	if digits < 0 || symbols < 0 {
+		return "", errors.New(errNegativeCount)
+	}
+	if digits+symbols > length {
+		return "", fmt.Errorf(errTooManyChars, digits, symbols, length)
+	}
+	symbolChars := defaultSymbolChars
+	if spec.SymbolCharacters != nil {
+		symbolChars = *spec.SymbolCharacters
+	}
+	letterChars := lowerChars
+	if !spec.NoUpper {
+		letterChars += upperChars
+	}
+
+	p := picker{allowRepeat: spec.AllowRepeat, used: make(map[rune]bool)}
+	for _, c := range []struct {
+		chars string
+		count int
+	}{
+		{digitChars, digits},
+		{symbolChars, symbols},
+		{letterChars, length - digits - symbols},
+	} {
+		if err := p.pick(c.chars, c.count); err != nil {
+			if errors.Is(err, errNoChars) {
+				return "", fmt.Errorf(errExhausted, length)
+			}
+			return "", fmt.Errorf(errGenerate, err)
+		}
+	}
+	if err := shuffle(p.out); err != nil {
+		return "", fmt.Errorf(errGenerate, err)
+	}
+	return string(p.out), nil
+}
+
+var errNoChars = errors.New("no characters left")
+
+// picker appends random characters to out. Unless repeats are allowed
+// every character is used at most once.
+type picker struct {
+	allowRepeat bool
+	used        map[rune]bool
+	out         []rune
+}
+
+func (p *picker) pick(chars string, count int) error {
+	for i := 0; i < count; i++ {
+		pool := make([]rune, 0, len(chars))
+		for _, c := range chars {
+			if p.allowRepeat || !p.used[c] {
+				pool = append(pool, c)
+			}
+		}
+		if len(pool) == 0 {
+			return errNoChars
+		}
+		n, err := randInt(len(pool))
+		if err != nil {
+			return err
+		}
+		p.used[pool[n]] = true
+		p.out = append(p.out, pool[n])
+	}
+	return nil
+}
+
+// shuffle permutes the runes in place with the Fisher-Yates algorithm.
+func shuffle(r []rune) error {
+	for i := len(r) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return err
+		}
+		r[i], r[j] = r[j], r[i]
+	}
+	return nil
+}
+
+func randInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.PasswordKind, &Generator{})
+}
@@ -0,0 +1,131 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+func TestGenerate(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	strPtr := func(s string) *string { return &s }
+	tests := map[string]struct {
+		spec         genv1alpha1.PasswordSpec
+		wantErr      string
+		wantLength   int
+		wantDigits   int
+		wantSymbols  int
+		symbolChars  string
+		wantNoUpper  bool
+		wantNoRepeat bool
+	}{
+		"defaults": {
+			wantLength:   24,
+			wantDigits:   6,
+			wantSymbols:  6,
+			symbolChars:  defaultSymbolChars,
+			wantNoRepeat: true,
+		},
+		"custom counts": {
+			spec:         genv1alpha1.PasswordSpec{Length: 10, Digits: intPtr(4), Symbols: intPtr(0)},
+			wantLength:   10,
+			wantDigits:   4,
+			wantNoRepeat: true,
+		},
+		"custom symbols without upper case": {
+			spec:         genv1alpha1.PasswordSpec{Length: 12, Digits: intPtr(0), Symbols: intPtr(2), SymbolCharacters: strPtr("-_"), NoUpper: true},
+			wantLength:   12,
+			wantSymbols:  2,
+			symbolChars:  "-_",
+			wantNoUpper:  true,
+			wantNoRepeat: true,
+		},
+		"repeats allowed": {
+			spec:       genv1alpha1.PasswordSpec{Length: 64, Digits: intPtr(20), Symbols: intPtr(0), AllowRepeat: true},
+			wantLength: 64,
+			wantDigits: 20,
+		},
+		"repeats exhaust characters": {
+			spec:    genv1alpha1.PasswordSpec{Length: 20, Digits: intPtr(11), Symbols: intPtr(0)},
+			wantErr: "not enough unique characters",
+		},
+		"counts exceed length": {
+			spec:    genv1alpha1.PasswordSpec{Length: 4, Digits: intPtr(3), Symbols: intPtr(2)},
+			wantErr: "exceed the length 4",
+		},
+		"negative length": {
+			spec:    genv1alpha1.PasswordSpec{Length: -1},
+			wantErr: "must be positive",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pass, err := generate(tc.spec)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			runes := []rune(pass)
+			if len(runes) != tc.wantLength {
+				t.Errorf("unexpected length: expected %d, got %d", tc.wantLength, len(runes))
+			}
+			var digits, symbols int
+			seen := make(map[rune]bool)
+			for _, r := range runes {
+				switch {
+				case unicode.IsDigit(r):
+					digits++
+				case tc.symbolChars != "" && strings.ContainsRune(tc.symbolChars, r):
+					symbols++
+				case tc.wantNoUpper && unicode.IsUpper(r):
+					t.Errorf("unexpected upper case character in %q", pass)
+				}
+				if tc.wantNoRepeat && seen[r] {
+					t.Errorf("unexpected repeated character in %q", pass)
+				}
+				seen[r] = true
+			}
+			if digits != tc.wantDigits || symbols != tc.wantSymbols {
+				t.Errorf("unexpected characters in %q: expected %d digits and %d symbols, got %d and %d", pass, tc.wantDigits, tc.wantSymbols, digits, symbols)
+			}
+		})
+	}
+}
+
+func TestGenerateFromJSON(t *testing.T) {
+	g := &Generator{}
+	data, err := g.Generate(context.Background(), &apiextensions.JSON{Raw: []byte(`{"spec":{"length":8,"digits":2,"symbols":2}}`)}, nil, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data[PasswordKey]) != 8 {
+		t.Errorf("unexpected password: %q", data[PasswordKey])
+	}
+	if _, err := g.Generate(context.Background(), nil, nil, "default"); err == nil {
+		t.Errorf("expected error without spec")
+	}
+}
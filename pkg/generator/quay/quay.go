@@ -0,0 +1,177 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/generator/internal/satoken"
+)
+
+// Generator exchanges OIDC tokens for robot account tokens of Quay.
+type Generator struct{}
+
+const (
+	defaultURL = "quay.io"
+
+	errNoSpec           = "no config spec provided"
+	errParseSpec        = "unable to parse spec: %w"
+	errMissingRobot     = "no robotAccount provided"
+	errInvalidURL       = "invalid url %q: %w"
+	errInvalidAuth      = "exactly one of serviceAccountRef or secretRef must be set"
+	errFindSecret       = "could not find secret %s/%s: %w"
+	errFindDataKey      = "no data for %q in secret '%s/%s'"
+	errFetchSAToken     = "unable to fetch service account token: %w"
+	errExchangeToken    = "unable to exchange token at %s: %w"
+	errUnexpectedStatus = "unexpected status code %d: %s"
+	errEmptyToken       = "empty token returned"
+)
+
+type saTokenFunc func(ctx context.Context, namespace, name, audience string) (string, error)
+
+// Generate returns a robot account token of the QuayAccessToken resource jsonSpec
+// as username and password for docker registries.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, satoken.Fetch, http.DefaultClient)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, saToken saTokenFunc, httpClient *http.Client) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	if res.Spec.RobotAccount == "" {
+		return nil, errors.New(errMissingRobot)
+	}
+	baseURL, err := quayURL(res.Spec.URL)
+	if err != nil {
+		return nil, err
+	}
+	var oidcToken string
+	auth := res.Spec.Auth
+	switch {
+	case auth.ServiceAccountRef != nil && auth.SecretRef == nil:
+		oidcToken, err = saToken(ctx, namespace, auth.ServiceAccountRef.Name, baseURL.Host)
+		if err != nil {
+			return nil, fmt.Errorf(errFetchSAToken, err)
+		}
+	case auth.SecretRef != nil && auth.ServiceAccountRef == nil:
+		oidcToken, err = secretKeyRef(ctx, kube, namespace, auth.SecretRef.Name, auth.SecretRef.Key)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New(errInvalidAuth)
+	}
+	token, err := exchangeToken(ctx, httpClient, baseURL, res.Spec.RobotAccount, oidcToken)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"registry": []byte(baseURL.Host),
+		"username": []byte(res.Spec.RobotAccount),
+		"password": []byte(token),
+	}, nil
+}
+
+// quayURL returns the URL of the Quay instance, https is used if no scheme is set.
+func quayURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		raw = defaultURL
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidURL, raw, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf(errInvalidURL, raw, errors.New("missing host"))
+	}
+	return u, nil
+}
+
+// exchangeToken exchanges the OIDC token for a token of the robot account
+// with the robot federation endpoint of Quay.
+func exchangeToken(ctx context.Context, httpClient *http.Client, baseURL *url.URL, robot, oidcToken string) (string, error) {
+	endpoint := strings.TrimSuffix(baseURL.String(), "/") + "/oauth2/federation/robot/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	req.SetBasicAuth(robot, oidcToken)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(errExchangeToken, endpoint, fmt.Errorf(errUnexpectedStatus, resp.StatusCode, string(body)))
+	}
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf(errExchangeToken, endpoint, err)
+	}
+	if out.Token == "" {
+		return "", errors.New(errEmptyToken)
+	}
+	return out.Token, nil
+}
+
+func secretKeyRef(ctx context.Context, kube client.Client, namespace, name, key string) (string, error) {
+	var secret corev1.Secret
+	err := kube.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &secret)
+	if err != nil {
+		return "", fmt.Errorf(errFindSecret, namespace, name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf(errFindDataKey, key, namespace, name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.QuayAccessToken, error) {
+	var spec genv1alpha1.QuayAccessToken
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.QuayAccessTokenKind, &Generator{})
+}
@@ -0,0 +1,124 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if r.URL.Path != "/oauth2/federation/robot/token" || !ok || user != "my-org+robot" || pass != "oidc-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"robot-token"}`))
+	}))
+	defer srv.Close()
+	srvURL, _ := url.Parse(srv.URL)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oidc", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("oidc-token\n"), "wrong": []byte("other-token")},
+	}).Build()
+	spec := func(robot, auth string) *apiextensions.JSON {
+		return &apiextensions.JSON{Raw: []byte(`{"spec":{"url":"` + srv.URL + `","robotAccount":"` + robot + `","auth":` + auth + `}}`)}
+	}
+	want := map[string][]byte{
+		"registry": []byte(srvURL.Host),
+		"username": []byte("my-org+robot"),
+		"password": []byte("robot-token"),
+	}
+	tests := map[string]struct {
+		spec    *apiextensions.JSON
+		saErr   error
+		want    map[string][]byte
+		wantErr bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"missing robot account": {
+			spec:    spec("", `{"secretRef":{"name":"oidc","key":"token"}}`),
+			wantErr: true,
+		},
+		"service account token": {
+			spec: spec("my-org+robot", `{"serviceAccountRef":{"name":"puller"}}`),
+			want: want,
+		},
+		"service account error": {
+			spec:    spec("my-org+robot", `{"serviceAccountRef":{"name":"puller"}}`),
+			saErr:   errors.New("forbidden"),
+			wantErr: true,
+		},
+		"secret token": {
+			spec: spec("my-org+robot", `{"secretRef":{"name":"oidc","key":"token"}}`),
+			want: want,
+		},
+		"rejected token": {
+			spec:    spec("my-org+robot", `{"secretRef":{"name":"oidc","key":"wrong"}}`),
+			wantErr: true,
+		},
+		"ambiguous auth": {
+			spec:    spec("my-org+robot", `{"serviceAccountRef":{"name":"puller"},"secretRef":{"name":"oidc","key":"token"}}`),
+			wantErr: true,
+		},
+		"no auth": {
+			spec:    spec("my-org+robot", `{}`),
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.generate(context.Background(), tc.spec, kube, "default", func(ctx context.Context, namespace, name, audience string) (string, error) {
+				if name != "puller" || audience != srvURL.Host {
+					t.Errorf("unexpected service account %s or audience %s", name, audience)
+				}
+				return "oidc-token", tc.saErr
+			}, srv.Client())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+		})
+	}
+}
+
+func TestQuayURL(t *testing.T) {
+	for raw, want := range map[string]string{
+		"":                          "https://quay.io",
+		"quay.example.com":          "https://quay.example.com",
+		"http://quay.internal:8080": "http://quay.internal:8080",
+	} {
+		got, err := quayURL(raw)
+		if err != nil || got.String() != want {
+			t.Errorf("unexpected url of %q: %v, %v", raw, got, err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// Generator generates ssh key pairs.
+type Generator struct{}
+
+const (
+	defaultRSAKeySize = 3072
+
+	// openSSHMagic is the header of private keys in the OpenSSH format.
+	openSSHMagic = "openssh-key-v1\x00"
+	// openSSHBlockSize is the block size of the unencrypted private section.
+	openSSHBlockSize = 8
+
+	errNoSpec         = "no config spec provided"
+	errParseSpec      = "unable to parse spec: %w"
+	errUnknownKeyType = "unknown key type %q"
+	errGenerateKey    = "unable to generate key pair: %w"
+	errPublicKey      = "unable to encode public key: %w"
+)
+
+// Generate returns a new key pair of the SSHKey resource jsonSpec.
+// The private key is encoded in the OpenSSH format, the public key
+// in the authorized_keys format.
+func (g *Generator) Generate(_ context.Context, jsonSpec *apiextensions.JSON, _ client.Client, _ string) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	spec := res.Spec
+	var privKey interface{}
+	switch spec.KeyType {
+	case genv1alpha1.SSHKeyTypeED25519, "":
+		_, privKey, err = ed25519.GenerateKey(rand.Reader)
+	case genv1alpha1.SSHKeyTypeRSA:
+		size := defaultRSAKeySize
+		if spec.KeySize != nil {
+			size = *spec.KeySize
+		}
+		privKey, err = rsa.GenerateKey(rand.Reader, size)
+	default:
+		return nil, fmt.Errorf(errUnknownKeyType, spec.KeyType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errGenerateKey, err)
+	}
+	signer, err := ssh.NewSignerFromKey(privKey)
+	if err != nil {
+		return nil, fmt.Errorf(errPublicKey, err)
+	}
+	privPEM, err := marshalPrivateKey(privKey, spec.Comment)
+	if err != nil {
+		return nil, fmt.Errorf(errGenerateKey, err)
+	}
+	pubKey := signer.PublicKey()
+	authorizedKey := ssh.MarshalAuthorizedKey(pubKey)
+	if spec.Comment != "" {
+		// MarshalAuthorizedKey terminates the line with a newline.
+		authorizedKey = append(authorizedKey[:len(authorizedKey)-1], []byte(" "+spec.Comment+"\n")...)
+	}
+	data := map[string][]byte{
+		"private_key": pem.EncodeToMemory(privPEM),
+		"public_key":  authorizedKey,
+	}
+	if len(spec.Hosts) > 0 {
+		data["known_hosts"] = []byte(knownhosts.Line(spec.Hosts, pubKey) + "\n")
+	}
+	return data, nil
+}
+
+// marshalPrivateKey encodes the unencrypted private key in the OpenSSH format
+// described in https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.key.
+func marshalPrivateKey(key interface{}, comment string) (*pem.Block, error) {
+	var check [4]byte
+	if _, err := rand.Read(check[:]); err != nil {
+		return nil, err
+	}
+	section := struct {
+		Check1  uint32
+		Check2  uint32
+		Keytype string
+		Rest    []byte `ssh:"rest"`
+	}{
+		Check1: binary.BigEndian.Uint32(check[:]),
+		Check2: binary.BigEndian.Uint32(check[:]),
+	}
+	var pubKey []byte
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		pub := k.Public().(ed25519.PublicKey)
+		section.Keytype = ssh.KeyAlgoED25519
+		section.Rest = ssh.Marshal(struct {
+			Pub     []byte
+			Priv    []byte
+			Comment string
+		}{pub, k, comment})
+		pubKey = ssh.Marshal(struct {
+			KeyType string
+			Pub     []byte
+		}{ssh.KeyAlgoED25519, pub})
+	case *rsa.PrivateKey:
+		e := big.NewInt(int64(k.E))
+		section.Keytype = ssh.KeyAlgoRSA
+		section.Rest = ssh.Marshal(struct {
+			N       *big.Int
+			E       *big.Int
+			D       *big.Int
+			Iqmp    *big.Int
+			P       *big.Int
+			Q       *big.Int
+			Comment string
+		}{k.N, e, k.D, k.Precomputed.Qinv, k.Primes[0], k.Primes[1], comment})
+		pubKey = ssh.Marshal(struct {
+			KeyType string
+			E       *big.Int
+			N       *big.Int
+		}{ssh.KeyAlgoRSA, e, k.N})
+	default:
+		return nil, fmt.Errorf(errUnknownKeyType, fmt.Sprintf("%T", key))
+	}
+	block := ssh.Marshal(section)
+	for i := 1; len(block)%openSSHBlockSize != 0; i++ {
+		block = append(block, byte(i))
+	}
+	out := ssh.Marshal(struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{"none", "none", "", 1, pubKey, block})
+	return &pem.Block{
+		Type:  "OPENSSH PRIVATE KEY",
+		Bytes: append([]byte(openSSHMagic), out...),
+	}, nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.SSHKey, error) {
+	var spec genv1alpha1.SSHKey
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.SSHKeyKind, &Generator{})
+}
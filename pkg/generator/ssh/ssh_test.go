@@ -0,0 +1,112 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		spec        *apiextensions.JSON
+		wantType    string
+		wantBits    int
+		wantComment string
+		wantHosts   []string
+		wantErr     bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"default ed25519": {
+			spec:     &apiextensions.JSON{Raw: []byte(`{"spec":{}}`)},
+			wantType: ssh.KeyAlgoED25519,
+		},
+		"rsa with comment": {
+			spec:        &apiextensions.JSON{Raw: []byte(`{"spec":{"keyType":"rsa","keySize":2048,"comment":"deploy@example.com"}}`)},
+			wantType:    ssh.KeyAlgoRSA,
+			wantBits:    2048,
+			wantComment: "deploy@example.com",
+		},
+		"known hosts": {
+			spec:      &apiextensions.JSON{Raw: []byte(`{"spec":{"keyType":"ed25519","hosts":["git.example.com","10.0.0.1:2222"]}}`)},
+			wantType:  ssh.KeyAlgoED25519,
+			wantHosts: []string{"git.example.com", "[10.0.0.1]:2222"},
+		},
+		"unknown key type": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"keyType":"dsa"}}`)},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.Generate(context.Background(), tc.spec, nil, "default")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			raw, err := ssh.ParseRawPrivateKey(got["private_key"])
+			if err != nil {
+				t.Fatalf("unable to parse private key: %v", err)
+			}
+			signer, err := ssh.NewSignerFromKey(raw)
+			if err != nil {
+				t.Fatalf("unable to create signer: %v", err)
+			}
+			pub, comment, _, _, err := ssh.ParseAuthorizedKey(got["public_key"])
+			if err != nil {
+				t.Fatalf("unable to parse public key: %v", err)
+			}
+			if pub.Type() != tc.wantType {
+				t.Errorf("unexpected key type: %s", pub.Type())
+			}
+			if !bytes.Equal(pub.Marshal(), signer.PublicKey().Marshal()) {
+				t.Errorf("public key does not match private key")
+			}
+			if comment != tc.wantComment {
+				t.Errorf("unexpected comment: %q", comment)
+			}
+			if k, ok := raw.(*rsa.PrivateKey); ok && k.N.BitLen() != tc.wantBits {
+				t.Errorf("unexpected key size: %d", k.N.BitLen())
+			}
+			knownHosts, ok := got["known_hosts"]
+			if ok != (tc.wantHosts != nil) {
+				t.Fatalf("unexpected known_hosts: %s", knownHosts)
+			}
+			if ok {
+				_, hosts, hostKey, _, _, err := ssh.ParseKnownHosts(knownHosts)
+				if err != nil {
+					t.Fatalf("unable to parse known_hosts: %v", err)
+				}
+				if strings.Join(hosts, ",") != strings.Join(tc.wantHosts, ",") {
+					t.Errorf("unexpected hosts: %v", hosts)
+				}
+				if !bytes.Equal(hostKey.Marshal(), pub.Marshal()) {
+					t.Errorf("known_hosts key does not match public key")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	awsauth "github.com/external-secrets/external-secrets/pkg/provider/aws/auth"
+)
+
+// Generator requests temporary AWS credentials from STS.
+type Generator struct{}
+
+const (
+	// roleSessionName is the session name of the assumed role.
+	roleSessionName = "external-secrets-generator"
+
+	errNoSpec          = "no config spec provided"
+	errParseSpec       = "unable to parse spec: %w"
+	errCreateSess      = "unable to create aws session: %w"
+	errAssumeRole      = "unable to assume role: %w"
+	errGetSessionToken = "unable to get session token: %w"
+	errNoCredentials   = "no credentials returned"
+)
+
+// Generate returns the temporary credentials of the STSSessionToken resource jsonSpec.
+// The credentials of the role are returned if a role is set,
+// otherwise the session credentials of the authenticated user.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, awsauth.DefaultSTSProvider)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, stsFunc awsauth.STSProvider) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	// the role is assumed below, so that the credentials of the role are returned.
+	sess, err := awsauth.NewGeneratorSession(ctx, res.Spec.Auth, "", res.Spec.Region, kube, namespace, awsauth.DefaultSTSProvider, awsauth.DefaultJWTProvider)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateSess, err)
+	}
+	creds, err := requestCredentials(ctx, stsFunc(sess), &res.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if creds == nil || creds.AccessKeyId == nil || creds.SecretAccessKey == nil {
+		return nil, errors.New(errNoCredentials)
+	}
+	var expiresAt string
+	if creds.Expiration != nil {
+		expiresAt = strconv.FormatInt(creds.Expiration.Unix(), 10)
+	}
+	return map[string][]byte{
+		"AWS_ACCESS_KEY_ID":     []byte(aws.StringValue(creds.AccessKeyId)),
+		"AWS_SECRET_ACCESS_KEY": []byte(aws.StringValue(creds.SecretAccessKey)),
+		"AWS_SESSION_TOKEN":     []byte(aws.StringValue(creds.SessionToken)),
+		"expires_at":            []byte(expiresAt),
+	}, nil
+}
+
+// requestCredentials assumes the role of spec or, without a role, requests a session token.
+func requestCredentials(ctx context.Context, stsClient stsiface.STSAPI, spec *genv1alpha1.STSSessionTokenSpec) (*sts.Credentials, error) {
+	params := spec.RequestParameters
+	if params == nil {
+		params = &genv1alpha1.STSSessionTokenRequestParameters{}
+	}
+	if spec.Role != "" {
+		out, err := stsClient.AssumeRoleWithContext(ctx, &sts.AssumeRoleInput{
+			RoleArn:         aws.String(spec.Role),
+			RoleSessionName: aws.String(roleSessionName),
+			DurationSeconds: params.SessionDuration,
+			SerialNumber:    params.SerialNumber,
+			TokenCode:       params.TokenCode,
+		})
+		if err != nil {
+			return nil, fmt.Errorf(errAssumeRole, err)
+		}
+		return out.Credentials, nil
+	}
+	out, err := stsClient.GetSessionTokenWithContext(ctx, &sts.GetSessionTokenInput{
+		DurationSeconds: params.SessionDuration,
+		SerialNumber:    params.SerialNumber,
+		TokenCode:       params.TokenCode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errGetSessionToken, err)
+	}
+	return out.Credentials, nil
+}
+
+// Expiry returns the expiration of the temporary credentials in data.
+func (g *Generator) Expiry(data map[string][]byte) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(string(data["expires_at"]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func parseSpec(data []byte) (*genv1alpha1.STSSessionToken, error) {
+	var spec genv1alpha1.STSSessionToken
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.STSSessionTokenKind, &Generator{})
+}
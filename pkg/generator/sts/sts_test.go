@@ -0,0 +1,144 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeSTS struct {
+	stsiface.STSAPI
+	creds           *sts.Credentials
+	err             error
+	assumeRoleInput *sts.AssumeRoleInput
+	sessionInput    *sts.GetSessionTokenInput
+}
+
+func (f *fakeSTS) AssumeRoleWithContext(_ aws.Context, in *sts.AssumeRoleInput, _ ...request.Option) (*sts.AssumeRoleOutput, error) {
+	f.assumeRoleInput = in
+	return &sts.AssumeRoleOutput{Credentials: f.creds}, f.err
+}
+
+func (f *fakeSTS) GetSessionTokenWithContext(_ aws.Context, in *sts.GetSessionTokenInput, _ ...request.Option) (*sts.GetSessionTokenOutput, error) {
+	f.sessionInput = in
+	return &sts.GetSessionTokenOutput{Credentials: f.creds}, f.err
+}
+
+func TestGenerate(t *testing.T) {
+	expiration := time.Unix(1700000000, 0)
+	creds := &sts.Credentials{
+		AccessKeyId:     aws.String("AKIAEXAMPLE"),
+		SecretAccessKey: aws.String("s3cr3t"),
+		SessionToken:    aws.String("token"),
+		Expiration:      &expiration,
+	}
+	want := map[string][]byte{
+		"AWS_ACCESS_KEY_ID":     []byte("AKIAEXAMPLE"),
+		"AWS_SECRET_ACCESS_KEY": []byte("s3cr3t"),
+		"AWS_SESSION_TOKEN":     []byte("token"),
+		"expires_at":            []byte("1700000000"),
+	}
+	tests := map[string]struct {
+		spec           *apiextensions.JSON
+		creds          *sts.Credentials
+		err            error
+		want           map[string][]byte
+		wantAssumeRole bool
+		wantErr        bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"session token": {
+			spec:  &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1","requestParameters":{"sessionDuration":900}}}`)},
+			creds: creds,
+			want:  want,
+		},
+		"assume role": {
+			spec:           &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1","role":"arn:aws:iam::123456789012:role/legacy","requestParameters":{"sessionDuration":900}}}`)},
+			creds:          creds,
+			want:           want,
+			wantAssumeRole: true,
+		},
+		"api error": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1"}}`)},
+			err:     errors.New("access denied"),
+			wantErr: true,
+		},
+		"no credentials": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1"}}`)},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeSTS{creds: tc.creds, err: tc.err}
+			g := &Generator{}
+			got, err := g.generate(context.Background(), tc.spec, clientfake.NewClientBuilder().Build(), "default", func(*session.Session) stsiface.STSAPI {
+				return fake
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+			if tc.wantAssumeRole {
+				if fake.assumeRoleInput == nil || aws.StringValue(fake.assumeRoleInput.RoleArn) != "arn:aws:iam::123456789012:role/legacy" {
+					t.Errorf("expected role to be assumed: %v", fake.assumeRoleInput)
+				}
+				if fake.sessionInput != nil {
+					t.Errorf("unexpected GetSessionToken request")
+				}
+			}
+			if tc.want != nil && aws.Int64Value(durationOf(fake)) != 900 {
+				t.Errorf("unexpected session duration")
+			}
+		})
+	}
+}
+
+func durationOf(f *fakeSTS) *int64 {
+	if f.assumeRoleInput != nil {
+		return f.assumeRoleInput.DurationSeconds
+	}
+	if f.sessionInput != nil {
+		return f.sessionInput.DurationSeconds
+	}
+	return nil
+}
+
+func TestExpiry(t *testing.T) {
+	g := &Generator{}
+	expiry, ok := g.Expiry(map[string][]byte{"expires_at": []byte("1654084800")})
+	if !ok || !expiry.Equal(time.Unix(1654084800, 0)) {
+		t.Errorf("unexpected expiry: %v, %v", expiry, ok)
+	}
+	if _, ok := g.Expiry(map[string][]byte{"expires_at": []byte("")}); ok {
+		t.Errorf("expected an unknown expiry")
+	}
+}
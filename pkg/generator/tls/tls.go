@@ -0,0 +1,246 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tls
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// Generator issues self-signed or CA-signed certificates.
+type Generator struct{}
+
+const (
+	defaultDuration = 24 * time.Hour
+	defaultRSASize  = 2048
+	defaultECSize   = 256
+	// backdate allows for clock skew between the controller and the clients.
+	backdate = time.Minute
+
+	errNoSpec          = "no config spec provided"
+	errParseSpec       = "unable to parse spec: %w"
+	errKeyType         = "unsupported key type %q"
+	errKeySize         = "unsupported key size %d for key type %s"
+	errGenerateKey     = "unable to generate key: %w"
+	errInvalidIP       = "invalid ip address %q"
+	errFindSecret      = "could not find secret %s/%s: %w"
+	errParseCA         = "unable to parse CA of secret %s/%s: %w"
+	errNotCA           = "certificate of secret %s/%s is not a CA"
+	errSerialNumber    = "unable to generate serial number: %w"
+	errCreateCert      = "unable to create certificate: %w"
+	errMarshalKey      = "unable to marshal private key: %w"
+	errInvalidDuration = "duration must be positive"
+)
+
+// Generate issues the certificate of the TLSCertificate resource jsonSpec.
+// It returns the PEM encoded certificate, private key and CA certificate in
+// tls.crt, tls.key and ca.crt.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, time.Now())
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, now time.Time) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	spec := res.Spec
+	duration := defaultDuration
+	if spec.Duration != nil {
+		duration = spec.Duration.Duration
+	}
+	if duration <= 0 {
+		return nil, errors.New(errInvalidDuration)
+	}
+	template, err := certificateTemplate(spec, now, duration)
+	if err != nil {
+		return nil, err
+	}
+	key, err := generateKey(spec.KeyType, spec.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	// without a CA the certificate is signed by its own key.
+	parent, signer := template, key
+	var caPEM []byte
+	if spec.CASecretRef != nil {
+		parent, signer, caPEM, err = loadCA(ctx, kube, namespace, spec.CASecretRef.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateCert, err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if caPEM == nil {
+		caPEM = certPEM
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf(errMarshalKey, err)
+	}
+	return map[string][]byte{
+		corev1.TLSCertKey:              certPEM,
+		corev1.TLSPrivateKeyKey:        pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		corev1.ServiceAccountRootCAKey: caPEM,
+	}, nil
+}
+
+func certificateTemplate(spec genv1alpha1.TLSCertificateSpec, now time.Time, duration time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf(errSerialNumber, err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: spec.CommonName},
+		DNSNames:              spec.DNSNames,
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              now.Add(duration),
+		BasicConstraintsValid: true,
+		IsCA:                  spec.IsCA,
+	}
+	for _, addr := range spec.IPAddresses {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return nil, fmt.Errorf(errInvalidIP, addr)
+		}
+		template.IPAddresses = append(template.IPAddresses, ip)
+	}
+	if spec.IsCA {
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	} else {
+		template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	return template, nil
+}
+
+func generateKey(keyType genv1alpha1.TLSKeyType, keySize *int) (crypto.Signer, error) {
+	var key crypto.Signer
+	var err error
+	switch keyType {
+	case "", genv1alpha1.TLSKeyTypeECDSA:
+		size := defaultECSize
+		if keySize != nil {
+			size = *keySize
+		}
+		var curve elliptic.Curve
+		switch size {
+		case 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf(errKeySize, size, genv1alpha1.TLSKeyTypeECDSA)
+		}
+		key, err = ecdsa.GenerateKey(curve, rand.Reader)
+	case genv1alpha1.TLSKeyTypeRSA:
+		size := defaultRSASize
+		if keySize != nil {
+			size = *keySize
+		}
+		if size < 2048 || size > 8192 {
+			return nil, fmt.Errorf(errKeySize, size, genv1alpha1.TLSKeyTypeRSA)
+		}
+		key, err = rsa.GenerateKey(rand.Reader, size)
+	case genv1alpha1.TLSKeyTypeED25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf(errKeyType, keyType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errGenerateKey, err)
+	}
+	return key, nil
+}
+
+// loadCA returns the CA certificate, its private key and
+// its PEM encoded certificate of the kubernetes.io/tls secret name.
+func loadCA(ctx context.Context, kube client.Client, namespace, name string) (*x509.Certificate, crypto.Signer, []byte, error) {
+	var secret corev1.Secret
+	err := kube.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &secret)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf(errFindSecret, namespace, name, err)
+	}
+	pair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf(errParseCA, namespace, name, err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf(errParseCA, namespace, name, err)
+	}
+	if !cert.IsCA {
+		return nil, nil, nil, fmt.Errorf(errNotCA, namespace, name)
+	}
+	signer, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf(errParseCA, namespace, name, errors.New("private key can not sign"))
+	}
+	return cert, signer, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: pair.Certificate[0]}), nil
+}
+
+// Expiry returns the expiration of the certificate in data.
+func (g *Generator) Expiry(data map[string][]byte) (time.Time, bool) {
+	block, _ := pem.Decode(data[corev1.TLSCertKey])
+	if block == nil {
+		return time.Time{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter, true
+}
+
+func parseSpec(data []byte) (*genv1alpha1.TLSCertificate, error) {
+	var spec genv1alpha1.TLSCertificate
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.TLSCertificateKind, &Generator{})
+}
@@ -0,0 +1,211 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerate(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	g := &Generator{}
+	issue := func(spec string) map[string][]byte {
+		data, err := g.generate(context.Background(), &apiextensions.JSON{Raw: []byte(spec)}, nil, "default", now)
+		if err != nil {
+			t.Fatalf("unable to issue certificate: %v", err)
+		}
+		return data
+	}
+	ca := issue(`{"spec":{"commonName":"dev-ca","isCA":true}}`)
+	leaf := issue(`{"spec":{"commonName":"leaf"}}`)
+	secrets := []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "default"},
+			Data:       ca,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: "default"},
+			Data:       leaf,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "default"},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("foo"), corev1.TLSPrivateKeyKey: []byte("bar")},
+		},
+	}
+	caCert := parseCertificate(t, ca[corev1.TLSCertKey])
+
+	tests := map[string]struct {
+		spec    string
+		check   func(t *testing.T, cert *x509.Certificate, data map[string][]byte)
+		wantErr bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"self-signed defaults": {
+			spec: `{"spec":{"commonName":"example","dnsNames":["example.com","*.example.com"]}}`,
+			check: func(t *testing.T, cert *x509.Certificate, data map[string][]byte) {
+				if cert.Subject.CommonName != "example" {
+					t.Errorf("unexpected common name: %s", cert.Subject.CommonName)
+				}
+				if !reflect.DeepEqual(cert.DNSNames, []string{"example.com", "*.example.com"}) {
+					t.Errorf("unexpected dns names: %v", cert.DNSNames)
+				}
+				if !cert.NotAfter.Equal(now.Add(24 * time.Hour)) {
+					t.Errorf("unexpected expiry: %v", cert.NotAfter)
+				}
+				if expiry, ok := g.Expiry(data); !ok || !expiry.Equal(cert.NotAfter) {
+					t.Errorf("unexpected expiry of the data: %v", expiry)
+				}
+				if cert.IsCA {
+					t.Errorf("expected a leaf certificate")
+				}
+				if cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) != nil {
+					t.Errorf("expected a self-signed certificate")
+				}
+				if string(data[corev1.ServiceAccountRootCAKey]) != string(data[corev1.TLSCertKey]) {
+					t.Errorf("expected the certificate as ca.crt")
+				}
+				key := parsePrivateKey(t, data[corev1.TLSPrivateKeyKey])
+				ecKey, ok := key.(*ecdsa.PrivateKey)
+				if !ok || ecKey.Curve.Params().BitSize != 256 {
+					t.Errorf("unexpected key: %T", key)
+				}
+			},
+		},
+		"rsa key and ip addresses": {
+			spec: `{"spec":{"ipAddresses":["10.0.0.1","::1"],"keyType":"rsa","keySize":3072,"duration":"1h"}}`,
+			check: func(t *testing.T, cert *x509.Certificate, data map[string][]byte) {
+				want := []net.IP{net.ParseIP("10.0.0.1").To4(), net.ParseIP("::1")}
+				if !reflect.DeepEqual(cert.IPAddresses, want) {
+					t.Errorf("unexpected ip addresses: %v", cert.IPAddresses)
+				}
+				if !cert.NotAfter.Equal(now.Add(time.Hour)) {
+					t.Errorf("unexpected expiry: %v", cert.NotAfter)
+				}
+				key, ok := parsePrivateKey(t, data[corev1.TLSPrivateKeyKey]).(*rsa.PrivateKey)
+				if !ok || key.N.BitLen() != 3072 {
+					t.Errorf("unexpected rsa key")
+				}
+			},
+		},
+		"ed25519 key": {
+			spec: `{"spec":{"commonName":"example","keyType":"ed25519"}}`,
+			check: func(t *testing.T, cert *x509.Certificate, data map[string][]byte) {
+				if _, ok := parsePrivateKey(t, data[corev1.TLSPrivateKeyKey]).(ed25519.PrivateKey); !ok {
+					t.Errorf("expected an ed25519 key")
+				}
+			},
+		},
+		"signed by ca": {
+			spec: `{"spec":{"commonName":"example","caSecretRef":{"name":"ca"}}}`,
+			check: func(t *testing.T, cert *x509.Certificate, data map[string][]byte) {
+				if err := cert.CheckSignatureFrom(caCert); err != nil {
+					t.Errorf("expected a certificate signed by the CA: %v", err)
+				}
+				if string(data[corev1.ServiceAccountRootCAKey]) != string(ca[corev1.TLSCertKey]) {
+					t.Errorf("expected the CA certificate as ca.crt")
+				}
+			},
+		},
+		"missing ca secret": {
+			spec:    `{"spec":{"caSecretRef":{"name":"missing"}}}`,
+			wantErr: true,
+		},
+		"ca secret without ca": {
+			spec:    `{"spec":{"caSecretRef":{"name":"leaf"}}}`,
+			wantErr: true,
+		},
+		"invalid ca secret": {
+			spec:    `{"spec":{"caSecretRef":{"name":"invalid"}}}`,
+			wantErr: true,
+		},
+		"invalid ip address": {
+			spec:    `{"spec":{"ipAddresses":["example.com"]}}`,
+			wantErr: true,
+		},
+		"invalid key type": {
+			spec:    `{"spec":{"keyType":"dsa"}}`,
+			wantErr: true,
+		},
+		"invalid key size": {
+			spec:    `{"spec":{"keySize":128}}`,
+			wantErr: true,
+		},
+		"invalid duration": {
+			spec:    `{"spec":{"duration":"-1h"}}`,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := clientfake.NewClientBuilder()
+			for _, s := range secrets {
+				builder = builder.WithObjects(s.DeepCopy())
+			}
+			var spec *apiextensions.JSON
+			if tc.spec != "" {
+				spec = &apiextensions.JSON{Raw: []byte(tc.spec)}
+			}
+			got, err := g.generate(context.Background(), spec, builder.Build(), "default", now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.check != nil {
+				tc.check(t, parseCertificate(t, got[corev1.TLSCertKey]), got)
+			}
+		})
+	}
+}
+
+func parseCertificate(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("unable to decode certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %v", err)
+	}
+	return cert
+}
+
+func parsePrivateKey(t *testing.T, data []byte) interface{} {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("unable to decode private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("unable to parse private key: %v", err)
+	}
+	return key
+}
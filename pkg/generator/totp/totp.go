@@ -0,0 +1,157 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package totp
+
+import (
+	"context"
+	"crypto/hmac"
+	// nolint:gosec
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// Generator creates time-based one-time passcodes.
+type Generator struct{}
+
+const (
+	defaultDigits = 6
+	defaultPeriod = 30
+
+	errNoSpec        = "no config spec provided"
+	errParseSpec     = "unable to parse spec: %w"
+	errFindSecret    = "could not find secret %s/%s: %w"
+	errFindDataKey   = "no data for %q in secret '%s/%s'"
+	errDecodeSeed    = "unable to decode base32 seed: %w"
+	errInvalidDigits = "digits must be between 6 and 8, got %d"
+	errInvalidPeriod = "period must be positive, got %d"
+	errAlgorithm     = "unsupported algorithm %q"
+)
+
+// Generate returns the passcode of the current period and its expiry
+// for the seed referenced by the TOTP resource jsonSpec.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, time.Now())
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, now time.Time) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	digits := res.Spec.Digits
+	if digits == 0 {
+		digits = defaultDigits
+	}
+	if digits < 6 || digits > 8 {
+		return nil, fmt.Errorf(errInvalidDigits, digits)
+	}
+	period := res.Spec.Period
+	if period == 0 {
+		period = defaultPeriod
+	}
+	if period < 0 {
+		return nil, fmt.Errorf(errInvalidPeriod, period)
+	}
+	newHash, err := hashFunc(res.Spec.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	ref := res.Spec.SecretRef
+	var secret corev1.Secret
+	err = kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &secret)
+	if err != nil {
+		return nil, fmt.Errorf(errFindSecret, namespace, ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf(errFindDataKey, ref.Key, namespace, ref.Name)
+	}
+	seed, err := decodeSeed(string(value))
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeSeed, err)
+	}
+	counter := now.Unix() / int64(period)
+	expiresAt := (counter + 1) * int64(period)
+	return map[string][]byte{
+		"token":      []byte(passcode(newHash, seed, uint64(counter), digits)),
+		"expires_at": []byte(strconv.FormatInt(expiresAt, 10)),
+	}, nil
+}
+
+// passcode returns the HOTP value of counter, see RFC 4226 section 5.3.
+func passcode(newHash func() hash.Hash, seed []byte, counter uint64, digits int) string {
+	mac := hmac.New(newHash, seed)
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
+
+// decodeSeed decodes a base32 seed, authenticator apps show them
+// in groups, lowercase and without padding.
+func decodeSeed(seed string) ([]byte, error) {
+	seed = strings.ToUpper(strings.Join(strings.Fields(seed), ""))
+	seed = strings.TrimRight(seed, "=")
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(seed)
+}
+
+func hashFunc(algorithm genv1alpha1.TOTPAlgorithm) (func() hash.Hash, error) {
+	switch algorithm {
+	case "", genv1alpha1.TOTPAlgorithmSHA1:
+		return sha1.New, nil
+	case genv1alpha1.TOTPAlgorithmSHA256:
+		return sha256.New, nil
+	case genv1alpha1.TOTPAlgorithmSHA512:
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf(errAlgorithm, algorithm)
+}
+
+func parseSpec(data []byte) (*genv1alpha1.TOTP, error) {
+	var spec genv1alpha1.TOTP
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.TOTPKind, &Generator{})
+}
@@ -0,0 +1,135 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package totp
+
+import (
+	"context"
+	"encoding/base32"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// the test vectors are taken from RFC 6238 appendix B.
+func TestGenerate(t *testing.T) {
+	seed := func(s string) []byte {
+		return []byte(base32.StdEncoding.EncodeToString([]byte(s)))
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mfa",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"sha1":      seed("12345678901234567890"),
+			"sha256":    seed("12345678901234567890123456789012"),
+			"sha512":    seed("1234567890123456789012345678901234567890123456789012345678901234"),
+			"formatted": []byte("gezd gnbv gy3t qojq gezd gnbv gy3t qojq"),
+			"invalid":   []byte("not base32!"),
+		},
+	}
+	tests := map[string]struct {
+		spec    string
+		now     int64
+		want    map[string][]byte
+		wantErr bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"sha1": {
+			spec: `{"spec":{"secretRef":{"name":"mfa","key":"sha1"},"digits":8}}`,
+			now:  59,
+			want: map[string][]byte{
+				"token":      []byte("94287082"),
+				"expires_at": []byte("60"),
+			},
+		},
+		"sha256": {
+			spec: `{"spec":{"secretRef":{"name":"mfa","key":"sha256"},"algorithm":"SHA256","digits":8}}`,
+			now:  1111111109,
+			want: map[string][]byte{
+				"token":      []byte("68084774"),
+				"expires_at": []byte("1111111110"),
+			},
+		},
+		"sha512": {
+			spec: `{"spec":{"secretRef":{"name":"mfa","key":"sha512"},"algorithm":"SHA512","digits":8}}`,
+			now:  20000000000,
+			want: map[string][]byte{
+				"token":      []byte("47863826"),
+				"expires_at": []byte("20000000010"),
+			},
+		},
+		"defaults with leading zero": {
+			spec: `{"spec":{"secretRef":{"name":"mfa","key":"sha1"}}}`,
+			now:  1111111109,
+			want: map[string][]byte{
+				"token":      []byte("081804"),
+				"expires_at": []byte("1111111110"),
+			},
+		},
+		"formatted seed and period": {
+			spec: `{"spec":{"secretRef":{"name":"mfa","key":"formatted"},"period":60,"digits":8}}`,
+			now:  118,
+			want: map[string][]byte{
+				"token":      []byte("94287082"),
+				"expires_at": []byte("120"),
+			},
+		},
+		"invalid seed": {
+			spec:    `{"spec":{"secretRef":{"name":"mfa","key":"invalid"}}}`,
+			wantErr: true,
+		},
+		"missing key": {
+			spec:    `{"spec":{"secretRef":{"name":"mfa","key":"missing"}}}`,
+			wantErr: true,
+		},
+		"missing secret": {
+			spec:    `{"spec":{"secretRef":{"name":"other","key":"sha1"}}}`,
+			wantErr: true,
+		},
+		"invalid algorithm": {
+			spec:    `{"spec":{"secretRef":{"name":"mfa","key":"sha1"},"algorithm":"MD5"}}`,
+			wantErr: true,
+		},
+		"invalid digits": {
+			spec:    `{"spec":{"secretRef":{"name":"mfa","key":"sha1"},"digits":10}}`,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kube := clientfake.NewClientBuilder().WithObjects(secret).Build()
+			var spec *apiextensions.JSON
+			if tc.spec != "" {
+				spec = &apiextensions.JSON{Raw: []byte(tc.spec)}
+			}
+			g := &Generator{}
+			got, err := g.generate(context.Background(), spec, kube, "default", time.Unix(tc.now, 0))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %q", got)
+			}
+		})
+	}
+}
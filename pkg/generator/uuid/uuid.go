@@ -0,0 +1,114 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uuid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// Generator generates random identifiers.
+type Generator struct{}
+
+const (
+	defaultLength     = 32
+	alphanumericChars = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	errNoSpec        = "no config spec provided"
+	errParseSpec     = "unable to parse spec: %w"
+	errInvalidLength = "length %d must be positive"
+	errUnknownFormat = "unknown format %q"
+	errGenerate      = "unable to generate identifier: %w"
+
+	// UUIDKey is the key of the generated identifier.
+	UUIDKey = "uuid"
+)
+
+// Generate returns a random identifier of the UUID resource jsonSpec.
+func (g *Generator) Generate(_ context.Context, jsonSpec *apiextensions.JSON, _ client.Client, _ string) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	id, err := generate(res.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{UUIDKey: []byte(id)}, nil
+}
+
+// generate returns a uuid or a random string of the configured length.
+func generate(spec genv1alpha1.UUIDSpec) (string, error) {
+	if spec.Format == genv1alpha1.UUIDFormatUUID || spec.Format == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return "", fmt.Errorf(errGenerate, err)
+		}
+		return id.String(), nil
+	}
+	length := defaultLength
+	if spec.Length != nil {
+		length = *spec.Length
+	}
+	if length <= 0 {
+		return "", fmt.Errorf(errInvalidLength, length)
+	}
+	switch spec.Format {
+	case genv1alpha1.UUIDFormatHex:
+		// two hex characters encode one byte.
+		buf := make([]byte, (length+1)/2)
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf(errGenerate, err)
+		}
+		return hex.EncodeToString(buf)[:length], nil
+	case genv1alpha1.UUIDFormatAlphanumeric:
+		max := big.NewInt(int64(len(alphanumericChars)))
+		id := make([]byte, length)
+		for i := range id {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", fmt.Errorf(errGenerate, err)
+			}
+			id[i] = alphanumericChars[n.Int64()]
+		}
+		return string(id), nil
+	default:
+		return "", fmt.Errorf(errUnknownFormat, spec.Format)
+	}
+}
+
+func parseSpec(data []byte) (*genv1alpha1.UUID, error) {
+	var spec genv1alpha1.UUID
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.UUIDKind, &Generator{})
+}
@@ -0,0 +1,75 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uuid
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		spec    *apiextensions.JSON
+		want    *regexp.Regexp
+		wantErr bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"default uuid": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{}}`)},
+			want: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		"uuid ignores length": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{"format":"uuid","length":4}}`)},
+			want: regexp.MustCompile(`^[0-9a-f-]{36}$`),
+		},
+		"hex default length": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{"format":"hex"}}`)},
+			want: regexp.MustCompile(`^[0-9a-f]{32}$`),
+		},
+		"hex odd length": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{"format":"hex","length":7}}`)},
+			want: regexp.MustCompile(`^[0-9a-f]{7}$`),
+		},
+		"alphanumeric": {
+			spec: &apiextensions.JSON{Raw: []byte(`{"spec":{"format":"alphanumeric","length":64}}`)},
+			want: regexp.MustCompile(`^[0-9a-zA-Z]{64}$`),
+		},
+		"invalid length": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"format":"hex","length":0}}`)},
+			wantErr: true,
+		},
+		"unknown format": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"format":"base64"}}`)},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.Generate(context.Background(), tc.spec, nil, "default")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.want != nil && !tc.want.Match(got[UUIDKey]) {
+				t.Errorf("unexpected identifier: %s", got[UUIDKey])
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	provider "github.com/external-secrets/external-secrets/pkg/provider/vault"
+)
+
+// Generator reads dynamic secrets from Vault.
+type Generator struct{}
+
+const (
+	defaultHTTPMethod = http.MethodGet
+
+	errNoSpec        = "no config spec provided"
+	errNoState       = "no state provided"
+	errParseSpec     = "unable to parse spec: %w"
+	errMissingPath   = "no path provided"
+	errMissingVault  = "no Vault provider config provided"
+	errVaultClient   = "unable to setup Vault client: %w"
+	errParseParams   = "unable to parse parameters: %w"
+	errVaultRequest  = "unable to request dynamic secret: %w"
+	errParseResponse = "unable to parse response: %w"
+	errEmptyResponse = "empty response from Vault for path %s"
+	errEncodeValue   = "unable to encode value of key %s: %w"
+	errEncodeState   = "unable to encode state: %w"
+	errParseState    = "unable to parse state: %w"
+	errRevokeLease   = "unable to revoke lease %s: %w"
+	errRenewLease    = "unable to renew lease %s: %w"
+	errNotRenewable  = "lease %s is not renewable"
+	errEmptyRenewal  = "empty response from Vault"
+	errLookupToken   = "unable to look up token: %w"
+	errRenewToken    = "unable to renew token of lease %s: %w"
+	errRevokeToken   = "unable to revoke token: %w"
+)
+
+// leaseState is the generator state of a dynamic secret with a lease.
+type leaseState struct {
+	LeaseID   string `json:"leaseID"`
+	Renewable bool   `json:"renewable,omitempty"`
+	// TokenAccessor is the accessor of the token the generator logged in with.
+	// The lease is revoked together with the token, so the token lives as long as the lease.
+	TokenAccessor string `json:"tokenAccessor,omitempty"`
+}
+
+type vaultClientFunc func(ctx context.Context, vaultSpec *esv1beta1.VaultProvider, kube client.Client, namespace string) (provider.Client, error)
+
+// Generate requests the dynamic secret of the VaultDynamicSecret resource jsonSpec.
+// It returns the data of the secret and, if Vault issued a lease,
+// the lease_id, lease_duration and renewable keys. Keys of the data take precedence.
+// The lease and the token it was requested with are not revoked, see GenerateWithState.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	data, _, err := g.generate(ctx, jsonSpec, kube, namespace, provider.NewGeneratorClient)
+	return data, err
+}
+
+// GenerateWithState works like Generate, the state holds the lease of the secret
+// and the token it was requested with, so that they are revoked once the secret is no longer used.
+func (g *Generator) GenerateWithState(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, *apiextensions.JSON, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, provider.NewGeneratorClient)
+}
+
+// Cleanup revokes the lease and the token recorded in state.
+func (g *Generator) Cleanup(ctx context.Context, jsonSpec, state *apiextensions.JSON, kube client.Client, namespace string) error {
+	return g.cleanup(ctx, jsonSpec, state, kube, namespace, provider.NewGeneratorClient)
+}
+
+// Renew renews the lease and the token recorded in state.
+func (g *Generator) Renew(ctx context.Context, jsonSpec, state *apiextensions.JSON, kube client.Client, namespace string) (time.Time, error) {
+	return g.renew(ctx, jsonSpec, state, kube, namespace, provider.NewGeneratorClient, time.Now())
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, newClient vaultClientFunc) (map[string][]byte, *apiextensions.JSON, error) {
+	res, c, err := newSpecClient(ctx, jsonSpec, kube, namespace, newClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := strings.TrimPrefix(res.Spec.Path, "/")
+	method := strings.ToUpper(res.Spec.Method)
+	if method == "" {
+		method = defaultHTTPMethod
+	}
+	var params map[string]interface{}
+	if res.Spec.Parameters != nil && method != http.MethodGet {
+		if err := json.Unmarshal(res.Spec.Parameters.Raw, &params); err != nil {
+			return nil, nil, revokeToken(ctx, c, res, fmt.Errorf(errParseParams, err))
+		}
+	}
+	secret, err := request(ctx, c, method, "/v1/"+path, params)
+	if err != nil {
+		return nil, nil, revokeToken(ctx, c, res, fmt.Errorf(errVaultRequest, err))
+	}
+	if secret == nil || (secret.Data == nil && secret.LeaseID == "") {
+		return nil, nil, revokeToken(ctx, c, res, fmt.Errorf(errEmptyResponse, path))
+	}
+	data, err := secretData(secret)
+	if err != nil {
+		return nil, nil, revokeToken(ctx, c, res, err)
+	}
+	if secret.LeaseID == "" {
+		// nothing depends on the token.
+		if err := revokeToken(ctx, c, res, nil); err != nil {
+			return nil, nil, err
+		}
+		return data, nil, nil
+	}
+	lease := leaseState{LeaseID: secret.LeaseID, Renewable: secret.Renewable}
+	if loggedIn(res) {
+		self, err := request(ctx, c, http.MethodGet, "/v1/auth/token/lookup-self", nil)
+		if err == nil {
+			lease.TokenAccessor, err = self.TokenAccessor()
+		}
+		if err != nil {
+			return nil, nil, revokeToken(ctx, c, res, fmt.Errorf(errLookupToken, err))
+		}
+	}
+	raw, err := json.Marshal(lease)
+	if err != nil {
+		return nil, nil, revokeToken(ctx, c, res, fmt.Errorf(errEncodeState, err))
+	}
+	return data, &apiextensions.JSON{Raw: raw}, nil
+}
+
+func (g *Generator) cleanup(ctx context.Context, jsonSpec, state *apiextensions.JSON, kube client.Client, namespace string, newClient vaultClientFunc) error {
+	if state == nil {
+		return nil
+	}
+	var lease leaseState
+	if err := json.Unmarshal(state.Raw, &lease); err != nil {
+		return fmt.Errorf(errParseState, err)
+	}
+	if lease.LeaseID == "" {
+		return nil
+	}
+	res, c, err := newSpecClient(ctx, jsonSpec, kube, namespace, newClient)
+	if err != nil {
+		return err
+	}
+	if _, err := request(ctx, c, http.MethodPut, "/v1/sys/leases/revoke", map[string]string{"lease_id": lease.LeaseID}); err != nil {
+		return revokeToken(ctx, c, res, fmt.Errorf(errRevokeLease, lease.LeaseID, err))
+	}
+	if lease.TokenAccessor != "" {
+		_, err := request(ctx, c, http.MethodPost, "/v1/auth/token/revoke-accessor", map[string]string{"accessor": lease.TokenAccessor})
+		// Vault rejects the accessor of a token that was already revoked or expired.
+		var respErr *vault.ResponseError
+		if err != nil && !(errors.As(err, &respErr) && respErr.StatusCode == http.StatusBadRequest) {
+			return revokeToken(ctx, c, res, fmt.Errorf(errRevokeToken, err))
+		}
+	}
+	return revokeToken(ctx, c, res, nil)
+}
+
+// renew renews the token and then the lease recorded in state,
+// the lease is revoked once the token expires.
+func (g *Generator) renew(ctx context.Context, jsonSpec, state *apiextensions.JSON, kube client.Client, namespace string, newClient vaultClientFunc, now time.Time) (time.Time, error) {
+	if state == nil {
+		return time.Time{}, errors.New(errNoState)
+	}
+	var lease leaseState
+	if err := json.Unmarshal(state.Raw, &lease); err != nil {
+		return time.Time{}, fmt.Errorf(errParseState, err)
+	}
+	if lease.LeaseID == "" || !lease.Renewable {
+		return time.Time{}, fmt.Errorf(errNotRenewable, lease.LeaseID)
+	}
+	res, c, err := newSpecClient(ctx, jsonSpec, kube, namespace, newClient)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var tokenTTL time.Duration
+	if lease.TokenAccessor != "" {
+		token, err := request(ctx, c, http.MethodPost, "/v1/auth/token/renew-accessor", map[string]string{"accessor": lease.TokenAccessor})
+		if err == nil && (token == nil || token.Auth == nil) {
+			err = errors.New(errEmptyRenewal)
+		}
+		if err != nil {
+			return time.Time{}, revokeToken(ctx, c, res, fmt.Errorf(errRenewToken, lease.LeaseID, err))
+		}
+		tokenTTL = time.Duration(token.Auth.LeaseDuration) * time.Second
+	}
+	renewed, err := request(ctx, c, http.MethodPut, "/v1/sys/leases/renew", map[string]string{"lease_id": lease.LeaseID})
+	if err == nil && renewed == nil {
+		err = errors.New(errEmptyRenewal)
+	}
+	if err != nil {
+		return time.Time{}, revokeToken(ctx, c, res, fmt.Errorf(errRenewLease, lease.LeaseID, err))
+	}
+	ttl := time.Duration(renewed.LeaseDuration) * time.Second
+	if tokenTTL > 0 && tokenTTL < ttl {
+		ttl = tokenTTL
+	}
+	return now.Add(ttl), revokeToken(ctx, c, res, nil)
+}
+
+// request sends a request with the JSON body to Vault and returns the parsed response,
+// which is nil for responses without a body.
+func request(ctx context.Context, c provider.Client, method, path string, body interface{}) (*vault.Secret, error) {
+	req := c.NewRequest(method, path)
+	if body != nil {
+		if err := req.SetJSONBody(body); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.RawRequestWithContext(ctx, req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vault.ParseSecret(resp.Body)
+}
+
+// loggedIn reports whether the client logged in with the auth method of the provider,
+// instead of using a static token.
+func loggedIn(res *genv1alpha1.VaultDynamicSecret) bool {
+	return res.Spec.Provider.Auth.TokenSecretRef == nil
+}
+
+// revokeToken revokes the token the client logged in with and returns err,
+// or the error of the revocation if err is nil.
+func revokeToken(ctx context.Context, c provider.Client, res *genv1alpha1.VaultDynamicSecret, err error) error {
+	if !loggedIn(res) || c.Token() == "" {
+		return err
+	}
+	_, revokeErr := request(ctx, c, http.MethodPost, "/v1/auth/token/revoke-self", nil)
+	c.ClearToken()
+	if err == nil && revokeErr != nil {
+		return fmt.Errorf(errRevokeToken, revokeErr)
+	}
+	return err
+}
+
+// newSpecClient parses the VaultDynamicSecret resource jsonSpec
+// and returns a client for its Vault provider.
+func newSpecClient(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, newClient vaultClientFunc) (*genv1alpha1.VaultDynamicSecret, provider.Client, error) {
+	if jsonSpec == nil {
+		return nil, nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errParseSpec, err)
+	}
+	if res.Spec.Provider == nil {
+		return nil, nil, errors.New(errMissingVault)
+	}
+	if strings.TrimPrefix(res.Spec.Path, "/") == "" {
+		return nil, nil, errors.New(errMissingPath)
+	}
+	c, err := newClient(ctx, res.Spec.Provider, kube, namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errVaultClient, err)
+	}
+	return res, c, nil
+}
+
+// secretData returns the data of secret. Strings are used as they are,
+// other values are encoded as JSON.
+func secretData(secret *vault.Secret) (map[string][]byte, error) {
+	data := make(map[string][]byte, len(secret.Data)+3)
+	if secret.LeaseID != "" {
+		data["lease_id"] = []byte(secret.LeaseID)
+		data["lease_duration"] = []byte(strconv.Itoa(secret.LeaseDuration))
+		data["renewable"] = []byte(strconv.FormatBool(secret.Renewable))
+	}
+	for k, v := range secret.Data {
+		switch t := v.(type) {
+		case string:
+			data[k] = []byte(t)
+		case nil:
+			data[k] = nil
+		default:
+			b, err := json.Marshal(t)
+			if err != nil {
+				return nil, fmt.Errorf(errEncodeValue, k, err)
+			}
+			data[k] = b
+		}
+	}
+	return data, nil
+}
+
+// Expiry returns the expiration of the lease of the dynamic secret in data.
+// It is called right after the secret was requested, so the lease starts now.
+func (g *Generator) Expiry(data map[string][]byte) (time.Time, bool) {
+	seconds, err := strconv.Atoi(string(data["lease_duration"]))
+	if err != nil || seconds <= 0 {
+		return time.Time{}, false
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second), true
+}
+
+func parseSpec(data []byte) (*genv1alpha1.VaultDynamicSecret, error) {
+	var spec genv1alpha1.VaultDynamicSecret
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.VaultDynamicSecretKind, &Generator{})
+}
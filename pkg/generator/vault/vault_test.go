@@ -0,0 +1,368 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	provider "github.com/external-secrets/external-secrets/pkg/provider/vault"
+)
+
+// newTestClient returns a vaultClientFunc of clients of srv which are logged in with a token.
+func newTestClient(srv *httptest.Server) vaultClientFunc {
+	return func(ctx context.Context, vaultSpec *esv1beta1.VaultProvider, kube client.Client, namespace string) (provider.Client, error) {
+		cfg := vault.DefaultConfig()
+		cfg.Address = srv.URL
+		c, err := vault.NewClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		c.SetToken("token")
+		return c, nil
+	}
+}
+
+// tokenHandler serves the token endpoints of Vault and the other requests with next.
+// It records the revoked token and if the client revoked its own token.
+type tokenHandler struct {
+	next        http.HandlerFunc
+	revokedSelf bool
+	revoked     string
+	renewed     string
+}
+
+func (h *tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]string
+	switch r.URL.Path {
+	case "/v1/auth/token/lookup-self":
+		_, _ = w.Write([]byte(`{"data":{"accessor":"accessor","ttl":3600}}`))
+	case "/v1/auth/token/revoke-self":
+		h.revokedSelf = true
+		w.WriteHeader(http.StatusNoContent)
+	case "/v1/auth/token/revoke-accessor":
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		h.revoked = body["accessor"]
+		w.WriteHeader(http.StatusNoContent)
+	case "/v1/auth/token/renew-accessor":
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		h.renewed = body["accessor"]
+		_, _ = w.Write([]byte(`{"auth":{"accessor":"accessor","lease_duration":1800}}`))
+	default:
+		h.next(w, r)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dbCreds := `{"lease_id":"database/creds/my-role/abc","lease_duration":3600,"renewable":true,"data":{"username":"v-generated","password":"s3cr3t"}}`
+	tests := map[string]struct {
+		spec           string
+		status         int
+		response       string
+		wantMethod     string
+		wantPath       string
+		wantBody       map[string]interface{}
+		want           map[string][]byte
+		wantState      string
+		wantRevokeSelf bool
+		wantErr        bool
+	}{
+		"no spec": {
+			wantErr: true,
+		},
+		"missing path": {
+			spec:    `{"spec":{"provider":{}}}`,
+			wantErr: true,
+		},
+		"missing provider": {
+			spec:    `{"spec":{"path":"database/creds/my-role"}}`,
+			wantErr: true,
+		},
+		"database credentials": {
+			spec:       `{"spec":{"path":"database/creds/my-role","provider":{}}}`,
+			status:     http.StatusOK,
+			response:   dbCreds,
+			wantMethod: http.MethodGet,
+			wantPath:   "/v1/database/creds/my-role",
+			want: map[string][]byte{
+				"username":       []byte("v-generated"),
+				"password":       []byte("s3cr3t"),
+				"lease_id":       []byte("database/creds/my-role/abc"),
+				"lease_duration": []byte("3600"),
+				"renewable":      []byte("true"),
+			},
+			wantState: `{"leaseID":"database/creds/my-role/abc","renewable":true,"tokenAccessor":"accessor"}`,
+		},
+		"database credentials with static token": {
+			spec:       `{"spec":{"path":"database/creds/my-role","provider":{"auth":{"tokenSecretRef":{"name":"vault","key":"token"}}}}}`,
+			status:     http.StatusOK,
+			response:   dbCreds,
+			wantMethod: http.MethodGet,
+			wantPath:   "/v1/database/creds/my-role",
+			want: map[string][]byte{
+				"username":       []byte("v-generated"),
+				"password":       []byte("s3cr3t"),
+				"lease_id":       []byte("database/creds/my-role/abc"),
+				"lease_duration": []byte("3600"),
+				"renewable":      []byte("true"),
+			},
+			wantState: `{"leaseID":"database/creds/my-role/abc","renewable":true}`,
+		},
+		"pki issue with parameters": {
+			spec:       `{"spec":{"method":"post","path":"/pki/issue/my-role","parameters":{"common_name":"example.com"},"provider":{}}}`,
+			status:     http.StatusOK,
+			response:   `{"data":{"certificate":"CERT","ca_chain":["CA"],"serial_number":"01"}}`,
+			wantMethod: http.MethodPost,
+			wantPath:   "/v1/pki/issue/my-role",
+			wantBody:   map[string]interface{}{"common_name": "example.com"},
+			want: map[string][]byte{
+				"certificate":   []byte("CERT"),
+				"ca_chain":      []byte(`["CA"]`),
+				"serial_number": []byte("01"),
+			},
+			wantRevokeSelf: true,
+		},
+		"empty response": {
+			spec:           `{"spec":{"path":"database/creds/my-role","provider":{}}}`,
+			status:         http.StatusNoContent,
+			wantPath:       "/v1/database/creds/my-role",
+			wantRevokeSelf: true,
+			wantErr:        true,
+		},
+		"request error": {
+			spec:           `{"spec":{"path":"database/creds/my-role","provider":{}}}`,
+			status:         http.StatusForbidden,
+			response:       `{"errors":["permission denied"]}`,
+			wantPath:       "/v1/database/creds/my-role",
+			wantRevokeSelf: true,
+			wantErr:        true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &tokenHandler{next: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tc.wantPath {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if tc.wantMethod != "" && r.Method != tc.wantMethod {
+					t.Errorf("unexpected method: %s", r.Method)
+				}
+				if tc.wantBody != nil {
+					b, _ := io.ReadAll(r.Body)
+					var body map[string]interface{}
+					if err := json.Unmarshal(b, &body); err != nil || !reflect.DeepEqual(body, tc.wantBody) {
+						t.Errorf("unexpected body: %s", b)
+					}
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}}
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+			var spec *apiextensions.JSON
+			if tc.spec != "" {
+				spec = &apiextensions.JSON{Raw: []byte(tc.spec)}
+			}
+			g := &Generator{}
+			got, state, err := g.generate(context.Background(), spec, nil, "default", newTestClient(srv))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+			if (state == nil && tc.wantState != "") || (state != nil && string(state.Raw) != tc.wantState) {
+				t.Errorf("unexpected state: %v", state)
+			}
+			if h.revokedSelf != tc.wantRevokeSelf {
+				t.Errorf("unexpected revocation of the token: %v", h.revokedSelf)
+			}
+		})
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	spec := &apiextensions.JSON{Raw: []byte(`{"spec":{"path":"database/creds/my-role","provider":{}}}`)}
+	tests := map[string]struct {
+		state          string
+		status         int
+		wantLease      string
+		wantToken      string
+		wantRevokeSelf bool
+		wantErr        bool
+	}{
+		"no lease": {
+			state: `{}`,
+		},
+		"revoke lease": {
+			state:          `{"leaseID":"database/creds/my-role/abc"}`,
+			status:         http.StatusNoContent,
+			wantLease:      "database/creds/my-role/abc",
+			wantRevokeSelf: true,
+		},
+		"revoke lease and token": {
+			state:          `{"leaseID":"database/creds/my-role/abc","tokenAccessor":"accessor"}`,
+			status:         http.StatusNoContent,
+			wantLease:      "database/creds/my-role/abc",
+			wantToken:      "accessor",
+			wantRevokeSelf: true,
+		},
+		"revoke error": {
+			state:          `{"leaseID":"database/creds/my-role/abc","tokenAccessor":"accessor"}`,
+			status:         http.StatusForbidden,
+			wantLease:      "database/creds/my-role/abc",
+			wantRevokeSelf: true,
+			wantErr:        true,
+		},
+		"invalid state": {
+			state:   `[]`,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var revoked string
+			h := &tokenHandler{next: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/sys/leases/revoke" || r.Method != http.MethodPut {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				revoked = body["lease_id"]
+				w.WriteHeader(tc.status)
+			}}
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+			g := &Generator{}
+			err := g.cleanup(context.Background(), spec, &apiextensions.JSON{Raw: []byte(tc.state)}, nil, "default", newTestClient(srv))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if revoked != tc.wantLease {
+				t.Errorf("unexpected revoked lease: %q", revoked)
+			}
+			if h.revoked != tc.wantToken {
+				t.Errorf("unexpected revoked token: %q", h.revoked)
+			}
+			if h.revokedSelf != tc.wantRevokeSelf {
+				t.Errorf("unexpected revocation of the token: %v", h.revokedSelf)
+			}
+		})
+	}
+}
+
+func TestRenew(t *testing.T) {
+	spec := &apiextensions.JSON{Raw: []byte(`{"spec":{"path":"database/creds/my-role","provider":{}}}`)}
+	now := time.Now()
+	tests := map[string]struct {
+		state     string
+		status    int
+		response  string
+		want      time.Time
+		wantLease string
+		wantToken string
+		wantErr   bool
+	}{
+		"renew lease": {
+			state:     `{"leaseID":"database/creds/my-role/abc","renewable":true}`,
+			status:    http.StatusOK,
+			response:  `{"lease_id":"database/creds/my-role/abc","lease_duration":3600,"renewable":true}`,
+			want:      now.Add(time.Hour),
+			wantLease: "database/creds/my-role/abc",
+		},
+		"renew lease and token": {
+			state:     `{"leaseID":"database/creds/my-role/abc","renewable":true,"tokenAccessor":"accessor"}`,
+			status:    http.StatusOK,
+			response:  `{"lease_id":"database/creds/my-role/abc","lease_duration":3600,"renewable":true}`,
+			want:      now.Add(30 * time.Minute),
+			wantLease: "database/creds/my-role/abc",
+			wantToken: "accessor",
+		},
+		"not renewable": {
+			state:   `{"leaseID":"database/creds/my-role/abc"}`,
+			wantErr: true,
+		},
+		"renew error": {
+			state:     `{"leaseID":"database/creds/my-role/abc","renewable":true}`,
+			status:    http.StatusBadRequest,
+			response:  `{"errors":["lease not found"]}`,
+			wantLease: "database/creds/my-role/abc",
+			wantErr:   true,
+		},
+		"invalid state": {
+			state:   `[]`,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var renewed string
+			h := &tokenHandler{next: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/sys/leases/renew" || r.Method != http.MethodPut {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				renewed = body["lease_id"]
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}}
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+			g := &Generator{}
+			got, err := g.renew(context.Background(), spec, &apiextensions.JSON{Raw: []byte(tc.state)}, nil, "default", newTestClient(srv), now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("unexpected expiry: %v", got)
+			}
+			if renewed != tc.wantLease || h.renewed != tc.wantToken {
+				t.Errorf("unexpected renewed lease %q and token %q", renewed, h.renewed)
+			}
+			if h.revokedSelf != (tc.wantLease != "") {
+				t.Errorf("unexpected revocation of the token: %v", h.revokedSelf)
+			}
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	g := &Generator{}
+	before := time.Now()
+	expiry, ok := g.Expiry(map[string][]byte{"lease_duration": []byte("3600")})
+	if !ok || expiry.Before(before.Add(time.Hour)) || expiry.After(time.Now().Add(time.Hour)) {
+		t.Errorf("unexpected expiry: %v, %v", expiry, ok)
+	}
+	for _, data := range []map[string][]byte{
+		{"username": []byte("foo")},
+		{"lease_duration": []byte("0")},
+	} {
+		if _, ok := g.Expiry(data); ok {
+			t.Errorf("expected an unknown expiry of %q", data)
+		}
+	}
+}
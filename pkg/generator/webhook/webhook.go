@@ -0,0 +1,79 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/provider/webhook"
+)
+
+// Generator calls webhooks and generates the fields of the response.
+type Generator struct{}
+
+const (
+	errNoSpec     = "no config spec provided"
+	errParseSpec  = "unable to parse spec: %w"
+	errMissingURL = "no url provided"
+	errWebhook    = "unable to call webhook: %w"
+)
+
+// Generate calls the webhook of the Webhook resource jsonSpec and returns the fields
+// of the response object.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	if res.Spec.URL == "" {
+		return nil, errors.New(errMissingURL)
+	}
+	data, err := webhook.GetGeneratorData(ctx, &esv1beta1.WebhookProvider{
+		Method:     res.Spec.Method,
+		URL:        res.Spec.URL,
+		Headers:    res.Spec.Headers,
+		Body:       res.Spec.Body,
+		Timeout:    res.Spec.Timeout,
+		Result:     res.Spec.Result,
+		Secrets:    res.Spec.Secrets,
+		CABundle:   res.Spec.CABundle,
+		CAProvider: res.Spec.CAProvider,
+	}, kube, namespace)
+	if err != nil {
+		return nil, fmt.Errorf(errWebhook, err)
+	}
+	return data, nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.Webhook, error) {
+	var spec genv1alpha1.Webhook
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.WebhookKind, &Generator{})
+}
@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/token":
+			_, _ = w.Write([]byte(`{"token":"generated","expires_in":3600,"scopes":["pull"]}`))
+		case "/nested":
+			_, _ = w.Write([]byte(`{"data":{"username":"robot","password":"generated"}}`))
+		default:
+			_, _ = w.Write([]byte(`["not","an","object"]`))
+		}
+	}))
+	defer srv.Close()
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}).Build()
+	spec := func(path, result string) *apiextensions.JSON {
+		raw := `{"spec":{"url":"` + srv.URL + path + `","headers":{"Authorization":"Bearer {{ .auth.token }}"},` +
+			`"secrets":[{"name":"auth","secretRef":{"name":"auth"}}]` + result + `}}`
+		return &apiextensions.JSON{Raw: []byte(raw)}
+	}
+	tests := map[string]struct {
+		spec    *apiextensions.JSON
+		want    map[string][]byte
+		wantErr string
+	}{
+		"no spec": {
+			wantErr: "no config spec",
+		},
+		"missing url": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{}}`)},
+			wantErr: "no url",
+		},
+		"response fields": {
+			spec: spec("/token", ""),
+			want: map[string][]byte{
+				"token":      []byte("generated"),
+				"expires_in": []byte("3600"),
+				"scopes":     []byte(`["pull"]`),
+			},
+		},
+		"result path": {
+			spec: spec("/nested", `,"result":{"jsonPath":"$.data"}`),
+			want: map[string][]byte{
+				"username": []byte("robot"),
+				"password": []byte("generated"),
+			},
+		},
+		"no object": {
+			spec:    spec("/list", ""),
+			wantErr: "wrong type",
+		},
+		"missing secret": {
+			spec:    &apiextensions.JSON{Raw: []byte(`{"spec":{"url":"` + srv.URL + `","secrets":[{"name":"auth","secretRef":{"name":"missing"}}]}}`)},
+			wantErr: "missing",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.Generate(context.Background(), tc.spec, kube, "default")
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: expected %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected data: %v", got)
+			}
+		})
+	}
+}
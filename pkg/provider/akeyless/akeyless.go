@@ -16,19 +16,40 @@ package akeyless
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/akeylesslabs/akeyless-go/v2"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 const (
 	defaultAPIUrl = "https://api.akeyless.io"
+
+	errInvalidGWApiURL   = "invalid akeylessGWApiURL %s: must be an absolute http or https url"
+	errInvalidCABundle   = "failed to append caBundle"
+	errUnknownCAProvider = "unknown caProvider type given"
+	errCANamespace       = "cannot read secret for CAProvider due to missing namespace on kind ClusterSecretStore"
+	errCANamespaceStore  = "namespace of caProvider is only allowed with a ClusterSecretStore"
+	errFetchCAProvider   = "could not fetch caProvider %s: %w"
+	errMissingCAKey      = "missing key %s in caProvider %s"
+	errMissingK8sAuth    = "missing accessID or k8sConfName in authSecretRef.kubernetesAuth"
+	errInvalidSARef      = "invalid authSecretRef.kubernetesAuth.serviceAccountRef: %w"
+	errPropertyNotFound  = "property %s does not exist in secret %s"
+	errFindByTags        = "find by tags is not supported by akeyless"
+	errInvalidVersion    = "invalid version %s: must be a version number"
 )
 
 // Provider satisfies the provider interface.
@@ -42,6 +63,12 @@ type akeylessBase struct {
 
 	akeylessGwAPIURL string
 	RestAPI          *akeyless.V2ApiService
+	// httpClient is used for the API endpoints the akeyless-go client does not cover.
+	httpClient *http.Client
+
+	// dynamicSecrets holds the produced values by item name. Every dynamic secret is
+	// produced once per client, so that all properties of it belong to the same credentials.
+	dynamicSecrets map[string]string
 }
 
 type Akeyless struct {
@@ -50,6 +77,7 @@ type Akeyless struct {
 
 type akeylessVaultInterface interface {
 	GetSecretByType(secretName, token string, version int32) (string, error)
+	ListSecrets(path, token string) ([]string, error)
 	TokenFromSecretRef(ctx context.Context) (string, error)
 }
 
@@ -65,10 +93,40 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 }
 
 func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	spec, err := GetAKeylessProvider(store)
+	if err != nil {
+		return err
+	}
+	if spec.AkeylessGWApiURL != nil && *spec.AkeylessGWApiURL != "" {
+		u, err := url.Parse(*spec.AkeylessGWApiURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf(errInvalidGWApiURL, *spec.AkeylessGWApiURL)
+		}
+	}
+	if spec.CAProvider != nil {
+		isClusterKind := store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind
+		if isClusterKind && spec.CAProvider.Namespace == nil {
+			return fmt.Errorf(errCANamespace)
+		}
+		if !isClusterKind && spec.CAProvider.Namespace != nil {
+			return fmt.Errorf(errCANamespaceStore)
+		}
+	}
+	if spec.Auth != nil && spec.Auth.KubernetesAuth != nil {
+		k8sAuth := spec.Auth.KubernetesAuth
+		if k8sAuth.AccessID == "" || k8sAuth.K8sConfName == "" {
+			return fmt.Errorf(errMissingK8sAuth)
+		}
+		if k8sAuth.ServiceAccountRef != nil {
+			if err := utils.ValidateServiceAccountSelector(store, *k8sAuth.ServiceAccountRef); err != nil {
+				return fmt.Errorf(errInvalidSARef, err)
+			}
+		}
+	}
 	return nil
 }
 
-func newClient(_ context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
+func newClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
 	akl := &akeylessBase{
 		kube:      kube,
 		store:     store,
@@ -79,16 +137,21 @@ func newClient(_ context.Context, store esv1beta1.GenericStore, kube client.Clie
 	if err != nil {
 		return nil, err
 	}
-	akeylessGwAPIURL := defaultAPIUrl
-	if spec != nil && spec.AkeylessGWApiURL != nil && *spec.AkeylessGWApiURL != "" {
-		akeylessGwAPIURL = getV2Url(*spec.AkeylessGWApiURL)
-	}
-
 	if spec.Auth == nil {
 		return nil, fmt.Errorf("missing Auth in store config")
 	}
 
+	httpClient, err := akl.getHTTPClient(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	akeylessGwAPIURL := defaultAPIUrl
+	if spec.AkeylessGWApiURL != nil && *spec.AkeylessGWApiURL != "" {
+		akeylessGwAPIURL = getV2Url(*spec.AkeylessGWApiURL, httpClient)
+	}
+
 	RestAPIClient := akeyless.NewAPIClient(&akeyless.Configuration{
+		HTTPClient: httpClient,
 		Servers: []akeyless.ServerConfiguration{
 			{
 				URL: akeylessGwAPIURL,
@@ -98,9 +161,76 @@ func newClient(_ context.Context, store esv1beta1.GenericStore, kube client.Clie
 
 	akl.akeylessGwAPIURL = akeylessGwAPIURL
 	akl.RestAPI = RestAPIClient
+	akl.httpClient = httpClient
 	return &Akeyless{Client: akl}, nil
 }
 
+// getHTTPClient returns a client that trusts the CA of the Akeyless Gateway, if one is configured.
+func (a *akeylessBase) getHTTPClient(ctx context.Context, spec *esv1beta1.AkeylessProvider) (*http.Client, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	if len(spec.CABundle) == 0 && spec.CAProvider == nil {
+		return client, nil
+	}
+	caCertPool := x509.NewCertPool()
+	if len(spec.CABundle) > 0 && !caCertPool.AppendCertsFromPEM(spec.CABundle) {
+		return nil, fmt.Errorf(errInvalidCABundle)
+	}
+	if spec.CAProvider != nil {
+		cert, err := a.getCAProviderCert(ctx, spec.CAProvider)
+		if err != nil {
+			return nil, err
+		}
+		if !caCertPool.AppendCertsFromPEM(cert) {
+			return nil, fmt.Errorf(errInvalidCABundle)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    caCertPool,
+		MinVersion: tls.VersionTLS12,
+	}
+	client.Transport = transport
+	return client, nil
+}
+
+func (a *akeylessBase) getCAProviderCert(ctx context.Context, caProvider *esv1beta1.CAProvider) ([]byte, error) {
+	objKey := client.ObjectKey{
+		Name:      caProvider.Name,
+		Namespace: a.namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if a.store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind {
+		if caProvider.Namespace == nil {
+			return nil, fmt.Errorf(errCANamespace)
+		}
+		objKey.Namespace = *caProvider.Namespace
+	}
+	switch caProvider.Type {
+	case esv1beta1.CAProviderTypeSecret:
+		secret := &corev1.Secret{}
+		if err := a.kube.Get(ctx, objKey, secret); err != nil {
+			return nil, fmt.Errorf(errFetchCAProvider, caProvider.Name, err)
+		}
+		cert, ok := secret.Data[caProvider.Key]
+		if !ok {
+			return nil, fmt.Errorf(errMissingCAKey, caProvider.Key, caProvider.Name)
+		}
+		return cert, nil
+	case esv1beta1.CAProviderTypeConfigMap:
+		configMap := &corev1.ConfigMap{}
+		if err := a.kube.Get(ctx, objKey, configMap); err != nil {
+			return nil, fmt.Errorf(errFetchCAProvider, caProvider.Name, err)
+		}
+		cert, ok := configMap.Data[caProvider.Key]
+		if !ok {
+			return nil, fmt.Errorf(errMissingCAKey, caProvider.Key, caProvider.Name)
+		}
+		return []byte(cert), nil
+	default:
+		return nil, fmt.Errorf(errUnknownCAProvider)
+	}
+}
+
 func (a *Akeyless) Close(ctx context.Context) error {
 	return nil
 }
@@ -109,6 +239,24 @@ func (a *Akeyless) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts a version number of the secret.
+func (a *Akeyless) ValidateVersion(version string) error {
+	_, err := parseVersion(version)
+	return err
+}
+
+// parseVersion returns the version number of version, 0 selects the latest version.
+func parseVersion(version string) (int32, error) {
+	if version == "" {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(version, 10, 32)
+	if err != nil || i < 1 {
+		return 0, fmt.Errorf(errInvalidVersion, version)
+	}
+	return int32(i), nil
+}
+
 // Implements store.Client.GetSecret Interface.
 // Retrieves a secret with the secret name defined in ref.Name.
 func (a *Akeyless) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
@@ -120,24 +268,68 @@ func (a *Akeyless) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDa
 	if err != nil {
 		return nil, err
 	}
-	version := int32(0)
-	if ref.Version != "" {
-		i, err := strconv.ParseInt(ref.Version, 10, 32)
-		if err == nil {
-			version = int32(i)
-		}
+	version, err := parseVersion(ref.Version)
+	if err != nil {
+		return nil, err
 	}
 	value, err := a.Client.GetSecretByType(ref.Key, token, version)
 	if err != nil {
 		return nil, err
 	}
-	return []byte(value), nil
+	if ref.Property == "" {
+		return []byte(value), nil
+	}
+	val, ok := utils.GetProperty([]byte(value), ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
 }
 
-// Empty GetAllSecrets.
+// Implements store.Client.GetAllSecrets Interface.
+// Retrieves the static secrets below ref.Path whose name matches ref.Name.
+// Names are matched and returned without the leading slash.
 func (a *Akeyless) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	// TO be implemented
-	return nil, fmt.Errorf("GetAllSecrets not implemented")
+	if utils.IsNil(a.Client) {
+		return nil, fmt.Errorf(errUninitalizedAkeylessProvider)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindByTags)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	path := "/"
+	if ref.Path != nil {
+		path = *ref.Path
+	}
+
+	token, err := a.Client.TokenFromSecretRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := a.Client.ListSecrets(path, token)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string][]byte)
+	for _, name := range names {
+		key := strings.TrimPrefix(name, "/")
+		if matcher != nil && !matcher.MatchName(key) {
+			continue
+		}
+		value, err := a.Client.GetSecretByType(name, token, 0)
+		if err != nil {
+			return nil, err
+		}
+		data[key] = []byte(value)
+	}
+	return utils.ConvertKeys(ref.ConversionStrategy, data)
 }
 
 // Implements store.Client.GetSecretMap Interface.
@@ -151,8 +343,8 @@ func (a *Akeyless) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecre
 	if err != nil {
 		return nil, err
 	}
-	// Maps the json data to a string:string map
-	kv := make(map[string]string)
+	// Maps the json data to a string:json map, values of other types than string are kept as JSON
+	kv := make(map[string]json.RawMessage)
 	err = json.Unmarshal(val, &kv)
 	if err != nil {
 		return nil, fmt.Errorf(errJSONSecretUnmarshal, err)
@@ -161,7 +353,12 @@ func (a *Akeyless) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecre
 	// Converts values in K:V pairs into bytes, while leaving keys as strings
 	secretData := make(map[string][]byte)
 	for k, v := range kv {
-		secretData[k] = []byte(v)
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
 	}
 	return secretData, nil
 }
@@ -15,14 +15,18 @@ limitations under the License.
 package akeyless
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	aws_cloud_id "github.com/akeylesslabs/akeyless-go-cloud-id/cloudprovider/aws"
 	azure_cloud_id "github.com/akeylesslabs/akeyless-go-cloud-id/cloudprovider/azure"
@@ -35,7 +39,6 @@ var apiErr akeyless.GenericOpenAPIError
 const DefServiceAccountFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 
 func (a *akeylessBase) GetToken(accessID, accType, accTypeParam string) (string, error) {
-	ctx := context.Background()
 	authBody := akeyless.NewAuthWithDefaults()
 	authBody.AccessId = akeyless.PtrString(accessID)
 	if accType == "api_key" || accType == "access_key" {
@@ -45,10 +48,7 @@ func (a *akeylessBase) GetToken(accessID, accType, accTypeParam string) (string,
 		if err != nil {
 			return "", fmt.Errorf("failed to read JWT with Kubernetes Auth from %v. error: %w", DefServiceAccountFile, err)
 		}
-		K8SAuthConfigName := accTypeParam
-		authBody.AccessType = akeyless.PtrString(accType)
-		authBody.K8sServiceAccountToken = akeyless.PtrString(jwtString)
-		authBody.K8sAuthConfigName = akeyless.PtrString(K8SAuthConfigName)
+		return a.GetK8sToken(accessID, accTypeParam, jwtString)
 	} else {
 		cloudID, err := a.getCloudID(accType, accTypeParam)
 		if err != nil {
@@ -57,7 +57,22 @@ func (a *akeylessBase) GetToken(accessID, accType, accTypeParam string) (string,
 		authBody.AccessType = akeyless.PtrString(accType)
 		authBody.CloudId = akeyless.PtrString(cloudID)
 	}
+	return a.auth(authBody)
+}
+
+// GetK8sToken authenticates with the Kubernetes auth method k8sConfName,
+// jwtString is the base64 encoded token of a ServiceAccount.
+func (a *akeylessBase) GetK8sToken(accessID, k8sConfName, jwtString string) (string, error) {
+	authBody := akeyless.NewAuthWithDefaults()
+	authBody.AccessId = akeyless.PtrString(accessID)
+	authBody.AccessType = akeyless.PtrString("k8s")
+	authBody.K8sServiceAccountToken = akeyless.PtrString(jwtString)
+	authBody.K8sAuthConfigName = akeyless.PtrString(k8sConfName)
+	return a.auth(authBody)
+}
 
+func (a *akeylessBase) auth(authBody *akeyless.Auth) (string, error) {
+	ctx := context.Background()
 	authOut, _, err := a.RestAPI.Auth(ctx).Body(*authBody).Execute()
 	if err != nil {
 		if errors.As(err, &apiErr) {
@@ -83,7 +98,15 @@ func (a *akeylessBase) GetSecretByType(secretName, token string, version int32)
 	case "DYNAMIC_SECRET":
 		return a.GetDynamicSecrets(secretName, token)
 	case "ROTATED_SECRET":
-		return a.GetRotatedSecrets(secretName, token, version)
+		value, err := a.GetRotatedSecrets(secretName, token, version)
+		if err != nil {
+			return "", err
+		}
+		return withRotationMetadata(value, item, version)
+	case "CERTIFICATE":
+		return a.GetCertificate(secretName, token, version)
+	case "CLASSIC_KEY":
+		return a.GetClassicKey(secretName, item, token, version)
 	default:
 		return "", fmt.Errorf("invalid item type: %v", secretType)
 	}
@@ -157,7 +180,42 @@ func (a *akeylessBase) GetRotatedSecrets(secretName, token string, version int32
 	return string(out), nil
 }
 
+// withRotationMetadata adds the rotation details of a rotated secret to its value
+// as the field rotation, if the value is a JSON object.
+func withRotationMetadata(value string, item *akeyless.Item, version int32) (string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return value, nil
+	}
+	if version == 0 {
+		version = item.GetLastVersion()
+	}
+	metadata := map[string]interface{}{
+		"version": version,
+	}
+	for _, v := range item.GetItemVersions() {
+		if v.GetVersion() == version && v.CreationDate != nil {
+			metadata["last_rotation_date"] = v.CreationDate.UTC().Format(time.RFC3339)
+		}
+	}
+	if item.NextRotationDate != nil {
+		metadata["next_rotation_date"] = item.NextRotationDate.UTC().Format(time.RFC3339)
+	}
+	if details := item.GetItemGeneralInfo(); details.RotatedSecretDetails != nil && details.RotatedSecretDetails.GetLastRotationError() != "" {
+		metadata["last_rotation_error"] = details.RotatedSecretDetails.GetLastRotationError()
+	}
+	fields["rotation"] = metadata
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("can't marshal rotated secret value: %w", err)
+	}
+	return string(out), nil
+}
+
 func (a *akeylessBase) GetDynamicSecrets(secretName, token string) (string, error) {
+	if value, ok := a.dynamicSecrets[secretName]; ok {
+		return value, nil
+	}
 	ctx := context.Background()
 
 	body := akeyless.GetDynamicSecretValue{
@@ -181,7 +239,10 @@ func (a *akeylessBase) GetDynamicSecrets(secretName, token string) (string, erro
 	if err != nil {
 		return "", fmt.Errorf("can't marshal dynamic secret value: %w", err)
 	}
-
+	if a.dynamicSecrets == nil {
+		a.dynamicSecrets = make(map[string]string)
+	}
+	a.dynamicSecrets[secretName] = string(out)
 	return string(out), nil
 }
 
@@ -214,6 +275,143 @@ func (a *akeylessBase) GetStaticSecret(secretName, token string, version int32)
 	return val, nil
 }
 
+// ListSecrets returns the names of the static secrets below path.
+func (a *akeylessBase) ListSecrets(path, token string) ([]string, error) {
+	ctx := context.Background()
+
+	body := akeyless.ListItems{
+		Path: &path,
+		Type: &[]string{"static-secret"},
+	}
+	if strings.HasPrefix(token, "u-") {
+		body.UidToken = &token
+	} else {
+		body.Token = &token
+	}
+
+	var names []string
+	for {
+		out, _, err := a.RestAPI.ListItems(ctx).Body(body).Execute()
+		if err != nil {
+			if errors.As(err, &apiErr) {
+				return nil, fmt.Errorf("can't list items: %v", string(apiErr.Body()))
+			}
+			return nil, fmt.Errorf("can't list items: %w", err)
+		}
+		for _, item := range out.GetItems() {
+			names = append(names, item.GetItemName())
+		}
+		if out.GetNextPage() == "" {
+			break
+		}
+		body.PaginationToken = out.NextPage
+	}
+	return names, nil
+}
+
+// itemValueRequest is the request body of the item value endpoints which are not
+// part of the akeyless-go client.
+type itemValueRequest struct {
+	Name     string  `json:"name"`
+	Version  *int32  `json:"version,omitempty"`
+	Token    *string `json:"token,omitempty"`
+	UIDToken *string `json:"uid-token,omitempty"`
+}
+
+func newItemValueRequest(name, token string, version int32) itemValueRequest {
+	body := itemValueRequest{Name: name}
+	if version != 0 {
+		body.Version = &version
+	}
+	if strings.HasPrefix(token, "u-") {
+		body.UIDToken = &token
+	} else {
+		body.Token = &token
+	}
+	return body
+}
+
+// GetCertificate returns the certificate, its chain and the private key of a certificate item as JSON,
+// with the fields certificate, certificate_chain and private_key.
+func (a *akeylessBase) GetCertificate(secretName, token string, version int32) (string, error) {
+	var out struct {
+		CertificatePem string `json:"certificate_pem"`
+		PrivateKeyPem  string `json:"private_key_pem"`
+	}
+	if err := a.postAPI("/get-certificate-value", newItemValueRequest(secretName, token, version), &out); err != nil {
+		return "", fmt.Errorf("can't get certificate value: %w", err)
+	}
+	certificate, chain := splitCertificateChain(out.CertificatePem)
+	value, err := json.Marshal(map[string]string{
+		"certificate":       certificate,
+		"certificate_chain": chain,
+		"private_key":       out.PrivateKeyPem,
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't marshal certificate value: %w", err)
+	}
+	return string(value), nil
+}
+
+// GetClassicKey returns the exported key material of a classic key as JSON, with the fields key,
+// public_key and certificate. The key must be exportable.
+func (a *akeylessBase) GetClassicKey(secretName string, item *akeyless.Item, token string, version int32) (string, error) {
+	var out struct {
+		Key string `json:"key"`
+	}
+	if err := a.postAPI("/export-classic-key", newItemValueRequest(secretName, token, version), &out); err != nil {
+		return "", fmt.Errorf("can't export classic key: %w", err)
+	}
+	value, err := json.Marshal(map[string]string{
+		"key":         out.Key,
+		"public_key":  item.GetPublicValue(),
+		"certificate": item.GetCertificates(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't marshal classic key: %w", err)
+	}
+	return string(value), nil
+}
+
+// splitCertificateChain splits a PEM bundle into the leaf certificate and the remaining chain.
+func splitCertificateChain(bundle string) (string, string) {
+	block, rest := pem.Decode([]byte(bundle))
+	if block == nil {
+		return bundle, ""
+	}
+	return string(pem.EncodeToMemory(block)), strings.TrimSpace(string(rest))
+}
+
+// postAPI sends body as JSON to the endpoint path of the Akeyless API and decodes the response into out.
+func (a *akeylessBase) postAPI(path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	client := a.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, strings.TrimSuffix(a.akeylessGwAPIURL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%v", string(resBody))
+	}
+	return json.Unmarshal(resBody, out)
+}
+
 func (a *akeylessBase) getCloudID(provider, accTypeParam string) (string, error) {
 	var cloudID string
 	var err error
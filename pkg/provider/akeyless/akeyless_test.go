@@ -15,12 +15,30 @@ package akeyless
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/akeylesslabs/akeyless-go/v2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 	fakeakeyless "github.com/external-secrets/external-secrets/pkg/provider/akeyless/fake"
 )
 
@@ -104,10 +122,26 @@ func TestAkeylessGetSecret(t *testing.T) {
 		smtc.expectedSecret = secretValue
 	}
 
+	// good case: a property of a JSON secret, e.g. of a dynamic secret
+	setProperty := func(smtc *akeylessTestCase) {
+		smtc.apiOutput.Value = `{"user":"tmp_user","password":"tmp_pass","ttl_in_minutes":"60"}`
+		smtc.ref.Property = "password"
+		smtc.expectedSecret = "tmp_pass"
+	}
+
+	// bad case: missing property
+	setMissingProperty := func(smtc *akeylessTestCase) {
+		smtc.apiOutput.Value = `{"user":"tmp_user"}`
+		smtc.ref.Property = "password"
+		smtc.expectError = "property password does not exist in secret test-secret"
+	}
+
 	successCases := []*akeylessTestCase{
 		makeValidAkeylessTestCaseCustom(setAPIErr),
 		makeValidAkeylessTestCaseCustom(setSecretString),
 		makeValidAkeylessTestCaseCustom(setNilMockClient),
+		makeValidAkeylessTestCaseCustom(setProperty),
+		makeValidAkeylessTestCaseCustom(setMissingProperty),
 	}
 
 	sm := Akeyless{}
@@ -137,8 +171,17 @@ func TestGetSecretMap(t *testing.T) {
 		smtc.expectError = "unable to unmarshal secret"
 	}
 
+	// good case: values other than strings are kept as JSON
+	setNonStringValues := func(smtc *akeylessTestCase) {
+		smtc.apiOutput.Value = `{"user":"tmp_user","ttl":60,"roles":["read"]}`
+		smtc.expectedData["user"] = []byte("tmp_user")
+		smtc.expectedData["ttl"] = []byte("60")
+		smtc.expectedData["roles"] = []byte(`["read"]`)
+	}
+
 	successCases := []*akeylessTestCase{
 		makeValidAkeylessTestCaseCustom(setDeserialization),
+		makeValidAkeylessTestCaseCustom(setNonStringValues),
 		makeValidAkeylessTestCaseCustom(setInvalidJSON),
 		makeValidAkeylessTestCaseCustom(setAPIErr),
 		makeValidAkeylessTestCaseCustom(setNilMockClient),
@@ -157,6 +200,503 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func makeStore(gwAPIURL string, tweaks ...func(*esv1beta1.AkeylessProvider)) *esv1beta1.SecretStore {
+	provider := &esv1beta1.AkeylessProvider{
+		AkeylessGWApiURL: pointer.StringPtr(gwAPIURL),
+		Auth:             &esv1beta1.AkeylessAuth{},
+	}
+	for _, fn := range tweaks {
+		fn(provider)
+	}
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{Akeyless: provider},
+		},
+	}
+}
+
+func TestNewClientGatewayCA(t *testing.T) {
+	var requests int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"error":"unknown command"}`))
+	}))
+	defer srv.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway-ca", Namespace: "default"},
+		Data:       map[string]string{"ca.crt": string(caPEM)},
+	}).Build()
+
+	tests := map[string]struct {
+		store        *esv1beta1.SecretStore
+		expectError  string
+		expectProbed bool
+	}{
+		"untrusted gateway": {
+			store: makeStore(srv.URL),
+		},
+		"caBundle": {
+			store: makeStore(srv.URL, func(p *esv1beta1.AkeylessProvider) {
+				p.CABundle = caPEM
+			}),
+			expectProbed: true,
+		},
+		"caProvider": {
+			store: makeStore(srv.URL, func(p *esv1beta1.AkeylessProvider) {
+				p.CAProvider = &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeConfigMap, Name: "gateway-ca", Key: "ca.crt"}
+			}),
+			expectProbed: true,
+		},
+		"missing caProvider key": {
+			store: makeStore(srv.URL, func(p *esv1beta1.AkeylessProvider) {
+				p.CAProvider = &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeConfigMap, Name: "gateway-ca", Key: "tls.crt"}
+			}),
+			expectError: "missing key tls.crt in caProvider gateway-ca",
+		},
+		"invalid caBundle": {
+			store: makeStore(srv.URL, func(p *esv1beta1.AkeylessProvider) {
+				p.CABundle = []byte("not a certificate")
+			}),
+			expectError: errInvalidCABundle,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			requests = 0
+			_, err := newClient(context.Background(), tc.store, kube, "default")
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if probed := requests > 0; probed != tc.expectProbed {
+				t.Errorf("unexpected gateway request: expected %v, got %v", tc.expectProbed, probed)
+			}
+		})
+	}
+}
+
+func TestKubernetesAuthTokenRequest(t *testing.T) {
+	var requestedNamespace string
+	var requestedAudiences []string
+	clientset := kfake.NewSimpleClientset()
+	clientset.PrependReactor("create", "serviceaccounts", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return true, nil, errors.New("unexpected subresource")
+		}
+		tr := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenRequest)
+		requestedNamespace = action.GetNamespace()
+		requestedAudiences = tr.Spec.Audiences
+		tr.Status.Token = "sa-token"
+		return true, tr, nil
+	})
+	defer func(orig func() (typedcorev1.ServiceAccountsGetter, error)) { newServiceAccountsClient = orig }(newServiceAccountsClient)
+	newServiceAccountsClient = func() (typedcorev1.ServiceAccountsGetter, error) {
+		return clientset.CoreV1(), nil
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body akeyless.Auth
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.URL.Path != "/auth" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if body.GetAccessType() != "k8s" || body.GetAccessId() != "p-k8s" || body.GetK8sAuthConfigName() != "tenant-a" ||
+			body.GetK8sServiceAccountToken() != base64.StdEncoding.EncodeToString([]byte("sa-token")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"access denied"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token":"t-akeyless"}`))
+	}))
+	defer srv.Close()
+
+	store := makeStore(srv.URL, func(p *esv1beta1.AkeylessProvider) {
+		p.Auth.KubernetesAuth = &esv1beta1.AkeylessKubernetesAuth{
+			AccessID:          "p-k8s",
+			K8sConfName:       "tenant-a",
+			ServiceAccountRef: &esmeta.ServiceAccountSelector{Name: "tenant-a"},
+			Audiences:         []string{"akeyless"},
+		}
+	})
+	akl := &akeylessBase{
+		kube:      clientfake.NewClientBuilder().Build(),
+		store:     store,
+		namespace: "tenant-a-ns",
+		RestAPI: akeyless.NewAPIClient(&akeyless.Configuration{
+			Servers: []akeyless.ServerConfiguration{{URL: srv.URL}},
+		}).V2Api,
+	}
+	token, err := akl.TokenFromSecretRef(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "t-akeyless" || requestedNamespace != "tenant-a-ns" || !reflect.DeepEqual(requestedAudiences, []string{"akeyless"}) {
+		t.Errorf("unexpected token request: token %s, namespace %s, audiences %v", token, requestedNamespace, requestedAudiences)
+	}
+
+	store.Spec.Provider.Akeyless.Auth.KubernetesAuth.K8sConfName = "tenant-b"
+	if _, err := akl.TokenFromSecretRef(context.Background()); !ErrorContains(err, "authentication failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	akl.store = &esv1beta1.ClusterSecretStore{
+		TypeMeta: metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+		Spec:     store.Spec,
+	}
+	if _, err := akl.TokenFromSecretRef(context.Background()); !ErrorContains(err, errInvalidClusterStoreMissingSANamespace) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDynamicSecretProducedOncePerClient(t *testing.T) {
+	var produced int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/describe-item":
+			w.Write([]byte(`{"item_type":"DYNAMIC_SECRET"}`))
+		case "/get-dynamic-secret-value":
+			produced++
+			fmt.Fprintf(w, `{"user":"tmp_user_%d","password":"tmp_pass_%d","ttl_in_minutes":"60"}`, produced, produced)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	newAkeyless := func() *Akeyless {
+		return &Akeyless{Client: &tokenClient{akeylessBase: &akeylessBase{
+			RestAPI: akeyless.NewAPIClient(&akeyless.Configuration{
+				Servers: []akeyless.ServerConfiguration{{URL: srv.URL}},
+			}).V2Api,
+		}}}
+	}
+
+	a := newAkeyless()
+	user, err := a.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "/db/producer", Property: "user"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	password, err := a.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "/db/producer", Property: "password"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(user) != "tmp_user_1" || string(password) != "tmp_pass_1" || produced != 1 {
+		t.Errorf("unexpected credentials: %s %s produced %d times", user, password, produced)
+	}
+
+	// a new client produces new credentials.
+	user, err = newAkeyless().GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "/db/producer", Property: "user"})
+	if err != nil || string(user) != "tmp_user_2" {
+		t.Errorf("unexpected credentials: %s, %v", user, err)
+	}
+}
+
+func TestRotatedSecretMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.URL.Path == "/describe-item":
+			w.Write([]byte(`{
+				"item_type": "ROTATED_SECRET",
+				"last_version": 2,
+				"next_rotation_date": "2022-05-08T00:00:00Z",
+				"item_versions": [
+					{"version": 1, "creation_date": "2022-04-24T00:00:00Z"},
+					{"version": 2, "creation_date": "2022-05-01T00:00:00Z"}
+				],
+				"item_general_info": {"rotated_secret_details": {"last_rotation_error": ""}}
+			}`))
+		case r.URL.Path == "/get-rotated-secret-value" && body["names"] == "/db/rotated":
+			w.Write([]byte(`{"value": {"target_value": {"username": "app", "password": "rotated"}}}`))
+		case r.URL.Path == "/get-rotated-secret-value" && body["names"] == "/custom/rotated":
+			w.Write([]byte(`{"value": {"payload": "plain-value"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	a := &Akeyless{Client: &tokenClient{akeylessBase: &akeylessBase{
+		RestAPI: akeyless.NewAPIClient(&akeyless.Configuration{
+			Servers: []akeyless.ServerConfiguration{{URL: srv.URL}},
+		}).V2Api,
+	}}}
+
+	tests := map[string]struct {
+		ref  esv1beta1.ExternalSecretDataRemoteRef
+		want string
+	}{
+		"current value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/db/rotated", Property: "password"},
+			want: "rotated",
+		},
+		"last rotation": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/db/rotated", Property: "rotation.last_rotation_date"},
+			want: "2022-05-01T00:00:00Z",
+		},
+		"rotation of version": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/db/rotated", Version: "1", Property: "rotation.last_rotation_date"},
+			want: "2022-04-24T00:00:00Z",
+		},
+		"whole value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/db/rotated"},
+			want: `{"password":"rotated","rotation":{"last_rotation_date":"2022-05-01T00:00:00Z","next_rotation_date":"2022-05-08T00:00:00Z","version":2},"username":"app"}`,
+		},
+		"payload": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/custom/rotated"},
+			want: "plain-value",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := a.GetSecret(context.Background(), tc.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestCertificateAndClassicKey(t *testing.T) {
+	const (
+		leaf  = "-----BEGIN CERTIFICATE-----\nbGVhZg==\n-----END CERTIFICATE-----\n"
+		chain = "-----BEGIN CERTIFICATE-----\naW50ZXJtZWRpYXRl\n-----END CERTIFICATE-----"
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.URL.Path == "/describe-item" && body["name"] == "/tls/cert":
+			w.Write([]byte(`{"item_type":"CERTIFICATE"}`))
+		case r.URL.Path == "/describe-item" && body["name"] == "/keys/signing":
+			w.Write([]byte(`{"item_type":"CLASSIC_KEY","public_value":"public-key","certificates":"key-cert"}`))
+		case r.URL.Path == "/get-certificate-value" && body["token"] == "t-token":
+			json.NewEncoder(w).Encode(map[string]string{
+				"certificate_pem": leaf + chain,
+				"private_key_pem": "private-key",
+			})
+		case r.URL.Path == "/export-classic-key" && body["version"] == float64(2):
+			w.Write([]byte(`{"key":"classic-key-v2"}`))
+		case r.URL.Path == "/export-classic-key":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"key is not exportable"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	a := &Akeyless{Client: &tokenClient{akeylessBase: &akeylessBase{
+		akeylessGwAPIURL: srv.URL,
+		RestAPI: akeyless.NewAPIClient(&akeyless.Configuration{
+			Servers: []akeyless.ServerConfiguration{{URL: srv.URL}},
+		}).V2Api,
+	}}}
+
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"certificate": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/tls/cert", Property: "certificate"},
+			want: leaf,
+		},
+		"certificate chain": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/tls/cert", Property: "certificate_chain"},
+			want: chain,
+		},
+		"certificate private key": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/tls/cert", Property: "private_key"},
+			want: "private-key",
+		},
+		"classic key": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/keys/signing", Version: "2", Property: "key"},
+			want: "classic-key-v2",
+		},
+		"classic public key": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/keys/signing", Version: "2", Property: "public_key"},
+			want: "public-key",
+		},
+		"classic key certificate": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/keys/signing", Version: "2", Property: "certificate"},
+			want: "key-cert",
+		},
+		"classic key not exportable": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "/keys/signing", Property: "key"},
+			expectError: "can't export classic key: {\"error\":\"key is not exportable\"}",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := a.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Fatalf("unexpected error: %v, expected %s", err, tc.expectError)
+			}
+			if string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.URL.Path == "/list-items" && body["path"] == "/prod" && body["pagination-token"] == nil:
+			w.Write([]byte(`{"items":[{"item_name":"/prod/payments/db"},{"item_name":"/prod/search/db"}],"next_page":"2"}`))
+		case r.URL.Path == "/list-items" && body["path"] == "/prod" && body["pagination-token"] == "2":
+			w.Write([]byte(`{"items":[{"item_name":"/prod/payments/api"}]}`))
+		case r.URL.Path == "/describe-item":
+			w.Write([]byte(`{"item_type":"STATIC_SECRET"}`))
+		case r.URL.Path == "/get-secret-value":
+			name := body["names"].([]interface{})[0].(string)
+			json.NewEncoder(w).Encode(map[string]string{name: "value of " + name})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	a := &Akeyless{Client: &tokenClient{akeylessBase: &akeylessBase{
+		RestAPI: akeyless.NewAPIClient(&akeyless.Configuration{
+			Servers: []akeyless.ServerConfiguration{{URL: srv.URL}},
+		}).V2Api,
+	}}}
+
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		expectError string
+	}{
+		"find by name": {
+			ref: esv1beta1.ExternalSecretFind{
+				Path:               pointer.String("/prod"),
+				Name:               &esv1beta1.FindName{RegExp: "^prod/payments/.*"},
+				ConversionStrategy: esv1beta1.ExternalSecretConversionDefault,
+			},
+			want: map[string][]byte{
+				"prod_payments_db":  []byte("value of /prod/payments/db"),
+				"prod_payments_api": []byte("value of /prod/payments/api"),
+			},
+		},
+		"find by path": {
+			ref: esv1beta1.ExternalSecretFind{
+				Path:               pointer.String("/prod"),
+				ConversionStrategy: esv1beta1.ExternalSecretConversionDefault,
+			},
+			want: map[string][]byte{
+				"prod_payments_db":  []byte("value of /prod/payments/db"),
+				"prod_search_db":    []byte("value of /prod/search/db"),
+				"prod_payments_api": []byte("value of /prod/payments/api"),
+			},
+		},
+		"list error": {
+			ref:         esv1beta1.ExternalSecretFind{Path: pointer.String("/missing")},
+			expectError: "can't list items",
+		},
+		"find by tags": {
+			ref:         esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "payments"}},
+			expectError: errFindByTags,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := a.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Fatalf("unexpected error: %v, expected %s", err, tc.expectError)
+			}
+			if !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secrets: expected %v, got %v", tc.want, out)
+			}
+		})
+	}
+}
+
+// tokenClient skips the authentication of akeylessBase.
+type tokenClient struct {
+	*akeylessBase
+}
+
+func (c *tokenClient) TokenFromSecretRef(ctx context.Context) (string, error) {
+	return "t-token", nil
+}
+
+func TestValidateStore(t *testing.T) {
+	clusterStore := func(store *esv1beta1.SecretStore) esv1beta1.GenericStore {
+		return &esv1beta1.ClusterSecretStore{
+			TypeMeta: metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+			Spec:     store.Spec,
+		}
+	}
+	withCAProvider := func(namespace *string) func(*esv1beta1.AkeylessProvider) {
+		return func(p *esv1beta1.AkeylessProvider) {
+			p.CAProvider = &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeSecret, Name: "ca", Key: "ca.crt", Namespace: namespace}
+		}
+	}
+	withKubernetesAuth := func(accessID string, ref *esmeta.ServiceAccountSelector) func(*esv1beta1.AkeylessProvider) {
+		return func(p *esv1beta1.AkeylessProvider) {
+			p.Auth.KubernetesAuth = &esv1beta1.AkeylessKubernetesAuth{AccessID: accessID, K8sConfName: "conf", ServiceAccountRef: ref}
+		}
+	}
+	tests := map[string]struct {
+		store       esv1beta1.GenericStore
+		expectError string
+	}{
+		"default url": {
+			store: makeStore(""),
+		},
+		"gateway url": {
+			store: makeStore("https://akeyless-gw.example.com:8080", withCAProvider(nil)),
+		},
+		"invalid url": {
+			store:       makeStore("akeyless-gw.example.com"),
+			expectError: "invalid akeylessGWApiURL akeyless-gw.example.com",
+		},
+		"caProvider namespace in SecretStore": {
+			store:       makeStore("", withCAProvider(pointer.StringPtr("foo"))),
+			expectError: errCANamespaceStore,
+		},
+		"caProvider of ClusterSecretStore": {
+			store: clusterStore(makeStore("", withCAProvider(pointer.StringPtr("foo")))),
+		},
+		"missing caProvider namespace in ClusterSecretStore": {
+			store:       clusterStore(makeStore("", withCAProvider(nil))),
+			expectError: errCANamespace,
+		},
+		"kubernetes auth": {
+			store: makeStore("", withKubernetesAuth("p-k8s", nil)),
+		},
+		"kubernetes auth missing accessID": {
+			store:       makeStore("", withKubernetesAuth("", nil)),
+			expectError: errMissingK8sAuth,
+		},
+		"kubernetes auth service account namespace in SecretStore": {
+			store:       makeStore("", withKubernetesAuth("p-k8s", &esmeta.ServiceAccountSelector{Name: "sa", Namespace: pointer.StringPtr("foo")})),
+			expectError: "invalid authSecretRef.kubernetesAuth.serviceAccountRef: namespace not allowed with namespaced SecretStore",
+		},
+		"kubernetes auth missing service account namespace in ClusterSecretStore": {
+			store:       clusterStore(makeStore("", withKubernetesAuth("p-k8s", &esmeta.ServiceAccountSelector{Name: "sa"}))),
+			expectError: "invalid authSecretRef.kubernetesAuth.serviceAccountRef: cluster scope requires namespace",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -16,10 +16,16 @@ package akeyless
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -31,13 +37,35 @@ const (
 	errFetchSAKSecret                          = "could not fetch AccessType secret: %w"
 	errMissingSAK                              = "missing SecretAccessKey"
 	errMissingAKID                             = "missing AccessKeyID"
+	errInvalidClusterStoreMissingSANamespace   = "invalid ClusterSecretStore: missing ServiceAccount Namespace"
+	errServiceAccountToken                     = "could not request token for service account %s: %w"
 )
 
+// serviceAccountTokenTTL is the lifetime of tokens requested for Kubernetes auth.
+const serviceAccountTokenTTL int64 = 600
+
+// newServiceAccountsClient returns a client for the service accounts of the cluster
+// external-secrets runs in. It is a variable so tests can replace it.
+var newServiceAccountsClient = func() (typedcorev1.ServiceAccountsGetter, error) {
+	cfg, err := ctrlcfg.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.CoreV1(), nil
+}
+
 func (a *akeylessBase) TokenFromSecretRef(ctx context.Context) (string, error) {
 	prov, err := GetAKeylessProvider(a.store)
 	if err != nil {
 		return "", err
 	}
+	if prov.Auth.KubernetesAuth != nil {
+		return a.tokenFromKubernetesAuth(ctx, prov.Auth.KubernetesAuth)
+	}
 
 	ke := client.ObjectKey{
 		Name:      prov.Auth.SecretRef.AccessID.Name,
@@ -90,7 +118,7 @@ func (a *akeylessBase) TokenFromSecretRef(ctx context.Context) (string, error) {
 	}
 	accessID := string(accessIDSecret.Data[prov.Auth.SecretRef.AccessID.Key])
 	accessType := string(accessTypeSecret.Data[prov.Auth.SecretRef.AccessType.Key])
-	accessTypeParam := string(accessTypeSecret.Data[prov.Auth.SecretRef.AccessTypeParam.Key])
+	accessTypeParam := string(accessTypeParamSecret.Data[prov.Auth.SecretRef.AccessTypeParam.Key])
 
 	if accessID == "" {
 		return "", fmt.Errorf(errMissingSAK)
@@ -101,3 +129,36 @@ func (a *akeylessBase) TokenFromSecretRef(ctx context.Context) (string, error) {
 
 	return a.GetToken(accessID, accessType, accessTypeParam)
 }
+
+// tokenFromKubernetesAuth authenticates with a token requested for the referenced
+// ServiceAccount, or with the token of external-secrets if none is referenced.
+func (a *akeylessBase) tokenFromKubernetesAuth(ctx context.Context, auth *esv1beta1.AkeylessKubernetesAuth) (string, error) {
+	if auth.ServiceAccountRef == nil {
+		return a.GetToken(auth.AccessID, "k8s", auth.K8sConfName)
+	}
+	ref := auth.ServiceAccountRef
+	namespace := a.namespace
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if a.store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind {
+		if ref.Namespace == nil {
+			return "", fmt.Errorf(errInvalidClusterStoreMissingSANamespace)
+		}
+		namespace = *ref.Namespace
+	}
+	saClient, err := newServiceAccountsClient()
+	if err != nil {
+		return "", fmt.Errorf(errServiceAccountToken, ref.Name, err)
+	}
+	ttl := serviceAccountTokenTTL
+	tokenRequest, err := saClient.ServiceAccounts(namespace).CreateToken(ctx, ref.Name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         auth.Audiences,
+			ExpirationSeconds: &ttl,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf(errServiceAccountToken, ref.Name, err)
+	}
+	jwtString := base64.StdEncoding.EncodeToString([]byte(tokenRequest.Status.Token))
+	return a.GetK8sToken(auth.AccessID, auth.K8sConfName, jwtString)
+}
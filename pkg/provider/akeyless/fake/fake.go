@@ -18,7 +18,8 @@ import (
 )
 
 type AkeylessMockClient struct {
-	getSecret func(secretName, token string, version int32) (string, error)
+	getSecret   func(secretName, token string, version int32) (string, error)
+	listSecrets func(path, token string) ([]string, error)
 }
 
 func (mc *AkeylessMockClient) TokenFromSecretRef(ctx context.Context) (string, error) {
@@ -29,6 +30,13 @@ func (mc *AkeylessMockClient) GetSecretByType(secretName, token string, version
 	return mc.getSecret(secretName, token, version)
 }
 
+func (mc *AkeylessMockClient) ListSecrets(path, token string) ([]string, error) {
+	if mc.listSecrets == nil {
+		return nil, nil
+	}
+	return mc.listSecrets(path, token)
+}
+
 func (mc *AkeylessMockClient) WithValue(in *Input, out *Output) {
 	if mc != nil {
 		mc.getSecret = func(secretName, token string, version int32) (string, error) {
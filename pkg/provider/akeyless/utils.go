@@ -19,7 +19,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -52,9 +51,9 @@ func GetAKeylessProvider(store esv1beta1.GenericStore) (*esv1beta1.AkeylessProvi
 	return prov, nil
 }
 
-func getV2Url(path string) string {
+func getV2Url(path string, client *http.Client) string {
 	// add check if not v2
-	rebody := sendReq(path)
+	rebody := sendReq(path, client)
 	if strings.Contains(rebody, "unknown command") {
 		return path
 	}
@@ -78,16 +77,13 @@ func getV2Url(path string) string {
 	return p
 }
 
-func sendReq(url string) string {
+func sendReq(url string, client *http.Client) string {
 	req, err := http.NewRequest("POST", url, http.NoBody)
 	if err != nil {
 		return ""
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return ""
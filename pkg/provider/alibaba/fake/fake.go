@@ -20,10 +20,15 @@ import (
 
 type AlibabaMockClient struct {
 	getSecretValue func(request *kmssdk.GetSecretValueRequest) (response *kmssdk.GetSecretValueResponse, err error)
+	listSecrets    func(request *kmssdk.ListSecretsRequest) (response *kmssdk.ListSecretsResponse, err error)
 }
 
-func (mc *AlibabaMockClient) GetSecretValue(*kmssdk.GetSecretValueRequest) (result *kmssdk.GetSecretValueResponse, err error) {
-	return mc.getSecretValue(&kmssdk.GetSecretValueRequest{})
+func (mc *AlibabaMockClient) GetSecretValue(request *kmssdk.GetSecretValueRequest) (result *kmssdk.GetSecretValueResponse, err error) {
+	return mc.getSecretValue(request)
+}
+
+func (mc *AlibabaMockClient) ListSecrets(request *kmssdk.ListSecretsRequest) (result *kmssdk.ListSecretsResponse, err error) {
+	return mc.listSecrets(request)
 }
 
 func (mc *AlibabaMockClient) WithValue(in *kmssdk.GetSecretValueRequest, val *kmssdk.GetSecretValueResponse, err error) {
@@ -33,3 +38,31 @@ func (mc *AlibabaMockClient) WithValue(in *kmssdk.GetSecretValueRequest, val *km
 		}
 	}
 }
+
+// WithSecrets serves the secrets by name, listing them pageSize per page.
+func (mc *AlibabaMockClient) WithSecrets(pageSize int, secrets []kmssdk.Secret, values map[string]string) {
+	if mc != nil {
+		mc.listSecrets = func(request *kmssdk.ListSecretsRequest) (*kmssdk.ListSecretsResponse, error) {
+			page, err := request.PageNumber.GetValue()
+			if err != nil {
+				return nil, err
+			}
+			start, end := (page-1)*pageSize, page*pageSize
+			if start > len(secrets) {
+				start = len(secrets)
+			}
+			if end > len(secrets) {
+				end = len(secrets)
+			}
+			return &kmssdk.ListSecretsResponse{
+				PageNumber: page,
+				PageSize:   pageSize,
+				TotalCount: len(secrets),
+				SecretList: kmssdk.SecretList{Secret: secrets[start:end]},
+			}, nil
+		}
+		mc.getSecretValue = func(request *kmssdk.GetSecretValueRequest) (*kmssdk.GetSecretValueResponse, error) {
+			return &kmssdk.GetSecretValueResponse{SecretName: request.SecretName, SecretData: values[request.SecretName]}, nil
+		}
+	}
+}
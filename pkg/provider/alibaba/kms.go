@@ -19,13 +19,14 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	kmssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
-	"github.com/tidwall/gjson"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
 	"github.com/external-secrets/external-secrets/pkg/provider/aws/util"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
@@ -39,8 +40,19 @@ const (
 	errFetchAKIDSecret                         = "could not fetch AccessKeyID secret: %w"
 	errMissingSAK                              = "missing AccessSecretKey"
 	errMissingAKID                             = "missing AccessKeyID"
+	errMissingRegionID                         = "missing regionID"
+	errMissingAuth                             = "missing auth: one of secretRef or rrsa is required"
+	errMultipleAuth                            = "only one of secretRef or rrsa may be specified"
+	errInvalidAKIDSecretRef                    = "invalid AccessKeyID secretRef: %w"
+	errInvalidSAKSecretRef                     = "invalid AccessKeySecret secretRef: %w"
+	errAssumeRoleWithOIDC                      = "cannot assume role with OIDC token: %w"
+	errFindByPath                              = "find by path is not supported by Alibaba KMS"
+	errListSecrets                             = "could not list secrets: %w"
 )
 
+// listSecretsPageSize is the maximum page size of the ListSecrets API.
+const listSecretsPageSize = 100
+
 type Client struct {
 	kube      kclient.Client
 	store     *esv1beta1.AlibabaProvider
@@ -49,6 +61,8 @@ type Client struct {
 	regionID  string
 	keyID     []byte
 	accessKey []byte
+	// securityToken is only set for temporary STS credentials
+	securityToken string
 }
 
 type KeyManagementService struct {
@@ -57,10 +71,25 @@ type KeyManagementService struct {
 
 type SMInterface interface {
 	GetSecretValue(request *kmssdk.GetSecretValueRequest) (response *kmssdk.GetSecretValueResponse, err error)
+	ListSecrets(request *kmssdk.ListSecretsRequest) (response *kmssdk.ListSecretsResponse, err error)
 }
 
 // setAuth creates a new Alibaba session based on a store.
 func (c *Client) setAuth(ctx context.Context) error {
+	c.regionID = c.store.RegionID
+	if c.store.Auth.RRSAAuth != nil {
+		creds, err := assumeRoleWithOIDC(ctx, stsEndpoint, c.store.Auth.RRSAAuth)
+		if err != nil {
+			return fmt.Errorf(errAssumeRoleWithOIDC, err)
+		}
+		c.keyID = []byte(creds.AccessKeyID)
+		c.accessKey = []byte(creds.AccessKeySecret)
+		c.securityToken = creds.SecurityToken
+		return nil
+	}
+	if c.store.Auth.SecretRef == nil {
+		return fmt.Errorf(errMissingAuth)
+	}
 	credentialsSecret := &corev1.Secret{}
 	credentialsSecretName := c.store.Auth.SecretRef.AccessKeyID.Name
 	if credentialsSecretName == "" {
@@ -95,8 +124,6 @@ func (c *Client) setAuth(ctx context.Context) error {
 		objectKey.Namespace = *c.store.Auth.SecretRef.AccessKeySecret.Namespace
 	}
 	c.keyID = credentialsSecret.Data[c.store.Auth.SecretRef.AccessKeyID.Key]
-	fmt.Println(c.keyID)
-	fmt.Println(c.accessKey)
 	if (c.keyID == nil) || (len(c.keyID) == 0) {
 		return fmt.Errorf(errMissingAKID)
 	}
@@ -104,14 +131,74 @@ func (c *Client) setAuth(ctx context.Context) error {
 	if (c.accessKey == nil) || (len(c.accessKey) == 0) {
 		return fmt.Errorf(errMissingSAK)
 	}
-	c.regionID = c.store.RegionID
 	return nil
 }
 
-// Empty GetAllSecrets.
+// GetAllSecrets returns the secrets whose name matches ref.Name and that carry all ref.Tags.
+// ListSecrets can't filter by tag key and value pairs, so the secrets are filtered client-side.
 func (kms *KeyManagementService) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	// TO be implemented
-	return nil, fmt.Errorf("GetAllSecrets not implemented")
+	if utils.IsNil(kms.Client) {
+		return nil, fmt.Errorf(errUninitalizedAlibabaProvider)
+	}
+	if ref.Path != nil {
+		return nil, fmt.Errorf(errFindByPath)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	data := make(map[string][]byte)
+	listed := 0
+	for page := 1; ; page++ {
+		request := kmssdk.CreateListSecretsRequest()
+		request.FetchTags = "true"
+		request.PageNumber = requests.NewInteger(page)
+		request.PageSize = requests.NewInteger(listSecretsPageSize)
+		request.SetScheme("https")
+		out, err := kms.Client.ListSecrets(request)
+		if err != nil {
+			return nil, fmt.Errorf(errListSecrets, util.SanitizeErr(err))
+		}
+		listed += len(out.SecretList.Secret)
+		for _, secret := range out.SecretList.Secret {
+			if matcher != nil && !matcher.MatchName(secret.SecretName) {
+				continue
+			}
+			if !hasTags(secret.Tags.Tag, ref.Tags) {
+				continue
+			}
+			value, err := kms.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: secret.SecretName})
+			if err != nil {
+				return nil, err
+			}
+			data[secret.SecretName] = value
+		}
+		if len(out.SecretList.Secret) == 0 || listed >= out.TotalCount {
+			break
+		}
+	}
+	return utils.ConvertKeys(ref.ConversionStrategy, data)
+}
+
+// hasTags returns true if tags contain all wanted key and value pairs.
+func hasTags(tags []kmssdk.Tag, wanted map[string]string) bool {
+	for k, v := range wanted {
+		found := false
+		for _, tag := range tags {
+			if tag.TagKey == k && tag.TagValue == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // GetSecret returns a single secret from the provider.
@@ -137,11 +224,11 @@ func (kms *KeyManagementService) GetSecret(ctx context.Context, ref esv1beta1.Ex
 	if secretOut.SecretData != "" {
 		payload = secretOut.SecretData
 	}
-	val := gjson.Get(payload, ref.Property)
-	if !val.Exists() {
+	val, ok := utils.GetProperty([]byte(payload), ref.Property)
+	if !ok {
 		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
-	return []byte(val.String()), nil
+	return val, nil
 }
 
 // GetSecretMap returns multiple k/v pairs from the provider.
@@ -178,7 +265,13 @@ func (kms *KeyManagementService) NewClient(ctx context.Context, store esv1beta1.
 	alibabaRegion := iStore.regionID
 	alibabaKeyID := iStore.keyID
 	alibabaSecretKey := iStore.accessKey
-	keyManagementService, err := kmssdk.NewClientWithAccessKey(alibabaRegion, string(alibabaKeyID), string(alibabaSecretKey))
+	var keyManagementService *kmssdk.Client
+	var err error
+	if iStore.securityToken != "" {
+		keyManagementService, err = kmssdk.NewClientWithStsToken(alibabaRegion, string(alibabaKeyID), string(alibabaSecretKey), iStore.securityToken)
+	} else {
+		keyManagementService, err = kmssdk.NewClientWithAccessKey(alibabaRegion, string(alibabaKeyID), string(alibabaSecretKey))
+	}
 	if err != nil {
 		return nil, fmt.Errorf(errAlibabaClient, err)
 	}
@@ -194,7 +287,35 @@ func (kms *KeyManagementService) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts a version id of the secret.
+func (kms *KeyManagementService) ValidateVersion(version string) error {
+	return nil
+}
+
 func (kms *KeyManagementService) ValidateStore(store esv1beta1.GenericStore) error {
+	storeSpec := store.GetSpec()
+	alibabaSpec := storeSpec.Provider.Alibaba
+	if alibabaSpec.RegionID == "" {
+		return fmt.Errorf(errMissingRegionID)
+	}
+	if alibabaSpec.Auth == nil {
+		return fmt.Errorf(errMissingAuth)
+	}
+	if alibabaSpec.Auth.RRSAAuth != nil {
+		if alibabaSpec.Auth.SecretRef != nil {
+			return fmt.Errorf(errMultipleAuth)
+		}
+		return validateRRSAAuth(alibabaSpec.Auth.RRSAAuth)
+	}
+	if alibabaSpec.Auth.SecretRef == nil {
+		return fmt.Errorf(errMissingAuth)
+	}
+	if err := utils.ValidateSecretSelector(store, alibabaSpec.Auth.SecretRef.AccessKeyID); err != nil {
+		return fmt.Errorf(errInvalidAKIDSecretRef, err)
+	}
+	if err := utils.ValidateSecretSelector(store, alibabaSpec.Auth.SecretRef.AccessKeySecret); err != nil {
+		return fmt.Errorf(errInvalidSAKSecretRef, err)
+	}
 	return nil
 }
 
@@ -17,14 +17,20 @@ package alibaba
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
 	kmssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
+	"k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
 	fakesm "github.com/external-secrets/external-secrets/pkg/provider/alibaba/fake"
 )
 
@@ -186,6 +192,170 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestGetAllSecrets(t *testing.T) {
+	tag := func(k, v string) kmssdk.Tag {
+		return kmssdk.Tag{TagKey: k, TagValue: v}
+	}
+	secrets := []kmssdk.Secret{
+		{SecretName: "payments-db", Tags: kmssdk.TagsInListSecrets{Tag: []kmssdk.Tag{tag("team", "payments"), tag("env", "prod")}}},
+		{SecretName: "payments-api", Tags: kmssdk.TagsInListSecrets{Tag: []kmssdk.Tag{tag("team", "payments"), tag("env", "dev")}}},
+		{SecretName: "search-db", Tags: kmssdk.TagsInListSecrets{Tag: []kmssdk.Tag{tag("team", "search"), tag("env", "prod")}}},
+	}
+	values := map[string]string{"payments-db": "db", "payments-api": "api", "search-db": "search"}
+
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		expectError string
+	}{
+		"find by tags": {
+			ref:  esv1beta1.ExternalSecretFind{Tags: map[string]string{"env": "prod"}},
+			want: map[string][]byte{"payments-db": []byte("db"), "search-db": []byte("search")},
+		},
+		"find by tags and name": {
+			ref: esv1beta1.ExternalSecretFind{
+				Tags: map[string]string{"team": "payments"},
+				Name: &esv1beta1.FindName{RegExp: "-db$"},
+			},
+			want: map[string][]byte{"payments-db": []byte("db")},
+		},
+		"tag value mismatch": {
+			ref:  esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "payments", "env": "staging"}},
+			want: map[string][]byte{},
+		},
+		"find by path": {
+			ref:         esv1beta1.ExternalSecretFind{Path: pointer.String("payments")},
+			expectError: errFindByPath,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &fakesm.AlibabaMockClient{}
+			// a page size of one makes sure all pages are listed.
+			client.WithSecrets(1, secrets, values)
+			kms := &KeyManagementService{Client: client}
+			out, err := kms.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Fatalf("unexpected error: %v, expected: %s", err, tc.expectError)
+			}
+			if err == nil && !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secrets: expected %v, got %v", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	secretRef := &esv1beta1.AlibabaAuthSecretRef{
+		AccessKeyID:     v1.SecretKeySelector{Name: "creds", Key: "id"},
+		AccessKeySecret: v1.SecretKeySelector{Name: "creds", Key: "secret"},
+	}
+	rrsa := &esv1beta1.AlibabaRRSAAuth{
+		OIDCProviderARN:   "acs:ram::1234:oidc-provider/ack-rrsa",
+		OIDCTokenFilePath: "/var/run/secrets/tokens/oidc-token",
+		RoleARN:           "acs:ram::1234:role/eso",
+		SessionName:       "eso",
+	}
+	tests := map[string]struct {
+		provider    *esv1beta1.AlibabaProvider
+		expectError string
+	}{
+		"secretRef": {
+			provider: &esv1beta1.AlibabaProvider{RegionID: "cn-hangzhou", Auth: &esv1beta1.AlibabaAuth{SecretRef: secretRef}},
+		},
+		"rrsa": {
+			provider: &esv1beta1.AlibabaProvider{RegionID: "cn-hangzhou", Auth: &esv1beta1.AlibabaAuth{RRSAAuth: rrsa}},
+		},
+		"missing region": {
+			provider:    &esv1beta1.AlibabaProvider{Auth: &esv1beta1.AlibabaAuth{SecretRef: secretRef}},
+			expectError: errMissingRegionID,
+		},
+		"missing auth": {
+			provider:    &esv1beta1.AlibabaProvider{RegionID: "cn-hangzhou", Auth: &esv1beta1.AlibabaAuth{}},
+			expectError: errMissingAuth,
+		},
+		"multiple auth": {
+			provider:    &esv1beta1.AlibabaProvider{RegionID: "cn-hangzhou", Auth: &esv1beta1.AlibabaAuth{SecretRef: secretRef, RRSAAuth: rrsa}},
+			expectError: errMultipleAuth,
+		},
+		"incomplete rrsa": {
+			provider:    &esv1beta1.AlibabaProvider{RegionID: "cn-hangzhou", Auth: &esv1beta1.AlibabaAuth{RRSAAuth: &esv1beta1.AlibabaRRSAAuth{OIDCProviderARN: "arn"}}},
+			expectError: errMissingOIDCTokenFile,
+		},
+		"namespace in secretRef": {
+			provider: &esv1beta1.AlibabaProvider{RegionID: "cn-hangzhou", Auth: &esv1beta1.AlibabaAuth{SecretRef: &esv1beta1.AlibabaAuthSecretRef{
+				AccessKeyID: v1.SecretKeySelector{Name: "creds", Key: "id", Namespace: pointer.StringPtr("default")},
+			}}},
+			expectError: "namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := &esv1beta1.SecretStore{
+				Spec: esv1beta1.SecretStoreSpec{
+					Provider: &esv1beta1.SecretStoreProvider{
+						Alibaba: tc.provider,
+					},
+				},
+			}
+			kms := &KeyManagementService{}
+			err := kms.ValidateStore(store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestAssumeRoleWithOIDC(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("oidc-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rrsa := &esv1beta1.AlibabaRRSAAuth{
+		OIDCProviderARN:   "acs:ram::1234:oidc-provider/ack-rrsa",
+		OIDCTokenFilePath: tokenFile,
+		RoleARN:           "acs:ram::1234:role/eso",
+		SessionName:       "eso",
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.Form.Get("Action") != "AssumeRoleWithOIDC" || r.Form.Get("OIDCToken") != "oidc-token" ||
+			r.Form.Get("RoleArn") != "acs:ram::1234:role/eso" || r.Form.Get("OIDCProviderArn") != rrsa.OIDCProviderARN ||
+			r.Form.Get("RoleSessionName") != rrsa.SessionName {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"Code":"InvalidParameter","Message":"unexpected request"}`)
+			return
+		}
+		fmt.Fprint(w, `{"RequestId":"1","Credentials":{"AccessKeyId":"STS.id","AccessKeySecret":"secret","SecurityToken":"token","Expiration":"2022-01-01T00:00:00Z"}}`)
+	}))
+	defer srv.Close()
+
+	creds, err := assumeRoleWithOIDC(context.Background(), srv.URL, rrsa)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &stsCredentials{AccessKeyID: "STS.id", AccessKeySecret: "secret", SecurityToken: "token", Expiration: "2022-01-01T00:00:00Z"}
+	if !reflect.DeepEqual(creds, want) {
+		t.Errorf("unexpected credentials: expected %#v, got %#v", want, creds)
+	}
+
+	rrsa.RoleARN = "acs:ram::1234:role/other"
+	_, err = assumeRoleWithOIDC(context.Background(), srv.URL, rrsa)
+	if !ErrorContains(err, "sts request failed with status 400: InvalidParameter: unexpected request") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	rrsa.OIDCTokenFilePath = filepath.Join(t.TempDir(), "missing")
+	_, err = assumeRoleWithOIDC(context.Background(), srv.URL, rrsa)
+	if !ErrorContains(err, "unable to read OIDC token file") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -0,0 +1,121 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alibaba
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errMissingOIDCProviderARN = "missing rrsa.oidcProviderArn"
+	errMissingOIDCTokenFile   = "missing rrsa.oidcTokenFilePath"
+	errMissingRoleARN         = "missing rrsa.roleArn"
+	errMissingSessionName     = "missing rrsa.sessionName"
+	errReadOIDCToken          = "unable to read OIDC token file %s: %w"
+	errSTSRequest             = "sts request failed: %w"
+	errSTSResponse            = "sts request failed with status %d: %s: %s"
+	errSTSDecode              = "unable to decode sts response: %w"
+
+	stsAPIVersion      = "2015-04-01"
+	stsDurationSeconds = "3600"
+)
+
+// stsEndpoint is the public Alibaba Cloud STS endpoint.
+// AssumeRoleWithOIDC is an anonymous call, so it does not need to be signed.
+var stsEndpoint = "https://sts.aliyuncs.com"
+
+var stsHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+type stsCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	AccessKeySecret string `json:"AccessKeySecret"`
+	SecurityToken   string `json:"SecurityToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+type assumeRoleWithOIDCResponse struct {
+	RequestID   string         `json:"RequestId"`
+	Code        string         `json:"Code"`
+	Message     string         `json:"Message"`
+	Credentials stsCredentials `json:"Credentials"`
+}
+
+func validateRRSAAuth(rrsa *esv1beta1.AlibabaRRSAAuth) error {
+	if rrsa.OIDCProviderARN == "" {
+		return fmt.Errorf(errMissingOIDCProviderARN)
+	}
+	if rrsa.OIDCTokenFilePath == "" {
+		return fmt.Errorf(errMissingOIDCTokenFile)
+	}
+	if rrsa.RoleARN == "" {
+		return fmt.Errorf(errMissingRoleARN)
+	}
+	if rrsa.SessionName == "" {
+		return fmt.Errorf(errMissingSessionName)
+	}
+	return nil
+}
+
+// assumeRoleWithOIDC exchanges the projected service account token
+// for temporary credentials of the configured RAM role.
+// see: https://www.alibabacloud.com/help/en/resource-access-management/latest/assumerolewithoidc
+func assumeRoleWithOIDC(ctx context.Context, endpoint string, rrsa *esv1beta1.AlibabaRRSAAuth) (*stsCredentials, error) {
+	if err := validateRRSAAuth(rrsa); err != nil {
+		return nil, err
+	}
+	token, err := os.ReadFile(rrsa.OIDCTokenFilePath)
+	if err != nil {
+		return nil, fmt.Errorf(errReadOIDCToken, rrsa.OIDCTokenFilePath, err)
+	}
+	form := url.Values{}
+	form.Set("Action", "AssumeRoleWithOIDC")
+	form.Set("Format", "JSON")
+	form.Set("Version", stsAPIVersion)
+	form.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05Z"))
+	form.Set("OIDCProviderArn", rrsa.OIDCProviderARN)
+	form.Set("RoleArn", rrsa.RoleARN)
+	form.Set("RoleSessionName", rrsa.SessionName)
+	form.Set("DurationSeconds", stsDurationSeconds)
+	form.Set("OIDCToken", strings.TrimSpace(string(token)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf(errSTSRequest, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := stsHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(errSTSRequest, err)
+	}
+	defer resp.Body.Close()
+
+	var out assumeRoleWithOIDCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf(errSTSDecode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(errSTSResponse, resp.StatusCode, out.Code, out.Message)
+	}
+	return &out.Credentials, nil
+}
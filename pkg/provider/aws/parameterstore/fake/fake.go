@@ -15,6 +15,7 @@ package fake
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/google/go-cmp/cmp"
@@ -22,13 +23,18 @@ import (
 
 // Client implements the aws parameterstore interface.
 type Client struct {
-	valFn func(*ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+	valFn    func(*ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+	byPathFn func(*ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error)
 }
 
 func (sm *Client) GetParameter(in *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
 	return sm.valFn(in)
 }
 
+func (sm *Client) GetParametersByPath(in *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+	return sm.byPathFn(in)
+}
+
 func (sm *Client) DescribeParameters(*ssm.DescribeParametersInput) (*ssm.DescribeParametersOutput, error) {
 	return nil, nil
 }
@@ -41,3 +47,20 @@ func (sm *Client) WithValue(in *ssm.GetParameterInput, val *ssm.GetParameterOutp
 		return val, err
 	}
 }
+
+// WithParametersByPath returns the pages of out for consecutive calls with the same path.
+func (sm *Client) WithParametersByPath(path string, out []*ssm.GetParametersByPathOutput, err error) {
+	sm.byPathFn = func(in *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+		if in.Path == nil || *in.Path != path || !*in.Recursive || !*in.WithDecryption {
+			return nil, fmt.Errorf("unexpected test argument")
+		}
+		if err != nil {
+			return nil, err
+		}
+		page := 0
+		if in.NextToken != nil {
+			page, _ = strconv.Atoi(*in.NextToken)
+		}
+		return out[page], nil
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ssm"
-	"github.com/tidwall/gjson"
 	utilpointer "k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
@@ -41,6 +40,7 @@ type ParameterStore struct {
 // see: https://docs.aws.amazon.com/sdk-for-go/api/service/ssm/ssmiface/
 type PMInterface interface {
 	GetParameter(*ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+	GetParametersByPath(*ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error)
 	DescribeParameters(*ssm.DescribeParametersInput) (*ssm.DescribeParametersOutput, error)
 }
 
@@ -64,6 +64,9 @@ func (pm *ParameterStore) GetAllSecrets(ctx context.Context, ref esv1beta1.Exter
 	if ref.Tags != nil {
 		return pm.findByTags(ref)
 	}
+	if ref.Path != nil {
+		return pm.findByPath(ref, nil)
+	}
 	return nil, errors.New(errUnexpectedFindOperator)
 }
 
@@ -72,6 +75,9 @@ func (pm *ParameterStore) findByName(ref esv1beta1.ExternalSecretFind) (map[stri
 	if err != nil {
 		return nil, err
 	}
+	if ref.Path != nil {
+		return pm.findByPath(ref, matcher)
+	}
 	pathFilter := make([]*ssm.ParameterStringFilter, 0)
 	if ref.Path != nil {
 		pathFilter = append(pathFilter, &ssm.ParameterStringFilter{
@@ -151,6 +157,36 @@ func (pm *ParameterStore) findByTags(ref esv1beta1.ExternalSecretFind) (map[stri
 	return utils.ConvertKeys(ref.ConversionStrategy, data)
 }
 
+// findByPath recursively fetches the decrypted parameters below ref.Path.
+// Parameters whose names do not match a non-nil matcher are skipped.
+func (pm *ParameterStore) findByPath(ref esv1beta1.ExternalSecretFind, matcher *find.Matcher) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	var nextToken *string
+	for {
+		it, err := pm.client.GetParametersByPath(&ssm.GetParametersByPathInput{
+			Path:           ref.Path,
+			Recursive:      aws.Bool(true),
+			WithDecryption: aws.Bool(true),
+			NextToken:      nextToken,
+		})
+		if err != nil {
+			return nil, util.SanitizeErr(err)
+		}
+		for _, param := range it.Parameters {
+			if matcher != nil && !matcher.MatchName(*param.Name) {
+				continue
+			}
+			data[*param.Name] = []byte(aws.StringValue(param.Value))
+		}
+		nextToken = it.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return utils.ConvertKeys(ref.ConversionStrategy, data)
+}
+
 func (pm *ParameterStore) fetchAndSet(data map[string][]byte, name string) error {
 	out, err := pm.client.GetParameter(&ssm.GetParameterInput{
 		Name:           utilpointer.StringPtr(name),
@@ -165,9 +201,15 @@ func (pm *ParameterStore) fetchAndSet(data map[string][]byte, name string) error
 }
 
 // GetSecret returns a single secret from the provider.
+// A ref.Version is passed to the API as parameter selector,
+// it can either be a version number or a parameter label.
 func (pm *ParameterStore) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	name := ref.Key
+	if ref.Version != "" {
+		name = fmt.Sprintf("%s:%s", ref.Key, ref.Version)
+	}
 	out, err := pm.client.GetParameter(&ssm.GetParameterInput{
-		Name:           &ref.Key,
+		Name:           &name,
 		WithDecryption: aws.Bool(true),
 	})
 
@@ -184,11 +226,11 @@ func (pm *ParameterStore) GetSecret(ctx context.Context, ref esv1beta1.ExternalS
 		}
 		return nil, fmt.Errorf("invalid secret received. parameter value is nil for key: %s", ref.Key)
 	}
-	val := gjson.Get(*out.Parameter.Value, ref.Property)
-	if !val.Exists() {
+	val, ok := utils.GetProperty([]byte(*out.Parameter.Value), ref.Property)
+	if !ok {
 		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
-	return []byte(val.String()), nil
+	return val, nil
 }
 
 // GetSecretMap returns multiple k/v pairs from the provider.
@@ -217,3 +259,8 @@ func (pm *ParameterStore) Validate() error {
 	_, err := pm.sess.Config.Credentials.Get()
 	return err
 }
+
+// ValidateVersion accepts a version number or a label of the parameter.
+func (pm *ParameterStore) ValidateVersion(version string) error {
+	return nil
+}
@@ -90,6 +90,14 @@ func TestGetSecret(t *testing.T) {
 		pstc.expectedSecret = "RRRRR"
 	}
 
+	// good case: version is passed as parameter selector
+	setVersion := func(pstc *parameterstoreTestCase) {
+		pstc.apiInput.Name = aws.String("/baz:3")
+		pstc.apiOutput.Parameter.Value = aws.String("RRRRR")
+		pstc.remoteRef.Version = "3"
+		pstc.expectedSecret = "RRRRR"
+	}
+
 	// good case: extract property
 	setExtractProperty := func(pstc *parameterstoreTestCase) {
 		pstc.apiOutput.Parameter.Value = aws.String(`{"/shmoo": "bang"}`)
@@ -126,6 +134,7 @@ func TestGetSecret(t *testing.T) {
 
 	successCases := []*parameterstoreTestCase{
 		makeValidParameterStoreTestCaseCustom(setSecretString),
+		makeValidParameterStoreTestCaseCustom(setVersion),
 		makeValidParameterStoreTestCaseCustom(setExtractProperty),
 		makeValidParameterStoreTestCaseCustom(setMissingProperty),
 		makeValidParameterStoreTestCaseCustom(setPropertyFail),
@@ -184,6 +193,64 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestGetAllSecretsByPath(t *testing.T) {
+	pages := []*ssm.GetParametersByPathOutput{
+		{
+			Parameters: []*ssm.Parameter{
+				{Name: aws.String("/app/db/user"), Value: aws.String("admin")},
+				{Name: aws.String("/app/db/password"), Value: aws.String("s3cr3t")},
+			},
+			NextToken: aws.String("1"),
+		},
+		{
+			Parameters: []*ssm.Parameter{
+				{Name: aws.String("/app/api/key"), Value: aws.String("abc")},
+			},
+		},
+	}
+	tests := map[string]struct {
+		ref          esv1beta1.ExternalSecretFind
+		expectedData map[string][]byte
+	}{
+		"path": {
+			ref: esv1beta1.ExternalSecretFind{
+				Path:               aws.String("/app"),
+				ConversionStrategy: esv1beta1.ExternalSecretConversionDefault,
+			},
+			expectedData: map[string][]byte{
+				"_app_db_user":     []byte("admin"),
+				"_app_db_password": []byte("s3cr3t"),
+				"_app_api_key":     []byte("abc"),
+			},
+		},
+		"path and name": {
+			ref: esv1beta1.ExternalSecretFind{
+				Path:               aws.String("/app"),
+				Name:               &esv1beta1.FindName{RegExp: "db/"},
+				ConversionStrategy: esv1beta1.ExternalSecretConversionDefault,
+			},
+			expectedData: map[string][]byte{
+				"_app_db_user":     []byte("admin"),
+				"_app_db_password": []byte("s3cr3t"),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := &fake.Client{}
+			fakeClient.WithParametersByPath("/app", pages, nil)
+			ps := ParameterStore{client: fakeClient}
+			out, err := ps.GetAllSecrets(context.Background(), tc.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(out, tc.expectedData) {
+				t.Errorf("unexpected secret data: expected %#v, got %#v", tc.expectedData, out)
+			}
+		})
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -24,6 +24,8 @@ import (
 // Client implements the aws secretsmanager interface.
 type Client struct {
 	ExecutionCounter int
+	listFn           func(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput, error)
+	describeFn       func(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error)
 	valFn            map[string]func(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)
 }
 
@@ -42,8 +44,35 @@ func (sm *Client) GetSecretValue(in *awssm.GetSecretValueInput) (*awssm.GetSecre
 	return nil, fmt.Errorf("test case not found")
 }
 
-func (sm *Client) ListSecrets(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput, error) {
-	return nil, nil
+func (sm *Client) ListSecrets(in *awssm.ListSecretsInput) (*awssm.ListSecretsOutput, error) {
+	if sm.listFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.listFn(in)
+}
+
+func (sm *Client) DescribeSecret(in *awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+	if sm.describeFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.describeFn(in)
+}
+
+// WithDescribe makes DescribeSecret return the given output for the secret id.
+func (sm *Client) WithDescribe(secretID string, out *awssm.DescribeSecretOutput, err error) {
+	sm.describeFn = func(in *awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+		if in.SecretId == nil || *in.SecretId != secretID {
+			return nil, &awssm.ResourceNotFoundException{}
+		}
+		return out, err
+	}
+}
+
+// WithSecrets makes ListSecrets return the given secrets in a single page.
+func (sm *Client) WithSecrets(secrets []*awssm.SecretListEntry) {
+	sm.listFn = func(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput, error) {
+		return &awssm.ListSecretsOutput{SecretList: secrets}, nil
+	}
 }
 
 func (sm *Client) cacheKeyForInput(in *awssm.GetSecretValueInput) string {
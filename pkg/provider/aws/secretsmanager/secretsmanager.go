@@ -19,10 +19,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	awssm "github.com/aws/aws-sdk-go/service/secretsmanager"
-	"github.com/tidwall/gjson"
 	utilpointer "k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -44,10 +45,14 @@ type SecretsManager struct {
 type SMInterface interface {
 	GetSecretValue(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)
 	ListSecrets(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput, error)
+	DescribeSecret(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error)
 }
 
 const (
+	versionIDPrefix = "uuid/"
+
 	errUnexpectedFindOperator = "unexpected find operator"
+	errMissingVersionID       = "invalid version %s: missing version id"
 )
 
 var log = ctrl.Log.WithName("provider").WithName("aws").WithName("secretsmanager")
@@ -73,10 +78,16 @@ func (sm *SecretsManager) fetch(_ context.Context, ref esv1beta1.ExternalSecretD
 		log.Info("found secret in cache", "key", ref.Key, "version", ver)
 		return secretOut, nil
 	}
-	secretOut, err := sm.client.GetSecretValue(&awssm.GetSecretValueInput{
-		SecretId:     &ref.Key,
-		VersionStage: &ver,
-	})
+	input := &awssm.GetSecretValueInput{
+		SecretId: &ref.Key,
+	}
+	// a version prefixed with uuid/ selects a version id, anything else a version stage.
+	if strings.HasPrefix(ver, versionIDPrefix) {
+		input.VersionId = utilpointer.StringPtr(strings.TrimPrefix(ver, versionIDPrefix))
+	} else {
+		input.VersionStage = &ver
+	}
+	secretOut, err := sm.client.GetSecretValue(input)
 	var nf *awssm.ResourceNotFoundException
 	if errors.As(err, &nf) {
 		return nil, esv1beta1.NoSecretErr
@@ -89,64 +100,20 @@ func (sm *SecretsManager) fetch(_ context.Context, ref esv1beta1.ExternalSecretD
 	return secretOut, nil
 }
 
-// Empty GetAllSecrets.
+// GetAllSecrets syncs all secrets whose names match ref.Name and that carry all ref.Tags.
 func (sm *SecretsManager) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	if ref.Name != nil {
-		return sm.findByName(ctx, ref)
-	}
-	if len(ref.Tags) > 0 {
-		return sm.findByTags(ctx, ref)
-	}
-	return nil, errors.New(errUnexpectedFindOperator)
-}
-
-func (sm *SecretsManager) findByName(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	matcher, err := find.New(*ref.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	filters := make([]*awssm.Filter, 0)
-	if ref.Path != nil {
-		filters = append(filters, &awssm.Filter{
-			Key: utilpointer.StringPtr(awssm.FilterNameStringTypeName),
-			Values: []*string{
-				ref.Path,
-			},
-		})
+	if ref.Name == nil && len(ref.Tags) == 0 {
+		return nil, errors.New(errUnexpectedFindOperator)
 	}
-
-	data := make(map[string][]byte)
-	var nextToken *string
-
-	for {
-		it, err := sm.client.ListSecrets(&awssm.ListSecretsInput{
-			Filters:   filters,
-			NextToken: nextToken,
-		})
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
 		if err != nil {
 			return nil, err
 		}
-		log.V(1).Info("aws sm findByName found", "secrets", len(it.SecretList))
-		for _, secret := range it.SecretList {
-			if !matcher.MatchName(*secret.Name) {
-				continue
-			}
-			log.V(1).Info("aws sm findByName matches", "name", *secret.Name)
-			err = sm.fetchAndSet(ctx, data, *secret.Name)
-			if err != nil {
-				return nil, err
-			}
-		}
-		nextToken = it.NextToken
-		if nextToken == nil {
-			break
-		}
+		matcher = m
 	}
-	return utils.ConvertKeys(ref.ConversionStrategy, data)
-}
 
-func (sm *SecretsManager) findByTags(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 	filters := make([]*awssm.Filter, 0)
 	for k, v := range ref.Tags {
 		filters = append(filters, &awssm.Filter{
@@ -161,7 +128,6 @@ func (sm *SecretsManager) findByTags(ctx context.Context, ref esv1beta1.External
 			},
 		})
 	}
-
 	if ref.Path != nil {
 		filters = append(filters, &awssm.Filter{
 			Key: utilpointer.StringPtr(awssm.FilterNameStringTypeName),
@@ -174,16 +140,23 @@ func (sm *SecretsManager) findByTags(ctx context.Context, ref esv1beta1.External
 	data := make(map[string][]byte)
 	var nextToken *string
 	for {
-		log.V(1).Info("aws sm findByTag", "nextToken", nextToken)
+		log.V(1).Info("aws sm find", "nextToken", nextToken)
 		it, err := sm.client.ListSecrets(&awssm.ListSecretsInput{
 			Filters:   filters,
 			NextToken: nextToken,
 		})
 		if err != nil {
-			return nil, err
+			return nil, util.SanitizeErr(err)
 		}
-		log.V(1).Info("aws sm findByTag found", "secrets", len(it.SecretList))
+		log.V(1).Info("aws sm find found", "secrets", len(it.SecretList))
 		for _, secret := range it.SecretList {
+			if matcher != nil && !matcher.MatchName(*secret.Name) {
+				continue
+			}
+			if !hasTags(secret.Tags, ref.Tags) {
+				continue
+			}
+			log.V(1).Info("aws sm find matches", "name", *secret.Name)
 			err = sm.fetchAndSet(ctx, data, *secret.Name)
 			if err != nil {
 				return nil, err
@@ -197,6 +170,25 @@ func (sm *SecretsManager) findByTags(ctx context.Context, ref esv1beta1.External
 	return utils.ConvertKeys(ref.ConversionStrategy, data)
 }
 
+// hasTags reports whether the tags of a secret contain all key/value pairs of want.
+// The tag-key and tag-value filters of ListSecrets match keys and values independently,
+// so a secret tagged team=prod,env=payments matches the filters of team=payments,env=prod, too.
+func hasTags(tags []*awssm.Tag, want map[string]string) bool {
+	for k, v := range want {
+		found := false
+		for _, tag := range tags {
+			if aws.StringValue(tag.Key) == k && aws.StringValue(tag.Value) == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (sm *SecretsManager) fetchAndSet(ctx context.Context, data map[string][]byte, name string) error {
 	sec, err := sm.fetch(ctx, esv1beta1.ExternalSecretDataRemoteRef{
 		Key: name,
@@ -215,6 +207,9 @@ func (sm *SecretsManager) fetchAndSet(ctx context.Context, data map[string][]byt
 
 // GetSecret returns a single secret from the provider.
 func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return sm.getSecretMetadata(ref)
+	}
 	secretOut, err := sm.fetch(ctx, ref)
 	if errors.Is(err, esv1beta1.NoSecretErr) {
 		return nil, err
@@ -239,11 +234,38 @@ func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1beta1.ExternalS
 		payload = string(secretOut.SecretBinary)
 	}
 
-	val := gjson.Get(payload, ref.Property)
-	if !val.Exists() {
+	val, ok := utils.GetProperty([]byte(payload), ref.Property)
+	if !ok {
 		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
-	return []byte(val.String()), nil
+	return val, nil
+}
+
+// getSecretMetadata returns the tags of the secret as json-encoded value,
+// or the value of the tag ref.Property.
+func (sm *SecretsManager) getSecretMetadata(ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	out, err := sm.client.DescribeSecret(&awssm.DescribeSecretInput{
+		SecretId: &ref.Key,
+	})
+	var nf *awssm.ResourceNotFoundException
+	if errors.As(err, &nf) {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if err != nil {
+		return nil, util.SanitizeErr(err)
+	}
+	tags := make(map[string]string, len(out.Tags))
+	for _, tag := range out.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	if ref.Property == "" {
+		return json.Marshal(tags)
+	}
+	val, ok := tags[ref.Property]
+	if !ok {
+		return nil, fmt.Errorf("tag %s does not exist in secret %s", ref.Property, ref.Key)
+	}
+	return []byte(val), nil
 }
 
 // GetSecretMap returns multiple k/v pairs from the provider.
@@ -279,3 +301,11 @@ func (sm *SecretsManager) Validate() error {
 	_, err := sm.sess.Config.Credentials.Get()
 	return err
 }
+
+// ValidateVersion accepts a version stage or a version id prefixed with uuid/.
+func (sm *SecretsManager) ValidateVersion(version string) error {
+	if version == versionIDPrefix {
+		return fmt.Errorf(errMissingVersionID, version)
+	}
+	return nil
+}
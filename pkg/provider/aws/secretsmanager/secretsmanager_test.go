@@ -134,6 +134,13 @@ func TestSecretsManagerGetSecret(t *testing.T) {
 		smtc.expectedSecret = "yesplease"
 	}
 
+	// good case: non UTF-8 binary data, e.g. a PKCS#12 bundle, is returned as is
+	setRawSecretBinary := func(smtc *secretsManagerTestCase) {
+		smtc.apiOutput.SecretBinary = []byte{0x30, 0x82, 0x0a, 0xff, 0x00, 0xfe}
+		smtc.apiOutput.SecretString = nil
+		smtc.expectedSecret = string([]byte{0x30, 0x82, 0x0a, 0xff, 0x00, 0xfe})
+	}
+
 	// bad case: both .SecretString and .SecretBinary are nil
 	setSecretBinaryAndSecretStringToNil := func(smtc *secretsManagerTestCase) {
 		smtc.apiOutput.SecretBinary = nil
@@ -156,6 +163,23 @@ func TestSecretsManagerGetSecret(t *testing.T) {
 		smtc.expectedSecret = "FOOBA!"
 	}
 
+	// good case: previous version stage
+	setPreviousVersionStage := func(smtc *secretsManagerTestCase) {
+		smtc.apiInput.VersionStage = aws.String("AWSPREVIOUS")
+		smtc.remoteRef.Version = "AWSPREVIOUS"
+		smtc.apiOutput.SecretString = aws.String("previous")
+		smtc.expectedSecret = "previous"
+	}
+
+	// good case: version id
+	setVersionID := func(smtc *secretsManagerTestCase) {
+		smtc.apiInput.VersionStage = nil
+		smtc.apiInput.VersionId = aws.String("a0a2b9e4-7b2c-4f3a-9c63-4f2d8a3e6b11")
+		smtc.remoteRef.Version = "uuid/a0a2b9e4-7b2c-4f3a-9c63-4f2d8a3e6b11"
+		smtc.apiOutput.SecretString = aws.String("pinned")
+		smtc.expectedSecret = "pinned"
+	}
+
 	successCases := []*secretsManagerTestCase{
 		makeValidSecretsManagerTestCase(),
 		makeValidSecretsManagerTestCaseCustom(setSecretString),
@@ -163,9 +187,12 @@ func TestSecretsManagerGetSecret(t *testing.T) {
 		makeValidSecretsManagerTestCaseCustom(setRemoteRefMissingProperty),
 		makeValidSecretsManagerTestCaseCustom(setRemoteRefMissingPropertyInvalidJSON),
 		makeValidSecretsManagerTestCaseCustom(setSecretBinaryNotSecretString),
+		makeValidSecretsManagerTestCaseCustom(setRawSecretBinary),
 		makeValidSecretsManagerTestCaseCustom(setSecretBinaryAndSecretStringToNil),
 		makeValidSecretsManagerTestCaseCustom(setNestedSecretValueJSONParsing),
 		makeValidSecretsManagerTestCaseCustom(setCustomVersion),
+		makeValidSecretsManagerTestCaseCustom(setPreviousVersionStage),
+		makeValidSecretsManagerTestCaseCustom(setVersionID),
 		makeValidSecretsManagerTestCaseCustom(setAPIErr),
 	}
 
@@ -257,6 +284,13 @@ func TestGetSecretMap(t *testing.T) {
 		smtc.expectedCounter = aws.Int(1)
 	}
 
+	// good case: binary json
+	setBinaryJSON := func(smtc *secretsManagerTestCase) {
+		smtc.apiOutput.SecretString = nil
+		smtc.apiOutput.SecretBinary = []byte(`{"foo":"bar"}`)
+		smtc.expectedData["foo"] = []byte("bar")
+	}
+
 	// bad case: invalid json
 	setInvalidJSON := func(smtc *secretsManagerTestCase) {
 		smtc.apiOutput.SecretString = aws.String(`-----------------`)
@@ -266,6 +300,7 @@ func TestGetSecretMap(t *testing.T) {
 	successCases := []*secretsManagerTestCase{
 		makeValidSecretsManagerTestCaseCustom(setDeserialization),
 		makeValidSecretsManagerTestCaseCustom(setNestedJSON),
+		makeValidSecretsManagerTestCaseCustom(setBinaryJSON),
 		makeValidSecretsManagerTestCaseCustom(setAPIErr),
 		makeValidSecretsManagerTestCaseCustom(setInvalidJSON),
 		makeValidSecretsManagerTestCaseCustom(cachedMap),
@@ -289,6 +324,124 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestGetAllSecrets(t *testing.T) {
+	tags := func(kv ...string) []*awssm.Tag {
+		out := make([]*awssm.Tag, 0)
+		for i := 0; i < len(kv); i += 2 {
+			out = append(out, &awssm.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+		}
+		return out
+	}
+	secrets := []*awssm.SecretListEntry{
+		{Name: aws.String("payments-db"), Tags: tags("team", "payments", "env", "prod")},
+		{Name: aws.String("payments-api"), Tags: tags("team", "payments", "env", "dev")},
+		{Name: aws.String("mixed-up"), Tags: tags("team", "prod", "env", "payments")},
+	}
+	tests := map[string]struct {
+		ref          esv1beta1.ExternalSecretFind
+		expectError  string
+		expectedData map[string][]byte
+	}{
+		"tags": {
+			ref: esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "payments"}},
+			expectedData: map[string][]byte{
+				"payments-db":  []byte("db"),
+				"payments-api": []byte("api"),
+			},
+		},
+		"tag pairs must match": {
+			ref: esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "payments", "env": "prod"}},
+			expectedData: map[string][]byte{
+				"payments-db": []byte("db"),
+			},
+		},
+		"name and tags": {
+			ref: esv1beta1.ExternalSecretFind{
+				Name: &esv1beta1.FindName{RegExp: "api$"},
+				Tags: map[string]string{"team": "payments"},
+			},
+			expectedData: map[string][]byte{
+				"payments-api": []byte("api"),
+			},
+		},
+		"no operator": {
+			ref:         esv1beta1.ExternalSecretFind{},
+			expectError: errUnexpectedFindOperator,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := fakesm.NewClient()
+			fakeClient.WithSecrets(secrets)
+			for key, val := range map[string]string{"payments-db": "db", "payments-api": "api", "mixed-up": "mixed"} {
+				fakeClient.WithValue(&awssm.GetSecretValueInput{
+					SecretId:     aws.String(key),
+					VersionStage: aws.String("AWSCURRENT"),
+				}, &awssm.GetSecretValueOutput{SecretString: aws.String(val)}, nil)
+			}
+			sm := SecretsManager{
+				cache:  make(map[string]*awssm.GetSecretValueOutput),
+				client: fakeClient,
+			}
+			out, err := sm.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Fatalf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && !cmp.Equal(out, tc.expectedData) {
+				t.Errorf("unexpected secret data: expected %#v, got %#v", tc.expectedData, out)
+			}
+		})
+	}
+}
+
+func TestGetSecretMetadata(t *testing.T) {
+	fakeClient := fakesm.NewClient()
+	fakeClient.WithDescribe("payments-db", &awssm.DescribeSecretOutput{
+		Name: aws.String("payments-db"),
+		Tags: []*awssm.Tag{
+			{Key: aws.String("team"), Value: aws.String("payments")},
+			{Key: aws.String("rotated"), Value: aws.String("2022-06-01")},
+		},
+	}, nil)
+	sm := SecretsManager{
+		cache:  make(map[string]*awssm.GetSecretValueOutput),
+		client: fakeClient,
+	}
+	tests := map[string]struct {
+		ref            esv1beta1.ExternalSecretDataRemoteRef
+		expectError    string
+		expectedSecret string
+	}{
+		"all tags": {
+			ref:            esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectedSecret: `{"rotated":"2022-06-01","team":"payments"}`,
+		},
+		"single tag": {
+			ref:            esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", Property: "team", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectedSecret: "payments",
+		},
+		"missing tag": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", Property: "owner", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectError: "tag owner does not exist in secret payments-db",
+		},
+		"missing secret": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-api", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := sm.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Fatalf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.expectedSecret {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.expectedSecret, string(out))
+			}
+		})
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
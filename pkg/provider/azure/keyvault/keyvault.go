@@ -16,9 +16,13 @@ package keyvault
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"regexp"
@@ -29,7 +33,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/adal"
 	kvauth "github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
-	"github.com/tidwall/gjson"
+	"github.com/lestrrat-go/jwx/jwk"
 	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,6 +42,7 @@ import (
 	kcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/client/config"
+	"software.sslmate.com/src/go-pkcs12"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
@@ -52,14 +57,29 @@ const (
 	azureDefaultAudience = "api://AzureADTokenExchange"
 	annotationClientID   = "azure.workload.identity/client-id"
 	annotationTenantID   = "azure.workload.identity/tenant-id"
+	contentTypePEM       = "application/x-pem-file"
+	contentTypePKCS12    = "application/x-pkcs12"
+	certPropCertificate  = "certificate"
+	certPropPrivateKey   = "privateKey"
+	certPropTLS          = "tls"
+	keyPropJWK           = "jwk"
+	keyPropPEM           = "pem"
+	pemTypeCertificate   = "CERTIFICATE"
+	pemTypePrivateKey    = "PRIVATE KEY"
+	pemTypePublicKey     = "PUBLIC KEY"
 
 	errUnexpectedStoreSpec   = "unexpected store spec"
 	errMissingAuthType       = "cannot initialize Azure Client: no valid authType was specified"
 	errPropNotExist          = "property %s does not exist in key %s"
 	errUnknownObjectType     = "unknown Azure Keyvault object Type for %s"
 	errUnmarshalJSONData     = "error unmarshalling json data: %w"
-	errDataFromCert          = "cannot get use dataFrom to get certificate secret"
-	errDataFromKey           = "cannot get use dataFrom to get key secret"
+	errNoCertData            = "certificate %s has no secret data"
+	errNoKeyData             = "key %s has no key material"
+	errNoCertificate         = "no certificate found in PEM data"
+	errUnsupportedCertType   = "unsupported content type %q of certificate %s"
+	errDecodeCertData        = "unable to decode certificate %s: %w"
+	errNoCertPrivateKey      = "certificate %s has no exportable private key"
+	errDecodeKeyData         = "unable to convert key %s to PEM: %w"
 	errMissingTenant         = "missing tenantID in store config"
 	errMissingSecretRef      = "missing secretRef in provider config"
 	errMissingClientIDSecret = "missing accessKeyID/secretAccessKey in store config"
@@ -232,6 +252,7 @@ func (a *Azure) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretF
 // Implements store.Client.GetSecret Interface.
 // Retrieves a secret/Key/Certificate with the secret name defined in ref.Name
 // The Object Type is defined as a prefix in the ref.Name , if no prefix is defined , we assume a secret is required.
+// For keys and certificates ref.Property selects one of the values returned by GetSecretMap.
 func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	version := ""
 	objectType, secretName := getObjType(ref)
@@ -246,31 +267,30 @@ func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataR
 		// https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#SecretBundle
 		secretResp, err := a.baseClient.GetSecret(context.Background(), *a.provider.VaultURL, secretName, version)
 		if err != nil {
-			return nil, err
-		}
-		if ref.Property == "" {
-			return []byte(*secretResp.Value), nil
-		}
-		res := gjson.Get(*secretResp.Value, ref.Property)
-		if !res.Exists() {
-			return nil, fmt.Errorf(errPropNotExist, ref.Property, ref.Key)
+			return nil, handleAzureError(err)
 		}
-		return []byte(res.String()), err
+		return secretProperty(ref, secretName, secretResp)
 	case objectTypeCert:
+		if ref.Property != "" {
+			return a.getObjectProperty(ctx, ref, a.getCertificateData)
+		}
 		// returns a CertBundle. We return CER contents of x509 certificate
 		// see: https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#CertificateBundle
 		secretResp, err := a.baseClient.GetCertificate(context.Background(), *a.provider.VaultURL, secretName, version)
 		if err != nil {
-			return nil, err
+			return nil, handleAzureError(err)
 		}
 		return *secretResp.Cer, nil
 	case objectTypeKey:
+		if ref.Property != "" {
+			return a.getObjectProperty(ctx, ref, a.getKeyData)
+		}
 		// returns a KeyBundle that contains a jwk
 		// azure kv returns only public keys
 		// see: https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#KeyBundle
 		keyResp, err := a.baseClient.GetKey(context.Background(), *a.provider.VaultURL, secretName, version)
 		if err != nil {
-			return nil, err
+			return nil, handleAzureError(err)
 		}
 		return json.Marshal(keyResp.Key)
 	}
@@ -278,6 +298,31 @@ func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataR
 	return nil, fmt.Errorf(errUnknownObjectType, secretName)
 }
 
+// secretProperty returns the value of a secret object, or the property ref.Property of it.
+// The secrets that back certificates hold PEM or PKCS#12 data instead of JSON, their properties
+// are the ones of certificate objects.
+func secretProperty(ref esv1beta1.ExternalSecretDataRemoteRef, secretName string, secretResp keyvault.SecretBundle) ([]byte, error) {
+	if ref.Property == "" {
+		return []byte(*secretResp.Value), nil
+	}
+	if isCertificateContentType(secretResp.ContentType) {
+		data, err := decodeCertificateSecret(secretName, secretResp)
+		if err != nil {
+			return nil, err
+		}
+		val, ok := data[ref.Property]
+		if !ok {
+			return nil, fmt.Errorf(errPropNotExist, ref.Property, ref.Key)
+		}
+		return val, nil
+	}
+	res, ok := utils.GetProperty([]byte(*secretResp.Value), ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropNotExist, ref.Property, ref.Key)
+	}
+	return res, nil
+}
+
 // Implements store.Client.GetSecretMap Interface.
 // New version of GetSecretMap.
 func (a *Azure) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
@@ -285,7 +330,14 @@ func (a *Azure) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDa
 
 	switch objectType {
 	case defaultObjType:
-		data, err := a.GetSecret(ctx, ref)
+		secretResp, err := a.baseClient.GetSecret(ctx, *a.provider.VaultURL, secretName, ref.Version)
+		if err != nil {
+			return nil, handleAzureError(err)
+		}
+		if ref.Property == "" && isCertificateContentType(secretResp.ContentType) {
+			return decodeCertificateSecret(secretName, secretResp)
+		}
+		data, err := secretProperty(ref, secretName, secretResp)
 		if err != nil {
 			return nil, err
 		}
@@ -303,14 +355,186 @@ func (a *Azure) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDa
 
 		return secretData, nil
 	case objectTypeCert:
-		return nil, fmt.Errorf(errDataFromCert)
+		data, err := a.getCertificateData(ctx, secretName, ref.Version)
+		if err != nil || ref.Property != certPropTLS {
+			return data, err
+		}
+		// the keys of a kubernetes.io/tls secret.
+		if len(data[certPropPrivateKey]) == 0 {
+			return nil, fmt.Errorf(errNoCertPrivateKey, secretName)
+		}
+		return map[string][]byte{
+			corev1.TLSCertKey:       data[certPropCertificate],
+			corev1.TLSPrivateKeyKey: data[certPropPrivateKey],
+		}, nil
 	case objectTypeKey:
-		return nil, fmt.Errorf(errDataFromKey)
+		return a.getKeyData(ctx, secretName, ref.Version)
 	}
 
 	return nil, fmt.Errorf(errUnknownObjectType, secretName)
 }
 
+func (a *Azure) getObjectProperty(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef, getData func(ctx context.Context, name, version string) (map[string][]byte, error)) ([]byte, error) {
+	_, name := getObjType(ref)
+	data, err := getData(ctx, name, ref.Version)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := data[ref.Property]
+	if !ok {
+		return nil, fmt.Errorf(errPropNotExist, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// getCertificateData reads the secret that backs a certificate object.
+// It contains the full certificate chain and, if the certificate policy
+// marks the key as exportable, the private key.
+// see: https://docs.microsoft.com/en-us/azure/key-vault/certificates/about-certificates#composition-of-a-certificate
+func (a *Azure) getCertificateData(ctx context.Context, name, version string) (map[string][]byte, error) {
+	secretResp, err := a.baseClient.GetSecret(ctx, *a.provider.VaultURL, name, version)
+	if err != nil {
+		return nil, handleAzureError(err)
+	}
+	return decodeCertificateSecret(name, secretResp)
+}
+
+func isCertificateContentType(contentType *string) bool {
+	return contentType != nil && (*contentType == contentTypePEM || *contentType == contentTypePKCS12)
+}
+
+// decodeCertificateSecret splits the PEM or PKCS#12 data of a certificate secret
+// into the certificate chain and the private key.
+func decodeCertificateSecret(name string, secretResp keyvault.SecretBundle) (map[string][]byte, error) {
+	if secretResp.Value == nil {
+		return nil, fmt.Errorf(errNoCertData, name)
+	}
+	contentType := ""
+	if secretResp.ContentType != nil {
+		contentType = *secretResp.ContentType
+	}
+	var certs, key []byte
+	var err error
+	switch contentType {
+	case contentTypePEM:
+		certs, key, err = splitPEM([]byte(*secretResp.Value))
+	case contentTypePKCS12:
+		certs, key, err = decodePKCS12(*secretResp.Value)
+	default:
+		return nil, fmt.Errorf(errUnsupportedCertType, contentType, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeCertData, name, err)
+	}
+	data := map[string][]byte{
+		certPropCertificate: certs,
+	}
+	if len(key) > 0 {
+		data[certPropPrivateKey] = key
+	}
+	return data, nil
+}
+
+// getKeyData returns the public part of a key object
+// as JSON Web Key and as PEM encoded PKIX public key.
+func (a *Azure) getKeyData(ctx context.Context, name, version string) (map[string][]byte, error) {
+	keyResp, err := a.baseClient.GetKey(ctx, *a.provider.VaultURL, name, version)
+	if err != nil {
+		return nil, handleAzureError(err)
+	}
+	if keyResp.Key == nil {
+		return nil, fmt.Errorf(errNoKeyData, name)
+	}
+	jwkData, err := json.Marshal(keyResp.Key)
+	if err != nil {
+		return nil, err
+	}
+	pemData, err := jwkToPEM(*keyResp.Key)
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeKeyData, name, err)
+	}
+	return map[string][]byte{
+		keyPropJWK: jwkData,
+		keyPropPEM: pemData,
+	}, nil
+}
+
+// handleAzureError reports objects that do not exist as missing,
+// so that the deletionPolicy of the ExternalSecret is applied.
+func handleAzureError(err error) error {
+	var derr autorest.DetailedError
+	if errors.As(err, &derr) && derr.StatusCode == http.StatusNotFound {
+		return esv1beta1.NoSecretErr
+	}
+	return err
+}
+
+// splitPEM separates the certificate blocks from the private key block.
+func splitPEM(data []byte) ([]byte, []byte, error) {
+	var certs, key []byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		encoded := pem.EncodeToMemory(block)
+		if block.Type == pemTypeCertificate {
+			certs = append(certs, encoded...)
+			continue
+		}
+		key = append(key, encoded...)
+	}
+	if len(certs) == 0 {
+		return nil, nil, errors.New(errNoCertificate)
+	}
+	return certs, key, nil
+}
+
+// decodePKCS12 decodes a base64 encoded PKCS#12 archive without password
+// into a PEM encoded certificate chain (leaf first) and a PKCS#8 private key.
+func decodePKCS12(value string) ([]byte, []byte, error) {
+	pfx, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, cert, caCerts, err := pkcs12.DecodeChain(pfx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	certs := pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: cert.Raw})
+	for _, ca := range caCerts {
+		certs = append(certs, pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: ca.Raw})...)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs, pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der}), nil
+}
+
+func jwkToPEM(key keyvault.JSONWebKey) ([]byte, error) {
+	// keys backed by a HSM use a kty suffix that is not part of RFC 7518
+	key.Kty = keyvault.JSONWebKeyType(strings.TrimSuffix(string(key.Kty), "-HSM"))
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	k, err := jwk.ParseKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	var pub interface{}
+	if err := k.Raw(&pub); err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: der}), nil
+}
+
 func (a *Azure) authorizerForWorkloadIdentity(ctx context.Context, tokenProvider tokenProviderFunc) (autorest.Authorizer, error) {
 	// if no serviceAccountRef was provided
 	// we expect certain env vars to be present.
@@ -480,6 +704,11 @@ func (a *Azure) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts a version id of the object.
+func (a *Azure) ValidateVersion(version string) error {
+	return nil
+}
+
 func getObjType(ref esv1beta1.ExternalSecretDataRemoteRef) (string, string) {
 	objectType := defaultObjType
 
@@ -16,14 +16,27 @@ package keyvault
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/keyvault/2016-10-01/keyvault"
+	"github.com/Azure/go-autorest/autorest"
 	"k8s.io/utils/pointer"
+	"software.sslmate.com/src/go-pkcs12"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
@@ -98,6 +111,53 @@ const (
 	fakeURL              = "noop"
 )
 
+const pemPubRSA = `-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAp2VQo8qCfWAZmdWBVaYu
+Yb+a+tWWm78K6Sr9poCvNcmv8rUPSLACxitQWR8gZaSH1DklVkqz+Ed8Cdlf8lkD
+g4Ex5tkB64jRdC1Uvn4CDpOH6cp+N2s8hTFLqy9/YaDmyQS7HiqthOi9oVjil1VM
+eWfaAbClGtFt6UnKD0Vb/DvLoWYQSqlhgBArFJi966b4E1pOq5Ad02K8pHBDThlI
+Ix7unibLehhDU6q3DCwNH/OOLx6bgNtmvGYJDd1cywpkLQ3YzNCUPWnfMBJRP3iQ
+P/WI21uP6cvo0DqBPBM4wvVzHbCT0vnIflwkbgEWkq1FprqAitZlop9KjLqzjp9v
+yQIDAQAB
+-----END PUBLIC KEY-----
+`
+
+// certificate material in the formats azure keyvault stores
+// certificates in: pem and base64 encoded pkcs12.
+var certPEM, keyPEM, certPFX = newTestCertificate()
+
+func newTestCertificate() (string, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+	pfx, err := pkcs12.Encode(rand.Reader, key, cert, nil, "")
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+		base64.StdEncoding.EncodeToString(pfx)
+}
+
 func newKVJWK(b []byte) *keyvault.JSONWebKey {
 	var key keyvault.JSONWebKey
 	err := json.Unmarshal(b, &key)
@@ -181,6 +241,88 @@ func TestAzureKeyVaultSecretManagerGetSecret(t *testing.T) {
 		smtc.ref.Key = smtc.secretName
 	}
 
+	setPubKeyPEM := func(smtc *secretManagerTestCase) {
+		smtc.secretName = keyName
+		smtc.expectedSecret = pemPubRSA
+		smtc.keyOutput = keyvault.KeyBundle{
+			Key: newKVJWK([]byte(jwkPubRSA)),
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.ref.Property = "pem"
+	}
+
+	setCertificateChain := func(smtc *secretManagerTestCase) {
+		value := certPEM + keyPEM
+		contentType := "application/x-pem-file"
+		smtc.secretName = certName
+		smtc.expectedSecret = certPEM
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &value,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.ref.Property = "certificate"
+	}
+
+	setCertificatePrivateKey := func(smtc *secretManagerTestCase) {
+		contentType := "application/x-pkcs12"
+		smtc.secretName = certName
+		smtc.expectedSecret = keyPEM
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &certPFX,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.ref.Property = "privateKey"
+	}
+
+	badCertificateProperty := func(smtc *secretManagerTestCase) {
+		value := certPEM
+		contentType := "application/x-pem-file"
+		smtc.secretName = certName
+		smtc.expectedSecret = ""
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &value,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.ref.Property = "privateKey"
+		smtc.expectError = fmt.Sprintf("property %s does not exist in key %s", smtc.ref.Property, smtc.ref.Key)
+	}
+
+	// good case: the secret that backs a certificate
+	setCertificateSecretProperty := func(smtc *secretManagerTestCase) {
+		value := certPEM + keyPEM
+		contentType := "application/x-pem-file"
+		smtc.expectedSecret = keyPEM
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &value,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = "secret/certname"
+		smtc.ref.Property = "privateKey"
+	}
+
+	badSecretNotFound := func(smtc *secretManagerTestCase) {
+		smtc.apiErr = autorest.DetailedError{StatusCode: http.StatusNotFound}
+		smtc.expectedSecret = ""
+		smtc.expectError = esv1beta1.NoSecretErr.Error()
+	}
+
+	badKeyNotFound := func(smtc *secretManagerTestCase) {
+		smtc.ref.Key = keyName
+		smtc.apiErr = autorest.DetailedError{StatusCode: http.StatusNotFound}
+		smtc.expectedSecret = ""
+		smtc.expectError = esv1beta1.NoSecretErr.Error()
+	}
+
+	badCertificateNotFound := func(smtc *secretManagerTestCase) {
+		smtc.ref.Key = certName
+		smtc.apiErr = autorest.DetailedError{StatusCode: http.StatusNotFound}
+		smtc.expectedSecret = ""
+		smtc.expectError = esv1beta1.NoSecretErr.Error()
+	}
+
 	badSecretType := func(smtc *secretManagerTestCase) {
 		smtc.secretName = "name"
 		smtc.expectedSecret = ""
@@ -197,6 +339,14 @@ func TestAzureKeyVaultSecretManagerGetSecret(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(setPubRSAKey),
 		makeValidSecretManagerTestCaseCustom(setPubECKey),
 		makeValidSecretManagerTestCaseCustom(setCertificate),
+		makeValidSecretManagerTestCaseCustom(setPubKeyPEM),
+		makeValidSecretManagerTestCaseCustom(setCertificateChain),
+		makeValidSecretManagerTestCaseCustom(setCertificatePrivateKey),
+		makeValidSecretManagerTestCaseCustom(badCertificateProperty),
+		makeValidSecretManagerTestCaseCustom(setCertificateSecretProperty),
+		makeValidSecretManagerTestCaseCustom(badSecretNotFound),
+		makeValidSecretManagerTestCaseCustom(badKeyNotFound),
+		makeValidSecretManagerTestCaseCustom(badCertificateNotFound),
 		makeValidSecretManagerTestCaseCustom(badSecretType),
 	}
 
@@ -258,25 +408,100 @@ func TestAzureKeyVaultSecretManagerGetSecretMap(t *testing.T) {
 		smtc.apiErr = errors.New(smtc.expectError)
 	}
 
-	badPubRSAKey := func(smtc *secretManagerTestCase) {
+	setPubRSAKey := func(smtc *secretManagerTestCase) {
 		smtc.secretName = keyName
-		smtc.expectedSecret = jwkPubRSA
 		smtc.keyOutput = keyvault.KeyBundle{
 			Key: newKVJWK([]byte(jwkPubRSA)),
 		}
 		smtc.ref.Key = smtc.secretName
-		smtc.expectError = "cannot get use dataFrom to get key secret"
+		smtc.expectedData["jwk"] = []byte(jwkPubRSA)
+		smtc.expectedData["pem"] = []byte(pemPubRSA)
 	}
 
-	badCertificate := func(smtc *secretManagerTestCase) {
-		byteArrString := []byte(secretCertificate)
+	setPubRSAHSMKey := func(smtc *secretManagerTestCase) {
+		smtc.secretName = keyName
+		key := newKVJWK([]byte(jwkPubRSA))
+		key.Kty = keyvault.RSAHSM
+		smtc.keyOutput = keyvault.KeyBundle{
+			Key: key,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.expectedData["jwk"] = []byte(strings.Replace(jwkPubRSA, `"kty":"RSA"`, `"kty":"RSA-HSM"`, 1))
+		smtc.expectedData["pem"] = []byte(pemPubRSA)
+	}
+
+	setPEMCertificate := func(smtc *secretManagerTestCase) {
+		value := certPEM + keyPEM
+		contentType := "application/x-pem-file"
 		smtc.secretName = certName
-		smtc.expectedSecret = secretCertificate
-		smtc.certOutput = keyvault.CertificateBundle{
-			Cer: &byteArrString,
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &value,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.expectedData["certificate"] = []byte(certPEM)
+		smtc.expectedData["privateKey"] = []byte(keyPEM)
+	}
+
+	setPKCS12Certificate := func(smtc *secretManagerTestCase) {
+		contentType := "application/x-pkcs12"
+		smtc.secretName = certName
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &certPFX,
+			ContentType: &contentType,
 		}
 		smtc.ref.Key = smtc.secretName
-		smtc.expectError = "cannot get use dataFrom to get certificate secret"
+		smtc.expectedData["certificate"] = []byte(certPEM)
+		smtc.expectedData["privateKey"] = []byte(keyPEM)
+	}
+
+	setPKCS12CertificateTLS := func(smtc *secretManagerTestCase) {
+		contentType := "application/x-pkcs12"
+		smtc.secretName = certName
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &certPFX,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.ref.Property = "tls"
+		smtc.expectedData["tls.crt"] = []byte(certPEM)
+		smtc.expectedData["tls.key"] = []byte(keyPEM)
+	}
+
+	setPKCS12CertificateSecret := func(smtc *secretManagerTestCase) {
+		contentType := "application/x-pkcs12"
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &certPFX,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = "secret/certname"
+		smtc.expectedData["certificate"] = []byte(certPEM)
+		smtc.expectedData["privateKey"] = []byte(keyPEM)
+	}
+
+	badCertificateTLSWithoutKey := func(smtc *secretManagerTestCase) {
+		value := certPEM
+		contentType := "application/x-pem-file"
+		smtc.secretName = certName
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &value,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.ref.Property = "tls"
+		smtc.expectError = "certificate certname has no exportable private key"
+	}
+
+	badCertificateContentType := func(smtc *secretManagerTestCase) {
+		value := secretCertificate
+		contentType := "text/plain"
+		smtc.secretName = certName
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value:       &value,
+			ContentType: &contentType,
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.expectError = `unsupported content type "text/plain" of certificate certname`
 	}
 
 	badSecretType := func(smtc *secretManagerTestCase) {
@@ -291,8 +516,14 @@ func TestAzureKeyVaultSecretManagerGetSecretMap(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(setSecretJSON),
 		makeValidSecretManagerTestCaseCustom(setSecretJSONWithProperty),
 		makeValidSecretManagerTestCaseCustom(badSecretWithProperty),
-		makeValidSecretManagerTestCaseCustom(badPubRSAKey),
-		makeValidSecretManagerTestCaseCustom(badCertificate),
+		makeValidSecretManagerTestCaseCustom(setPubRSAKey),
+		makeValidSecretManagerTestCaseCustom(setPubRSAHSMKey),
+		makeValidSecretManagerTestCaseCustom(setPEMCertificate),
+		makeValidSecretManagerTestCaseCustom(setPKCS12Certificate),
+		makeValidSecretManagerTestCaseCustom(setPKCS12CertificateTLS),
+		makeValidSecretManagerTestCaseCustom(setPKCS12CertificateSecret),
+		makeValidSecretManagerTestCaseCustom(badCertificateTLSWithoutKey),
+		makeValidSecretManagerTestCaseCustom(badCertificateContentType),
 		makeValidSecretManagerTestCaseCustom(badSecretType),
 	}
 
@@ -0,0 +1,403 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package beyondtrust
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultSeparator = "/"
+
+	propertyUsername = "username"
+	propertyPassword = "password"
+
+	errMissingStoreSpec     = "missing store provider beyondtrust"
+	errMissingAPIURL        = "missing apiUrl"
+	errInvalidAPIURL        = "invalid apiUrl %s: must be an absolute https url"
+	errInvalidRetrievalType = "invalid retrievalType %s: must be SECRET or MANAGED_ACCOUNT"
+	errMissingRunAs         = "missing auth.runAs"
+	errMissingSecretRefName = "missing name in auth.secretRef.%s"
+	errMissingSecretRefKey  = "missing key in auth.secretRef.%s"
+	errInvalidSecretRef     = "invalid auth.secretRef.%s: %w"
+	errIncompleteClientCert = "auth.secretRef.clientCertificate and auth.secretRef.clientKey must be set together"
+	errFetchCredentials     = "could not fetch credentials secret %s: %w"
+	errMissingCredentials   = "missing %s in secret %s"
+	errInvalidCABundle      = "failed to append caBundle"
+	errInvalidClientCert    = "invalid client certificate: %w"
+	errUninitalizedClient   = "provider beyondtrust is not initialized"
+	errInvalidKey           = "invalid key %s: must be in the form %s"
+	errAmbiguousTitle       = "found %d secrets titled %s"
+	errUnknownProperty      = "unknown property %s of managed account %s: must be username or password"
+	errPropertyNotFound     = "property %s does not exist in secret %s"
+	errJSONSecretUnmarshal  = "unable to unmarshal secret %s: %w"
+	errFindManagedAccounts  = "find is not supported for managed accounts"
+	errFindNotImplemented   = "find by tags is not supported by beyondtrust"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// BeyondTrust reads Team Passwords or managed account credentials of Password Safe.
+type BeyondTrust struct {
+	client        *apiClient
+	retrievalType esv1beta1.BeyondTrustRetrievalType
+	separator     string
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		BeyondTrust: &esv1beta1.BeyondTrustProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.BeyondTrustProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.BeyondTrust == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.BeyondTrust, nil
+}
+
+// NewClient signs in to Password Safe with the api key and client certificate referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	secretRef := provider.Auth.SecretRef
+	apiKey, err := secretKeyRef(ctx, kube, storeKind, namespace, "apiKey", secretRef.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(provider.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(provider.CABundle) {
+			return nil, fmt.Errorf(errInvalidCABundle)
+		}
+	}
+	if secretRef.ClientCertificate != nil && secretRef.ClientKey != nil {
+		cert, err := secretKeyRef(ctx, kube, storeKind, namespace, "clientCertificate", *secretRef.ClientCertificate)
+		if err != nil {
+			return nil, err
+		}
+		key, err := secretKeyRef(ctx, kube, storeKind, namespace, "clientKey", *secretRef.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf(errInvalidClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	client, err := newAPIClient(provider.APIURL, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.signIn(ctx, strings.TrimSpace(apiKey), provider.Auth.RunAs); err != nil {
+		return nil, err
+	}
+	bt := &BeyondTrust{
+		client:        client,
+		retrievalType: provider.RetrievalType,
+		separator:     provider.Separator,
+	}
+	if bt.retrievalType == "" {
+		bt.retrievalType = esv1beta1.BeyondTrustRetrievalSecret
+	}
+	if bt.separator == "" {
+		bt.separator = defaultSeparator
+	}
+	return bt, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := string(secret.Data[ref.Key])
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.APIURL == "" {
+		return fmt.Errorf(errMissingAPIURL)
+	}
+	if u, err := url.Parse(provider.APIURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf(errInvalidAPIURL, provider.APIURL)
+	}
+	switch provider.RetrievalType {
+	case "", esv1beta1.BeyondTrustRetrievalSecret, esv1beta1.BeyondTrustRetrievalManagedAccount:
+	default:
+		return fmt.Errorf(errInvalidRetrievalType, provider.RetrievalType)
+	}
+	if provider.Auth.RunAs == "" {
+		return fmt.Errorf(errMissingRunAs)
+	}
+	secretRef := provider.Auth.SecretRef
+	if err := validateSecretRef(store, "apiKey", secretRef.APIKey); err != nil {
+		return err
+	}
+	if (secretRef.ClientCertificate == nil) != (secretRef.ClientKey == nil) {
+		return fmt.Errorf(errIncompleteClientCert)
+	}
+	if secretRef.ClientCertificate != nil {
+		if err := validateSecretRef(store, "clientCertificate", *secretRef.ClientCertificate); err != nil {
+			return err
+		}
+		return validateSecretRef(store, "clientKey", *secretRef.ClientKey)
+	}
+	return nil
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingSecretRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingSecretRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidSecretRef, name, err)
+	}
+	return nil
+}
+
+// GetSecret returns the password of the secret folder/title or
+// the managed account system/account referenced by ref.Key.
+// ref.Property username returns the username instead. For secrets any other
+// property is a gjson expression on the value.
+func (b *BeyondTrust) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if b.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if b.retrievalType == esv1beta1.BeyondTrustRetrievalManagedAccount {
+		fields, err := b.managedAccountCredentials(ctx, ref.Key)
+		if err != nil {
+			return nil, err
+		}
+		property := ref.Property
+		if property == "" {
+			property = propertyPassword
+		}
+		val, ok := fields[property]
+		if !ok {
+			return nil, fmt.Errorf(errUnknownProperty, property, ref.Key)
+		}
+		return val, nil
+	}
+	s, err := b.findSecret(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == propertyUsername {
+		return []byte(s.Username), nil
+	}
+	value, err := b.secretValue(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" || ref.Property == propertyPassword {
+		return value, nil
+	}
+	val, ok := utils.GetProperty(value, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the username and password of a managed account or a credential secret.
+// Text and file secrets must hold a JSON object, its key/value pairs are returned.
+func (b *BeyondTrust) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if b.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if b.retrievalType == esv1beta1.BeyondTrustRetrievalManagedAccount {
+		return b.managedAccountCredentials(ctx, ref.Key)
+	}
+	s, err := b.findSecret(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if s.SecretType == "Credential" {
+		return map[string][]byte{
+			propertyUsername: []byte(s.Username),
+			propertyPassword: []byte(s.Password),
+		}, nil
+	}
+	value, err := b.secretValue(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(value, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns the values of the secrets in the folder ref.Path whose titles match ref.Name.
+func (b *BeyondTrust) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if b.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if b.retrievalType == esv1beta1.BeyondTrustRetrievalManagedAccount {
+		return nil, fmt.Errorf(errFindManagedAccounts)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	var path string
+	if ref.Path != nil {
+		path = *ref.Path
+	}
+	secrets, err := b.client.findSecrets(ctx, path, "", b.separator)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte)
+	for i := range secrets {
+		if matcher != nil && !matcher.MatchName(secrets[i].Title) {
+			continue
+		}
+		value, err := b.secretValue(ctx, &secrets[i])
+		if err != nil {
+			return nil, err
+		}
+		out[secrets[i].Title] = value
+	}
+	return out, nil
+}
+
+// findSecret returns the secret referenced by key in the form folder/title.
+func (b *BeyondTrust) findSecret(ctx context.Context, key string) (*secret, error) {
+	path, title := "", key
+	if i := strings.LastIndex(key, b.separator); i >= 0 {
+		path, title = key[:i], key[i+len(b.separator):]
+	}
+	if title == "" {
+		return nil, fmt.Errorf(errInvalidKey, key, "folder"+b.separator+"title")
+	}
+	secrets, err := b.client.findSecrets(ctx, path, title, b.separator)
+	if err != nil {
+		return nil, err
+	}
+	var matches []secret
+	for _, s := range secrets {
+		if s.Title == title {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, esv1beta1.NoSecretErr
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf(errAmbiguousTitle, len(matches), key)
+	}
+}
+
+func (b *BeyondTrust) secretValue(ctx context.Context, s *secret) ([]byte, error) {
+	if s.SecretType == secretTypeFile {
+		return b.client.downloadFile(ctx, s.ID)
+	}
+	return []byte(s.Password), nil
+}
+
+// managedAccountCredentials checks out the password of the managed account
+// referenced by key in the form system/account.
+func (b *BeyondTrust) managedAccountCredentials(ctx context.Context, key string) (map[string][]byte, error) {
+	parts := strings.SplitN(key, b.separator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf(errInvalidKey, key, "system"+b.separator+"account")
+	}
+	account, err := b.client.getManagedAccount(ctx, parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+	password, err := b.client.checkoutPassword(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		propertyUsername: []byte(account.AccountName),
+		propertyPassword: []byte(password),
+	}, nil
+}
+
+// Close signs out of Password Safe.
+func (b *BeyondTrust) Close(ctx context.Context) error {
+	if b.client == nil {
+		return nil
+	}
+	return b.client.signOut(ctx)
+}
+
+func (b *BeyondTrust) Validate() error {
+	return nil
+}
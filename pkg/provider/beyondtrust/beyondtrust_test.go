@@ -0,0 +1,395 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package beyondtrust
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+var testSecrets = []secret{
+	{ID: "1", Title: "db", FolderPath: "ops/prod", SecretType: "Credential", Username: "admin", Password: "hunter2"},
+	{ID: "2", Title: "config", FolderPath: "ops/prod", SecretType: "Text", Password: `{"host":"db.internal","port":5432}`},
+	{ID: "3", Title: "kubeconfig", FolderPath: "ops/prod", SecretType: "File"},
+	{ID: "4", Title: "db", FolderPath: "ops/dev", SecretType: "Credential", Username: "dev", Password: "dev"},
+}
+
+func newClientCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "external-secrets"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func newFakePasswordSafe(t *testing.T) *httptest.Server {
+	checkedOut := 0
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/BeyondTrust/api/public/v3/Auth/SignAppin" {
+			if r.Header.Get("Authorization") != "PS-Auth key=apikey; runas=eso;" || len(r.TLS.PeerCertificates) == 0 {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `"User not authenticated"`)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "ASP.NET_SessionId", Value: "session", Path: "/"})
+			fmt.Fprint(w, `{"UserId":1,"UserName":"eso"}`)
+			return
+		}
+		if c, err := r.Cookie("ASP.NET_SessionId"); err != nil || c.Value != "session" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		q := r.URL.Query()
+		switch p := strings.TrimPrefix(r.URL.Path, "/BeyondTrust/api/public/v3"); {
+		case p == "/Auth/Signout":
+			w.WriteHeader(http.StatusOK)
+		case p == "/secrets-safe/secrets":
+			out := []secret{}
+			for _, s := range testSecrets {
+				if (q.Get("path") == "" || s.FolderPath == q.Get("path")) && (q.Get("title") == "" || s.Title == q.Get("title")) {
+					out = append(out, s)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		case p == "/secrets-safe/secrets/3/file/download":
+			fmt.Fprint(w, "apiVersion: v1\nkind: Config\n")
+		case p == "/ManagedAccounts":
+			if q.Get("systemName") != "db01" || q.Get("accountName") != "root" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"SystemId":7,"SystemName":"db01","AccountId":12,"AccountName":"root"}`)
+		case p == "/Requests" && r.Method == http.MethodPost:
+			var req credentialRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.SystemID != 7 || req.AccountID != 12 || req.AccessType != "View" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			checkedOut++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, "42")
+		case p == "/Credentials/42":
+			fmt.Fprint(w, `"r0tated"`)
+		case p == "/Requests/42/Checkin" && r.Method == http.MethodPut:
+			checkedOut--
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	t.Cleanup(func() {
+		srv.Close()
+		if checkedOut != 0 {
+			t.Errorf("%d requests were not checked in", checkedOut)
+		}
+	})
+	return srv
+}
+
+func makeStore(apiURL string, retrievalType esv1beta1.BeyondTrustRetrievalType, secretRef esv1beta1.BeyondTrustAuthSecretRef) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				BeyondTrust: &esv1beta1.BeyondTrustProvider{
+					APIURL:        apiURL,
+					RetrievalType: retrievalType,
+					Auth: esv1beta1.BeyondTrustAuth{
+						RunAs:     "eso",
+						SecretRef: secretRef,
+					},
+				},
+			},
+		},
+	}
+}
+
+func validSecretRef() esv1beta1.BeyondTrustAuthSecretRef {
+	return esv1beta1.BeyondTrustAuthSecretRef{
+		APIKey:            esmeta.SecretKeySelector{Name: "beyondtrust", Key: "apiKey"},
+		ClientCertificate: &esmeta.SecretKeySelector{Name: "beyondtrust", Key: "tls.crt"},
+		ClientKey:         &esmeta.SecretKeySelector{Name: "beyondtrust", Key: "tls.key"},
+	}
+}
+
+func newTestClient(t *testing.T, retrievalType esv1beta1.BeyondTrustRetrievalType, apiKey string) (esv1beta1.SecretsClient, error) {
+	srv := newFakePasswordSafe(t)
+	certPEM, keyPEM := newClientCertificate(t)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "beyondtrust", Namespace: "default"},
+		Data: map[string][]byte{
+			"apiKey":  []byte(apiKey),
+			"tls.crt": certPEM,
+			"tls.key": keyPEM,
+		},
+	}).Build()
+	store := makeStore(srv.URL+"/BeyondTrust/api/public/v3/", retrievalType, validSecretRef())
+	store.Spec.Provider.BeyondTrust.CABundle = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	return (&Provider{}).NewClient(context.Background(), store, kube, "default")
+}
+
+func TestBeyondTrustGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"credential": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "ops/prod/db"},
+			want: "hunter2",
+		},
+		"credential username": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "ops/prod/db", Property: "username"},
+			want: "admin",
+		},
+		"text property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "ops/prod/config", Property: "host"},
+			want: "db.internal",
+		},
+		"file": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "ops/prod/kubeconfig"},
+			want: "apiVersion: v1\nkind: Config\n",
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "ops/prod/config", Property: "user"},
+			expectError: "property user does not exist in secret ops/prod/config",
+		},
+		"ambiguous title": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db"},
+			expectError: "found 2 secrets titled db",
+		},
+		"missing secret": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "ops/prod/nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c, err := newTestClient(t, esv1beta1.BeyondTrustRetrievalSecret, "apikey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+	if err := c.Close(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBeyondTrustGetSecretMap(t *testing.T) {
+	c, err := newTestClient(t, esv1beta1.BeyondTrustRetrievalSecret, "apikey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := map[string]map[string][]byte{
+		"ops/prod/db":     {"username": []byte("admin"), "password": []byte("hunter2")},
+		"ops/prod/config": {"host": []byte("db.internal"), "port": []byte("5432")},
+	}
+	for key, want := range tests {
+		out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: key})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(out, want) {
+			t.Errorf("unexpected secret data of %s: expected %#v, got %#v", key, want, out)
+		}
+	}
+}
+
+func TestBeyondTrustGetAllSecrets(t *testing.T) {
+	c, err := newTestClient(t, esv1beta1.BeyondTrustRetrievalSecret, "apikey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Path: pointer.StringPtr("ops/prod"),
+		Name: &esv1beta1.FindName{RegExp: "^(db|config)$"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"db":     []byte("hunter2"),
+		"config": []byte(`{"host":"db.internal","port":5432}`),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secrets: expected %#v, got %#v", want, out)
+	}
+	_, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Tags: map[string]string{"env": "prod"}})
+	if !ErrorContains(err, errFindNotImplemented) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBeyondTrustManagedAccount(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"password": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db01/root"},
+			want: "r0tated",
+		},
+		"username": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db01/root", Property: "username"},
+			want: "root",
+		},
+		"unknown property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db01/root", Property: "host"},
+			expectError: "unknown property host of managed account db01/root",
+		},
+		"invalid key": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db01"},
+			expectError: "invalid key db01: must be in the form system/account",
+		},
+		"missing account": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db01/admin"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c, err := newTestClient(t, esv1beta1.BeyondTrustRetrievalManagedAccount, "apikey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+	_, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: ".*"}})
+	if !ErrorContains(err, errFindManagedAccounts) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBeyondTrustSignInFailure(t *testing.T) {
+	_, err := newTestClient(t, esv1beta1.BeyondTrustRetrievalSecret, "wrong")
+	if !ErrorContains(err, "unable to sign in to password safe: password safe api returned status 401: User not authenticated") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	apiURL := "https://example.com/BeyondTrust/api/public/v3"
+	withoutClientKey := validSecretRef()
+	withoutClientKey.ClientKey = nil
+	apiKeyOnly := esv1beta1.BeyondTrustAuthSecretRef{APIKey: validSecretRef().APIKey}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore(apiURL, "", validSecretRef()),
+		},
+		"valid without client certificate": {
+			store: makeStore(apiURL, esv1beta1.BeyondTrustRetrievalManagedAccount, apiKeyOnly),
+		},
+		"missing api url": {
+			store:       makeStore("", "", validSecretRef()),
+			expectError: errMissingAPIURL,
+		},
+		"plain http api url": {
+			store:       makeStore("http://example.com", "", validSecretRef()),
+			expectError: "invalid apiUrl http://example.com",
+		},
+		"invalid retrieval type": {
+			store:       makeStore(apiURL, "ASSET", validSecretRef()),
+			expectError: "invalid retrievalType ASSET",
+		},
+		"incomplete client certificate": {
+			store:       makeStore(apiURL, "", withoutClientKey),
+			expectError: errIncompleteClientCert,
+		},
+		"namespace not allowed": {
+			store: makeStore(apiURL, "", esv1beta1.BeyondTrustAuthSecretRef{
+				APIKey: esmeta.SecretKeySelector{Name: "beyondtrust", Key: "apiKey", Namespace: pointer.StringPtr("foo")},
+			}),
+			expectError: "invalid auth.secretRef.apiKey: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
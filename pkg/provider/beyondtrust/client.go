@@ -0,0 +1,205 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package beyondtrust
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/external-secrets/external-secrets/pkg/provider/internal/jsonapi"
+)
+
+const (
+	// requestDuration is how long a credential request of a managed account stays open.
+	requestDuration = 5
+	requestReason   = "external-secrets"
+
+	secretTypeFile = "File"
+
+	errLogin   = "unable to sign in to password safe: %w"
+	errCheckin = "unable to check in request %d: %w"
+)
+
+// secret is a Team Password of the Secrets Safe.
+type secret struct {
+	ID         string `json:"Id"`
+	Title      string `json:"Title"`
+	FolderPath string `json:"FolderPath"`
+	SecretType string `json:"SecretType"`
+	Username   string `json:"Username"`
+	Password   string `json:"Password"`
+}
+
+type managedAccount struct {
+	SystemID    int    `json:"SystemId"`
+	SystemName  string `json:"SystemName"`
+	AccountID   int    `json:"AccountId"`
+	AccountName string `json:"AccountName"`
+}
+
+type credentialRequest struct {
+	SystemID        int    `json:"SystemID"`
+	AccountID       int    `json:"AccountID"`
+	DurationMinutes int    `json:"DurationMinutes"`
+	Reason          string `json:"Reason"`
+	AccessType      string `json:"AccessType"`
+	ConflictOption  string `json:"ConflictOption"`
+}
+
+type checkinRequest struct {
+	Reason string `json:"Reason"`
+}
+
+// apiClient talks to the Password Safe public API with a signed in session.
+// see: https://docs.beyondtrust.com/bips/docs/password-safe-apis
+type apiClient struct {
+	baseURL string
+	api     *jsonapi.Client
+}
+
+func newAPIClient(apiURL string, tlsConfig *tls.Config) (*apiClient, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(apiURL, "/"),
+		api: &jsonapi.Client{
+			HTTP: &http.Client{
+				Timeout:   30 * time.Second,
+				Jar:       jar,
+				Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			},
+			Name:         "password safe api",
+			ErrorMessage: quotedMessage,
+		},
+	}, nil
+}
+
+// signIn starts a session for the user runAs with the api key of an API registration.
+// The session is kept in a cookie.
+func (c *apiClient) signIn(ctx context.Context, apiKey, runAs string) error {
+	req, err := c.api.NewRequest(ctx, http.MethodPost, c.baseURL+"/Auth/SignAppin", nil)
+	if err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("PS-Auth key=%s; runas=%s;", apiKey, runAs))
+	if err := c.api.Do(req, nil); err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	return nil
+}
+
+func (c *apiClient) signOut(ctx context.Context) error {
+	return c.send(ctx, http.MethodPost, "/Auth/Signout", nil, nil)
+}
+
+// findSecrets returns the secrets of the folder path with the given title.
+// If title is empty all secrets of the folder are returned.
+func (c *apiClient) findSecrets(ctx context.Context, path, title, separator string) ([]secret, error) {
+	params := url.Values{}
+	if path != "" {
+		params.Set("path", path)
+	}
+	if title != "" {
+		params.Set("title", title)
+	}
+	params.Set("separator", separator)
+	var out []secret
+	if err := c.read(ctx, "/secrets-safe/secrets?"+params.Encode(), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// downloadFile returns the content of a file secret.
+func (c *apiClient) downloadFile(ctx context.Context, id string) ([]byte, error) {
+	var out []byte
+	if err := c.read(ctx, "/secrets-safe/secrets/"+url.PathEscape(id)+"/file/download", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) getManagedAccount(ctx context.Context, systemName, accountName string) (*managedAccount, error) {
+	params := url.Values{}
+	params.Set("systemName", systemName)
+	params.Set("accountName", accountName)
+	var out managedAccount
+	if err := c.read(ctx, "/ManagedAccounts?"+params.Encode(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// checkoutPassword requests the current password of a managed account
+// and checks the request in again once the password has been read.
+func (c *apiClient) checkoutPassword(ctx context.Context, account *managedAccount) (string, error) {
+	var requestID int
+	err := c.send(ctx, http.MethodPost, "/Requests", credentialRequest{
+		SystemID:        account.SystemID,
+		AccountID:       account.AccountID,
+		DurationMinutes: requestDuration,
+		Reason:          requestReason,
+		AccessType:      "View",
+		ConflictOption:  "reuse",
+	}, &requestID)
+	if err != nil {
+		return "", err
+	}
+	var password string
+	err = c.send(ctx, http.MethodGet, "/Credentials/"+strconv.Itoa(requestID), nil, &password)
+	checkinErr := c.send(ctx, http.MethodPut, "/Requests/"+strconv.Itoa(requestID)+"/Checkin", checkinRequest{Reason: requestReason}, nil)
+	if err != nil {
+		return "", err
+	}
+	if checkinErr != nil {
+		return "", fmt.Errorf(errCheckin, requestID, checkinErr)
+	}
+	return password, nil
+}
+
+// send sends a request of path with body encoded as JSON and decodes the response into out.
+// A *[]byte receives the raw body.
+func (c *apiClient) send(ctx context.Context, method, path string, body, out interface{}) error {
+	req, err := c.api.NewRequest(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	return c.api.Do(req, out)
+}
+
+// read sends a GET request of a secret or managed account like send,
+// a 404 response is reported as NoSecretErr.
+func (c *apiClient) read(ctx context.Context, path string, out interface{}) error {
+	req, err := c.api.NewRequest(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	return c.api.ReadSecret(req, out)
+}
+
+// quotedMessage returns the message of an error response,
+// which is plain text that may be quoted like a JSON string.
+func quotedMessage(body []byte) string {
+	return strings.Trim(strings.TrimSpace(string(body)), `"`)
+}
@@ -0,0 +1,267 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errMissingStoreSpec      = "missing store provider bitwardensecretsmanager"
+	errMissingOrganizationID = "missing organizationID"
+	errMissingTokenName      = "missing auth.secretRef.accessToken.name"
+	errMissingTokenKey       = "missing auth.secretRef.accessToken.key"
+	errInvalidTokenRef       = "invalid auth.secretRef.accessToken: %w"
+	errFetchTokenSecret      = "could not fetch accessToken secret: %w"
+	errMissingToken          = "missing accessToken in secret %s"
+	errUninitalizedClient    = "provider bitwarden is not initialized"
+	errPropertyNotFound      = "property %s does not exist in secret %s"
+	errJSONSecretUnmarshal   = "unable to unmarshal secret %s: %w"
+	errFindNotImplemented    = "find by tags is not supported by bitwarden"
+	errSecretNotInProject    = "secret %s does not belong to project %s"
+	errAmbiguousSecretName   = "found %d secrets with name %s, use the secret id instead"
+	errOrganizationMismatch  = "organization %s does not match the organization of secret %s"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Bitwarden reads secrets of an organization, optionally restricted to one project.
+type Bitwarden struct {
+	client         SecretsClient
+	organizationID string
+	projectID      string
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		BitwardenSecretsManager: &esv1beta1.BitwardenSecretsManagerProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.BitwardenSecretsManagerProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.BitwardenSecretsManager == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.BitwardenSecretsManager, nil
+}
+
+// NewClient logs in with the machine account access token referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	tokenRef := provider.Auth.SecretRef.AccessToken
+	objectKey := types.NamespacedName{
+		Name:      tokenRef.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind && tokenRef.Namespace != nil {
+		objectKey.Namespace = *tokenRef.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errFetchTokenSecret, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[tokenRef.Key]))
+	if token == "" {
+		return nil, fmt.Errorf(errMissingToken, tokenRef.Name)
+	}
+	client, err := newAPIClient(ctx, provider.APIURL, provider.IdentityURL, token)
+	if err != nil {
+		return nil, err
+	}
+	return &Bitwarden{
+		client:         client,
+		organizationID: provider.OrganizationID,
+		projectID:      provider.ProjectID,
+	}, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.OrganizationID == "" {
+		return fmt.Errorf(errMissingOrganizationID)
+	}
+	tokenRef := provider.Auth.SecretRef.AccessToken
+	if tokenRef.Name == "" {
+		return fmt.Errorf(errMissingTokenName)
+	}
+	if tokenRef.Key == "" {
+		return fmt.Errorf(errMissingTokenKey)
+	}
+	if err := utils.ValidateSecretSelector(store, tokenRef); err != nil {
+		return fmt.Errorf(errInvalidTokenRef, err)
+	}
+	return nil
+}
+
+// GetSecret returns the value of a secret.
+// ref.Key is either the id of the secret or its name. Names are resolved
+// within the project of the store, if set, and must be unique.
+func (b *Bitwarden) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if utils.IsNil(b.client) {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	secret, err := b.resolveSecret(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return []byte(secret.Value), nil
+	}
+	val, ok := utils.GetProperty([]byte(secret.Value), ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the key/value pairs of a secret that holds a JSON object.
+func (b *Bitwarden) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := b.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte)
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns all secrets whose names match ref.Name, keyed by name.
+func (b *Bitwarden) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if utils.IsNil(b.client) {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	ids, err := b.client.ListSecrets(ctx, b.organizationID)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte)
+	for _, id := range ids {
+		if !b.inProject(id.ProjectIDs) {
+			continue
+		}
+		if matcher != nil && !matcher.MatchName(id.Key) {
+			continue
+		}
+		if ref.Path != nil && !strings.HasPrefix(id.Key, *ref.Path) {
+			continue
+		}
+		secret, err := b.client.GetSecret(ctx, id.ID)
+		if err != nil {
+			return nil, err
+		}
+		secretData[secret.Key] = []byte(secret.Value)
+	}
+	return secretData, nil
+}
+
+func (b *Bitwarden) resolveSecret(ctx context.Context, key string) (*Secret, error) {
+	if uuidRegexp.MatchString(key) {
+		secret, err := b.client.GetSecret(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if secret.OrganizationID != "" && secret.OrganizationID != b.organizationID {
+			return nil, fmt.Errorf(errOrganizationMismatch, b.organizationID, key)
+		}
+		if !b.inProject([]string{secret.ProjectID}) {
+			return nil, fmt.Errorf(errSecretNotInProject, key, b.projectID)
+		}
+		return secret, nil
+	}
+	ids, err := b.client.ListSecrets(ctx, b.organizationID)
+	if err != nil {
+		return nil, err
+	}
+	var matches []SecretIdentifier
+	for _, id := range ids {
+		if id.Key == key && b.inProject(id.ProjectIDs) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, esv1beta1.NoSecretErr
+	case 1:
+		return b.client.GetSecret(ctx, matches[0].ID)
+	default:
+		return nil, fmt.Errorf(errAmbiguousSecretName, len(matches), key)
+	}
+}
+
+// inProject reports whether a secret with the given projects is visible to the store.
+func (b *Bitwarden) inProject(projectIDs []string) bool {
+	if b.projectID == "" {
+		return true
+	}
+	for _, id := range projectIDs {
+		if id == b.projectID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bitwarden) Close(ctx context.Context) error {
+	return nil
+}
+
+func (b *Bitwarden) Validate() error {
+	return nil
+}
@@ -0,0 +1,367 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	orgID     = "383e7ec6-7e6b-4ee4-a7f9-5e8d4ea0d1b1"
+	projectA  = "c1f6a3d2-72ff-4d07-a3c7-d1f3e8a5b0a1"
+	projectB  = "f4d8c1e0-0f7a-4a0e-9a4e-8d2b7c6e5f4d"
+	secretDB  = "2f6f1a5d-1c1e-4c8d-9d3e-6b6f7f8a9b0c"
+	secretAPI = "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	secretDup = "9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+	clientID  = "ec2c1d46-6a4b-4751-a310-af9601317f2d"
+)
+
+type fakeSecret struct {
+	key, value, project string
+}
+
+// fakeBitwarden serves the identity and api endpoints with encrypted secrets.
+type fakeBitwarden struct {
+	token   string
+	orgKey  *symmetricKey
+	secrets map[string]fakeSecret
+}
+
+func newFakeBitwarden(t *testing.T) (*fakeBitwarden, *httptest.Server) {
+	seed := randomBytes(t, 16)
+	orgKey, err := newSymmetricKey(randomBytes(t, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb := &fakeBitwarden{
+		token:  fmt.Sprintf("0.%s.clientsecret:%s", clientID, base64.StdEncoding.EncodeToString(seed)),
+		orgKey: orgKey,
+		secrets: map[string]fakeSecret{
+			secretDB:  {key: "db", value: `{"user":"admin","password":"pass"}`, project: projectA},
+			secretAPI: {key: "api-key", value: "s3cr3t", project: projectA},
+			secretDup: {key: "db", value: "other", project: projectB},
+		},
+	}
+	tokenKey, err := deriveShareableKey(seed, "accesstoken", "sm-access-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/identity/connect/token":
+			if err := r.ParseForm(); err != nil || r.Form.Get("client_id") != clientID || r.Form.Get("client_secret") != "clientsecret" || r.Form.Get("scope") != "api.secrets" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			payload, _ := json.Marshal(orgKeyPayload{EncryptionKey: base64.StdEncoding.EncodeToString(append(orgKey.encKey, orgKey.macKey...))})
+			writeJSON(w, tokenResponse{AccessToken: "bearer", EncryptedPayload: encryptString(t, tokenKey, string(payload))})
+		case r.Header.Get("Authorization") != "Bearer bearer":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/api/organizations/"+orgID+"/secrets":
+			var out secretListResponse
+			for id, s := range fb.secrets {
+				out.Secrets = append(out.Secrets, secretListItem{
+					ID:       id,
+					Key:      encryptString(t, orgKey, s.key),
+					Projects: []projectRef{{ID: s.project}},
+				})
+			}
+			writeJSON(w, out)
+		case strings.HasPrefix(r.URL.Path, "/api/secrets/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/secrets/")
+			s, ok := fb.secrets[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writeJSON(w, secretResponse{
+				ID:             id,
+				OrganizationID: orgID,
+				ProjectID:      s.project,
+				Key:            encryptString(t, orgKey, s.key),
+				Value:          encryptString(t, orgKey, s.value),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return fb, srv
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func encryptString(t *testing.T, k *symmetricKey, plain string) string {
+	iv := randomBytes(t, aes.BlockSize)
+	n := aes.BlockSize - len(plain)%aes.BlockSize
+	data := append([]byte(plain), bytes.Repeat([]byte{byte(n)}, n)...)
+	block, err := aes.NewCipher(k.encKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	h := hmac.New(sha256.New, k.macKey)
+	h.Write(iv)
+	h.Write(data)
+	return "2." + base64.StdEncoding.EncodeToString(iv) + "|" + base64.StdEncoding.EncodeToString(data) + "|" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func makeStore(url, projectID string, tokenRef esmeta.SecretKeySelector) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				BitwardenSecretsManager: &esv1beta1.BitwardenSecretsManagerProvider{
+					OrganizationID: orgID,
+					ProjectID:      projectID,
+					APIURL:         url + "/api",
+					IdentityURL:    url + "/identity",
+					Auth: esv1beta1.BitwardenSecretsManagerAuth{
+						SecretRef: esv1beta1.BitwardenSecretsManagerSecretRef{
+							AccessToken: tokenRef,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, projectID string) *Bitwarden {
+	fb, srv := newFakeBitwarden(t)
+	t.Cleanup(srv.Close)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bitwarden", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte(fb.token)},
+	}).Build()
+	store := makeStore(srv.URL, projectID, esmeta.SecretKeySelector{Name: "bitwarden", Key: "token"})
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c.(*Bitwarden)
+}
+
+func TestBitwardenGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		projectID   string
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"by id": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: secretAPI},
+			want: "s3cr3t",
+		},
+		"by name": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "api-key"},
+			want: "s3cr3t",
+		},
+		"by name with property": {
+			projectID: projectA,
+			ref:       esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "user"},
+			want:      "admin",
+		},
+		"by name in other project": {
+			projectID: projectB,
+			ref:       esv1beta1.ExternalSecretDataRemoteRef{Key: "db"},
+			want:      "other",
+		},
+		"ambiguous name": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db"},
+			expectError: "found 2 secrets with name db",
+		},
+		"id outside of project": {
+			projectID:   projectB,
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: secretAPI},
+			expectError: fmt.Sprintf("secret %s does not belong to project %s", secretAPI, projectB),
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: secretDB, Property: "host"},
+			expectError: "property host does not exist",
+		},
+		"name not found": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "missing"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+		"id not found": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "00000000-0000-0000-0000-000000000000"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, tc.projectID)
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestBitwardenGetSecretMap(t *testing.T) {
+	c := newTestClient(t, projectA)
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{"user": []byte("admin"), "password": []byte("pass")}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+	_, err = c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "api-key"})
+	if !ErrorContains(err, "unable to unmarshal secret api-key") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBitwardenGetAllSecrets(t *testing.T) {
+	c := newTestClient(t, projectA)
+	out, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^api"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{"api-key": []byte("s3cr3t")}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+	_, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Tags: map[string]string{"foo": "bar"}})
+	if !ErrorContains(err, errFindNotImplemented) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBitwardenCrypto(t *testing.T) {
+	key, err := newSymmetricKey(randomBytes(t, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := encryptString(t, key, "hello world")
+	out, err := key.decryptString(enc)
+	if err != nil || out != "hello world" {
+		t.Errorf("unexpected result: %q, %v", out, err)
+	}
+	otherKey, _ := newSymmetricKey(randomBytes(t, 64))
+	if _, err := otherKey.decryptString(enc); !ErrorContains(err, errInvalidMAC) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := key.decryptString("0." + strings.TrimPrefix(enc, "2.")); !ErrorContains(err, "unsupported encryption type 0") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, token := range []string{"", "0.id.secret", "1.id.secret:AAAAAAAAAAAAAAAAAAAAAA==", "0.id.secret:short"} {
+		if _, err := parseAccessToken(token); !ErrorContains(err, errInvalidAccessToken) {
+			t.Errorf("token %q: unexpected error: %v", token, err)
+		}
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	validRef := esmeta.SecretKeySelector{Name: "bitwarden", Key: "token"}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("", "", validRef),
+		},
+		"missing organization": {
+			store: func() *esv1beta1.SecretStore {
+				s := makeStore("", "", validRef)
+				s.Spec.Provider.BitwardenSecretsManager.OrganizationID = ""
+				return s
+			}(),
+			expectError: errMissingOrganizationID,
+		},
+		"missing token key": {
+			store:       makeStore("", "", esmeta.SecretKeySelector{Name: "bitwarden"}),
+			expectError: errMissingTokenKey,
+		},
+		"namespace not allowed": {
+			store:       makeStore("", "", esmeta.SecretKeySelector{Name: "bitwarden", Key: "token", Namespace: pointer.StringPtr("foo")}),
+			expectError: "namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestNewClientLoginFailure(t *testing.T) {
+	_, srv := newFakeBitwarden(t)
+	defer srv.Close()
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bitwarden", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("0.other.clientsecret:AAAAAAAAAAAAAAAAAAAAAA==")},
+	}).Build()
+	store := makeStore(srv.URL, "", esmeta.SecretKeySelector{Name: "bitwarden", Key: "token"})
+	_, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if !ErrorContains(err, "unable to login with machine account: bitwarden api returned status 400") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if errors.Is(err, esv1beta1.NoSecretErr) {
+		t.Errorf("login errors must not be reported as missing secret")
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
@@ -0,0 +1,229 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/external-secrets/external-secrets/pkg/provider/internal/jsonapi"
+)
+
+const (
+	defaultAPIURL      = "https://api.bitwarden.com"
+	defaultIdentityURL = "https://identity.bitwarden.com"
+
+	errLogin            = "unable to login with machine account: %w"
+	errDecryptOrgKey    = "unable to decrypt organization key: %w"
+	errDecryptSecret    = "unable to decrypt secret %s: %w"
+	errMissingOrgKeyRes = "login response does not contain an encrypted payload"
+)
+
+// Secret is a decrypted Bitwarden Secrets Manager secret.
+type Secret struct {
+	ID             string
+	OrganizationID string
+	ProjectID      string
+	Key            string
+	Value          string
+	Note           string
+}
+
+// SecretIdentifier references a secret by its decrypted name.
+type SecretIdentifier struct {
+	ID         string
+	Key        string
+	ProjectIDs []string
+}
+
+// SecretsClient is the subset of the Bitwarden Secrets Manager API used by the provider.
+type SecretsClient interface {
+	GetSecret(ctx context.Context, id string) (*Secret, error)
+	ListSecrets(ctx context.Context, organizationID string) ([]SecretIdentifier, error)
+}
+
+// apiClient talks to the Bitwarden Secrets Manager REST API.
+// Secrets are end-to-end encrypted with the organization key,
+// which itself is encrypted with a key contained in the access token.
+type apiClient struct {
+	apiURL string
+	api    *jsonapi.Client
+	bearer string
+	orgKey *symmetricKey
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	EncryptedPayload string `json:"encrypted_payload"`
+}
+
+type orgKeyPayload struct {
+	EncryptionKey string `json:"encryptionKey"`
+}
+
+type secretResponse struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organizationId"`
+	ProjectID      string `json:"projectId"`
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	Note           string `json:"note"`
+}
+
+type secretListResponse struct {
+	Secrets []secretListItem `json:"secrets"`
+}
+
+type secretListItem struct {
+	ID       string       `json:"id"`
+	Key      string       `json:"key"`
+	Projects []projectRef `json:"projects"`
+}
+
+type projectRef struct {
+	ID string `json:"id"`
+}
+
+// newAPIClient logs in with the machine account and decrypts the organization key.
+func newAPIClient(ctx context.Context, apiURL, identityURL, token string) (*apiClient, error) {
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	if identityURL == "" {
+		identityURL = defaultIdentityURL
+	}
+	at, err := parseAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	c := &apiClient{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		api: &jsonapi.Client{
+			HTTP: &http.Client{Timeout: 30 * time.Second},
+			Name: "bitwarden api",
+			// the identity server returns oauth errors, the api a message.
+			ErrorMessage: jsonapi.MessageField("error_description", "error", "message"),
+		},
+	}
+	if err := c.login(ctx, strings.TrimSuffix(identityURL, "/"), at); err != nil {
+		return nil, fmt.Errorf(errLogin, err)
+	}
+	return c, nil
+}
+
+func (c *apiClient) login(ctx context.Context, identityURL string, at *accessToken) error {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", "api.secrets")
+	form.Set("client_id", at.clientID)
+	form.Set("client_secret", at.clientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, identityURL+"/connect/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var out tokenResponse
+	if err := c.api.Do(req, &out); err != nil {
+		return err
+	}
+	if out.EncryptedPayload == "" {
+		return fmt.Errorf(errMissingOrgKeyRes)
+	}
+	payload, err := at.key.decryptString(out.EncryptedPayload)
+	if err != nil {
+		return fmt.Errorf(errDecryptOrgKey, err)
+	}
+	var orgKey orgKeyPayload
+	if err := json.Unmarshal([]byte(payload), &orgKey); err != nil {
+		return fmt.Errorf(errDecryptOrgKey, err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(orgKey.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf(errDecryptOrgKey, err)
+	}
+	c.orgKey, err = newSymmetricKey(raw)
+	if err != nil {
+		return fmt.Errorf(errDecryptOrgKey, err)
+	}
+	c.bearer = out.AccessToken
+	return nil
+}
+
+func (c *apiClient) GetSecret(ctx context.Context, id string) (*Secret, error) {
+	var out secretResponse
+	if err := c.get(ctx, "/secrets/"+url.PathEscape(id), &out); err != nil {
+		return nil, err
+	}
+	key, err := c.orgKey.decryptString(out.Key)
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptSecret, id, err)
+	}
+	value, err := c.orgKey.decryptString(out.Value)
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptSecret, id, err)
+	}
+	var note string
+	if out.Note != "" {
+		note, err = c.orgKey.decryptString(out.Note)
+		if err != nil {
+			return nil, fmt.Errorf(errDecryptSecret, id, err)
+		}
+	}
+	return &Secret{
+		ID:             out.ID,
+		OrganizationID: out.OrganizationID,
+		ProjectID:      out.ProjectID,
+		Key:            key,
+		Value:          value,
+		Note:           note,
+	}, nil
+}
+
+func (c *apiClient) ListSecrets(ctx context.Context, organizationID string) ([]SecretIdentifier, error) {
+	var out secretListResponse
+	if err := c.get(ctx, "/organizations/"+url.PathEscape(organizationID)+"/secrets", &out); err != nil {
+		return nil, err
+	}
+	ids := make([]SecretIdentifier, 0, len(out.Secrets))
+	for _, s := range out.Secrets {
+		key, err := c.orgKey.decryptString(s.Key)
+		if err != nil {
+			return nil, fmt.Errorf(errDecryptSecret, s.ID, err)
+		}
+		projects := make([]string, 0, len(s.Projects))
+		for _, p := range s.Projects {
+			projects = append(projects, p.ID)
+		}
+		ids = append(ids, SecretIdentifier{ID: s.ID, Key: key, ProjectIDs: projects})
+	}
+	return ids, nil
+}
+
+// get reads a secret or the secrets of an organization, a missing secret or
+// organization is reported as NoSecretErr.
+func (c *apiClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := c.api.NewRequest(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.bearer)
+	return c.api.ReadSecret(req, out)
+}
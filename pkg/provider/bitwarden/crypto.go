@@ -0,0 +1,162 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// encTypeAesCbc256HmacSha256 is the only cipher used for secrets manager payloads.
+	encTypeAesCbc256HmacSha256 = "2"
+
+	errInvalidAccessToken = "invalid access token format"
+	errInvalidEncString   = "invalid encrypted string"
+	errUnsupportedEncType = "unsupported encryption type %s"
+	errInvalidMAC         = "invalid mac, the key does not match the encrypted data"
+	errInvalidPadding     = "invalid padding"
+	errInvalidKeyLength   = "invalid key length %d"
+)
+
+// symmetricKey holds the aes and hmac keys of a bitwarden symmetric crypto key.
+type symmetricKey struct {
+	encKey []byte
+	macKey []byte
+}
+
+func newSymmetricKey(b []byte) (*symmetricKey, error) {
+	if len(b) != 64 {
+		return nil, fmt.Errorf(errInvalidKeyLength, len(b))
+	}
+	return &symmetricKey{encKey: b[:32], macKey: b[32:]}, nil
+}
+
+// accessToken is a parsed machine account access token.
+// It has the format `0.<client id>.<client secret>:<base64 encryption key>`.
+type accessToken struct {
+	clientID     string
+	clientSecret string
+	key          *symmetricKey
+}
+
+func parseAccessToken(token string) (*accessToken, error) {
+	parts := strings.SplitN(strings.TrimSpace(token), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New(errInvalidAccessToken)
+	}
+	ids := strings.Split(parts[0], ".")
+	if len(ids) != 3 || ids[0] != "0" || ids[1] == "" || ids[2] == "" {
+		return nil, errors.New(errInvalidAccessToken)
+	}
+	seed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil || len(seed) != 16 {
+		return nil, errors.New(errInvalidAccessToken)
+	}
+	key, err := deriveShareableKey(seed, "accesstoken", "sm-access-token")
+	if err != nil {
+		return nil, err
+	}
+	return &accessToken{
+		clientID:     ids[1],
+		clientSecret: ids[2],
+		key:          key,
+	}, nil
+}
+
+// deriveShareableKey stretches a random seed into a symmetric key
+// using HKDF-SHA256 with the salt `bitwarden-<name>`.
+func deriveShareableKey(seed []byte, name, info string) (*symmetricKey, error) {
+	r := hkdf.New(sha256.New, seed, []byte("bitwarden-"+name), []byte(info))
+	b := make([]byte, 64)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return newSymmetricKey(b)
+}
+
+// decryptString decrypts an encrypted string of the form `2.<iv>|<data>|<mac>`.
+func (k *symmetricKey) decryptString(enc string) (string, error) {
+	typ, payload, ok := cut(enc, ".")
+	if !ok {
+		return "", errors.New(errInvalidEncString)
+	}
+	if typ != encTypeAesCbc256HmacSha256 {
+		return "", fmt.Errorf(errUnsupportedEncType, typ)
+	}
+	parts := strings.Split(payload, "|")
+	if len(parts) != 3 {
+		return "", errors.New(errInvalidEncString)
+	}
+	var raw [3][]byte
+	for i, p := range parts {
+		b, err := base64.StdEncoding.DecodeString(p)
+		if err != nil {
+			return "", errors.New(errInvalidEncString)
+		}
+		raw[i] = b
+	}
+	iv, data, mac := raw[0], raw[1], raw[2]
+
+	h := hmac.New(sha256.New, k.macKey)
+	h.Write(iv)
+	h.Write(data)
+	if !hmac.Equal(h.Sum(nil), mac) {
+		return "", errors.New(errInvalidMAC)
+	}
+	block, err := aes.NewCipher(k.encKey)
+	if err != nil {
+		return "", err
+	}
+	if len(iv) != aes.BlockSize || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return "", errors.New(errInvalidEncString)
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	out, err = unpad(out)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func unpad(b []byte) ([]byte, error) {
+	n := int(b[len(b)-1])
+	if n == 0 || n > aes.BlockSize || n > len(b) {
+		return nil, errors.New(errInvalidPadding)
+	}
+	if !bytes.Equal(b[len(b)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, errors.New(errInvalidPadding)
+	}
+	return b[:len(b)-n], nil
+}
+
+// cut is strings.Cut, which is not available in go 1.17.
+func cut(s, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
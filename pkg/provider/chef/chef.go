@@ -0,0 +1,298 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chef
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errMissingStoreSpec     = "missing store provider chef"
+	errMissingServerURL     = "missing serverUrl"
+	errInvalidServerURL     = "invalid serverUrl %s: must be an absolute http or https url"
+	errMissingUserName      = "missing auth.userName"
+	errMissingSecretRefName = "missing name in auth.secretRef.%s"
+	errMissingSecretRefKey  = "missing key in auth.secretRef.%s"
+	errInvalidSecretRef     = "invalid auth.secretRef.%s: %w"
+	errFetchCredentials     = "could not fetch credentials secret %s: %w"
+	errMissingCredentials   = "missing %s in secret %s"
+	errInvalidCABundle      = "failed to append caBundle"
+	errUninitalizedClient   = "provider chef is not initialized"
+	errInvalidKey           = "invalid key %s: must have the form <data bag>/<item>"
+	errPropertyNotFound     = "property %s does not exist in item %s"
+	errFindNotImplemented   = "find by tags is not supported by chef"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Chef reads data bag items of a Chef Infra Server organization.
+type Chef struct {
+	client        *apiClient
+	dataBagSecret []byte
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Chef: &esv1beta1.ChefProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.ChefProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Chef == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Chef, nil
+}
+
+// NewClient constructs a Chef Infra Server client which signs requests with the referenced private key.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	privateKey, err := secretKeyRef(ctx, kube, storeKind, namespace, "privateKey", provider.Auth.SecretRef.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	var dataBagSecret string
+	if ref := provider.Auth.SecretRef.DataBagSecret; ref != nil {
+		dataBagSecret, err = secretKeyRef(ctx, kube, storeKind, namespace, "dataBagSecret", *ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(provider.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(provider.CABundle) {
+			return nil, fmt.Errorf(errInvalidCABundle)
+		}
+	}
+	return &Chef{
+		client: newAPIClient(provider.ServerURL, provider.Auth.UserName, key, tlsConfig),
+		// knife strips the secret file as well.
+		dataBagSecret: []byte(strings.TrimSpace(dataBagSecret)),
+	}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := string(secret.Data[ref.Key])
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.ServerURL == "" {
+		return fmt.Errorf(errMissingServerURL)
+	}
+	if u, err := url.Parse(provider.ServerURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf(errInvalidServerURL, provider.ServerURL)
+	}
+	if provider.Auth.UserName == "" {
+		return fmt.Errorf(errMissingUserName)
+	}
+	if err := validateSecretRef(store, "privateKey", provider.Auth.SecretRef.PrivateKey); err != nil {
+		return err
+	}
+	if ref := provider.Auth.SecretRef.DataBagSecret; ref != nil {
+		return validateSecretRef(store, "dataBagSecret", *ref)
+	}
+	return nil
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingSecretRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingSecretRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidSecretRef, name, err)
+	}
+	return nil
+}
+
+// GetSecret returns the decrypted data bag item ref.Key (<data bag>/<item>) as JSON.
+// ref.Property takes a gjson expression selecting a field of the item.
+func (c *Chef) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	data, err := c.getItem(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return data, nil
+	}
+	val, ok := utils.GetProperty(data, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the decrypted fields of a data bag item, except its id.
+func (c *Chef) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	bag, item, err := splitKey(ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := c.decryptedItem(ctx, bag, item)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte, len(fields))
+	for k, v := range fields {
+		if k == "id" {
+			continue
+		}
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns the decrypted items of the data bag ref.Path, or of all
+// data bags if no path is set, whose keys (<data bag>/<item>) match ref.Name.
+func (c *Chef) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	var bags []string
+	if ref.Path != nil {
+		bags = []string{strings.Trim(*ref.Path, "/")}
+	} else {
+		var err error
+		bags, err = c.client.listDataBags(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	secretData := make(map[string][]byte)
+	for _, bag := range bags {
+		items, err := c.client.listItems(ctx, bag)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			key := bag + "/" + item
+			if matcher != nil && !matcher.MatchName(key) {
+				continue
+			}
+			data, err := c.getItem(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			secretData[key] = data
+		}
+	}
+	return secretData, nil
+}
+
+func (c *Chef) getItem(ctx context.Context, key string) ([]byte, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	bag, item, err := splitKey(key)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := c.decryptedItem(ctx, bag, item)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+func (c *Chef) decryptedItem(ctx context.Context, bag, item string) (map[string]json.RawMessage, error) {
+	fields, err := c.client.getItem(ctx, bag, item)
+	if err != nil {
+		return nil, err
+	}
+	return decryptItem(fields, c.dataBagSecret)
+}
+
+func splitKey(key string) (string, string, error) {
+	parts := strings.Split(strings.Trim(key, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf(errInvalidKey, key)
+	}
+	return parts[0], parts[1], nil
+}
+
+func (c *Chef) Close(ctx context.Context) error {
+	return nil
+}
+
+func (c *Chef) Validate() error {
+	return nil
+}
@@ -0,0 +1,416 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chef
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	testOrgPath = "/organizations/example"
+	testUser    = "external-secrets"
+	testSecret  = "s3cr3t-data-bag-key"
+)
+
+func wrap(t *testing.T, value interface{}) []byte {
+	plain, err := json.Marshal(map[string]interface{}{"json_wrapper": value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plain
+}
+
+func encryptV3(t *testing.T, value interface{}) map[string]interface{} {
+	key := sha256.Sum256([]byte(testSecret))
+	block, _ := aes.NewCipher(key[:])
+	gcm, _ := cipher.NewGCM(block)
+	iv := make([]byte, gcm.NonceSize())
+	rand.Read(iv)
+	out := gcm.Seal(nil, iv, wrap(t, value), nil)
+	data, tag := out[:len(out)-gcm.Overhead()], out[len(out)-gcm.Overhead():]
+	return map[string]interface{}{
+		"encrypted_data": base64.StdEncoding.EncodeToString(data),
+		"iv":             base64.StdEncoding.EncodeToString(iv),
+		"auth_tag":       base64.StdEncoding.EncodeToString(tag),
+		"version":        3,
+		"cipher":         "aes-256-gcm",
+	}
+}
+
+func encryptCBC(t *testing.T, value interface{}, version int) map[string]interface{} {
+	key := sha256.Sum256([]byte(testSecret))
+	block, _ := aes.NewCipher(key[:])
+	plain := wrap(t, value)
+	pad := aes.BlockSize - len(plain)%aes.BlockSize
+	plain = append(plain, []byte(strings.Repeat(string(rune(pad)), pad))...)
+	iv := make([]byte, aes.BlockSize)
+	rand.Read(iv)
+	data := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, plain)
+	// ruby's Base64.encode64 breaks lines after 60 characters.
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var lines []string
+	for len(encoded) > 60 {
+		lines, encoded = append(lines, encoded[:60]), encoded[60:]
+	}
+	encoded = strings.Join(append(lines, encoded), "\n") + "\n"
+	out := map[string]interface{}{
+		"encrypted_data": encoded,
+		"iv":             base64.StdEncoding.EncodeToString(iv),
+		"version":        version,
+		"cipher":         "aes-256-cbc",
+	}
+	if version == 2 {
+		mac := hmac.New(sha256.New, []byte(testSecret))
+		mac.Write([]byte(encoded))
+		out["hmac"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	return out
+}
+
+// newTestServer serves data bags and rejects requests that are not signed by key.
+func newTestServer(t *testing.T, key *rsa.PublicKey) *httptest.Server {
+	bags := map[string]map[string]interface{}{
+		"credentials": {
+			"db": map[string]interface{}{
+				"id":       "db",
+				"username": encryptV3(t, "admin"),
+				"password": encryptCBC(t, "hunter2", 2),
+				"port":     encryptCBC(t, 5432, 1),
+				"hosts":    encryptV3(t, []string{"db-0", "db-1"}),
+			},
+			"api": map[string]interface{}{
+				"id":    "api",
+				"token": encryptV3(t, "t0ken"),
+			},
+		},
+		"config": {
+			"app": map[string]interface{}{"id": "app", "log_level": "debug"},
+		},
+		"broken": {
+			"hmac": map[string]interface{}{
+				"id": "hmac",
+				"password": func() map[string]interface{} {
+					v := encryptCBC(t, "hunter2", 2)
+					v["hmac"] = base64.StdEncoding.EncodeToString(make([]byte, 32))
+					return v
+				}(),
+			},
+		},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifyRequest(r, key); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{"error": [%q]}`, err.Error())
+			return
+		}
+		path := strings.TrimPrefix(r.URL.Path, testOrgPath+"/data")
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		var out interface{}
+		switch {
+		case path == "":
+			list := map[string]string{}
+			for bag := range bags {
+				list[bag] = "https://chef.example.com" + testOrgPath + "/data/" + bag
+			}
+			out = list
+		case len(parts) == 1 && bags[parts[0]] != nil:
+			list := map[string]string{}
+			for item := range bags[parts[0]] {
+				list[item] = "https://chef.example.com" + r.URL.Path + "/" + item
+			}
+			out = list
+		case len(parts) == 2 && bags[parts[0]][parts[1]] != nil:
+			out = bags[parts[0]][parts[1]]
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": ["not found"]}`)
+			return
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func verifyRequest(r *http.Request, key *rsa.PublicKey) error {
+	if r.Header.Get("X-Ops-UserId") != testUser || r.Header.Get("X-Ops-Sign") != "algorithm=sha256;version=1.3" {
+		return fmt.Errorf("invalid user or protocol")
+	}
+	var encoded string
+	for i := 1; r.Header.Get(fmt.Sprintf("X-Ops-Authorization-%d", i)) != ""; i++ {
+		chunk := r.Header.Get(fmt.Sprintf("X-Ops-Authorization-%d", i))
+		if len(chunk) > 60 {
+			return fmt.Errorf("authorization header too long")
+		}
+		encoded += chunk
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+	emptyHash := sha256.Sum256(nil)
+	if r.Header.Get("X-Ops-Content-Hash") != base64.StdEncoding.EncodeToString(emptyHash[:]) {
+		return fmt.Errorf("invalid content hash")
+	}
+	canonical := fmt.Sprintf("Method:%s\nPath:%s\nX-Ops-Content-Hash:%s\nX-Ops-Sign:version=1.3\nX-Ops-Timestamp:%s\nX-Ops-UserId:%s\nX-Ops-Server-API-Version:%s",
+		r.Method, r.URL.EscapedPath(), r.Header.Get("X-Ops-Content-Hash"), r.Header.Get("X-Ops-Timestamp"), testUser, r.Header.Get("X-Ops-Server-API-Version"))
+	digest := sha256.Sum256([]byte(canonical))
+	return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig)
+}
+
+func makeStore(serverURL string, dataBagSecret bool) *esv1beta1.SecretStore {
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Chef: &esv1beta1.ChefProvider{
+					ServerURL: serverURL,
+					Auth: esv1beta1.ChefAuth{
+						UserName: testUser,
+						SecretRef: esv1beta1.ChefAuthSecretRef{
+							PrivateKey: esmeta.SecretKeySelector{Name: "chef", Key: "client.pem"},
+						},
+					},
+				},
+			},
+		},
+	}
+	if dataBagSecret {
+		store.Spec.Provider.Chef.Auth.SecretRef.DataBagSecret = &esmeta.SecretKeySelector{Name: "chef", Key: "secret"}
+	}
+	return store
+}
+
+func newTestClient(t *testing.T, dataBagSecret bool) esv1beta1.SecretsClient {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestServer(t, &key.PublicKey)
+	t.Cleanup(srv.Close)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "chef", Namespace: "default"},
+		Data: map[string][]byte{
+			"client.pem": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			"secret":     []byte(testSecret + "\n"),
+		},
+	}).Build()
+	c, err := (&Provider{}).NewClient(context.Background(), makeStore(srv.URL+testOrgPath+"/", dataBagSecret), kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestChefGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"version 3": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/db", Property: "username"},
+			want: "admin",
+		},
+		"version 2": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/db", Property: "password"},
+			want: "hunter2",
+		},
+		"version 1": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/db", Property: "port"},
+			want: "5432",
+		},
+		"nested property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/db", Property: "hosts.1"},
+			want: "db-1",
+		},
+		"whole item": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/api"},
+			want: `{"id":"api","token":"t0ken"}`,
+		},
+		"unencrypted item": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/config/app/", Property: "log_level"},
+			want: "debug",
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/db", Property: "user"},
+			expectError: "property user does not exist in item credentials/db",
+		},
+		"invalid hmac": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "broken/hmac"},
+			expectError: "invalid hmac of field password",
+		},
+		"invalid key": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials"},
+			expectError: "invalid key credentials: must have the form <data bag>/<item>",
+		},
+		"missing item": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c := newTestClient(t, true)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestChefMissingDataBagSecret(t *testing.T) {
+	c := newTestClient(t, false)
+	if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "config/app"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/api"})
+	if !ErrorContains(err, "field token is encrypted but the store has no dataBagSecret") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestChefGetSecretMap(t *testing.T) {
+	c := newTestClient(t, true)
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "credentials/db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("hunter2"),
+		"port":     []byte("5432"),
+		"hosts":    []byte(`["db-0","db-1"]`),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+}
+
+func TestChefGetAllSecrets(t *testing.T) {
+	c := newTestClient(t, true)
+	out, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("credentials")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || string(out["credentials/api"]) != `{"id":"api","token":"t0ken"}` {
+		t.Errorf("unexpected secrets: %v", out)
+	}
+	out, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^(config|credentials)/a"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || out["config/app"] == nil || out["credentials/api"] == nil {
+		t.Errorf("unexpected secrets: %v", out)
+	}
+	_, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Tags: map[string]string{"foo": "bar"}})
+	if !ErrorContains(err, errFindNotImplemented) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestChefInvalidSignature(t *testing.T) {
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	srv := newTestServer(t, &other.PublicKey)
+	defer srv.Close()
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	c := &Chef{client: newAPIClient(srv.URL+testOrgPath, testUser, key, nil)}
+	_, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "config/app"})
+	if !ErrorContains(err, "chef api returned status 401: crypto/rsa: verification error") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	missingUser := makeStore("https://chef.example.com/organizations/example", false)
+	missingUser.Spec.Provider.Chef.Auth.UserName = ""
+	missingSecretKey := makeStore("https://chef.example.com/organizations/example", true)
+	missingSecretKey.Spec.Provider.Chef.Auth.SecretRef.DataBagSecret.Key = ""
+	namespaced := makeStore("https://chef.example.com/organizations/example", false)
+	namespaced.Spec.Provider.Chef.Auth.SecretRef.PrivateKey.Namespace = pointer.StringPtr("foo")
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("https://chef.example.com/organizations/example", true),
+		},
+		"missing server url": {
+			store:       makeStore("", false),
+			expectError: errMissingServerURL,
+		},
+		"invalid server url": {
+			store:       makeStore("chef.example.com", false),
+			expectError: "invalid serverUrl chef.example.com",
+		},
+		"missing user name": {
+			store:       missingUser,
+			expectError: errMissingUserName,
+		},
+		"missing data bag secret key": {
+			store:       missingSecretKey,
+			expectError: "missing key in auth.secretRef.dataBagSecret",
+		},
+		"namespace not allowed": {
+			store:       namespaced,
+			expectError: "invalid auth.secretRef.privateKey: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
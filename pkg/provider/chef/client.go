@@ -0,0 +1,207 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chef
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errAPIRequest       = "chef api request failed: %w"
+	errAPIResponse      = "chef api returned status %d: %s"
+	errAPIDecode        = "unable to decode chef api response: %w"
+	errInvalidKeyPEM    = "private key is not PEM encoded"
+	errParsePrivateKey  = "unable to parse private key: %w"
+	errUnsupportedKey   = "private key must be an RSA key"
+	errSignRequest      = "unable to sign request: %w"
+	serverAPIVersion    = "1"
+	authHeaderChunkSize = 60
+)
+
+var slashesRegexp = regexp.MustCompile(`/+`)
+
+// apiClient reads data bags through the Chef Infra Server API, requests are
+// signed with version 1.3 of the Chef authentication protocol.
+// see: https://docs.chef.io/server/api_chef_server/#authentication-headers
+type apiClient struct {
+	baseURL  string
+	userName string
+	key      *rsa.PrivateKey
+	http     *http.Client
+	now      func() time.Time
+}
+
+func newAPIClient(serverURL, userName string, key *rsa.PrivateKey, tlsConfig *tls.Config) *apiClient {
+	return &apiClient{
+		baseURL:  strings.TrimSuffix(serverURL, "/"),
+		userName: userName,
+		key:      key,
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		now: time.Now,
+	}
+}
+
+// parsePrivateKey accepts PKCS #1 and PKCS #8 encoded RSA keys.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf(errInvalidKeyPEM)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf(errParsePrivateKey, err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf(errUnsupportedKey)
+	}
+	return rsaKey, nil
+}
+
+// listDataBags returns the names of all data bags of the organization.
+func (c *apiClient) listDataBags(ctx context.Context) ([]string, error) {
+	return c.listNames(ctx, "/data")
+}
+
+// listItems returns the ids of all items of a data bag.
+func (c *apiClient) listItems(ctx context.Context, bag string) ([]string, error) {
+	return c.listNames(ctx, "/data/"+url.PathEscape(bag))
+}
+
+// getItem returns the raw JSON of a data bag item.
+func (c *apiClient) getItem(ctx context.Context, bag, item string) (map[string]json.RawMessage, error) {
+	var out map[string]json.RawMessage
+	if err := c.get(ctx, "/data/"+url.PathEscape(bag)+"/"+url.PathEscape(item), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// listNames returns the sorted keys of a name to URL listing.
+func (c *apiClient) listNames(ctx context.Context, path string) ([]string, error) {
+	var out map[string]string
+	if err := c.get(ctx, path, &out); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(out))
+	for name := range out {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *apiClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := c.sign(req, nil); err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return esv1beta1.NoSecretErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(errAPIResponse, resp.StatusCode, apiErrorMessage(msg))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf(errAPIDecode, err)
+	}
+	return nil
+}
+
+// sign adds the authentication headers of protocol version 1.3 to the request.
+func (c *apiClient) sign(req *http.Request, body []byte) error {
+	bodyHash := sha256.Sum256(body)
+	contentHash := base64.StdEncoding.EncodeToString(bodyHash[:])
+	timestamp := c.now().UTC().Format(time.RFC3339)
+	path := slashesRegexp.ReplaceAllString(req.URL.EscapedPath(), "/")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	canonical := strings.Join([]string{
+		"Method:" + req.Method,
+		"Path:" + path,
+		"X-Ops-Content-Hash:" + contentHash,
+		"X-Ops-Sign:version=1.3",
+		"X-Ops-Timestamp:" + timestamp,
+		"X-Ops-UserId:" + c.userName,
+		"X-Ops-Server-API-Version:" + serverAPIVersion,
+	}, "\n")
+	digest := sha256.Sum256([]byte(canonical))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return fmt.Errorf(errSignRequest, err)
+	}
+	req.Header.Set("X-Ops-Sign", "algorithm=sha256;version=1.3")
+	req.Header.Set("X-Ops-UserId", c.userName)
+	req.Header.Set("X-Ops-Timestamp", timestamp)
+	req.Header.Set("X-Ops-Content-Hash", contentHash)
+	req.Header.Set("X-Ops-Server-API-Version", serverAPIVersion)
+	encoded := base64.StdEncoding.EncodeToString(sig)
+	for i := 0; i*authHeaderChunkSize < len(encoded); i++ {
+		end := (i + 1) * authHeaderChunkSize
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		req.Header.Set(fmt.Sprintf("X-Ops-Authorization-%d", i+1), encoded[i*authHeaderChunkSize:end])
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the messages of an error response, which has the form {"error": ["..."]}.
+func apiErrorMessage(body []byte) string {
+	var apiErr struct {
+		Error []string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err == nil && len(apiErr.Error) > 0 {
+		return strings.Join(apiErr.Error, ", ")
+	}
+	return string(bytes.TrimSpace(body))
+}
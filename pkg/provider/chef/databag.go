@@ -0,0 +1,145 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chef
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	errMissingDataBagSecret = "field %s is encrypted but the store has no dataBagSecret"
+	errUnsupportedVersion   = "field %s uses unsupported encryption version %d"
+	errDecodeField          = "unable to decode field %s: %w"
+	errDecryptField         = "unable to decrypt field %s, check the dataBagSecret"
+	errInvalidHMAC          = "invalid hmac of field %s, check the dataBagSecret"
+)
+
+// encryptedValue is a field of an encrypted data bag item.
+// see: https://docs.chef.io/data_bags/#encrypt-a-data-bag-item
+type encryptedValue struct {
+	EncryptedData string `json:"encrypted_data"`
+	IV            string `json:"iv"`
+	Version       int    `json:"version"`
+	Cipher        string `json:"cipher"`
+	// HMAC is set by version 2.
+	HMAC string `json:"hmac,omitempty"`
+	// AuthTag is set by version 3.
+	AuthTag string `json:"auth_tag,omitempty"`
+}
+
+// decryptItem returns the fields of a data bag item, encrypted fields are
+// decrypted with secret. The id of an item is never encrypted.
+func decryptItem(item map[string]json.RawMessage, secret []byte) (map[string]json.RawMessage, error) {
+	out := make(map[string]json.RawMessage, len(item))
+	for field, raw := range item {
+		var enc encryptedValue
+		if field == "id" || json.Unmarshal(raw, &enc) != nil || enc.EncryptedData == "" {
+			out[field] = raw
+			continue
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf(errMissingDataBagSecret, field)
+		}
+		val, err := decryptValue(field, &enc, secret)
+		if err != nil {
+			return nil, err
+		}
+		out[field] = val
+	}
+	return out, nil
+}
+
+func decryptValue(field string, enc *encryptedValue, secret []byte) (json.RawMessage, error) {
+	data, err := base64.StdEncoding.DecodeString(enc.EncryptedData)
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeField, field, err)
+	}
+	iv, err := base64.StdEncoding.DecodeString(enc.IV)
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeField, field, err)
+	}
+	key := sha256.Sum256(secret)
+	var plain []byte
+	switch enc.Version {
+	case 1, 2:
+		if enc.Version == 2 {
+			// the hmac is computed over the base64 encoded data with the plain secret.
+			mac := hmac.New(sha256.New, secret)
+			mac.Write([]byte(enc.EncryptedData))
+			expected, err := base64.StdEncoding.DecodeString(enc.HMAC)
+			if err != nil || !hmac.Equal(mac.Sum(nil), expected) {
+				return nil, fmt.Errorf(errInvalidHMAC, field)
+			}
+		}
+		plain, err = decryptCBC(key[:], iv, data)
+	case 3:
+		var tag []byte
+		tag, err = base64.StdEncoding.DecodeString(enc.AuthTag)
+		if err != nil {
+			return nil, fmt.Errorf(errDecodeField, field, err)
+		}
+		plain, err = decryptGCM(key[:], iv, append(data, tag...))
+	default:
+		return nil, fmt.Errorf(errUnsupportedVersion, field, enc.Version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptField, field)
+	}
+	// values are wrapped so that any JSON type can be encrypted.
+	var wrapper struct {
+		Value json.RawMessage `json:"json_wrapper"`
+	}
+	if err := json.Unmarshal(plain, &wrapper); err != nil || wrapper.Value == nil {
+		return nil, fmt.Errorf(errDecryptField, field)
+	}
+	return wrapper.Value, nil
+}
+
+func decryptCBC(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+	// remove the PKCS #7 padding.
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > block.BlockSize() || !bytes.Equal(plain[len(plain)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return plain[:len(plain)-pad], nil
+}
+
+func decryptGCM(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, iv, data, nil)
+}
@@ -0,0 +1,126 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consul
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errAPIRequest  = "consul api request failed: %w"
+	errAPIResponse = "consul api returned status %d: %s"
+	errAPIDecode   = "unable to decode consul api response: %w"
+)
+
+// kvPair is an entry of the KV store. Value is base64 encoded by the API.
+type kvPair struct {
+	Key   string `json:"Key"`
+	Value []byte `json:"Value"`
+}
+
+// apiClient reads the KV store through the Consul HTTP API.
+// see: https://developer.hashicorp.com/consul/api-docs/kv
+type apiClient struct {
+	baseURL string
+	token   string
+	// query holds the namespace, partition and datacenter of all requests.
+	query url.Values
+	http  *http.Client
+}
+
+func newAPIClient(server, token string, query url.Values, tlsConfig *tls.Config) *apiClient {
+	return &apiClient{
+		baseURL: strings.TrimSuffix(server, "/") + "/v1/kv/",
+		token:   token,
+		query:   query,
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}
+}
+
+// get returns the entry with the given key.
+func (c *apiClient) get(ctx context.Context, key string) (*kvPair, error) {
+	pairs, err := c.list(ctx, key, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return nil, esv1beta1.NoSecretErr
+	}
+	return &pairs[0], nil
+}
+
+// list returns the entry with the given key or, if recurse is set,
+// all entries whose keys start with key.
+func (c *apiClient) list(ctx context.Context, key string, recurse bool) ([]kvPair, error) {
+	params := url.Values{}
+	for k, v := range c.query {
+		params[k] = v
+	}
+	if recurse {
+		params.Set("recurse", "true")
+	}
+	u := c.baseURL + escapeKey(strings.TrimPrefix(key, "/"))
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf(errAPIRequest, err)
+	}
+	req.Header.Set("X-Consul-Token", c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(errAPIRequest, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		if recurse {
+			return nil, nil
+		}
+		return nil, esv1beta1.NoSecretErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		// errors are returned as plain text.
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf(errAPIResponse, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var out []kvPair
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf(errAPIDecode, err)
+	}
+	return out, nil
+}
+
+// escapeKey escapes the segments of a key but keeps its slashes.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,228 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consul
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errMissingStoreSpec    = "missing store provider consul"
+	errMissingServer       = "missing server"
+	errInvalidServer       = "invalid server %s: must be an absolute http or https url"
+	errMissingTokenName    = "missing auth.secretRef.token.name"
+	errMissingTokenKey     = "missing auth.secretRef.token.key"
+	errInvalidTokenRef     = "invalid auth.secretRef.token: %w"
+	errFetchTokenSecret    = "could not fetch token secret: %w"
+	errMissingToken        = "missing token in secret %s"
+	errInvalidCABundle     = "failed to append caBundle"
+	errUninitalizedClient  = "provider consul is not initialized"
+	errPropertyNotFound    = "property %s does not exist in key %s"
+	errJSONSecretUnmarshal = "unable to unmarshal key %s: %w"
+	errFindNotImplemented  = "find by tags is not supported by consul"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Consul reads entries of the Consul KV store.
+type Consul struct {
+	client *apiClient
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Consul: &esv1beta1.ConsulProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.ConsulProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Consul == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Consul, nil
+}
+
+// NewClient constructs a Consul KV client using the ACL token referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	tokenRef := provider.Auth.SecretRef.Token
+	objectKey := types.NamespacedName{
+		Name:      tokenRef.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind && tokenRef.Namespace != nil {
+		objectKey.Namespace = *tokenRef.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errFetchTokenSecret, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[tokenRef.Key]))
+	if token == "" {
+		return nil, fmt.Errorf(errMissingToken, tokenRef.Name)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(provider.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(provider.CABundle) {
+			return nil, fmt.Errorf(errInvalidCABundle)
+		}
+	}
+	query := url.Values{}
+	if provider.Namespace != "" {
+		query.Set("ns", provider.Namespace)
+	}
+	if provider.Partition != "" {
+		query.Set("partition", provider.Partition)
+	}
+	if provider.Datacenter != "" {
+		query.Set("dc", provider.Datacenter)
+	}
+	return &Consul{
+		client: newAPIClient(provider.Server, token, query, tlsConfig),
+	}, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.Server == "" {
+		return fmt.Errorf(errMissingServer)
+	}
+	if u, err := url.Parse(provider.Server); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf(errInvalidServer, provider.Server)
+	}
+	tokenRef := provider.Auth.SecretRef.Token
+	if tokenRef.Name == "" {
+		return fmt.Errorf(errMissingTokenName)
+	}
+	if tokenRef.Key == "" {
+		return fmt.Errorf(errMissingTokenKey)
+	}
+	if err := utils.ValidateSecretSelector(store, tokenRef); err != nil {
+		return fmt.Errorf(errInvalidTokenRef, err)
+	}
+	return nil
+}
+
+// GetSecret returns the value of the KV entry ref.Key.
+// ref.Property takes a gjson expression on a JSON encoded value.
+func (c *Consul) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	pair, err := c.client.get(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return pair.Value, nil
+	}
+	val, ok := utils.GetProperty(pair.Value, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the key/value pairs of a KV entry holding a JSON object.
+func (c *Consul) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := c.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets recursively returns all KV entries below the prefix ref.Path
+// whose keys match ref.Name. Folder entries are skipped.
+func (c *Consul) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	var prefix string
+	if ref.Path != nil {
+		prefix = *ref.Path
+	}
+	pairs, err := c.client.list(ctx, prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte)
+	for _, pair := range pairs {
+		if strings.HasSuffix(pair.Key, "/") {
+			continue
+		}
+		if matcher != nil && !matcher.MatchName(pair.Key) {
+			continue
+		}
+		secretData[pair.Key] = pair.Value
+	}
+	return secretData, nil
+}
+
+func (c *Consul) Close(ctx context.Context) error {
+	return nil
+}
+
+func (c *Consul) Validate() error {
+	return nil
+}
@@ -0,0 +1,257 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+var testPairs = []kvPair{
+	{Key: "app/"},
+	{Key: "app/db", Value: []byte(`{"user":"admin","password":"hunter2","port":5432}`)},
+	{Key: "app/api token", Value: []byte("s3cr3t")},
+	{Key: "app/feature/flags", Value: []byte("on")},
+	{Key: "other/key", Value: []byte("other")},
+}
+
+func newFakeConsul(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("Permission denied: token with AccessorID '00000000-0000-0000-0000-000000000002' lacks permission 'key:read'"))
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("ns") != "team-a" || q.Get("partition") != "apps" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+		out := []kvPair{}
+		for _, pair := range testPairs {
+			if pair.Key == key || (q.Get("recurse") == "true" && strings.HasPrefix(pair.Key, key)) {
+				out = append(out, pair)
+			}
+		}
+		if len(out) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func makeStore(server string, token esmeta.SecretKeySelector) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Consul: &esv1beta1.ConsulProvider{
+					Server:    server,
+					Namespace: "team-a",
+					Partition: "apps",
+					Auth: esv1beta1.ConsulAuth{
+						SecretRef: esv1beta1.ConsulAuthSecretRef{
+							Token: token,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, token string) esv1beta1.SecretsClient {
+	srv := newFakeConsul(t)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "consul", Namespace: "default"},
+		Data: map[string][]byte{
+			"token": []byte(token),
+		},
+	}).Build()
+	store := makeStore(srv.URL, esmeta.SecretKeySelector{Name: "consul", Key: "token"})
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestConsulGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "app/api token"},
+			want: "s3cr3t",
+		},
+		"leading slash": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/feature/flags"},
+			want: "on",
+		},
+		"property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "app/db", Property: "password"},
+			want: "hunter2",
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "app/db", Property: "host"},
+			expectError: "property host does not exist in key app/db",
+		},
+		"missing key": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "app/nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c := newTestClient(t, "token")
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestConsulGetSecretMap(t *testing.T) {
+	c := newTestClient(t, "token")
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "app/db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"user":     []byte("admin"),
+		"password": []byte("hunter2"),
+		"port":     []byte("5432"),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+}
+
+func TestConsulGetAllSecrets(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		expectError string
+	}{
+		"recursive prefix": {
+			ref: esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("app/")},
+			want: map[string][]byte{
+				"app/db":            []byte(`{"user":"admin","password":"hunter2","port":5432}`),
+				"app/api token":     []byte("s3cr3t"),
+				"app/feature/flags": []byte("on"),
+			},
+		},
+		"prefix and name": {
+			ref:  esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("app/"), Name: &esv1beta1.FindName{RegExp: "flags$"}},
+			want: map[string][]byte{"app/feature/flags": []byte("on")},
+		},
+		"missing prefix": {
+			ref:  esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("nope/")},
+			want: map[string][]byte{},
+		},
+		"tags": {
+			ref:         esv1beta1.ExternalSecretFind{Tags: map[string]string{"env": "prod"}},
+			expectError: errFindNotImplemented,
+		},
+	}
+	c := newTestClient(t, "token")
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secrets: expected %#v, got %#v", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestConsulPermissionDenied(t *testing.T) {
+	c := newTestClient(t, "wrong")
+	_, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "app/db"})
+	if !ErrorContains(err, "consul api returned status 403: Permission denied") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	token := esmeta.SecretKeySelector{Name: "consul", Key: "token"}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("https://consul.example.com:8501", token),
+		},
+		"missing server": {
+			store:       makeStore("", token),
+			expectError: errMissingServer,
+		},
+		"invalid server": {
+			store:       makeStore("consul:8500", token),
+			expectError: "invalid server consul:8500",
+		},
+		"missing token key": {
+			store:       makeStore("http://consul:8500", esmeta.SecretKeySelector{Name: "consul"}),
+			expectError: errMissingTokenKey,
+		},
+		"namespace not allowed": {
+			store:       makeStore("http://consul:8500", esmeta.SecretKeySelector{Name: "consul", Key: "token", Namespace: pointer.StringPtr("foo")}),
+			expectError: "invalid auth.secretRef.token: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
@@ -0,0 +1,134 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doppler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	defaultURL = "https://api.doppler.com"
+
+	errAPIRequest  = "doppler api request failed: %w"
+	errAPIResponse = "doppler api returned status %d: %s"
+	errAPIDecode   = "unable to decode doppler api response: %w"
+)
+
+// SecretsClient is the subset of the Doppler API used by the provider.
+type SecretsClient interface {
+	// GetSecret returns the computed value of a single secret.
+	GetSecret(ctx context.Context, project, config, name string) (string, error)
+	// GetSecrets returns all computed secrets of a config.
+	GetSecrets(ctx context.Context, project, config string) (map[string]string, error)
+}
+
+// apiClient implements SecretsClient against the Doppler REST API.
+// see: https://docs.doppler.com/reference/api
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+type secretResponse struct {
+	Name  string `json:"name"`
+	Value struct {
+		Raw      string `json:"raw"`
+		Computed string `json:"computed"`
+	} `json:"value"`
+}
+
+type errorResponse struct {
+	Messages []string `json:"messages"`
+}
+
+func newAPIClient(baseURL, token string) *apiClient {
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *apiClient) GetSecret(ctx context.Context, project, config, name string) (string, error) {
+	params := scopeParams(project, config)
+	params.Set("name", name)
+	var out secretResponse
+	if err := c.get(ctx, "/v3/configs/config/secret", params, &out); err != nil {
+		return "", err
+	}
+	return out.Value.Computed, nil
+}
+
+func (c *apiClient) GetSecrets(ctx context.Context, project, config string) (map[string]string, error) {
+	params := scopeParams(project, config)
+	params.Set("format", "json")
+	out := make(map[string]string)
+	if err := c.get(ctx, "/v3/configs/config/secrets/download", params, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+params.Encode(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return esv1beta1.NoSecretErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf(errAPIResponse, resp.StatusCode, strings.Join(errResp.Messages, ", "))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf(errAPIDecode, err)
+	}
+	return nil
+}
+
+// scopeParams returns the query parameters that select a config.
+// Service tokens are scoped to a single config, so both may be empty.
+func scopeParams(project, config string) url.Values {
+	params := url.Values{}
+	if project != "" {
+		params.Set("project", project)
+	}
+	if config != "" {
+		params.Set("config", config)
+	}
+	return params
+}
@@ -0,0 +1,199 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doppler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errMissingStoreSpec          = "missing store provider doppler"
+	errMissingTokenName          = "missing auth.secretRef.dopplerToken.name"
+	errMissingTokenKey           = "missing auth.secretRef.dopplerToken.key"
+	errInvalidTokenRef           = "invalid auth.secretRef.dopplerToken: %w"
+	errFetchTokenSecret          = "could not fetch dopplerToken secret: %w"
+	errMissingToken              = "missing dopplerToken in secret %s"
+	errUninitalizedDopplerClient = "provider doppler is not initialized"
+	errPropertyNotFound          = "property %s does not exist in secret %s"
+	errJSONSecretUnmarshal       = "unable to unmarshal secret %s: %w"
+	errFindNotImplemented        = "find by tags is not supported by doppler"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Doppler reads secrets of a single Doppler config.
+type Doppler struct {
+	client  SecretsClient
+	project string
+	config  string
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Doppler: &esv1beta1.DopplerProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.DopplerProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Doppler == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Doppler, nil
+}
+
+// NewClient constructs a Doppler client using the token referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	tokenRef := provider.Auth.SecretRef.DopplerToken
+	objectKey := types.NamespacedName{
+		Name:      tokenRef.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind && tokenRef.Namespace != nil {
+		objectKey.Namespace = *tokenRef.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errFetchTokenSecret, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[tokenRef.Key]))
+	if token == "" {
+		return nil, fmt.Errorf(errMissingToken, tokenRef.Name)
+	}
+	return &Doppler{
+		client:  newAPIClient(provider.URL, token),
+		project: provider.Project,
+		config:  provider.Config,
+	}, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	tokenRef := provider.Auth.SecretRef.DopplerToken
+	if tokenRef.Name == "" {
+		return fmt.Errorf(errMissingTokenName)
+	}
+	if tokenRef.Key == "" {
+		return fmt.Errorf(errMissingTokenKey)
+	}
+	if err := utils.ValidateSecretSelector(store, tokenRef); err != nil {
+		return fmt.Errorf(errInvalidTokenRef, err)
+	}
+	return nil
+}
+
+// GetSecret returns the value of the Doppler secret with the name ref.Key.
+// If ref.Property is set the value is parsed as JSON and the property is returned.
+func (d *Doppler) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if utils.IsNil(d.client) {
+		return nil, fmt.Errorf(errUninitalizedDopplerClient)
+	}
+	value, err := d.client.GetSecret(ctx, d.project, d.config, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return []byte(value), nil
+	}
+	val, ok := utils.GetProperty([]byte(value), ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the key/value pairs of a Doppler secret that holds a JSON object.
+func (d *Doppler) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := d.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte)
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns all secrets of the config whose names match ref.Name.
+// Without a name filter the whole config is returned.
+func (d *Doppler) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if utils.IsNil(d.client) {
+		return nil, fmt.Errorf(errUninitalizedDopplerClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	secrets, err := d.client.GetSecrets(ctx, d.project, d.config)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte)
+	for name, value := range secrets {
+		if matcher != nil && !matcher.MatchName(name) {
+			continue
+		}
+		if ref.Path != nil && !strings.HasPrefix(name, *ref.Path) {
+			continue
+		}
+		secretData[name] = []byte(value)
+	}
+	return secretData, nil
+}
+
+func (d *Doppler) Close(ctx context.Context) error {
+	return nil
+}
+
+func (d *Doppler) Validate() error {
+	return nil
+}
@@ -0,0 +1,330 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doppler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/provider/doppler/fake"
+)
+
+type dopplerTestCase struct {
+	mockClient     *fake.DopplerMockClient
+	ref            *esv1beta1.ExternalSecretDataRemoteRef
+	refFind        *esv1beta1.ExternalSecretFind
+	apiValue       string
+	apiSecrets     map[string]string
+	apiErr         error
+	expectError    string
+	expectedSecret string
+	// for testing secretmap
+	expectedData map[string][]byte
+}
+
+func makeValidDopplerTestCase() *dopplerTestCase {
+	return &dopplerTestCase{
+		mockClient:     &fake.DopplerMockClient{},
+		ref:            &esv1beta1.ExternalSecretDataRemoteRef{Key: "API_KEY"},
+		refFind:        &esv1beta1.ExternalSecretFind{},
+		apiValue:       "secret",
+		apiSecrets:     map[string]string{},
+		expectedSecret: "secret",
+		expectedData:   map[string][]byte{},
+	}
+}
+
+func makeValidDopplerTestCaseCustom(tweaks ...func(tc *dopplerTestCase)) *dopplerTestCase {
+	tc := makeValidDopplerTestCase()
+	for _, fn := range tweaks {
+		fn(tc)
+	}
+	tc.mockClient.WithValue(tc.apiValue, tc.apiErr)
+	tc.mockClient.WithSecrets(tc.apiSecrets, tc.apiErr)
+	return tc
+}
+
+var setAPIErr = func(tc *dopplerTestCase) {
+	tc.apiErr = fmt.Errorf("oh no")
+	tc.expectError = "oh no"
+}
+
+var setNilMockClient = func(tc *dopplerTestCase) {
+	tc.mockClient = nil
+	tc.expectError = errUninitalizedDopplerClient
+}
+
+func TestDopplerGetSecret(t *testing.T) {
+	setProperty := func(tc *dopplerTestCase) {
+		tc.apiValue = `{"user": {"name": "foo"}}`
+		tc.ref.Property = "user.name"
+		tc.expectedSecret = "foo"
+	}
+	setMissingProperty := func(tc *dopplerTestCase) {
+		tc.apiValue = `{"user": {"name": "foo"}}`
+		tc.ref.Property = "user.password"
+		tc.expectError = "property user.password does not exist in secret API_KEY"
+	}
+	setNotFound := func(tc *dopplerTestCase) {
+		tc.apiErr = esv1beta1.NoSecretErr
+		tc.expectError = esv1beta1.NoSecretErr.Error()
+	}
+
+	cases := []*dopplerTestCase{
+		makeValidDopplerTestCaseCustom(),
+		makeValidDopplerTestCaseCustom(setProperty),
+		makeValidDopplerTestCaseCustom(setMissingProperty),
+		makeValidDopplerTestCaseCustom(setNotFound),
+		makeValidDopplerTestCaseCustom(setAPIErr),
+		makeValidDopplerTestCaseCustom(setNilMockClient),
+	}
+
+	d := Doppler{}
+	for k, v := range cases {
+		d.client = v.mockClient
+		out, err := d.GetSecret(context.Background(), *v.ref)
+		if !ErrorContains(err, v.expectError) {
+			t.Errorf("[%d] unexpected error: %v, expected: '%s'", k, err, v.expectError)
+		}
+		if err == nil && string(out) != v.expectedSecret {
+			t.Errorf("[%d] unexpected secret: expected %s, got %s", k, v.expectedSecret, string(out))
+		}
+	}
+}
+
+func TestDopplerGetSecretMap(t *testing.T) {
+	setJSON := func(tc *dopplerTestCase) {
+		tc.apiValue = `{"user": "foo", "port": 5432, "nested": {"a": "b"}}`
+		tc.expectedData["user"] = []byte("foo")
+		tc.expectedData["port"] = []byte("5432")
+		tc.expectedData["nested"] = []byte(`{"a": "b"}`)
+	}
+	setInvalidJSON := func(tc *dopplerTestCase) {
+		tc.apiValue = "not json"
+		tc.expectError = "unable to unmarshal secret API_KEY"
+	}
+
+	cases := []*dopplerTestCase{
+		makeValidDopplerTestCaseCustom(setJSON),
+		makeValidDopplerTestCaseCustom(setInvalidJSON),
+		makeValidDopplerTestCaseCustom(setAPIErr),
+		makeValidDopplerTestCaseCustom(setNilMockClient),
+	}
+
+	d := Doppler{}
+	for k, v := range cases {
+		d.client = v.mockClient
+		out, err := d.GetSecretMap(context.Background(), *v.ref)
+		if !ErrorContains(err, v.expectError) {
+			t.Errorf("[%d] unexpected error: %v, expected: '%s'", k, err, v.expectError)
+		}
+		if err == nil && !reflect.DeepEqual(out, v.expectedData) {
+			t.Errorf("[%d] unexpected secret data: expected %#v, got %#v", k, v.expectedData, out)
+		}
+	}
+}
+
+func TestDopplerGetAllSecrets(t *testing.T) {
+	secrets := map[string]string{
+		"DB_USER":     "admin",
+		"DB_PASSWORD": "pass",
+		"API_KEY":     "key",
+	}
+	setWholeConfig := func(tc *dopplerTestCase) {
+		tc.apiSecrets = secrets
+		tc.expectedData = map[string][]byte{
+			"DB_USER":     []byte("admin"),
+			"DB_PASSWORD": []byte("pass"),
+			"API_KEY":     []byte("key"),
+		}
+	}
+	setFindByName := func(tc *dopplerTestCase) {
+		tc.apiSecrets = secrets
+		tc.refFind.Name = &esv1beta1.FindName{RegExp: "^DB_"}
+		tc.expectedData = map[string][]byte{
+			"DB_USER":     []byte("admin"),
+			"DB_PASSWORD": []byte("pass"),
+		}
+	}
+	setFindByTags := func(tc *dopplerTestCase) {
+		tc.refFind.Tags = map[string]string{"foo": "bar"}
+		tc.expectError = errFindNotImplemented
+	}
+
+	cases := []*dopplerTestCase{
+		makeValidDopplerTestCaseCustom(setWholeConfig),
+		makeValidDopplerTestCaseCustom(setFindByName),
+		makeValidDopplerTestCaseCustom(setFindByTags),
+		makeValidDopplerTestCaseCustom(setAPIErr),
+		makeValidDopplerTestCaseCustom(setNilMockClient),
+	}
+
+	d := Doppler{}
+	for k, v := range cases {
+		d.client = v.mockClient
+		out, err := d.GetAllSecrets(context.Background(), *v.refFind)
+		if !ErrorContains(err, v.expectError) {
+			t.Errorf("[%d] unexpected error: %v, expected: '%s'", k, err, v.expectError)
+		}
+		if err == nil && !reflect.DeepEqual(out, v.expectedData) {
+			t.Errorf("[%d] unexpected secret data: expected %#v, got %#v", k, v.expectedData, out)
+		}
+	}
+}
+
+func TestAPIClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer dp.st.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"messages":["Invalid Auth token"],"success":false}`)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("project") != "backend" || q.Get("config") != "prd" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v3/configs/config/secret":
+			if q.Get("name") != "API_KEY" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"messages":["Could not find requested secret"],"success":false}`)
+				return
+			}
+			fmt.Fprint(w, `{"name":"API_KEY","value":{"raw":"${OTHER}","computed":"resolved"}}`)
+		case "/v3/configs/config/secrets/download":
+			fmt.Fprint(w, `{"API_KEY":"resolved","DB_USER":"admin"}`)
+		}
+	}))
+	defer srv.Close()
+
+	c := newAPIClient(srv.URL+"/", "dp.st.token")
+	val, err := c.GetSecret(context.Background(), "backend", "prd", "API_KEY")
+	if err != nil || val != "resolved" {
+		t.Errorf("unexpected secret: %q, %v", val, err)
+	}
+	_, err = c.GetSecret(context.Background(), "backend", "prd", "MISSING")
+	if !errors.Is(err, esv1beta1.NoSecretErr) {
+		t.Errorf("expected NoSecretErr, got %v", err)
+	}
+	all, err := c.GetSecrets(context.Background(), "backend", "prd")
+	if err != nil || !reflect.DeepEqual(all, map[string]string{"API_KEY": "resolved", "DB_USER": "admin"}) {
+		t.Errorf("unexpected secrets: %#v, %v", all, err)
+	}
+	c.token = "invalid"
+	_, err = c.GetSecrets(context.Background(), "backend", "prd")
+	if !ErrorContains(err, "doppler api returned status 401: Invalid Auth token") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "doppler-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("dp.st.token\n")},
+	}).Build()
+	store := makeStore("doppler-token", "token", nil)
+
+	client, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := client.(*Doppler)
+	if d.project != "backend" || d.config != "prd" || d.client.(*apiClient).token != "dp.st.token" {
+		t.Errorf("unexpected client: %#v", d)
+	}
+
+	store = makeStore("doppler-token", "missing", nil)
+	_, err = (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if !ErrorContains(err, "missing dopplerToken in secret doppler-token") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	tests := map[string]struct {
+		store       esv1beta1.GenericStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("doppler-token", "token", nil),
+		},
+		"missing name": {
+			store:       makeStore("", "token", nil),
+			expectError: errMissingTokenName,
+		},
+		"missing key": {
+			store:       makeStore("doppler-token", "", nil),
+			expectError: errMissingTokenKey,
+		},
+		"namespace not allowed": {
+			store:       makeStore("doppler-token", "token", pointer.StringPtr("foo")),
+			expectError: "namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func makeStore(name, key string, namespace *string) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Doppler: &esv1beta1.DopplerProvider{
+					Project: "backend",
+					Config:  "prd",
+					Auth: esv1beta1.DopplerAuth{
+						SecretRef: esv1beta1.DopplerAuthSecretRef{
+							DopplerToken: esmeta.SecretKeySelector{
+								Name:      name,
+								Key:       key,
+								Namespace: namespace,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
@@ -0,0 +1,50 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+)
+
+type DopplerMockClient struct {
+	getSecret  func(ctx context.Context, project, config, name string) (string, error)
+	getSecrets func(ctx context.Context, project, config string) (map[string]string, error)
+}
+
+func (mc *DopplerMockClient) GetSecret(ctx context.Context, project, config, name string) (string, error) {
+	return mc.getSecret(ctx, project, config, name)
+}
+
+func (mc *DopplerMockClient) GetSecrets(ctx context.Context, project, config string) (map[string]string, error) {
+	return mc.getSecrets(ctx, project, config)
+}
+
+// WithValue configures the client to return val for all secret reads.
+func (mc *DopplerMockClient) WithValue(val string, err error) {
+	if mc != nil {
+		mc.getSecret = func(ctx context.Context, project, config, name string) (string, error) {
+			return val, err
+		}
+	}
+}
+
+// WithSecrets configures the client to return secrets when a whole config is downloaded.
+func (mc *DopplerMockClient) WithSecrets(secrets map[string]string, err error) {
+	if mc != nil {
+		mc.getSecrets = func(ctx context.Context, project, config string) (map[string]string, error) {
+			return secrets, err
+		}
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	errAPIRequest  = "etcd api request failed: %w"
+	errAPIResponse = "etcd api returned status %d: %s"
+	errAPIDecode   = "unable to decode etcd api response: %w"
+	errLogin       = "unable to authenticate with etcd: %w"
+)
+
+// keyValue is an etcd key. Keys and values are base64 encoded by the gateway.
+type keyValue struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value"`
+	ModRevision int64  `json:"mod_revision,string,omitempty"`
+}
+
+type rangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+	Revision int64  `json:"revision,string,omitempty"`
+}
+
+type rangeResponse struct {
+	Kvs []keyValue `json:"kvs"`
+}
+
+type authenticateRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type authenticateResponse struct {
+	Token string `json:"token"`
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// apiClient talks to the JSON gateway of the etcd v3 API.
+// see: https://etcd.io/docs/v3.5/dev-guide/api_grpc_gateway/
+type apiClient struct {
+	endpoints []string
+	token     string
+	http      *http.Client
+}
+
+func newAPIClient(endpoints []string, tlsConfig *tls.Config) *apiClient {
+	trimmed := make([]string, len(endpoints))
+	for i, ep := range endpoints {
+		trimmed[i] = strings.TrimSuffix(ep, "/")
+	}
+	return &apiClient{
+		endpoints: trimmed,
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}
+}
+
+// authenticate requests a token for the given etcd user.
+func (c *apiClient) authenticate(ctx context.Context, username, password string) error {
+	var out authenticateResponse
+	if err := c.post(ctx, "/v3/auth/authenticate", authenticateRequest{Name: username, Password: password}, &out); err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	c.token = out.Token
+	return nil
+}
+
+// get returns the key at the given revision, or at the latest revision if revision is 0.
+func (c *apiClient) get(ctx context.Context, key string, revision int64) (*keyValue, error) {
+	var out rangeResponse
+	if err := c.post(ctx, "/v3/kv/range", rangeRequest{Key: []byte(key), Revision: revision}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Kvs) == 0 {
+		return nil, nil
+	}
+	return &out.Kvs[0], nil
+}
+
+// list returns all keys starting with prefix.
+func (c *apiClient) list(ctx context.Context, prefix string) ([]keyValue, error) {
+	key := []byte(prefix)
+	if prefix == "" {
+		// the empty key is invalid, \0 is the smallest key.
+		key = []byte{0}
+	}
+	var out rangeResponse
+	if err := c.post(ctx, "/v3/kv/range", rangeRequest{Key: key, RangeEnd: prefixRangeEnd(prefix)}, &out); err != nil {
+		return nil, err
+	}
+	return out.Kvs, nil
+}
+
+// prefixRangeEnd returns the smallest key greater than all keys starting with prefix.
+// An empty prefix selects all keys.
+func prefixRangeEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return []byte{0}
+}
+
+// post sends the request to the endpoints in order until one responds.
+func (c *apiClient) post(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	var lastErr error
+	for _, ep := range c.endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf(errAPIRequest, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.token != "" {
+			req.Header.Set("Authorization", c.token)
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf(errAPIRequest, err)
+			}
+			// try the next member
+			lastErr = err
+			continue
+		}
+		return decodeResponse(resp, out)
+	}
+	return fmt.Errorf(errAPIRequest, lastErr)
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		msg := errResp.Message
+		if msg == "" {
+			msg = errResp.Error
+		}
+		return fmt.Errorf(errAPIResponse, resp.StatusCode, msg)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf(errAPIDecode, err)
+	}
+	return nil
+}
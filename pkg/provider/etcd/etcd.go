@@ -0,0 +1,302 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errMissingStoreSpec    = "missing store provider etcd"
+	errMissingEndpoints    = "missing endpoints"
+	errInvalidEndpoint     = "invalid endpoint %s: must be an absolute http or https url"
+	errMissingRefName      = "missing name in auth.%s"
+	errMissingRefKey       = "missing key in auth.%s"
+	errInvalidRef          = "invalid auth.%s: %w"
+	errFetchCredentials    = "could not fetch credentials secret %s: %w"
+	errMissingCredentials  = "missing %s in secret %s"
+	errInvalidCABundle     = "failed to append caBundle"
+	errInvalidClientCert   = "invalid client certificate: %w"
+	errUninitalizedClient  = "provider etcd is not initialized"
+	errInvalidRevision     = "invalid version %s: must be a revision number"
+	errPropertyNotFound    = "property %s does not exist in key %s"
+	errJSONSecretUnmarshal = "unable to unmarshal key %s: %w"
+	errFindNotImplemented  = "find by tags is not supported by etcd"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Etcd reads keys of an etcd v3 cluster.
+type Etcd struct {
+	client *apiClient
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Etcd: &esv1beta1.EtcdProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.EtcdProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Etcd == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Etcd, nil
+}
+
+// NewClient constructs an etcd client using the client certificate
+// and user credentials referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(provider.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(provider.CABundle) {
+			return nil, fmt.Errorf(errInvalidCABundle)
+		}
+	}
+	auth := provider.Auth
+	if auth == nil {
+		auth = &esv1beta1.EtcdAuth{}
+	}
+	if auth.Cert != nil {
+		cert, err := secretKeyRef(ctx, kube, storeKind, namespace, "cert.clientCert", auth.Cert.ClientCert)
+		if err != nil {
+			return nil, err
+		}
+		key, err := secretKeyRef(ctx, kube, storeKind, namespace, "cert.clientKey", auth.Cert.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf(errInvalidClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	client := newAPIClient(provider.Endpoints, tlsConfig)
+	if auth.SecretRef != nil {
+		username, err := secretKeyRef(ctx, kube, storeKind, namespace, "secretRef.username", auth.SecretRef.Username)
+		if err != nil {
+			return nil, err
+		}
+		password, err := secretKeyRef(ctx, kube, storeKind, namespace, "secretRef.password", auth.SecretRef.Password)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.authenticate(ctx, strings.TrimSpace(username), password); err != nil {
+			return nil, err
+		}
+	}
+	return &Etcd{client: client}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := string(secret.Data[ref.Key])
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if len(provider.Endpoints) == 0 {
+		return fmt.Errorf(errMissingEndpoints)
+	}
+	for _, ep := range provider.Endpoints {
+		if u, err := url.Parse(ep); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf(errInvalidEndpoint, ep)
+		}
+	}
+	if provider.Auth == nil {
+		return nil
+	}
+	if cert := provider.Auth.Cert; cert != nil {
+		if err := validateSecretRef(store, "cert.clientCert", cert.ClientCert); err != nil {
+			return err
+		}
+		if err := validateSecretRef(store, "cert.clientKey", cert.ClientKey); err != nil {
+			return err
+		}
+	}
+	if ref := provider.Auth.SecretRef; ref != nil {
+		if err := validateSecretRef(store, "secretRef.username", ref.Username); err != nil {
+			return err
+		}
+		return validateSecretRef(store, "secretRef.password", ref.Password)
+	}
+	return nil
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidRef, name, err)
+	}
+	return nil
+}
+
+// GetSecret returns the value of the key ref.Key. ref.Version selects a revision
+// of the key, ref.Property takes a gjson expression on a JSON encoded value.
+func (e *Etcd) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if e.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	revision, err := parseRevision(ref.Version)
+	if err != nil {
+		return nil, err
+	}
+	kv, err := e.client.get(ctx, ref.Key, revision)
+	if err != nil {
+		return nil, err
+	}
+	if kv == nil {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if ref.Property == "" {
+		return kv.Value, nil
+	}
+	val, ok := utils.GetProperty(kv.Value, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the key/value pairs of a key holding a JSON object.
+func (e *Etcd) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := e.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns all keys starting with the prefix ref.Path whose names match ref.Name.
+func (e *Etcd) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if e.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	var prefix string
+	if ref.Path != nil {
+		prefix = *ref.Path
+	}
+	kvs, err := e.client.list(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte, len(kvs))
+	for _, kv := range kvs {
+		key := string(kv.Key)
+		if matcher != nil && !matcher.MatchName(key) {
+			continue
+		}
+		secretData[key] = kv.Value
+	}
+	return secretData, nil
+}
+
+func (e *Etcd) Close(ctx context.Context) error {
+	return nil
+}
+
+func (e *Etcd) Validate() error {
+	return nil
+}
+
+// ValidateVersion accepts a revision number.
+func (e *Etcd) ValidateVersion(version string) error {
+	_, err := parseRevision(version)
+	return err
+}
+
+// parseRevision returns the revision of version, 0 selects the latest revision.
+func parseRevision(version string) (int64, error) {
+	if version == "" {
+		return 0, nil
+	}
+	rev, err := strconv.ParseInt(version, 10, 64)
+	if err != nil || rev < 1 {
+		return 0, fmt.Errorf(errInvalidRevision, version)
+	}
+	return rev, nil
+}
@@ -0,0 +1,302 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// testKeys holds the revisions of each key, the last one is the latest.
+var testKeys = map[string][]keyValue{
+	"/app/db": {
+		{Key: []byte("/app/db"), Value: []byte(`{"user":"admin","password":"old"}`), ModRevision: 2},
+		{Key: []byte("/app/db"), Value: []byte(`{"user":"admin","password":"hunter2"}`), ModRevision: 5},
+	},
+	"/app/token": {{Key: []byte("/app/token"), Value: []byte("s3cr3t"), ModRevision: 3}},
+	"/apps/x":    {{Key: []byte("/apps/x"), Value: []byte("x"), ModRevision: 4}},
+	"/other":     {{Key: []byte("/other"), Value: []byte("other"), ModRevision: 1}},
+}
+
+func newFakeEtcd(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/auth/authenticate":
+			var req authenticateRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Name != "eso" || req.Password != "hunter2" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"etcdserver: authentication failed, invalid user ID or password","code":3,"message":"etcdserver: authentication failed, invalid user ID or password"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"header":{},"token":"token.1"}`))
+		case "/v3/kv/range":
+			if r.Header.Get("Authorization") != "token.1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"etcdserver: user name is empty","code":16,"message":"etcdserver: user name is empty"}`))
+				return
+			}
+			var req rangeRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			out := rangeResponse{}
+			for key, revisions := range testKeys {
+				inRange := key == string(req.Key)
+				if req.RangeEnd != nil {
+					// a range end of \0 selects all keys
+					inRange = key >= string(req.Key) && (key < string(req.RangeEnd) || bytes.Equal(req.RangeEnd, []byte{0}))
+				}
+				if !inRange {
+					continue
+				}
+				for i := len(revisions) - 1; i >= 0; i-- {
+					if req.Revision == 0 || revisions[i].ModRevision <= req.Revision {
+						out.Kvs = append(out.Kvs, revisions[i])
+						break
+					}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func makeStore(endpoints []string, auth *esv1beta1.EtcdAuth) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Etcd: &esv1beta1.EtcdProvider{
+					Endpoints: endpoints,
+					Auth:      auth,
+				},
+			},
+		},
+	}
+}
+
+func passwordAuth() *esv1beta1.EtcdAuth {
+	return &esv1beta1.EtcdAuth{
+		SecretRef: &esv1beta1.EtcdAuthSecretRef{
+			Username: esmeta.SecretKeySelector{Name: "etcd", Key: "username"},
+			Password: esmeta.SecretKeySelector{Name: "etcd", Key: "password"},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, password string) (esv1beta1.SecretsClient, error) {
+	srv := newFakeEtcd(t)
+	// the first member is down
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd", Namespace: "default"},
+		Data: map[string][]byte{
+			"username": []byte("eso"),
+			"password": []byte(password),
+		},
+	}).Build()
+	store := makeStore([]string{down.URL, srv.URL + "/"}, passwordAuth())
+	return (&Provider{}).NewClient(context.Background(), store, kube, "default")
+}
+
+func TestEtcdGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/token"},
+			want: "s3cr3t",
+		},
+		"property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/db", Property: "password"},
+			want: "hunter2",
+		},
+		"revision": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/db", Property: "password", Version: "4"},
+			want: "old",
+		},
+		"invalid revision": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/db", Version: "latest"},
+			expectError: "invalid version latest: must be a revision number",
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/db", Property: "host"},
+			expectError: "property host does not exist in key /app/db",
+		},
+		"missing key": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c, err := newTestClient(t, "hunter2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestEtcdGetSecretMap(t *testing.T) {
+	c, err := newTestClient(t, "hunter2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "/app/db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{"user": []byte("admin"), "password": []byte("hunter2")}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+}
+
+func TestEtcdGetAllSecrets(t *testing.T) {
+	c, err := newTestClient(t, "hunter2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("/app/")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"/app/db":    []byte(`{"user":"admin","password":"hunter2"}`),
+		"/app/token": []byte("s3cr3t"),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secrets: expected %#v, got %#v", want, out)
+	}
+	out, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^/apps?/t"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(out, map[string][]byte{"/app/token": []byte("s3cr3t")}) {
+		t.Errorf("unexpected secrets: %#v", out)
+	}
+}
+
+func TestEtcdAuthenticationFailure(t *testing.T) {
+	_, err := newTestClient(t, "wrong")
+	if !ErrorContains(err, "unable to authenticate with etcd: etcd api returned status 400: etcdserver: authentication failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPrefixRangeEnd(t *testing.T) {
+	tests := map[string][]byte{
+		"/app/":    []byte("/app0"),
+		"a\xff":    []byte("b"),
+		"":         {0},
+		"\xff\xff": {0},
+	}
+	for prefix, want := range tests {
+		if got := prefixRangeEnd(prefix); !bytes.Equal(got, want) {
+			t.Errorf("prefixRangeEnd(%q): expected %q, got %q", prefix, want, got)
+		}
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	endpoints := []string{"https://etcd-0.example.com:2379"}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid without auth": {
+			store: makeStore(endpoints, nil),
+		},
+		"valid with password and certificate": {
+			store: makeStore(endpoints, &esv1beta1.EtcdAuth{
+				Cert: &esv1beta1.CertAuth{
+					ClientCert: esmeta.SecretKeySelector{Name: "etcd", Key: "tls.crt"},
+					ClientKey:  esmeta.SecretKeySelector{Name: "etcd", Key: "tls.key"},
+				},
+				SecretRef: passwordAuth().SecretRef,
+			}),
+		},
+		"missing endpoints": {
+			store:       makeStore(nil, nil),
+			expectError: errMissingEndpoints,
+		},
+		"invalid endpoint": {
+			store:       makeStore([]string{"etcd-0:2379"}, nil),
+			expectError: "invalid endpoint etcd-0:2379",
+		},
+		"missing client key": {
+			store: makeStore(endpoints, &esv1beta1.EtcdAuth{
+				Cert: &esv1beta1.CertAuth{
+					ClientCert: esmeta.SecretKeySelector{Name: "etcd", Key: "tls.crt"},
+				},
+			}),
+			expectError: "missing name in auth.cert.clientKey",
+		},
+		"namespace not allowed": {
+			store: makeStore(endpoints, &esv1beta1.EtcdAuth{
+				SecretRef: &esv1beta1.EtcdAuthSecretRef{
+					Username: esmeta.SecretKeySelector{Name: "etcd", Key: "username", Namespace: pointer.StringPtr("foo")},
+					Password: esmeta.SecretKeySelector{Name: "etcd", Key: "password"},
+				},
+			}),
+			expectError: "invalid auth.secretRef.username: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
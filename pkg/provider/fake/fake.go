@@ -17,10 +17,13 @@ package fake
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 var (
@@ -28,6 +31,8 @@ var (
 	errMissingFakeProvider = fmt.Errorf("missing store provider fake")
 	errMissingKeyField     = "key must be set in data %v"
 	errMissingValueField   = "at least one of value or valueMap must be set in data %v"
+	errPropertyNotFound    = "property %s does not exist in key %s"
+	errFindTagsUnsupported = fmt.Errorf("find by tags is not supported by the fake provider")
 )
 
 type Provider struct {
@@ -55,18 +60,52 @@ func getProvider(store esv1beta1.GenericStore) (*esv1beta1.FakeProvider, error)
 	return spc.Provider.Fake, nil
 }
 
-// Empty GetAllSecrets.
+// GetAllSecrets returns the unversioned values whose keys match ref.Name and start with ref.Path.
 func (p *Provider) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	// TO be implemented
-	return nil, fmt.Errorf("GetAllSecrets not implemented")
+	if len(ref.Tags) > 0 {
+		return nil, errFindTagsUnsupported
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	secrets := make(map[string][]byte)
+	for _, data := range p.config.Data {
+		if data.Version != "" || data.Value == "" {
+			continue
+		}
+		if ref.Path != nil && !strings.HasPrefix(data.Key, *ref.Path) {
+			continue
+		}
+		if matcher != nil && !matcher.MatchName(data.Key) {
+			continue
+		}
+		secrets[data.Key] = []byte(data.Value)
+	}
+	return secrets, nil
 }
 
 // GetSecret returns a single secret from the provider.
+// If ref.Property is set it is looked up in valueMap, or as gjson path in value.
 func (p *Provider) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	for _, data := range p.config.Data {
-		if data.Key == ref.Key && data.Version == ref.Version {
+		if data.Key != ref.Key || data.Version != ref.Version {
+			continue
+		}
+		if ref.Property == "" {
 			return []byte(data.Value), nil
 		}
+		if val, ok := data.ValueMap[ref.Property]; ok {
+			return []byte(val), nil
+		}
+		if val, ok := utils.GetProperty([]byte(data.Value), ref.Property); ok {
+			return val, nil
+		}
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
 	}
 	return nil, esv1beta1.NoSecretErr
 }
@@ -98,6 +137,11 @@ func (p *Provider) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts any version, it must match the version of the data.
+func (p *Provider) ValidateVersion(version string) error {
+	return nil
+}
+
 func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 	prov := store.GetSpec().Provider.Fake
 	if prov == nil {
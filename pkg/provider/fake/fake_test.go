@@ -121,6 +121,48 @@ func TestGetSecret(t *testing.T) {
 			},
 			expValue: "bar2",
 		},
+		{
+			name: "get property from valueMap",
+			input: []esv1beta1.FakeProviderData{
+				{
+					Key:      "/foo",
+					ValueMap: map[string]string{"user": "admin"},
+				},
+			},
+			request: esv1beta1.ExternalSecretDataRemoteRef{
+				Key:      "/foo",
+				Property: "user",
+			},
+			expValue: "admin",
+		},
+		{
+			name: "get nested property from json value",
+			input: []esv1beta1.FakeProviderData{
+				{
+					Key:   "/foo",
+					Value: `{"db":{"user":"admin"}}`,
+				},
+			},
+			request: esv1beta1.ExternalSecretDataRemoteRef{
+				Key:      "/foo",
+				Property: "db.user",
+			},
+			expValue: "admin",
+		},
+		{
+			name: "return err when property not found",
+			input: []esv1beta1.FakeProviderData{
+				{
+					Key:   "/foo",
+					Value: "bar",
+				},
+			},
+			request: esv1beta1.ExternalSecretDataRemoteRef{
+				Key:      "/foo",
+				Property: "user",
+			},
+			expErr: fmt.Sprintf(errPropertyNotFound, "user", "/foo"),
+		},
 	}
 
 	for _, row := range tbl {
@@ -226,3 +268,44 @@ func TestGetSecretMap(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAllSecrets(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	p := &Provider{}
+	cl, err := p.NewClient(context.Background(), &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Fake: &esv1beta1.FakeProvider{
+					Data: []esv1beta1.FakeProviderData{
+						{Key: "/app/user", Value: "admin"},
+						{Key: "/app/password", Value: "hunter2"},
+						{Key: "/app/password", Value: "old", Version: "v1"},
+						{Key: "/other/user", Value: "root"},
+						{Key: "/app/config", ValueMap: map[string]string{"foo": "bar"}},
+					},
+				},
+			},
+		},
+	}, nil, "")
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+	out, err := cl.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Name: &esv1beta1.FindName{RegExp: "user$"},
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(out).To(gomega.Equal(map[string][]byte{
+		"/app/user":   []byte("admin"),
+		"/other/user": []byte("root"),
+	}))
+
+	path := "/app/"
+	out, err = cl.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: &path})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(out).To(gomega.Equal(map[string][]byte{
+		"/app/user":     []byte("admin"),
+		"/app/password": []byte("hunter2"),
+	}))
+
+	_, err = cl.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Tags: map[string]string{"foo": "bar"}})
+	gomega.Expect(err).To(gomega.MatchError(errFindTagsUnsupported))
+}
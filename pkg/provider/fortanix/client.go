@@ -0,0 +1,155 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fortanix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/external-secrets/external-secrets/pkg/provider/internal/jsonapi"
+)
+
+const (
+	defaultAPIURL = "https://apps.smartkey.io"
+	// listPageSize is the number of security objects requested per page.
+	listPageSize = 100
+
+	errLogin = "unable to login to fortanix: %w"
+
+	keyOpExport = "EXPORT"
+)
+
+// securityObject is a Fortanix DSM security object.
+// Value holds the base64 encoded key material and is only set on export.
+type securityObject struct {
+	Kid            string            `json:"kid"`
+	Name           string            `json:"name"`
+	ObjType        string            `json:"obj_type"`
+	KeyOps         []string          `json:"key_ops"`
+	Enabled        bool              `json:"enabled"`
+	CustomMetadata map[string]string `json:"custom_metadata"`
+	Value          []byte            `json:"value"`
+}
+
+func (o *securityObject) exportable() bool {
+	for _, op := range o.KeyOps {
+		if op == keyOpExport {
+			return true
+		}
+	}
+	return false
+}
+
+type sobjectDescriptor struct {
+	Name string `json:"name"`
+}
+
+type authResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// apiClient talks to the Fortanix DSM REST API with a session of an application.
+// see: https://www.fortanix.com/fortanix-restful-api-references/dsm
+type apiClient struct {
+	baseURL string
+	api     *jsonapi.Client
+	token   string
+}
+
+// newAPIClient opens a session with the API key of an application.
+func newAPIClient(ctx context.Context, apiURL, apiKey string) (*apiClient, error) {
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	c := &apiClient{
+		baseURL: strings.TrimSuffix(apiURL, "/"),
+		// errors are returned as plain text.
+		api: &jsonapi.Client{
+			HTTP: &http.Client{Timeout: 30 * time.Second},
+			Name: "fortanix api",
+		},
+	}
+	req, err := c.api.NewRequest(ctx, http.MethodPost, c.baseURL+"/sys/v1/session/auth", nil)
+	if err != nil {
+		return nil, fmt.Errorf(errLogin, err)
+	}
+	// the api key already is the base64 encoded app id and secret.
+	req.Header.Set("Authorization", "Basic "+apiKey)
+	var out authResponse
+	if err := c.api.Do(req, &out); err != nil {
+		return nil, fmt.Errorf(errLogin, err)
+	}
+	c.token = out.AccessToken
+	return c, nil
+}
+
+// exportObject returns the security object with the given name including its value.
+func (c *apiClient) exportObject(ctx context.Context, name string) (*securityObject, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/crypto/v1/keys/export", sobjectDescriptor{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	var out securityObject
+	if err := c.api.ReadSecret(req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// listObjects returns all security objects the application has access to, without values.
+func (c *apiClient) listObjects(ctx context.Context) ([]securityObject, error) {
+	var objects []securityObject
+	for offset := 0; ; offset += listPageSize {
+		params := url.Values{}
+		params.Set("limit", strconv.Itoa(listPageSize))
+		params.Set("offset", strconv.Itoa(offset))
+		req, err := c.newRequest(ctx, http.MethodGet, "/crypto/v1/keys?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []securityObject
+		if err := c.api.Do(req, &page); err != nil {
+			return nil, err
+		}
+		objects = append(objects, page...)
+		if len(page) < listPageSize {
+			return objects, nil
+		}
+	}
+}
+
+// terminate ends the session.
+func (c *apiClient) terminate(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodPost, "/sys/v1/session/terminate", nil)
+	if err != nil {
+		return err
+	}
+	return c.api.Do(req, nil)
+}
+
+// newRequest returns a request of path in the session with body encoded as JSON.
+func (c *apiClient) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	req, err := c.api.NewRequest(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	return req, nil
+}
@@ -0,0 +1,222 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fortanix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errMissingStoreSpec    = "missing store provider fortanix"
+	errInvalidAPIURL       = "invalid apiUrl %s: must be an absolute https url"
+	errMissingAPIKeyName   = "missing auth.secretRef.apiKey.name"
+	errMissingAPIKeyKey    = "missing auth.secretRef.apiKey.key"
+	errInvalidAPIKeyRef    = "invalid auth.secretRef.apiKey: %w"
+	errFetchAPIKeySecret   = "could not fetch apiKey secret: %w"
+	errMissingAPIKey       = "missing apiKey in secret %s"
+	errUninitalizedClient  = "provider fortanix is not initialized"
+	errPropertyNotFound    = "property %s does not exist in security object %s"
+	errJSONSecretUnmarshal = "unable to unmarshal security object %s: %w"
+	errFindPathUnsupported = "find by path is not supported by fortanix"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Fortanix reads the security objects an application has access to.
+type Fortanix struct {
+	client *apiClient
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Fortanix: &esv1beta1.FortanixProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.FortanixProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Fortanix == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Fortanix, nil
+}
+
+// NewClient opens a Fortanix DSM session using the api key referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	keyRef := provider.Auth.SecretRef.APIKey
+	objectKey := types.NamespacedName{
+		Name:      keyRef.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind && keyRef.Namespace != nil {
+		objectKey.Namespace = *keyRef.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errFetchAPIKeySecret, err)
+	}
+	apiKey := strings.TrimSpace(string(secret.Data[keyRef.Key]))
+	if apiKey == "" {
+		return nil, fmt.Errorf(errMissingAPIKey, keyRef.Name)
+	}
+	client, err := newAPIClient(ctx, provider.APIURL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Fortanix{client: client}, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.APIURL != "" {
+		if u, err := url.Parse(provider.APIURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf(errInvalidAPIURL, provider.APIURL)
+		}
+	}
+	keyRef := provider.Auth.SecretRef.APIKey
+	if keyRef.Name == "" {
+		return fmt.Errorf(errMissingAPIKeyName)
+	}
+	if keyRef.Key == "" {
+		return fmt.Errorf(errMissingAPIKeyKey)
+	}
+	if err := utils.ValidateSecretSelector(store, keyRef); err != nil {
+		return fmt.Errorf(errInvalidAPIKeyRef, err)
+	}
+	return nil
+}
+
+// GetSecret exports the security object named ref.Key and returns its key material.
+// ref.Property takes a gjson expression on a JSON encoded value.
+func (f *Fortanix) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if f.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	obj, err := f.client.exportObject(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return obj.Value, nil
+	}
+	val, ok := utils.GetProperty(obj.Value, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the key/value pairs of a security object holding a JSON object.
+func (f *Fortanix) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := f.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets exports all enabled and exportable security objects whose names match ref.Name.
+// ref.Tags are matched against the custom metadata of the security objects.
+func (f *Fortanix) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if f.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if ref.Path != nil {
+		return nil, fmt.Errorf(errFindPathUnsupported)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	objects, err := f.client.listObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string][]byte)
+	for i := range objects {
+		obj := &objects[i]
+		if !obj.Enabled || !obj.exportable() || !matchTags(obj.CustomMetadata, ref.Tags) {
+			continue
+		}
+		if matcher != nil && !matcher.MatchName(obj.Name) {
+			continue
+		}
+		exported, err := f.client.exportObject(ctx, obj.Name)
+		if err != nil {
+			return nil, err
+		}
+		secrets[obj.Name] = exported.Value
+	}
+	return secrets, nil
+}
+
+func matchTags(metadata, tags map[string]string) bool {
+	for k, v := range tags {
+		if metadata[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Close terminates the Fortanix DSM session.
+func (f *Fortanix) Close(ctx context.Context) error {
+	if f.client == nil {
+		return nil
+	}
+	return f.client.terminate(ctx)
+}
+
+func (f *Fortanix) Validate() error {
+	return nil
+}
@@ -0,0 +1,293 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fortanix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+var testObjects = []securityObject{
+	{Kid: "1", Name: "db", ObjType: "SECRET", KeyOps: []string{"EXPORT", "APPMANAGEABLE"}, Enabled: true,
+		CustomMetadata: map[string]string{"team": "backend"}, Value: []byte(`{"user":"admin","password":"hunter2","port":5432}`)},
+	{Kid: "2", Name: "api-token", ObjType: "OPAQUE", KeyOps: []string{"EXPORT"}, Enabled: true, Value: []byte("s3cr3t")},
+	{Kid: "3", Name: "signing-key", ObjType: "RSA", KeyOps: []string{"SIGN", "VERIFY"}, Enabled: true},
+	{Kid: "4", Name: "disabled", ObjType: "SECRET", KeyOps: []string{"EXPORT"}, Value: []byte("old")},
+}
+
+func newFakeFortanix(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sys/v1/session/auth" {
+			if r.Header.Get("Authorization") != "Basic apikey" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, "invalid api key")
+				return
+			}
+			fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":600}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/crypto/v1/keys":
+			list := make([]securityObject, len(testObjects))
+			for i, obj := range testObjects {
+				obj.Value = nil
+				list[i] = obj
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		case "/crypto/v1/keys/export":
+			var desc sobjectDescriptor
+			_ = json.NewDecoder(r.Body).Decode(&desc)
+			for _, obj := range testObjects {
+				if obj.Name != desc.Name {
+					continue
+				}
+				if !obj.exportable() {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, "operation not allowed for this security object")
+					return
+				}
+				_ = json.NewEncoder(w).Encode(obj)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "sobject does not exist")
+		case "/sys/v1/session/terminate":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func makeStore(apiURL string, apiKey esmeta.SecretKeySelector) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Fortanix: &esv1beta1.FortanixProvider{
+					APIURL: apiURL,
+					Auth: esv1beta1.FortanixAuth{
+						SecretRef: esv1beta1.FortanixAuthSecretRef{
+							APIKey: apiKey,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, apiKey string) (esv1beta1.SecretsClient, error) {
+	srv := newFakeFortanix(t)
+	t.Cleanup(srv.Close)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "fortanix", Namespace: "default"},
+		Data: map[string][]byte{
+			"apiKey": []byte(apiKey),
+		},
+	}).Build()
+	store := makeStore(srv.URL, esmeta.SecretKeySelector{Name: "fortanix", Key: "apiKey"})
+	return (&Provider{}).NewClient(context.Background(), store, kube, "default")
+}
+
+func TestFortanixGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"opaque value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "api-token"},
+			want: "s3cr3t",
+		},
+		"json value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db"},
+			want: `{"user":"admin","password":"hunter2","port":5432}`,
+		},
+		"property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "password"},
+			want: "hunter2",
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "host"},
+			expectError: "property host does not exist in security object db",
+		},
+		"not exportable": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "signing-key"},
+			expectError: "fortanix api returned status 400: operation not allowed for this security object",
+		},
+		"missing security object": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c, err := newTestClient(t, "apikey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestFortanixGetSecretMap(t *testing.T) {
+	c, err := newTestClient(t, "apikey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"user":     []byte("admin"),
+		"password": []byte("hunter2"),
+		"port":     []byte("5432"),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+	_, err = c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "api-token"})
+	if !ErrorContains(err, "unable to unmarshal security object api-token") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFortanixGetAllSecrets(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		expectError string
+	}{
+		"all exportable": {
+			ref: esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: ".*"}},
+			want: map[string][]byte{
+				"db":        []byte(`{"user":"admin","password":"hunter2","port":5432}`),
+				"api-token": []byte("s3cr3t"),
+			},
+		},
+		"by name": {
+			ref:  esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^api"}},
+			want: map[string][]byte{"api-token": []byte("s3cr3t")},
+		},
+		"by custom metadata": {
+			ref:  esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "backend"}},
+			want: map[string][]byte{"db": []byte(`{"user":"admin","password":"hunter2","port":5432}`)},
+		},
+		"path": {
+			ref:         esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("/app")},
+			expectError: errFindPathUnsupported,
+		},
+	}
+	c, err := newTestClient(t, "apikey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secrets: expected %#v, got %#v", tc.want, out)
+			}
+		})
+	}
+	if err := c.Close(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFortanixLoginFailure(t *testing.T) {
+	_, err := newTestClient(t, "wrong")
+	if !ErrorContains(err, "unable to login to fortanix: fortanix api returned status 401: invalid api key") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	apiKey := esmeta.SecretKeySelector{Name: "fortanix", Key: "apiKey"}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("", apiKey),
+		},
+		"valid api url": {
+			store: makeStore("https://eu.smartkey.io", apiKey),
+		},
+		"invalid api url": {
+			store:       makeStore("eu.smartkey.io", apiKey),
+			expectError: "invalid apiUrl eu.smartkey.io",
+		},
+		"missing api key name": {
+			store:       makeStore("", esmeta.SecretKeySelector{Key: "apiKey"}),
+			expectError: errMissingAPIKeyName,
+		},
+		"missing api key key": {
+			store:       makeStore("", esmeta.SecretKeySelector{Name: "fortanix"}),
+			expectError: errMissingAPIKeyKey,
+		},
+		"namespace not allowed": {
+			store:       makeStore("", esmeta.SecretKeySelector{Name: "fortanix", Key: "apiKey", Namespace: pointer.StringPtr("foo")}),
+			expectError: "invalid auth.secretRef.apiKey: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
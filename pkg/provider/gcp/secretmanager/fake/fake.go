@@ -17,10 +17,13 @@ import (
 	"context"
 	"fmt"
 
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	grpc "github.com/googleapis/gax-go/v2"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MockSMClient struct {
@@ -32,6 +35,14 @@ func (mc *MockSMClient) AccessSecretVersion(ctx context.Context, req *secretmana
 	return mc.accessSecretFn(ctx, req)
 }
 
+func (mc *MockSMClient) GetSecret(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...grpc.CallOption) (*secretmanagerpb.Secret, error) {
+	return nil, status.Error(codes.NotFound, "secret not found")
+}
+
+func (mc *MockSMClient) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...grpc.CallOption) *secretmanager.SecretIterator {
+	return nil
+}
+
 func (mc *MockSMClient) Close() error {
 	return mc.closeFn()
 }
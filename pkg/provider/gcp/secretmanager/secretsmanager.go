@@ -16,22 +16,29 @@ package secretmanager
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"github.com/googleapis/gax-go/v2"
-	"github.com/tidwall/gjson"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
@@ -51,7 +58,11 @@ const (
 	errUnableCreateGCPSMClient                = "failed to create GCP secretmanager client: %w"
 	errUninitalizedGCPProvider                = "provider GCP is not initialized"
 	errClientGetSecretAccess                  = "unable to access Secret from SecretManager Client: %w"
+	errClientPermissionDenied                 = "permission denied accessing Secret %s: %w"
 	errJSONSecretUnmarshal                    = "unable to unmarshal secret: %w"
+	errClientListSecrets                      = "unable to list secrets: %w"
+	errFindByPath                             = "find by path is not supported by GCP Secret Manager"
+	errInitWorkloadIdentity                   = "unable to initialize workload identity: %w"
 
 	errInvalidStore         = "invalid store"
 	errInvalidStoreSpec     = "invalid store spec"
@@ -63,6 +74,8 @@ const (
 
 type GoogleSecretManagerClient interface {
 	AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
+	GetSecret(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error)
+	ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator
 	Close() error
 }
 
@@ -103,6 +116,40 @@ func (c *gClient) getTokenSource(ctx context.Context, store esv1beta1.GenericSto
 	return google.DefaultTokenSource(ctx, CloudPlatformRole)
 }
 
+// NewTokenSource returns a token source for a generator in namespace.
+// It uses the same authentication mechanisms as the provider, the referenced
+// secrets and service accounts must exist in namespace.
+func NewTokenSource(ctx context.Context, auth esv1beta1.GCPSMAuth, projectID string, kube kclient.Client, namespace string) (oauth2.TokenSource, error) {
+	store := &esv1beta1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				GCPSM: &esv1beta1.GCPSMProvider{
+					Auth:      auth,
+					ProjectID: projectID,
+				},
+			},
+		},
+	}
+	wi, err := newWorkloadIdentity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(errInitWorkloadIdentity, err)
+	}
+	// the token source of workload identity holds a static token,
+	// so the IAM client isn't needed after it was created.
+	defer func() {
+		_ = wi.Close()
+	}()
+	c := &gClient{
+		kube:             kube,
+		store:            store.Spec.Provider.GCPSM,
+		namespace:        namespace,
+		storeKind:        esv1beta1.SecretStoreKind,
+		workloadIdentity: wi,
+	}
+	return c.getTokenSource(ctx, store, kube, namespace)
+}
+
 func (c *gClient) Close() error {
 	return c.workloadIdentity.Close()
 }
@@ -201,29 +248,77 @@ func (sm *ProviderGCP) NewClient(ctx context.Context, store esv1beta1.GenericSto
 	return sm, nil
 }
 
-// Empty GetAllSecrets.
+// GetAllSecrets returns the latest versions of all secrets that carry the labels ref.Tags
+// and whose names match ref.Name.
 func (sm *ProviderGCP) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	// TO be implemented
-	return nil, fmt.Errorf("GetAllSecrets not implemented")
+	if utils.IsNil(sm.SecretManagerClient) || sm.projectID == "" {
+		return nil, fmt.Errorf(errUninitalizedGCPProvider)
+	}
+	if ref.Path != nil {
+		return nil, fmt.Errorf(errFindByPath)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	filters := make([]string, 0, len(ref.Tags))
+	for k, v := range ref.Tags {
+		filters = append(filters, fmt.Sprintf("labels.%s=%s", k, v))
+	}
+	// map iteration order is random, a stable filter is easier to debug.
+	sort.Strings(filters)
+
+	it := sm.SecretManagerClient.ListSecrets(ctx, &secretmanagerpb.ListSecretsRequest{
+		Parent: fmt.Sprintf("projects/%s", sm.projectID),
+		Filter: strings.Join(filters, " AND "),
+	})
+	data := make(map[string][]byte)
+	for {
+		secret, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(errClientListSecrets, err)
+		}
+		// secret names have the form projects/<project>/secrets/<name>
+		name := secret.Name[strings.LastIndex(secret.Name, "/")+1:]
+		if matcher != nil && !matcher.MatchName(name) {
+			continue
+		}
+		result, err := sm.SecretManagerClient.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+			Name: fmt.Sprintf("%s/versions/%s", secret.Name, defaultVersion),
+		})
+		if err != nil {
+			return nil, handleAccessError(secret.Name, err)
+		}
+		data[name] = result.Payload.Data
+	}
+	return utils.ConvertKeys(ref.ConversionStrategy, data)
 }
 
 // GetSecret returns a single secret from the provider.
+// ref.Version is a version number, latest or a version alias of the secret.
 func (sm *ProviderGCP) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if utils.IsNil(sm.SecretManagerClient) || sm.projectID == "" {
 		return nil, fmt.Errorf(errUninitalizedGCPProvider)
 	}
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return sm.getSecretMetadata(ctx, ref)
+	}
 
 	version := ref.Version
 	if version == "" {
 		version = defaultVersion
 	}
 
-	req := &secretmanagerpb.AccessSecretVersionRequest{
-		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/%s", sm.projectID, ref.Key, version),
-	}
-	result, err := sm.SecretManagerClient.AccessSecretVersion(ctx, req)
+	result, err := sm.accessSecretVersion(ctx, ref.Key, version)
 	if err != nil {
-		return nil, fmt.Errorf(errClientGetSecretAccess, err)
+		return nil, err
 	}
 
 	if ref.Property == "" {
@@ -237,20 +332,85 @@ func (sm *ProviderGCP) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecr
 	if result.Payload.Data != nil {
 		payload = string(result.Payload.Data)
 	}
-	idx := strings.Index(ref.Property, ".")
-	refProperty := ref.Property
-	if idx > 0 {
-		refProperty = strings.ReplaceAll(refProperty, ".", "\\.")
-		val := gjson.Get(payload, refProperty)
-		if val.Exists() {
-			return []byte(val.String()), nil
-		}
-	}
-	val := gjson.Get(payload, ref.Property)
-	if !val.Exists() {
+	val, ok := utils.GetProperty([]byte(payload), ref.Property)
+	if !ok {
 		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
-	return []byte(val.String()), nil
+	return val, nil
+}
+
+// getSecretMetadata returns the labels of the secret as json-encoded value,
+// or the value of the label ref.Property.
+func (sm *ProviderGCP) getSecretMetadata(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secretName := fmt.Sprintf("projects/%s/secrets/%s", sm.projectID, ref.Key)
+	secret, err := sm.SecretManagerClient.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: secretName})
+	if err != nil {
+		return nil, handleAccessError(secretName, err)
+	}
+	labels := secret.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	if ref.Property == "" {
+		return json.Marshal(labels)
+	}
+	val, ok := labels[ref.Property]
+	if !ok {
+		return nil, fmt.Errorf("label %s does not exist in secret %s", ref.Property, ref.Key)
+	}
+	return []byte(val), nil
+}
+
+// accessSecretVersion accesses a version of the secret key. Versions that are neither
+// a number nor latest are looked up in the version aliases of the secret, if the API
+// does not resolve them.
+func (sm *ProviderGCP) accessSecretVersion(ctx context.Context, key, version string) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	secretName := fmt.Sprintf("projects/%s/secrets/%s", sm.projectID, key)
+	req := &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("%s/versions/%s", secretName, version),
+	}
+	result, err := sm.SecretManagerClient.AccessSecretVersion(ctx, req)
+	if err == nil {
+		return result, nil
+	}
+	if !isVersionAlias(version) || (status.Code(err) != codes.NotFound && status.Code(err) != codes.InvalidArgument) {
+		return nil, handleAccessError(req.Name, err)
+	}
+	secret, serr := sm.SecretManagerClient.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: secretName})
+	if serr != nil {
+		return nil, handleAccessError(req.Name, serr)
+	}
+	number, ok := secret.VersionAliases[version]
+	if !ok {
+		return nil, handleAccessError(req.Name, err)
+	}
+	req.Name = fmt.Sprintf("%s/versions/%d", secretName, number)
+	result, err = sm.SecretManagerClient.AccessSecretVersion(ctx, req)
+	if err != nil {
+		return nil, handleAccessError(req.Name, err)
+	}
+	return result, nil
+}
+
+func isVersionAlias(version string) bool {
+	if version == defaultVersion {
+		return false
+	}
+	_, err := strconv.ParseInt(version, 10, 64)
+	return err != nil
+}
+
+// handleAccessError distinguishes a missing secret from missing permissions
+// so that both cases show up differently in the ExternalSecret status.
+func handleAccessError(name string, err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return esv1beta1.NoSecretErr
+	case codes.PermissionDenied:
+		return fmt.Errorf(errClientPermissionDenied, name, err)
+	default:
+		return fmt.Errorf(errClientGetSecretAccess, err)
+	}
 }
 
 // GetSecretMap returns multiple k/v pairs from the provider.
@@ -300,6 +460,11 @@ func (sm *ProviderGCP) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts a version number, latest or a version alias.
+func (sm *ProviderGCP) ValidateVersion(version string) error {
+	return nil
+}
+
 func (sm *ProviderGCP) ValidateStore(store esv1beta1.GenericStore) error {
 	if store == nil {
 		return fmt.Errorf(errInvalidStore)
@@ -16,11 +16,18 @@ package secretmanager
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
 	"strings"
 	"testing"
 
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"google.golang.org/api/option"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
@@ -95,6 +102,18 @@ var setAPIErr = func(smtc *secretManagerTestCase) {
 	smtc.expectError = "oh no"
 }
 
+// bad case: secret does not exist.
+var setNotFoundErr = func(smtc *secretManagerTestCase) {
+	smtc.apiErr = status.Error(codes.NotFound, "secret not found")
+	smtc.expectError = esv1beta1.NoSecretErr.Error()
+}
+
+// bad case: missing permissions to access the secret.
+var setPermissionDeniedErr = func(smtc *secretManagerTestCase) {
+	smtc.apiErr = status.Error(codes.PermissionDenied, "missing secretmanager.versions.access")
+	smtc.expectError = "permission denied accessing Secret projects/default/secrets//baz/versions/default"
+}
+
 var setNilMockClient = func(smtc *secretManagerTestCase) {
 	smtc.mockClient = nil
 	smtc.expectError = errUninitalizedGCPProvider
@@ -162,6 +181,8 @@ func TestSecretManagerGetSecret(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(setSecretString),
 		makeValidSecretManagerTestCaseCustom(setCustomVersion),
 		makeValidSecretManagerTestCaseCustom(setAPIErr),
+		makeValidSecretManagerTestCaseCustom(setNotFoundErr),
+		makeValidSecretManagerTestCaseCustom(setPermissionDeniedErr),
 		makeValidSecretManagerTestCaseCustom(setCustomRef),
 		makeValidSecretManagerTestCaseCustom(setDotRef),
 		makeValidSecretManagerTestCaseCustom(setNilMockClient),
@@ -181,6 +202,200 @@ func TestSecretManagerGetSecret(t *testing.T) {
 	}
 }
 
+// fakeServer serves the Secret Manager API for tests that need a real client, e.g. to iterate over ListSecrets.
+type fakeServer struct {
+	secretmanagerpb.UnimplementedSecretManagerServiceServer
+	secrets  []*secretmanagerpb.Secret
+	versions map[string][]byte
+	filter   string
+}
+
+func (f *fakeServer) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest) (*secretmanagerpb.ListSecretsResponse, error) {
+	f.filter = req.Filter
+	return &secretmanagerpb.ListSecretsResponse{Secrets: f.secrets}, nil
+}
+
+func (f *fakeServer) GetSecret(ctx context.Context, req *secretmanagerpb.GetSecretRequest) (*secretmanagerpb.Secret, error) {
+	for _, secret := range f.secrets {
+		if secret.Name == req.Name {
+			return secret, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "secret not found")
+}
+
+func (f *fakeServer) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	data, ok := f.versions[req.Name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "version not found")
+	}
+	return &secretmanagerpb.AccessSecretVersionResponse{
+		Name:    req.Name,
+		Payload: &secretmanagerpb.SecretPayload{Data: data},
+	}, nil
+}
+
+func newFakeServerClient(t *testing.T, srv *fakeServer) *secretmanager.Client {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gsrv := grpc.NewServer()
+	secretmanagerpb.RegisterSecretManagerServiceServer(gsrv, srv)
+	go func() {
+		_ = gsrv.Serve(lis)
+	}()
+	t.Cleanup(gsrv.Stop)
+	client, err := secretmanager.NewClient(context.Background(),
+		option.WithEndpoint(lis.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		secrets: []*secretmanagerpb.Secret{
+			{
+				Name:           "projects/default/secrets/payments-db",
+				Labels:         map[string]string{"team": "payments"},
+				VersionAliases: map[string]int64{"stable": 1},
+			},
+			{
+				Name:   "projects/default/secrets/payments-api",
+				Labels: map[string]string{"team": "payments"},
+			},
+		},
+		versions: map[string][]byte{
+			"projects/default/secrets/payments-db/versions/1":       []byte("db-v1"),
+			"projects/default/secrets/payments-db/versions/latest":  []byte("db-v2"),
+			"projects/default/secrets/payments-api/versions/latest": []byte("api"),
+		},
+	}
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	srv := newFakeServer()
+	sm := ProviderGCP{
+		projectID:           "default",
+		SecretManagerClient: newFakeServerClient(t, srv),
+	}
+
+	out, err := sm.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Tags: map[string]string{"team": "payments", "env": "prod"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if srv.filter != "labels.env=prod AND labels.team=payments" {
+		t.Errorf("unexpected filter: %s", srv.filter)
+	}
+	expected := map[string][]byte{
+		"payments-db":  []byte("db-v2"),
+		"payments-api": []byte("api"),
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("unexpected secrets: expected %q, got %q", expected, out)
+	}
+
+	out, err = sm.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Name: &esv1beta1.FindName{RegExp: "-db$"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(out, map[string][]byte{"payments-db": []byte("db-v2")}) {
+		t.Errorf("unexpected secrets: %q", out)
+	}
+
+	_, err = sm.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("payments")})
+	if !ErrorContains(err, errFindByPath) {
+		t.Errorf("unexpected error: %v, expected: '%s'", err, errFindByPath)
+	}
+}
+
+func TestGetSecretVersionAlias(t *testing.T) {
+	sm := ProviderGCP{
+		projectID:           "default",
+		SecretManagerClient: newFakeServerClient(t, newFakeServer()),
+	}
+	tests := map[string]struct {
+		ref            esv1beta1.ExternalSecretDataRemoteRef
+		expectError    string
+		expectedSecret string
+	}{
+		"alias": {
+			ref:            esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", Version: "stable"},
+			expectedSecret: "db-v1",
+		},
+		"unknown alias": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", Version: "canary"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+		"latest": {
+			ref:            esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db"},
+			expectedSecret: "db-v2",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := sm.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Fatalf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.expectedSecret {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.expectedSecret, string(out))
+			}
+		})
+	}
+}
+
+func TestGetSecretMetadata(t *testing.T) {
+	sm := ProviderGCP{
+		projectID:           "default",
+		SecretManagerClient: newFakeServerClient(t, newFakeServer()),
+	}
+	tests := map[string]struct {
+		ref            esv1beta1.ExternalSecretDataRemoteRef
+		expectError    string
+		expectedSecret string
+	}{
+		"all labels": {
+			ref:            esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectedSecret: `{"team":"payments"}`,
+		},
+		"single label": {
+			ref:            esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", Property: "team", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectedSecret: "payments",
+		},
+		"missing label": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-db", Property: "env", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectError: "label env does not exist in secret payments-db",
+		},
+		"missing secret": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "payments-web", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := sm.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Fatalf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.expectedSecret {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.expectedSecret, string(out))
+			}
+		})
+	}
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	setDeserialization := func(smtc *secretManagerTestCase) {
@@ -0,0 +1,204 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	errAPIRequest       = "github api request failed: %w"
+	errAPIResponse      = "github api returned status %d: %s"
+	errAPIDecode        = "unable to decode github api response: %w"
+	errInvalidPublicKey = "invalid public key %s"
+	errEncryptSecret    = "unable to encrypt secret: %w"
+	errSignAppJWT       = "unable to sign github app token: %w"
+	apiVersion          = "2022-11-28"
+)
+
+type publicKey struct {
+	KeyID string `json:"key_id"`
+	Key   string `json:"key"`
+}
+
+type secretRequest struct {
+	EncryptedValue string `json:"encrypted_value"`
+	KeyID          string `json:"key_id"`
+	Visibility     string `json:"visibility,omitempty"`
+}
+
+// apiClient writes Actions secrets of a repository or an organization.
+// see: https://docs.github.com/en/rest/actions/secrets
+type apiClient struct {
+	baseURL string
+	token   string
+	// secretsPath is either repos/<owner>/<repo> or orgs/<org>.
+	secretsPath string
+	visibility  string
+	http        *http.Client
+	publicKey   *publicKey
+}
+
+func newAPIClient(baseURL, token, secretsPath, visibility string) *apiClient {
+	return &apiClient{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		token:       token,
+		secretsPath: secretsPath,
+		visibility:  visibility,
+		http:        &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// installationToken exchanges a JWT signed with the private key of a GitHub App
+// for an access token of one of its installations.
+// see: https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/authenticating-as-a-github-app-installation
+func installationToken(ctx context.Context, baseURL string, appID, installationID int64, privateKey []byte) (string, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+	if err != nil {
+		return "", fmt.Errorf(errSignAppJWT, err)
+	}
+	now := time.Now()
+	appToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		// backdated to allow for clock drift.
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		Issuer:    strconv.FormatInt(appID, 10),
+	}).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf(errSignAppJWT, err)
+	}
+	c := newAPIClient(baseURL, appToken, "", "")
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/app/installations/%d/access_tokens", installationID), nil, &out); err != nil {
+		return "", err
+	}
+	return out.Token, nil
+}
+
+// putSecret encrypts value with the public key of the repository or organization and writes it to the secret name.
+func (c *apiClient) putSecret(ctx context.Context, name string, value []byte) error {
+	if c.publicKey == nil {
+		var key publicKey
+		if err := c.do(ctx, http.MethodGet, "/"+c.secretsPath+"/actions/secrets/public-key", nil, &key); err != nil {
+			return err
+		}
+		c.publicKey = &key
+	}
+	encrypted, err := sealSecret(c.publicKey, value)
+	if err != nil {
+		return err
+	}
+	body := secretRequest{
+		EncryptedValue: encrypted,
+		KeyID:          c.publicKey.KeyID,
+		Visibility:     c.visibility,
+	}
+	return c.do(ctx, http.MethodPut, "/"+c.secretsPath+"/actions/secrets/"+url.PathEscape(name), body, nil)
+}
+
+// deleteSecret deletes the secret name, a missing secret is not an error.
+func (c *apiClient) deleteSecret(ctx context.Context, name string) error {
+	err := c.do(ctx, http.MethodDelete, "/"+c.secretsPath+"/actions/secrets/"+url.PathEscape(name), nil, nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.statusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// apiError is returned for unsuccessful responses of the API.
+type apiError struct {
+	statusCode int
+	message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf(errAPIResponse, e.statusCode, e.message)
+}
+
+// sealSecret encrypts value with a libsodium sealed box as expected by the API.
+func sealSecret(key *publicKey, value []byte) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(key.Key)
+	if err != nil || len(raw) != 32 {
+		return "", fmt.Errorf(errInvalidPublicKey, key.KeyID)
+	}
+	var recipient [32]byte
+	copy(recipient[:], raw)
+	sealed, err := box.SealAnonymous(nil, value, &recipient, rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf(errEncryptSecret, err)
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader = http.NoBody
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf(errAPIRequest, err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(msg, &apiErr); err == nil && apiErr.Message != "" {
+			msg = []byte(apiErr.Message)
+		}
+		return &apiError{statusCode: resp.StatusCode, message: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf(errAPIDecode, err)
+	}
+	return nil
+}
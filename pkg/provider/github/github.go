@@ -0,0 +1,237 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultURL        = "https://api.github.com"
+	defaultVisibility = "private"
+	// maxSecretSize is the size limit of Actions secrets.
+	maxSecretSize = 48 * 1024
+
+	errMissingStoreSpec   = "missing store provider github"
+	errInvalidURL         = "invalid url %s: must be an absolute https url"
+	errMissingOwner       = "missing owner"
+	errInvalidVisibility  = "invalid visibility %s: must be all or private"
+	errVisibilityRepo     = "visibility can only be set for organization secrets"
+	errMissingAuth        = "missing auth: one of token or app is required"
+	errMultipleAuth       = "only one of auth.token or auth.app can be set"
+	errMissingAppID       = "missing auth.app.appId or auth.app.installationId"
+	errMissingRefName     = "missing name in auth.%s"
+	errMissingRefKey      = "missing key in auth.%s"
+	errInvalidRef         = "invalid auth.%s: %w"
+	errFetchCredentials   = "could not fetch credentials secret %s: %w"
+	errMissingCredentials = "missing %s in secret %s"
+	errUninitalizedClient = "provider github is not initialized"
+	errWriteOnly          = "github actions secrets are write-only and can not be read"
+	errInvalidSecretName  = "invalid secret name %s: must contain only alphanumeric characters or underscores, must not start with a number or GITHUB_"
+	errSecretTooLarge     = "secret %s is larger than 48 KB"
+)
+
+var secretNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Github writes Actions secrets of a repository or an organization.
+type Github struct {
+	client *apiClient
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Github: &esv1beta1.GithubProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.GithubProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Github == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Github, nil
+}
+
+// NewClient constructs a GitHub client with a personal access token or
+// an installation token of the GitHub App referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := provider.URL
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	var token string
+	switch {
+	case provider.Auth.Token != nil:
+		token, err = secretKeyRef(ctx, kube, storeKind, namespace, "token", *provider.Auth.Token)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(token)
+	case provider.Auth.App != nil:
+		app := provider.Auth.App
+		privateKey, err := secretKeyRef(ctx, kube, storeKind, namespace, "app.privateKey", app.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		token, err = installationToken(ctx, baseURL, app.AppID, app.InstallationID, []byte(privateKey))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(errMissingAuth)
+	}
+	secretsPath := "orgs/" + url.PathEscape(provider.Owner)
+	visibility := provider.Visibility
+	if provider.Repository != "" {
+		secretsPath = "repos/" + url.PathEscape(provider.Owner) + "/" + url.PathEscape(provider.Repository)
+	} else if visibility == "" {
+		visibility = defaultVisibility
+	}
+	return &Github{
+		client: newAPIClient(baseURL, token, secretsPath, visibility),
+	}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := string(secret.Data[ref.Key])
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.URL != "" {
+		if u, err := url.Parse(provider.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf(errInvalidURL, provider.URL)
+		}
+	}
+	if provider.Owner == "" {
+		return fmt.Errorf(errMissingOwner)
+	}
+	if provider.Visibility != "" {
+		if provider.Repository != "" {
+			return fmt.Errorf(errVisibilityRepo)
+		}
+		if provider.Visibility != "all" && provider.Visibility != "private" {
+			return fmt.Errorf(errInvalidVisibility, provider.Visibility)
+		}
+	}
+	auth := provider.Auth
+	switch {
+	case auth.Token != nil && auth.App != nil:
+		return fmt.Errorf(errMultipleAuth)
+	case auth.Token != nil:
+		return validateSecretRef(store, "token", *auth.Token)
+	case auth.App != nil:
+		if auth.App.AppID == 0 || auth.App.InstallationID == 0 {
+			return fmt.Errorf(errMissingAppID)
+		}
+		return validateSecretRef(store, "app.privateKey", auth.App.PrivateKey)
+	default:
+		return fmt.Errorf(errMissingAuth)
+	}
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidRef, name, err)
+	}
+	return nil
+}
+
+// PushSecret creates or updates the Actions secret remoteKey.
+func (g *Github) PushSecret(ctx context.Context, value []byte, remoteKey string) error {
+	if g.client == nil {
+		return fmt.Errorf(errUninitalizedClient)
+	}
+	if !secretNameRegexp.MatchString(remoteKey) || strings.HasPrefix(strings.ToUpper(remoteKey), "GITHUB_") {
+		return fmt.Errorf(errInvalidSecretName, remoteKey)
+	}
+	if len(value) > maxSecretSize {
+		return fmt.Errorf(errSecretTooLarge, remoteKey)
+	}
+	return g.client.putSecret(ctx, remoteKey, value)
+}
+
+// DeleteSecret deletes the Actions secret remoteKey.
+func (g *Github) DeleteSecret(ctx context.Context, remoteKey string) error {
+	if g.client == nil {
+		return fmt.Errorf(errUninitalizedClient)
+	}
+	return g.client.deleteSecret(ctx, remoteKey)
+}
+
+func (g *Github) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	return nil, fmt.Errorf(errWriteOnly)
+}
+
+func (g *Github) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	return nil, fmt.Errorf(errWriteOnly)
+}
+
+func (g *Github) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	return nil, fmt.Errorf(errWriteOnly)
+}
+
+func (g *Github) Close(ctx context.Context) error {
+	return nil
+}
+
+func (g *Github) Validate() error {
+	return nil
+}
@@ -0,0 +1,338 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/nacl/box"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	testToken        = "ghp_test"
+	testInstallToken = "ghs_installation"
+	testKeyID        = "568250167242549743"
+)
+
+type fakeGithub struct {
+	*httptest.Server
+	public, private *[32]byte
+	appKey          *rsa.PublicKey
+	// secrets holds the decrypted secrets by path.
+	secrets    map[string]string
+	visibility map[string]string
+}
+
+func newFakeGithub(t *testing.T, appKey *rsa.PublicKey) *fakeGithub {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeGithub{public: public, private: private, appKey: appKey, secrets: map[string]string{}, visibility: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		claims := jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &claims, func(*jwt.Token) (interface{}, error) {
+			return f.appKey, nil
+		})
+		if err != nil || r.Method != http.MethodPost || claims.Issuer != "1234" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"A JSON web token could not be decoded"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token":"` + testInstallToken + `","expires_at":"2030-01-01T00:00:00Z"}`))
+	})
+	secrets := func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if (auth != "Bearer "+testToken && auth != "Bearer "+testInstallToken) || r.Header.Get("X-GitHub-Api-Version") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/actions/secrets/public-key") {
+			w.Write([]byte(`{"key_id":"` + testKeyID + `","key":"` + base64.StdEncoding.EncodeToString(f.public[:]) + `"}`))
+			return
+		}
+		if r.Method == http.MethodDelete {
+			if _, ok := f.secrets[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"Not Found"}`))
+				return
+			}
+			delete(f.secrets, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		var req secretRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.Method != http.MethodPut || req.KeyID != testKeyID {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Invalid request"}`))
+			return
+		}
+		sealed, _ := base64.StdEncoding.DecodeString(req.EncryptedValue)
+		plain, ok := box.OpenAnonymous(nil, sealed, f.public, f.private)
+		if !ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		f.secrets[r.URL.Path] = string(plain)
+		f.visibility[r.URL.Path] = req.Visibility
+		w.WriteHeader(http.StatusCreated)
+	}
+	mux.HandleFunc("/repos/example/app/actions/secrets/", secrets)
+	mux.HandleFunc("/orgs/example/actions/secrets/", secrets)
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func makeStore(url, repository string, auth esv1beta1.GithubAuth) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Github: &esv1beta1.GithubProvider{
+					URL:        url,
+					Auth:       auth,
+					Owner:      "example",
+					Repository: repository,
+				},
+			},
+		},
+	}
+}
+
+func newKube(appKey []byte) *clientfake.ClientBuilder {
+	return clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "github", Namespace: "default"},
+		Data: map[string][]byte{
+			"token":       []byte(testToken + "\n"),
+			"private-key": appKey,
+		},
+	})
+}
+
+func pushSecret(t *testing.T, c esv1beta1.SecretsClient, value, remoteKey string) error {
+	pusher, ok := c.(esv1beta1.SecretsPusher)
+	if !ok {
+		t.Fatalf("client does not implement SecretsPusher")
+	}
+	return pusher.PushSecret(context.Background(), []byte(value), remoteKey)
+}
+
+func TestGithubPushRepositorySecret(t *testing.T) {
+	f := newFakeGithub(t, nil)
+	store := makeStore(f.URL, "app", esv1beta1.GithubAuth{Token: &esmeta.SecretKeySelector{Name: "github", Key: "token"}})
+	c, err := (&Provider{}).NewClient(context.Background(), store, newKube(nil).Build(), "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := map[string]struct {
+		remoteKey   string
+		value       string
+		expectError string
+	}{
+		"push": {
+			remoteKey: "DB_PASSWORD",
+			value:     "hunter2",
+		},
+		"invalid name": {
+			remoteKey:   "db-password",
+			expectError: "invalid secret name db-password",
+		},
+		"reserved prefix": {
+			remoteKey:   "github_token",
+			expectError: "invalid secret name github_token",
+		},
+		"too large": {
+			remoteKey:   "LARGE",
+			value:       strings.Repeat("x", maxSecretSize+1),
+			expectError: "secret LARGE is larger than 48 KB",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := pushSecret(t, c, tc.value, tc.remoteKey)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && f.secrets["/repos/example/app/actions/secrets/"+tc.remoteKey] != tc.value {
+				t.Errorf("unexpected secret: %v", f.secrets)
+			}
+		})
+	}
+	if v := f.visibility["/repos/example/app/actions/secrets/DB_PASSWORD"]; v != "" {
+		t.Errorf("unexpected visibility of repository secret: %s", v)
+	}
+	if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "DB_PASSWORD"}); !ErrorContains(err, errWriteOnly) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGithubDeleteSecret(t *testing.T) {
+	f := newFakeGithub(t, nil)
+	store := makeStore(f.URL, "app", esv1beta1.GithubAuth{Token: &esmeta.SecretKeySelector{Name: "github", Key: "token"}})
+	c, err := (&Provider{}).NewClient(context.Background(), store, newKube(nil).Build(), "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := pushSecret(t, c, "hunter2", "DB_PASSWORD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deleter, ok := c.(esv1beta1.SecretsDeleter)
+	if !ok {
+		t.Fatalf("client does not implement SecretsDeleter")
+	}
+	if err := deleter.DeleteSecret(context.Background(), "DB_PASSWORD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := f.secrets["/repos/example/app/actions/secrets/DB_PASSWORD"]; ok {
+		t.Errorf("secret was not deleted: %v", f.secrets)
+	}
+	// deleting a missing secret is not an error.
+	if err := deleter.DeleteSecret(context.Background(), "DB_PASSWORD"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGithubPushOrganizationSecretWithApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newFakeGithub(t, &key.PublicKey)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	store := makeStore(f.URL, "", esv1beta1.GithubAuth{App: &esv1beta1.GithubAppAuth{
+		AppID:          1234,
+		InstallationID: 42,
+		PrivateKey:     esmeta.SecretKeySelector{Name: "github", Key: "private-key"},
+	}})
+	c, err := (&Provider{}).NewClient(context.Background(), store, newKube(keyPEM).Build(), "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := pushSecret(t, c, "s3cr3t", "API_TOKEN"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := "/orgs/example/actions/secrets/API_TOKEN"
+	if f.secrets[path] != "s3cr3t" || f.visibility[path] != defaultVisibility {
+		t.Errorf("unexpected secret: %v %v", f.secrets, f.visibility)
+	}
+
+	store.Spec.Provider.Github.Auth.App.AppID = 1
+	_, err = (&Provider{}).NewClient(context.Background(), store, newKube(keyPEM).Build(), "default")
+	if !ErrorContains(err, "github api returned status 401: A JSON web token could not be decoded") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGithubBadCredentials(t *testing.T) {
+	f := newFakeGithub(t, nil)
+	c := &Github{client: newAPIClient(f.URL, "wrong", "repos/example/app", "")}
+	err := c.PushSecret(context.Background(), []byte("value"), "NAME")
+	if !ErrorContains(err, "github api returned status 401: Bad credentials") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	token := esv1beta1.GithubAuth{Token: &esmeta.SecretKeySelector{Name: "github", Key: "token"}}
+	app := esv1beta1.GithubAuth{App: &esv1beta1.GithubAppAuth{AppID: 1, InstallationID: 2, PrivateKey: esmeta.SecretKeySelector{Name: "github", Key: "private-key"}}}
+	repoVisibility := makeStore("", "app", token)
+	repoVisibility.Spec.Provider.Github.Visibility = "all"
+	invalidVisibility := makeStore("", "", token)
+	invalidVisibility.Spec.Provider.Github.Visibility = "selected"
+	missingOwner := makeStore("", "app", token)
+	missingOwner.Spec.Provider.Github.Owner = ""
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid token": {
+			store: makeStore("", "app", token),
+		},
+		"valid app": {
+			store: makeStore("https://github.example.com/api/v3", "", app),
+		},
+		"invalid url": {
+			store:       makeStore("http://github.example.com", "app", token),
+			expectError: "invalid url http://github.example.com",
+		},
+		"missing owner": {
+			store:       missingOwner,
+			expectError: errMissingOwner,
+		},
+		"visibility of repository secrets": {
+			store:       repoVisibility,
+			expectError: errVisibilityRepo,
+		},
+		"invalid visibility": {
+			store:       invalidVisibility,
+			expectError: "invalid visibility selected",
+		},
+		"missing auth": {
+			store:       makeStore("", "app", esv1beta1.GithubAuth{}),
+			expectError: errMissingAuth,
+		},
+		"multiple auth": {
+			store:       makeStore("", "app", esv1beta1.GithubAuth{Token: token.Token, App: app.App}),
+			expectError: errMultipleAuth,
+		},
+		"missing app id": {
+			store:       makeStore("", "app", esv1beta1.GithubAuth{App: &esv1beta1.GithubAppAuth{PrivateKey: app.App.PrivateKey}}),
+			expectError: errMissingAppID,
+		},
+		"namespace not allowed": {
+			store:       makeStore("", "app", esv1beta1.GithubAuth{Token: &esmeta.SecretKeySelector{Name: "github", Key: "token", Namespace: pointer.StringPtr("foo")}}),
+			expectError: "invalid auth.token: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
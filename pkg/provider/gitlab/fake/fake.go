@@ -14,20 +14,28 @@ limitations under the License.
 package fake
 
 import (
+	"fmt"
+	"net/http"
+
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type GitlabMockClient struct {
-	getVariable func(pid interface{}, key string, options ...gitlab.RequestOptionFunc) (*gitlab.ProjectVariable, *gitlab.Response, error)
+	getVariable func(pid interface{}, key string, opt *gitlab.GetProjectVariableOptions, options ...gitlab.RequestOptionFunc) (*gitlab.ProjectVariable, *gitlab.Response, error)
+	variables   []*gitlab.ProjectVariable
 }
 
 func (mc *GitlabMockClient) GetVariable(pid interface{}, key string, opt *gitlab.GetProjectVariableOptions, options ...gitlab.RequestOptionFunc) (*gitlab.ProjectVariable, *gitlab.Response, error) {
-	return mc.getVariable(pid, key, nil)
+	return mc.getVariable(pid, key, opt)
+}
+
+func (mc *GitlabMockClient) ListVariables(pid interface{}, opt *gitlab.ListProjectVariablesOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.ProjectVariable, *gitlab.Response, error) {
+	return mc.variables, &gitlab.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
 }
 
 func (mc *GitlabMockClient) WithValue(projectIDinput, keyInput string, output *gitlab.ProjectVariable, err error) {
 	if mc != nil {
-		mc.getVariable = func(pid interface{}, key string, options ...gitlab.RequestOptionFunc) (*gitlab.ProjectVariable, *gitlab.Response, error) {
+		mc.getVariable = func(pid interface{}, key string, opt *gitlab.GetProjectVariableOptions, options ...gitlab.RequestOptionFunc) (*gitlab.ProjectVariable, *gitlab.Response, error) {
 			// type secretmanagerpb.AccessSecretVersionRequest contains unexported fields
 			// use cmpopts.IgnoreUnexported to ignore all the unexported fields in the cmp.
 			// if !cmp.Equal(paramReq, input, cmpopts.IgnoreUnexported(gitlab.ProjectVariable{})) {
@@ -37,3 +45,47 @@ func (mc *GitlabMockClient) WithValue(projectIDinput, keyInput string, output *g
 		}
 	}
 }
+
+// WithVariables serves the variables by key and environment scope.
+// Like the GitLab API it responds with 404 if no variable matches.
+func (mc *GitlabMockClient) WithVariables(variables ...*gitlab.ProjectVariable) {
+	if mc != nil {
+		mc.variables = variables
+		mc.getVariable = func(pid interface{}, key string, opt *gitlab.GetProjectVariableOptions, options ...gitlab.RequestOptionFunc) (*gitlab.ProjectVariable, *gitlab.Response, error) {
+			for _, variable := range variables {
+				if variable.Key != key {
+					continue
+				}
+				if opt != nil && opt.Filter != nil && opt.Filter.EnvironmentScope != variable.EnvironmentScope {
+					continue
+				}
+				return variable, &gitlab.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+			}
+			return nil, notFound(), fmt.Errorf("404 Variable Not Found")
+		}
+	}
+}
+
+type GitlabMockGroupsClient struct {
+	variables map[string][]*gitlab.GroupVariable
+}
+
+func (mc *GitlabMockGroupsClient) ListVariables(gid interface{}, opt *gitlab.ListGroupVariablesOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.GroupVariable, *gitlab.Response, error) {
+	variables, ok := mc.variables[fmt.Sprint(gid)]
+	if !ok {
+		return nil, notFound(), fmt.Errorf("404 Group Not Found")
+	}
+	return variables, &gitlab.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+}
+
+// WithVariables sets the variables of the group gid.
+func (mc *GitlabMockGroupsClient) WithVariables(gid string, variables ...*gitlab.GroupVariable) {
+	if mc.variables == nil {
+		mc.variables = make(map[string][]*gitlab.GroupVariable)
+	}
+	mc.variables[gid] = variables
+}
+
+func notFound() *gitlab.Response {
+	return &gitlab.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+}
@@ -16,10 +16,11 @@ package gitlab
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
-	"github.com/tidwall/gjson"
 	gitlab "github.com/xanzy/go-gitlab"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -27,6 +28,7 @@ import (
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	"github.com/external-secrets/external-secrets/e2e/framework/log"
+	"github.com/external-secrets/external-secrets/pkg/find"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
@@ -39,16 +41,29 @@ const (
 	errMissingSAK                             = "missing credentials while setting auth"
 	errUninitalizedGitlabProvider             = "provider gitlab is not initialized"
 	errJSONSecretUnmarshal                    = "unable to unmarshal secret: %w"
+	errFindByTags                             = "find by tags is not supported by gitlab"
+	errFindByPath                             = "find by path is not supported by gitlab"
 )
 
+// wildcardScope is the environment scope of variables that apply to all environments.
+const wildcardScope = "*"
+
 type Client interface {
 	GetVariable(pid interface{}, key string, opt *gitlab.GetProjectVariableOptions, options ...gitlab.RequestOptionFunc) (*gitlab.ProjectVariable, *gitlab.Response, error)
+	ListVariables(pid interface{}, opt *gitlab.ListProjectVariablesOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.ProjectVariable, *gitlab.Response, error)
+}
+
+type GroupsClient interface {
+	ListVariables(gid interface{}, opt *gitlab.ListGroupVariablesOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.GroupVariable, *gitlab.Response, error)
 }
 
 // Gitlab Provider struct with reference to a GitLab client and a projectID.
 type Gitlab struct {
-	client    Client
-	projectID interface{}
+	client       Client
+	groupsClient GroupsClient
+	projectID    string
+	groupIDs     []string
+	environment  string
 }
 
 // Client for interacting with kubernetes cluster...?
@@ -141,15 +156,62 @@ func (g *Gitlab) NewClient(ctx context.Context, store esv1beta1.GenericStore, ku
 	}
 
 	g.client = gitlabClient.ProjectVariables
+	g.groupsClient = gitlabClient.GroupVariables
 	g.projectID = cliStore.store.ProjectID
+	g.groupIDs = cliStore.store.GroupIDs
+	g.environment = cliStore.store.Environment
 
 	return g, nil
 }
 
-// Empty GetAllSecrets.
+// GetAllSecrets returns the variables of the project and its groups whose key matches ref.Name.
+// Like GetSecret, project variables take precedence over group variables.
 func (g *Gitlab) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	// TO be implemented
-	return nil, fmt.Errorf("GetAllSecrets not implemented")
+	if utils.IsNil(g.client) {
+		return nil, fmt.Errorf(errUninitalizedGitlabProvider)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindByTags)
+	}
+	if ref.Path != nil {
+		return nil, fmt.Errorf(errFindByPath)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	variables := make(map[string]string)
+	// walk the groups backwards, so earlier groups override later ones.
+	for i := len(g.groupIDs) - 1; i >= 0; i-- {
+		groupVariables, err := g.groupVariables(g.groupIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range groupVariables {
+			variables[k] = v
+		}
+	}
+	if g.projectID != "" || len(g.groupIDs) == 0 {
+		projectVariables, err := g.projectVariables()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range projectVariables {
+			variables[k] = v
+		}
+	}
+	data := make(map[string][]byte)
+	for k, v := range variables {
+		if matcher != nil && !matcher.MatchName(k) {
+			continue
+		}
+		data[k] = []byte(v)
+	}
+	return utils.ConvertKeys(ref.ConversionStrategy, data)
 }
 
 func (g *Gitlab) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
@@ -158,35 +220,170 @@ func (g *Gitlab) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretData
 	}
 	// Need to replace hyphens with underscores to work with Gitlab API
 	ref.Key = strings.ReplaceAll(ref.Key, "-", "_")
-	// Retrieves a gitlab variable in the form
-	// {
-	// 	"key": "TEST_VARIABLE_1",
-	// 	"variable_type": "env_var",
-	// 	"value": "TEST_1",
-	// 	"protected": false,
-	// 	"masked": true
-	data, _, err := g.client.GetVariable(g.projectID, ref.Key, nil) // Optional 'filter' parameter could be added later
+	payload, err := g.getVariableValue(ref.Key)
 	if err != nil {
 		return nil, err
 	}
 
 	if ref.Property == "" {
-		if data.Value != "" {
-			return []byte(data.Value), nil
+		if payload != "" {
+			return []byte(payload), nil
 		}
 		return nil, fmt.Errorf("invalid secret received. no secret string for key: %s", ref.Key)
 	}
 
-	var payload string
-	if data.Value != "" {
-		payload = data.Value
+	val, ok := utils.GetProperty([]byte(payload), ref.Property)
+	if !ok {
+		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
+	return val, nil
+}
 
-	val := gjson.Get(payload, ref.Property)
-	if !val.Exists() {
-		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
+// getVariableValue returns the value of the variable key of the project.
+// Variables the project does not define are looked up in the groups, in order.
+func (g *Gitlab) getVariableValue(key string) (string, error) {
+	if g.projectID != "" || len(g.groupIDs) == 0 {
+		data, err := g.getProjectVariable(key)
+		if err == nil {
+			return data.Value, nil
+		}
+		if !errors.Is(err, esv1beta1.NoSecretErr) {
+			return "", err
+		}
+	}
+	for _, groupID := range g.groupIDs {
+		value, err := g.getGroupVariable(groupID, key)
+		if err == nil {
+			return value, nil
+		}
+		if !errors.Is(err, esv1beta1.NoSecretErr) {
+			return "", err
+		}
+	}
+	return "", esv1beta1.NoSecretErr
+}
+
+// getProjectVariable retrieves a project variable in the form
+// {
+// 	"key": "TEST_VARIABLE_1",
+// 	"variable_type": "env_var",
+// 	"value": "TEST_1",
+// 	"protected": false,
+// 	"masked": true,
+// 	"environment_scope": "*"
+// }
+// A variable of the environment scope takes precedence over one of the wildcard scope.
+func (g *Gitlab) getProjectVariable(key string) (*gitlab.ProjectVariable, error) {
+	var opts *gitlab.GetProjectVariableOptions
+	if g.environment != "" {
+		opts = &gitlab.GetProjectVariableOptions{
+			Filter: &gitlab.VariableFilter{EnvironmentScope: g.environment},
+		}
+	}
+	data, resp, err := g.client.GetVariable(g.projectID, key, opts)
+	if isNotFound(resp) && g.environment != "" && g.environment != wildcardScope {
+		opts.Filter.EnvironmentScope = wildcardScope
+		data, resp, err = g.client.GetVariable(g.projectID, key, opts)
 	}
-	return []byte(val.String()), nil
+	if isNotFound(resp) {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// getGroupVariable retrieves the variable key of a group.
+func (g *Gitlab) getGroupVariable(groupID, key string) (string, error) {
+	variables, err := g.groupVariables(groupID)
+	if err != nil {
+		return "", err
+	}
+	value, ok := variables[key]
+	if !ok {
+		return "", esv1beta1.NoSecretErr
+	}
+	return value, nil
+}
+
+// projectVariables lists the variables of the project visible to the environment by key.
+func (g *Gitlab) projectVariables() (map[string]string, error) {
+	scoped := newScopedVariables(g.environment)
+	opts := &gitlab.ListProjectVariablesOptions{PerPage: 100}
+	for {
+		variables, resp, err := g.client.ListVariables(g.projectID, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range variables {
+			scoped.add(variable.Key, variable.Value, variable.EnvironmentScope)
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return scoped.values, nil
+}
+
+// groupVariables lists the variables of a group visible to the environment by key.
+// The group variables API does not filter by environment scope, so all variables are listed.
+func (g *Gitlab) groupVariables(groupID string) (map[string]string, error) {
+	if utils.IsNil(g.groupsClient) {
+		return nil, fmt.Errorf(errUninitalizedGitlabProvider)
+	}
+	scoped := newScopedVariables(g.environment)
+	opts := &gitlab.ListGroupVariablesOptions{PerPage: 100}
+	for {
+		variables, resp, err := g.groupsClient.ListVariables(groupID, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range variables {
+			scoped.add(variable.Key, variable.Value, variable.EnvironmentScope)
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return scoped.values, nil
+}
+
+// scopedVariables resolves the values of variables defined for several environment scopes.
+// A variable of the environment scope takes precedence over one of the wildcard scope,
+// variables of other scopes are ignored. Without an environment the first variable wins.
+type scopedVariables struct {
+	environment string
+	values      map[string]string
+	exact       map[string]bool
+}
+
+func newScopedVariables(environment string) *scopedVariables {
+	return &scopedVariables{
+		environment: environment,
+		values:      make(map[string]string),
+		exact:       make(map[string]bool),
+	}
+}
+
+func (s *scopedVariables) add(key, value, scope string) {
+	switch {
+	case s.environment == "":
+		if _, ok := s.values[key]; !ok {
+			s.values[key] = value
+		}
+	case scope == s.environment:
+		s.values[key] = value
+		s.exact[key] = true
+	case scope == wildcardScope && !s.exact[key]:
+		s.values[key] = value
+	}
+}
+
+func isNotFound(resp *gitlab.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusNotFound
 }
 
 func (g *Gitlab) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
@@ -15,6 +15,7 @@ package gitlab
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -167,6 +168,164 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestGetSecretEnvironmentAndGroups(t *testing.T) {
+	projectVariables := []*gitlab.ProjectVariable{
+		{Key: "DB_PASSWORD", Value: "project-production", EnvironmentScope: "production"},
+		{Key: "DB_PASSWORD", Value: "project-all", EnvironmentScope: "*"},
+	}
+	subgroupVariables := []*gitlab.GroupVariable{
+		{Key: "API_TOKEN", Value: "subgroup-production", EnvironmentScope: "production"},
+		{Key: "API_TOKEN", Value: "subgroup-all", EnvironmentScope: "*"},
+	}
+	groupVariables := []*gitlab.GroupVariable{
+		{Key: "API_TOKEN", Value: "group-all", EnvironmentScope: "*"},
+		{Key: "REGISTRY_TOKEN", Value: "group-staging", EnvironmentScope: "staging"},
+	}
+
+	tests := map[string]struct {
+		projectID   string
+		environment string
+		key         string
+		want        string
+		wantErr     error
+	}{
+		"project variable of environment": {
+			projectID:   "project",
+			environment: "production",
+			key:         "DB_PASSWORD",
+			want:        "project-production",
+		},
+		"project variable of wildcard scope": {
+			projectID:   "project",
+			environment: "staging",
+			key:         "DB_PASSWORD",
+			want:        "project-all",
+		},
+		"group variable of environment": {
+			projectID:   "project",
+			environment: "production",
+			key:         "API_TOKEN",
+			want:        "subgroup-production",
+		},
+		"group variable of wildcard scope": {
+			projectID:   "project",
+			environment: "staging",
+			key:         "API_TOKEN",
+			want:        "subgroup-all",
+		},
+		"groups without project": {
+			environment: "staging",
+			key:         "REGISTRY_TOKEN",
+			want:        "group-staging",
+		},
+		"variable of other environment": {
+			projectID:   "project",
+			environment: "production",
+			key:         "REGISTRY_TOKEN",
+			wantErr:     esv1beta1.NoSecretErr,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &fakegitlab.GitlabMockClient{}
+			client.WithVariables(projectVariables...)
+			groupsClient := &fakegitlab.GitlabMockGroupsClient{}
+			groupsClient.WithVariables("subgroup", subgroupVariables...)
+			groupsClient.WithVariables("group", groupVariables...)
+			sm := Gitlab{
+				client:       client,
+				groupsClient: groupsClient,
+				projectID:    tc.projectID,
+				groupIDs:     []string{"subgroup", "group"},
+				environment:  tc.environment,
+			}
+			out, err := sm.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: tc.key})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("unexpected error: %v, expected: %v", err, tc.wantErr)
+			}
+			if string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	projectVariables := []*gitlab.ProjectVariable{
+		{Key: "DB_PASSWORD", Value: "project-production", EnvironmentScope: "production"},
+		{Key: "DB_PASSWORD", Value: "project-all", EnvironmentScope: "*"},
+		{Key: "DB_USER", Value: "project-user", EnvironmentScope: "*"},
+	}
+	subgroupVariables := []*gitlab.GroupVariable{
+		{Key: "DB_USER", Value: "subgroup-user", EnvironmentScope: "*"},
+		{Key: "DB_HOST", Value: "subgroup-host", EnvironmentScope: "production"},
+	}
+	groupVariables := []*gitlab.GroupVariable{
+		{Key: "DB_HOST", Value: "group-host", EnvironmentScope: "*"},
+		{Key: "DB_PORT", Value: "5432", EnvironmentScope: "*"},
+		{Key: "API_TOKEN", Value: "group-token", EnvironmentScope: "*"},
+	}
+
+	tests := map[string]struct {
+		environment string
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		wantErr     string
+	}{
+		"find by name": {
+			environment: "production",
+			ref:         esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^DB_"}},
+			want: map[string][]byte{
+				"DB_PASSWORD": []byte("project-production"),
+				"DB_USER":     []byte("project-user"),
+				"DB_HOST":     []byte("subgroup-host"),
+				"DB_PORT":     []byte("5432"),
+			},
+		},
+		"find by name of other environment": {
+			environment: "staging",
+			ref:         esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^DB_"}},
+			want: map[string][]byte{
+				"DB_PASSWORD": []byte("project-all"),
+				"DB_USER":     []byte("project-user"),
+				"DB_HOST":     []byte("group-host"),
+				"DB_PORT":     []byte("5432"),
+			},
+		},
+		"invalid regexp": {
+			ref:     esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "["}},
+			wantErr: "could not compile find.name.regexp",
+		},
+		"find by tags": {
+			ref:     esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "payments"}},
+			wantErr: errFindByTags,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &fakegitlab.GitlabMockClient{}
+			client.WithVariables(projectVariables...)
+			groupsClient := &fakegitlab.GitlabMockGroupsClient{}
+			groupsClient.WithVariables("subgroup", subgroupVariables...)
+			groupsClient.WithVariables("group", groupVariables...)
+			sm := Gitlab{
+				client:       client,
+				groupsClient: groupsClient,
+				projectID:    "project",
+				groupIDs:     []string{"subgroup", "group"},
+				environment:  tc.environment,
+			}
+			out, err := sm.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.wantErr) {
+				t.Fatalf("unexpected error: %v, expected: %s", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secrets: expected %v, got %v", tc.want, out)
+			}
+		})
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
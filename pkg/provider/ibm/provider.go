@@ -23,7 +23,6 @@ import (
 
 	core "github.com/IBM/go-sdk-core/v5/core"
 	sm "github.com/IBM/secrets-manager-go-sdk/secretsmanagerv1"
-	gjson "github.com/tidwall/gjson"
 	corev1 "k8s.io/api/core/v1"
 	types "k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -45,8 +44,17 @@ const (
 	errFetchSAKSecret                        = "could not fetch SecretAccessKey secret: %w"
 	errMissingSAK                            = "missing SecretAccessKey"
 	errJSONSecretUnmarshal                   = "unable to unmarshal secret: %w"
+
+	iamCredentialsAPIKey = "apikey"
 )
 
+// typedSecretKeys lists the keys secrets of the typed secret types are projected to.
+var typedSecretKeys = map[string][]string{
+	sm.CreateSecretOptionsSecretTypeUsernamePasswordConst: {"username", "password"},
+	sm.CreateSecretOptionsSecretTypeImportedCertConst:     {"certificate", "private_key", "intermediate"},
+	sm.CreateSecretOptionsSecretTypePublicCertConst:       {"certificate", "private_key", "intermediate"},
+}
+
 type SecretManagerClient interface {
 	GetSecret(getSecretOptions *sm.GetSecretOptions) (result *sm.GetSecret, response *core.DetailedResponse, err error)
 }
@@ -121,32 +129,21 @@ func (ibm *providerIBM) GetSecret(ctx context.Context, ref esv1beta1.ExternalSec
 
 		return getArbitrarySecret(ibm, &secretName)
 
-	case sm.CreateSecretOptionsSecretTypeUsernamePasswordConst:
-
-		if ref.Property == "" {
-			return nil, fmt.Errorf("remoteRef.property required for secret type username_password")
-		}
-		return getUsernamePasswordSecret(ibm, &secretName, ref)
-
 	case sm.CreateSecretOptionsSecretTypeIamCredentialsConst:
 
-		return getIamCredentialsSecret(ibm, &secretName)
-
-	case sm.CreateSecretOptionsSecretTypeImportedCertConst:
-
 		if ref.Property == "" {
-			return nil, fmt.Errorf("remoteRef.property required for secret type imported_cert")
+			ref.Property = iamCredentialsAPIKey
 		}
+		return getTypedSecret(ibm, &secretName, secretType, ref)
 
-		return getImportCertSecret(ibm, &secretName, ref)
-
-	case sm.CreateSecretOptionsSecretTypePublicCertConst:
+	case sm.CreateSecretOptionsSecretTypeUsernamePasswordConst,
+		sm.CreateSecretOptionsSecretTypeImportedCertConst,
+		sm.CreateSecretOptionsSecretTypePublicCertConst:
 
 		if ref.Property == "" {
-			return nil, fmt.Errorf("remoteRef.property required for secret type public_cert")
+			return nil, fmt.Errorf("remoteRef.property required for secret type %s", secretType)
 		}
-
-		return getPublicCertSecret(ibm, &secretName, ref)
+		return getTypedSecret(ibm, &secretName, secretType, ref)
 
 	case sm.CreateSecretOptionsSecretTypeKvConst:
 
@@ -173,77 +170,37 @@ func getArbitrarySecret(ibm *providerIBM, secretName *string) ([]byte, error) {
 	return []byte(arbitrarySecretPayload), nil
 }
 
-func getImportCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
-		&sm.GetSecretOptions{
-			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeImportedCertConst),
-			ID:         secretName,
-		})
+// getTypedSecret returns the key ref.Property of a secret of a typed secret type.
+func getTypedSecret(ibm *providerIBM, secretName *string, secretType string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, err := getSecretByType(ibm, secretName, secretType)
 	if err != nil {
 		return nil, err
 	}
 
-	secret := response.Resources[0].(*sm.SecretResource)
-	secretData := secret.SecretData.(map[string]interface{})
-
-	if val, ok := secretData[ref.Property]; ok {
-		return []byte(val.(string)), nil
+	if val, ok := getTypedSecretData(secret, secretType)[ref.Property]; ok {
+		return val, nil
 	}
 	return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 }
 
-func getPublicCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
-		&sm.GetSecretOptions{
-			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypePublicCertConst),
-			ID:         secretName,
-		})
-	if err != nil {
-		return nil, err
-	}
-
-	secret := response.Resources[0].(*sm.SecretResource)
-	secretData := secret.SecretData.(map[string]interface{})
-
-	if val, ok := secretData[ref.Property]; ok {
-		return []byte(val.(string)), nil
-	}
-	return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
-}
-
-func getIamCredentialsSecret(ibm *providerIBM, secretName *string) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
-		&sm.GetSecretOptions{
-			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeIamCredentialsConst),
-			ID:         secretName,
-		})
-	if err != nil {
-		return nil, err
-	}
-
-	secret := response.Resources[0].(*sm.SecretResource)
-	secretData := *secret.APIKey
-
-	return []byte(secretData), nil
-}
-
-func getUsernamePasswordSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
-		&sm.GetSecretOptions{
-			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeUsernamePasswordConst),
-			ID:         secretName,
-		})
-	if err != nil {
-		return nil, err
+// getTypedSecretData projects a secret of a typed secret type to its well-known keys.
+// Keys that are not set on the secret, e.g. the intermediate of a certificate, are omitted.
+func getTypedSecretData(secret *sm.SecretResource, secretType string) map[string][]byte {
+	data := make(map[string][]byte)
+	if secretType == sm.CreateSecretOptionsSecretTypeIamCredentialsConst {
+		if secret.APIKey != nil {
+			data[iamCredentialsAPIKey] = []byte(*secret.APIKey)
+		}
+		return data
 	}
 
-	secret := response.Resources[0].(*sm.SecretResource)
-	secretData := secret.SecretData.(map[string]interface{})
-
-	if val, ok := secretData[ref.Property]; ok {
-		return []byte(val.(string)), nil
+	secretData, _ := secret.SecretData.(map[string]interface{})
+	for _, key := range typedSecretKeys[secretType] {
+		if val, ok := secretData[key].(string); ok {
+			data[key] = []byte(val)
+		}
 	}
-	return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
+	return data
 }
 
 // Returns a secret of type kv and supports json path.
@@ -279,32 +236,14 @@ func getKVSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSec
 		return []byte(payloadJSON.(string)), nil
 	}
 
-	// returns the requested key
-	// consider that the key contains a ".". this could be one of 2 options
-	// a) "." is part of the key name
-	// b) "." is symbole for JSON path
+	// returns the requested key, keys containing a "." take precedence over
+	// the JSON path
 	if ref.Property != "" {
-		refProperty := ref.Property
-
-		// a) "." is part the key name
-		// escape "."
-		idx := strings.Index(refProperty, ".")
-		if idx > 0 {
-			refProperty = strings.ReplaceAll(refProperty, ".", "\\.")
-
-			val := gjson.Get(payloadJSON.(string), refProperty)
-			if val.Exists() {
-				return []byte(val.String()), nil
-			}
-		}
-
-		// b) "." is symbole for JSON path
-		// try to get value for this path
-		val := gjson.Get(payloadJSON.(string), ref.Property)
-		if !val.Exists() {
+		val, ok := utils.GetProperty([]byte(payloadJSON.(string)), ref.Property)
+		if !ok {
 			return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 		}
-		return []byte(val.String()), nil
+		return val, nil
 	}
 
 	return nil, fmt.Errorf("no property provided for secret %s", ref.Key)
@@ -344,7 +283,7 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 		response, _, err := ibm.IBMClient.GetSecret(
 			&sm.GetSecretOptions{
 				SecretType: core.StringPtr(sm.GetSecretOptionsSecretTypeArbitraryConst),
-				ID:         &ref.Key,
+				ID:         &secretName,
 			})
 		if err != nil {
 			return nil, err
@@ -364,74 +303,16 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 
 		return secretMap, nil
 
-	case sm.CreateSecretOptionsSecretTypeUsernamePasswordConst:
-		response, _, err := ibm.IBMClient.GetSecret(
-			&sm.GetSecretOptions{
-				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeUsernamePasswordConst),
-				ID:         &secretName,
-			})
-		if err != nil {
-			return nil, err
-		}
-
-		secret := response.Resources[0].(*sm.SecretResource)
-		secretData := secret.SecretData.(map[string]interface{})
-
-		secretMap := byteArrayMap(secretData)
-
-		return secretMap, nil
-
-	case sm.CreateSecretOptionsSecretTypeIamCredentialsConst:
-		response, _, err := ibm.IBMClient.GetSecret(
-			&sm.GetSecretOptions{
-				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeIamCredentialsConst),
-				ID:         &secretName,
-			})
-		if err != nil {
-			return nil, err
-		}
-
-		secret := response.Resources[0].(*sm.SecretResource)
-		secretData := *secret.APIKey
-
-		secretMap := make(map[string][]byte)
-		secretMap["apikey"] = []byte(secretData)
-
-		return secretMap, nil
-
-	case sm.CreateSecretOptionsSecretTypeImportedCertConst:
-		response, _, err := ibm.IBMClient.GetSecret(
-			&sm.GetSecretOptions{
-				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeImportedCertConst),
-				ID:         &secretName,
-			})
-		if err != nil {
-			return nil, err
-		}
-
-		secret := response.Resources[0].(*sm.SecretResource)
-		secretData := secret.SecretData.(map[string]interface{})
-
-		secretMap := byteArrayMap(secretData)
-
-		return secretMap, nil
-
-	case sm.CreateSecretOptionsSecretTypePublicCertConst:
-		response, _, err := ibm.IBMClient.GetSecret(
-			&sm.GetSecretOptions{
-				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypePublicCertConst),
-				ID:         &secretName,
-			})
+	case sm.CreateSecretOptionsSecretTypeUsernamePasswordConst,
+		sm.CreateSecretOptionsSecretTypeIamCredentialsConst,
+		sm.CreateSecretOptionsSecretTypeImportedCertConst,
+		sm.CreateSecretOptionsSecretTypePublicCertConst:
+		secret, err := getSecretByType(ibm, &secretName, secretType)
 		if err != nil {
 			return nil, err
 		}
 
-		secret := response.Resources[0].(*sm.SecretResource)
-		secretData := secret.SecretData.(map[string]interface{})
-
-		secretMap := byteArrayMap(secretData)
-
-		return secretMap, nil
+		return getTypedSecretData(secret, secretType), nil
 
 	case sm.CreateSecretOptionsSecretTypeKvConst:
 		secret, err := getKVSecret(ibm, &secretName, ref)
@@ -492,6 +373,12 @@ func (ibm *providerIBM) Close(ctx context.Context) error {
 	return nil
 }
 
+// RetriesInternally implements esv1beta1.InternalRetrier,
+// the retrySettings of the store are passed to the IBM client.
+func (ibm *providerIBM) RetriesInternally() bool {
+	return true
+}
+
 func (ibm *providerIBM) Validate() error {
 	return nil
 }
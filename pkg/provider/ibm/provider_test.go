@@ -239,6 +239,12 @@ func TestIBMSecretManagerGetSecret(t *testing.T) {
 		smtc.expectedSecret = secretAPIKey
 	}
 
+	// good case: iam_credenatials type with property
+	setSecretIamWithProperty := func(smtc *secretManagerTestCase) {
+		setSecretIam(smtc)
+		smtc.ref.Property = "apikey"
+	}
+
 	// good case: imported_cert type with property
 	secretCert := "imported_cert/test-secret"
 	setSecretCert := func(smtc *secretManagerTestCase) {
@@ -271,6 +277,14 @@ func TestIBMSecretManagerGetSecret(t *testing.T) {
 		smtc.expectError = "remoteRef.property required for secret type imported_cert"
 	}
 
+	// bad case: imported_cert type with a property that is not a certificate key
+	badSecretCertProperty := func(smtc *secretManagerTestCase) {
+		setSecretCert(smtc)
+		smtc.ref.Property = "payload"
+		smtc.expectedSecret = ""
+		smtc.expectError = "key payload does not exist in secret imported_cert/test-secret"
+	}
+
 	// good case: public_cert type with property
 	secretPublicCert := "public_cert/test-secret"
 	setSecretPublicCert := func(smtc *secretManagerTestCase) {
@@ -418,8 +432,10 @@ func TestIBMSecretManagerGetSecret(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(badSecretUserPass),
 		makeValidSecretManagerTestCaseCustom(setSecretUserPass),
 		makeValidSecretManagerTestCaseCustom(setSecretIam),
+		makeValidSecretManagerTestCaseCustom(setSecretIamWithProperty),
 		makeValidSecretManagerTestCaseCustom(setSecretCert),
 		makeValidSecretManagerTestCaseCustom(badSecretCert),
+		makeValidSecretManagerTestCaseCustom(badSecretCertProperty),
 		makeValidSecretManagerTestCaseCustom(setSecretKV),
 		makeValidSecretManagerTestCaseCustom(setSecretKVWithKey),
 		makeValidSecretManagerTestCaseCustom(setSecretKVWithKeyPath),
@@ -573,6 +589,27 @@ func TestGetSecretMap(t *testing.T) {
 		smtc.expectedData["intermediate"] = []byte(secretIntermediate)
 	}
 
+	// good case: public_cert without intermediate, only certificate keys are returned
+	setSecretPublicCertWithoutIntermediate := func(smtc *secretManagerTestCase) {
+		secretData := make(map[string]interface{})
+		secretData["certificate"] = secretCertificate
+		secretData["private_key"] = secretPrivateKey
+		secretData["common_name"] = "example.com"
+
+		resources := []sm.SecretResourceIntf{
+			&sm.SecretResource{
+				SecretType: utilpointer.StringPtr(sm.CreateSecretOptionsSecretTypePublicCertConst),
+				Name:       utilpointer.StringPtr("testyname"),
+				SecretData: secretData,
+			}}
+
+		smtc.apiInput.SecretType = core.StringPtr(sm.CreateSecretOptionsSecretTypePublicCertConst)
+		smtc.apiOutput.Resources = resources
+		smtc.ref.Key = "public_cert/test-secret"
+		smtc.expectedData["certificate"] = []byte(secretCertificate)
+		smtc.expectedData["private_key"] = []byte(secretPrivateKey)
+	}
+
 	// good case: kv, no property, return entire payload as key:value pairs
 	setSecretKV := func(smtc *secretManagerTestCase) {
 		secretData := make(map[string]interface{})
@@ -664,6 +701,7 @@ func TestGetSecretMap(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(setSecretKVWithPathAndProperty),
 		makeValidSecretManagerTestCaseCustom(badSecretKVWithUnknownProperty),
 		makeValidSecretManagerTestCaseCustom(setSecretPublicCert),
+		makeValidSecretManagerTestCaseCustom(setSecretPublicCertWithoutIntermediate),
 	}
 
 	sm := providerIBM{}
@@ -0,0 +1,150 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package infisical
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/external-secrets/external-secrets/pkg/provider/internal/jsonapi"
+)
+
+const (
+	defaultHostAPI = "https://app.infisical.com/api"
+
+	errLogin = "unable to login with machine identity: %w"
+)
+
+type secretScope struct {
+	projectSlug     string
+	environmentSlug string
+	secretsPath     string
+	recursive       bool
+}
+
+type rawSecret struct {
+	SecretKey   string `json:"secretKey"`
+	SecretValue string `json:"secretValue"`
+	SecretPath  string `json:"secretPath"`
+}
+
+type secretResponse struct {
+	Secret rawSecret `json:"secret"`
+}
+
+type secretsResponse struct {
+	Secrets []rawSecret `json:"secrets"`
+}
+
+type loginResponse struct {
+	AccessToken string `json:"accessToken"`
+}
+
+// apiClient talks to the Infisical REST API with a machine identity access token.
+// see: https://infisical.com/docs/api-reference/overview/introduction
+type apiClient struct {
+	baseURL string
+	api     *jsonapi.Client
+	token   string
+}
+
+func newAPIClient(hostAPI string) *apiClient {
+	if hostAPI == "" {
+		hostAPI = defaultHostAPI
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(hostAPI, "/"),
+		api: &jsonapi.Client{
+			HTTP:         &http.Client{Timeout: 30 * time.Second},
+			Name:         "infisical api",
+			ErrorMessage: jsonapi.MessageField("message"),
+		},
+	}
+}
+
+// universalAuthLogin exchanges client id and secret for an access token.
+func (c *apiClient) universalAuthLogin(ctx context.Context, clientID, clientSecret string) error {
+	return c.login(ctx, "/v1/auth/universal-auth/login", map[string]string{
+		"clientId":     clientID,
+		"clientSecret": clientSecret,
+	})
+}
+
+// kubernetesAuthLogin exchanges a service account token for an access token.
+func (c *apiClient) kubernetesAuthLogin(ctx context.Context, identityID, jwt string) error {
+	return c.login(ctx, "/v1/auth/kubernetes-auth/login", map[string]string{
+		"identityId": identityID,
+		"jwt":        jwt,
+	})
+}
+
+func (c *apiClient) login(ctx context.Context, path string, body map[string]string) error {
+	req, err := c.api.NewRequest(ctx, http.MethodPost, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	var out loginResponse
+	if err := c.api.Do(req, &out); err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	c.token = out.AccessToken
+	return nil
+}
+
+// getSecret returns a single secret of the folder secretPath.
+func (c *apiClient) getSecret(ctx context.Context, scope secretScope, secretPath, name string) (*rawSecret, error) {
+	params := scopeParams(scope, secretPath)
+	var out secretResponse
+	if err := c.get(ctx, "/v3/secrets/raw/"+url.PathEscape(name), params, &out); err != nil {
+		return nil, err
+	}
+	return &out.Secret, nil
+}
+
+// listSecrets returns all secrets of the folder secretPath.
+func (c *apiClient) listSecrets(ctx context.Context, scope secretScope, secretPath string) ([]rawSecret, error) {
+	params := scopeParams(scope, secretPath)
+	params.Set("recursive", strconv.FormatBool(scope.recursive))
+	var out secretsResponse
+	if err := c.get(ctx, "/v3/secrets/raw", params, &out); err != nil {
+		return nil, err
+	}
+	return out.Secrets, nil
+}
+
+func scopeParams(scope secretScope, secretPath string) url.Values {
+	params := url.Values{}
+	params.Set("workspaceSlug", scope.projectSlug)
+	params.Set("environment", scope.environmentSlug)
+	params.Set("secretPath", secretPath)
+	params.Set("expandSecretReferences", "true")
+	return params
+}
+
+// get reads a secret or the secrets of a folder, a missing secret or folder
+// is reported as NoSecretErr.
+func (c *apiClient) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := c.api.NewRequest(ctx, http.MethodGet, c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	return c.api.ReadSecret(req, out)
+}
@@ -0,0 +1,281 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package infisical
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	errMissingStoreSpec     = "missing store provider infisical"
+	errMissingAuth          = "missing auth: one of universalAuthCredentials or kubernetesAuthCredentials is required"
+	errMultipleAuth         = "only one of universalAuthCredentials or kubernetesAuthCredentials may be specified"
+	errMissingProjectSlug   = "missing secretsScope.projectSlug"
+	errMissingEnvSlug       = "missing secretsScope.environmentSlug"
+	errMissingIdentityID    = "missing kubernetesAuthCredentials.identityId"
+	errMissingSecretRefName = "missing name in universalAuthCredentials.%s"
+	errMissingSecretRefKey  = "missing key in universalAuthCredentials.%s"
+	errInvalidSecretRef     = "invalid universalAuthCredentials.%s: %w"
+	errFetchCredentials     = "could not fetch credentials secret %s: %w"
+	errMissingCredentials   = "missing %s in secret %s"
+	errReadSAToken          = "unable to read service account token %s: %w"
+	errUninitalizedClient   = "provider infisical is not initialized"
+	errPropertyNotFound     = "property %s does not exist in secret %s"
+	errJSONSecretUnmarshal  = "unable to unmarshal secret %s: %w"
+	errFindNotImplemented   = "find by tags is not supported by infisical"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Infisical reads secrets of a single project environment.
+type Infisical struct {
+	client *apiClient
+	scope  secretScope
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Infisical: &esv1beta1.InfisicalProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.InfisicalProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Infisical == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Infisical, nil
+}
+
+// NewClient logs in with the machine identity configured in the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	client := newAPIClient(provider.HostAPI)
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	switch {
+	case provider.Auth.UniversalAuthCredentials != nil:
+		creds := provider.Auth.UniversalAuthCredentials
+		clientID, err := secretKeyRef(ctx, kube, storeKind, namespace, "clientId", creds.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		clientSecret, err := secretKeyRef(ctx, kube, storeKind, namespace, "clientSecret", creds.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.universalAuthLogin(ctx, clientID, clientSecret); err != nil {
+			return nil, err
+		}
+	case provider.Auth.KubernetesAuthCredentials != nil:
+		creds := provider.Auth.KubernetesAuthCredentials
+		tokenPath := creds.ServiceAccountTokenPath
+		if tokenPath == "" {
+			tokenPath = defaultServiceAccountTokenPath
+		}
+		jwt, err := os.ReadFile(tokenPath)
+		if err != nil {
+			return nil, fmt.Errorf(errReadSAToken, tokenPath, err)
+		}
+		if err := client.kubernetesAuthLogin(ctx, creds.IdentityID, strings.TrimSpace(string(jwt))); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(errMissingAuth)
+	}
+	secretsPath := provider.SecretsScope.SecretsPath
+	if secretsPath == "" {
+		secretsPath = "/"
+	}
+	return &Infisical{
+		client: client,
+		scope: secretScope{
+			projectSlug:     provider.SecretsScope.ProjectSlug,
+			environmentSlug: provider.SecretsScope.EnvironmentSlug,
+			secretsPath:     secretsPath,
+			recursive:       provider.SecretsScope.Recursive,
+		},
+	}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if value == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.SecretsScope.ProjectSlug == "" {
+		return fmt.Errorf(errMissingProjectSlug)
+	}
+	if provider.SecretsScope.EnvironmentSlug == "" {
+		return fmt.Errorf(errMissingEnvSlug)
+	}
+	universal := provider.Auth.UniversalAuthCredentials
+	kubernetes := provider.Auth.KubernetesAuthCredentials
+	if universal != nil && kubernetes != nil {
+		return fmt.Errorf(errMultipleAuth)
+	}
+	if kubernetes != nil {
+		if kubernetes.IdentityID == "" {
+			return fmt.Errorf(errMissingIdentityID)
+		}
+		return nil
+	}
+	if universal == nil {
+		return fmt.Errorf(errMissingAuth)
+	}
+	if err := validateSecretRef(store, "clientId", universal.ClientID); err != nil {
+		return err
+	}
+	return validateSecretRef(store, "clientSecret", universal.ClientSecret)
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingSecretRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingSecretRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidSecretRef, name, err)
+	}
+	return nil
+}
+
+// GetSecret returns the value of a secret. Secret references are expanded.
+// ref.Key is the name of the secret in the secrets path of the store,
+// or a path relative to it, e.g. database/PASSWORD.
+func (i *Infisical) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if i.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	dir, name := path.Split(ref.Key)
+	secret, err := i.client.getSecret(ctx, i.scope, path.Join(i.scope.secretsPath, dir), name)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return []byte(secret.SecretValue), nil
+	}
+	val, ok := utils.GetProperty([]byte(secret.SecretValue), ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the key/value pairs of a secret that holds a JSON object.
+func (i *Infisical) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := i.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte)
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns all secrets of a folder whose names match ref.Name.
+// ref.Path selects a folder relative to the secrets path of the store.
+func (i *Infisical) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if i.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	secretsPath := i.scope.secretsPath
+	if ref.Path != nil {
+		secretsPath = path.Join(secretsPath, *ref.Path)
+	}
+	secrets, err := i.client.listSecrets(ctx, i.scope, secretsPath)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte)
+	for _, s := range secrets {
+		if matcher != nil && !matcher.MatchName(s.SecretKey) {
+			continue
+		}
+		secretData[s.SecretKey] = []byte(s.SecretValue)
+	}
+	return secretData, nil
+}
+
+func (i *Infisical) Close(ctx context.Context) error {
+	return nil
+}
+
+func (i *Infisical) Validate() error {
+	return nil
+}
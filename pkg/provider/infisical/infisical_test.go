@@ -0,0 +1,356 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package infisical
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+var testSecrets = map[string][]rawSecret{
+	"/app": {
+		{SecretKey: "DB_USER", SecretValue: "admin", SecretPath: "/app"},
+		{SecretKey: "DB_CONFIG", SecretValue: `{"host":"db","port":5432}`, SecretPath: "/app"},
+	},
+	"/app/redis": {
+		{SecretKey: "REDIS_PASSWORD", SecretValue: "hunter2", SecretPath: "/app/redis"},
+	},
+}
+
+func newFakeInfisical(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/universal-auth/login":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["clientId"] != "id" || body["clientSecret"] != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"message":"Invalid credentials"}`)
+				return
+			}
+			fmt.Fprint(w, `{"accessToken":"token","expiresIn":7200,"tokenType":"Bearer"}`)
+			return
+		case "/api/v1/auth/kubernetes-auth/login":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["identityId"] != "identity" || body["jwt"] != "sa-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"message":"Invalid credentials"}`)
+				return
+			}
+			fmt.Fprint(w, `{"accessToken":"token","expiresIn":7200,"tokenType":"Bearer"}`)
+			return
+		}
+		q := r.URL.Query()
+		if r.Header.Get("Authorization") != "Bearer token" || q.Get("workspaceSlug") != "project" || q.Get("environment") != "dev" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		secretPath := q.Get("secretPath")
+		if r.URL.Path == "/api/v3/secrets/raw" {
+			secrets := append([]rawSecret{}, testSecrets[secretPath]...)
+			if q.Get("recursive") == "true" {
+				for p, s := range testSecrets {
+					if strings.HasPrefix(p, secretPath+"/") {
+						secrets = append(secrets, s...)
+					}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(secretsResponse{Secrets: secrets})
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/api/v3/secrets/raw/")
+		for _, s := range testSecrets[secretPath] {
+			if s.SecretKey == name {
+				_ = json.NewEncoder(w).Encode(secretResponse{Secret: s})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Secret not found"}`)
+	}))
+}
+
+func makeStore(hostAPI string, auth esv1beta1.InfisicalAuth, recursive bool) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Infisical: &esv1beta1.InfisicalProvider{
+					Auth: auth,
+					SecretsScope: esv1beta1.InfisicalSecretsScope{
+						ProjectSlug:     "project",
+						EnvironmentSlug: "dev",
+						SecretsPath:     "/app",
+						Recursive:       recursive,
+					},
+					HostAPI: hostAPI,
+				},
+			},
+		},
+	}
+}
+
+func universalAuth() esv1beta1.InfisicalAuth {
+	return esv1beta1.InfisicalAuth{
+		UniversalAuthCredentials: &esv1beta1.InfisicalUniversalAuthCredentials{
+			ClientID:     esmeta.SecretKeySelector{Name: "infisical", Key: "clientId"},
+			ClientSecret: esmeta.SecretKeySelector{Name: "infisical", Key: "clientSecret"},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, auth esv1beta1.InfisicalAuth, clientSecret string, recursive bool) (esv1beta1.SecretsClient, error) {
+	srv := newFakeInfisical(t)
+	t.Cleanup(srv.Close)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "infisical", Namespace: "default"},
+		Data: map[string][]byte{
+			"clientId":     []byte("id"),
+			"clientSecret": []byte(clientSecret),
+		},
+	}).Build()
+	return (&Provider{}).NewClient(context.Background(), makeStore(srv.URL+"/api", auth, recursive), kube, "default")
+}
+
+func TestInfisicalGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"plain value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "DB_USER"},
+			want: "admin",
+		},
+		"property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "DB_CONFIG", Property: "host"},
+			want: "db",
+		},
+		"sub folder": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "redis/REDIS_PASSWORD"},
+			want: "hunter2",
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "DB_CONFIG", Property: "user"},
+			expectError: "property user does not exist in secret DB_CONFIG",
+		},
+		"missing secret": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "NOPE"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c, err := newTestClient(t, universalAuth(), "secret", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestInfisicalGetSecretMap(t *testing.T) {
+	c, err := newTestClient(t, universalAuth(), "secret", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "DB_CONFIG"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"host": []byte("db"),
+		"port": []byte("5432"),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+	_, err = c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "DB_USER"})
+	if !ErrorContains(err, "unable to unmarshal secret DB_USER") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInfisicalGetAllSecrets(t *testing.T) {
+	tests := map[string]struct {
+		recursive   bool
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		expectError string
+	}{
+		"secrets path": {
+			ref: esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^DB_"}},
+			want: map[string][]byte{
+				"DB_USER":   []byte("admin"),
+				"DB_CONFIG": []byte(`{"host":"db","port":5432}`),
+			},
+		},
+		"recursive": {
+			recursive: true,
+			ref:       esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "PASSWORD"}},
+			want: map[string][]byte{
+				"REDIS_PASSWORD": []byte("hunter2"),
+			},
+		},
+		"find path": {
+			ref: esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("redis")},
+			want: map[string][]byte{
+				"REDIS_PASSWORD": []byte("hunter2"),
+			},
+		},
+		"tags": {
+			ref:         esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "a"}},
+			expectError: errFindNotImplemented,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := newTestClient(t, universalAuth(), "secret", tc.recursive)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			out, err := c.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secret data: expected %#v, got %#v", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestInfisicalLogin(t *testing.T) {
+	_, err := newTestClient(t, universalAuth(), "wrong", false)
+	if !ErrorContains(err, "unable to login with machine identity: infisical api returned status 401: Invalid credentials") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenPath, []byte("sa-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := newTestClient(t, esv1beta1.InfisicalAuth{
+		KubernetesAuthCredentials: &esv1beta1.InfisicalKubernetesAuthCredentials{
+			IdentityID:              "identity",
+			ServiceAccountTokenPath: tokenPath,
+		},
+	}, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "DB_USER"})
+	if err != nil || string(out) != "admin" {
+		t.Errorf("unexpected secret: %s, error: %v", string(out), err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	kubernetesAuth := esv1beta1.InfisicalAuth{
+		KubernetesAuthCredentials: &esv1beta1.InfisicalKubernetesAuthCredentials{IdentityID: "identity"},
+	}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"universal auth": {
+			store: makeStore("", universalAuth(), false),
+		},
+		"kubernetes auth": {
+			store: makeStore("", kubernetesAuth, false),
+		},
+		"missing auth": {
+			store:       makeStore("", esv1beta1.InfisicalAuth{}, false),
+			expectError: errMissingAuth,
+		},
+		"multiple auth": {
+			store: makeStore("", esv1beta1.InfisicalAuth{
+				UniversalAuthCredentials:  universalAuth().UniversalAuthCredentials,
+				KubernetesAuthCredentials: kubernetesAuth.KubernetesAuthCredentials,
+			}, false),
+			expectError: errMultipleAuth,
+		},
+		"missing identity id": {
+			store: makeStore("", esv1beta1.InfisicalAuth{
+				KubernetesAuthCredentials: &esv1beta1.InfisicalKubernetesAuthCredentials{},
+			}, false),
+			expectError: errMissingIdentityID,
+		},
+		"missing client secret key": {
+			store: makeStore("", esv1beta1.InfisicalAuth{
+				UniversalAuthCredentials: &esv1beta1.InfisicalUniversalAuthCredentials{
+					ClientID:     esmeta.SecretKeySelector{Name: "infisical", Key: "clientId"},
+					ClientSecret: esmeta.SecretKeySelector{Name: "infisical"},
+				},
+			}, false),
+			expectError: "missing key in universalAuthCredentials.clientSecret",
+		},
+		"namespace not allowed": {
+			store: makeStore("", esv1beta1.InfisicalAuth{
+				UniversalAuthCredentials: &esv1beta1.InfisicalUniversalAuthCredentials{
+					ClientID:     esmeta.SecretKeySelector{Name: "infisical", Key: "clientId", Namespace: pointer.StringPtr("foo")},
+					ClientSecret: esmeta.SecretKeySelector{Name: "infisical", Key: "clientSecret"},
+				},
+			}, false),
+			expectError: "invalid universalAuthCredentials.clientId: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+	store := makeStore("", universalAuth(), false)
+	store.Spec.Provider.Infisical.SecretsScope.EnvironmentSlug = ""
+	if err := (&Provider{}).ValidateStore(store); !ErrorContains(err, errMissingEnvSlug) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
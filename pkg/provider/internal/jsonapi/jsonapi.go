@@ -0,0 +1,143 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errRequest         = "%s request failed: %w"
+	errResponse        = "%s returned status %d"
+	errResponseMessage = "%s returned status %d: %s"
+	errDecode          = "unable to decode %s response: %w"
+
+	// maxErrorBody is the number of bytes of an error response that are read for its message.
+	maxErrorBody = 1024
+)
+
+// Client sends requests to the JSON REST API of a provider.
+type Client struct {
+	// HTTP sends the requests.
+	HTTP *http.Client
+	// Name is the name of the API in errors, e.g. "fortanix api".
+	Name string
+	// ErrorMessage returns the message of the body of a failed response.
+	// Without ErrorMessage the body is used as plain text message.
+	ErrorMessage func(body []byte) string
+}
+
+// NewRequest returns a request to url with body encoded as JSON,
+// a nil body sends no body.
+func (c *Client) NewRequest(ctx context.Context, method, url string, body interface{}) (*http.Request, error) {
+	var payload io.Reader = http.NoBody
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf(errRequest, c.Name, err)
+		}
+		payload = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, payload)
+	if err != nil {
+		return nil, fmt.Errorf(errRequest, c.Name, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// Do sends the request and decodes the JSON body of a successful response into out.
+// A nil out discards the body and a *[]byte receives the raw body.
+// Responses with a status other than 2xx fail with the message of their body.
+func (c *Client) Do(req *http.Request, out interface{}) error {
+	return c.do(req, out, false)
+}
+
+// ReadSecret sends a request that reads a secret like Do. A 404 response means
+// that the secret does not exist and is reported as esv1beta1.NoSecretErr.
+func (c *Client) ReadSecret(req *http.Request, out interface{}) error {
+	return c.do(req, out, true)
+}
+
+func (c *Client) do(req *http.Request, out interface{}, isSecretRead bool) error {
+	if _, raw := out.(*[]byte); !raw && req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf(errRequest, c.Name, err)
+	}
+	defer resp.Body.Close()
+	if isSecretRead && resp.StatusCode == http.StatusNotFound {
+		return esv1beta1.NoSecretErr
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		if msg := c.errorMessage(body); msg != "" {
+			return fmt.Errorf(errResponseMessage, c.Name, resp.StatusCode, msg)
+		}
+		return fmt.Errorf(errResponse, c.Name, resp.StatusCode)
+	}
+	switch o := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		if *o, err = io.ReadAll(resp.Body); err != nil {
+			return fmt.Errorf(errDecode, c.Name, err)
+		}
+		return nil
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf(errDecode, c.Name, err)
+	}
+	return nil
+}
+
+func (c *Client) errorMessage(body []byte) string {
+	if c.ErrorMessage != nil {
+		return c.ErrorMessage(body)
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// MessageField returns an ErrorMessage func that reads the message of a JSON error
+// response from the first of the fields which is set.
+func MessageField(fields ...string) func(body []byte) string {
+	return func(body []byte) string {
+		var errResp map[string]interface{}
+		if err := json.Unmarshal(body, &errResp); err != nil {
+			return ""
+		}
+		for _, f := range fields {
+			if msg, ok := errResp[f].(string); ok && msg != "" {
+				return msg
+			}
+		}
+		return ""
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+type item struct {
+	Name string `json:"name"`
+}
+
+func newFakeAPI(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/item":
+			if r.Header.Get("Accept") != "application/json" {
+				t.Errorf("unexpected accept header %q", r.Header.Get("Accept"))
+			}
+			fmt.Fprint(w, `{"name":"foo"}`)
+		case "/echo":
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+			}
+			var in item
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				t.Errorf("unable to decode request: %v", err)
+			}
+			_ = json.NewEncoder(w).Encode(in)
+		case "/file":
+			if r.Header.Get("Accept") != "" {
+				t.Errorf("unexpected accept header %q", r.Header.Get("Accept"))
+			}
+			fmt.Fprint(w, "raw content")
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/invalid":
+			fmt.Fprint(w, "not json")
+		case "/denied":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"access denied"}`)
+		case "/plain":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "bad request\n")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDo(t *testing.T) {
+	srv := newFakeAPI(t)
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         interface{}
+		out          func() interface{}
+		errorMessage func([]byte) string
+		secretRead   bool
+		want         interface{}
+		wantErr      string
+		wantNoSecret bool
+	}{
+		{
+			name: "decodes json",
+			path: "/item",
+			out:  func() interface{} { return &item{} },
+			want: &item{Name: "foo"},
+		},
+		{
+			name:   "encodes body",
+			method: http.MethodPost,
+			path:   "/echo",
+			body:   item{Name: "bar"},
+			out:    func() interface{} { return &item{} },
+			want:   &item{Name: "bar"},
+		},
+		{
+			name: "raw body",
+			path: "/file",
+			out:  func() interface{} { return &[]byte{} },
+			want: func() *[]byte { b := []byte("raw content"); return &b }(),
+		},
+		{
+			name: "nil out discards body",
+			path: "/item",
+			out:  func() interface{} { return nil },
+			want: nil,
+		},
+		{
+			name: "no content",
+			path: "/empty",
+			out:  func() interface{} { return &item{} },
+			want: &item{},
+		},
+		{
+			name:    "invalid json",
+			path:    "/invalid",
+			out:     func() interface{} { return &item{} },
+			wantErr: "unable to decode test api response: invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			name:    "plain text error",
+			path:    "/plain",
+			out:     func() interface{} { return &item{} },
+			wantErr: "test api returned status 400: bad request",
+		},
+		{
+			name:         "json error message",
+			path:         "/denied",
+			out:          func() interface{} { return &item{} },
+			errorMessage: MessageField("error", "message"),
+			wantErr:      "test api returned status 403: access denied",
+		},
+		{
+			name:         "error without message",
+			path:         "/plain",
+			out:          func() interface{} { return &item{} },
+			errorMessage: MessageField("message"),
+			wantErr:      "test api returned status 400",
+		},
+		{
+			name:    "missing item",
+			path:    "/missing",
+			out:     func() interface{} { return &item{} },
+			wantErr: "test api returned status 404",
+		},
+		{
+			name:         "missing secret",
+			path:         "/missing",
+			out:          func() interface{} { return &item{} },
+			secretRead:   true,
+			wantNoSecret: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{HTTP: srv.Client(), Name: "test api", ErrorMessage: tt.errorMessage}
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, err := c.NewRequest(context.Background(), method, srv.URL+tt.path, tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			out := tt.out()
+			if tt.secretRead {
+				err = c.ReadSecret(req, out)
+			} else {
+				err = c.Do(req, out)
+			}
+			if tt.wantNoSecret {
+				if !errors.Is(err, esv1beta1.NoSecretErr) {
+					t.Fatalf("expected NoSecretErr, got %v", err)
+				}
+				return
+			}
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("unexpected result: got %#v, want %#v", out, tt.want)
+			}
+		})
+	}
+}
@@ -16,18 +16,26 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
@@ -39,10 +47,32 @@ const (
 	errMissingCredentials                  = "missing Credentials: %v"
 	errUninitalizedKubernetesProvider      = "provider kubernetes is not initialized"
 	errEmptyKey                            = "key %s found but empty"
+	errParseKubeconfig                     = "could not parse kubeconfig: %w"
+	errServiceAccountToken                 = "could not request token for service account %s: %w"
+	errFindByPath                          = "find by path is not supported by the kubernetes provider"
+	errListSecrets                         = "could not list secrets: %w"
 )
 
+// serviceAccountTokenTTL is the lifetime of tokens requested for serviceAccount auth.
+const serviceAccountTokenTTL int64 = 600
+
+// newServiceAccountsClient returns a client for the service accounts of the cluster
+// external-secrets runs in. It is a variable so tests can replace it.
+var newServiceAccountsClient = func() (typedcorev1.ServiceAccountsGetter, error) {
+	cfg, err := ctrlcfg.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.CoreV1(), nil
+}
+
 type KClient interface {
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*corev1.SecretList, error)
 }
 
 type RClient interface {
@@ -90,31 +120,52 @@ func (k *ProviderKubernetes) NewClient(ctx context.Context, store esv1beta1.Gene
 		storeKind: store.GetObjectKind().GroupVersionKind().Kind,
 	}
 
-	if err := bStore.setAuth(ctx); err != nil {
+	config, err := bStore.getConfig(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	config := &rest.Config{
-		Host:        bStore.store.Server.URL,
-		BearerToken: string(bStore.BearerToken),
-		TLSClientConfig: rest.TLSClientConfig{
-			Insecure: false,
-			CertData: bStore.Certificate,
-			KeyData:  bStore.Key,
-			CAData:   bStore.CA,
-		},
-	}
-
 	kubeClientSet, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("error configuring clientset: %w", err)
 	}
 
-	k.Client = kubeClientSet.CoreV1().Secrets(bStore.store.RemoteNamespace)
-	k.Namespace = bStore.store.RemoteNamespace
-	k.ReviewClient = kubeClientSet.AuthorizationV1().SelfSubjectAccessReviews()
+	return &ProviderKubernetes{
+		Client:       kubeClientSet.CoreV1().Secrets(bStore.store.RemoteNamespace),
+		Namespace:    bStore.store.RemoteNamespace,
+		ReviewClient: kubeClientSet.AuthorizationV1().SelfSubjectAccessReviews(),
+	}, nil
+}
+
+// getConfig builds the rest config either from the kubeconfig referenced by authRef
+// or from the server and auth settings of the store.
+func (k *BaseClient) getConfig(ctx context.Context) (*rest.Config, error) {
+	if k.store.AuthRef != nil {
+		kubeconfig, err := k.fetchSecretKey(ctx, *k.store.AuthRef, "kubeconfig")
+		if err != nil {
+			return nil, err
+		}
+		config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf(errParseKubeconfig, err)
+		}
+		return config, nil
+	}
+
+	if err := k.setAuth(ctx); err != nil {
+		return nil, err
+	}
 
-	return k, nil
+	return &rest.Config{
+		Host:        k.store.Server.URL,
+		BearerToken: string(k.BearerToken),
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure: false,
+			CertData: k.Certificate,
+			KeyData:  k.Key,
+			CAData:   k.CA,
+		},
+	}, nil
 }
 
 func (k *ProviderKubernetes) Close(ctx context.Context) error {
@@ -146,6 +197,9 @@ func (k *ProviderKubernetes) GetSecretMap(ctx context.Context, ref esv1beta1.Ext
 	opts := metav1.GetOptions{}
 	secretOut, err := k.Client.Get(ctx, ref.Key, opts)
 
+	if apierrors.IsNotFound(err) {
+		return nil, esv1beta1.NoSecretErr
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -158,8 +212,46 @@ func (k *ProviderKubernetes) GetSecretMap(ctx context.Context, ref esv1beta1.Ext
 	return payload, nil
 }
 
+// GetAllSecrets returns the json-encoded data of all secrets in the remote namespace
+// matching the label selector ref.Tags and the name expression ref.Name, keyed by secret name.
 func (k *ProviderKubernetes) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	return nil, fmt.Errorf("not implemented")
+	if utils.IsNil(k.Client) {
+		return nil, fmt.Errorf(errUninitalizedKubernetesProvider)
+	}
+	if ref.Path != nil {
+		return nil, fmt.Errorf(errFindByPath)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	opts := metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(ref.Tags).String(),
+	}
+	secrets, err := k.Client.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf(errListSecrets, err)
+	}
+	secretData := make(map[string][]byte)
+	for _, secret := range secrets.Items {
+		if matcher != nil && !matcher.MatchName(secret.Name) {
+			continue
+		}
+		data := make(map[string]string, len(secret.Data))
+		for key, val := range secret.Data {
+			data[key] = string(val)
+		}
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		secretData[secret.Name] = payload
+	}
+	return utils.ConvertKeys(ref.ConversionStrategy, secretData)
 }
 
 func (k *BaseClient) setAuth(ctx context.Context) error {
@@ -180,13 +272,20 @@ func (k *BaseClient) setAuth(ctx context.Context) error {
 		return fmt.Errorf("no Certificate Authority provided")
 	}
 
+	if k.store.Auth == nil {
+		return fmt.Errorf("no credentials provided")
+	}
+
 	if k.store.Auth.Token != nil {
 		k.BearerToken, err = k.fetchSecretKey(ctx, k.store.Auth.Token.BearerToken, "bearerToken")
 		if err != nil {
 			return err
 		}
 	} else if k.store.Auth.ServiceAccount != nil {
-		return fmt.Errorf("not implemented yet")
+		k.BearerToken, err = k.serviceAccountToken(ctx, k.store.Auth.ServiceAccount)
+		if err != nil {
+			return err
+		}
 	} else if k.store.Auth.Cert != nil {
 		k.Certificate, err = k.fetchSecretKey(ctx, k.store.Auth.Cert.ClientCert, "cert")
 		if err != nil {
@@ -203,6 +302,34 @@ func (k *BaseClient) setAuth(ctx context.Context) error {
 	return nil
 }
 
+// serviceAccountToken requests a short-lived token for the referenced service account.
+func (k *BaseClient) serviceAccountToken(ctx context.Context, auth *esv1beta1.ServiceAccountAuth) ([]byte, error) {
+	ref := auth.ServiceAccountRef
+	namespace := k.namespace
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if k.storeKind == esv1beta1.ClusterSecretStoreKind {
+		if ref.Namespace == nil {
+			return nil, fmt.Errorf(errInvalidClusterStoreMissingNamespace)
+		}
+		namespace = *ref.Namespace
+	}
+	saClient, err := newServiceAccountsClient()
+	if err != nil {
+		return nil, fmt.Errorf(errServiceAccountToken, ref.Name, err)
+	}
+	ttl := serviceAccountTokenTTL
+	tokenRequest, err := saClient.ServiceAccounts(namespace).CreateToken(ctx, ref.Name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         auth.Audiences,
+			ExpirationSeconds: &ttl,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf(errServiceAccountToken, ref.Name, err)
+	}
+	return []byte(tokenRequest.Status.Token), nil
+}
+
 func (k *BaseClient) fetchSecretKey(ctx context.Context, key esmeta.SecretKeySelector, component string) ([]byte, error) {
 	keySecret := &corev1.Secret{}
 	keySecretName := key.Name
@@ -264,10 +391,25 @@ func (k *ProviderKubernetes) Validate() error {
 func (k *ProviderKubernetes) ValidateStore(store esv1beta1.GenericStore) error {
 	storeSpec := store.GetSpec()
 	k8sSpec := storeSpec.Provider.Kubernetes
+	if k8sSpec.AuthRef != nil {
+		if k8sSpec.Auth != nil {
+			return fmt.Errorf("only one of auth or authRef is allowed")
+		}
+		if k8sSpec.AuthRef.Name == "" {
+			return fmt.Errorf("AuthRef.Name cannot be empty")
+		}
+		if k8sSpec.AuthRef.Key == "" {
+			return fmt.Errorf("AuthRef.Key cannot be empty")
+		}
+		return utils.ValidateSecretSelector(store, *k8sSpec.AuthRef)
+	}
 	if k8sSpec.Server.CABundle == nil && k8sSpec.Server.CAProvider == nil {
 		return fmt.Errorf("a CABundle or CAProvider is required")
 	}
 
+	if k8sSpec.Auth == nil {
+		return fmt.Errorf("an Auth type must be specified")
+	}
 	if k8sSpec.Auth.Cert != nil {
 		if k8sSpec.Auth.Cert.ClientCert.Name == "" {
 			return fmt.Errorf("ClientCert.Name cannot be empty")
@@ -288,6 +430,13 @@ func (k *ProviderKubernetes) ValidateStore(store esv1beta1.GenericStore) error {
 		if err := utils.ValidateSecretSelector(store, k8sSpec.Auth.Token.BearerToken); err != nil {
 			return err
 		}
+	} else if k8sSpec.Auth.ServiceAccount != nil {
+		if k8sSpec.Auth.ServiceAccount.ServiceAccountRef.Name == "" {
+			return fmt.Errorf("ServiceAccount.Name cannot be empty")
+		}
+		if err := utils.ValidateServiceAccountSelector(store, k8sSpec.Auth.ServiceAccount.ServiceAccountRef); err != nil {
+			return err
+		}
 	} else {
 		return fmt.Errorf("an Auth type must be specified")
 	}
@@ -21,9 +21,16 @@ import (
 	"strings"
 	"testing"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
 	fclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
@@ -50,6 +57,14 @@ func (fk fakeClient) Get(ctx context.Context, name string, opts metav1.GetOption
 	return &secret, nil
 }
 
+func (fk fakeClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.SecretList, error) {
+	list := &corev1.SecretList{}
+	for _, secret := range fk.secretMap {
+		list.Items = append(list.Items, secret)
+	}
+	return list, nil
+}
+
 type fakeReviewClient struct {
 	authReview *authv1.SelfSubjectAccessReview
 }
@@ -193,7 +208,7 @@ func TestKubernetesSecretManagerSetAuth(t *testing.T) {
 	}
 
 	kp = esv1beta1.KubernetesProvider{
-		Auth: esv1beta1.KubernetesAuth{
+		Auth: &esv1beta1.KubernetesAuth{
 			Cert: &esv1beta1.CertAuth{
 				ClientCert: v1.SecretKeySelector{
 					Name: "fake-name",
@@ -288,7 +303,7 @@ func TestValidateStore(t *testing.T) {
 	} else if err.Error() != "an Auth type must be specified" {
 		t.Errorf("empty Auth test failed")
 	}
-	store.Spec.Provider.Kubernetes.Auth = esv1beta1.KubernetesAuth{Cert: &esv1beta1.CertAuth{}}
+	store.Spec.Provider.Kubernetes.Auth = &esv1beta1.KubernetesAuth{Cert: &esv1beta1.CertAuth{}}
 	err = p.ValidateStore(store)
 	if err == nil {
 		t.Errorf(errExpectedErr)
@@ -311,7 +326,7 @@ func TestValidateStore(t *testing.T) {
 	} else if err.Error() != "namespace not allowed with namespaced SecretStore" {
 		t.Errorf("KeySelector test failed: expected namespace not allowed, got %v", err)
 	}
-	store.Spec.Provider.Kubernetes.Auth = esv1beta1.KubernetesAuth{Token: &esv1beta1.TokenAuth{}}
+	store.Spec.Provider.Kubernetes.Auth = &esv1beta1.KubernetesAuth{Token: &esv1beta1.TokenAuth{}}
 	err = p.ValidateStore(store)
 	if err == nil {
 		t.Errorf(errExpectedErr)
@@ -333,7 +348,7 @@ func TestValidateStore(t *testing.T) {
 	} else if err.Error() != "namespace not allowed with namespaced SecretStore" {
 		t.Errorf("KeySelector test failed: expected namespace not allowed, got %v", err)
 	}
-	store.Spec.Provider.Kubernetes.Auth = esv1beta1.KubernetesAuth{
+	store.Spec.Provider.Kubernetes.Auth = &esv1beta1.KubernetesAuth{
 		Cert: &esv1beta1.CertAuth{
 			ClientCert: v1.SecretKeySelector{
 				Name: secretName,
@@ -396,3 +411,202 @@ func TestValidate(t *testing.T) {
 		t.Errorf("Test Failed! Wanted could not verify if client is valid: Something went wrong got: %v", err)
 	}
 }
+
+type notFoundClient struct{}
+
+func (notFoundClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+	return nil, apierrors.NewNotFound(corev1.Resource("secrets"), name)
+}
+
+func (notFoundClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.SecretList, error) {
+	return &corev1.SecretList{}, nil
+}
+
+func TestKubernetesSecretManagerNotFound(t *testing.T) {
+	kp := ProviderKubernetes{Client: notFoundClient{}}
+	_, err := kp.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "missing", Property: "foo"})
+	if !errors.Is(err, esv1beta1.NoSecretErr) {
+		t.Errorf("expected NoSecretErr, got %v", err)
+	}
+}
+
+func TestKubernetesGetAllSecrets(t *testing.T) {
+	makeSecret := func(name string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "remote",
+				Labels:    labels,
+			},
+			Data: map[string][]byte{"password": []byte(name)},
+		}
+	}
+	clientset := kfake.NewSimpleClientset(
+		makeSecret("foo-db", map[string]string{"app": "foo"}),
+		makeSecret("foo-api", map[string]string{"app": "foo", "tier": "backend"}),
+		makeSecret("bar-db", map[string]string{"app": "bar"}),
+	)
+	kp := ProviderKubernetes{Client: clientset.CoreV1().Secrets("remote")}
+
+	tests := map[string]struct {
+		ref  esv1beta1.ExternalSecretFind
+		want map[string][]byte
+		err  string
+	}{
+		"by labels": {
+			ref: esv1beta1.ExternalSecretFind{Tags: map[string]string{"app": "foo"}},
+			want: map[string][]byte{
+				"foo-db":  []byte(`{"password":"foo-db"}`),
+				"foo-api": []byte(`{"password":"foo-api"}`),
+			},
+		},
+		"by labels and name": {
+			ref: esv1beta1.ExternalSecretFind{
+				Tags: map[string]string{"app": "foo"},
+				Name: &esv1beta1.FindName{RegExp: "-db$"},
+			},
+			want: map[string][]byte{
+				"foo-db": []byte(`{"password":"foo-db"}`),
+			},
+		},
+		"by path": {
+			ref: esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("foo")},
+			err: errFindByPath,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := kp.GetAllSecrets(context.Background(), tc.ref)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if errStr != tc.err {
+				t.Fatalf("unexpected error: '%s' (expected '%s')", errStr, tc.err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected result: %v (expected %v)", got, tc.want)
+			}
+		})
+	}
+}
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: eso
+current-context: remote
+users:
+- name: eso
+  user:
+    token: remote-token
+`
+
+func TestKubernetesGetConfig(t *testing.T) {
+	fs := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote-cluster", Namespace: "default"},
+		Data: map[string][]byte{
+			"kubeconfig": []byte(testKubeconfig),
+			"broken":     []byte("{"),
+		},
+	}
+	fk := fclient.NewClientBuilder().WithObjects(fs).Build()
+	kp := esv1beta1.KubernetesProvider{AuthRef: &v1.SecretKeySelector{Name: "remote-cluster", Key: "kubeconfig"}}
+	bc := BaseClient{kube: fk, store: &kp, namespace: "default", storeKind: esv1beta1.SecretStoreKind}
+
+	config, err := bc.getConfig(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Host != "https://remote.example.com:6443" || config.BearerToken != "remote-token" {
+		t.Errorf("unexpected config: host %s, token %s", config.Host, config.BearerToken)
+	}
+
+	kp.AuthRef.Key = "broken"
+	if _, err := bc.getConfig(context.Background()); !ErrorContains(err, "could not parse kubeconfig") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestKubernetesServiceAccountAuth(t *testing.T) {
+	clientset := kfake.NewSimpleClientset()
+	clientset.PrependReactor("create", "serviceaccounts", func(action ktesting.Action) (bool, runtime.Object, error) {
+		create := action.(ktesting.CreateAction)
+		if action.GetSubresource() != "token" || action.GetNamespace() != "default" {
+			return true, nil, errors.New(errSomethingWentWrong)
+		}
+		tr := create.GetObject().(*authenticationv1.TokenRequest)
+		tr.Status.Token = "sa-token"
+		return true, tr, nil
+	})
+	defer func(orig func() (typedcorev1.ServiceAccountsGetter, error)) { newServiceAccountsClient = orig }(newServiceAccountsClient)
+	newServiceAccountsClient = func() (typedcorev1.ServiceAccountsGetter, error) {
+		return clientset.CoreV1(), nil
+	}
+
+	kp := esv1beta1.KubernetesProvider{
+		Server: esv1beta1.KubernetesServer{CABundle: []byte("ca")},
+		Auth: &esv1beta1.KubernetesAuth{
+			ServiceAccount: &esv1beta1.ServiceAccountAuth{
+				ServiceAccountRef: v1.ServiceAccountSelector{Name: "remote-reader"},
+			},
+		},
+	}
+	bc := BaseClient{kube: fclient.NewClientBuilder().Build(), store: &kp, namespace: "default", storeKind: esv1beta1.SecretStoreKind}
+	if err := bc.setAuth(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(bc.BearerToken) != "sa-token" {
+		t.Errorf("unexpected token: %s", string(bc.BearerToken))
+	}
+
+	bc.storeKind = esv1beta1.ClusterSecretStoreKind
+	if err := bc.setAuth(context.Background()); !ErrorContains(err, errInvalidClusterStoreMissingNamespace) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStoreAuthRefAndServiceAccount(t *testing.T) {
+	p := ProviderKubernetes{}
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Kubernetes: &esv1beta1.KubernetesProvider{
+					AuthRef: &v1.SecretKeySelector{Name: "remote-cluster", Key: "kubeconfig"},
+				},
+			},
+		},
+	}
+	if err := p.ValidateStore(store); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	store.Spec.Provider.Kubernetes.AuthRef.Key = ""
+	if err := p.ValidateStore(store); !ErrorContains(err, "AuthRef.Key cannot be empty") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	store.Spec.Provider.Kubernetes.AuthRef.Key = "kubeconfig"
+	store.Spec.Provider.Kubernetes.Auth = &esv1beta1.KubernetesAuth{Token: &esv1beta1.TokenAuth{}}
+	if err := p.ValidateStore(store); !ErrorContains(err, "only one of auth or authRef is allowed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	store.Spec.Provider.Kubernetes.AuthRef = nil
+	store.Spec.Provider.Kubernetes.Server.CABundle = []byte("ca")
+	store.Spec.Provider.Kubernetes.Auth = &esv1beta1.KubernetesAuth{
+		ServiceAccount: &esv1beta1.ServiceAccountAuth{},
+	}
+	if err := p.ValidateStore(store); !ErrorContains(err, "ServiceAccount.Name cannot be empty") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	store.Spec.Provider.Kubernetes.Auth.ServiceAccount.ServiceAccountRef.Name = "remote-reader"
+	if err := p.ValidateStore(store); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -16,11 +16,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 
-	"github.com/oracle/oci-go-sdk/v56/common"
-	"github.com/oracle/oci-go-sdk/v56/common/auth"
-	"github.com/oracle/oci-go-sdk/v56/secrets"
-	"github.com/tidwall/gjson"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/secrets"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,6 +39,7 @@ const (
 	errOracleClient                          = "cannot setup new oracle client: %w"
 	errORACLECredSecretName                  = "invalid oracle SecretStore resource: missing oracle APIKey"
 	errUninitalizedOracleProvider            = "provider oracle is not initialized"
+	errInvalidStage                          = "invalid version %s: must be one of %s"
 	errInvalidClusterStoreMissingSKNamespace = "invalid ClusterStore, missing namespace"
 	errFetchSAKSecret                        = "could not fetch SecretAccessKey secret: %w"
 	errMissingPK                             = "missing PrivateKey"
@@ -50,6 +51,9 @@ const (
 	errJSONSecretUnmarshal                   = "unable to unmarshal secret: %w"
 	errMissingKey                            = "missing Key in secret: %s"
 	errUnexpectedContent                     = "unexpected secret bundle content"
+	errMissingAuth                           = "missing auth for principal type %s"
+	errAuthNotAllowed                        = "auth can not be set for principal type %s"
+	errUnknownPrincipalType                  = "unknown principal type %s"
 )
 
 type VaultManagementService struct {
@@ -95,13 +99,11 @@ func (vms *VaultManagementService) GetSecret(ctx context.Context, ref esv1beta1.
 		return payload, nil
 	}
 
-	val := gjson.Get(string(payload), ref.Property)
-
-	if !val.Exists() {
+	val, ok := utils.GetProperty(payload, ref.Property)
+	if !ok {
 		return nil, fmt.Errorf(errMissingKey, ref.Key)
 	}
-
-	return []byte(val.String()), nil
+	return val, nil
 }
 
 func (vms *VaultManagementService) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
@@ -138,9 +140,15 @@ func (vms *VaultManagementService) NewClient(ctx context.Context, store esv1beta
 		err                   error
 		configurationProvider common.ConfigurationProvider
 	)
-	if oracleSpec.Auth == nil {
+	switch principalType(oracleSpec) {
+	case esv1beta1.InstancePrincipal:
 		configurationProvider, err = auth.InstancePrincipalConfigurationProvider()
-	} else {
+	case esv1beta1.WorkloadPrincipal:
+		configurationProvider, err = auth.OkeWorkloadIdentityConfigurationProvider()
+	default:
+		if oracleSpec.Auth == nil {
+			return nil, fmt.Errorf(errMissingAuth, esv1beta1.UserPrincipal)
+		}
 		configurationProvider, err = getUserAuthConfigurationProvider(ctx, kube, oracleSpec, namespace, store.GetObjectKind().GroupVersionKind().Kind, oracleSpec.Region)
 	}
 	if err != nil {
@@ -223,10 +231,43 @@ func (vms *VaultManagementService) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts a stage of the secret bundle.
+func (vms *VaultManagementService) ValidateVersion(version string) error {
+	if _, ok := secrets.GetMappingGetSecretBundleByNameStageEnum(version); !ok {
+		return fmt.Errorf(errInvalidStage, version, strings.Join(secrets.GetGetSecretBundleByNameStageEnumStringValues(), ", "))
+	}
+	return nil
+}
+
 func (vms *VaultManagementService) ValidateStore(store esv1beta1.GenericStore) error {
+	oracleSpec := store.GetSpec().Provider.Oracle
+	switch pt := principalType(oracleSpec); pt {
+	case esv1beta1.UserPrincipal:
+		if oracleSpec.Auth == nil {
+			return fmt.Errorf(errMissingAuth, pt)
+		}
+	case esv1beta1.InstancePrincipal, esv1beta1.WorkloadPrincipal:
+		if oracleSpec.Auth != nil {
+			return fmt.Errorf(errAuthNotAllowed, pt)
+		}
+	default:
+		return fmt.Errorf(errUnknownPrincipalType, pt)
+	}
 	return nil
 }
 
+// principalType returns the principal type of the store, by default
+// the user principal if auth is set and the instance principal otherwise.
+func principalType(store *esv1beta1.OracleProvider) esv1beta1.OraclePrincipalType {
+	if store.PrincipalType != "" {
+		return store.PrincipalType
+	}
+	if store.Auth != nil {
+		return esv1beta1.UserPrincipal
+	}
+	return esv1beta1.InstancePrincipal
+}
+
 func init() {
 	esv1beta1.Register(&VaultManagementService{}, &esv1beta1.SecretStoreProvider{
 		Oracle: &esv1beta1.OracleProvider{},
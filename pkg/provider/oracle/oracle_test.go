@@ -19,7 +19,7 @@ import (
 	"strings"
 	"testing"
 
-	secrets "github.com/oracle/oci-go-sdk/v56/secrets"
+	secrets "github.com/oracle/oci-go-sdk/v65/secrets"
 	utilpointer "k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
@@ -177,3 +177,53 @@ func ErrorContains(out error, want string) bool {
 	}
 	return strings.Contains(out.Error(), want)
 }
+
+func TestValidateStore(t *testing.T) {
+	userAuth := &esv1beta1.OracleAuth{Tenancy: "tenancy", User: "user"}
+	tests := map[string]struct {
+		auth          *esv1beta1.OracleAuth
+		principalType esv1beta1.OraclePrincipalType
+		expectError   string
+	}{
+		"user principal from auth": {
+			auth: userAuth,
+		},
+		"instance principal without auth": {},
+		"user principal without auth": {
+			principalType: esv1beta1.UserPrincipal,
+			expectError:   "missing auth for principal type UserPrincipal",
+		},
+		"workload principal": {
+			principalType: esv1beta1.WorkloadPrincipal,
+		},
+		"workload principal with auth": {
+			auth:          userAuth,
+			principalType: esv1beta1.WorkloadPrincipal,
+			expectError:   "auth can not be set for principal type Workload",
+		},
+		"unknown principal type": {
+			principalType: "Unknown",
+			expectError:   "unknown principal type Unknown",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := &esv1beta1.SecretStore{
+				Spec: esv1beta1.SecretStoreSpec{
+					Provider: &esv1beta1.SecretStoreProvider{
+						Oracle: &esv1beta1.OracleProvider{
+							Region:        "eu-frankfurt-1",
+							Vault:         "test-vault",
+							Auth:          tc.auth,
+							PrincipalType: tc.principalType,
+						},
+					},
+				},
+			}
+			err := (&VaultManagementService{}).ValidateStore(store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+
+	// nolint:staticcheck
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/external-secrets/external-secrets/pkg/provider/internal/jsonapi"
+)
+
+const (
+	headerAuthToken     = "X-GPGAuth-User-Auth-Token"
+	headerAuthenticated = "X-GPGAuth-Authenticated"
+
+	errAPIDecode   = "unable to decode passbolt api response: %w"
+	errLogin       = "unable to login to passbolt: %w"
+	errNoAuthToken = "server did not return a GPGAuth token"
+	errNotAuthed   = "server did not accept the GPGAuth token"
+)
+
+type resource struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Username    string `json:"username"`
+	URI         string `json:"uri"`
+	Description string `json:"description"`
+}
+
+type secretResponse struct {
+	ResourceID string `json:"resource_id"`
+	Data       string `json:"data"`
+}
+
+// envelope is the wrapper of all Passbolt API responses.
+type envelope struct {
+	Header struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"header"`
+	Body json.RawMessage `json:"body"`
+}
+
+type gpgAuthRequest struct {
+	Data struct {
+		GPGAuth struct {
+			KeyID           string `json:"keyid"`
+			UserTokenResult string `json:"user_token_result,omitempty"`
+		} `json:"gpg_auth"`
+	} `json:"data"`
+}
+
+// apiClient talks to the Passbolt REST API with a GPGAuth session.
+// see: https://help.passbolt.com/api
+type apiClient struct {
+	baseURL string
+	api     *jsonapi.Client
+	keyring openpgp.EntityList
+}
+
+func newAPIClient(host string, keyring openpgp.EntityList) (*apiClient, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(host, "/"),
+		api: &jsonapi.Client{
+			HTTP:         &http.Client{Timeout: 30 * time.Second, Jar: jar},
+			Name:         "passbolt api",
+			ErrorMessage: envelopeMessage,
+		},
+		keyring: keyring,
+	}, nil
+}
+
+// login runs the GPGAuth handshake: the server encrypts a nonce for the key
+// of the user, which is decrypted and sent back to obtain a session cookie.
+// see: https://help.passbolt.com/api/authentication
+func (c *apiClient) login(ctx context.Context) error {
+	var req gpgAuthRequest
+	req.Data.GPGAuth.KeyID = fingerprint(c.keyring)
+	resp, err := c.postLogin(ctx, req)
+	if err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	encToken, err := url.QueryUnescape(resp.Header.Get(headerAuthToken))
+	if err != nil || encToken == "" {
+		return fmt.Errorf(errLogin, fmt.Errorf(errNoAuthToken))
+	}
+	token, err := decryptMessage(c.keyring, strings.ReplaceAll(encToken, `\ `, " "))
+	if err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	req.Data.GPGAuth.UserTokenResult = string(token)
+	resp, err = c.postLogin(ctx, req)
+	if err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get(headerAuthenticated) != "true" {
+		return fmt.Errorf(errLogin, fmt.Errorf(errNotAuthed))
+	}
+	return nil
+}
+
+func (c *apiClient) postLogin(ctx context.Context, body gpgAuthRequest) (*http.Response, error) {
+	req, err := c.api.NewRequest(ctx, http.MethodPost, c.baseURL+"/auth/login.json", body)
+	if err != nil {
+		return nil, err
+	}
+	// the handshake is carried by the headers of the response.
+	resp, err := c.api.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+func (c *apiClient) logout(ctx context.Context) error {
+	var out interface{}
+	return c.get(ctx, "/auth/logout.json", &out)
+}
+
+func (c *apiClient) listResources(ctx context.Context) ([]resource, error) {
+	var out []resource
+	if err := c.get(ctx, "/resources.json", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) getResource(ctx context.Context, id string) (*resource, error) {
+	var out resource
+	if err := c.read(ctx, "/resources/"+url.PathEscape(id)+".json", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// getSecret returns the armored secret of a resource, encrypted for the user.
+func (c *apiClient) getSecret(ctx context.Context, resourceID string) (string, error) {
+	var out secretResponse
+	if err := c.read(ctx, "/secrets/resource/"+url.PathEscape(resourceID)+".json", &out); err != nil {
+		return "", err
+	}
+	return out.Data, nil
+}
+
+// get sends a GET request and decodes the body of the response envelope into out.
+func (c *apiClient) get(ctx context.Context, path string, out interface{}) error {
+	return c.send(ctx, path, c.api.Do, out)
+}
+
+// read is get for a resource or its secret, a missing resource is reported as NoSecretErr.
+func (c *apiClient) read(ctx context.Context, path string, out interface{}) error {
+	return c.send(ctx, path, c.api.ReadSecret, out)
+}
+
+func (c *apiClient) send(ctx context.Context, path string, do func(*http.Request, interface{}) error, out interface{}) error {
+	req, err := c.api.NewRequest(ctx, http.MethodGet, c.baseURL+path+"?api-version=v2", nil)
+	if err != nil {
+		return err
+	}
+	var env envelope
+	if err := do(req, &env); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(env.Body, out); err != nil {
+		return fmt.Errorf(errAPIDecode, err)
+	}
+	return nil
+}
+
+// envelopeMessage returns the message of the envelope of an error response.
+func envelopeMessage(body []byte) string {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return ""
+	}
+	return env.Header.Message
+}
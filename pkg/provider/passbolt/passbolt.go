@@ -0,0 +1,289 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	propertyPassword    = "password"
+	propertyDescription = "description"
+	propertyUsername    = "username"
+	propertyURI         = "uri"
+	propertyName        = "name"
+
+	errMissingStoreSpec     = "missing store provider passbolt"
+	errMissingHost          = "missing host"
+	errInvalidHost          = "invalid host %s: must be an absolute https url"
+	errMissingSecretRefName = "missing name in auth.secretRef.%s"
+	errMissingSecretRefKey  = "missing key in auth.secretRef.%s"
+	errInvalidSecretRef     = "invalid auth.secretRef.%s: %w"
+	errFetchCredentials     = "could not fetch credentials secret %s: %w"
+	errMissingCredentials   = "missing %s in secret %s"
+	errUninitalizedClient   = "provider passbolt is not initialized"
+	errAmbiguousName        = "found %d resources named %s, use the resource id instead"
+	errUnknownProperty      = "unknown property %s: must be one of password, description, username, uri or name"
+	errFindNotImplemented   = "find by tags or path is not supported by passbolt"
+)
+
+// resource ids are UUIDs, anything else is looked up by name.
+var resourceIDRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Passbolt reads resources shared with the user of the store.
+type Passbolt struct {
+	client *apiClient
+}
+
+// secretData is the decrypted secret of a resource with the
+// password-and-description resource type.
+type secretData struct {
+	Password    string `json:"password"`
+	Description string `json:"description"`
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Passbolt: &esv1beta1.PassboltProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.PassboltProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Passbolt == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Passbolt, nil
+}
+
+// NewClient logs in to Passbolt with the private key referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	privateKey, err := secretKeyRef(ctx, kube, storeKind, namespace, "privateKey", provider.Auth.SecretRef.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := secretKeyRef(ctx, kube, storeKind, namespace, "passphrase", provider.Auth.SecretRef.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := loadKeyRing(privateKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newAPIClient(provider.Host, keyring)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.login(ctx); err != nil {
+		return nil, err
+	}
+	return &Passbolt{client: client}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := string(secret.Data[ref.Key])
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.Host == "" {
+		return fmt.Errorf(errMissingHost)
+	}
+	if u, err := url.Parse(provider.Host); err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf(errInvalidHost, provider.Host)
+	}
+	if err := validateSecretRef(store, "privateKey", provider.Auth.SecretRef.PrivateKey); err != nil {
+		return err
+	}
+	return validateSecretRef(store, "passphrase", provider.Auth.SecretRef.Passphrase)
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingSecretRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingSecretRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidSecretRef, name, err)
+	}
+	return nil
+}
+
+// GetSecret returns a field of the resource referenced by ref.Key, which is either
+// the id or the name of the resource. ref.Property selects the field and defaults to password.
+func (p *Passbolt) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	fields, err := p.GetSecretMap(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	property := ref.Property
+	if property == "" {
+		property = propertyPassword
+	}
+	val, ok := fields[property]
+	if !ok {
+		return nil, fmt.Errorf(errUnknownProperty, property)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns all fields of a resource, including the decrypted password and description.
+func (p *Passbolt) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	res, err := p.findResource(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	return p.resourceFields(ctx, res)
+}
+
+func (p *Passbolt) findResource(ctx context.Context, key string) (*resource, error) {
+	if resourceIDRegexp.MatchString(key) {
+		return p.client.getResource(ctx, key)
+	}
+	resources, err := p.client.listResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matches []resource
+	for _, res := range resources {
+		if res.Name == key {
+			matches = append(matches, res)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, esv1beta1.NoSecretErr
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf(errAmbiguousName, len(matches), key)
+	}
+}
+
+func (p *Passbolt) resourceFields(ctx context.Context, res *resource) (map[string][]byte, error) {
+	armored, err := p.client.getSecret(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := decryptMessage(p.client.keyring, armored)
+	if err != nil {
+		return nil, err
+	}
+	// password-string resources hold the plain password, newer resource types hold a JSON object.
+	secret := secretData{Password: string(plain), Description: res.Description}
+	var data secretData
+	if err := json.Unmarshal(plain, &data); err == nil {
+		secret = data
+	}
+	return map[string][]byte{
+		propertyPassword:    []byte(secret.Password),
+		propertyDescription: []byte(secret.Description),
+		propertyUsername:    []byte(res.Username),
+		propertyURI:         []byte(res.URI),
+		propertyName:        []byte(res.Name),
+	}, nil
+}
+
+// GetAllSecrets returns the passwords of all resources whose names match ref.Name.
+func (p *Passbolt) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 || ref.Path != nil {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	resources, err := p.client.listResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string][]byte)
+	for i := range resources {
+		if matcher != nil && !matcher.MatchName(resources[i].Name) {
+			continue
+		}
+		fields, err := p.resourceFields(ctx, &resources[i])
+		if err != nil {
+			return nil, err
+		}
+		secrets[resources[i].Name] = fields[propertyPassword]
+	}
+	return secrets, nil
+}
+
+// Close ends the Passbolt session.
+func (p *Passbolt) Close(ctx context.Context) error {
+	if p.client == nil {
+		return nil
+	}
+	return p.client.logout(ctx)
+}
+
+func (p *Passbolt) Validate() error {
+	return nil
+}
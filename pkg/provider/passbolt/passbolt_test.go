@@ -0,0 +1,333 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passbolt
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	// nolint:staticcheck
+	"golang.org/x/crypto/openpgp"
+	// nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor"
+	// nolint:staticcheck
+	"golang.org/x/crypto/openpgp/packet"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	dbResourceID  = "8e3874ae-4b40-590b-968a-418f704b9d9a"
+	apiResourceID = "f3a6e9e1-1f4d-4b8a-9d3a-2b7c3f1e9a10"
+	authNonce     = "gpgauthv1.3.0|36|10e2074b-f610-42ad-9d3e-3c496d1e6e8d|gpgauthv1.3.0"
+)
+
+var testResources = []resource{
+	{ID: dbResourceID, Name: "db", Username: "admin", URI: "postgres://db", Description: "legacy description"},
+	{ID: apiResourceID, Name: "api", Username: "svc"},
+}
+
+var testSecrets = map[string]string{
+	dbResourceID:  `{"password":"hunter2","description":"production database"}`,
+	apiResourceID: "s3cr3t",
+}
+
+func newTestEntity(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("eso", "", "eso@example.com", &packet.Config{DefaultHash: crypto.SHA256})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	return entity, buf.String()
+}
+
+func encryptFor(t *testing.T, entity *openpgp.Entity, plain string) string {
+	var buf bytes.Buffer
+	aw, err := armor.Encode(&buf, "PGP MESSAGE", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw, err := openpgp.Encrypt(aw, openpgp.EntityList{entity}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(pw, plain)
+	pw.Close()
+	aw.Close()
+	return buf.String()
+}
+
+func writeBody(w http.ResponseWriter, body interface{}) {
+	raw, _ := json.Marshal(body)
+	_ = json.NewEncoder(w).Encode(envelope{Body: raw})
+}
+
+func newFakePassbolt(t *testing.T, entity *openpgp.Entity) *httptest.Server {
+	keyID := strings.ToUpper(fmt.Sprintf("%x", entity.PrimaryKey.Fingerprint))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/login.json" {
+			var req gpgAuthRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			switch {
+			case req.Data.GPGAuth.KeyID != keyID:
+				w.WriteHeader(http.StatusNotFound)
+			case req.Data.GPGAuth.UserTokenResult == "":
+				w.Header().Set(headerAuthToken, url.QueryEscape(encryptFor(t, entity, authNonce)))
+				w.WriteHeader(http.StatusForbidden)
+			case req.Data.GPGAuth.UserTokenResult == authNonce:
+				http.SetCookie(w, &http.Cookie{Name: "passbolt_session", Value: "session", Path: "/"})
+				w.Header().Set(headerAuthenticated, "true")
+			default:
+				w.WriteHeader(http.StatusForbidden)
+			}
+			return
+		}
+		if c, err := r.Cookie("passbolt_session"); err != nil || c.Value != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"header": map[string]string{"message": "You need to login to access this location."}})
+			return
+		}
+		switch {
+		case r.URL.Path == "/auth/logout.json":
+			http.SetCookie(w, &http.Cookie{Name: "passbolt_session", Path: "/", MaxAge: -1})
+			writeBody(w, nil)
+		case r.URL.Path == "/resources.json":
+			writeBody(w, testResources)
+		case strings.HasPrefix(r.URL.Path, "/resources/"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/resources/"), ".json")
+			for _, res := range testResources {
+				if res.ID == id {
+					writeBody(w, res)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/secrets/resource/"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/secrets/resource/"), ".json")
+			if plain, ok := testSecrets[id]; ok {
+				writeBody(w, secretResponse{ResourceID: id, Data: encryptFor(t, entity, plain)})
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func makeStore(host string, privateKey, passphrase esmeta.SecretKeySelector) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Passbolt: &esv1beta1.PassboltProvider{
+					Host: host,
+					Auth: esv1beta1.PassboltAuth{
+						SecretRef: esv1beta1.PassboltAuthSecretRef{
+							PrivateKey: privateKey,
+							Passphrase: passphrase,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, serverEntity *openpgp.Entity, privateKey string) (esv1beta1.SecretsClient, error) {
+	srv := newFakePassbolt(t, serverEntity)
+	t.Cleanup(srv.Close)
+	// the generated test keys are not encrypted, so any passphrase is accepted
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "passbolt", Namespace: "default"},
+		Data: map[string][]byte{
+			"privateKey": []byte(privateKey),
+			"passphrase": []byte("passphrase"),
+		},
+	}).Build()
+	store := makeStore(srv.URL,
+		esmeta.SecretKeySelector{Name: "passbolt", Key: "privateKey"},
+		esmeta.SecretKeySelector{Name: "passbolt", Key: "passphrase"})
+	return (&Provider{}).NewClient(context.Background(), store, kube, "default")
+}
+
+func newLoggedInClient(t *testing.T) esv1beta1.SecretsClient {
+	entity, privateKey := newTestEntity(t)
+	c, err := newTestClient(t, entity, privateKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestPassboltGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"by id": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: dbResourceID},
+			want: "hunter2",
+		},
+		"by name": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "api"},
+			want: "s3cr3t",
+		},
+		"encrypted description": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "description"},
+			want: "production database",
+		},
+		"username": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "username"},
+			want: "admin",
+		},
+		"unknown property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "notes"},
+			expectError: "unknown property notes",
+		},
+		"missing name": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+		"missing id": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "00000000-0000-0000-0000-000000000000"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c := newLoggedInClient(t)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestPassboltGetSecretMapAndAll(t *testing.T) {
+	c := newLoggedInClient(t)
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"password":    []byte("s3cr3t"),
+		"description": []byte(""),
+		"username":    []byte("svc"),
+		"uri":         []byte(""),
+		"name":        []byte("api"),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+
+	all, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^d"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(all, map[string][]byte{"db": []byte("hunter2")}) {
+		t.Errorf("unexpected secrets: %#v", all)
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, err = c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	if !ErrorContains(err, "passbolt api returned status 403: You need to login") {
+		t.Errorf("unexpected error after logout: %v", err)
+	}
+}
+
+func TestPassboltLoginFailure(t *testing.T) {
+	entity, _ := newTestEntity(t)
+	_, otherKey := newTestEntity(t)
+	_, err := newTestClient(t, entity, otherKey)
+	if !ErrorContains(err, "unable to login to passbolt") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	privateKey := esmeta.SecretKeySelector{Name: "passbolt", Key: "privateKey"}
+	passphrase := esmeta.SecretKeySelector{Name: "passbolt", Key: "passphrase"}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("https://passbolt.example.com", privateKey, passphrase),
+		},
+		"missing host": {
+			store:       makeStore("", privateKey, passphrase),
+			expectError: errMissingHost,
+		},
+		"plain http host": {
+			store:       makeStore("http://passbolt.example.com", privateKey, passphrase),
+			expectError: "invalid host http://passbolt.example.com",
+		},
+		"missing passphrase key": {
+			store:       makeStore("https://passbolt.example.com", privateKey, esmeta.SecretKeySelector{Name: "passbolt"}),
+			expectError: "missing key in auth.secretRef.passphrase",
+		},
+		"namespace not allowed": {
+			store:       makeStore("https://passbolt.example.com", esmeta.SecretKeySelector{Name: "passbolt", Key: "privateKey", Namespace: pointer.StringPtr("foo")}, passphrase),
+			expectError: "invalid auth.secretRef.privateKey: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
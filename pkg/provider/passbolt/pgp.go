@@ -0,0 +1,83 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passbolt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	// nolint:staticcheck
+	"golang.org/x/crypto/openpgp"
+	// nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+const (
+	errReadPrivateKey   = "unable to read private key: %w"
+	errNoPrivateKey     = "private key is missing"
+	errDecryptKey       = "unable to decrypt private key with passphrase: %w"
+	errDecryptMessage   = "unable to decrypt message: %w"
+	errDecodeArmorBlock = "unable to decode armored message: %w"
+)
+
+// loadKeyRing reads an armored private key and decrypts it with the passphrase.
+func loadKeyRing(privateKey, passphrase string) (openpgp.EntityList, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf(errReadPrivateKey, err)
+	}
+	if len(keyring) == 0 || keyring[0].PrivateKey == nil {
+		return nil, fmt.Errorf(errNoPrivateKey)
+	}
+	for _, entity := range keyring {
+		if entity.PrivateKey != nil && entity.PrivateKey.Encrypted {
+			if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf(errDecryptKey, err)
+			}
+		}
+		for _, subkey := range entity.Subkeys {
+			if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+				if err := subkey.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+					return nil, fmt.Errorf(errDecryptKey, err)
+				}
+			}
+		}
+	}
+	return keyring, nil
+}
+
+// fingerprint returns the fingerprint of the primary key as used by GPGAuth.
+func fingerprint(keyring openpgp.EntityList) string {
+	return strings.ToUpper(fmt.Sprintf("%x", keyring[0].PrimaryKey.Fingerprint))
+}
+
+// decryptMessage decrypts an armored message encrypted for the keyring.
+func decryptMessage(keyring openpgp.EntityList, message string) ([]byte, error) {
+	block, err := armor.Decode(bytes.NewReader([]byte(message)))
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeArmorBlock, err)
+	}
+	md, err := openpgp.ReadMessage(block.Body, keyring, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptMessage, err)
+	}
+	data, err := io.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptMessage, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,127 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	defaultAPIURL = "https://api.pulumi.com"
+	// openDuration is how long an opened environment session stays valid.
+	openDuration = "5m"
+
+	errAPIRequest  = "pulumi api request failed: %w"
+	errAPIResponse = "pulumi api returned status %d: %s"
+	errAPIDecode   = "unable to decode pulumi api response: %w"
+	errOpenEnv     = "unable to open environment %s: %w"
+)
+
+type openResponse struct {
+	ID string `json:"id"`
+}
+
+// value is a node of an evaluated ESC environment.
+// Objects and arrays hold nested values.
+type value struct {
+	Value   json.RawMessage `json:"value"`
+	Secret  bool            `json:"secret,omitempty"`
+	Unknown bool            `json:"unknown,omitempty"`
+}
+
+type environmentResponse struct {
+	Properties map[string]value `json:"properties"`
+}
+
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiClient talks to the Pulumi ESC REST API with an access token.
+// see: https://www.pulumi.com/docs/pulumi-cloud/cloud-rest-api/#environments
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newAPIClient(apiURL, token string) *apiClient {
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(apiURL, "/") + "/api/esc/environments",
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func envPath(org, project, env string) string {
+	return "/" + url.PathEscape(org) + "/" + url.PathEscape(project) + "/" + url.PathEscape(env)
+}
+
+// openEnvironment evaluates the environment, resolving all imports,
+// dynamic provider values and secrets, and returns the values.
+func (c *apiClient) openEnvironment(ctx context.Context, org, project, env string) (map[string]value, error) {
+	p := envPath(org, project, env)
+	params := url.Values{}
+	params.Set("duration", openDuration)
+	var session openResponse
+	if err := c.do(ctx, http.MethodPost, p+"/open?"+params.Encode(), &session); err != nil {
+		return nil, fmt.Errorf(errOpenEnv, env, err)
+	}
+	var out environmentResponse
+	if err := c.do(ctx, http.MethodGet, p+"/open/"+url.PathEscape(session.ID), &out); err != nil {
+		return nil, fmt.Errorf(errOpenEnv, env, err)
+	}
+	return out.Properties, nil
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf(errAPIRequest, err)
+	}
+	defer resp.Body.Close()
+
+	// a missing environment means all of its values are missing.
+	if resp.StatusCode == http.StatusNotFound {
+		return esv1beta1.NoSecretErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf(errAPIResponse, resp.StatusCode, errResp.Message)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf(errAPIDecode, err)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultProject = "default"
+
+	errMissingStoreSpec    = "missing store provider pulumi"
+	errMissingOrganization = "missing organization"
+	errMissingEnvironment  = "missing environment"
+	errMissingTokenName    = "missing auth.secretRef.accessToken.name"
+	errMissingTokenKey     = "missing auth.secretRef.accessToken.key"
+	errInvalidTokenRef     = "invalid auth.secretRef.accessToken: %w"
+	errFetchTokenSecret    = "could not fetch accessToken secret: %w"
+	errMissingToken        = "missing accessToken in secret %s"
+	errUninitalizedClient  = "provider pulumi is not initialized"
+	errDecodeValue         = "unable to decode value of environment %s: %w"
+	errUnknownValue        = "value %s of environment %s is unknown"
+	errPropertyNotFound    = "property %s does not exist in value %s"
+	errJSONSecretUnmarshal = "unable to unmarshal value %s: %w"
+	errFindNotImplemented  = "find by tags is not supported by pulumi"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Pulumi reads the values of a single ESC environment.
+type Pulumi struct {
+	client       *apiClient
+	organization string
+	project      string
+	environment  string
+	// values of the opened environment, evaluated once per client.
+	values []byte
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Pulumi: &esv1beta1.PulumiProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.PulumiProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Pulumi == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Pulumi, nil
+}
+
+// NewClient constructs a Pulumi ESC client using the access token referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	tokenRef := provider.Auth.SecretRef.AccessToken
+	objectKey := types.NamespacedName{
+		Name:      tokenRef.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind && tokenRef.Namespace != nil {
+		objectKey.Namespace = *tokenRef.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errFetchTokenSecret, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[tokenRef.Key]))
+	if token == "" {
+		return nil, fmt.Errorf(errMissingToken, tokenRef.Name)
+	}
+	project := provider.Project
+	if project == "" {
+		project = defaultProject
+	}
+	return &Pulumi{
+		client:       newAPIClient(provider.APIURL, token),
+		organization: provider.Organization,
+		project:      project,
+		environment:  provider.Environment,
+	}, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.Organization == "" {
+		return fmt.Errorf(errMissingOrganization)
+	}
+	if provider.Environment == "" {
+		return fmt.Errorf(errMissingEnvironment)
+	}
+	tokenRef := provider.Auth.SecretRef.AccessToken
+	if tokenRef.Name == "" {
+		return fmt.Errorf(errMissingTokenName)
+	}
+	if tokenRef.Key == "" {
+		return fmt.Errorf(errMissingTokenKey)
+	}
+	if err := utils.ValidateSecretSelector(store, tokenRef); err != nil {
+		return fmt.Errorf(errInvalidTokenRef, err)
+	}
+	return nil
+}
+
+// open evaluates the environment and returns its values as plain JSON object.
+func (p *Pulumi) open(ctx context.Context) ([]byte, error) {
+	if p.values != nil {
+		return p.values, nil
+	}
+	properties, err := p.client.openEnvironment(ctx, p.organization, p.project, p.environment)
+	if err != nil {
+		return nil, err
+	}
+	plain := make(map[string]interface{}, len(properties))
+	for k, v := range properties {
+		if plain[k], err = v.plain(); err != nil {
+			return nil, fmt.Errorf(errDecodeValue, p.environment, err)
+		}
+	}
+	if p.values, err = json.Marshal(plain); err != nil {
+		return nil, fmt.Errorf(errDecodeValue, p.environment, err)
+	}
+	return p.values, nil
+}
+
+// plain strips the ESC metadata of a value and its nested values.
+// Unknown values are returned as nil.
+func (v value) plain() (interface{}, error) {
+	if v.Unknown || len(v.Value) == 0 {
+		return nil, nil
+	}
+	var object map[string]value
+	if err := json.Unmarshal(v.Value, &object); err == nil {
+		out := make(map[string]interface{}, len(object))
+		for k, nested := range object {
+			if out[k], err = nested.plain(); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	var array []value
+	if err := json.Unmarshal(v.Value, &array); err == nil {
+		out := make([]interface{}, len(array))
+		for i, nested := range array {
+			if out[i], err = nested.plain(); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	var primitive interface{}
+	if err := json.Unmarshal(v.Value, &primitive); err != nil {
+		return nil, err
+	}
+	return primitive, nil
+}
+
+// GetSecret returns a value of the environment.
+// ref.Key is the path of the value below values, e.g. app.database.
+// String values are returned as-is, other values are JSON encoded.
+func (p *Pulumi) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	values, err := p.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	val := gjson.GetBytes(values, ref.Key)
+	if !val.Exists() {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if val.Type == gjson.Null {
+		return nil, fmt.Errorf(errUnknownValue, ref.Key, p.environment)
+	}
+	if ref.Property == "" {
+		return []byte(val.String()), nil
+	}
+	prop := val.Get(ref.Property)
+	if !prop.Exists() {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return []byte(prop.String()), nil
+}
+
+// GetSecretMap returns the key/value pairs of an object value of the environment.
+func (p *Pulumi) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := p.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	return rawToSecretData(kv), nil
+}
+
+// GetAllSecrets returns the values of the environment whose names match ref.Name.
+// ref.Path selects a nested object value, e.g. app.
+func (p *Pulumi) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	values, err := p.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Path != nil {
+		val := gjson.GetBytes(values, *ref.Path)
+		if !val.IsObject() {
+			return nil, esv1beta1.NoSecretErr
+		}
+		values = []byte(val.Raw)
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(values, &kv); err != nil {
+		return nil, fmt.Errorf(errDecodeValue, p.environment, err)
+	}
+	for k, v := range kv {
+		if (matcher != nil && !matcher.MatchName(k)) || string(v) == "null" {
+			delete(kv, k)
+		}
+	}
+	return rawToSecretData(kv), nil
+}
+
+func rawToSecretData(kv map[string]json.RawMessage) map[string][]byte {
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData
+}
+
+func (p *Pulumi) Close(ctx context.Context) error {
+	return nil
+}
+
+func (p *Pulumi) Validate() error {
+	return nil
+}
@@ -0,0 +1,268 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// testEnvironment is an opened environment as returned by the ESC API.
+const testEnvironment = `{
+  "properties": {
+    "app": {"value": {
+      "user": {"value": "admin"},
+      "password": {"value": "hunter2", "secret": true},
+      "port": {"value": 5432}
+    }},
+    "hosts": {"value": [{"value": "a"}, {"value": "b"}]},
+    "token": {"value": "t0k3n", "secret": true},
+    "pending": {"unknown": true}
+  }
+}`
+
+func newFakeESC(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token pul-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code":401,"message":"Unauthorized: No credentials provided or are invalid."}`)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/esc/environments/acme/default/dev/open":
+			if r.URL.Query().Get("duration") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"id":"1234"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/esc/environments/acme/default/dev/open/1234":
+			fmt.Fprint(w, testEnvironment)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":404,"message":"Environment not found"}`)
+		}
+	}))
+}
+
+func makeStore(apiURL, environment string) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Pulumi: &esv1beta1.PulumiProvider{
+					Organization: "acme",
+					Environment:  environment,
+					APIURL:       apiURL,
+					Auth: esv1beta1.PulumiAuth{
+						SecretRef: esv1beta1.PulumiAuthSecretRef{
+							AccessToken: esmeta.SecretKeySelector{Name: "pulumi", Key: "token"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, environment, token string) esv1beta1.SecretsClient {
+	srv := newFakeESC(t)
+	t.Cleanup(srv.Close)
+	store := makeStore(srv.URL, environment)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pulumi", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte(token)},
+	}).Build()
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestPulumiGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"string": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "token"},
+			want: "t0k3n",
+		},
+		"nested path": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "app.password"},
+			want: "hunter2",
+		},
+		"property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "app", Property: "port"},
+			want: "5432",
+		},
+		"array": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "hosts"},
+			want: `["a","b"]`,
+		},
+		"unknown": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "pending"},
+			expectError: "value pending of environment dev is unknown",
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "app", Property: "host"},
+			expectError: "property host does not exist in value app",
+		},
+		"missing value": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c := newTestClient(t, "dev", "pul-test")
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestPulumiOpenErrors(t *testing.T) {
+	c := newTestClient(t, "prod", "pul-test")
+	_, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "token"})
+	if !ErrorContains(err, esv1beta1.NoSecretErr.Error()) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	c = newTestClient(t, "dev", "wrong")
+	_, err = c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "token"})
+	if !ErrorContains(err, "unable to open environment dev: pulumi api returned status 401: Unauthorized") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPulumiGetSecretMap(t *testing.T) {
+	c := newTestClient(t, "dev", "pul-test")
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"user":     []byte("admin"),
+		"password": []byte("hunter2"),
+		"port":     []byte("5432"),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+}
+
+func TestPulumiGetAllSecrets(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		expectError string
+	}{
+		"top level": {
+			ref: esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^(token|hosts|pending)$"}},
+			want: map[string][]byte{
+				"token": []byte("t0k3n"),
+				"hosts": []byte(`["a","b"]`),
+			},
+		},
+		"path": {
+			ref: esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("app"), Name: &esv1beta1.FindName{RegExp: "^p"}},
+			want: map[string][]byte{
+				"password": []byte("hunter2"),
+				"port":     []byte("5432"),
+			},
+		},
+		"tags": {
+			ref:         esv1beta1.ExternalSecretFind{Tags: map[string]string{"a": "b"}},
+			expectError: errFindNotImplemented,
+		},
+	}
+	c := newTestClient(t, "dev", "pul-test")
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secret data: expected %#v, got %#v", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	tests := map[string]struct {
+		tweak       func(p *esv1beta1.PulumiProvider)
+		expectError string
+	}{
+		"valid": {
+			tweak: func(p *esv1beta1.PulumiProvider) {},
+		},
+		"missing organization": {
+			tweak:       func(p *esv1beta1.PulumiProvider) { p.Organization = "" },
+			expectError: errMissingOrganization,
+		},
+		"missing environment": {
+			tweak:       func(p *esv1beta1.PulumiProvider) { p.Environment = "" },
+			expectError: errMissingEnvironment,
+		},
+		"missing token key": {
+			tweak:       func(p *esv1beta1.PulumiProvider) { p.Auth.SecretRef.AccessToken.Key = "" },
+			expectError: errMissingTokenKey,
+		},
+		"namespace not allowed": {
+			tweak:       func(p *esv1beta1.PulumiProvider) { p.Auth.SecretRef.AccessToken.Namespace = pointer.StringPtr("foo") },
+			expectError: "invalid auth.secretRef.accessToken: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := makeStore("", "dev")
+			tc.tweak(store.Spec.Provider.Pulumi)
+			err := (&Provider{}).ValidateStore(store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
@@ -0,0 +1,136 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/external-secrets/external-secrets/pkg/provider/internal/jsonapi"
+)
+
+const (
+	defaultAPIURL = "https://api.scaleway.com"
+	listPageSize  = 100
+)
+
+type secret struct {
+	ID        string   `json:"id"`
+	ProjectID string   `json:"project_id"`
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	Tags      []string `json:"tags"`
+}
+
+type listSecretsResponse struct {
+	Secrets    []secret `json:"secrets"`
+	TotalCount int      `json:"total_count"`
+}
+
+type accessSecretVersionResponse struct {
+	SecretID string `json:"secret_id"`
+	Revision int    `json:"revision"`
+	// Data is base64 encoded, json decodes it into the raw bytes.
+	Data []byte `json:"data"`
+}
+
+// apiClient talks to the Scaleway Secret Manager REST API of a single region.
+// see: https://www.scaleway.com/en/developers/api/secret-manager/
+type apiClient struct {
+	baseURL   string
+	secretKey string
+	api       *jsonapi.Client
+}
+
+func newAPIClient(apiURL, region, secretKey string) *apiClient {
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	return &apiClient{
+		baseURL:   strings.TrimSuffix(apiURL, "/") + "/secret-manager/v1beta1/regions/" + url.PathEscape(region),
+		secretKey: secretKey,
+		api: &jsonapi.Client{
+			HTTP:         &http.Client{Timeout: 30 * time.Second},
+			Name:         "scaleway api",
+			ErrorMessage: jsonapi.MessageField("message"),
+		},
+	}
+}
+
+// accessSecretVersion returns the payload of a secret revision.
+// The revision is either a number or "latest".
+func (c *apiClient) accessSecretVersion(ctx context.Context, secretID, revision string) ([]byte, error) {
+	var out accessSecretVersionResponse
+	path := fmt.Sprintf("/secrets/%s/versions/%s/access", url.PathEscape(secretID), url.PathEscape(revision))
+	req, err := c.newRequest(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.api.ReadSecret(req, &out); err != nil {
+		return nil, err
+	}
+	return out.Data, nil
+}
+
+// listSecrets returns all secrets of the project matching the given filters.
+func (c *apiClient) listSecrets(ctx context.Context, projectID, name, path string, tags []string) ([]secret, error) {
+	var secrets []secret
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("project_id", projectID)
+		params.Set("page", strconv.Itoa(page))
+		params.Set("page_size", strconv.Itoa(listPageSize))
+		if name != "" {
+			params.Set("name", name)
+		}
+		if path != "" {
+			params.Set("path", path)
+		}
+		for _, tag := range tags {
+			params.Add("tags", tag)
+		}
+		req, err := c.newRequest(ctx, "/secrets", params)
+		if err != nil {
+			return nil, err
+		}
+		var out listSecretsResponse
+		if err := c.api.Do(req, &out); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, out.Secrets...)
+		if len(out.Secrets) == 0 || len(secrets) >= out.TotalCount {
+			return secrets, nil
+		}
+	}
+}
+
+// newRequest returns an authenticated GET request of path with the query params.
+func (c *apiClient) newRequest(ctx context.Context, path string, params url.Values) (*http.Request, error) {
+	u := c.baseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := c.api.NewRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Auth-Token", c.secretKey)
+	return req, nil
+}
@@ -0,0 +1,304 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaleway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	keyPrefixID   = "id:"
+	keyPrefixName = "name:"
+
+	revisionLatest = "latest"
+
+	errMissingStoreSpec       = "missing store provider scaleway"
+	errMissingRegion          = "missing region"
+	errMissingProjectID       = "missing projectId"
+	errMissingSecretRefName   = "missing name in auth.secretRef.%s"
+	errMissingSecretRefKey    = "missing key in auth.secretRef.%s"
+	errInvalidSecretRef       = "invalid auth.secretRef.%s: %w"
+	errFetchCredentials       = "could not fetch credentials secret %s: %w"
+	errMissingCredentials     = "missing %s in secret %s"
+	errUninitalizedClient     = "provider scaleway is not initialized"
+	errInvalidRevision        = "invalid version %q: must be latest or a revision number"
+	errAmbiguousSecretName    = "found %d secrets named %s, use id:<secret-id> or name:/<path>/<name> instead"
+	errPropertyNotFound       = "property %s does not exist in secret %s"
+	errJSONSecretUnmarshal    = "unable to unmarshal secret %s: %w"
+	errFindRequiresNameOrTags = "find requires name or tags"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// Scaleway reads secrets of a single project from Scaleway Secret Manager.
+type Scaleway struct {
+	client    *apiClient
+	projectID string
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Scaleway: &esv1beta1.ScalewayProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.ScalewayProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Scaleway == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.Scaleway, nil
+}
+
+// NewClient constructs a Scaleway client using the API key referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	// the access key only identifies the API key, requests are authenticated with the secret key.
+	if _, err := secretKeyRef(ctx, kube, storeKind, namespace, "accessKey", provider.Auth.SecretRef.AccessKey); err != nil {
+		return nil, err
+	}
+	secretKey, err := secretKeyRef(ctx, kube, storeKind, namespace, "secretKey", provider.Auth.SecretRef.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Scaleway{
+		client:    newAPIClient(provider.APIURL, provider.Region, secretKey),
+		projectID: provider.ProjectID,
+	}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if value == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.Region == "" {
+		return fmt.Errorf(errMissingRegion)
+	}
+	if provider.ProjectID == "" {
+		return fmt.Errorf(errMissingProjectID)
+	}
+	if err := validateSecretRef(store, "accessKey", provider.Auth.SecretRef.AccessKey); err != nil {
+		return err
+	}
+	return validateSecretRef(store, "secretKey", provider.Auth.SecretRef.SecretKey)
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingSecretRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingSecretRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidSecretRef, name, err)
+	}
+	return nil
+}
+
+// GetSecret returns the payload of a secret revision.
+// ref.Key is either id:<secret-id> or the name of the secret, optionally prefixed
+// with name: and its path. ref.Version is latest (default) or a revision number.
+func (s *Scaleway) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	revision, err := parseRevision(ref.Version)
+	if err != nil {
+		return nil, err
+	}
+	secretID, err := s.resolveSecretID(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.client.accessSecretVersion(ctx, secretID, revision)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return data, nil
+	}
+	val, ok := utils.GetProperty(data, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+func parseRevision(version string) (string, error) {
+	if version == "" || version == revisionLatest {
+		return revisionLatest, nil
+	}
+	if n, err := strconv.ParseUint(version, 10, 32); err != nil || n == 0 {
+		return "", fmt.Errorf(errInvalidRevision, version)
+	}
+	return version, nil
+}
+
+func (s *Scaleway) resolveSecretID(ctx context.Context, key string) (string, error) {
+	if strings.HasPrefix(key, keyPrefixID) {
+		return strings.TrimPrefix(key, keyPrefixID), nil
+	}
+	name := strings.TrimPrefix(key, keyPrefixName)
+	var secretPath string
+	if strings.Contains(name, "/") {
+		secretPath, name = path.Split(path.Join("/", name))
+		secretPath = path.Clean(secretPath)
+	}
+	secrets, err := s.client.listSecrets(ctx, s.projectID, name, secretPath, nil)
+	if err != nil {
+		return "", err
+	}
+	// the name filter of the API is not an exact match
+	var matches []secret
+	for _, sec := range secrets {
+		if sec.Name == name && (secretPath == "" || sec.Path == secretPath) {
+			matches = append(matches, sec)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", esv1beta1.NoSecretErr
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return "", fmt.Errorf(errAmbiguousSecretName, len(matches), name)
+	}
+}
+
+// GetSecretMap returns the key/value pairs of a secret that holds a JSON object.
+func (s *Scaleway) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := s.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte)
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns the latest revision of all secrets of the project
+// whose names match ref.Name and that carry all tags of ref.Tags.
+// Scaleway tags are plain strings: a tag with an empty value matches by key,
+// otherwise the tag key=value is expected.
+func (s *Scaleway) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if ref.Name == nil && len(ref.Tags) == 0 {
+		return nil, fmt.Errorf(errFindRequiresNameOrTags)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	tags := make([]string, 0, len(ref.Tags))
+	for k, v := range ref.Tags {
+		if v == "" {
+			tags = append(tags, k)
+		} else {
+			tags = append(tags, k+"="+v)
+		}
+	}
+	var secretPath string
+	if ref.Path != nil {
+		secretPath = path.Join("/", *ref.Path)
+	}
+	secrets, err := s.client.listSecrets(ctx, s.projectID, "", secretPath, tags)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte)
+	for _, sec := range secrets {
+		if matcher != nil && !matcher.MatchName(sec.Name) {
+			continue
+		}
+		data, err := s.client.accessSecretVersion(ctx, sec.ID, revisionLatest)
+		if err != nil {
+			return nil, err
+		}
+		secretData[sec.Name] = data
+	}
+	return secretData, nil
+}
+
+func (s *Scaleway) Close(ctx context.Context) error {
+	return nil
+}
+
+func (s *Scaleway) Validate() error {
+	return nil
+}
+
+// ValidateVersion accepts latest or a revision number.
+func (s *Scaleway) ValidateVersion(version string) error {
+	_, err := parseRevision(version)
+	return err
+}
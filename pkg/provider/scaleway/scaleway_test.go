@@ -0,0 +1,305 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaleway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	testProjectID = "11111111-1111-1111-1111-111111111111"
+	testSecretKey = "22222222-2222-2222-2222-222222222222"
+)
+
+var testSecrets = []secret{
+	{ID: "db-id", ProjectID: testProjectID, Name: "db", Path: "/", Tags: []string{"env=prod"}},
+	{ID: "api-id", ProjectID: testProjectID, Name: "api-key", Path: "/", Tags: []string{"env=prod", "team"}},
+	{ID: "other-id", ProjectID: testProjectID, Name: "api-key", Path: "/staging"},
+}
+
+var testVersions = map[string]map[string]string{
+	"db-id":    {"1": `{"user":"admin","port":5432}`, "2": `{"user":"root","port":5432}`},
+	"api-id":   {"1": "s3cr3t"},
+	"other-id": {"1": "staging"},
+}
+
+func newFakeScaleway(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != testSecretKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"authentication is denied","type":"denied_authentication"}`)
+			return
+		}
+		prefix := "/secret-manager/v1beta1/regions/fr-par"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		p := strings.TrimPrefix(r.URL.Path, prefix)
+		if p == "/secrets" {
+			q := r.URL.Query()
+			out := listSecretsResponse{Secrets: []secret{}}
+			for _, s := range testSecrets {
+				if q.Get("project_id") != s.ProjectID ||
+					!strings.Contains(s.Name, q.Get("name")) ||
+					(q.Get("path") != "" && q.Get("path") != s.Path) ||
+					!hasTags(s.Tags, q["tags"]) {
+					continue
+				}
+				out.Secrets = append(out.Secrets, s)
+			}
+			out.TotalCount = len(out.Secrets)
+			_ = json.NewEncoder(w).Encode(out)
+			return
+		}
+		// /secrets/{id}/versions/{revision}/access
+		if parts := strings.Split(p, "/"); len(parts) == 6 && parts[1] == "secrets" && parts[3] == "versions" && parts[5] == "access" {
+			versions := testVersions[parts[2]]
+			revision := parts[4]
+			if revision == revisionLatest {
+				revision = fmt.Sprint(len(versions))
+			}
+			if data, ok := versions[revision]; ok {
+				_ = json.NewEncoder(w).Encode(accessSecretVersionResponse{SecretID: parts[2], Data: []byte(data)})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"resource is not found","type":"not_found"}`)
+	}))
+}
+
+func hasTags(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if h == w {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func makeStore(apiURL string, accessKey, secretKey esmeta.SecretKeySelector) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Scaleway: &esv1beta1.ScalewayProvider{
+					Region:    "fr-par",
+					ProjectID: testProjectID,
+					APIURL:    apiURL,
+					Auth: esv1beta1.ScalewayAuth{
+						SecretRef: esv1beta1.ScalewayAuthSecretRef{
+							AccessKey: accessKey,
+							SecretKey: secretKey,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T) esv1beta1.SecretsClient {
+	srv := newFakeScaleway(t)
+	t.Cleanup(srv.Close)
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "scaleway", Namespace: "default"},
+		Data: map[string][]byte{
+			"accessKey": []byte("SCWXXXXXXXXXXXXXXXXX"),
+			"secretKey": []byte(testSecretKey),
+		},
+	}).Build()
+	store := makeStore(srv.URL,
+		esmeta.SecretKeySelector{Name: "scaleway", Key: "accessKey"},
+		esmeta.SecretKeySelector{Name: "scaleway", Key: "secretKey"})
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestScalewayGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"by id": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "id:api-id"},
+			want: "s3cr3t",
+		},
+		"by name latest": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "user"},
+			want: "root",
+		},
+		"by name with revision": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "name:db", Property: "user", Version: "1"},
+			want: "admin",
+		},
+		"by path": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "name:/staging/api-key"},
+			want: "staging",
+		},
+		"ambiguous name": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "api-key"},
+			expectError: "found 2 secrets named api-key",
+		},
+		"invalid revision": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Version: "v1"},
+			expectError: `invalid version "v1"`,
+		},
+		"missing revision": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Version: "3"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+		"missing secret": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "nope"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "password"},
+			expectError: "property password does not exist in secret db",
+		},
+	}
+	c := newTestClient(t)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestScalewayGetSecretMap(t *testing.T) {
+	c := newTestClient(t)
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"user": []byte("root"),
+		"port": []byte("5432"),
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+}
+
+func TestScalewayGetAllSecrets(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretFind
+		want        map[string][]byte
+		expectError string
+	}{
+		"by name": {
+			ref:  esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^d"}},
+			want: map[string][]byte{"db": []byte(`{"user":"root","port":5432}`)},
+		},
+		"by tags": {
+			ref:  esv1beta1.ExternalSecretFind{Tags: map[string]string{"env": "prod", "team": ""}},
+			want: map[string][]byte{"api-key": []byte("s3cr3t")},
+		},
+		"by path": {
+			ref:  esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("staging"), Name: &esv1beta1.FindName{RegExp: ".*"}},
+			want: map[string][]byte{"api-key": []byte("staging")},
+		},
+		"no filter": {
+			ref:         esv1beta1.ExternalSecretFind{},
+			expectError: errFindRequiresNameOrTags,
+		},
+	}
+	c := newTestClient(t)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetAllSecrets(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && !reflect.DeepEqual(out, tc.want) {
+				t.Errorf("unexpected secret data: expected %#v, got %#v", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	accessKey := esmeta.SecretKeySelector{Name: "scaleway", Key: "accessKey"}
+	secretKey := esmeta.SecretKeySelector{Name: "scaleway", Key: "secretKey"}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("", accessKey, secretKey),
+		},
+		"missing secret key": {
+			store:       makeStore("", accessKey, esmeta.SecretKeySelector{Name: "scaleway"}),
+			expectError: "missing key in auth.secretRef.secretKey",
+		},
+		"namespace not allowed": {
+			store:       makeStore("", esmeta.SecretKeySelector{Name: "scaleway", Key: "accessKey", Namespace: pointer.StringPtr("foo")}, secretKey),
+			expectError: "invalid auth.secretRef.accessKey: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+	store := makeStore("", accessKey, secretKey)
+	store.Spec.Provider.Scaleway.Region = ""
+	if err := (&Provider{}).ValidateStore(store); !ErrorContains(err, errMissingRegion) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
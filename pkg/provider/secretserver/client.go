@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/external-secrets/external-secrets/pkg/provider/internal/jsonapi"
+)
+
+const (
+	errLogin = "unable to login to secret server: %w"
+)
+
+// secret is a Secret Server secret with its fields.
+type secret struct {
+	ID       int          `json:"id"`
+	Name     string       `json:"name"`
+	FolderID int          `json:"folderId"`
+	Items    []secretItem `json:"items"`
+}
+
+type secretItem struct {
+	FieldName string `json:"fieldName"`
+	Slug      string `json:"slug"`
+	ItemValue string `json:"itemValue"`
+	IsFile    bool   `json:"isFile"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// apiClient talks to the Secret Server REST API.
+// see: https://updates.thycotic.net/secretserver/restapiguide/
+type apiClient struct {
+	baseURL string
+	api     *jsonapi.Client
+	token   string
+}
+
+func newAPIClient(ctx context.Context, serverURL, username, password, domain string) (*apiClient, error) {
+	c := &apiClient{
+		baseURL: strings.TrimSuffix(serverURL, "/"),
+		api: &jsonapi.Client{
+			HTTP: &http.Client{Timeout: 30 * time.Second},
+			Name: "secret server api",
+			// the api returns a message, the token endpoint an oauth error.
+			ErrorMessage: jsonapi.MessageField("message", "error_description"),
+		},
+	}
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("username", username)
+	form.Set("password", password)
+	if domain != "" {
+		form.Set("domain", domain)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf(errLogin, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var out tokenResponse
+	if err := c.api.Do(req, &out); err != nil {
+		return nil, fmt.Errorf(errLogin, err)
+	}
+	c.token = out.AccessToken
+	return c, nil
+}
+
+// getSecretByID returns the secret with the given id.
+func (c *apiClient) getSecretByID(ctx context.Context, id int) (*secret, error) {
+	return c.getSecret(ctx, "/api/v1/secrets/"+strconv.Itoa(id))
+}
+
+// getSecretByPath returns the secret at the given folder path, e.g. \Folder\Secret.
+func (c *apiClient) getSecretByPath(ctx context.Context, path string) (*secret, error) {
+	return c.getSecret(ctx, "/api/v1/secrets/0?"+url.Values{"secretPath": []string{path}}.Encode())
+}
+
+func (c *apiClient) getSecret(ctx context.Context, path string) (*secret, error) {
+	var out secret
+	if err := c.get(ctx, path, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// getFileField returns the content of a file attachment field.
+func (c *apiClient) getFileField(ctx context.Context, id int, slug string) ([]byte, error) {
+	var out []byte
+	err := c.get(ctx, fmt.Sprintf("/api/v1/secrets/%d/fields/%s", id, url.PathEscape(slug)), &out)
+	return out, err
+}
+
+// get reads a secret or one of its fields, a missing secret is reported as NoSecretErr.
+func (c *apiClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := c.api.NewRequest(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	return c.api.ReadSecret(req, out)
+}
@@ -0,0 +1,226 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errMissingStoreSpec     = "missing store provider secretserver"
+	errMissingServerURL     = "missing serverURL"
+	errInvalidServerURL     = "invalid serverURL: %w"
+	errMissingSecretRefName = "missing name in auth.secretRef.%s"
+	errMissingSecretRefKey  = "missing key in auth.secretRef.%s"
+	errInvalidSecretRef     = "invalid auth.secretRef.%s: %w"
+	errFetchCredentials     = "could not fetch credentials secret %s: %w"
+	errMissingCredentials   = "missing %s in secret %s"
+	errUninitalizedClient   = "provider secretserver is not initialized"
+	errFieldNotFound        = "field %s does not exist in secret %s"
+	errNotImplemented       = "GetAllSecrets not implemented"
+)
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// SecretServer reads secret fields from a Secret Server instance.
+type SecretServer struct {
+	client *apiClient
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		SecretServer: &esv1beta1.SecretServerProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.SecretServerProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.SecretServer == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.SecretServer, nil
+}
+
+// NewClient logs in to Secret Server with the credentials referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	username, err := secretKeyRef(ctx, kube, storeKind, namespace, "username", provider.Auth.SecretRef.Username)
+	if err != nil {
+		return nil, err
+	}
+	password, err := secretKeyRef(ctx, kube, storeKind, namespace, "password", provider.Auth.SecretRef.Password)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newAPIClient(ctx, provider.ServerURL, username, password, provider.Auth.Domain)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretServer{client: client}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if value == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.ServerURL == "" {
+		return fmt.Errorf(errMissingServerURL)
+	}
+	if _, err := url.ParseRequestURI(provider.ServerURL); err != nil {
+		return fmt.Errorf(errInvalidServerURL, err)
+	}
+	refs := map[string]esmeta.SecretKeySelector{
+		"username": provider.Auth.SecretRef.Username,
+		"password": provider.Auth.SecretRef.Password,
+	}
+	for name, ref := range refs {
+		if ref.Name == "" {
+			return fmt.Errorf(errMissingSecretRefName, name)
+		}
+		if ref.Key == "" {
+			return fmt.Errorf(errMissingSecretRefKey, name)
+		}
+		if err := utils.ValidateSecretSelector(store, ref); err != nil {
+			return fmt.Errorf(errInvalidSecretRef, name, err)
+		}
+	}
+	return nil
+}
+
+// GetSecret returns a field of a secret.
+// ref.Key is either the numeric id of the secret or its folder path, e.g. /Folder/Secret.
+// ref.Property selects the field by slug or name. Without a property all fields
+// are returned as JSON object keyed by slug.
+func (s *SecretServer) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	secret, err := s.lookupSecret(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		fields, err := s.fieldValues(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]string, len(fields))
+		for k, v := range fields {
+			values[k] = string(v)
+		}
+		return json.Marshal(values)
+	}
+	for _, item := range secret.Items {
+		if item.Slug == ref.Property || strings.EqualFold(item.FieldName, ref.Property) {
+			return s.fieldValue(ctx, secret, item)
+		}
+	}
+	return nil, fmt.Errorf(errFieldNotFound, ref.Property, ref.Key)
+}
+
+// GetSecretMap returns all fields of a secret keyed by slug.
+func (s *SecretServer) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	secret, err := s.lookupSecret(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	return s.fieldValues(ctx, secret)
+}
+
+// Empty GetAllSecrets.
+func (s *SecretServer) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	return nil, fmt.Errorf(errNotImplemented)
+}
+
+func (s *SecretServer) lookupSecret(ctx context.Context, key string) (*secret, error) {
+	if id, err := strconv.Atoi(key); err == nil {
+		return s.client.getSecretByID(ctx, id)
+	}
+	// secret server uses backslashes as folder separator
+	path := strings.ReplaceAll(key, "/", "\\")
+	if !strings.HasPrefix(path, "\\") {
+		path = "\\" + path
+	}
+	return s.client.getSecretByPath(ctx, path)
+}
+
+func (s *SecretServer) fieldValues(ctx context.Context, secret *secret) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(secret.Items))
+	for _, item := range secret.Items {
+		v, err := s.fieldValue(ctx, secret, item)
+		if err != nil {
+			return nil, err
+		}
+		values[item.Slug] = v
+	}
+	return values, nil
+}
+
+func (s *SecretServer) fieldValue(ctx context.Context, secret *secret, item secretItem) ([]byte, error) {
+	if item.IsFile {
+		return s.client.getFileField(ctx, secret.ID, item.Slug)
+	}
+	return []byte(item.ItemValue), nil
+}
+
+func (s *SecretServer) Close(ctx context.Context) error {
+	return nil
+}
+
+func (s *SecretServer) Validate() error {
+	return nil
+}
@@ -0,0 +1,255 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	metadataKey = "sops"
+
+	errParseFile       = "unable to parse %s: %w"
+	errNotEncrypted    = "file %s is not encrypted with sops"
+	errNoDataKey       = "unable to decrypt the data key of %s with the configured age identities or kms keys: %s"
+	errDecryptValue    = "unable to decrypt value of %s: %w"
+	errUnknownType     = "unknown type %s"
+	errUnsupportedTree = "unsupported non-string keys below %s"
+)
+
+// encryptedValue matches the values of a sops encrypted file.
+// see: https://github.com/getsops/sops#encryption-protocol
+var encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.+),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+// metadata is the sops section of an encrypted file. Each entry holds the
+// data key of the file encrypted for one master key.
+type metadata struct {
+	KMS []kmsKey `yaml:"kms"`
+	Age []ageKey `yaml:"age"`
+}
+
+type kmsKey struct {
+	ARN     string             `yaml:"arn"`
+	Enc     string             `yaml:"enc"`
+	Context map[string]*string `yaml:"context"`
+}
+
+type ageKey struct {
+	Recipient string `yaml:"recipient"`
+	Enc       string `yaml:"enc"`
+}
+
+// kmsAPI is the subset of the KMS API used to decrypt data keys.
+type kmsAPI interface {
+	DecryptWithContext(ctx aws.Context, input *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error)
+}
+
+// keyDecrypter decrypts data keys with age identities or KMS.
+type keyDecrypter struct {
+	identities []age.Identity
+	// newKMSClient returns a KMS client for the region, it is nil if KMS is not configured.
+	newKMSClient func(region string) (kmsAPI, error)
+	kmsRegion    string
+}
+
+// decryptFile decrypts a sops encrypted YAML or JSON document.
+// The sops metadata is removed from the result.
+func (d *keyDecrypter) decryptFile(ctx context.Context, name string, raw []byte) (map[string]interface{}, error) {
+	var file struct {
+		Metadata *metadata `yaml:"sops"`
+	}
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf(errParseFile, name, err)
+	}
+	if file.Metadata == nil {
+		return nil, fmt.Errorf(errNotEncrypted, name)
+	}
+	tree := make(map[string]interface{})
+	if err := yaml.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf(errParseFile, name, err)
+	}
+	delete(tree, metadataKey)
+	key, err := d.dataKey(ctx, name, file.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	out, err := decryptTree(tree, nil, key)
+	if err != nil {
+		return nil, err
+	}
+	return out.(map[string]interface{}), nil
+}
+
+// dataKey returns the data key of the file, trying all master keys that are configured.
+func (d *keyDecrypter) dataKey(ctx context.Context, name string, meta *metadata) ([]byte, error) {
+	var errs []string
+	if len(d.identities) > 0 {
+		for _, entry := range meta.Age {
+			r, err := age.Decrypt(armor.NewReader(strings.NewReader(entry.Enc)), d.identities...)
+			if err == nil {
+				var key []byte
+				if key, err = io.ReadAll(r); err == nil {
+					return key, nil
+				}
+			}
+			errs = append(errs, fmt.Sprintf("age %s: %v", entry.Recipient, err))
+		}
+	}
+	if d.newKMSClient != nil {
+		for _, entry := range meta.KMS {
+			key, err := d.decryptKMS(ctx, entry)
+			if err == nil {
+				return key, nil
+			}
+			errs = append(errs, fmt.Sprintf("kms %s: %v", entry.ARN, err))
+		}
+	}
+	if len(errs) == 0 {
+		errs = append(errs, "no matching master key")
+	}
+	return nil, fmt.Errorf(errNoDataKey, name, strings.Join(errs, ", "))
+}
+
+func (d *keyDecrypter) decryptKMS(ctx context.Context, entry kmsKey) ([]byte, error) {
+	region := d.kmsRegion
+	if region == "" {
+		keyARN, err := arn.Parse(entry.ARN)
+		if err != nil {
+			return nil, err
+		}
+		region = keyARN.Region
+	}
+	client, err := d.newKMSClient(region)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(entry.Enc)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob:    ciphertext,
+		EncryptionContext: entry.Context,
+		KeyId:             aws.String(entry.ARN),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// decryptTree decrypts all encrypted values below node.
+// The path of a value, its keys joined by colons, is authenticated with the value.
+// Values of lists share the path of the list.
+func decryptTree(node interface{}, path []string, key []byte) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			var err error
+			if out[k], err = decryptTree(v, append(path[:len(path):len(path)], k), key); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(n))
+		for i, v := range n {
+			var err error
+			if out[i], err = decryptTree(v, path, key); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case string:
+		m := encryptedValue.FindStringSubmatch(n)
+		if m == nil {
+			return n, nil
+		}
+		aad := strings.Join(path, ":") + ":"
+		v, err := decryptValue(m[1], m[2], m[3], m[4], aad, key)
+		if err != nil {
+			return nil, fmt.Errorf(errDecryptValue, strings.Join(path, "."), err)
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		return nil, fmt.Errorf(errUnsupportedTree, strings.Join(path, "."))
+	default:
+		return node, nil
+	}
+}
+
+func decryptValue(data, iv, tag, typ, aad string, key []byte) (interface{}, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := base64.StdEncoding.DecodeString(tag)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(nonce))
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, nonce, append(ciphertext, mac...), []byte(aad))
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case "str", "bytes", "comment":
+		return string(plain), nil
+	case "int":
+		return strconv.Atoi(string(plain))
+	case "float":
+		return strconv.ParseFloat(string(plain), 64)
+	case "bool":
+		return strconv.ParseBool(string(plain))
+	default:
+		return nil, fmt.Errorf(errUnknownType, typ)
+	}
+}
+
+// isEncrypted reports whether the document has a sops section.
+func isEncrypted(raw []byte) bool {
+	var file struct {
+		Metadata *metadata `yaml:"sops"`
+	}
+	return yaml.Unmarshal(raw, &file) == nil && file.Metadata != nil
+}
@@ -0,0 +1,103 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+const (
+	errClone    = "unable to clone %s: %w"
+	errReadFile = "unable to read %s: %w"
+)
+
+// checkout is the worktree of a shallow in-memory clone.
+type checkout struct {
+	fs billy.Filesystem
+}
+
+// cloneRepository clones the latest commit of the branch into memory.
+// If branch is empty the default branch of the repository is used.
+func cloneRepository(ctx context.Context, url, branch string, auth transport.AuthMethod) (*checkout, error) {
+	opts := &git.CloneOptions{
+		URL:          url,
+		Auth:         auth,
+		SingleBranch: true,
+		Depth:        1,
+		Tags:         git.NoTags,
+	}
+	if branch != "" {
+		opts.ReferenceName = plumbing.NewBranchReferenceName(branch)
+	}
+	fs := memfs.New()
+	if _, err := git.CloneContext(ctx, memory.NewStorage(), fs, opts); err != nil {
+		return nil, fmt.Errorf(errClone, url, err)
+	}
+	return &checkout{fs: fs}, nil
+}
+
+// readFile returns the content of the file, a missing file is reported as os.ErrNotExist.
+func (c *checkout) readFile(name string) ([]byte, error) {
+	data, err := util.ReadFile(c.fs, cleanPath(name))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf(errReadFile, name, err)
+	}
+	return data, err
+}
+
+// listFiles returns the paths of all YAML and JSON files below dir.
+func (c *checkout) listFiles(dir string) ([]string, error) {
+	var files []string
+	infos, err := c.fs.ReadDir(cleanPath(dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errReadFile, dir, err)
+	}
+	for _, info := range infos {
+		name := path.Join(cleanPath(dir), info.Name())
+		if info.IsDir() {
+			nested, err := c.listFiles(name)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, nested...)
+			continue
+		}
+		switch path.Ext(name) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// cleanPath returns the path relative to the root of the worktree.
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,350 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultGitUsername = "git"
+
+	errMissingStoreSpec    = "missing store provider sops"
+	errMissingURL          = "missing repository.url"
+	errInvalidURL          = "invalid repository.url %s: must be an http, https or file url"
+	errMissingDecryption   = "missing decryption: configure age or kms"
+	errMissingRefName      = "missing name in %s"
+	errMissingRefKey       = "missing key in %s"
+	errInvalidRef          = "invalid %s: %w"
+	errFetchCredentials    = "could not fetch credentials secret %s: %w"
+	errMissingCredentials  = "missing %s in secret %s"
+	errParseAgeIdentities  = "unable to parse age identities: %w"
+	errUninitalizedClient  = "provider sops is not initialized"
+	errPropertyNotFound    = "property %s does not exist in file %s"
+	errJSONSecretUnmarshal = "unable to unmarshal file %s: %w"
+	errFindNotImplemented  = "find by tags is not supported by sops"
+)
+
+// newKMSClient is a var so it can be replaced in tests.
+var newKMSClient = func(region string, creds *credentials.Credentials) (kmsAPI, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: creds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kms.New(sess), nil
+}
+
+// Provider satisfies the provider interface.
+type Provider struct{}
+
+// SOPS reads the decrypted values of sops encrypted files of a Git repository.
+type SOPS struct {
+	checkout  *checkout
+	decrypter *keyDecrypter
+	// decrypted files by path, every file is decrypted once per client.
+	files map[string][]byte
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		SOPS: &esv1beta1.SOPSProvider{},
+	})
+}
+
+func getProvider(store esv1beta1.GenericStore) (*esv1beta1.SOPSProvider, error) {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.SOPS == nil {
+		return nil, fmt.Errorf(errMissingStoreSpec)
+	}
+	return spec.Provider.SOPS, nil
+}
+
+// NewClient clones the repository and loads the decryption keys referenced by the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	provider, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	decrypter := &keyDecrypter{}
+	if provider.Decryption.Age != nil {
+		identities, err := secretKeyRef(ctx, kube, storeKind, namespace, "decryption.age.secretRef", provider.Decryption.Age.SecretRef)
+		if err != nil {
+			return nil, err
+		}
+		if decrypter.identities, err = age.ParseIdentities(strings.NewReader(identities)); err != nil {
+			return nil, fmt.Errorf(errParseAgeIdentities, err)
+		}
+	}
+	if kmsConfig := provider.Decryption.KMS; kmsConfig != nil {
+		var creds *credentials.Credentials
+		if ref := kmsConfig.SecretRef; ref != nil {
+			id, err := secretKeyRef(ctx, kube, storeKind, namespace, "decryption.kms.secretRef.accessKeyIDSecretRef", ref.AccessKeyID)
+			if err != nil {
+				return nil, err
+			}
+			secret, err := secretKeyRef(ctx, kube, storeKind, namespace, "decryption.kms.secretRef.secretAccessKeySecretRef", ref.SecretAccessKey)
+			if err != nil {
+				return nil, err
+			}
+			creds = credentials.NewStaticCredentials(strings.TrimSpace(id), strings.TrimSpace(secret), "")
+		}
+		decrypter.kmsRegion = kmsConfig.Region
+		decrypter.newKMSClient = func(region string) (kmsAPI, error) {
+			return newKMSClient(region, creds)
+		}
+	}
+	var auth transport.AuthMethod
+	if repoAuth := provider.Repository.Auth; repoAuth != nil {
+		basicAuth := &githttp.BasicAuth{Username: defaultGitUsername}
+		if repoAuth.SecretRef.Username != nil {
+			username, err := secretKeyRef(ctx, kube, storeKind, namespace, "repository.auth.secretRef.username", *repoAuth.SecretRef.Username)
+			if err != nil {
+				return nil, err
+			}
+			basicAuth.Username = strings.TrimSpace(username)
+		}
+		if basicAuth.Password, err = secretKeyRef(ctx, kube, storeKind, namespace, "repository.auth.secretRef.password", repoAuth.SecretRef.Password); err != nil {
+			return nil, err
+		}
+		basicAuth.Password = strings.TrimSpace(basicAuth.Password)
+		auth = basicAuth
+	}
+	co, err := cloneRepository(ctx, provider.Repository.URL, provider.Repository.Branch, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &SOPS{
+		checkout:  co,
+		decrypter: decrypter,
+		files:     make(map[string][]byte),
+	}, nil
+}
+
+func secretKeyRef(ctx context.Context, kube kclient.Client, storeKind, namespace, name string, ref esmeta.SecretKeySelector) (string, error) {
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind && ref.Namespace != nil {
+		objectKey.Namespace = *ref.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return "", fmt.Errorf(errFetchCredentials, ref.Name, err)
+	}
+	value := string(secret.Data[ref.Key])
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf(errMissingCredentials, name, ref.Name)
+	}
+	return value, nil
+}
+
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.Repository.URL == "" {
+		return fmt.Errorf(errMissingURL)
+	}
+	u, err := url.Parse(provider.Repository.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "file") {
+		return fmt.Errorf(errInvalidURL, provider.Repository.URL)
+	}
+	if repoAuth := provider.Repository.Auth; repoAuth != nil {
+		if repoAuth.SecretRef.Username != nil {
+			if err := validateSecretRef(store, "repository.auth.secretRef.username", *repoAuth.SecretRef.Username); err != nil {
+				return err
+			}
+		}
+		if err := validateSecretRef(store, "repository.auth.secretRef.password", repoAuth.SecretRef.Password); err != nil {
+			return err
+		}
+	}
+	decryption := provider.Decryption
+	if decryption.Age == nil && decryption.KMS == nil {
+		return fmt.Errorf(errMissingDecryption)
+	}
+	if decryption.Age != nil {
+		if err := validateSecretRef(store, "decryption.age.secretRef", decryption.Age.SecretRef); err != nil {
+			return err
+		}
+	}
+	if decryption.KMS != nil && decryption.KMS.SecretRef != nil {
+		if err := validateSecretRef(store, "decryption.kms.secretRef.accessKeyIDSecretRef", decryption.KMS.SecretRef.AccessKeyID); err != nil {
+			return err
+		}
+		return validateSecretRef(store, "decryption.kms.secretRef.secretAccessKeySecretRef", decryption.KMS.SecretRef.SecretAccessKey)
+	}
+	return nil
+}
+
+func validateSecretRef(store esv1beta1.GenericStore, name string, ref esmeta.SecretKeySelector) error {
+	if ref.Name == "" {
+		return fmt.Errorf(errMissingRefName, name)
+	}
+	if ref.Key == "" {
+		return fmt.Errorf(errMissingRefKey, name)
+	}
+	if err := utils.ValidateSecretSelector(store, ref); err != nil {
+		return fmt.Errorf(errInvalidRef, name, err)
+	}
+	return nil
+}
+
+// decryptedFile returns the decrypted document of the file as JSON.
+func (s *SOPS) decryptedFile(ctx context.Context, name string) ([]byte, error) {
+	name = cleanPath(name)
+	if data, ok := s.files[name]; ok {
+		return data, nil
+	}
+	raw, err := s.checkout.readFile(name)
+	if os.IsNotExist(err) {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.decrypter.decryptFile(ctx, name, raw)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	s.files[name] = data
+	return data, nil
+}
+
+// GetSecret returns the decrypted file at the path ref.Key as JSON.
+// ref.Property takes a gjson expression on the decrypted document.
+func (s *SOPS) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if s.checkout == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	data, err := s.decryptedFile(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		return data, nil
+	}
+	val, ok := utils.GetProperty(data, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the top level keys of the decrypted file.
+func (s *SOPS) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := s.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errJSONSecretUnmarshal, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+		} else {
+			secretData[k] = v
+		}
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets returns the decrypted YAML and JSON files below the directory ref.Path
+// whose paths match ref.Name. Files that are not encrypted with sops are skipped.
+func (s *SOPS) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if s.checkout == nil {
+		return nil, fmt.Errorf(errUninitalizedClient)
+	}
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errFindNotImplemented)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	var dir string
+	if ref.Path != nil {
+		dir = *ref.Path
+	}
+	files, err := s.checkout.listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	secretData := make(map[string][]byte)
+	for _, name := range files {
+		if matcher != nil && !matcher.MatchName(name) {
+			continue
+		}
+		raw, err := s.checkout.readFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if !isEncrypted(raw) {
+			continue
+		}
+		if secretData[name], err = s.decryptedFile(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	return secretData, nil
+}
+
+func (s *SOPS) Close(ctx context.Context) error {
+	return nil
+}
+
+func (s *SOPS) Validate() error {
+	return nil
+}
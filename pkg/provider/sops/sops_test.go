@@ -0,0 +1,405 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const testKMSARN = "arn:aws:kms:eu-west-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+// encryptValue encrypts a value the way sops does, aad is the path of the value.
+func encryptValue(t *testing.T, key []byte, value, typ, aad string) string {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := make([]byte, 32)
+	if _, err := rand.Read(iv); err != nil {
+		t.Fatal(err)
+	}
+	out := gcm.Seal(nil, iv, []byte(value), []byte(aad))
+	data, tag := out[:len(out)-gcm.Overhead()], out[len(out)-gcm.Overhead():]
+	enc := base64.StdEncoding.EncodeToString
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:%s]", enc(data), enc(iv), enc(tag), typ)
+}
+
+func encryptDataKey(t *testing.T, recipient age.Recipient, key []byte) string {
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(key); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	aw.Close()
+	return buf.String()
+}
+
+type fakeKMS struct {
+	key []byte
+}
+
+func (f *fakeKMS) DecryptWithContext(ctx aws.Context, input *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error) {
+	if aws.StringValue(input.KeyId) != testKMSARN || aws.StringValue(input.EncryptionContext["app"]) != "api" {
+		return nil, fmt.Errorf("AccessDeniedException: invalid ciphertext")
+	}
+	if !bytes.Equal(input.CiphertextBlob, []byte("wrapped")) {
+		return nil, fmt.Errorf("InvalidCiphertextException")
+	}
+	return &kms.DecryptOutput{Plaintext: f.key}, nil
+}
+
+// newTestRepository creates a git repository with sops encrypted files and returns its url.
+func newTestRepository(t *testing.T, identity *age.X25519Identity, kmsKey []byte) string {
+	ageKey := make([]byte, 32)
+	if _, err := rand.Read(ageKey); err != nil {
+		t.Fatal(err)
+	}
+	dbFile := fmt.Sprintf(`db:
+    user: %s
+    password: %s
+    port: %s
+    hosts:
+        - %s
+        - %s
+tls_unencrypted: false
+sops:
+    age:
+        - recipient: %s
+          enc: |
+%s
+    lastmodified: "2022-04-01T00:00:00Z"
+    version: 3.7.3
+`,
+		encryptValue(t, ageKey, "admin", "str", "db:user:"),
+		encryptValue(t, ageKey, "hunter2", "str", "db:password:"),
+		encryptValue(t, ageKey, "5432", "int", "db:port:"),
+		encryptValue(t, ageKey, "db-0", "str", "db:hosts:"),
+		encryptValue(t, ageKey, "db-1", "str", "db:hosts:"),
+		identity.Recipient().String(),
+		indent(encryptDataKey(t, identity.Recipient(), ageKey), "            "))
+	apiFile := fmt.Sprintf(`{
+	"token": %q,
+	"debug": %q,
+	"sops": {
+		"kms": [{"arn": %q, "enc": %q, "context": {"app": "api"}}],
+		"version": "3.7.3"
+	}
+}`,
+		encryptValue(t, kmsKey, "s3cr3t", "str", "token:"),
+		encryptValue(t, kmsKey, "True", "bool", "debug:"),
+		testKMSARN, base64.StdEncoding.EncodeToString([]byte("wrapped")))
+	tampered := strings.Replace(dbFile, "password: ENC[AES256_GCM,data:", "password: ENC[AES256_GCM,data:AAAA", 1)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"apps/db.enc.yaml":      dbFile,
+		"apps/nested/api.json":  apiFile,
+		"apps/README.md":        "# secrets",
+		"apps/plain.yaml":       "foo: bar\n",
+		"broken/tampered.yaml":  tampered,
+		".sops.yaml":            "creation_rules:\n  - age: " + identity.Recipient().String() + "\n",
+		"unrelated/config.json": "{}",
+	}
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := wt.Commit("add secrets", &git.CommitOptions{
+		Author: &object.Signature{Name: "eso", Email: "eso@example.com", When: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return "file://" + dir
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func makeStore(url string, decryption esv1beta1.SOPSDecryption) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				SOPS: &esv1beta1.SOPSProvider{
+					Repository: esv1beta1.SOPSRepository{URL: url},
+					Decryption: decryption,
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T) esv1beta1.SecretsClient {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kmsKey := make([]byte, 32)
+	if _, err := rand.Read(kmsKey); err != nil {
+		t.Fatal(err)
+	}
+	url := newTestRepository(t, identity, kmsKey)
+
+	defaultKMSClient := newKMSClient
+	t.Cleanup(func() { newKMSClient = defaultKMSClient })
+	newKMSClient = func(region string, creds *credentials.Credentials) (kmsAPI, error) {
+		if region != "eu-west-1" {
+			return nil, fmt.Errorf("unexpected region %s", region)
+		}
+		return &fakeKMS{key: kmsKey}, nil
+	}
+
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops", Namespace: "default"},
+		Data: map[string][]byte{
+			"keys.txt": []byte("# created: 2022-04-01T00:00:00Z\n" + identity.String() + "\n"),
+		},
+	}).Build()
+	store := makeStore(url, esv1beta1.SOPSDecryption{
+		Age: &esv1beta1.SOPSAgeDecryption{SecretRef: esmeta.SecretKeySelector{Name: "sops", Key: "keys.txt"}},
+		KMS: &esv1beta1.SOPSKMSDecryption{},
+	})
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestSOPSGetSecret(t *testing.T) {
+	tests := map[string]struct {
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		want        string
+		expectError string
+	}{
+		"age property": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/db.enc.yaml", Property: "db.password"},
+			want: "hunter2",
+		},
+		"list item": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "/apps/db.enc.yaml", Property: "db.hosts.1"},
+			want: "db-1",
+		},
+		"typed value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/db.enc.yaml", Property: "db.port"},
+			want: "5432",
+		},
+		"unencrypted value": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/db.enc.yaml", Property: "tls_unencrypted"},
+			want: "false",
+		},
+		"kms whole file": {
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/nested/api.json"},
+			want: `{"debug":true,"token":"s3cr3t"}`,
+		},
+		"missing property": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/db.enc.yaml", Property: "db.host"},
+			expectError: "property db.host does not exist in file apps/db.enc.yaml",
+		},
+		"not encrypted": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/plain.yaml"},
+			expectError: "file apps/plain.yaml is not encrypted with sops",
+		},
+		"tampered value": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "broken/tampered.yaml"},
+			expectError: "unable to decrypt value of db.password",
+		},
+		"missing file": {
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/nope.yaml"},
+			expectError: esv1beta1.NoSecretErr.Error(),
+		},
+	}
+	c := newTestClient(t)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := c.GetSecret(context.Background(), tc.ref)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+			if err == nil && string(out) != tc.want {
+				t.Errorf("unexpected secret: expected %s, got %s", tc.want, string(out))
+			}
+		})
+	}
+}
+
+func TestSOPSGetSecretMap(t *testing.T) {
+	c := newTestClient(t)
+	out, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/nested/api.json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{"token": []byte("s3cr3t"), "debug": []byte("true")}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("unexpected secret data: expected %#v, got %#v", want, out)
+	}
+}
+
+func TestSOPSGetAllSecrets(t *testing.T) {
+	c := newTestClient(t)
+	out, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: pointer.StringPtr("apps")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 2 || string(out["apps/nested/api.json"]) != `{"debug":true,"token":"s3cr3t"}` || len(out["apps/db.enc.yaml"]) == 0 {
+		t.Errorf("unexpected secrets: %v", out)
+	}
+	out, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: `\.json$`}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 || out["apps/nested/api.json"] == nil {
+		t.Errorf("unexpected secrets: %v", out)
+	}
+}
+
+func TestSOPSMissingIdentity(t *testing.T) {
+	identity, _ := age.GenerateX25519Identity()
+	other, _ := age.GenerateX25519Identity()
+	url := newTestRepository(t, identity, make([]byte, 32))
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops", Namespace: "default"},
+		Data:       map[string][]byte{"keys.txt": []byte(other.String())},
+	}).Build()
+	store := makeStore(url, esv1beta1.SOPSDecryption{
+		Age: &esv1beta1.SOPSAgeDecryption{SecretRef: esmeta.SecretKeySelector{Name: "sops", Key: "keys.txt"}},
+	})
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "apps/db.enc.yaml"})
+	if !ErrorContains(err, "unable to decrypt the data key of apps/db.enc.yaml") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	ageDecryption := esv1beta1.SOPSDecryption{
+		Age: &esv1beta1.SOPSAgeDecryption{SecretRef: esmeta.SecretKeySelector{Name: "sops", Key: "keys.txt"}},
+	}
+	withAuth := makeStore("https://github.com/example/secrets.git", ageDecryption)
+	withAuth.Spec.Provider.SOPS.Repository.Auth = &esv1beta1.SOPSRepositoryAuth{
+		SecretRef: esv1beta1.SOPSRepositoryAuthSecretRef{Password: esmeta.SecretKeySelector{Name: "git"}},
+	}
+	tests := map[string]struct {
+		store       *esv1beta1.SecretStore
+		expectError string
+	}{
+		"valid": {
+			store: makeStore("https://github.com/example/secrets.git", ageDecryption),
+		},
+		"valid kms with default credentials": {
+			store: makeStore("https://github.com/example/secrets.git", esv1beta1.SOPSDecryption{KMS: &esv1beta1.SOPSKMSDecryption{}}),
+		},
+		"missing url": {
+			store:       makeStore("", ageDecryption),
+			expectError: errMissingURL,
+		},
+		"ssh url": {
+			store:       makeStore("ssh://git@github.com/example/secrets.git", ageDecryption),
+			expectError: "invalid repository.url ssh://git@github.com/example/secrets.git",
+		},
+		"missing decryption": {
+			store:       makeStore("https://github.com/example/secrets.git", esv1beta1.SOPSDecryption{}),
+			expectError: errMissingDecryption,
+		},
+		"missing password key": {
+			store:       withAuth,
+			expectError: "missing key in repository.auth.secretRef.password",
+		},
+		"namespace not allowed": {
+			store: makeStore("https://github.com/example/secrets.git", esv1beta1.SOPSDecryption{
+				Age: &esv1beta1.SOPSAgeDecryption{SecretRef: esmeta.SecretKeySelector{Name: "sops", Key: "keys.txt", Namespace: pointer.StringPtr("foo")}},
+			}),
+			expectError: "invalid decryption.age.secretRef: namespace not allowed with namespaced SecretStore",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Provider{}).ValidateStore(tc.store)
+			if !ErrorContains(err, tc.expectError) {
+				t.Errorf("unexpected error: %v, expected: '%s'", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func ErrorContains(out error, want string) bool {
+	if out == nil {
+		return want == ""
+	}
+	if want == "" {
+		return false
+	}
+	return strings.Contains(out.Error(), want)
+}
@@ -28,9 +28,12 @@ var _ esv1beta1.Provider = &Client{}
 type Client struct {
 	NewFn func(context.Context, esv1beta1.GenericStore, client.Client,
 		string) (esv1beta1.SecretsClient, error)
-	GetSecretFn     func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error)
-	GetSecretMapFn  func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error)
-	GetAllSecretsFn func(context.Context, esv1beta1.ExternalSecretFind) (map[string][]byte, error)
+	GetSecretFn       func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error)
+	GetSecretMapFn    func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error)
+	GetAllSecretsFn   func(context.Context, esv1beta1.ExternalSecretFind) (map[string][]byte, error)
+	PushSecretFn      func(ctx context.Context, value []byte, remoteKey string) error
+	DeleteSecretFn    func(ctx context.Context, remoteKey string) error
+	ValidateVersionFn func(version string) error
 }
 
 // New returns a fake provider/client.
@@ -45,6 +48,15 @@ func New() *Client {
 		GetAllSecretsFn: func(context.Context, esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 			return nil, nil
 		},
+		PushSecretFn: func(context.Context, []byte, string) error {
+			return nil
+		},
+		DeleteSecretFn: func(context.Context, string) error {
+			return nil
+		},
+		ValidateVersionFn: func(string) error {
+			return nil
+		},
 	}
 
 	v.NewFn = func(context.Context, esv1beta1.GenericStore, client.Client, string) (esv1beta1.SecretsClient, error) {
@@ -77,6 +89,39 @@ func (v *Client) WithGetSecret(secData []byte, err error) *Client {
 	return v
 }
 
+// PushSecret implements the provider.SecretsPusher interface.
+func (v *Client) PushSecret(ctx context.Context, value []byte, remoteKey string) error {
+	return v.PushSecretFn(ctx, value, remoteKey)
+}
+
+// WithPushSecret wraps the function called when pushing a secret to this provider.
+func (v *Client) WithPushSecret(f func(ctx context.Context, value []byte, remoteKey string) error) *Client {
+	v.PushSecretFn = f
+	return v
+}
+
+// DeleteSecret implements the provider.SecretsDeleter interface.
+func (v *Client) DeleteSecret(ctx context.Context, remoteKey string) error {
+	return v.DeleteSecretFn(ctx, remoteKey)
+}
+
+// WithDeleteSecret wraps the function called when deleting a pushed secret from this provider.
+func (v *Client) WithDeleteSecret(f func(ctx context.Context, remoteKey string) error) *Client {
+	v.DeleteSecretFn = f
+	return v
+}
+
+// ValidateVersion implements the provider.VersionValidator interface.
+func (v *Client) ValidateVersion(version string) error {
+	return v.ValidateVersionFn(version)
+}
+
+// WithValidateVersion wraps the function validating the version of a remoteRef.
+func (v *Client) WithValidateVersion(f func(version string) error) *Client {
+	v.ValidateVersionFn = f
+	return v
+}
+
 // GetSecretMap imeplements the provider.Provider interface.
 func (v *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
 	return v.GetSecretMapFn(ctx, ref)
@@ -131,4 +176,10 @@ func (v *Client) Reset() {
 		string) (esv1beta1.SecretsClient, error) {
 		return v, nil
 	})
+	v.WithValidateVersion(func(string) error {
+		return nil
+	})
+	v.WithDeleteSecret(func(context.Context, string) error {
+		return nil
+	})
 }
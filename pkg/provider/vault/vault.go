@@ -28,7 +28,6 @@ import (
 
 	"github.com/go-logr/logr"
 	vault "github.com/hashicorp/vault/api"
-	"github.com/tidwall/gjson"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -61,7 +60,7 @@ const (
 	errInvalidCredentials   = "invalid vault credentials: %w"
 	errDataField            = "failed to find data field"
 	errJSONUnmarshall       = "failed to unmarshall JSON"
-	errPathInvalid          = "provided Path isn't a valid kv v2 path"
+	errPathInvalid          = "provided Path isn't a valid kv path"
 	errSecretFormat         = "secret data not in expected format"
 	errUnexpectedKey        = "unexpected key in data: %s"
 	errVaultToken           = "cannot parse Vault authentication token: %w"
@@ -70,7 +69,11 @@ const (
 	errVaultResponse        = "cannot parse Vault response: %w"
 	errServiceAccount       = "cannot read Kubernetes service account token from file system: %w"
 	errJwtNoTokenSource     = "neither `secretRef` nor `kubernetesServiceAccountToken` was supplied as token source for jwt authentication"
-	errUnsupportedKvVersion = "cannot perform find operations with kv version v1"
+	errUnsupportedKvVersion = "cannot find secrets by tags with kv version v1"
+
+	errUnsupportedMetadataKvVersion = "cannot fetch secret metadata with kv version v1"
+	errUnsupportedVersionKvVersion  = "cannot select a secret version with kv version v1"
+	errInvalidVersion               = "invalid version %s: must be a version number"
 
 	errGetKubeSA             = "cannot get Kubernetes service account %q: %w"
 	errGetKubeSASecrets      = "cannot find secrets bound to service account: %q"
@@ -154,12 +157,41 @@ func (c *connector) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 	return c.newClient(ctx, store, kube, clientset.CoreV1(), namespace)
 }
 
+// NewGeneratorClient returns a client of the Vault server of provider, which is
+// authenticated for a generator in namespace. The referenced secrets and service
+// accounts must exist in namespace.
+// Unlike the provider client, the token isn't revoked on close: revoking it would also
+// revoke the leases of the dynamic secrets created with it, so the caller revokes it.
+func NewGeneratorClient(ctx context.Context, provider *esv1beta1.VaultProvider, kube kclient.Client, namespace string) (Client, error) {
+	restCfg, err := ctrlcfg.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, err
+	}
+	store := &esv1beta1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{Vault: provider},
+		},
+	}
+	c := &connector{newVaultClient: newVaultClient}
+	sc, err := c.newClient(ctx, store, kube, clientset.CoreV1(), namespace)
+	if err != nil {
+		return nil, err
+	}
+	return sc.(*client).client, nil
+}
+
 func (c *connector) newClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, corev1 typedcorev1.CoreV1Interface, namespace string) (esv1beta1.SecretsClient, error) {
 	storeSpec := store.GetSpec()
 	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.Vault == nil {
 		return nil, errors.New(errVaultStore)
 	}
-	vaultSpec := storeSpec.Provider.Vault
+	// the kv version may be detected below, do not modify the cached store.
+	vaultSpec := storeSpec.Provider.Vault.DeepCopy()
 
 	vStore := &client{
 		kube:      kube,
@@ -194,9 +226,40 @@ func (c *connector) newClient(ctx context.Context, store esv1beta1.GenericStore,
 
 	vStore.client = client
 
+	if vaultSpec.Version == "" {
+		vaultSpec.Version = vStore.detectKVVersion(ctx)
+	}
+
 	return vStore, nil
 }
 
+// detectKVVersion looks up the version of the kv engine mounted at the store path.
+// The sys/internal/ui/mounts endpoint is readable by every token that has access to the mount.
+// Without a store path, or if the lookup fails, v2 is assumed.
+func (v *client) detectKVVersion(ctx context.Context) esv1beta1.VaultKVStoreVersion {
+	if v.store.Path == nil {
+		return esv1beta1.VaultKVStoreV2
+	}
+	mount := strings.TrimSuffix(*v.store.Path, "/data")
+	req := v.client.NewRequest(http.MethodGet, fmt.Sprintf("/v1/sys/internal/ui/mounts/%s", mount))
+	resp, err := v.client.RawRequestWithContext(ctx, req)
+	if err != nil {
+		v.log.Info("cannot detect kv version, assuming v2", "path", mount, "error", err.Error())
+		return esv1beta1.VaultKVStoreV2
+	}
+	defer resp.Body.Close()
+	mountInfo, err := vault.ParseSecret(resp.Body)
+	if err != nil || mountInfo == nil {
+		return esv1beta1.VaultKVStoreV2
+	}
+	// kv v1 mounts have no version option.
+	options, _ := mountInfo.Data["options"].(map[string]interface{})
+	if version, _ := options["version"].(string); version == "2" {
+		return esv1beta1.VaultKVStoreV2
+	}
+	return esv1beta1.VaultKVStoreV1
+}
+
 func (c *connector) ValidateStore(store esv1beta1.GenericStore) error {
 	if store == nil {
 		return fmt.Errorf(errInvalidStore)
@@ -267,8 +330,9 @@ func (c *connector) ValidateStore(store esv1beta1.GenericStore) error {
 // GetAllSecrets
 // First load all secrets from secretStore path configuration.
 // Then, gets secrets from a matching name or matching custom_metadata.
+// kv v1 has no metadata, so finding secrets by tags requires kv v2.
 func (v *client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	if v.store.Version == esv1beta1.VaultKVStoreV1 {
+	if v.store.Version == esv1beta1.VaultKVStoreV1 && ref.Name == nil {
 		return nil, errors.New(errUnsupportedKvVersion)
 	}
 	searchPath := ""
@@ -332,7 +396,7 @@ func (v *client) findSecretsFromName(ctx context.Context, candidates []string, r
 
 func (v *client) listSecrets(ctx context.Context, path string) ([]string, error) {
 	secrets := make([]string, 0)
-	url, err := v.buildMetadataPath(path)
+	url, err := v.buildListPath(path)
 	if err != nil {
 		return nil, err
 	}
@@ -379,6 +443,10 @@ func (v *client) readSecretMetadata(ctx context.Context, path string) (map[strin
 	}
 	r := v.client.NewRequest(http.MethodGet, url)
 	resp, err := v.client.RawRequestWithContext(ctx, r)
+	var respErr *vault.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		return nil, esv1beta1.NoSecretErr
+	}
 	if err != nil {
 		return nil, fmt.Errorf(errReadSecret, err)
 	}
@@ -406,6 +474,9 @@ func (v *client) readSecretMetadata(ctx context.Context, path string) (map[strin
 // 2. get a key from the secret.
 //    Nested values are supported by specifying a gjson expression
 func (v *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return v.getSecretMetadata(ctx, ref)
+	}
 	data, err := v.readSecret(ctx, ref.Key, ref.Version)
 	if err != nil {
 		return nil, err
@@ -427,11 +498,34 @@ func (v *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretData
 	}
 
 	// (3): extract key from secret using gjson
-	val := gjson.Get(string(jsonStr), ref.Property)
-	if !val.Exists() {
+	val, ok := utils.GetProperty(jsonStr, ref.Property)
+	if !ok {
+		return nil, fmt.Errorf(errSecretKeyFmt, ref.Property)
+	}
+	return val, nil
+}
+
+// getSecretMetadata returns the custom_metadata of a kv v2 secret as json-encoded value,
+// or the value of the metadata key ref.Property.
+func (v *client) getSecretMetadata(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if v.store.Version == esv1beta1.VaultKVStoreV1 {
+		return nil, errors.New(errUnsupportedMetadataKvVersion)
+	}
+	metadata, err := v.readSecretMetadata(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Property == "" {
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		return json.Marshal(metadata)
+	}
+	val, ok := metadata[ref.Property]
+	if !ok {
 		return nil, fmt.Errorf(errSecretKeyFmt, ref.Property)
 	}
-	return []byte(val.String()), nil
+	return []byte(val), nil
 }
 
 // GetSecretMap supports two modes of operation:
@@ -504,6 +598,17 @@ func (v *client) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts the version number of a kv v2 secret.
+func (v *client) ValidateVersion(version string) error {
+	if v.store.Version == esv1beta1.VaultKVStoreV1 {
+		return errors.New(errUnsupportedVersionKvVersion)
+	}
+	if n, err := strconv.ParseUint(version, 10, 64); err != nil || n == 0 {
+		return fmt.Errorf(errInvalidVersion, version)
+	}
+	return nil
+}
+
 func (v *client) buildMetadataPath(path string) (string, error) {
 	var url string
 	if v.store.Path == nil && !strings.Contains(path, "data") {
@@ -517,6 +622,22 @@ func (v *client) buildMetadataPath(path string) (string, error) {
 	}
 	return url, nil
 }
+
+// buildListPath returns the url to list the secrets below path.
+// kv v1 lists secrets at the mount itself, kv v2 at its metadata.
+func (v *client) buildListPath(path string) (string, error) {
+	if v.store.Version != esv1beta1.VaultKVStoreV1 {
+		return v.buildMetadataPath(path)
+	}
+	if v.store.Path == nil {
+		if path == "" {
+			return "", fmt.Errorf(errPathInvalid)
+		}
+		return fmt.Sprintf("/v1/%s", path), nil
+	}
+	return fmt.Sprintf("/v1/%s/%s", *v.store.Path, path), nil
+}
+
 func (v *client) buildPath(path string) string {
 	optionalMount := v.store.Path
 	origPath := strings.Split(path, "/")
@@ -556,6 +677,10 @@ func (v *client) readSecret(ctx context.Context, path, version string) (map[stri
 	}
 
 	resp, err := v.client.RawRequestWithContext(ctx, req)
+	var respErr *vault.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		return nil, esv1beta1.NoSecretErr
+	}
 	if err != nil {
 		return nil, fmt.Errorf(errReadSecret, err)
 	}
@@ -574,6 +699,10 @@ func (v *client) readSecret(ctx context.Context, path, version string) (map[stri
 		if !ok {
 			return nil, errors.New(errDataField)
 		}
+		// a deleted or destroyed version keeps its metadata but has no data
+		if dataInt == nil {
+			return nil, esv1beta1.NoSecretErr
+		}
 		secretData, ok = dataInt.(map[string]interface{})
 		if !ok {
 			return nil, errors.New(errJSONUnmarshall)
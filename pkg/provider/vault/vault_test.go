@@ -22,9 +22,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	vault "github.com/hashicorp/vault/api"
 	corev1 "k8s.io/api/core/v1"
@@ -590,6 +593,12 @@ func TestGetSecret(t *testing.T) {
 			"bar": "also ok?",
 		},
 	}
+	secretMetadata := map[string]interface{}{
+		"custom_metadata": map[string]interface{}{
+			"owner":    "team-a",
+			"rotation": "30d",
+		},
+	}
 
 	type args struct {
 		store   *esv1beta1.VaultProvider
@@ -734,6 +743,121 @@ func TestGetSecret(t *testing.T) {
 				err: fmt.Errorf(errReadSecret, errBoom),
 			},
 		},
+		"ReadSecretNotFound": {
+			reason: "Should return NoSecretErr if vault responds with 404.",
+			args: args{
+				store: makeSecretStore().Spec.Provider.Vault,
+				vClient: &fake.VaultClient{
+					MockNewRequest: fake.NewMockNewRequestFn(&vault.Request{}),
+					MockRawRequestWithContext: fake.NewMockRawRequestWithContextFn(nil, &vault.ResponseError{
+						StatusCode: http.StatusNotFound,
+					}),
+				},
+			},
+			want: want{
+				err: esv1beta1.NoSecretErr,
+			},
+		},
+		"ReadDeletedSecretVersion": {
+			reason: "Should return NoSecretErr if the kv v2 version has been deleted.",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:     "secret/foo",
+					Version: "2",
+				},
+				vClient: &fake.VaultClient{
+					MockNewRequest: fake.NewMockNewRequestFn(&vault.Request{Params: url.Values{}}),
+					MockRawRequestWithContext: fake.NewMockRawRequestWithContextFn(
+						newVaultResponseWithData(map[string]interface{}{
+							"data": nil,
+							"metadata": map[string]interface{}{
+								"deletion_time": "2022-04-01T10:00:00.000000Z",
+								"destroyed":     false,
+								"version":       2,
+							},
+						}), nil,
+					),
+				},
+			},
+			want: want{
+				err: esv1beta1.NoSecretErr,
+			},
+		},
+		"ReadSecretMetadata": {
+			reason: "Should return the custom_metadata value with property",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:            "foo",
+					Property:       "owner",
+					MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+				},
+				vClient: &fake.VaultClient{
+					MockNewRequest: fake.NewMockNewRequestFn(&vault.Request{}),
+					MockRawRequestWithContext: fake.NewMockRawRequestWithContextFn(
+						newVaultResponseWithData(secretMetadata), nil,
+					),
+				},
+			},
+			want: want{
+				err: nil,
+				val: []byte("team-a"),
+			},
+		},
+		"ReadSecretMetadataWithoutProperty": {
+			reason: "Should return the json encoded custom_metadata without property",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:            "foo",
+					MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+				},
+				vClient: &fake.VaultClient{
+					MockNewRequest: fake.NewMockNewRequestFn(&vault.Request{}),
+					MockRawRequestWithContext: fake.NewMockRawRequestWithContextFn(
+						newVaultResponseWithData(secretMetadata), nil,
+					),
+				},
+			},
+			want: want{
+				err: nil,
+				val: []byte(`{"owner":"team-a","rotation":"30d"}`),
+			},
+		},
+		"ReadSecretMetadataNonexistentProperty": {
+			reason: "Should return error if the custom_metadata key does not exist",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:            "foo",
+					Property:       "nop",
+					MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+				},
+				vClient: &fake.VaultClient{
+					MockNewRequest: fake.NewMockNewRequestFn(&vault.Request{}),
+					MockRawRequestWithContext: fake.NewMockRawRequestWithContextFn(
+						newVaultResponseWithData(secretMetadata), nil,
+					),
+				},
+			},
+			want: want{
+				err: fmt.Errorf(errSecretKeyFmt, "nop"),
+			},
+		},
+		"ReadSecretMetadataKVv1": {
+			reason: "Should return error if metadata is fetched with kv version v1",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV1).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:            "foo",
+					MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+				},
+			},
+			want: want{
+				err: errors.New(errUnsupportedMetadataKvVersion),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -1213,6 +1337,172 @@ func TestGetAllSecrets(t *testing.T) {
 	}
 }
 
+func TestGetAllSecretsKVv1(t *testing.T) {
+	lists := map[string][]interface{}{
+		"/v1/secret/":     {"app/", "db"},
+		"/v1/secret/app/": {"api"},
+	}
+	data := map[string]map[string]interface{}{
+		"/v1/secret/app/api": {"key": "api"},
+		"/v1/secret/db":      {"key": "db"},
+	}
+	vClient := &fake.VaultClient{
+		MockNewRequest: fake.NewMockNewRequestListFn(&vault.Request{}),
+		MockRawRequestWithContext: func(_ context.Context, r *vault.Request) (*vault.Response, error) {
+			if r.Params.Get("list") == "true" {
+				return newVaultResponseWithData(map[string]interface{}{"keys": lists[r.URL.Path]}), nil
+			}
+			return newVaultResponseWithData(data[r.URL.Path]), nil
+		},
+	}
+	vStore := &client{
+		client: vClient,
+		store:  makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV1).Spec.Provider.Vault,
+	}
+	val, err := vStore.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Name: &esv1beta1.FindName{RegExp: ".*"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"app/api": []byte(`{"key":"api"}`),
+		"db":      []byte(`{"key":"db"}`),
+	}
+	if diff := cmp.Diff(want, val); diff != "" {
+		t.Errorf("vault.GetAllSecrets(...): -want val, +got val:\n%s", diff)
+	}
+}
+
+func TestDetectKVVersion(t *testing.T) {
+	mountResponse := func(options map[string]interface{}) fake.MockRawRequestWithContextFn {
+		return fake.NewMockRawRequestWithContextFn(newVaultResponseWithData(map[string]interface{}{
+			"type":    "kv",
+			"path":    "secret/",
+			"options": options,
+		}), nil, func(req *vault.Request) error {
+			if req.URL.Path != "/v1/sys/internal/ui/mounts/secret" {
+				return fmt.Errorf("unexpected path %s", req.URL.Path)
+			}
+			return nil
+		})
+	}
+	cases := map[string]struct {
+		rawRequest fake.MockRawRequestWithContextFn
+		want       esv1beta1.VaultKVStoreVersion
+	}{
+		"v2": {
+			rawRequest: mountResponse(map[string]interface{}{"version": "2"}),
+			want:       esv1beta1.VaultKVStoreV2,
+		},
+		"v1": {
+			rawRequest: mountResponse(nil),
+			want:       esv1beta1.VaultKVStoreV1,
+		},
+		"LookupFails": {
+			rawRequest: fake.NewMockRawRequestWithContextFn(nil, errors.New("permission denied")),
+			want:       esv1beta1.VaultKVStoreV2,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			vStore := &client{
+				client: &fake.VaultClient{
+					MockNewRequest:            fake.NewMockNewRequestListFn(&vault.Request{}),
+					MockRawRequestWithContext: tc.rawRequest,
+				},
+				store: makeValidSecretStoreWithVersion("").Spec.Provider.Vault,
+				log:   logr.Discard(),
+			}
+			if got := vStore.detectKVVersion(context.Background()); got != tc.want {
+				t.Errorf("detectKVVersion(): want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateVersion(t *testing.T) {
+	cases := map[string]struct {
+		kvVersion esv1beta1.VaultKVStoreVersion
+		version   string
+		wantErr   error
+	}{
+		"VersionNumber": {
+			kvVersion: esv1beta1.VaultKVStoreV2,
+			version:   "3",
+		},
+		"InvalidVersion": {
+			kvVersion: esv1beta1.VaultKVStoreV2,
+			version:   "latest",
+			wantErr:   fmt.Errorf(errInvalidVersion, "latest"),
+		},
+		"KVv1": {
+			kvVersion: esv1beta1.VaultKVStoreV1,
+			version:   "3",
+			wantErr:   errors.New(errUnsupportedVersionKvVersion),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			vStore := &client{
+				store: makeValidSecretStoreWithVersion(tc.kvVersion).Spec.Provider.Vault,
+				log:   logr.Discard(),
+			}
+			err := vStore.ValidateVersion(tc.version)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("vault.ValidateVersion(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNamespaceHeader(t *testing.T) {
+	namespaces := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespaces[r.URL.Path] = r.Header.Get("X-Vault-Namespace")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			fmt.Fprint(w, `{"auth":{"client_token":"test-token"}}`)
+		default:
+			fmt.Fprint(w, `{"data":{"data":{"foo":"bar"}}}`)
+		}
+	}))
+	defer srv.Close()
+
+	store := makeValidSecretStore()
+	store.Spec.Provider.Vault.Server = srv.URL
+	store.Spec.Provider.Vault.Namespace = pointer.StringPtr("ns1")
+	store.Spec.Provider.Vault.Auth = esv1beta1.VaultAuth{
+		AppRole: &esv1beta1.VaultAppRole{
+			Path:   "approle",
+			RoleID: "role",
+			SecretRef: esmeta.SecretKeySelector{
+				Name: "approle-secret",
+				Key:  "token",
+			},
+		},
+	}
+	conn := &connector{newVaultClient: newVaultClient}
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, kubeMockWithSecretTokenAndServiceAcc),
+	}
+	vStore, err := conn.newClient(context.Background(), store, kube, nil, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := vStore.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"/v1/auth/approle/login": "ns1",
+		"/v1/secret/data/foo":    "ns1",
+	}
+	if diff := cmp.Diff(want, namespaces); diff != "" {
+		t.Errorf("X-Vault-Namespace: -want, +got:\n%s", diff)
+	}
+}
+
 func TestGetSecretPath(t *testing.T) {
 	storeV2 := makeValidSecretStore()
 	storeV2NoPath := storeV2.DeepCopy()
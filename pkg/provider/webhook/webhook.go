@@ -19,6 +19,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -28,13 +29,16 @@ import (
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/PaesslerAG/jsonpath"
+	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 	"github.com/external-secrets/external-secrets/pkg/template/v2"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 // Provider satisfies the provider interface.
@@ -73,6 +77,51 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 }
 
 func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	provider, err := getProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.URL == "" {
+		return fmt.Errorf("missing url")
+	}
+	if err := parseTemplate(provider.URL); err != nil {
+		return fmt.Errorf("failed to parse url: %w", err)
+	}
+	if err := parseTemplate(provider.Body); err != nil {
+		return fmt.Errorf("failed to parse body: %w", err)
+	}
+	for hKey, hValueTpl := range provider.Headers {
+		if err := parseTemplate(hValueTpl); err != nil {
+			return fmt.Errorf("failed to parse header %s: %w", hKey, err)
+		}
+	}
+	if provider.Result.JSONPath != "" && provider.Result.GJSON != "" {
+		return fmt.Errorf("result jsonpath and gjson are mutually exclusive")
+	}
+	if err := parseTemplate(provider.Result.JSONPath); err != nil {
+		return fmt.Errorf("failed to parse result jsonpath: %w", err)
+	}
+	// a templated jsonpath can only be validated once it has been rendered
+	if provider.Result.JSONPath != "" && !strings.Contains(provider.Result.JSONPath, "{{") {
+		if _, err := jsonpath.New(provider.Result.JSONPath); err != nil {
+			return fmt.Errorf("failed to parse result jsonpath %s: %w", provider.Result.JSONPath, err)
+		}
+	}
+	if err := parseTemplate(provider.Result.GJSON); err != nil {
+		return fmt.Errorf("failed to parse result gjson: %w", err)
+	}
+	for _, secref := range provider.Secrets {
+		if secref.Name == "" {
+			return fmt.Errorf("missing name of webhook secret %s", secref.SecretRef.Name)
+		}
+		if err := utils.ValidateSecretSelector(store, secref.SecretRef); err != nil {
+			return fmt.Errorf("invalid webhook secret %s: %w", secref.Name, err)
+		}
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	if provider.CAProvider != nil && storeKind == esv1beta1.ClusterSecretStoreKind && provider.CAProvider.Namespace == nil {
+		return fmt.Errorf("missing namespace on CAProvider secret")
+	}
 	return nil
 }
 
@@ -113,19 +162,30 @@ func (w *WebHook) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDat
 	if err != nil {
 		return nil, fmt.Errorf("failed to get store: %w", err)
 	}
-	result, err := w.getWebhookData(ctx, provider, ref)
+	data, err := w.getTemplateData(ctx, ref, provider.Secrets)
 	if err != nil {
 		return nil, err
 	}
+	result, err := w.getWebhookData(ctx, provider, data)
+	if err != nil {
+		return nil, err
+	}
+	if provider.Result.GJSON != "" {
+		val, err := getResultGJSON(provider, data, result)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(val.String()), nil
+	}
 	// Only parse as json if we have a jsonpath set
 	if provider.Result.JSONPath != "" {
 		jsondata := interface{}(nil)
 		if err := yaml.Unmarshal(result, &jsondata); err != nil {
 			return nil, fmt.Errorf("failed to parse response json: %w", err)
 		}
-		jsondata, err = jsonpath.Get(provider.Result.JSONPath, jsondata)
+		jsondata, err = getResultJSONPath(provider, data, jsondata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get response path %s: %w", provider.Result.JSONPath, err)
+			return nil, err
 		}
 		jsonvalue, ok := jsondata.(string)
 		if !ok {
@@ -142,21 +202,101 @@ func (w *WebHook) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecret
 	if err != nil {
 		return nil, fmt.Errorf("failed to get store: %w", err)
 	}
-	result, err := w.getWebhookData(ctx, provider, ref)
+	data, err := w.getTemplateData(ctx, ref, provider.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	result, err := w.getWebhookData(ctx, provider, data)
+	if err != nil {
+		return nil, err
+	}
+	jsonvalue, err := getResultMap(provider, data, result)
 	if err != nil {
 		return nil, err
 	}
 
-	// We always want json here, so just parse it out
+	// Change the map of generic objects to a map of byte arrays
+	values := make(map[string][]byte)
+	for rKey, rValue := range jsonvalue {
+		jVal, ok := rValue.(string)
+		if !ok {
+			return nil, fmt.Errorf("failed to get response (wrong type in key '%s': %T)", rKey, rValue)
+		}
+		values[rKey] = []byte(jVal)
+	}
+	return values, nil
+}
+
+// GetGeneratorData calls the webhook of provider for a generator in namespace and returns the
+// fields of the result object. String values are used as they are, other values are encoded as JSON.
+// The referenced secrets must exist in namespace.
+func GetGeneratorData(ctx context.Context, provider *esv1beta1.WebhookProvider, kube client.Client, namespace string) (map[string][]byte, error) {
+	w := &WebHook{
+		kube: kube,
+		store: &esv1beta1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider: &esv1beta1.SecretStoreProvider{Webhook: provider},
+			},
+		},
+		namespace: namespace,
+		storeKind: esv1beta1.SecretStoreKind,
+	}
+	var err error
+	w.http, err = w.getHTTPClient(provider)
+	if err != nil {
+		return nil, err
+	}
+	data, err := w.getTemplateData(ctx, esv1beta1.ExternalSecretDataRemoteRef{}, provider.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	result, err := w.getWebhookData(ctx, provider, data)
+	if err != nil {
+		return nil, err
+	}
+	jsonvalue, err := getResultMap(provider, data, result)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]byte, len(jsonvalue))
+	for rKey, rValue := range jsonvalue {
+		if jVal, ok := rValue.(string); ok {
+			values[rKey] = []byte(jVal)
+			continue
+		}
+		jVal, err := json.Marshal(rValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode response value of key '%s': %w", rKey, err)
+		}
+		values[rKey] = jVal
+	}
+	return values, nil
+}
+
+// getResultMap returns the object of the webhook result, selected by the jsonPath or gjson
+// expression of the result.
+func getResultMap(provider *esv1beta1.WebhookProvider, data map[string]map[string]string, result []byte) (map[string]interface{}, error) {
 	jsondata := interface{}(nil)
-	if err := yaml.Unmarshal(result, &jsondata); err != nil {
-		return nil, fmt.Errorf("failed to parse response json: %w", err)
+	if provider.Result.GJSON != "" {
+		// Get subdata via gjson
+		val, err := getResultGJSON(provider, data, result)
+		if err != nil {
+			return nil, err
+		}
+		jsondata = val.Value()
+	} else {
+		// We always want json here, so just parse it out
+		if err := yaml.Unmarshal(result, &jsondata); err != nil {
+			return nil, fmt.Errorf("failed to parse response json: %w", err)
+		}
 	}
 	// Get subdata via jsonpath, if given
 	if provider.Result.JSONPath != "" {
-		jsondata, err = jsonpath.Get(provider.Result.JSONPath, jsondata)
+		var err error
+		jsondata, err = getResultJSONPath(provider, data, jsondata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get response path %s: %w", provider.Result.JSONPath, err)
+			return nil, err
 		}
 	}
 	// If the value is a string, try to parse it as json
@@ -173,17 +313,34 @@ func (w *WebHook) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecret
 	if !ok {
 		return nil, fmt.Errorf("failed to get response (wrong type: %T)", jsondata)
 	}
+	return jsonvalue, nil
+}
 
-	// Change the map of generic objects to a map of byte arrays
-	values := make(map[string][]byte)
-	for rKey, rValue := range jsonvalue {
-		jVal, ok := rValue.(string)
-		if !ok {
-			return nil, fmt.Errorf("failed to get response (wrong type in key '%s': %T)", rKey, rValue)
-		}
-		values[rKey] = []byte(jVal)
+func getResultJSONPath(provider *esv1beta1.WebhookProvider, data map[string]map[string]string, jsondata interface{}) (interface{}, error) {
+	path, err := executeTemplateString(provider.Result.JSONPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse result jsonpath: %w", err)
 	}
-	return values, nil
+	jsondata, err = jsonpath.Get(path, jsondata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response path %s: %w", path, err)
+	}
+	return jsondata, nil
+}
+
+func getResultGJSON(provider *esv1beta1.WebhookProvider, data map[string]map[string]string, result []byte) (gjson.Result, error) {
+	path, err := executeTemplateString(provider.Result.GJSON, data)
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("failed to parse result gjson: %w", err)
+	}
+	if !gjson.ValidBytes(result) {
+		return gjson.Result{}, fmt.Errorf("failed to parse response json")
+	}
+	val := gjson.GetBytes(result, path)
+	if !val.Exists() {
+		return gjson.Result{}, fmt.Errorf("failed to get response path %s", path)
+	}
+	return val, nil
 }
 
 func (w *WebHook) getTemplateData(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef, secrets []esv1beta1.WebhookSecret) (map[string]map[string]string, error) {
@@ -209,14 +366,10 @@ func (w *WebHook) getTemplateData(ctx context.Context, ref esv1beta1.ExternalSec
 	return data, nil
 }
 
-func (w *WebHook) getWebhookData(ctx context.Context, provider *esv1beta1.WebhookProvider, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+func (w *WebHook) getWebhookData(ctx context.Context, provider *esv1beta1.WebhookProvider, data map[string]map[string]string) ([]byte, error) {
 	if w.http == nil {
 		return nil, fmt.Errorf("http client not initialized")
 	}
-	data, err := w.getTemplateData(ctx, ref, provider.Secrets)
-	if err != nil {
-		return nil, err
-	}
 	method := provider.Method
 	if method == "" {
 		method = http.MethodGet
@@ -389,6 +542,11 @@ func (w *WebHook) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts any version, it is passed to the url template.
+func (w *WebHook) ValidateVersion(version string) error {
+	return nil
+}
+
 func executeTemplateString(tmpl string, data map[string]map[string]string) (string, error) {
 	result, err := executeTemplate(tmpl, data)
 	if err != nil {
@@ -397,12 +555,21 @@ func executeTemplateString(tmpl string, data map[string]map[string]string) (stri
 	return result.String(), nil
 }
 
+func newTemplate(tmpl string) (*tpl.Template, error) {
+	return tpl.New("webhooktemplate").Funcs(sprig.TxtFuncMap()).Funcs(template.FuncMap()).Parse(tmpl)
+}
+
+func parseTemplate(tmpl string) error {
+	_, err := newTemplate(tmpl)
+	return err
+}
+
 func executeTemplate(tmpl string, data map[string]map[string]string) (bytes.Buffer, error) {
 	var result bytes.Buffer
 	if tmpl == "" {
 		return result, nil
 	}
-	urlt, err := tpl.New("webhooktemplate").Funcs(sprig.TxtFuncMap()).Funcs(template.FuncMap()).Parse(tmpl)
+	urlt, err := newTemplate(tmpl)
 	if err != nil {
 		return result, err
 	}
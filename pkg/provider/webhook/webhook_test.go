@@ -25,9 +25,13 @@ import (
 	"time"
 
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 )
 
 type testCase struct {
@@ -43,6 +47,7 @@ type args struct {
 	Key        string `json:"key,omitempty"`
 	Version    string `json:"version,omitempty"`
 	JSONPath   string `json:"jsonpath,omitempty"`
+	GJSON      string `json:"gjson,omitempty"`
 	Response   string `json:"response,omitempty"`
 	StatusCode int    `json:"statuscode,omitempty"`
 }
@@ -201,6 +206,68 @@ want:
   resultmap:
     thesecret: secret-value
     alsosecret: another-value
+---
+case: good templated jsonpath
+args:
+  url: /api/getsecret?id={{ .remoteRef.key }}
+  key: thesecret
+  jsonpath: $.result.{{ .remoteRef.key }}
+  response: '{"result":{"thesecret":"secret-value"}}'
+want:
+  path: /api/getsecret?id=thesecret
+  result: secret-value
+---
+case: good gjson
+args:
+  url: /api/getsecret?id={{ .remoteRef.key }}
+  key: testkey
+  gjson: result.secrets.#(name=="{{ .remoteRef.key }}").value
+  response: '{"result":{"secrets":[{"name":"other","value":"other-value"},{"name":"testkey","value":"secret-value"}]}}'
+want:
+  path: /api/getsecret?id=testkey
+  result: secret-value
+---
+case: good gjson object
+args:
+  url: /api/getsecret?id={{ .remoteRef.key }}
+  key: testkey
+  gjson: result
+  response: '{"result":{"thesecret":"secret-value"}}'
+want:
+  path: /api/getsecret?id=testkey
+  result: '{"thesecret":"secret-value"}'
+---
+case: good gjson map
+args:
+  url: /api/getsecret?id={{ .remoteRef.key }}
+  key: testkey
+  gjson: result
+  response: '{"result":{"thesecret":"secret-value","alsosecret":"another-value"}}'
+want:
+  path: /api/getsecret?id=testkey
+  resultmap:
+    thesecret: secret-value
+    alsosecret: another-value
+---
+case: error gjson not found
+args:
+  url: /api/getsecret?id={{ .remoteRef.key }}
+  key: testkey
+  gjson: result.nosecret
+  response: '{"result":{"thesecret":"secret-value"}}'
+want:
+  path: /api/getsecret?id=testkey
+  err: failed to get response path result.nosecret
+---
+case: error gjson bad json
+args:
+  url: /api/getsecret?id={{ .remoteRef.key }}
+  key: testkey
+  gjson: result
+  response: '{"result":{"thesecret":"secret-value"}'
+want:
+  path: /api/getsecret?id=testkey
+  err: failed to parse response json
 `
 
 func TestWebhookGetSecret(t *testing.T) {
@@ -312,6 +379,120 @@ func testGetSecret(tc testCase, t *testing.T, client esv1beta1.SecretsClient) {
 	}
 }
 
+func TestWebhookSecrets(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		if got := req.Header.Get("Authorization"); got != "Bearer my-token" {
+			t.Errorf("unexpected authorization header: '%s'", got)
+		}
+		if string(body) != `{"user":"admin"}` {
+			t.Errorf("unexpected body: '%s'", body)
+		}
+		rw.Write([]byte(`{"result":{"thesecret":"secret-value"}}`))
+	}))
+	defer ts.Close()
+
+	store := makeClusterSecretStore(ts.URL, args{URL: "/api/getsecret?id={{ .remoteRef.key }}", GJSON: "result.thesecret"})
+	store.Spec.Provider.Webhook.Method = http.MethodPost
+	store.Spec.Provider.Webhook.Headers["Authorization"] = "Bearer {{ .creds.token }}"
+	store.Spec.Provider.Webhook.Body = `{"user":"{{ .creds.user }}"}`
+	store.Spec.Provider.Webhook.Secrets = []esv1beta1.WebhookSecret{{
+		Name: "creds",
+		SecretRef: esmeta.SecretKeySelector{
+			Name:      "webhook-creds",
+			Namespace: pointer.StringPtr("default"),
+		},
+	}}
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "webhook-creds",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"token": []byte("my-token"),
+			"user":  []byte("admin"),
+		},
+	}).Build()
+	client, err := (&Provider{}).NewClient(context.Background(), store, kube, "testnamespace")
+	if err != nil {
+		t.Fatalf("error creating client: %s", err.Error())
+	}
+	secret, err := client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "testkey"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if string(secret) != "secret-value" {
+		t.Errorf("unexpected response: '%s' (expected 'secret-value')", secret)
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	tests := map[string]struct {
+		tweak func(p *esv1beta1.WebhookProvider)
+		err   string
+	}{
+		"valid": {
+			tweak: func(p *esv1beta1.WebhookProvider) {},
+		},
+		"missing url": {
+			tweak: func(p *esv1beta1.WebhookProvider) { p.URL = "" },
+			err:   "missing url",
+		},
+		"bad url template": {
+			tweak: func(p *esv1beta1.WebhookProvider) { p.URL = "http://example.com/{{ .unclosed" },
+			err:   "failed to parse url",
+		},
+		"bad header template": {
+			tweak: func(p *esv1beta1.WebhookProvider) { p.Headers["X-SecretKey"] = "{{ .unclosed" },
+			err:   "failed to parse header X-SecretKey",
+		},
+		"bad jsonpath": {
+			tweak: func(p *esv1beta1.WebhookProvider) { p.Result.JSONPath = "$.[" },
+			err:   "failed to parse result jsonpath",
+		},
+		"templated jsonpath": {
+			tweak: func(p *esv1beta1.WebhookProvider) { p.Result.JSONPath = "$.{{ .remoteRef.property }}" },
+		},
+		"bad gjson template": {
+			tweak: func(p *esv1beta1.WebhookProvider) {
+				p.Result.JSONPath = ""
+				p.Result.GJSON = "{{ .unclosed"
+			},
+			err: "failed to parse result gjson",
+		},
+		"jsonpath and gjson": {
+			tweak: func(p *esv1beta1.WebhookProvider) { p.Result.GJSON = "result" },
+			err:   "result jsonpath and gjson are mutually exclusive",
+		},
+		"secret without namespace": {
+			tweak: func(p *esv1beta1.WebhookProvider) {
+				p.Secrets = []esv1beta1.WebhookSecret{{Name: "creds", SecretRef: esmeta.SecretKeySelector{Name: "webhook-creds"}}}
+			},
+			err: "invalid webhook secret creds: cluster scope requires namespace",
+		},
+		"ca provider without namespace": {
+			tweak: func(p *esv1beta1.WebhookProvider) {
+				p.CAProvider = &esv1beta1.WebhookCAProvider{Type: esv1beta1.WebhookCAProviderTypeConfigMap, Name: "ca", Key: "ca.crt"}
+			},
+			err: "missing namespace on CAProvider secret",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := makeClusterSecretStore("http://example.com", args{URL: "/api/getsecret?id={{ .remoteRef.key }}", JSONPath: "$.result"})
+			tc.tweak(store.Spec.Provider.Webhook)
+			err := (&Provider{}).ValidateStore(store)
+			errStr := ""
+			if err != nil {
+				errStr = err.Error()
+			}
+			if (tc.err == "") != (errStr == "") || !strings.Contains(errStr, tc.err) {
+				t.Errorf("unexpected error: '%s' (expected '%s')", errStr, tc.err)
+			}
+		})
+	}
+}
+
 func makeClusterSecretStore(url string, args args) *esv1beta1.ClusterSecretStore {
 	store := &esv1beta1.ClusterSecretStore{
 		TypeMeta: metav1.TypeMeta{
@@ -332,6 +513,7 @@ func makeClusterSecretStore(url string, args args) *esv1beta1.ClusterSecretStore
 					},
 					Result: esv1beta1.WebhookResult{
 						JSONPath: args.JSONPath,
+						GJSON:    args.GJSON,
 					},
 				},
 			},
@@ -288,6 +288,11 @@ func (c *lockboxSecretsClient) Validate() error {
 	return nil
 }
 
+// ValidateVersion accepts a version id of the secret.
+func (c *lockboxSecretsClient) ValidateVersion(version string) error {
+	return nil
+}
+
 func getValueAsIs(entry *lockbox.Payload_Entry) (interface{}, error) {
 	switch entry.Value.(type) {
 	case *lockbox.Payload_Entry_TextValue:
@@ -25,6 +25,8 @@ import (
 	"github.com/youmark/pkcs8"
 	"golang.org/x/crypto/pkcs12"
 	corev1 "k8s.io/api/core/v1"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
 
 var tplFuncs = tpl.FuncMap{
@@ -65,13 +67,18 @@ const (
 	errDecodeBase64         = "unable to decode base64: %s"
 	errUnmarshalJSON        = "unable to unmarshal json: %s"
 	errMarshalJSON          = "unable to marshal json: %s"
+	errUnsupportedTarget    = "template target %s is not supported, use engineVersion v2"
 )
 
 // Execute renders the secret data as template. If an error occurs processing is stopped immediately.
-func Execute(tpl, data map[string][]byte, secret *corev1.Secret) error {
+// Only the data of the secret can be templated.
+func Execute(tpl, data map[string][]byte, target esapi.TemplateTarget, secret *corev1.Secret) error {
 	if tpl == nil {
 		return nil
 	}
+	if target != "" && target != esapi.TemplateTargetData {
+		return fmt.Errorf(errUnsupportedTarget, target)
+	}
 	for k, v := range tpl {
 		val, err := execute(k, string(v), data)
 		if err != nil {
@@ -20,6 +20,8 @@ import (
 
 	"github.com/Masterminds/sprig/v3"
 	corev1 "k8s.io/api/core/v1"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
 
 var tplFuncs = tpl.FuncMap{
@@ -42,6 +44,7 @@ func FuncMap() tpl.FuncMap {
 const (
 	errParse                = "unable to parse template at key %s: %s"
 	errExecute              = "unable to execute template at key %s: %s"
+	errUnknownTarget        = "unknown template target %s"
 	errDecodePKCS12WithPass = "unable to decode pkcs12 with password: %s"
 	errDecodeCertWithPass   = "unable to decode pkcs12 certificate with password: %s"
 	errParsePrivKey         = "unable to parse private key type"
@@ -59,8 +62,9 @@ func init() {
 	}
 }
 
-// Execute renders the secret data as template. If an error occurs processing is stopped immediately.
-func Execute(tpl, data map[string][]byte, secret *corev1.Secret) error {
+// Execute renders the templates into the data, annotations or labels of the secret,
+// depending on the target. If an error occurs processing is stopped immediately.
+func Execute(tpl, data map[string][]byte, target esapi.TemplateTarget, secret *corev1.Secret) error {
 	if tpl == nil {
 		return nil
 	}
@@ -69,7 +73,25 @@ func Execute(tpl, data map[string][]byte, secret *corev1.Secret) error {
 		if err != nil {
 			return fmt.Errorf(errExecute, k, err)
 		}
-		secret.Data[k] = val
+		switch target {
+		case esapi.TemplateTargetAnnotations:
+			if secret.Annotations == nil {
+				secret.Annotations = make(map[string]string)
+			}
+			secret.Annotations[k] = string(val)
+		case esapi.TemplateTargetLabels:
+			if secret.Labels == nil {
+				secret.Labels = make(map[string]string)
+			}
+			secret.Labels[k] = string(val)
+		case esapi.TemplateTargetData, "":
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte)
+			}
+			secret.Data[k] = val
+		default:
+			return fmt.Errorf(errUnknownTarget, target)
+		}
 	}
 	return nil
 }
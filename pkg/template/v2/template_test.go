@@ -21,6 +21,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
 
 const (
@@ -373,7 +376,7 @@ func TestExecute(t *testing.T) {
 			sec := &corev1.Secret{
 				Data: make(map[string][]byte),
 			}
-			err := Execute(row.tpl, row.data, sec)
+			err := Execute(row.tpl, row.data, esapi.TemplateTargetData, sec)
 			if !ErrorContains(err, row.expErr) {
 				t.Errorf("unexpected error: %s, expected: %s", err, row.expErr)
 			}
@@ -385,6 +388,56 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteTarget(t *testing.T) {
+	tpl := map[string][]byte{
+		"foo": []byte(`{{ .secret | upper }}`),
+	}
+	data := map[string][]byte{
+		"secret": []byte("bar"),
+	}
+	tbl := []struct {
+		name   string
+		target esapi.TemplateTarget
+		expSec *corev1.Secret
+		expErr string
+	}{
+		{
+			name:   "data",
+			target: esapi.TemplateTargetData,
+			expSec: &corev1.Secret{Data: map[string][]byte{"foo": []byte("BAR")}},
+		},
+		{
+			name:   "annotations",
+			target: esapi.TemplateTargetAnnotations,
+			expSec: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "BAR"}}},
+		},
+		{
+			name:   "labels",
+			target: esapi.TemplateTargetLabels,
+			expSec: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "BAR"}}},
+		},
+		{
+			name:   "unknown target",
+			target: "Spec",
+			expErr: "unknown template target Spec",
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.name, func(t *testing.T) {
+			sec := &corev1.Secret{}
+			err := Execute(tpl, data, row.target, sec)
+			if !ErrorContains(err, row.expErr) {
+				t.Errorf("unexpected error: %s, expected: %s", err, row.expErr)
+			}
+			if row.expSec == nil {
+				return
+			}
+			assert.EqualValues(t, row.expSec, sec)
+		})
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
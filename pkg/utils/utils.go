@@ -15,16 +15,25 @@ limitations under the License.
 package utils
 
 import (
-
+	"bytes"
 	// nolint:gosec
 	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
+	"text/template"
+	"time"
 	"unicode"
 
+	"github.com/tidwall/gjson"
+	"k8s.io/apimachinery/pkg/util/wait"
+
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	tplv2 "github.com/external-secrets/external-secrets/pkg/template/v2"
 )
 
 // MergeByteMap merges map of byte slices.
@@ -36,8 +45,17 @@ func MergeByteMap(dst, src map[string][]byte) map[string][]byte {
 }
 
 // ConvertKeys converts a secret map into a valid key.
-// Replaces any non-alphanumeric characters depending on convert strategy.
+// Replaces any character that is not allowed in a Secret key depending on convert strategy.
 func ConvertKeys(strategy esv1beta1.ExternalSecretConversionStrategy, in map[string][]byte) (map[string][]byte, error) {
+	switch strategy {
+	case esv1beta1.ExternalSecretConversionNone:
+		return in, nil
+	case "":
+		strategy = esv1beta1.ExternalSecretConversionDefault
+	case esv1beta1.ExternalSecretConversionDefault, esv1beta1.ExternalSecretConversionUnicode:
+	default:
+		return nil, fmt.Errorf("conversion strategy %s is not supported", strategy)
+	}
 	out := make(map[string][]byte, len(in))
 	for k, v := range in {
 		key := convert(strategy, k)
@@ -50,25 +68,137 @@ func ConvertKeys(strategy esv1beta1.ExternalSecretConversionStrategy, in map[str
 }
 
 func convert(strategy esv1beta1.ExternalSecretConversionStrategy, str string) string {
-	rs := []rune(str)
-	newName := make([]string, len(rs))
-	for rk, rv := range rs {
-		if !unicode.IsNumber(rv) &&
-			!unicode.IsLetter(rv) &&
-			rv != '-' &&
-			rv != '.' &&
-			rv != '_' {
-			switch strategy {
-			case esv1beta1.ExternalSecretConversionDefault:
-				newName[rk] = "_"
-			case esv1beta1.ExternalSecretConversionUnicode:
-				newName[rk] = fmt.Sprintf("_U%04x_", rv)
-			}
+	var b strings.Builder
+	for _, r := range str {
+		if isValidKeyRune(r) {
+			b.WriteRune(r)
+			continue
+		}
+		if strategy == esv1beta1.ExternalSecretConversionUnicode {
+			fmt.Fprintf(&b, "_U%04x_", r)
 		} else {
-			newName[rk] = string(rv)
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// isValidKeyRune returns true for the characters allowed in a Secret key.
+func isValidKeyRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.' || r == '_')
+}
+
+// Decode decodes a provider value with the decoding strategy.
+// Auto decodes base64 and base64url values, other values are returned as they are.
+func Decode(strategy esv1beta1.ExternalSecretDecodingStrategy, in []byte) ([]byte, error) {
+	switch strategy {
+	case esv1beta1.ExternalSecretDecodeBase64:
+		return decodeBase64(base64.StdEncoding, in)
+	case esv1beta1.ExternalSecretDecodeBase64URL:
+		return decodeBase64(base64.URLEncoding, in)
+	case esv1beta1.ExternalSecretDecodeAuto:
+		if out, err := decodeBase64(base64.StdEncoding, in); err == nil {
+			return out, nil
+		}
+		if out, err := decodeBase64(base64.URLEncoding, in); err == nil {
+			return out, nil
+		}
+		return in, nil
+	case esv1beta1.ExternalSecretDecodeNone, "":
+		return in, nil
+	}
+	return nil, fmt.Errorf("decoding strategy %s is not supported", strategy)
+}
+
+// decodeBase64 decodes padded and unpadded values.
+func decodeBase64(enc *base64.Encoding, in []byte) ([]byte, error) {
+	value := strings.TrimSpace(string(in))
+	if !strings.HasSuffix(value, "=") && len(value)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	return enc.DecodeString(value)
+}
+
+// DecodeMap decodes all values of a secret map with the decoding strategy.
+func DecodeMap(strategy esv1beta1.ExternalSecretDecodingStrategy, in map[string][]byte) (map[string][]byte, error) {
+	out := make(map[string][]byte, len(in))
+	for k, v := range in {
+		value, err := Decode(strategy, v)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode key %s: %w", k, err)
+		}
+		out[k] = value
+	}
+	return out, nil
+}
+
+// RewriteMap applies the rewrite operations in order to the keys of a secret map.
+func RewriteMap(operations []esv1beta1.ExternalSecretRewrite, in map[string][]byte) (map[string][]byte, error) {
+	out := in
+	for i, op := range operations {
+		rewrite, err := newRewriteFunc(op)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rewrite[%d]: %w", i, err)
+		}
+		next := make(map[string][]byte, len(out))
+		for k, v := range out {
+			key, err := rewrite(k)
+			if err != nil {
+				return nil, fmt.Errorf("could not rewrite key %s with rewrite[%d]: %w", k, i, err)
+			}
+			if _, exists := next[key]; exists {
+				return nil, fmt.Errorf("secret name collision during rewrite[%d]: %s", i, key)
+			}
+			next[key] = v
+		}
+		out = next
+	}
+	return out, nil
+}
+
+func newRewriteFunc(op esv1beta1.ExternalSecretRewrite) (func(string) (string, error), error) {
+	switch {
+	case op.Regexp != nil:
+		re, err := regexp.Compile(op.Regexp.Source)
+		if err != nil {
+			return nil, err
+		}
+		return func(key string) (string, error) {
+			return re.ReplaceAllString(key, op.Regexp.Target), nil
+		}, nil
+	case op.Transform != nil:
+		tpl, err := template.New("rewrite").Funcs(tplv2.FuncMap()).Option("missingkey=error").Parse(op.Transform.Template)
+		if err != nil {
+			return nil, err
+		}
+		return func(key string) (string, error) {
+			var buf bytes.Buffer
+			if err := tpl.Execute(&buf, map[string]string{"value": key}); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		}, nil
+	}
+	return nil, fmt.Errorf("one of regexp or transform must be set")
+}
+
+// GetProperty returns the value of property in the JSON document payload.
+// A top-level key matching property exactly takes precedence, so keys
+// containing dots keep working. Otherwise property is evaluated as gjson path,
+// e.g. `db.hosts.0.name` or `db.hosts.#.name`. Strings are returned unquoted,
+// objects and arrays as raw JSON.
+func GetProperty(payload []byte, property string) ([]byte, bool) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &obj); err == nil {
+		if raw, ok := obj[property]; ok {
+			return []byte(gjson.ParseBytes(raw).String()), true
 		}
 	}
-	return strings.Join(newName, "")
+	val := gjson.GetBytes(payload, property)
+	if !val.Exists() {
+		return nil, false
+	}
+	return []byte(val.String()), true
 }
 
 // MergeStringMap performs a deep clone from src to dest.
@@ -90,6 +220,30 @@ func IsNil(i interface{}) bool {
 	return false
 }
 
+// Jitter extends the duration by a random fraction of it of up to maxFactor,
+// so that the requeues of objects with the same interval spread over time.
+// The duration is never shortened, as objects requeued before their interval
+// passed are not refreshed. A maxFactor of 0 or less disables the jitter.
+func Jitter(d time.Duration, maxFactor float64) time.Duration {
+	if d <= 0 || maxFactor <= 0 {
+		return d
+	}
+	return wait.Jitter(d, maxFactor)
+}
+
+// Backoff returns the time a failed operation is retried after: base after the first
+// of the consecutive failures, doubled with every further failure up to maxBackoff.
+func Backoff(base, maxBackoff time.Duration, failures int32) time.Duration {
+	backoff := base
+	for i := int32(1); i < failures && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
 // ObjectHash calculates md5 sum of the data contained in the secret.
 // nolint:gosec
 func ObjectHash(object interface{}) string {
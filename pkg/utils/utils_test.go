@@ -17,8 +17,9 @@ package utils
 import (
 	"reflect"
 	"testing"
+	"time"
 
-	vault "github.com/oracle/oci-go-sdk/v56/vault"
+	vault "github.com/oracle/oci-go-sdk/v65/vault"
 	v1 "k8s.io/api/core/v1"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
@@ -198,6 +199,63 @@ func TestConvertKeys(t *testing.T) {
 				"foo_bar_baz_bing_": []byte(`noop`),
 			},
 		},
+		{
+			name: "convert non ascii letters",
+			args: args{
+				strategy: esv1beta1.ExternalSecretConversionDefault,
+				in: map[string][]byte{
+					"café/crème": []byte(`noop`),
+				},
+			},
+			want: map[string][]byte{
+				"caf__cr_me": []byte(`noop`),
+			},
+		},
+		{
+			name: "convert non ascii letters to unicode",
+			args: args{
+				strategy: esv1beta1.ExternalSecretConversionUnicode,
+				in: map[string][]byte{
+					"café/crème": []byte(`noop`),
+				},
+			},
+			want: map[string][]byte{
+				"caf_U00e9__U002f_cr_U00e8_me": []byte(`noop`),
+			},
+		},
+		{
+			name: "default strategy",
+			args: args{
+				in: map[string][]byte{
+					"/foo/bar": []byte(`noop`),
+				},
+			},
+			want: map[string][]byte{
+				"_foo_bar": []byte(`noop`),
+			},
+		},
+		{
+			name: "error on unknown strategy",
+			args: args{
+				strategy: "unicode",
+				in: map[string][]byte{
+					"/foo/bar": []byte(`noop`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "keep keys",
+			args: args{
+				strategy: esv1beta1.ExternalSecretConversionNone,
+				in: map[string][]byte{
+					"/foo/bar": []byte(`noop`),
+				},
+			},
+			want: map[string][]byte{
+				"/foo/bar": []byte(`noop`),
+			},
+		},
 		{
 			name: "convert unicode",
 			args: args{
@@ -224,3 +282,299 @@ func TestConvertKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestRewriteMap(t *testing.T) {
+	type args struct {
+		operations []esv1beta1.ExternalSecretRewrite
+		in         map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string][]byte
+		wantErr bool
+	}{
+		{
+			name: "rewrite with regexp",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "^prod/app/(.*)", Target: "$1"}},
+				},
+				in: map[string][]byte{
+					"prod/app/DB_PASSWORD": []byte(`noop`),
+					"prod/api/TOKEN":       []byte(`noop`),
+				},
+			},
+			want: map[string][]byte{
+				"DB_PASSWORD":    []byte(`noop`),
+				"prod/api/TOKEN": []byte(`noop`),
+			},
+		},
+		{
+			name: "operations are applied in order",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Transform: &esv1beta1.ExternalSecretRewriteTransform{Template: `{{ .value | trimPrefix "prod/" }}`}},
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "/", Target: "-"}},
+					{Transform: &esv1beta1.ExternalSecretRewriteTransform{Template: `{{ .value | lower }}`}},
+				},
+				in: map[string][]byte{
+					"prod/app/DB_PASSWORD": []byte(`noop`),
+				},
+			},
+			want: map[string][]byte{
+				"app-db_password": []byte(`noop`),
+			},
+		},
+		{
+			name: "no operations",
+			args: args{
+				in: map[string][]byte{
+					"prod/app/DB_PASSWORD": []byte(`noop`),
+				},
+			},
+			want: map[string][]byte{
+				"prod/app/DB_PASSWORD": []byte(`noop`),
+			},
+		},
+		{
+			name: "error on collision",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "^[a-z]+/", Target: ""}},
+				},
+				in: map[string][]byte{
+					"prod/DB_PASSWORD": []byte(`noop`),
+					"dev/DB_PASSWORD":  []byte(`noop`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error on invalid regexp",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "(", Target: ""}},
+				},
+				in: map[string][]byte{
+					"prod/DB_PASSWORD": []byte(`noop`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error on invalid template",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Transform: &esv1beta1.ExternalSecretRewriteTransform{Template: `{{ .value | unknown }}`}},
+				},
+				in: map[string][]byte{
+					"prod/DB_PASSWORD": []byte(`noop`),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RewriteMap(tt.args.operations, tt.args.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RewriteMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RewriteMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy esv1beta1.ExternalSecretDecodingStrategy
+		in       string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "no decoding",
+			strategy: esv1beta1.ExternalSecretDecodeNone,
+			in:       "aGVsbG8=",
+			want:     "aGVsbG8=",
+		},
+		{
+			name:     "base64",
+			strategy: esv1beta1.ExternalSecretDecodeBase64,
+			in:       "aGVsbG8=",
+			want:     "hello",
+		},
+		{
+			name:     "unpadded base64",
+			strategy: esv1beta1.ExternalSecretDecodeBase64,
+			in:       "aGVsbG8",
+			want:     "hello",
+		},
+		{
+			name:     "invalid base64",
+			strategy: esv1beta1.ExternalSecretDecodeBase64,
+			in:       "-_8=",
+			wantErr:  true,
+		},
+		{
+			name:     "base64url",
+			strategy: esv1beta1.ExternalSecretDecodeBase64URL,
+			in:       "-_8=",
+			want:     "\xfb\xff",
+		},
+		{
+			name:     "auto base64",
+			strategy: esv1beta1.ExternalSecretDecodeAuto,
+			in:       "+/8=",
+			want:     "\xfb\xff",
+		},
+		{
+			name:     "auto base64url",
+			strategy: esv1beta1.ExternalSecretDecodeAuto,
+			in:       "-_8=",
+			want:     "\xfb\xff",
+		},
+		{
+			name:     "auto plain value",
+			strategy: esv1beta1.ExternalSecretDecodeAuto,
+			in:       "hello world!",
+			want:     "hello world!",
+		},
+		{
+			name:     "unknown strategy",
+			strategy: "Hex",
+			in:       "aGVsbG8=",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.strategy, []byte(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Decode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProperty(t *testing.T) {
+	payload := []byte(`{"user":"admin","tls.crt":"cert","db":{"host":"db.local","port":5432},"replicas":[{"host":"a"},{"host":"b"}]}`)
+	tests := []struct {
+		name     string
+		property string
+		want     string
+		wantOk   bool
+	}{
+		{
+			name:     "top-level key",
+			property: "user",
+			want:     "admin",
+			wantOk:   true,
+		},
+		{
+			name:     "key containing a dot",
+			property: "tls.crt",
+			want:     "cert",
+			wantOk:   true,
+		},
+		{
+			name:     "nested key",
+			property: "db.port",
+			want:     "5432",
+			wantOk:   true,
+		},
+		{
+			name:     "object",
+			property: "db",
+			want:     `{"host":"db.local","port":5432}`,
+			wantOk:   true,
+		},
+		{
+			name:     "array index",
+			property: "replicas.1.host",
+			want:     "b",
+			wantOk:   true,
+		},
+		{
+			name:     "wildcard",
+			property: "replicas.#.host",
+			want:     `["a","b"]`,
+			wantOk:   true,
+		},
+		{
+			name:     "missing key",
+			property: "db.user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetProperty(payload, tt.property)
+			if ok != tt.wantOk {
+				t.Fatalf("GetProperty() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if string(got) != tt.want {
+				t.Errorf("GetProperty() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[string]struct {
+		failures int32
+		want     time.Duration
+	}{
+		"first failure":  {failures: 1, want: 5 * time.Second},
+		"third failure":  {failures: 3, want: 20 * time.Second},
+		"capped":         {failures: 10, want: time.Minute},
+		"many failures":  {failures: 1000, want: time.Minute},
+		"no failure yet": {failures: 0, want: 5 * time.Second},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Backoff(5*time.Second, time.Minute, tc.failures); got != tc.want {
+				t.Errorf("unexpected backoff: expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	tests := map[string]struct {
+		duration  time.Duration
+		maxFactor float64
+		wantMax   time.Duration
+	}{
+		"disabled": {
+			duration: time.Hour,
+			wantMax:  time.Hour,
+		},
+		"zero duration": {
+			maxFactor: 0.5,
+		},
+		"jitter": {
+			duration:  time.Hour,
+			maxFactor: 0.1,
+			wantMax:   time.Hour + 6*time.Minute,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got := Jitter(tc.duration, tc.maxFactor)
+				if got < tc.duration || got > tc.wantMax {
+					t.Fatalf("unexpected jitter: expected duration between %v and %v, got %v", tc.duration, tc.wantMax, got)
+				}
+			}
+		})
+	}
+}